@@ -0,0 +1,224 @@
+// Package app is the root model of the TUI. It routes messages to the
+// screen showing, moves between screens as they finish, and keeps track of
+// the files picked and saved across them.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/state"
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+// Model is the root Bubble Tea model of the TUI. It owns the model of every
+// screen, so that screens can hand files to each other.
+type Model struct {
+	currentScreen screenID
+	menu          tui.MenuModel
+	picker        tui.PickerModel
+	preview       tui.PreviewModel
+	form          tui.FormModel
+	compare       tui.CompareModel
+	move          tui.MoveModel
+	settings      tui.SettingsModel
+	statusBar     tui.StatusBar
+	confirmQuit   bool // asking whether to quit with unsaved form edits
+	quitting      bool // waiting for a write to finish before quitting
+	fileList      []string
+	fileIndex     int
+	pickerMode    tui.MenuChoice
+	windowHeight  int
+	windowWidth   int
+	inline        bool     // running without the alternate screen
+	written       []string // files written this run, summarized on exit in inline mode
+	savedFiles    map[int]bool
+	statePath     string
+	session       state.Session
+	sessionErr    error // loading the session, reported once the TUI starts
+	checkUpdate   tea.Cmd
+	updateNotice  string
+	cfg           config.Config
+	configPath    string
+	opts          tui.Options
+}
+
+// New returns the root model, starting at the menu with the default config.
+// Every screen is opened with opts.
+func New(opts tui.Options) Model {
+	m := Model{
+		currentScreen: menuScreen,
+		menu:          tui.NewMenuModel(opts),
+		cfg:           config.Default(),
+		opts:          opts,
+	}
+	m.statusBar.SetTheme(opts.Theme)
+	return m
+}
+
+// WithConfig applies cfg to the model. Changes made on the settings screen
+// are saved to path; an empty path keeps them for this run only.
+func (m Model) WithConfig(cfg config.Config, path string) Model {
+	m.cfg = cfg
+	m.configPath = path
+	m.menu.SetEnableBackup(cfg.Backup)
+	return m
+}
+
+// WithInline runs the TUI without the alternate screen: it takes up at most
+// inlineHeight lines, and Summary lists the files it wrote.
+func (m Model) WithInline() Model {
+	m.inline = true
+	return m
+}
+
+// Inline reports whether WithInline was set.
+func (m Model) Inline() bool {
+	return m.inline
+}
+
+// Init starts the update check, if there is one, and reports a session
+// that could not be loaded.
+func (m Model) Init() tea.Cmd {
+	if m.sessionErr != nil {
+		return tea.Batch(m.checkUpdate, sessionWarning(m.sessionErr))
+	}
+	return m.checkUpdate
+}
+
+// inlineHeight is the most lines the TUI takes up in inline mode, so it
+// does not push the whole terminal into the scrollback.
+const inlineHeight = 24
+
+// contentHeight is the height left to screens above the status bar.
+func (m Model) contentHeight() int {
+	height := m.windowHeight
+	if m.inline {
+		height = min(height, inlineHeight)
+	}
+	return max(height-tui.StatusBarHeight, 0)
+}
+
+// report shows the result of a write in the status bar and keeps it for the
+// summary printed on exit in inline mode.
+func (m *Model) report(status string, files ...string) {
+	m.statusBar.Set(status)
+	m.written = append(m.written, files...)
+}
+
+// Summary lists the files written this run.
+func (m Model) Summary() string {
+	if len(m.written) == 0 {
+		return "No files written\n"
+	}
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "Wrote %d file(s):\n", len(m.written))
+	for _, line := range m.written {
+		_, _ = fmt.Fprintf(&b, "  %s\n", line)
+	}
+	return b.String()
+}
+
+// setWindowWidth passes the terminal width to every screen, so those not
+// showing pick it up when they open.
+func (m *Model) setWindowWidth(w int) {
+	m.windowWidth = w
+	m.menu.SetWindowWidth(w)
+	m.picker.SetWindowWidth(w)
+	m.preview.SetWindowWidth(w)
+	m.form.SetWindowWidth(w)
+	m.compare.SetWindowWidth(w)
+	m.move.SetWindowWidth(w)
+	m.settings.SetWindowWidth(w)
+	m.statusBar.SetWidth(w)
+}
+
+// Update handles the messages every screen shares, such as window sizes
+// and Ctrl+C, and passes the rest to the current screen.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.windowHeight = size.Height
+		m.setWindowWidth(size.Width)
+		size.Height = m.contentHeight()
+		msg = size
+	}
+
+	switch msg := msg.(type) {
+	case updateAvailableMsg:
+		m.updateNotice = msg.version
+		m.menu.SetUpdateAvailable(msg.version)
+		return m, nil
+	case tui.StatusMsg:
+		var cmd tea.Cmd
+		m.statusBar, cmd = m.statusBar.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		if m.quitting {
+			return m, nil
+		}
+		if m.confirmQuit {
+			m.confirmQuit = false
+			if msg.String() == "y" || msg.String() == "Y" || key.Matches(msg, m.opts.Keys.Interrupt) {
+				return m.quit()
+			}
+			return m, nil
+		}
+		if key.Matches(msg, m.opts.Keys.Interrupt) {
+			if m.currentScreen == formScreen && m.form.Dirty() {
+				m.confirmQuit = true
+				return m, nil
+			}
+			return m.quit()
+		}
+	}
+
+	updated, cmd := screens[m.currentScreen].update(msg, m)
+	if updated.quitting && !updated.form.Busy() {
+		return updated, tea.Quit
+	}
+	return updated, cmd
+}
+
+// quit exits the program, first letting a save or restore that is being
+// written finish so the file is not left half-written.
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	if m.currentScreen == formScreen && m.form.Busy() {
+		m.quitting = true
+		return m, nil
+	}
+	return m, tea.Quit
+}
+
+// returnToMenu shows a fresh menu, keeping the backup toggle, the session
+// to resume and the update banner.
+func returnToMenu(m Model) Model {
+	m.currentScreen = menuScreen
+	backup := m.menu.EnableBackup()
+	m.menu = tui.NewMenuModel(m.opts)
+	m.menu.SetEnableBackup(backup)
+	m.menu.SetResume(sessionLabel(m.session))
+	m.menu.SetUpdateAvailable(m.updateNotice)
+	m.menu.SetWindowWidth(m.windowWidth)
+	return m
+}
+
+// View draws the current screen above the status bar.
+func (m Model) View() string {
+	s := screens[m.currentScreen]
+	view := s.view(m)
+	if !strings.HasSuffix(view, "\n") {
+		view += "\n"
+	}
+	switch {
+	case m.confirmQuit:
+		return view + m.statusBar.Prompt("Unsaved changes in the form. Quit anyway? y/N")
+	case m.quitting:
+		return view + m.statusBar.Prompt("Finishing write before quitting…")
+	}
+	mode, counts := s.status(m)
+	return view + m.statusBar.View(mode, counts)
+}
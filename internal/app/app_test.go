@@ -0,0 +1,588 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/state"
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+func TestUpdateFormTracksSavedFiles(t *testing.T) {
+	// Arrange
+	m := Model{
+		currentScreen: formScreen,
+		fileList:      []string{"/test/file1.env", "/test/file2.env", "/test/file3.env"},
+		fileIndex:     0,
+		savedFiles:    make(map[int]bool),
+		form:          tui.FormModel{},
+	}
+	msg := tui.FormSavedMsg{Success: true, Error: ""}
+
+	// Act
+	newModelTyped, _ := formRoute{}.update(msg, m)
+
+	// Assert
+	if !newModelTyped.savedFiles[0] {
+		t.Errorf("update() should mark file index 0 as saved after successful save")
+	}
+
+	if len(newModelTyped.savedFiles) != 1 {
+		t.Errorf("update() savedFiles length = %d, expected 1", len(newModelTyped.savedFiles))
+	}
+}
+
+func TestUpdateFormDoesNotTrackFailedSaves(t *testing.T) {
+	// Arrange
+	m := Model{
+		currentScreen: formScreen,
+		fileList:      []string{"/test/file1.env"},
+		fileIndex:     0,
+		savedFiles:    make(map[int]bool),
+		form:          tui.FormModel{},
+	}
+	msg := tui.FormSavedMsg{Success: false, Error: "permission denied"}
+
+	// Act
+	newModelTyped, _ := formRoute{}.update(msg, m)
+
+	// Assert
+	if newModelTyped.savedFiles[0] {
+		t.Errorf("update() should not mark file as saved after failed save")
+	}
+
+	if len(newModelTyped.savedFiles) != 0 {
+		t.Errorf("update() savedFiles length = %d, expected 0", len(newModelTyped.savedFiles))
+	}
+}
+
+func TestUpdateFormReturnsToMenuOnEnter(t *testing.T) {
+	// Arrange
+	m := Model{
+		currentScreen: formScreen,
+		fileList:      []string{"/test/file1.env"},
+		fileIndex:     0,
+		savedFiles:    make(map[int]bool),
+		form:          tui.FormModel{},
+	}
+	msg := tui.FormFinishedMsg{Success: true, Error: "", Dir: 0}
+
+	// Act
+	newModelTyped, _ := formRoute{}.update(msg, m)
+
+	// Assert
+	if newModelTyped.currentScreen != menuScreen {
+		t.Errorf("update() with Dir=0 should return to menuScreen, got %v", newModelTyped.currentScreen)
+	}
+}
+
+func TestUpdateFormReturnsToMenuWhenAllFilesSaved(t *testing.T) {
+	// Arrange
+	m := Model{
+		currentScreen: formScreen,
+		fileList:      []string{"/test/file1.env", "/test/file2.env", "/test/file3.env"},
+		fileIndex:     0,
+		savedFiles:    map[int]bool{0: true, 1: true, 2: true},
+		form:          tui.FormModel{},
+	}
+	msg := tui.FormFinishedMsg{Success: true, Error: "", Dir: 1}
+
+	// Act
+	newModelTyped, _ := formRoute{}.update(msg, m)
+
+	// Assert
+	if newModelTyped.currentScreen != menuScreen {
+		t.Errorf("update() when all files saved should return to menuScreen, got %v", newModelTyped.currentScreen)
+	}
+}
+
+func TestUpdateFormNavigatesToNextUnsavedFile(t *testing.T) {
+	tests := []struct {
+		name           string
+		fileList       []string
+		initialIndex   int
+		savedFiles     map[int]bool
+		direction      int
+		expectedIndex  int
+		expectedScreen screenID
+	}{
+		{
+			name:           "moves to next file when current is saved",
+			fileList:       []string{"f1", "f2", "f3"},
+			initialIndex:   0,
+			savedFiles:     map[int]bool{0: true},
+			direction:      1,
+			expectedIndex:  1,
+			expectedScreen: formScreen,
+		},
+		{
+			name:           "skips saved files moving forward",
+			fileList:       []string{"f1", "f2", "f3", "f4"},
+			initialIndex:   0,
+			savedFiles:     map[int]bool{0: true, 1: true},
+			direction:      1,
+			expectedIndex:  2,
+			expectedScreen: formScreen,
+		},
+		{
+			name:           "wraps around to find unsaved file",
+			fileList:       []string{"f1", "f2", "f3"},
+			initialIndex:   2,
+			savedFiles:     map[int]bool{2: true, 0: true},
+			direction:      1,
+			expectedIndex:  1,
+			expectedScreen: formScreen,
+		},
+		{
+			name:           "returns to menu when all files are saved",
+			fileList:       []string{"f1", "f2", "f3"},
+			initialIndex:   0,
+			savedFiles:     map[int]bool{0: true, 1: true, 2: true},
+			direction:      1,
+			expectedIndex:  0,
+			expectedScreen: menuScreen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			m := Model{
+				currentScreen: formScreen,
+				fileList:      tt.fileList,
+				fileIndex:     tt.initialIndex,
+				savedFiles:    tt.savedFiles,
+				form:          tui.FormModel{},
+			}
+			msg := tui.FormFinishedMsg{Success: true, Error: "", Dir: tt.direction}
+
+			// Act
+			newModelTyped, _ := formRoute{}.update(msg, m)
+
+			// Assert
+			if newModelTyped.currentScreen != tt.expectedScreen {
+				t.Errorf("update() screen = %v, expected %v", newModelTyped.currentScreen, tt.expectedScreen)
+			}
+
+			if newModelTyped.currentScreen == formScreen && newModelTyped.fileIndex != tt.expectedIndex {
+				t.Errorf("update() fileIndex = %d, expected %d", newModelTyped.fileIndex, tt.expectedIndex)
+			}
+		})
+	}
+}
+
+func TestReturnToMenu(t *testing.T) {
+	// Arrange
+	m := Model{
+		currentScreen: formScreen,
+		form:          tui.FormModel{},
+		menu:          tui.MenuModel{},
+	}
+
+	// Act
+	newModelTyped := returnToMenu(m)
+
+	// Assert
+	if newModelTyped.currentScreen != menuScreen {
+		t.Errorf("returnToMenu() should set currentScreen to menuScreen, got %v", newModelTyped.currentScreen)
+	}
+}
+
+func TestInitialModel(t *testing.T) {
+	// Act
+	m := New(tui.DefaultOptions())
+
+	// Assert
+	if m.currentScreen != menuScreen {
+		t.Errorf("New(tui.DefaultOptions()) should start at menuScreen, got %v", m.currentScreen)
+	}
+
+	if m.fileList != nil {
+		t.Errorf("New(tui.DefaultOptions()) fileList should be nil, got %v", m.fileList)
+	}
+
+	if m.savedFiles != nil {
+		t.Errorf("New(tui.DefaultOptions()) savedFiles should be nil, got %v", m.savedFiles)
+	}
+}
+
+func TestModelUpdateWindowSize(t *testing.T) {
+	// Arrange
+	m := New(tui.DefaultOptions())
+	msg := tea.WindowSizeMsg{Height: 42}
+
+	// Act
+	newModel, _ := m.Update(msg)
+	newModelTyped, ok := newModel.(Model)
+	if !ok {
+		t.Fatalf("Update() should return model type")
+	}
+
+	// Assert
+	if newModelTyped.windowHeight != 42 {
+		t.Errorf("Update(WindowSizeMsg) windowHeight = %d, expected 42", newModelTyped.windowHeight)
+	}
+}
+
+func TestUpdateAvailableBannerSurvivesReturnToMenu(t *testing.T) {
+	// Arrange
+	m := New(tui.DefaultOptions())
+
+	// Act
+	newModel, _ := m.Update(updateAvailableMsg{version: "v1.4.0"})
+	m = newModel.(Model)
+	m.currentScreen = formScreen
+	m = returnToMenu(m)
+
+	// Assert
+	if !strings.Contains(m.View(), "v1.4.0 available") {
+		t.Errorf("menu should keep the update banner after returning from another screen")
+	}
+}
+
+func TestHelpOverlayBlocksNavigation(t *testing.T) {
+	m := New(tui.DefaultOptions())
+
+	opened, _ := menuRoute{}.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}}, m)
+	closed, _ := menuRoute{}.update(tea.KeyMsg{Type: tea.KeyEnter}, opened)
+
+	if closed.currentScreen != menuScreen {
+		t.Errorf("Enter while help is open should close help, not open the picker")
+	}
+}
+
+func TestPickerPatternPromptKeepsTypedKeys(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("KEY=value\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m := New(tui.DefaultOptions())
+	m.currentScreen = pickerScreen
+	next, _ := pickerRoute{}.update(tui.NewPickerModel(tui.GenerateExample, dir, tui.DefaultOptions())(), m)
+
+	for _, r := range "*q" {
+		next, _ = pickerRoute{}.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}, next)
+	}
+	if next.currentScreen != pickerScreen {
+		t.Errorf("q typed into the pattern prompt should not leave the picker")
+	}
+}
+
+func TestCompareScreen(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	m := New(tui.DefaultOptions()).WithSession(statePath)
+
+	picked, cmd := pickerRoute{}.update(tui.PickerFinishedMsg{
+		Selected: []string{".env.staging", ".env.production"},
+		Mode:     tui.CompareFiles,
+	}, m)
+	pm := picked
+	if pm.currentScreen != compareScreen || cmd == nil {
+		t.Fatalf("picking two files to compare should open the compare screen")
+	}
+	if _, err := os.Stat(statePath); err == nil {
+		t.Errorf("comparing files should not start a resumable session")
+	}
+
+	next, _ := compareRoute{}.update(tui.PatchWrittenMsg{Path: "staging.patch"}, pm)
+	if got := next.Summary(); !strings.Contains(got, "staging.patch") {
+		t.Errorf("summary() = %q, want the written patch listed", got)
+	}
+	next, _ = compareRoute{}.update(tui.CompareFinishedMsg{}, next)
+	if next.currentScreen != menuScreen {
+		t.Errorf("leaving the compare screen should return to the menu")
+	}
+}
+
+func TestMoveScreen(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	m := New(tui.DefaultOptions()).WithSession(statePath)
+
+	picked, cmd := pickerRoute{}.update(tui.PickerFinishedMsg{
+		Selected: []string{".env"},
+		Mode:     tui.MoveKeys,
+	}, m)
+	pm := picked
+	if pm.currentScreen != moveScreen || cmd == nil {
+		t.Fatalf("picking a file to move keys from should open the move screen")
+	}
+	if _, err := os.Stat(statePath); err == nil {
+		t.Errorf("moving keys should not start a resumable session")
+	}
+
+	next, _ := moveRoute{}.update(tui.KeysMovedMsg{From: ".env", To: "billing/.env", Keys: []string{"STRIPE_KEY"}}, pm)
+	if got := next.Summary(); !strings.Contains(got, "billing/.env") || !strings.Contains(got, ".env\n") {
+		t.Errorf("summary() = %q, want both files listed", got)
+	}
+	next, _ = moveRoute{}.update(tui.MoveFinishedMsg{}, next)
+	if next.currentScreen != menuScreen {
+		t.Errorf("leaving the move screen should return to the menu")
+	}
+}
+
+func TestSessionPersistsAndResumes(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	m := New(tui.DefaultOptions()).WithSession(statePath)
+
+	if m.menu.Choice() != tui.GenerateExample {
+		t.Fatalf("fresh model should start at GenerateExample")
+	}
+
+	picked, _ := pickerRoute{}.update(tui.PickerFinishedMsg{
+		Selected: []string{"a/.env.example", "b/.env.example"},
+		Mode:     tui.GenerateEnv,
+	}, m)
+	pm := picked
+
+	_, _ = formRoute{}.update(tui.FormSavedMsg{Success: true}, pm)
+
+	session, err := state.Load(statePath)
+	if err != nil {
+		t.Fatalf("state.Load() unexpected error: %v", err)
+	}
+	if session.Mode != state.ModeEnv || len(session.Files) != 2 {
+		t.Fatalf("session = %+v", session)
+	}
+	if pending := session.Pending(); len(pending) != 1 || !strings.HasSuffix(pending[0], filepath.Join("b", ".env.example")) {
+		t.Errorf("Pending() = %v, expected only b/.env.example", pending)
+	}
+
+	restarted := New(tui.DefaultOptions()).WithSession(statePath)
+	if sessionLabel(restarted.session) != "(2 files, 1 saved)" {
+		t.Errorf("sessionLabel() = %q", sessionLabel(restarted.session))
+	}
+
+	resumed, cmd := resumeSession(restarted)
+	rm := resumed
+	if rm.currentScreen != formScreen || rm.fileIndex != 1 || cmd == nil {
+		t.Errorf("resume should open the form on the first unsaved file, got screen %v index %d", rm.currentScreen, rm.fileIndex)
+	}
+	if !rm.savedFiles[0] {
+		t.Errorf("resume should restore saved status")
+	}
+}
+
+func TestSessionFromAnotherRootIsDiscarded(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	other := t.TempDir()
+	session := state.Session{Root: other, Mode: state.ModeEnv, Files: []string{filepath.Join(other, ".env.example")}}
+	if err := state.Save(statePath, session); err != nil {
+		t.Fatalf("state.Save() unexpected error: %v", err)
+	}
+
+	m := New(tui.DefaultOptions()).WithSession(statePath)
+	if len(m.session.Files) != 0 || sessionLabel(m.session) != "" {
+		t.Errorf("a session started in %s should not be offered for resuming here, got %+v", other, m.session)
+	}
+}
+
+func TestSessionErrorsShowInStatusBar(t *testing.T) {
+	isWarning := func(cmd tea.Cmd) bool {
+		if cmd == nil {
+			return false
+		}
+		msg, ok := cmd().(tui.StatusMsg)
+		return ok && msg.Error && strings.HasPrefix(msg.Text, "Warning: ")
+	}
+
+	corrupt := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if m := New(tui.DefaultOptions()).WithSession(corrupt); m.sessionErr == nil || !isWarning(m.Init()) {
+		t.Errorf("a session that cannot be loaded should be reported in the status bar")
+	}
+
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	m := New(tui.DefaultOptions())
+	m.statePath = filepath.Join(blocker, "state.json")
+	if cmd := m.startSession([]string{".env.example"}, tui.GenerateEnv); !isWarning(cmd) {
+		t.Errorf("a session that cannot be saved should be reported in the status bar")
+	}
+}
+
+func TestUpdateFormRestoreUnmarksSaved(t *testing.T) {
+	m := Model{
+		currentScreen: formScreen,
+		fileList:      []string{"a/.env.example"},
+		savedFiles:    map[int]bool{0: true},
+	}
+
+	newModel, _ := formRoute{}.update(tui.FormRestoredMsg{Success: true}, m)
+
+	if newModel.savedFiles[0] {
+		t.Errorf("restoring a backup should mark the file as unsaved")
+	}
+}
+
+func TestSettingsScreenSavesAndAppliesChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), config.ProjectFileName)
+	m := New(tui.DefaultOptions()).WithConfig(config.Default(), path)
+
+	opened, _ := menuRoute{}.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}, m)
+	m = opened
+	if m.currentScreen != settingsScreen {
+		t.Fatalf("s should open the settings screen, got screen %v", m.currentScreen)
+	}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next, _ = next.(Model).Update(cmd())
+	m = next.(Model)
+
+	if m.cfg.Backup || m.menu.EnableBackup() {
+		t.Errorf("toggling backup should turn it off in the config and the menu")
+	}
+	loaded, err := config.LoadFrom([]string{path}, func(string) (string, bool) { return "", false })
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if loaded.Backup {
+		t.Errorf("saved config should have backup off")
+	}
+
+	back, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	back, _ = back.(Model).Update(cmd())
+	m = back.(Model)
+	if m.currentScreen != menuScreen || m.menu.EnableBackup() {
+		t.Errorf("leaving settings should return to the menu with backup off")
+	}
+}
+
+func TestStatusBarShowsLastAction(t *testing.T) {
+	m := New(tui.DefaultOptions())
+	m.currentScreen = formScreen
+	m.fileList = []string{filepath.Join("api", ".env.example"), ".env.example"}
+	m.savedFiles = map[int]bool{}
+
+	updated, _ := m.Update(tui.FormSavedMsg{Success: true, Path: filepath.Join("api", ".env"), BackupPath: filepath.Join("api", ".env.bak.1")})
+	m = updated.(Model)
+	view := m.View()
+	for _, want := range []string{"Generate .env", "file 1/2, 1 saved", "Saved " + filepath.Join("api", ".env") + " (backup created: " + filepath.Join("api", ".env.bak.1") + ")"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q:\n%s", want, view)
+		}
+	}
+
+	// The result stays visible after returning to the menu, until an error
+	// toast replaces it.
+	m = returnToMenu(m)
+	if !strings.Contains(m.View(), "Saved ") {
+		t.Error("status should survive returning to the menu")
+	}
+	updated, cmd := m.Update(tui.StatusMsg{Text: "boom", Error: true})
+	m = updated.(Model)
+	if cmd == nil || !strings.Contains(m.View(), "boom") {
+		t.Errorf("an error status should be shown and expire later")
+	}
+}
+
+// formModel opens the form on a fresh example with one key.
+func formModel(t *testing.T) Model {
+	t.Helper()
+	example := filepath.Join(t.TempDir(), ".env.example")
+	if err := os.WriteFile(example, []byte("KEY=value\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m := New(tui.DefaultOptions())
+	m.currentScreen = formScreen
+	m.fileList = []string{example}
+	m.savedFiles = map[int]bool{}
+	updated, _ := m.Update(tui.NewFormModel(example, 0, 1, m.savedFiles, false, tui.DefaultOptions())())
+	return updated.(Model)
+}
+
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func TestInterruptQuitsFromAnyScreen(t *testing.T) {
+	ctrlC := tea.KeyMsg{Type: tea.KeyCtrlC}
+	for _, s := range []screenID{menuScreen, pickerScreen, previewScreen, formScreen, settingsScreen} {
+		m := New(tui.DefaultOptions())
+		m.currentScreen = s
+		if _, cmd := m.Update(ctrlC); !isQuit(cmd) {
+			t.Errorf("Ctrl+C on screen %d should quit", s)
+		}
+	}
+}
+
+func TestInterruptConfirmsUnsavedFormEdits(t *testing.T) {
+	ctrlC := tea.KeyMsg{Type: tea.KeyCtrlC}
+	typed := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	m := formModel(t)
+	if _, cmd := m.Update(ctrlC); !isQuit(cmd) {
+		t.Fatal("Ctrl+C without edits should quit right away")
+	}
+
+	updated, _ := m.Update(typed("x"))
+	m = updated.(Model)
+	updated, cmd := m.Update(ctrlC)
+	m = updated.(Model)
+	if isQuit(cmd) || !strings.Contains(m.View(), "Quit anyway? y/N") {
+		t.Fatalf("Ctrl+C with unsaved edits should ask first:\n%s", m.View())
+	}
+	updated, cmd = m.Update(typed("n"))
+	m = updated.(Model)
+	if isQuit(cmd) || m.confirmQuit {
+		t.Fatal("n should keep the form open")
+	}
+	if got := m.form.View(); !strings.Contains(got, "valuex") {
+		t.Errorf("the answer should not be typed into the form:\n%s", got)
+	}
+
+	updated, _ = m.Update(ctrlC)
+	if _, cmd := updated.Update(typed("y")); !isQuit(cmd) {
+		t.Error("y should quit")
+	}
+}
+
+func TestInterruptWaitsForSave(t *testing.T) {
+	m := formModel(t)
+	updated, save := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if save == nil || !m.form.Busy() {
+		t.Fatal("Ctrl+S should start saving")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = updated.(Model)
+	if isQuit(cmd) || !strings.Contains(m.View(), "Finishing write") {
+		t.Fatal("Ctrl+C during a save should wait for it")
+	}
+	if _, cmd := m.Update(save()); !isQuit(cmd) {
+		t.Error("the program should quit once the save finishes")
+	}
+}
+
+func TestInlineModeSummary(t *testing.T) {
+	m := New(tui.DefaultOptions())
+	m.inline = true
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 60})
+	m = updated.(Model)
+	if got := m.contentHeight(); got != inlineHeight-tui.StatusBarHeight {
+		t.Errorf("contentHeight() = %d in inline mode, want %d", got, inlineHeight-tui.StatusBarHeight)
+	}
+	if got := m.Summary(); got != "No files written\n" {
+		t.Errorf("summary() = %q before any write", got)
+	}
+
+	m = formModel(t)
+	updated, _ = m.Update(tui.FormSavedMsg{Success: true, Path: "app/.env", BackupPath: "app/.env.backup"})
+	m = updated.(Model)
+	if got, want := m.Summary(), "Wrote 1 file(s):\n  app/.env (backup: app/.env.backup)\n"; got != want {
+		t.Errorf("summary() = %q, want %q", got, want)
+	}
+}
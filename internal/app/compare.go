@@ -0,0 +1,33 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+// compareRoute reports the patches written from the compare screen.
+type compareRoute struct{}
+
+func (compareRoute) update(msg tea.Msg, m Model) (Model, tea.Cmd) {
+	compareModel, cmd := m.compare.Update(msg)
+	m.compare = compareModel.(tui.CompareModel)
+
+	switch msg := msg.(type) {
+	case tui.PatchWrittenMsg:
+		m.report("Wrote patch "+msg.Path, msg.Path)
+	case tui.CompareFinishedMsg:
+		return returnToMenu(m), nil
+	}
+	return m, cmd
+}
+
+func (compareRoute) view(m Model) string {
+	return m.compare.View()
+}
+
+func (compareRoute) status(m Model) (mode, counts string) {
+	return "Compare", fmt.Sprintf("%d difference(s)", m.compare.Differences())
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+// formRoute tracks which of the picked examples have been saved, and moves
+// the form between them until every one is saved or the user leaves.
+type formRoute struct{}
+
+func (formRoute) update(msg tea.Msg, m Model) (Model, tea.Cmd) {
+	formModel, cmd := m.form.Update(msg)
+	m.form = formModel.(tui.FormModel)
+
+	switch msg := msg.(type) {
+	case tui.FormSavedMsg:
+		if !msg.Success {
+			var statusCmd tea.Cmd
+			m.statusBar, statusCmd = m.statusBar.Update(tui.StatusMsg{Text: msg.Error, Error: true})
+			return m, tea.Batch(cmd, statusCmd)
+		}
+		if msg.BackupPath != "" {
+			m.report(fmt.Sprintf("Saved %s (backup created: %s)", msg.Path, msg.BackupPath),
+				fmt.Sprintf("%s (backup: %s)", msg.Path, msg.BackupPath))
+		} else {
+			m.report("Saved "+msg.Path, msg.Path)
+		}
+		m.savedFiles[m.fileIndex] = true
+		if m.fileIndex < len(m.fileList) {
+			cmd = tea.Batch(cmd, m.markSessionSaved(m.fileList[m.fileIndex]))
+		}
+	case tui.FormRestoredMsg:
+		if !msg.Success {
+			var statusCmd tea.Cmd
+			m.statusBar, statusCmd = m.statusBar.Update(tui.StatusMsg{Text: "Restore failed: " + msg.Error, Error: true})
+			return m, tea.Batch(cmd, statusCmd)
+		}
+		if m.fileIndex < len(m.fileList) {
+			restored := filepath.Join(filepath.Dir(m.fileList[m.fileIndex]), ".env")
+			m.report("Restored "+restored+" from backup", restored+" (restored from backup)")
+		}
+		delete(m.savedFiles, m.fileIndex)
+		if m.fileIndex < len(m.fileList) {
+			cmd = tea.Batch(cmd, m.markSessionUnsaved(m.fileList[m.fileIndex]))
+		}
+	case tui.FormFinishedMsg:
+		next, ok := m.nextUnsaved(msg.Dir)
+		if !ok {
+			return returnToMenu(m), nil
+		}
+		m.fileIndex = next
+		m.currentScreen = formScreen
+		return m, tui.NewFormModel(m.fileList[m.fileIndex], m.fileIndex, len(m.fileList), m.savedFiles, m.menu.EnableBackup(), m.opts)
+	}
+
+	return m, cmd
+}
+
+// nextUnsaved returns the index of the next unsaved file in direction dir,
+// 1 or -1, wrapping around the list. It reports false when dir is 0, for
+// leaving the form, or when every other file is saved.
+func (m Model) nextUnsaved(dir int) (int, bool) {
+	n := len(m.fileList)
+	if dir == 0 || len(m.savedFiles) >= n {
+		return 0, false
+	}
+	next := (m.fileIndex + dir + n) % n
+	for i := 0; i < n-1; i++ {
+		if !m.savedFiles[next] {
+			break
+		}
+		next = (next + dir + n) % n
+	}
+	return next, !m.savedFiles[next]
+}
+
+func (formRoute) view(m Model) string {
+	return m.form.View()
+}
+
+func (formRoute) status(m Model) (mode, counts string) {
+	return "Generate .env", fmt.Sprintf("file %d/%d, %d saved", m.fileIndex+1, len(m.fileList), len(m.savedFiles))
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+// menuRoute opens the picker for the chosen action, the previous session or
+// the settings screen.
+type menuRoute struct{}
+
+func (menuRoute) update(msg tea.Msg, m Model) (Model, tea.Cmd) {
+	helpOpen := m.menu.HelpVisible()
+	menuModel, cmd := m.menu.Update(msg)
+	m.menu = menuModel.(tui.MenuModel)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !helpOpen {
+		if key.Matches(keyMsg, m.opts.Keys.Menu.Select) {
+			if m.menu.Choice() == tui.ResumeSession {
+				return resumeSession(m)
+			}
+			m.currentScreen = pickerScreen
+			m.picker.SetWindowHeight(m.contentHeight())
+			return m, tui.NewPickerModel(m.menu.Choice(), scanRoot(m.cfg), m.opts)
+		}
+		if key.Matches(keyMsg, m.opts.Keys.Menu.Settings) {
+			m.cfg.Backup = m.menu.EnableBackup()
+			m.currentScreen = settingsScreen
+			m.settings = tui.NewSettingsModel(currentSettings(m.cfg), m.opts)
+			m.settings.SetWindowWidth(m.windowWidth)
+			return m, nil
+		}
+	}
+
+	return m, cmd
+}
+
+func (menuRoute) view(m Model) string {
+	return m.menu.View()
+}
+
+func (menuRoute) status(Model) (mode, counts string) {
+	return "Menu", ""
+}
+
+// scanRoot returns the directory the picker scans.
+func scanRoot(cfg config.Config) string {
+	if cfg.Scan.Root == "" {
+		return "."
+	}
+	return cfg.Scan.Root
+}
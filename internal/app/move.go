@@ -0,0 +1,37 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+// moveRoute reports the files keys were moved or copied between.
+type moveRoute struct{}
+
+func (moveRoute) update(msg tea.Msg, m Model) (Model, tea.Cmd) {
+	moveModel, cmd := m.move.Update(msg)
+	m.move = moveModel.(tui.MoveModel)
+
+	switch msg := msg.(type) {
+	case tui.KeysMovedMsg:
+		verb, files := "Moved", []string{msg.To, msg.From}
+		if msg.Copy {
+			verb, files = "Copied", []string{msg.To}
+		}
+		m.report(fmt.Sprintf("%s %d key(s) to %s", verb, len(msg.Keys), msg.To), files...)
+	case tui.MoveFinishedMsg:
+		return returnToMenu(m), nil
+	}
+	return m, cmd
+}
+
+func (moveRoute) view(m Model) string {
+	return m.move.View()
+}
+
+func (moveRoute) status(m Model) (mode, counts string) {
+	return "Move keys", fmt.Sprintf("%d selected", len(m.move.Selected()))
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+// pickerRoute hands the picked files to the screen of the menu's action.
+type pickerRoute struct{}
+
+func (pickerRoute) update(msg tea.Msg, m Model) (Model, tea.Cmd) {
+	helpOpen := m.picker.HelpVisible()
+	prompting := m.picker.PromptVisible()
+	pickerModel, cmd := m.picker.Update(msg)
+	m.picker = pickerModel.(tui.PickerModel)
+
+	switch msg := msg.(type) {
+	case tui.PickerFinishedMsg:
+		if msg.Mode == tui.CompareFiles && len(msg.Selected) == 2 {
+			m.currentScreen = compareScreen
+			m.compare.SetWindowHeight(m.contentHeight())
+			return m, tui.NewCompareModel(msg.Selected[0], msg.Selected[1], m.opts)
+		}
+		if msg.Mode == tui.MoveKeys && len(msg.Selected) == 1 {
+			m.currentScreen = moveScreen
+			m.move.SetWindowHeight(m.contentHeight())
+			return m, tui.NewMoveModel(msg.Selected[0], m.menu.EnableBackup(), m.opts)
+		}
+		if len(msg.Selected) > 0 {
+			m.fileList = msg.Selected
+			m.fileIndex = 0
+			m.pickerMode = msg.Mode
+			m.savedFiles = make(map[int]bool)
+			sessionCmd := m.startSession(msg.Selected, msg.Mode)
+
+			if msg.Mode == tui.GenerateExample {
+				m.currentScreen = previewScreen
+				m.preview.SetWindowHeight(m.contentHeight())
+				return m, tea.Batch(tui.NewPreviewModel(msg.Selected, m.menu.EnableBackup(), m.opts), sessionCmd)
+			}
+			if msg.Mode == tui.GenerateEnv {
+				m.currentScreen = formScreen
+				return m, tea.Batch(tui.NewFormModel(msg.Selected[0], 0, len(msg.Selected), m.savedFiles, m.menu.EnableBackup(), m.opts), sessionCmd)
+			}
+		}
+		return returnToMenu(m), nil
+	case tea.KeyMsg:
+		if !helpOpen && !prompting && key.Matches(msg, m.opts.Keys.Picker.Back) {
+			return returnToMenu(m), nil
+		}
+	}
+
+	return m, cmd
+}
+
+func (pickerRoute) view(m Model) string {
+	return m.picker.View()
+}
+
+func (pickerRoute) status(m Model) (mode, counts string) {
+	files, selected := m.picker.Counts()
+	mode = "Pick .env files"
+	switch m.menu.Choice() {
+	case tui.GenerateEnv:
+		mode = "Pick .env.example files"
+	case tui.CompareFiles:
+		mode = "Pick two files to compare"
+	case tui.MoveKeys:
+		mode = "Pick the file to move keys from"
+	}
+	return mode, fmt.Sprintf("%d/%d selected", selected, files)
+}
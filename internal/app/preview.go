@@ -0,0 +1,50 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+// previewRoute reports the .env.example files written from the preview and
+// marks their sources saved in the session.
+type previewRoute struct{}
+
+func (previewRoute) update(msg tea.Msg, m Model) (Model, tea.Cmd) {
+	previewModel, cmd := m.preview.Update(msg)
+	m.preview = previewModel.(tui.PreviewModel)
+
+	if finished, ok := msg.(tui.PreviewFinishedMsg); ok {
+		written := make(map[string]bool)
+		var paths []string
+		for _, r := range finished.Results {
+			if r.Success {
+				written[r.OutputPath] = true
+				paths = append(paths, r.OutputPath)
+			}
+		}
+		if len(finished.Results) > 0 {
+			m.report(fmt.Sprintf("Wrote %d/%d .env.example file(s)", len(written), len(finished.Results)), paths...)
+		}
+		var cmds []tea.Cmd
+		for _, f := range m.fileList {
+			if written[filepath.Join(filepath.Dir(f), ".env.example")] {
+				cmds = append(cmds, m.markSessionSaved(f))
+			}
+		}
+		return returnToMenu(m), tea.Batch(cmds...)
+	}
+
+	return m, cmd
+}
+
+func (previewRoute) view(m Model) string {
+	return m.preview.View()
+}
+
+func (previewRoute) status(m Model) (mode, counts string) {
+	return "Generate .env.example", fmt.Sprintf("%d file(s)", len(m.fileList))
+}
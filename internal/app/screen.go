@@ -0,0 +1,39 @@
+package app
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// screenID names a screen of the TUI.
+type screenID int
+
+const (
+	menuScreen screenID = iota
+	pickerScreen
+	previewScreen
+	formScreen
+	compareScreen
+	moveScreen
+	settingsScreen
+)
+
+// screen routes messages to one screen and draws it. The screen's own
+// model lives in Model, so routes are stateless: update returns the root
+// model with the screen's model updated, and with another screen showing
+// when this one is done.
+type screen interface {
+	update(msg tea.Msg, m Model) (Model, tea.Cmd)
+	view(m Model) string
+	// status returns the mode and counts shown in the status bar.
+	status(m Model) (mode, counts string)
+}
+
+// screens holds the route of every screen. A new screen needs a screenID,
+// a route and an entry here.
+var screens = map[screenID]screen{
+	menuScreen:     menuRoute{},
+	pickerScreen:   pickerRoute{},
+	previewScreen:  previewRoute{},
+	formScreen:     formRoute{},
+	compareScreen:  compareRoute{},
+	moveScreen:     moveRoute{},
+	settingsScreen: settingsRoute{},
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/state"
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+// WithSession enables session persistence at path and offers to resume the
+// previous session from the menu when there is unfinished work in the
+// current directory. A session started in another directory is discarded,
+// as its files are not the ones this run works on. A session that cannot
+// be loaded is reported in the status bar once the TUI starts.
+func (m Model) WithSession(path string) Model {
+	m.statePath = path
+	if path == "" {
+		return m
+	}
+	session, err := state.Load(path)
+	if err != nil {
+		m.sessionErr = err
+		return m
+	}
+	if root, _ := filepath.Abs("."); session.Root != root {
+		return m
+	}
+	m.session = session
+	m.menu.SetResume(sessionLabel(session))
+	return m
+}
+
+// sessionLabel describes a resumable session for the menu, or returns an
+// empty string when there is nothing to resume.
+func sessionLabel(s state.Session) string {
+	if !s.Resumable() {
+		return ""
+	}
+	return fmt.Sprintf("(%d files, %d saved)", len(s.Files), len(s.Files)-len(s.Pending()))
+}
+
+// sessionWarning returns a command that reports err, from loading or saving
+// the session, in the status bar. Printing it would garble the TUI.
+func sessionWarning(err error) tea.Cmd {
+	return func() tea.Msg {
+		return tui.StatusMsg{Text: fmt.Sprintf("Warning: %v", err), Error: true}
+	}
+}
+
+// startSession records the files selected in the picker as a new session.
+// It returns a command reporting a failure to save it.
+func (m *Model) startSession(files []string, mode tui.MenuChoice) tea.Cmd {
+	if m.statePath == "" {
+		return nil
+	}
+	root, _ := filepath.Abs(".")
+	sessionMode := state.ModeExample
+	if mode == tui.GenerateEnv {
+		sessionMode = state.ModeEnv
+	}
+	absFiles := make([]string, 0, len(files))
+	for _, f := range files {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			abs = f
+		}
+		absFiles = append(absFiles, abs)
+	}
+	m.session = state.Session{Root: root, Mode: sessionMode, Files: absFiles, Saved: make(map[string]bool)}
+	return m.persistSession()
+}
+
+// markSessionSaved records that a file from the current session was written.
+func (m *Model) markSessionSaved(file string) tea.Cmd {
+	if m.statePath == "" {
+		return nil
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		abs = file
+	}
+	m.session.MarkSaved(abs)
+	return m.persistSession()
+}
+
+// markSessionUnsaved records that a previously saved file was rolled back.
+func (m *Model) markSessionUnsaved(file string) tea.Cmd {
+	if m.statePath == "" {
+		return nil
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		abs = file
+	}
+	m.session.MarkUnsaved(abs)
+	return m.persistSession()
+}
+
+// persistSession saves the session, returning a command that reports a
+// failure in the status bar.
+func (m Model) persistSession() tea.Cmd {
+	if err := state.Save(m.statePath, m.session); err != nil {
+		return sessionWarning(err)
+	}
+	return nil
+}
+
+// resumeSession reopens the unsaved files from the previous session.
+func resumeSession(m Model) (Model, tea.Cmd) {
+	cwd, _ := filepath.Abs(".")
+	var files []string
+	m.savedFiles = make(map[int]bool)
+	m.fileIndex = -1
+	for i, f := range m.session.Files {
+		display := f
+		if rel, err := filepath.Rel(cwd, f); err == nil && !strings.HasPrefix(rel, "..") {
+			display = rel
+		}
+		files = append(files, display)
+		if m.session.Saved[f] {
+			m.savedFiles[i] = true
+		} else if m.fileIndex == -1 {
+			m.fileIndex = i
+		}
+	}
+	if m.fileIndex == -1 {
+		return returnToMenu(m), nil
+	}
+	m.fileList = files
+
+	if m.session.Mode == state.ModeEnv {
+		m.pickerMode = tui.GenerateEnv
+		m.currentScreen = formScreen
+		return m, tui.NewFormModel(files[m.fileIndex], m.fileIndex, len(files), m.savedFiles, m.menu.EnableBackup(), m.opts)
+	}
+
+	var pending []string
+	for i, f := range files {
+		if !m.savedFiles[i] {
+			pending = append(pending, f)
+		}
+	}
+	m.fileList = pending
+	m.pickerMode = tui.GenerateExample
+	m.currentScreen = previewScreen
+	m.preview.SetWindowHeight(m.contentHeight())
+	return m, tui.NewPreviewModel(pending, m.menu.EnableBackup(), m.opts)
+}
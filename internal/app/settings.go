@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/tui"
+)
+
+// settingsRoute applies and saves the changes made on the settings screen.
+type settingsRoute struct{}
+
+func (settingsRoute) update(msg tea.Msg, m Model) (Model, tea.Cmd) {
+	settingsModel, cmd := m.settings.Update(msg)
+	m.settings = settingsModel.(tui.SettingsModel)
+
+	switch msg := msg.(type) {
+	case tui.SettingsChangedMsg:
+		m.cfg.Backup = msg.Settings.Backup
+		m.cfg.Example.Style = string(msg.Settings.Style)
+		m.cfg.Theme = msg.Settings.Theme
+		m.cfg.Example.Sort = string(msg.Settings.Sort)
+		m.cfg.Scan.Root = msg.Settings.ScanRoot
+		m.menu.SetEnableBackup(m.cfg.Backup)
+		m.applyConfig()
+		m.settings.SetStatus(saveSettings(m.cfg, m.configPath))
+	case tui.SettingsFinishedMsg:
+		return returnToMenu(m), nil
+	}
+
+	return m, cmd
+}
+
+func (settingsRoute) view(m Model) string {
+	return m.settings.View()
+}
+
+func (settingsRoute) status(Model) (mode, counts string) {
+	return "Settings", ""
+}
+
+// currentSettings extracts the settings screen values from cfg.
+func currentSettings(cfg config.Config) tui.Settings {
+	return tui.Settings{
+		Backup:   cfg.Backup,
+		Style:    detector.PlaceholderStyle(cfg.Example.Style),
+		Theme:    cfg.Theme,
+		Sort:     generator.SortOrder(cfg.Example.Sort),
+		ScanRoot: cfg.Scan.Root,
+	}
+}
+
+// applyConfig passes the settings screen values on to the screens opened
+// from now on, and redraws those showing in the new theme.
+func (m *Model) applyConfig() {
+	if th, err := m.cfg.ResolveTheme(); err == nil {
+		m.opts.Theme = th
+		m.settings.SetTheme(th)
+		m.statusBar.SetTheme(th)
+	}
+	if opts, err := m.cfg.ExampleOptions(); err == nil {
+		m.opts.Example = opts
+	}
+}
+
+// saveSettings writes the settings screen values to path and returns a
+// status line describing the outcome.
+func saveSettings(cfg config.Config, path string) string {
+	if path == "" {
+		return "Settings apply to this session only"
+	}
+	err := config.SetValues(path, map[string]any{
+		"backup":        cfg.Backup,
+		"example.style": cfg.Example.Style,
+		"example.sort":  cfg.Example.Sort,
+		"theme":         cfg.Theme,
+		"scan.root":     cfg.Scan.Root,
+	})
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	return "Saved to " + path
+}
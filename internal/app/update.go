@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/upgrade"
+)
+
+// updateAvailableMsg reports a newer release found by the background check.
+type updateAvailableMsg struct {
+	version string
+}
+
+// WithUpdateCheck makes the TUI check for a release newer than
+// currentVersion in the background when it starts, and announce it in the
+// menu. cachePath keeps the result of the check for a day.
+func (m Model) WithUpdateCheck(currentVersion, cachePath string) Model {
+	m.checkUpdate = updateCheck(currentVersion, cachePath)
+	return m
+}
+
+// updateCheck returns a command that checks for a newer release in the
+// background. Errors are ignored: the banner is a courtesy, not a feature
+// worth interrupting the user for.
+func updateCheck(currentVersion, cachePath string) tea.Cmd {
+	return func() tea.Msg {
+		latest, ok, err := upgrade.CheckForUpdate(context.Background(), currentVersion, cachePath)
+		if err != nil || !ok {
+			return nil
+		}
+		return updateAvailableMsg{version: latest}
+	}
+}
@@ -0,0 +1,171 @@
+// Package audit reports real-looking secrets in env files where they should
+// not be, such as committed files and .env.example templates.
+package audit
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// Severity ranks how urgently a finding needs attention.
+type Severity string
+
+// Severity levels, from most to least urgent.
+const (
+	High   Severity = "high"
+	Medium Severity = "medium"
+	Low    Severity = "low"
+)
+
+// rank orders severities so reports can be sorted and thresholds compared.
+func (s Severity) rank() int {
+	switch s {
+	case High:
+		return 3
+	case Medium:
+		return 2
+	case Low:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether s is as severe as other or more.
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() >= other.rank()
+}
+
+// FileInfo describes an env file and how it is tracked by git.
+type FileInfo struct {
+	Path    string
+	Example bool // .env.example-style template meant to be committed
+	Tracked bool // committed to git
+	Ignored bool // matched by .gitignore
+}
+
+// Finding is a single secret found in a file where it should not be.
+type Finding struct {
+	File     string   `json:"file"`
+	Key      string   `json:"key"`
+	Severity Severity `json:"severity"`
+	Reason   string   `json:"reason"`
+	// Detection is the detector's reason for treating the value as a
+	// secret.
+	Detection string `json:"detection,omitempty"`
+}
+
+// Report is the result of auditing a set of files.
+type Report struct {
+	FilesScanned int       `json:"files_scanned"`
+	Findings     []Finding `json:"findings"`
+}
+
+// CheckFile audits the entries of one file, telling secrets apart by rules.
+// Secrets in local .env files that git ignores are expected and are not
+// reported.
+func CheckFile(info FileInfo, entries []parser.Entry, rules detector.Rules) []Finding {
+	var findings []Finding
+
+	for _, entry := range entries {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok || detector.IsPlaceholder(kv.Value) {
+			continue
+		}
+		class := rules.Classify(kv.Key, kv.Value)
+		if !class.IsSecret {
+			continue
+		}
+
+		valueMatch := detector.HasSecretValue(kv.Value)
+
+		var severity Severity
+		var reason string
+		switch {
+		case info.Example && valueMatch:
+			severity, reason = High, "real-looking secret value in example file"
+		case info.Example:
+			severity, reason = Medium, "secret-named key has a concrete value in example file"
+		case info.Tracked && valueMatch:
+			severity, reason = High, "secret value committed to git"
+		case info.Tracked:
+			severity, reason = Medium, "secret-named key committed to git"
+		case !info.Ignored:
+			severity, reason = Low, "secret in file not ignored by git"
+		default:
+			continue
+		}
+
+		findings = append(findings, Finding{
+			File:      info.Path,
+			Key:       kv.Key,
+			Severity:  severity,
+			Reason:    reason,
+			Detection: class.Reason,
+		})
+	}
+
+	return findings
+}
+
+// CheckRotation reports the keys of a file whose rotate-by annotation has
+// passed at now. lastChanged, typically taken from the history log, adds
+// when each value was last written to the reason. Example files are skipped
+// since their values are placeholders.
+func CheckRotation(info FileInfo, entries []parser.Entry, lastChanged map[string]time.Time, now time.Time) []Finding {
+	if info.Example {
+		return nil
+	}
+	dates, _ := parser.RotateBy(entries)
+
+	var findings []Finding
+	for _, key := range slices.Sorted(maps.Keys(dates)) {
+		day := dates[key]
+		if !parser.Overdue(day, now) {
+			continue
+		}
+		reason := "rotation overdue since " + day.Format(time.DateOnly)
+		if changed, ok := lastChanged[key]; ok {
+			reason += ", value last changed " + changed.Local().Format(time.DateOnly)
+		}
+		findings = append(findings, Finding{
+			File:     info.Path,
+			Key:      key,
+			Severity: Medium,
+			Reason:   reason,
+		})
+	}
+	return findings
+}
+
+// Add appends findings to the report and keeps them sorted by severity,
+// then file, then key.
+func (r *Report) Add(findings ...Finding) {
+	r.Findings = append(r.Findings, findings...)
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		a, b := r.Findings[i], r.Findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity.rank() > b.Severity.rank()
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Key < b.Key
+	})
+}
+
+// Count returns the number of findings at or above the given severity.
+func (r Report) Count(threshold Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(threshold) {
+			n++
+		}
+	}
+	return n
+}
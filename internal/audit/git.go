@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitStatus reports which of the given root-relative files are tracked and
+// which are ignored by git. Outside a git repository both maps are empty.
+func GitStatus(root string, files []string) (tracked, ignored map[string]bool) {
+	tracked = make(map[string]bool)
+	ignored = make(map[string]bool)
+	if len(files) == 0 {
+		return tracked, ignored
+	}
+
+	args := make([]string, 0, len(files))
+	for _, f := range files {
+		args = append(args, filepath.ToSlash(f))
+	}
+
+	for _, f := range runGit(root, append([]string{"ls-files", "-z", "--"}, args...)...) {
+		tracked[filepath.FromSlash(f)] = true
+	}
+	for _, f := range runGit(root, append([]string{"check-ignore", "--"}, args...)...) {
+		ignored[filepath.FromSlash(f)] = true
+	}
+
+	return tracked, ignored
+}
+
+// runGit runs a git subcommand in dir and splits its NUL- or line-separated
+// output. Errors (no git, not a repository, no matches) yield no results.
+func runGit(dir string, args ...string) []string {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	_ = cmd.Run()
+
+	return strings.FieldsFunc(stdout.String(), func(r rune) bool {
+		return r == 0 || r == '\n'
+	})
+}
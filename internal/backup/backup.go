@@ -2,18 +2,44 @@
 package backup
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// Options says where backups go and whether they are compressed. The zero
+// value keeps each backup next to the file it was taken from.
+type Options struct {
+	// Dir is a single directory for backups, such as ".dotenv-tui/backups".
+	// The original location is flattened into each backup's name, so
+	// backups of apps/api/.env and apps/web/.env do not collide.
+	Dir string
+	// Compress gzips new backups. Existing backups, and restoring from
+	// them, are unaffected.
+	Compress bool
+	// Deterministic numbers new backups, one past the most recent backup of
+	// the same file, instead of naming them after the time they are taken,
+	// so that repeated runs over the same files produce the same names.
+	Deterministic bool
+}
+
 // CreateBackup creates a timestamped backup of the file at the given path.
 // Returns the backup file path on success, or an error if the backup fails.
 // If the source file does not exist, returns empty string and no error.
 // Preserves the original file's permissions.
 func CreateBackup(path string) (string, error) {
-	return CreateBackupWithFS(path, realFS{})
+	return Options{}.Create(path)
+}
+
+// Create backs up the file at path like CreateBackup, with o instead of the
+// zero Options.
+func (o Options) Create(path string) (string, error) {
+	return o.CreateWithFS(path, realFS{})
 }
 
 // realFS is the default filesystem implementation.
@@ -28,6 +54,9 @@ func (realFS) Open(name string) (io.ReadCloser, error) {
 func (realFS) CreateWithMode(name string, mode os.FileMode) (io.WriteCloser, error) {
 	return os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
 }
+func (realFS) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
 
 // FileSystem defines file operations for testing.
 type FileSystem interface {
@@ -36,9 +65,37 @@ type FileSystem interface {
 	CreateWithMode(name string, mode os.FileMode) (io.WriteCloser, error)
 }
 
+// dirMaker is implemented by filesystems that create the backup directory
+// themselves. Others fall back to os.MkdirAll.
+type dirMaker interface {
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// ensureDir creates the backup directory if one is set. It is private to
+// the user because backups hold the same secrets as the files they copy.
+func (o Options) ensureDir(fs FileSystem) error {
+	if o.Dir == "" {
+		return nil
+	}
+	mkdirAll := os.MkdirAll
+	if dm, ok := fs.(dirMaker); ok {
+		mkdirAll = dm.MkdirAll
+	}
+	if err := mkdirAll(o.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	return nil
+}
+
 // CreateBackupWithFS creates a timestamped backup using the provided filesystem interface.
 // Preserves the original file's permissions.
 func CreateBackupWithFS(path string, fs FileSystem) (string, error) {
+	return Options{}.CreateWithFS(path, fs)
+}
+
+// CreateWithFS backs up the file at path like CreateBackupWithFS, with o
+// instead of the zero Options.
+func (o Options) CreateWithFS(path string, fs FileSystem) (string, error) {
 	info, err := fs.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -48,21 +105,25 @@ func CreateBackupWithFS(path string, fs FileSystem) (string, error) {
 		return "", fmt.Errorf("failed to stat source file: %w", err)
 	}
 
-	timestamp := time.Now().Format("20060102150405.999999999")
-	backupPath := fmt.Sprintf("%s.bak.%s", path, timestamp)
-
-	srcFile, err := fs.Open(path)
+	data, err := readAll(path, fs)
 	if err != nil {
-		return "", fmt.Errorf("failed to open source file: %w", err)
+		return "", fmt.Errorf("failed to read source file: %w", err)
+	}
+
+	backupPath, exists := o.nextBackup(path, data, fs)
+	if exists {
+		return backupPath, nil
+	}
+	if err := o.ensureDir(fs); err != nil {
+		return "", err
 	}
-	defer func() { _ = srcFile.Close() }()
 
 	destFile, err := fs.CreateWithMode(backupPath, info.Mode())
 	if err != nil {
 		return "", fmt.Errorf("failed to create backup file: %w", err)
 	}
 
-	if _, err := io.Copy(destFile, srcFile); err != nil {
+	if err := o.writeBackup(destFile, data); err != nil {
 		_ = destFile.Close()
 		return "", fmt.Errorf("failed to copy file: %w", err)
 	}
@@ -74,9 +135,130 @@ func CreateBackupWithFS(path string, fs FileSystem) (string, error) {
 	return backupPath, nil
 }
 
+// PlanBackup returns the path CreateBackupWithFS would return for path
+// without writing anything, or "" if path does not exist.
+func PlanBackup(path string, fs FileSystem) (string, error) {
+	return Options{}.Plan(path, fs)
+}
+
+// Plan returns the path o.CreateWithFS would return for path without
+// writing anything, or "" if path does not exist.
+func (o Options) Plan(path string, fs FileSystem) (string, error) {
+	data, err := readAll(path, fs)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read source file: %w", err)
+	}
+	backupPath, _ := o.nextBackup(path, data, fs)
+	return backupPath, nil
+}
+
+// nextBackup returns where a backup of path holding data goes. A backup
+// identical to the most recent one adds nothing, so that one is returned
+// with exists set, and callers can still restore from it.
+func (o Options) nextBackup(path string, data []byte, fs FileSystem) (backupPath string, exists bool) {
+	latest := o.latestBackup(path, fs)
+	if latest != "" {
+		if prev, err := readBackup(latest, fs); err == nil && sha256.Sum256(prev) == sha256.Sum256(data) {
+			return latest, true
+		}
+	}
+	if o.Deterministic {
+		backupPath = o.stampedPath(path, nextSequence(latest))
+	} else {
+		backupPath = o.stampedPath(path, time.Now().Format(timestampLayout))
+	}
+	if o.Compress {
+		backupPath += gzipExt
+	}
+	return backupPath, false
+}
+
+// timestampLayout names backups by time without colons or other characters
+// that are invalid in Windows file names.
+const timestampLayout = "20060102150405.999999999"
+
 // GetBackupPath generates a backup path for the given file.
 // This is useful for testing or displaying the backup path without creating it.
 func GetBackupPath(path string, timestamp time.Time) string {
-	ts := timestamp.Format("20060102150405.999999999")
-	return fmt.Sprintf("%s.bak.%s", path, ts)
+	return Options{}.stampedPath(path, timestamp.Format(timestampLayout))
+}
+
+// stampedPath returns the path of the backup of path named by stamp.
+func (o Options) stampedPath(path, stamp string) string {
+	if o.Dir != "" {
+		return filepath.Join(o.Dir, fmt.Sprintf("%s.bak.%s", FlattenPath(path), stamp))
+	}
+	return fmt.Sprintf("%s.bak.%s", path, stamp)
+}
+
+// sequenceWidth is the width of a timestamp's integer part. Backup numbers
+// are padded to it, so names still sort in the order backups were taken.
+const sequenceWidth = len("20060102150405")
+
+// nextSequence returns the number of the backup taken after latest: one
+// past latest's number or timestamp, or 1 if there is no backup yet.
+func nextSequence(latest string) string {
+	var n uint64
+	if latest != "" {
+		name := strings.TrimSuffix(filepath.Base(latest), gzipExt)
+		stamp := name[strings.LastIndex(name, ".bak.")+len(".bak."):]
+		integer, _, _ := strings.Cut(stamp, ".")
+		n, _ = strconv.ParseUint(integer, 10, 64)
+	}
+	return fmt.Sprintf("%0*d", sequenceWidth, n+1)
+}
+
+// flattenReplacer escapes the characters that cannot appear in a single
+// file name. "%" is escaped first so distinct paths stay distinct.
+var flattenReplacer = strings.NewReplacer("%", "%25", "/", "%2F", "\\", "%5C", ":", "%3A")
+
+// FlattenPath turns path into a single file name that records where the
+// file lives, e.g. "apps%2Fapi%2F.env" for apps/api/.env. Paths under the
+// working directory are made relative to it; others keep their absolute
+// location.
+func FlattenPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				path = rel
+			}
+		}
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	return flattenReplacer.Replace(path)
+}
+
+// Restore copies the backup at backupPath over path, preserving the backup's
+// permissions and decompressing gzipped backups. The backup file itself is
+// left in place.
+func Restore(backupPath, path string) error {
+	info, err := os.Stat(backupPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat backup file: %w", err)
+	}
+
+	data, err := readBackup(backupPath, realFS{})
+	if err != nil {
+		return fmt.Errorf("failed to read backup file: %w", err)
+	}
+
+	destFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return fmt.Errorf("failed to open target file: %w", err)
+	}
+
+	if _, err := destFile.Write(data); err != nil {
+		_ = destFile.Close()
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	if err := destFile.Close(); err != nil {
+		return fmt.Errorf("failed to close target file: %w", err)
+	}
+
+	return nil
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -186,6 +188,13 @@ func TestCreateBackupWithFS(t *testing.T) {
 	}
 }
 
+func TestGetBackupPathIsPortable(t *testing.T) {
+	got := filepath.Base(GetBackupPath(".env", time.Date(2026, 12, 31, 23, 59, 59, 1, time.Local)))
+	if strings.ContainsAny(got, `<>:"/\|?*`) {
+		t.Errorf("backup name %q contains characters invalid on Windows", got)
+	}
+}
+
 func TestGetBackupPath(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -216,3 +225,211 @@ func TestGetBackupPath(t *testing.T) {
 		})
 	}
 }
+
+func TestRestore(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, ".env")
+	if err := os.WriteFile(target, []byte("KEY=old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backupPath, err := CreateBackup(target)
+	if err != nil {
+		t.Fatalf("CreateBackup() unexpected error: %v", err)
+	}
+	if err := os.WriteFile(target, []byte("KEY=new\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Restore(backupPath, target); err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "KEY=old\n" {
+		t.Errorf("restored content = %q, expected original", content)
+	}
+	if _, err := os.Stat(backupPath); err != nil {
+		t.Errorf("Restore() should keep the backup file: %v", err)
+	}
+
+	if err := Restore(filepath.Join(dir, "missing.bak"), target); err == nil {
+		t.Error("Restore() expected error for missing backup")
+	}
+}
+
+func TestFlattenPath(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: ".env", want: ".env"},
+		{path: filepath.Join("apps", "api", ".env"), want: "apps%2Fapi%2F.env"},
+		{path: filepath.Join("odd%2Fname", ".env"), want: "odd%252Fname%2F.env"},
+	}
+	for _, tt := range tests {
+		if got := FlattenPath(tt.path); got != tt.want {
+			t.Errorf("FlattenPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	outside := FlattenPath(filepath.Join("..", "shared", ".env"))
+	if strings.ContainsAny(outside, `/\:`) || !strings.HasSuffix(outside, "%2Fshared%2F.env") {
+		t.Errorf("FlattenPath(outside cwd) = %q, want an absolute flattened path", outside)
+	}
+}
+
+func TestCreateBackupInDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	opts := Options{Dir: filepath.Join(".dotenv-tui", "backups")}
+
+	if err := os.MkdirAll(filepath.Join("apps", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join("apps", "api", ".env")
+	if err := os.WriteFile(target, []byte("KEY=value\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backupPath, err := opts.Create(target)
+	if err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+
+	if filepath.Dir(backupPath) != opts.Dir {
+		t.Errorf("backup %q should be written to %q", backupPath, opts.Dir)
+	}
+	if !strings.HasPrefix(filepath.Base(backupPath), "apps%2Fapi%2F.env.bak.") {
+		t.Errorf("backup name %q should record the original location", filepath.Base(backupPath))
+	}
+	content, err := os.ReadFile(backupPath)
+	if err != nil || string(content) != "KEY=value\n" {
+		t.Errorf("backup content = %q, %v; want the original", content, err)
+	}
+	entries, _ := os.ReadDir(filepath.Join("apps", "api"))
+	if len(entries) != 1 {
+		t.Errorf("no backup should be left next to the original, found %d files", len(entries))
+	}
+}
+
+func TestCreateBackupSkipsUnchangedContent(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, ".env")
+	if err := os.WriteFile(target, []byte("KEY=one\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := CreateBackup(target)
+	if err != nil {
+		t.Fatalf("CreateBackup() unexpected error: %v", err)
+	}
+	again, err := CreateBackup(target)
+	if err != nil {
+		t.Fatalf("CreateBackup() unexpected error: %v", err)
+	}
+	if again != first {
+		t.Errorf("unchanged file should reuse backup %q, got %q", first, again)
+	}
+
+	if err := os.WriteFile(target, []byte("KEY=two\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := CreateBackup(target)
+	if err != nil {
+		t.Fatalf("CreateBackup() unexpected error: %v", err)
+	}
+	if changed == first {
+		t.Errorf("changed file should get a new backup")
+	}
+	if got := LatestBackup(target, realFS{}); got != changed {
+		t.Errorf("LatestBackup() = %q, want %q", got, changed)
+	}
+
+	backups, _ := filepath.Glob(target + ".bak.*")
+	if len(backups) != 2 {
+		t.Errorf("expected 2 backups, found %v", backups)
+	}
+}
+
+func TestCreateBackupCompressed(t *testing.T) {
+	opts := Options{Compress: true}
+	dir := t.TempDir()
+	target := filepath.Join(dir, ".env")
+	if err := os.WriteFile(target, []byte("KEY=old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backupPath, err := opts.Create(target)
+	if err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(backupPath, ".gz") {
+		t.Fatalf("compressed backup %q should end in .gz", backupPath)
+	}
+	raw, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+		t.Errorf("backup should be gzipped, got %q", raw)
+	}
+
+	if again, _ := opts.Create(target); again != backupPath {
+		t.Errorf("unchanged file should reuse compressed backup %q, got %q", backupPath, again)
+	}
+
+	if err := os.WriteFile(target, []byte("KEY=new\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(backupPath, target); err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "KEY=old\n" {
+		t.Errorf("restored content = %q, expected original", content)
+	}
+}
+
+func TestCreateBackupDeterministic(t *testing.T) {
+	opts := Options{Deterministic: true}
+	dir := t.TempDir()
+	target := filepath.Join(dir, ".env")
+	var got []string
+	for _, content := range []string{"KEY=one\n", "KEY=two\n"} {
+		if err := os.WriteFile(target, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		backupPath, err := opts.Create(target)
+		if err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+		got = append(got, filepath.Base(backupPath))
+	}
+	want := []string{".env.bak.00000000000001", ".env.bak.00000000000002"}
+	if !slices.Equal(got, want) {
+		t.Errorf("backups = %v, want %v", got, want)
+	}
+
+	// A timestamped backup from an earlier run is followed, not overwritten.
+	if err := os.WriteFile(target+".bak.20260102030405.5", []byte("KEY=old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("KEY=three\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	next, err := opts.Plan(target, realFS{})
+	if err != nil {
+		t.Fatalf("Plan() unexpected error: %v", err)
+	}
+	if filepath.Base(next) != ".env.bak.20260102030406" {
+		t.Errorf("Plan() = %q, want the number after the latest timestamp", next)
+	}
+}
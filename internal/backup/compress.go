@@ -0,0 +1,100 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gzipExt is appended to the names of compressed backups.
+const gzipExt = ".gz"
+
+// dirReader is implemented by filesystems that can list the backups of a
+// file themselves. Others fall back to os.ReadDir.
+type dirReader interface {
+	ReadDir(name string) ([]os.DirEntry, error)
+}
+
+// backupLocation returns the directory holding path's backups and the
+// name prefix they share, e.g. ".env.bak.".
+func (o Options) backupLocation(path string) (string, string) {
+	if o.Dir != "" {
+		return o.Dir, FlattenPath(path) + ".bak."
+	}
+	return filepath.Dir(path), filepath.Base(path) + ".bak."
+}
+
+// LatestBackup returns the most recent backup of path, compressed or not,
+// or "" if there is none.
+func LatestBackup(path string, fs FileSystem) string {
+	return Options{}.latestBackup(path, fs)
+}
+
+func (o Options) latestBackup(path string, fs FileSystem) string {
+	location, prefix := o.backupLocation(path)
+	readDir := os.ReadDir
+	if dr, ok := fs.(dirReader); ok {
+		readDir = dr.ReadDir
+	}
+	entries, err := readDir(location)
+	if err != nil {
+		return ""
+	}
+
+	// Timestamps have a fixed-width integer part, so names sort by time.
+	latest, latestStamp := "", ""
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), gzipExt)
+		if stamp > latestStamp {
+			latest, latestStamp = name, stamp
+		}
+	}
+	if latest == "" {
+		return ""
+	}
+	return filepath.Join(location, latest)
+}
+
+func readAll(path string, fs FileSystem) ([]byte, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return io.ReadAll(f)
+}
+
+// readBackup returns the content of a backup, decompressing it if needed.
+func readBackup(path string, fs FileSystem) ([]byte, error) {
+	data, err := readAll(path, fs)
+	if err != nil || !strings.HasSuffix(path, gzipExt) {
+		return data, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	defer func() { _ = zr.Close() }()
+	return io.ReadAll(zr)
+}
+
+// writeBackup writes data to w, gzipped if compression is enabled.
+func (o Options) writeBackup(w io.Writer, data []byte) error {
+	if !o.Compress {
+		_, err := w.Write(data)
+		return err
+	}
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
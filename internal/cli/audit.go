@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jellydn/dotenv-tui/internal/audit"
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/history"
+)
+
+// ErrAuditFindings is returned by AuditFiles when high-severity findings exist,
+// so callers can exit non-zero without printing an extra error.
+var ErrAuditFindings = errors.New("audit found high-severity secrets")
+
+// GitInspector reports git status for files, for testing.
+type GitInspector interface {
+	Status(root string, files []string) (tracked, ignored map[string]bool)
+}
+
+// RealGitInspector is the default GitInspector backed by the git CLI.
+type RealGitInspector struct{}
+
+// Status implements GitInspector.Status.
+func (RealGitInspector) Status(root string, files []string) (tracked, ignored map[string]bool) {
+	return audit.GitStatus(root, files)
+}
+
+// Output formats supported by reporting commands.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// AuditFiles scans dir for env files and example files and reports secrets
+// found where they should not be, and keys past their rotate-by date, in
+// text or JSON format. Keys masking keeps unmasked are expected to have
+// values in examples and are not reported there.
+func AuditFiles(ctx context.Context, dir string, format string, masking detector.Masking, settings Settings, sc DirScanner, fs FileSystem, git GitInspector, out io.Writer) error {
+	if dir == "" {
+		dir = "."
+	}
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unsupported format %q (use %s or %s)", format, FormatText, FormatJSON)
+	}
+
+	envFiles, err := sc.Scan(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+	exampleFiles, err := sc.ScanExamples(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan for .env.example files: %w", err)
+	}
+
+	allFiles := append(append([]string{}, envFiles...), exampleFiles...)
+	tracked, ignored := git.Status(dir, allFiles)
+
+	examples := make(map[string]bool, len(exampleFiles))
+	for _, f := range exampleFiles {
+		examples[f] = true
+	}
+
+	// The history log only adds detail to rotation findings, so a log that
+	// cannot be read is ignored.
+	var records []history.Record
+	if settings.History != "" {
+		records, _ = history.Load(settings.History)
+	}
+
+	var report audit.Report
+	for _, file := range allFiles {
+		entries, err := parseAndClose(filepath.Join(dir, file), fs)
+		if err != nil {
+			return err
+		}
+		report.FilesScanned++
+		info := audit.FileInfo{
+			Path:    file,
+			Example: examples[file],
+			Tracked: tracked[file],
+			Ignored: ignored[file],
+		}
+		fileMask, err := settings.fileMasking(filepath.Join(dir, file), masking)
+		if err != nil {
+			return err
+		}
+		for _, f := range audit.CheckFile(info, entries, fileMask.Rules) {
+			if !info.Example || !fileMask.KeepsKey(f.Key) {
+				report.Add(f)
+			}
+		}
+		report.Add(audit.CheckRotation(info, entries, history.LastChanged(records, filepath.Join(dir, file)), now())...)
+	}
+
+	if format == FormatJSON {
+		if report.Findings == nil {
+			report.Findings = []audit.Finding{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+	} else {
+		writeAuditText(report, out)
+	}
+
+	if report.Count(audit.High) > 0 {
+		return ErrAuditFindings
+	}
+	return nil
+}
+
+func writeAuditText(report audit.Report, out io.Writer) {
+	if len(report.Findings) == 0 {
+		_, _ = fmt.Fprintf(out, "Audited %d file(s): no misplaced secrets or overdue rotations found\n", report.FilesScanned)
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "Audited %d file(s): %d finding(s)\n\n", report.FilesScanned, len(report.Findings))
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, f := range report.Findings {
+		reason := f.Reason
+		if f.Detection != "" {
+			reason += " (" + f.Detection + ")"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", strings.ToUpper(string(f.Severity)), f.File, f.Key, reason)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+	"github.com/jellydn/dotenv-tui/internal/schema"
+)
+
+// ErrCheckFailed is returned by CheckFiles when an env file is missing,
+// lacks keys its example defines or leaves required keys empty, so callers
+// can exit non-zero without printing an extra error.
+var ErrCheckFailed = errors.New("check found missing keys")
+
+// maxSuggestionDistance is the largest edit distance at which a key in the
+// env file is suggested as a typo of a missing one.
+const maxSuggestionDistance = 2
+
+// missingKey is a key defined in an example but not in its env file.
+type missingKey struct {
+	Key string `json:"key"`
+	// Suggestion is a key in the env file that is probably a misspelling
+	// of Key, e.g. DATABSE_URL for DATABASE_URL.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// checkResult compares one example with the env file generated from it.
+type checkResult struct {
+	Env     string       `json:"env"`
+	Example string       `json:"example"`
+	Exists  bool         `json:"exists"`
+	Missing []missingKey `json:"missing"`
+	// Empty are the keys the example requires that the env file defines
+	// without a value, or with the example's placeholder still in place.
+	Empty []string `json:"empty"`
+}
+
+// CheckFiles scans dir for example files and reports the keys each defines
+// that the matching env file lacks, suggesting likely typos among the env
+// file's own keys, and the keys the example requires (see schema.Required)
+// that the env file leaves empty, in text or JSON format.
+func CheckFiles(ctx context.Context, dir string, format string, sc DirScanner, fs FileSystem, out io.Writer) error {
+	if dir == "" {
+		dir = "."
+	}
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unsupported format %q (use %s or %s)", format, FormatText, FormatJSON)
+	}
+
+	exampleFiles, err := sc.ScanExamples(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan for .env.example files: %w", err)
+	}
+
+	results := []checkResult{}
+	failed := false
+	for _, example := range exampleFiles {
+		exampleEntries, err := parseAndClose(filepath.Join(dir, example), fs)
+		if err != nil {
+			return err
+		}
+		result := checkResult{Env: scanner.ExampleTarget(example), Example: example, Missing: []missingKey{}, Empty: []string{}}
+		envPath := filepath.Join(dir, result.Env)
+		if fileExists(fs, envPath) {
+			envEntries, err := parseAndClose(envPath, fs)
+			if err != nil {
+				return err
+			}
+			result.Exists = true
+			result.Missing = missingKeys(exampleEntries, envEntries)
+			result.Empty = emptyRequiredKeys(exampleEntries, envEntries)
+		}
+		if !result.Exists || len(result.Missing) > 0 || len(result.Empty) > 0 {
+			failed = true
+		}
+		results = append(results, result)
+	}
+
+	if format == FormatJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+	} else {
+		writeCheckText(results, out)
+	}
+
+	if failed {
+		return ErrCheckFailed
+	}
+	return nil
+}
+
+func writeCheckText(results []checkResult, out io.Writer) {
+	ok := 0
+	for _, r := range results {
+		if !r.Exists {
+			_, _ = fmt.Fprintf(out, "%s: not found (expected by %s)\n", r.Env, r.Example)
+			continue
+		}
+		if len(r.Missing) > 0 {
+			_, _ = fmt.Fprintf(out, "%s: missing %d key(s) from %s\n", r.Env, len(r.Missing), r.Example)
+			for _, m := range r.Missing {
+				if m.Suggestion != "" {
+					_, _ = fmt.Fprintf(out, "  %s (did you mean %s?)\n", m.Key, m.Suggestion)
+				} else {
+					_, _ = fmt.Fprintf(out, "  %s\n", m.Key)
+				}
+			}
+		}
+		if len(r.Empty) > 0 {
+			_, _ = fmt.Fprintf(out, "%s: %d required key(s) without a value (mark them \"# optional\" in %s if they may be empty)\n", r.Env, len(r.Empty), r.Example)
+			for _, key := range r.Empty {
+				_, _ = fmt.Fprintf(out, "  %s\n", key)
+			}
+		}
+		if len(r.Missing) == 0 && len(r.Empty) == 0 {
+			ok++
+		}
+	}
+	_, _ = fmt.Fprintf(out, "Checked %d file(s): %d complete\n", len(results), ok)
+}
+
+// missingKeys returns the keys of example that env does not define. Each
+// is paired with the closest key env defines but example does not, if one
+// is within maxSuggestionDistance.
+func missingKeys(example, env []parser.Entry) []missingKey {
+	exampleKeys := keySet(example)
+	envKeys := keySet(env)
+
+	var extra []string
+	for _, e := range env {
+		if kv, ok := e.(parser.KeyValue); ok && !exampleKeys[kv.Key] {
+			extra = append(extra, kv.Key)
+		}
+	}
+
+	missing := []missingKey{}
+	seen := make(map[string]bool)
+	for _, e := range example {
+		kv, ok := e.(parser.KeyValue)
+		if !ok || envKeys[kv.Key] || seen[kv.Key] {
+			continue
+		}
+		seen[kv.Key] = true
+		missing = append(missing, missingKey{Key: kv.Key, Suggestion: closestKey(kv.Key, extra)})
+	}
+	return missing
+}
+
+// emptyRequiredKeys returns the keys example requires that env defines
+// but leaves empty or set to the example's placeholder, in the env file's order.
+func emptyRequiredKeys(example, env []parser.Entry) []string {
+	required := schema.Required(example)
+	placeholders := make(map[string]string)
+	for _, e := range example {
+		if kv, ok := e.(parser.KeyValue); ok && schema.HasNoDefault(kv.Value) {
+			placeholders[kv.Key] = kv.Value
+		}
+	}
+
+	empty := []string{}
+	seen := make(map[string]bool)
+	for _, e := range env {
+		kv, ok := e.(parser.KeyValue)
+		if !ok || !required[kv.Key] || seen[kv.Key] {
+			continue
+		}
+		seen[kv.Key] = true
+		if value := strings.TrimSpace(kv.Value); value == "" || value == strings.TrimSpace(placeholders[kv.Key]) {
+			empty = append(empty, kv.Key)
+		}
+	}
+	return empty
+}
+
+func keySet(entries []parser.Entry) map[string]bool {
+	keys := make(map[string]bool)
+	for _, e := range entries {
+		if kv, ok := e.(parser.KeyValue); ok {
+			keys[kv.Key] = true
+		}
+	}
+	return keys
+}
+
+// closestKey returns the candidate nearest to key, ignoring case, or "" if
+// none is within maxSuggestionDistance. Short keys allow fewer edits, so
+// PORT is not mistaken for HOST. Ties go to the earlier candidate.
+func closestKey(key string, candidates []string) string {
+	best, bestDist := "", min(maxSuggestionDistance, len([]rune(key))/3)+1
+	for _, c := range candidates {
+		if d := levenshtein(strings.ToUpper(key), strings.ToUpper(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the number of single-rune insertions, deletions and
+// substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
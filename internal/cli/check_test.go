@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckFiles(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[filepath.Join("proj", ".env.example")] = "DATABASE_URL=\nPORT=3000\nREDIS_URL=\n"
+	fs.files[filepath.Join("proj", ".env")] = "DATABSE_URL=postgres://localhost/app\nPORT=4000\n"
+	fs.files[filepath.Join("proj", "api", ".env.sample")] = "TOKEN=\n"
+	fs.files[filepath.Join("proj", "web", ".env.example")] = "HOST=\n"
+	fs.files[filepath.Join("proj", "web", ".env")] = "HOST=localhost\n"
+
+	sc := &mockDirScanner{exampleFiles: []string{
+		".env.example",
+		filepath.Join("api", ".env.sample"),
+		filepath.Join("web", ".env.example"),
+	}}
+
+	t.Run("text", func(t *testing.T) {
+		var out strings.Builder
+		err := CheckFiles(t.Context(), "proj", FormatText, sc, fs, &out)
+		if !errors.Is(err, ErrCheckFailed) {
+			t.Errorf("CheckFiles() error = %v, want ErrCheckFailed", err)
+		}
+		for _, want := range []string{
+			".env: missing 2 key(s) from .env.example",
+			"DATABASE_URL (did you mean DATABSE_URL?)",
+			"  REDIS_URL\n",
+			filepath.Join("api", ".env") + ": not found",
+			"Checked 3 file(s): 1 complete",
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out strings.Builder
+		_ = CheckFiles(t.Context(), "proj", FormatJSON, sc, fs, &out)
+		var results []checkResult
+		if err := json.Unmarshal([]byte(out.String()), &results); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+		}
+		if len(results) != 3 || results[0].Missing[0].Suggestion != "DATABSE_URL" || results[1].Exists {
+			t.Errorf("results = %+v", results)
+		}
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		var out strings.Builder
+		complete := &mockDirScanner{exampleFiles: []string{filepath.Join("web", ".env.example")}}
+		if err := CheckFiles(t.Context(), "proj", FormatText, complete, fs, &out); err != nil {
+			t.Errorf("CheckFiles() unexpected error: %v", err)
+		}
+	})
+}
+
+func TestCheckFilesEmptyRequiredKeys(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env.example"] = "PORT=3000\nAPI_KEY=\nSTRIPE_KEY=sk_***\n# optional\nSENTRY_DSN=\n"
+	fs.files[".env"] = "PORT=\nAPI_KEY=\nSTRIPE_KEY=sk_***\nSENTRY_DSN=\n"
+	sc := &mockDirScanner{exampleFiles: []string{".env.example"}}
+
+	var out strings.Builder
+	if err := CheckFiles(t.Context(), ".", FormatText, sc, fs, &out); !errors.Is(err, ErrCheckFailed) {
+		t.Errorf("CheckFiles() error = %v, want ErrCheckFailed", err)
+	}
+	for _, want := range []string{".env: 2 required key(s) without a value", "  API_KEY\n", "  STRIPE_KEY\n", "Checked 1 file(s): 0 complete"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	for _, unwanted := range []string{"PORT", "SENTRY_DSN"} {
+		if strings.Contains(out.String(), unwanted) {
+			t.Errorf("output should not list %s, which has a default or is optional:\n%s", unwanted, out.String())
+		}
+	}
+
+	fs.files[".env"] = "PORT=\nAPI_KEY=abc\nSTRIPE_KEY=sk_test_123\nSENTRY_DSN=\n"
+	if err := CheckFiles(t.Context(), ".", FormatText, sc, fs, &strings.Builder{}); err != nil {
+		t.Errorf("CheckFiles() unexpected error once required keys are set: %v", err)
+	}
+}
+
+func TestClosestKey(t *testing.T) {
+	tests := []struct {
+		key        string
+		candidates []string
+		want       string
+	}{
+		{"DATABASE_URL", []string{"DATABSE_URL"}, "DATABSE_URL"},
+		{"API_KEY", []string{"APIKEY", "API_KEYS"}, "APIKEY"},
+		{"SECRET", []string{"secret"}, "secret"},
+		{"PORT", []string{"HOST"}, ""},
+		{"REDIS_URL", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := closestKey(tt.key, tt.candidates); got != tt.want {
+			t.Errorf("closestKey(%q, %v) = %q, want %q", tt.key, tt.candidates, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"PORT", "", 4},
+		{"kitten", "sitting", 3},
+		{"DATABASE_URL", "DATABSE_URL", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
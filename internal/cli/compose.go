@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+)
+
+// ComposeScanner finds the Docker Compose services of a directory, for
+// testing.
+type ComposeScanner interface {
+	ScanCompose(ctx context.Context, root string) ([]scanner.ComposeService, error)
+}
+
+// composeConsumers maps each env file path to the names of the compose
+// services reading it through env_file.
+func composeConsumers(services []scanner.ComposeService) map[string][]string {
+	consumers := make(map[string][]string)
+	for _, s := range services {
+		for _, f := range s.EnvFiles {
+			if !slices.Contains(consumers[f.Path], s.Name) {
+				consumers[f.Path] = append(consumers[f.Path], s.Name)
+			}
+		}
+	}
+	for _, names := range consumers {
+		slices.Sort(names)
+	}
+	return consumers
+}
+
+// listCompose prints the compose services that set environment variables,
+// with their env files and environment keys, and warns about required env
+// files that do not exist.
+func listCompose(services []scanner.ComposeService, out io.Writer) {
+	var listed []scanner.ComposeService
+	for _, s := range services {
+		if len(s.EnvFiles) > 0 || len(s.Environment) > 0 {
+			listed = append(listed, s)
+		}
+	}
+	if len(listed) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "Found %d Docker Compose service(s) with environment:\n", len(listed))
+	var missing []string
+	for _, s := range listed {
+		var parts, paths []string
+		for _, f := range s.EnvFiles {
+			paths = append(paths, f.Path)
+			if !f.Exists && f.Required {
+				missing = append(missing, fmt.Sprintf("service %s in %s reads %s, which does not exist", s.Name, filepath.Base(s.File), f.Path))
+			}
+		}
+		if len(paths) > 0 {
+			parts = append(parts, "env_file "+strings.Join(paths, ", "))
+		}
+		if len(s.Environment) > 0 {
+			parts = append(parts, "environment "+strings.Join(s.Environment, ", "))
+		}
+		_, _ = fmt.Fprintf(out, "  %s (%s): %s\n", s.Name, filepath.Base(s.File), strings.Join(parts, "; "))
+	}
+	for _, m := range missing {
+		_, _ = fmt.Fprintf(out, "Warning: %s\n", m)
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+)
+
+type mockComposeScanner struct {
+	mockDirScanner
+	services []scanner.ComposeService
+}
+
+func (m *mockComposeScanner) ScanCompose(_ context.Context, _ string) ([]scanner.ComposeService, error) {
+	return m.services, nil
+}
+
+func TestScanAndListCompose(t *testing.T) {
+	sc := &mockComposeScanner{
+		mockDirScanner: mockDirScanner{scanFiles: []string{".env", "api/.env", "web/.env"}},
+		services: []scanner.ComposeService{
+			{Name: "worker", File: "compose.yaml", EnvFiles: []scanner.ComposeEnvFile{{Path: ".env", Required: true, Exists: true}}},
+			{Name: "api", File: "compose.yaml", Environment: []string{"PORT"}, EnvFiles: []scanner.ComposeEnvFile{
+				{Path: ".env", Required: true, Exists: true},
+				{Path: "api/.env", Required: true, Exists: true},
+				{Path: "api/.env.local"},
+				{Path: "api/secrets.env", Required: true},
+			}},
+			{Name: "db", File: "compose.yaml"},
+		},
+	}
+	var out strings.Builder
+	if err := ScanAndList(t.Context(), ".", sc, &out); err != nil {
+		t.Fatalf("ScanAndList() error = %v", err)
+	}
+	want := `Found 3 .env file(s):
+  .env  (compose: api, worker)
+  api/.env  (compose: api)
+  web/.env
+Found 2 Docker Compose service(s) with environment:
+  worker (compose.yaml): env_file .env
+  api (compose.yaml): env_file .env, api/.env, api/.env.local, api/secrets.env; environment PORT
+Warning: service api in compose.yaml reads api/secrets.env, which does not exist
+`
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	sc.scanFiles = nil
+	out.Reset()
+	if err := ScanAndList(t.Context(), ".", sc, &out); err != nil || !strings.Contains(out.String(), "No .env files found\nFound 2 Docker Compose") {
+		t.Errorf("ScanAndList() without env files = %q, %v", out.String(), err)
+	}
+}
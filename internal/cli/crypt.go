@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/dotenvx"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// CryptOptions configures EncryptFile and DecryptFile.
+type CryptOptions struct {
+	File string
+	// Keys limits the values encrypted or decrypted; empty means all.
+	Keys []string
+	// Stdout prints the decrypted file instead of writing it.
+	Stdout       bool
+	CreateBackup bool
+	Settings     Settings
+}
+
+// EncryptFile encrypts the values of an env file the way dotenvx does, for
+// the public key stored in the file. A file without one gets a new key
+// pair: the public key is added at the top of the file and the private key
+// is saved to the .env.keys file next to it, unless the private key is
+// already set in the environment or in .env.keys.
+func EncryptFile(opts CryptOptions, fs FileSystem, env LookupFunc, out io.Writer) error {
+	before, err := parseAndClose(opts.File, fs)
+	if err != nil {
+		return err
+	}
+	if err := checkKeys(before, opts); err != nil {
+		return err
+	}
+	entries := before
+
+	publicName, privateName := dotenvx.KeyNames(opts.File)
+	keysPath := dotenvx.KeysPath(opts.File)
+	var keysEntries []parser.Entry
+	public, ok := dotenvx.Lookup(entries, publicName)
+	if !ok {
+		private, found, err := privateKey(opts.File, fs, env)
+		if err != nil {
+			return err
+		}
+		if found {
+			public, err = dotenvx.PublicKey(private)
+		} else {
+			public, private, err = dotenvx.GenerateKeyPair()
+			keysEntries = append(existingEntries(keysPath, fs), parser.KeyValue{Key: privateName, Value: private, Quoted: `"`})
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", privateName, err)
+		}
+		header := []parser.Entry{
+			parser.Comment{Text: "# " + publicName + " encrypts values for dotenvx; share it, keep " + privateName + " secret"},
+			parser.KeyValue{Key: publicName, Value: public, Quoted: `"`},
+			parser.BlankLine{},
+		}
+		entries = append(header, before...)
+	}
+
+	encrypted, keys, err := dotenvx.EncryptEntries(entries, public, opts.Keys)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", opts.File, err)
+	}
+	if len(keys) == 0 {
+		_, _ = fmt.Fprintf(out, "No values to encrypt in %s\n", opts.File)
+		return nil
+	}
+
+	// The private key is saved first, so values are never encrypted for a
+	// key that was lost.
+	if keysEntries != nil {
+		if err := opts.Settings.writeEntries(keysPath, fs, keysEntries); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "Saved %s to %s; do not commit it\n", privateName, keysPath)
+	}
+	if err := writeCrypted(history.OpEncrypt, opts, fs, before, encrypted, out); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Encrypted %d value(s) in %s: %s\n", len(keys), opts.File, strings.Join(keys, ", "))
+	return nil
+}
+
+// DecryptFile decrypts the dotenvx encrypted values of an env file with
+// the private key set in the environment or in the .env.keys file next to
+// it, and writes the file back, or prints it with opts.Stdout.
+func DecryptFile(opts CryptOptions, fs FileSystem, env LookupFunc, out io.Writer) error {
+	entries, err := parseAndClose(opts.File, fs)
+	if err != nil {
+		return err
+	}
+	if err := checkKeys(entries, opts); err != nil {
+		return err
+	}
+
+	_, privateName := dotenvx.KeyNames(opts.File)
+	private, found, err := privateKey(opts.File, fs, env)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no private key for %s: set %s or add it to %s", opts.File, privateName, dotenvx.KeysPath(opts.File))
+	}
+
+	decrypted, keys, err := dotenvx.DecryptEntries(entries, private, opts.Keys)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", opts.File, err)
+	}
+
+	if opts.Stdout {
+		return parser.WriteWithOptions(out, decrypted, opts.Settings.Write)
+	}
+	if len(keys) == 0 {
+		_, _ = fmt.Fprintf(out, "No encrypted values in %s\n", opts.File)
+		return nil
+	}
+	if err := writeCrypted(history.OpDecrypt, opts, fs, entries, decrypted, out); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Decrypted %d value(s) in %s: %s\n", len(keys), opts.File, strings.Join(keys, ", "))
+	return nil
+}
+
+// privateKey looks up the dotenvx private key of the env file at path in
+// env, then in the .env.keys file next to it.
+func privateKey(path string, fs FileSystem, env LookupFunc) (string, bool, error) {
+	_, name := dotenvx.KeyNames(path)
+	if env != nil {
+		if value, ok := env(name); ok && value != "" {
+			return value, true, nil
+		}
+	}
+	keysPath := dotenvx.KeysPath(path)
+	if !fileExists(fs, keysPath) {
+		return "", false, nil
+	}
+	entries, err := parseAndClose(keysPath, fs)
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := dotenvx.Lookup(entries, name)
+	return value, ok && value != "", nil
+}
+
+// checkKeys reports the first of opts.Keys missing from entries.
+func checkKeys(entries []parser.Entry, opts CryptOptions) error {
+	present := keySet(entries)
+	for _, key := range opts.Keys {
+		if !present[key] {
+			return fmt.Errorf("key %s not found in %s", key, opts.File)
+		}
+	}
+	return nil
+}
+
+// writeCrypted backs up and writes an encrypted or decrypted env file and
+// records it in the history.
+func writeCrypted(op string, opts CryptOptions, fs FileSystem, before, after []parser.Entry, out io.Writer) error {
+	var backupPath string
+	if opts.CreateBackup {
+		var err error
+		if backupPath, err = opts.Settings.backupFile(opts.File, fs); err != nil {
+			return fmt.Errorf("failed to create backup: %w", err)
+		}
+		if backupPath != "" {
+			_, _ = fmt.Fprintf(out, "Backup created: %s\n", backupPath)
+		}
+	}
+	if err := opts.Settings.writeEntries(opts.File, fs, after); err != nil {
+		return err
+	}
+	opts.Settings.recordWrite(op, opts.File, before, after, backupPath, out)
+	return nil
+}
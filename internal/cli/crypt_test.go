@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/dotenvx"
+)
+
+func TestEncryptDecryptFile(t *testing.T) {
+	fs := newMockFileSystem()
+	original := "# db\nDB_PASS=\"hunter 2\"\nPORT=3000\n"
+	fs.files[".env.production"] = original
+
+	var out strings.Builder
+	if err := EncryptFile(CryptOptions{File: ".env.production"}, fs, nil, &out); err != nil {
+		t.Fatalf("EncryptFile() error = %v", err)
+	}
+	encrypted := fs.files[".env.production"]
+	if !strings.HasPrefix(encrypted, "# DOTENV_PUBLIC_KEY_PRODUCTION encrypts") || strings.Contains(encrypted, "hunter") || !strings.Contains(encrypted, "DB_PASS=\"encrypted:") {
+		t.Errorf("encrypted file = %q", encrypted)
+	}
+	if !strings.Contains(fs.files[".env.keys"], "DOTENV_PRIVATE_KEY_PRODUCTION=\"") {
+		t.Errorf(".env.keys = %q, want the new private key", fs.files[".env.keys"])
+	}
+	if !strings.Contains(out.String(), "Encrypted 2 value(s) in .env.production: DB_PASS, PORT") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	// Everything is encrypted already.
+	out.Reset()
+	if err := EncryptFile(CryptOptions{File: ".env.production"}, fs, nil, &out); err != nil || !strings.Contains(out.String(), "No values to encrypt") {
+		t.Errorf("EncryptFile() again = %v, output %q", err, out.String())
+	}
+
+	out.Reset()
+	if err := DecryptFile(CryptOptions{File: ".env.production", Stdout: true}, fs, nil, &out); err != nil {
+		t.Fatalf("DecryptFile(stdout) error = %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "\n"+original) || fs.files[".env.production"] != encrypted {
+		t.Errorf("DecryptFile(stdout) printed %q and should leave the file alone", out.String())
+	}
+
+	out.Reset()
+	if err := DecryptFile(CryptOptions{File: ".env.production", Keys: []string{"PORT"}}, fs, nil, &out); err != nil {
+		t.Fatalf("DecryptFile(PORT) error = %v", err)
+	}
+	if got := fs.files[".env.production"]; !strings.Contains(got, "\nPORT=3000\n") || !strings.Contains(got, "DB_PASS=\"encrypted:") {
+		t.Errorf("only PORT should be decrypted, got %q", got)
+	}
+}
+
+func TestEncryptFileExistingKey(t *testing.T) {
+	public, private, err := dotenvx.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+	fs := newMockFileSystem()
+	fs.files[filepath.Join("api", ".env")] = "TOKEN=abc\n"
+	env := mapLookup(map[string]string{"DOTENV_PRIVATE_KEY": private})
+
+	if err := EncryptFile(CryptOptions{File: filepath.Join("api", ".env")}, fs, env, &strings.Builder{}); err != nil {
+		t.Fatalf("EncryptFile() error = %v", err)
+	}
+	if _, ok := fs.files[filepath.Join("api", ".env.keys")]; ok {
+		t.Error("a private key from the environment should not be saved to .env.keys")
+	}
+	if !strings.Contains(fs.files[filepath.Join("api", ".env")], "DOTENV_PUBLIC_KEY=\""+public+"\"") {
+		t.Errorf("file = %q, want the public key of the private key in the environment", fs.files[filepath.Join("api", ".env")])
+	}
+}
+
+func TestDecryptFileErrors(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "TOKEN=\"encrypted:AAAA\"\n"
+
+	err := DecryptFile(CryptOptions{File: ".env"}, fs, nil, &strings.Builder{})
+	if err == nil || !strings.Contains(err.Error(), "no private key for .env: set DOTENV_PRIVATE_KEY") {
+		t.Errorf("DecryptFile() error = %v, want a missing key error", err)
+	}
+
+	err = DecryptFile(CryptOptions{File: ".env", Keys: []string{"MISSING"}}, fs, nil, &strings.Builder{})
+	if err == nil || !strings.Contains(err.Error(), "key MISSING not found in .env") {
+		t.Errorf("DecryptFile() error = %v, want an unknown key error", err)
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/direnv"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// DirenvScanner finds direnv .envrc files, for testing.
+type DirenvScanner interface {
+	ScanDirenv(ctx context.Context, root string) ([]string, error)
+}
+
+// ExportDirenv converts the env file at inputPath into an .envrc next to
+// it, exporting every key with its value quoted for the shell. An existing
+// .envrc is only replaced with force, since it is often written by hand.
+func ExportDirenv(inputPath string, force bool, createBackup bool, dryRun bool, settings Settings, fs FileSystem, out io.Writer) error {
+	entries, err := parseAndClose(inputPath, fs)
+	if err != nil {
+		return err
+	}
+
+	outputPath := filepath.Join(filepath.Dir(inputPath), direnv.FileName)
+	exists := fileExists(fs, outputPath)
+	if exists && !force && !dryRun {
+		return fmt.Errorf("%s already exists. Use --force to overwrite", outputPath)
+	}
+
+	var content strings.Builder
+	skipped, err := direnv.Write(&content, entries, filepath.Base(inputPath))
+	if err != nil {
+		return fmt.Errorf("failed to convert %s: %w", inputPath, err)
+	}
+	if len(skipped) > 0 {
+		_, _ = fmt.Fprintf(out, "Warning: skipped %d key(s) that are not valid variable names: %s\n", len(skipped), strings.Join(skipped, ", "))
+	}
+
+	if dryRun {
+		status := "Would CREATE new file"
+		if exists {
+			status = "Would OVERWRITE existing file"
+		}
+		_, _ = fmt.Fprintf(out, "\n=== DRY RUN PREVIEW ===\nFile: %s\nStatus: %s\n\nContent preview:\n---\n%s---\n\n", outputPath, status, content.String())
+		return nil
+	}
+
+	before := existingDirenvEntries(outputPath, fs)
+	var backupPath string
+	if createBackup {
+		if backupPath, err = settings.backupFile(outputPath, fs); err != nil {
+			return fmt.Errorf("failed to create backup: %w", err)
+		}
+		if backupPath != "" {
+			_, _ = fmt.Fprintf(out, "Backup created: %s\n", backupPath)
+		}
+	}
+
+	outFile, err := fs.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outputPath, err)
+	}
+	if _, err := io.WriteString(outFile, content.String()); err != nil {
+		_ = outFile.Close()
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+	if err := outFile.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", outputPath, err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Generated %s; run \"direnv allow\" to load it\n", outputPath)
+	settings.recordWrite(history.OpExportDirenv, outputPath, before, entries, backupPath, out)
+	return nil
+}
+
+// existingDirenvEntries returns the entries of the .envrc at path, or nil
+// if it does not exist or cannot be read.
+func existingDirenvEntries(path string, fs FileSystem) []parser.Entry {
+	if !fileExists(fs, path) {
+		return nil
+	}
+	file, err := fs.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = file.Close() }()
+	entries, err := direnv.Parse(file)
+	if err != nil {
+		return nil
+	}
+	return entries
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportDirenv(t *testing.T) {
+	fs := newMockFileSystem()
+	env := filepath.Join("api", ".env")
+	envrc := filepath.Join("api", ".envrc")
+	fs.files[env] = "PORT=3000\nGREETING=\"it's me\"\n"
+
+	var out strings.Builder
+	if err := ExportDirenv(env, false, false, true, Settings{}, fs, &out); err != nil {
+		t.Fatalf("ExportDirenv(dry run) error = %v", err)
+	}
+	if _, ok := fs.files[envrc]; ok || !strings.Contains(out.String(), "export GREETING='it'\\''s me'") {
+		t.Errorf("a dry run should only preview, output %q", out.String())
+	}
+
+	out.Reset()
+	if err := ExportDirenv(env, false, false, false, Settings{}, fs, &out); err != nil {
+		t.Fatalf("ExportDirenv() error = %v", err)
+	}
+	want := "# Generated by dotenv-tui from .env; edit that file and export again instead.\nexport PORT=3000\nexport GREETING='it'\\''s me'\n"
+	if got := fs.files[envrc]; got != want {
+		t.Errorf(".envrc = %q, want %q", got, want)
+	}
+	if !strings.Contains(out.String(), "Generated "+envrc) {
+		t.Errorf("output = %q", out.String())
+	}
+
+	err := ExportDirenv(env, false, false, false, Settings{}, fs, &out)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("ExportDirenv() over an existing .envrc error = %v, want it refused without --force", err)
+	}
+	if err := ExportDirenv(env, true, false, false, Settings{}, fs, &out); err != nil {
+		t.Errorf("ExportDirenv(force) error = %v", err)
+	}
+}
+
+func TestExportDirenvSkipsInvalidKeys(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "$(echo pwned>&2) X=1\nPORT=3000\n"
+
+	var out strings.Builder
+	if err := ExportDirenv(".env", false, false, false, Settings{}, fs, &out); err != nil {
+		t.Fatalf("ExportDirenv() error = %v", err)
+	}
+	if got := fs.files[".envrc"]; strings.Contains(got, "pwned") || !strings.Contains(got, "export PORT=3000") {
+		t.Errorf(".envrc = %q, want the invalid key left out", got)
+	}
+	if !strings.Contains(out.String(), "skipped 1 key(s) that are not valid variable names: $(echo pwned>&2) X") {
+		t.Errorf("output = %q, want the skipped key reported", out.String())
+	}
+}
+
+type mockDirenvScanner struct {
+	mockDirScanner
+	direnvFiles []string
+}
+
+func (m *mockDirenvScanner) ScanDirenv(_ context.Context, _ string) ([]string, error) {
+	return m.direnvFiles, nil
+}
+
+func TestScanAndListDirenv(t *testing.T) {
+	sc := &mockDirenvScanner{mockDirScanner: mockDirScanner{scanFiles: []string{".env"}}, direnvFiles: []string{".envrc"}}
+	var out strings.Builder
+	if err := ScanAndList(t.Context(), ".", sc, &out); err != nil {
+		t.Fatalf("ScanAndList() error = %v", err)
+	}
+	if want := "Found 1 .env file(s):\n  .env\nFound 1 direnv file(s), read-only:\n  .envrc\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+
+	sc.scanFiles = nil
+	out.Reset()
+	if err := ScanAndList(t.Context(), ".", sc, &out); err != nil || strings.Contains(out.String(), "No .env files found") {
+		t.Errorf("ScanAndList() with only an .envrc = %q, %v", out.String(), err)
+	}
+}
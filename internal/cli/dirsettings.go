@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"path/filepath"
+
+	"github.com/jellydn/dotenv-tui/internal/backup"
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/schema"
+)
+
+// fileMasking returns the masking for the file at path.
+func (s Settings) fileMasking(path string, masking detector.Masking) (detector.Masking, error) {
+	if s.Dir == nil {
+		return masking, nil
+	}
+	d, err := s.Dir(path)
+	if err != nil {
+		return masking, err
+	}
+	return d.Example.Masking, nil
+}
+
+// fileBackup reports whether to back up the file at path before writing it.
+func (s Settings) fileBackup(path string, createBackup bool) (bool, error) {
+	if s.Dir == nil {
+		return createBackup, nil
+	}
+	d, err := s.Dir(path)
+	if err != nil {
+		return createBackup, err
+	}
+	return d.Backup, nil
+}
+
+// backupOptions returns the backup directory and compression set for the
+// file at path.
+func (s Settings) backupOptions(path string) (backup.Options, error) {
+	if s.Dir == nil {
+		return backup.Options{Deterministic: s.Deterministic}, nil
+	}
+	d, err := s.Dir(path)
+	if err != nil {
+		return backup.Options{}, err
+	}
+	return backup.Options{Dir: d.BackupDir, Compress: d.BackupCompress, Deterministic: s.Deterministic}, nil
+}
+
+// backupFile backs up the file at path with the options set for it,
+// returning the backup path.
+func (s Settings) backupFile(path string, fs FileSystem) (string, error) {
+	opts, err := s.backupOptions(path)
+	if err != nil {
+		return "", err
+	}
+	return opts.CreateWithFS(path, fsAdapter{fs})
+}
+
+// schemaPath returns the schema file describing the env file at path: the
+// one set for its directory, or the .env.schema next to it.
+func (s Settings) schemaPath(path string) (string, error) {
+	if s.Dir != nil {
+		d, err := s.Dir(path)
+		if err != nil {
+			return "", err
+		}
+		if d.Schema != "" {
+			return d.Schema, nil
+		}
+	}
+	return filepath.Join(filepath.Dir(path), schema.FileName), nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jellydn/dotenv-tui/internal/convert"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// ExportDocument prints the keys of the env file at inputPath as a JSON,
+// YAML or TOML document. With unflatten, keys are nested on sep, reversing
+// ImportFile with --flatten; otherwise the document is flat.
+func ExportDocument(inputPath, format string, unflatten bool, sep string, fs FileSystem, out io.Writer) error {
+	entries, err := parseAndClose(inputPath, fs)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]string)
+	for _, entry := range entries {
+		if kv, ok := entry.(parser.KeyValue); ok {
+			values[kv.Key] = kv.Value
+		}
+	}
+
+	doc := convert.Flat(values)
+	if unflatten {
+		if doc, err = convert.Unflatten(values, sep); err != nil {
+			return fmt.Errorf("failed to unflatten %s: %w", inputPath, err)
+		}
+	}
+	if err := convert.Encode(out, format, doc); err != nil {
+		return fmt.Errorf("failed to write %s: %w", format, err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportDocument(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "# db\nDATABASE__HOST=db\nDATABASE__PORT=5432\nDEBUG=true\n"
+
+	var out strings.Builder
+	if err := ExportDocument(".env", "yaml", true, "__", fs, &out); err != nil {
+		t.Fatalf("ExportDocument() error = %v", err)
+	}
+	if want := "database:\n  host: db\n  port: \"5432\"\ndebug: \"true\"\n"; out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := ExportDocument(".env", "json", false, "", fs, &out); err != nil {
+		t.Fatalf("ExportDocument(flat) error = %v", err)
+	}
+	if !strings.Contains(out.String(), `"DATABASE__HOST": "db"`) {
+		t.Errorf("flat output = %s", out.String())
+	}
+
+	if err := ExportDocument(".env", "ini", false, "", fs, &out); err == nil || !strings.Contains(err.Error(), "unsupported format") {
+		t.Errorf("ExportDocument(ini) error = %v", err)
+	}
+}
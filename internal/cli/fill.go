@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// LoadAnswers reads key/value answers from a .env-style file, or from a JSON
+// object when the file has a .json extension. JSON numbers and booleans are
+// converted to their string form.
+func LoadAnswers(path string, fs FileSystem) (map[string]string, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return loadJSONAnswers(path, fs)
+	}
+
+	entries, err := parseAndClose(path, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make(map[string]string)
+	for _, entry := range entries {
+		if kv, ok := entry.(parser.KeyValue); ok {
+			answers[kv.Key] = kv.Value
+		}
+	}
+	return answers, nil
+}
+
+func loadJSONAnswers(path string, fs FileSystem) (map[string]string, error) {
+	file, err := fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	answers := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			answers[key] = v
+		case float64, bool:
+			answers[key] = fmt.Sprint(v)
+		case nil:
+			answers[key] = ""
+		default:
+			return nil, fmt.Errorf("invalid value for %s in %s: must be a string, number or boolean", key, path)
+		}
+	}
+	return answers, nil
+}
+
+// LookupFunc returns the value for a key and whether one was found, with the
+// same contract as os.LookupEnv.
+type LookupFunc func(key string) (string, bool)
+
+// mapLookup adapts a map of answers to a LookupFunc.
+func mapLookup(values map[string]string) LookupFunc {
+	if len(values) == 0 {
+		return nil
+	}
+	return func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+}
+
+// fillValues replaces the value of every entry that lookup knows about and
+// returns the updated entries with the keys that were filled, in file order.
+// Keys listed in skip are left alone. Values that would not survive unquoted
+// are quoted, as parser.QuoteValue quotes them.
+func fillValues(entries []parser.Entry, lookup LookupFunc, skip ...string) ([]parser.Entry, []string) {
+	if lookup == nil {
+		return entries, nil
+	}
+
+	var filled []string
+	result := make([]parser.Entry, len(entries))
+	for i, entry := range entries {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok || slices.Contains(skip, kv.Key) {
+			result[i] = entry
+			continue
+		}
+		value, found := lookup(kv.Key)
+		if !found {
+			result[i] = entry
+			continue
+		}
+
+		kv.Value = value
+		result[i] = parser.QuoteValue(kv)
+		filled = append(filled, kv.Key)
+	}
+	return result, filled
+}
+
+// fillAnswers applies answers and then, for the keys answers does not
+// cover, env to entries. via names where the answers came from, such as
+// "--set", for provenance comments. The entries are for a new file, so the
+// example's encoding is dropped and the file is written as plain UTF-8.
+func (s Settings) fillAnswers(entries []parser.Entry, answers map[string]string, via string, env LookupFunc) (filled []parser.Entry, answered, fromEnv []string) {
+	filled, answered = fillValues(withoutEncoding(entries), mapLookup(answers))
+	filled, fromEnv = fillValues(filled, env, answered...)
+	filled = s.annotateKeys(filled, answered, parser.SetVia(via, s.today()))
+	filled = s.annotateKeys(filled, fromEnv, parser.SourcedFrom("environment", s.today()))
+	return filled, answered, fromEnv
+}
+
+// withoutEncoding returns entries without the Encoding that Parse puts
+// first for a file starting with a byte order mark, so that env files
+// generated from a UTF-16 example are plain UTF-8, which loaders can read.
+func withoutEncoding(entries []parser.Entry) []parser.Entry {
+	if len(entries) > 0 {
+		if _, ok := entries[0].(parser.Encoding); ok {
+			return entries[1:]
+		}
+	}
+	return entries
+}
+
+// now dates provenance comments; tests replace it.
+var now = time.Now
+
+// today returns the day to date provenance comments and changelog blocks
+// with, or the zero time, which leaves the date out, in deterministic mode.
+func (s Settings) today() time.Time {
+	if s.Deterministic {
+		return time.Time{}
+	}
+	return now()
+}
+
+// annotateKeys gives keys the provenance note when annotating is enabled.
+func (s Settings) annotateKeys(entries []parser.Entry, keys []string, note string) []parser.Entry {
+	if !s.Annotate || len(keys) == 0 {
+		return entries
+	}
+	notes := make(map[string]string, len(keys))
+	for _, key := range keys {
+		notes[key] = note
+	}
+	return parser.Annotate(entries, notes)
+}
+
+// ParseSetValues parses KEY=VALUE assignments, as given to --set. Later
+// assignments of a key win. The value may be empty or contain "=".
+func ParseSetValues(assignments []string) (map[string]string, error) {
+	values := make(map[string]string, len(assignments))
+	for _, a := range assignments {
+		key, value, ok := strings.Cut(a, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set %q: want KEY=VALUE", a)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// unknownKeys returns the keys of values that entries does not define,
+// sorted.
+func unknownKeys(values map[string]string, entries []parser.Entry) []string {
+	defined := keySet(entries)
+	var unknown []string
+	for key := range values {
+		if !defined[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func TestLoadAnswers(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/ci/answers.env"] = "# CI values\nAPI_KEY=abc123\nDEBUG=false\n"
+	fs.files["/ci/answers.json"] = `{"API_KEY": "abc123", "PORT": 8080, "DEBUG": false}`
+	fs.files["/ci/nested.json"] = `{"API_KEY": {"value": "abc"}}`
+	fs.files["/ci/broken.json"] = `{"API_KEY": `
+
+	tests := []struct {
+		name     string
+		path     string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{"env file", "/ci/answers.env", map[string]string{"API_KEY": "abc123", "DEBUG": "false"}, false},
+		{"json file", "/ci/answers.json", map[string]string{"API_KEY": "abc123", "PORT": "8080", "DEBUG": "false"}, false},
+		{"nested json value", "/ci/nested.json", nil, true},
+		{"malformed json", "/ci/broken.json", nil, true},
+		{"missing file", "/ci/missing.env", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers, err := LoadAnswers(tt.path, fs)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(answers) != len(tt.expected) {
+				t.Fatalf("LoadAnswers() = %v, expected %v", answers, tt.expected)
+			}
+			for k, v := range tt.expected {
+				if answers[k] != v {
+					t.Errorf("answers[%s] = %q, expected %q", k, answers[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestFillValues(t *testing.T) {
+	entries := []parser.Entry{
+		parser.Comment{Text: "# keys"},
+		parser.KeyValue{Key: "API_KEY", Value: "***"},
+		parser.KeyValue{Key: "GREETING", Value: "***"},
+		parser.KeyValue{Key: "PORT", Value: "3000"},
+	}
+
+	filled, keys := fillValues(entries, mapLookup(map[string]string{"API_KEY": "abc", "GREETING": "hello world", "UNUSED": "x"}))
+
+	if strings.Join(keys, ",") != "API_KEY,GREETING" {
+		t.Errorf("filled keys = %v, expected [API_KEY GREETING]", keys)
+	}
+
+	var out bytes.Buffer
+	if err := parser.Write(&out, filled); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# keys\nAPI_KEY=abc\nGREETING=\"hello world\"\nPORT=3000\n"
+	if out.String() != expected {
+		t.Errorf("filled output = %q, expected %q", out.String(), expected)
+	}
+
+	if _, ok := entries[1].(parser.KeyValue); !ok || entries[1].(parser.KeyValue).Value != "***" {
+		t.Error("fillValues must not modify its input")
+	}
+}
+
+func TestGenerateAllEnvFilesWithAnswers(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/a/.env.example"] = "API_KEY=***\nPORT=3000\n"
+	sc := &mockDirScanner{exampleFiles: []string{"/a/.env.example"}}
+
+	var out bytes.Buffer
+	opts := YoloOptions{Answers: map[string]string{"API_KEY": "abc123"}}
+	if err := GenerateAllEnvFilesWithOptions(t.Context(), opts, fs, sc, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fs.files["/a/.env"] != "API_KEY=abc123\nPORT=3000\n" {
+		t.Errorf("generated .env = %q", fs.files["/a/.env"])
+	}
+	if !strings.Contains(out.String(), "1 answered") {
+		t.Errorf("summary should report answered keys:\n%s", out.String())
+	}
+}
+
+func TestGenerateAllEnvFilesFromEnv(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/a/.env.example"] = "API_KEY=***\nDB_URL=***\nPORT=3000\n"
+	sc := &mockDirScanner{exampleFiles: []string{"/a/.env.example"}}
+	env := mapLookup(map[string]string{"API_KEY": "from-env", "DB_URL": "postgres://ci"})
+
+	var out bytes.Buffer
+	opts := YoloOptions{Answers: map[string]string{"API_KEY": "answered"}, Env: env}
+	if err := GenerateAllEnvFilesWithOptions(t.Context(), opts, fs, sc, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fs.files["/a/.env"] != "API_KEY=answered\nDB_URL=postgres://ci\nPORT=3000\n" {
+		t.Errorf("answers should win over the environment, got %q", fs.files["/a/.env"])
+	}
+	if !strings.Contains(out.String(), "from env: DB_URL") {
+		t.Errorf("summary should list keys sourced from the environment:\n%s", out.String())
+	}
+}
+
+func TestGenerateEnvFileWithLookup(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/test/.env.example"] = "API_KEY=***\nPORT=3000\n"
+	env := mapLookup(map[string]string{"API_KEY": "secret"})
+
+	var out bytes.Buffer
+	if err := GenerateEnvFileWithLookup("/test/.env.example", false, false, false, env, fs, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fs.files["/test/.env"] != "API_KEY=secret\nPORT=3000\n" {
+		t.Errorf("generated .env = %q", fs.files["/test/.env"])
+	}
+	if !strings.Contains(out.String(), "Filled from environment: API_KEY") {
+		t.Errorf("output should report filled keys:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "secret") {
+		t.Error("report must not print values")
+	}
+}
+
+func TestParseSetValues(t *testing.T) {
+	values, err := ParseSetValues([]string{"PORT=8080", "DSN=a=b", "EMPTY=", "PORT=9090"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"PORT": "9090", "DSN": "a=b", "EMPTY": ""}
+	if len(values) != len(want) {
+		t.Fatalf("ParseSetValues() = %v, want %v", values, want)
+	}
+	for k, v := range want {
+		if values[k] != v {
+			t.Errorf("%s = %q, want %q", k, values[k], v)
+		}
+	}
+
+	for _, bad := range []string{"PORT", "=8080"} {
+		if _, err := ParseSetValues([]string{bad}); err == nil {
+			t.Errorf("ParseSetValues(%q) should fail", bad)
+		}
+	}
+}
+
+func TestGenerateEnvFileWithValues(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/test/.env.example"] = "API_KEY=***\nDB_URL=***\nPORT=3000\n"
+	values := map[string]string{"API_KEY": "set value", "TYPO_KEY": "x"}
+	env := mapLookup(map[string]string{"API_KEY": "from-env", "DB_URL": "postgres://ci"})
+
+	var out bytes.Buffer
+	if err := GenerateEnvFileWithValues("/test/.env.example", false, false, false, values, env, Settings{}, fs, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := fs.files["/test/.env"], "API_KEY=\"set value\"\nDB_URL=postgres://ci\nPORT=3000\n"; got != want {
+		t.Errorf("generated .env = %q, want %q", got, want)
+	}
+	for _, line := range []string{"Set: API_KEY", "Filled from environment: DB_URL", "Ignored keys not in /test/.env.example: TYPO_KEY"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output should contain %q:\n%s", line, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "set value") {
+		t.Error("report must not print values")
+	}
+}
+
+func TestGenerateEnvFileAnnotates(t *testing.T) {
+	now = func() time.Time { return time.Date(2026, 2, 8, 9, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+
+	fs := newMockFileSystem()
+	fs.files["/test/.env.example"] = "# set via --set 2026-01-01\nAPI_KEY=***\nDB_URL=***\nPORT=3000\n"
+	values := map[string]string{"API_KEY": "secret"}
+	env := mapLookup(map[string]string{"DB_URL": "postgres://ci"})
+
+	var out bytes.Buffer
+	if err := GenerateEnvFileWithValues("/test/.env.example", false, false, false, values, env, Settings{Annotate: true}, fs, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# set via --set 2026-02-08\nAPI_KEY=secret\n# sourced from environment 2026-02-08\nDB_URL=postgres://ci\nPORT=3000\n"
+	if got := fs.files["/test/.env"]; got != want {
+		t.Errorf("generated .env = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateEnvFileQuotePolicy(t *testing.T) {
+	settings := Settings{Write: parser.WriteOptions{QuotePolicy: parser.QuoteWhenNeeded}}
+	fs := newMockFileSystem()
+	fs.files["/test/.env.example"] = "APP_NAME=\nCOLOR=\n"
+	values := map[string]string{"APP_NAME": "My App", "COLOR": "red # not blue"}
+
+	var out bytes.Buffer
+	if err := GenerateEnvFileWithValues("/test/.env.example", false, false, false, values, mapLookup(nil), settings, fs, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "APP_NAME=\"My App\"\nCOLOR=\"red # not blue\"\n"
+	if got := fs.files["/test/.env"]; got != want {
+		t.Errorf("generated .env = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateEnvFileDeterministic(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/test/.env.example"] = "API_KEY=***\nDB_URL=***\n"
+	values := map[string]string{"API_KEY": "secret"}
+	env := mapLookup(map[string]string{"DB_URL": "postgres://ci"})
+
+	var out bytes.Buffer
+	if err := GenerateEnvFileWithValues("/test/.env.example", false, false, false, values, env, Settings{Annotate: true, Deterministic: true}, fs, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# set via --set\nAPI_KEY=secret\n# sourced from environment\nDB_URL=postgres://ci\n"
+	if got := fs.files["/test/.env"]; got != want {
+		t.Errorf("generated .env = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateEnvFileFromUTF16Example(t *testing.T) {
+	var example []byte
+	for _, u := range utf16.Encode([]rune("\ufeffAPI_KEY=***\nPORT=3000\n")) {
+		example = append(example, byte(u), byte(u>>8))
+	}
+	want := "API_KEY=abc123\nPORT=3000\n"
+
+	t.Run("generate env", func(t *testing.T) {
+		fs := newMockFileSystem()
+		fs.files["/test/.env.example"] = string(example)
+		values := map[string]string{"API_KEY": "abc123"}
+		if err := GenerateEnvFileWithValues("/test/.env.example", false, false, false, values, nil, Settings{}, fs, &bytes.Buffer{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := fs.files["/test/.env"]; got != want {
+			t.Errorf("generated .env = %q, want plain UTF-8 %q", got, want)
+		}
+	})
+
+	t.Run("yolo", func(t *testing.T) {
+		fs := newMockFileSystem()
+		fs.files["/a/.env.example"] = string(example)
+		sc := &mockDirScanner{exampleFiles: []string{"/a/.env.example"}}
+		opts := YoloOptions{Answers: map[string]string{"API_KEY": "abc123"}}
+		if err := GenerateAllEnvFilesWithOptions(t.Context(), opts, fs, sc, strings.NewReader(""), &bytes.Buffer{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := fs.files["/a/.env"]; got != want {
+			t.Errorf("generated .env = %q, want plain UTF-8 %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jellydn/dotenv-tui/internal/format"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// FormatOptions configures FormatFiles.
+type FormatOptions struct {
+	// Files are the env files to format. When empty, every env file and
+	// example under Dir is formatted.
+	Files []string
+	Dir   string
+	// Check lists the files that are not formatted instead of writing
+	// them, and fails if there are any.
+	Check    bool
+	Format   format.Options
+	Settings Settings
+}
+
+// FormatFiles normalizes the layout of env files as format.Format does,
+// rewriting only the files that change, so it can run as a pre-commit
+// hook.
+func FormatFiles(ctx context.Context, opts FormatOptions, fs FileSystem, sc DirScanner, out io.Writer) error {
+	files := opts.Files
+	if len(files) == 0 {
+		var err error
+		if files, err = scanAllFiles(ctx, opts.Dir, sc); err != nil {
+			return err
+		}
+	}
+
+	var reformatted []string
+	for _, path := range files {
+		entries, after, changed, err := formatFile(path, fs, opts)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		reformatted = append(reformatted, path)
+		if opts.Check {
+			_, _ = fmt.Fprintf(out, "%s is not formatted\n", path)
+			continue
+		}
+		if err := opts.Settings.writeEntries(path, fs, after); err != nil {
+			return err
+		}
+		opts.Settings.recordWrite(history.OpFormat, path, entries, after, "", out)
+		_, _ = fmt.Fprintf(out, "Formatted %s\n", path)
+	}
+
+	if opts.Check && len(reformatted) > 0 {
+		return fmt.Errorf("%d of %d file(s) need formatting; run dotenv-tui fmt", len(reformatted), len(files))
+	}
+	if !opts.Check {
+		_, _ = fmt.Fprintf(out, "%d of %d file(s) formatted\n", len(reformatted), len(files))
+	}
+	return nil
+}
+
+// formatFile reads path and returns its entries, the entries once
+// formatted and whether formatting changes the file.
+func formatFile(path string, fs FileSystem, opts FormatOptions) ([]parser.Entry, []parser.Entry, bool, error) {
+	file, err := fs.Open(path)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	data, err := io.ReadAll(file)
+	_ = file.Close()
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	entries, err := parser.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	after := format.Format(entries, opts.Format)
+	var formatted bytes.Buffer
+	if err := parser.WriteWithOptions(&formatted, after, opts.Settings.Write); err != nil {
+		return nil, nil, false, fmt.Errorf("failed to format %s: %w", path, err)
+	}
+	return entries, after, !bytes.Equal(data, formatted.Bytes()), nil
+}
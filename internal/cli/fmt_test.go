@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/format"
+)
+
+func TestFormatFiles(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "PORT = 3000\n\n\nHOST='localhost'\n"
+	fs.files[".env.example"] = "HOST=localhost\nPORT=3000\n"
+	sc := &mockDirScanner{scanFiles: []string{".env"}, exampleFiles: []string{".env.example"}}
+	opts := FormatOptions{Format: format.Options{Quotes: format.QuoteMinimal}}
+
+	var out strings.Builder
+	check := opts
+	check.Check = true
+	err := FormatFiles(t.Context(), check, fs, sc, &out)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 file(s) need formatting") {
+		t.Errorf("FormatFiles(check) error = %v, want .env reported", err)
+	}
+	if !strings.Contains(out.String(), ".env is not formatted") || fs.files[".env"] != "PORT = 3000\n\n\nHOST='localhost'\n" {
+		t.Errorf("a check should report without writing, output %q", out.String())
+	}
+
+	out.Reset()
+	if err := FormatFiles(t.Context(), opts, fs, sc, &out); err != nil {
+		t.Fatalf("FormatFiles() error = %v", err)
+	}
+	if got := fs.files[".env"]; got != "PORT=3000\n\nHOST=localhost\n" {
+		t.Errorf(".env = %q", got)
+	}
+	if !strings.Contains(out.String(), "Formatted .env\n1 of 2 file(s) formatted") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	// A second run finds nothing to do.
+	if err := FormatFiles(t.Context(), check, fs, sc, &out); err != nil {
+		t.Errorf("FormatFiles(check) after formatting error = %v", err)
+	}
+}
+
+func TestFormatFilesParseError(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "KEY=\"unclosed\n"
+
+	opts := FormatOptions{Files: []string{".env"}}
+	if err := FormatFiles(t.Context(), opts, fs, &mockDirScanner{}, &strings.Builder{}); err == nil || !strings.Contains(err.Error(), "failed to parse .env") {
+		t.Errorf("FormatFiles() error = %v, want a parse error", err)
+	}
+	if fs.files[".env"] != "KEY=\"unclosed\n" {
+		t.Errorf("a file that does not parse should not be written")
+	}
+}
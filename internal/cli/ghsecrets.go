@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/direnv"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// GHSecretsOptions configures ExportGHSecrets.
+type GHSecretsOptions struct {
+	// Repo is the owner/name repository the secrets are set in; empty lets
+	// gh use the repository of the current directory.
+	Repo string
+	// Execute runs gh secret set instead of printing the commands.
+	Execute bool
+	// Rules are the project's extra secret detection rules, which decide
+	// the keys exported.
+	Rules detector.Rules
+}
+
+// SecretSetter sets one GitHub Actions secret, for testing.
+type SecretSetter func(key, value, repo string) error
+
+// GHSecretSet sets a GitHub Actions secret with the gh CLI, passing the
+// value on stdin so it never appears in the process list.
+func GHSecretSet(key, value, repo string) error {
+	args := []string{"secret", "set", key}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+	cmd := exec.Command("gh", args...)
+	cmd.Stdin = strings.NewReader(value)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// ExportGHSecrets uploads the secret keys of the env file at inputPath as
+// GitHub Actions secrets. Keys that are not secrets, and empty or
+// placeholder values, are skipped, as are keys that are not valid variable
+// names, which the printed script would run as commands. By default it prints a shell script of
+// gh secret set commands to review and run; with opts.Execute it calls set
+// for each secret instead.
+func ExportGHSecrets(inputPath string, opts GHSecretsOptions, fs FileSystem, set SecretSetter, out io.Writer) error {
+	if opts.Repo != "" {
+		if owner, name, ok := strings.Cut(opts.Repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("invalid repository %q: want owner/name", opts.Repo)
+		}
+	}
+	entries, err := parseAndClose(inputPath, fs)
+	if err != nil {
+		return err
+	}
+
+	var secrets []parser.KeyValue
+	var skipped, invalid []string
+	for _, entry := range entries {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok {
+			continue
+		}
+		if !parser.IsValidKey(kv.Key) {
+			invalid = append(invalid, kv.Key)
+			continue
+		}
+		if kv.Value == "" || detector.IsPlaceholder(kv.Value) || !opts.Rules.IsSecret(kv.Key, kv.Value) {
+			skipped = append(skipped, kv.Key)
+			continue
+		}
+		secrets = append(secrets, kv)
+	}
+	invalidNote := ""
+	if len(invalid) > 0 {
+		invalidNote = fmt.Sprintf("Skipped %d key(s) that are not valid variable names: %s\n", len(invalid), strings.Join(invalid, ", "))
+	}
+	if len(secrets) == 0 {
+		_, _ = io.WriteString(out, invalidNote)
+		_, _ = fmt.Fprintf(out, "No secrets to export in %s\n", inputPath)
+		return nil
+	}
+
+	if !opts.Execute {
+		_, _ = fmt.Fprintf(out, "# GitHub Actions secrets from %s; review, then run with sh\n", inputPath)
+		repo := ""
+		if opts.Repo != "" {
+			repo = " --repo " + direnv.Quote(opts.Repo)
+		}
+		for _, kv := range secrets {
+			_, _ = fmt.Fprintf(out, "printf %%s %s | gh secret set %s%s\n", direnv.Quote(kv.Value), kv.Key, repo)
+		}
+		if len(skipped) > 0 {
+			_, _ = fmt.Fprintf(out, "# Skipped %d key(s) that are not secrets: %s\n", len(skipped), strings.Join(skipped, ", "))
+		}
+		if invalidNote != "" {
+			_, _ = io.WriteString(out, "# "+invalidNote)
+		}
+		return nil
+	}
+
+	_, _ = io.WriteString(out, invalidNote)
+	for _, kv := range secrets {
+		if err := set(kv.Key, kv.Value, opts.Repo); err != nil {
+			return fmt.Errorf("failed to set secret %s: %w", kv.Key, err)
+		}
+		_, _ = fmt.Fprintf(out, "Set secret %s\n", kv.Key)
+	}
+	_, _ = fmt.Fprintf(out, "Uploaded %d secret(s) from %s", len(secrets), inputPath)
+	if len(skipped) > 0 {
+		_, _ = fmt.Fprintf(out, ", skipped %d key(s) that are not secrets: %s", len(skipped), strings.Join(skipped, ", "))
+	}
+	_, _ = fmt.Fprintln(out)
+	return nil
+}
@@ -3,14 +3,16 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/jellydn/dotenv-tui/internal/backup"
+	"github.com/jellydn/dotenv-tui/internal/detector"
 	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/history"
 	"github.com/jellydn/dotenv-tui/internal/parser"
 	"github.com/jellydn/dotenv-tui/internal/scanner"
 )
@@ -28,8 +30,19 @@ type FileSystem interface {
 
 // DirScanner defines directory scanning operations for testing.
 type DirScanner interface {
-	Scan(root string) ([]string, error)
-	ScanExamples(root string) ([]string, error)
+	Scan(ctx context.Context, root string) ([]string, error)
+	ScanExamples(ctx context.Context, root string) ([]string, error)
+}
+
+// SkipReporter is a DirScanner that also reports the directories a scan
+// could not read, for testing.
+type SkipReporter interface {
+	ScanReport(ctx context.Context, root string) (scanner.Result, error)
+}
+
+// SourceScanner finds source code files, for testing.
+type SourceScanner interface {
+	ScanSource(ctx context.Context, root string) ([]string, error)
 }
 
 // RealFileSystem is the default filesystem implementation.
@@ -56,20 +69,47 @@ func (RealFileSystem) CreateWithMode(name string, mode os.FileMode) (io.WriteClo
 }
 
 // RealDirScanner is the default scanner implementation using the scanner package.
-type RealDirScanner struct{}
+type RealDirScanner struct {
+	Options scanner.Options
+}
 
 // Scan implements DirScanner.Scan.
-func (RealDirScanner) Scan(root string) ([]string, error) {
-	return scanner.Scan(root)
+func (s RealDirScanner) Scan(ctx context.Context, root string) ([]string, error) {
+	result, err := s.ScanReport(ctx, root)
+	return result.Files, err
+}
+
+// ScanReport implements SkipReporter.ScanReport.
+func (s RealDirScanner) ScanReport(ctx context.Context, root string) (scanner.Result, error) {
+	return scanner.ScanWithOptions(ctx, root, s.Options)
 }
 
 // ScanExamples implements DirScanner.ScanExamples.
-func (RealDirScanner) ScanExamples(root string) ([]string, error) {
-	return scanner.ScanExamples(root)
+func (s RealDirScanner) ScanExamples(ctx context.Context, root string) ([]string, error) {
+	result, err := scanner.ScanExamplesWithOptions(ctx, root, s.Options)
+	return result.Files, err
+}
+
+// ScanDirenv implements DirenvScanner.ScanDirenv.
+func (s RealDirScanner) ScanDirenv(ctx context.Context, root string) ([]string, error) {
+	result, err := scanner.ScanDirenvWithOptions(ctx, root, s.Options)
+	return result.Files, err
+}
+
+// ScanCompose implements ComposeScanner.ScanCompose. Only the compose files
+// at root are read, so there is no walk for ctx to cancel.
+func (s RealDirScanner) ScanCompose(_ context.Context, root string) ([]scanner.ComposeService, error) {
+	return scanner.FindComposeServices(root)
+}
+
+// ScanSource implements SourceScanner.ScanSource.
+func (s RealDirScanner) ScanSource(ctx context.Context, root string) ([]string, error) {
+	result, err := scanner.ScanSourceWithOptions(ctx, root, s.Options)
+	return result.Files, err
 }
 
 // GenerateFile generates a file from an input file, processing entries with the provided function.
-func GenerateFile(inputPath string, force bool, createBackup bool, dryRun bool, outputFilename string, processEntries EntryProcessor, parseErrMsg string, fs FileSystem, out io.Writer) error {
+func GenerateFile(inputPath string, force bool, createBackup bool, dryRun bool, outputFilename string, processEntries EntryProcessor, parseErrMsg string, settings Settings, fs FileSystem, out io.Writer) error {
 	file, err := fs.Open(inputPath)
 	if err != nil {
 		return fmt.Errorf("failed to open input file: %w", err)
@@ -91,11 +131,13 @@ func GenerateFile(inputPath string, force bool, createBackup bool, dryRun bool,
 
 	// Dry-run mode: preview the output without writing
 	if dryRun {
-		return previewOutput(outputPath, processedEntries, fs, out)
+		return settings.previewOutput(outputPath, processedEntries, fs, out)
 	}
 
+	before := existingEntries(outputPath, fs)
+	var backupPath string
 	if createBackup {
-		backupPath, err := backup.CreateBackupWithFS(outputPath, fsAdapter{fs})
+		backupPath, err = settings.backupFile(outputPath, fs)
 		if err != nil {
 			return fmt.Errorf("failed to create backup: %w", err)
 		}
@@ -109,7 +151,7 @@ func GenerateFile(inputPath string, force bool, createBackup bool, dryRun bool,
 		return fmt.Errorf("failed to create output file: %w", err)
 	}
 
-	if err := parser.Write(outFile, processedEntries); err != nil {
+	if err := parser.WriteWithOptions(outFile, processedEntries, settings.Write); err != nil {
 		_ = outFile.Close()
 		return fmt.Errorf("failed to write output file: %w", err)
 	}
@@ -119,90 +161,177 @@ func GenerateFile(inputPath string, force bool, createBackup bool, dryRun bool,
 	}
 
 	_, _ = fmt.Fprintf(out, "Generated %s\n", outputPath)
+	op := history.OpGenerateEnv
+	switch outputFilename {
+	case ".env.example":
+		op = history.OpGenerateExample
+	case ".env.test":
+		op = history.OpGenerateTest
+	}
+	settings.recordWrite(op, outputPath, before, processedEntries, backupPath, out)
 	return nil
 }
 
 // GenerateExampleFile generates a .env.example file from a .env file.
 func GenerateExampleFile(inputPath string, force bool, createBackup bool, dryRun bool, fs FileSystem, out io.Writer) error {
-	return GenerateFile(inputPath, force, createBackup, dryRun, ".env.example", generator.GenerateExample, ".env file", fs, out)
+	return GenerateExampleFileWithOptions(inputPath, force, createBackup, dryRun, generator.Options{}, Settings{}, fs, out)
+}
+
+// GenerateExampleFileWithOptions is GenerateExampleFile with a choice of
+// placeholder style and key order.
+func GenerateExampleFileWithOptions(inputPath string, force bool, createBackup bool, dryRun bool, opts generator.Options, settings Settings, fs FileSystem, out io.Writer) error {
+	examplePath := filepath.Join(filepath.Dir(inputPath), ".env.example")
+	return GenerateFile(inputPath, force, createBackup, dryRun, ".env.example", func(entries []parser.Entry) []parser.Entry {
+		return settings.generateExample(entries, examplePath, opts, fs)
+	}, ".env file", settings, fs, out)
+}
+
+// generateExample generates the entries of the example at examplePath, with
+// a changelog of the keys added and removed since its current version when
+// opts.Changelog is set.
+func (s Settings) generateExample(entries []parser.Entry, examplePath string, opts generator.Options, fs FileSystem) []parser.Entry {
+	example := generator.GenerateExampleWithOptions(entries, opts)
+	if opts.Changelog {
+		example = generator.WithChangelog(existingEntries(examplePath, fs), example, s.today())
+	}
+	return example
+}
+
+// GenerateTestFile generates a .env.test file from a .env or .env.example
+// file, with fake values in place of secrets; see generator.GenerateTest.
+func GenerateTestFile(inputPath string, force bool, createBackup bool, dryRun bool, masking detector.Masking, settings Settings, fs FileSystem, out io.Writer) error {
+	return GenerateFile(inputPath, force, createBackup, dryRun, ".env.test", func(entries []parser.Entry) []parser.Entry {
+		return generator.GenerateTest(entries, masking)
+	}, "env file", settings, fs, out)
 }
 
 // GenerateEnvFile generates a .env file from a .env.example file.
 func GenerateEnvFile(inputPath string, force bool, createBackup bool, dryRun bool, fs FileSystem, out io.Writer) error {
-	return GenerateFile(inputPath, force, createBackup, dryRun, ".env", func(entries []parser.Entry) []parser.Entry {
-		return entries
-	}, ".env.example file", fs, out)
+	return GenerateEnvFileWithLookup(inputPath, force, createBackup, dryRun, nil, fs, out)
 }
 
-// ScanAndList scans a directory for .env files and lists them.
-func ScanAndList(dir string, sc DirScanner, out io.Writer) error {
-	if dir == "" {
-		dir = "."
-	}
+// GenerateEnvFileWithLookup is GenerateEnvFile but fills every key that
+// lookup (typically os.LookupEnv) knows, then reports which keys were filled.
+func GenerateEnvFileWithLookup(inputPath string, force bool, createBackup bool, dryRun bool, lookup LookupFunc, fs FileSystem, out io.Writer) error {
+	return GenerateEnvFileWithValues(inputPath, force, createBackup, dryRun, nil, lookup, Settings{}, fs, out)
+}
 
-	files, err := sc.Scan(dir)
+// GenerateEnvFileWithValues is GenerateEnvFileWithLookup but first sets the
+// keys in values, as given by --set and --set-file. Other keys keep the
+// example's value unless lookup knows them. Keys in values that the example
+// does not define are reported and ignored.
+func GenerateEnvFileWithValues(inputPath string, force bool, createBackup bool, dryRun bool, values map[string]string, lookup LookupFunc, settings Settings, fs FileSystem, out io.Writer) error {
+	var set, filled, unknown []string
+	err := GenerateFile(inputPath, force, createBackup, dryRun, ".env", func(entries []parser.Entry) []parser.Entry {
+		unknown = unknownKeys(values, entries)
+		entries, set, filled = settings.fillAnswers(entries, values, "--set", lookup)
+		return entries
+	}, ".env.example file", settings, fs, out)
 	if err != nil {
-		return fmt.Errorf("failed to scan directory: %w", err)
+		return err
 	}
 
-	if len(files) == 0 {
-		_, _ = fmt.Fprintln(out, "No .env files found")
-		return nil
+	if len(set) > 0 {
+		_, _ = fmt.Fprintf(out, "Set: %s\n", strings.Join(set, ", "))
 	}
-
-	_, _ = fmt.Fprintf(out, "Found %d .env file(s):\n", len(files))
-	for _, file := range files {
-		_, _ = fmt.Fprintf(out, "  %s\n", file)
+	if len(filled) > 0 {
+		_, _ = fmt.Fprintf(out, "Filled from environment: %s\n", strings.Join(filled, ", "))
+	}
+	if len(unknown) > 0 {
+		_, _ = fmt.Fprintf(out, "Ignored keys not in %s: %s\n", inputPath, strings.Join(unknown, ", "))
 	}
-
 	return nil
 }
 
-// GenerateAllEnvFiles generates .env files from all .env.example files.
-func GenerateAllEnvFiles(force bool, createBackup bool, dryRun bool, fs FileSystem, sc DirScanner, in io.Reader, out io.Writer) error {
-	exampleFiles, err := sc.ScanExamples(".")
+// ScanAndList scans a directory for .env files and lists them. When sc is
+// also a DirenvScanner, .envrc files are listed too, as read-only sources,
+// and when it is a ComposeScanner, each file is marked with the Docker
+// Compose services reading it and missing compose env files are reported.
+// When it is a SkipReporter, the directories the scan could not read are
+// listed after a warning, so they are not mistaken for having no env files.
+func ScanAndList(ctx context.Context, dir string, sc DirScanner, out io.Writer) error {
+	if dir == "" {
+		dir = "."
+	}
+
+	var files []string
+	var skipped []scanner.Skipped
+	var err error
+	if sr, ok := sc.(SkipReporter); ok {
+		var result scanner.Result
+		result, err = sr.ScanReport(ctx, dir)
+		files, skipped = result.Files, result.Skipped
+	} else {
+		files, err = sc.Scan(ctx, dir)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to scan for .env.example files: %w", err)
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+	var envrcs []string
+	if ds, ok := sc.(DirenvScanner); ok {
+		if envrcs, err = ds.ScanDirenv(ctx, dir); err != nil {
+			return fmt.Errorf("failed to scan directory: %w", err)
+		}
 	}
 
-	if len(exampleFiles) == 0 {
-		return fmt.Errorf("no .env.example files found")
+	var services []scanner.ComposeService
+	if cs, ok := sc.(ComposeScanner); ok {
+		if services, err = cs.ScanCompose(ctx, dir); err != nil {
+			return fmt.Errorf("failed to read compose files: %w", err)
+		}
 	}
 
-	_, _ = fmt.Fprintf(out, "Found %d .env.example file(s):\n", len(exampleFiles))
-	for _, file := range exampleFiles {
-		_, _ = fmt.Fprintf(out, "  %s\n", file)
+	if len(files) == 0 && len(envrcs) == 0 {
+		_, _ = fmt.Fprintln(out, "No .env files found")
+		listCompose(services, out)
+		listSkipped(skipped, out)
+		return nil
 	}
 
-	if dryRun {
-		_, _ = fmt.Fprintln(out, "\n[DRY RUN MODE - No files will be written]")
-		for _, exampleFile := range exampleFiles {
-			outputPath := strings.TrimSuffix(exampleFile, ".example")
-			entries, err := parseAndClose(exampleFile, fs)
-			if err != nil {
-				return err
-			}
-			if err := previewOutput(outputPath, entries, fs, out); err != nil {
-				return err
+	if len(files) > 0 {
+		consumers := composeConsumers(services)
+		_, _ = fmt.Fprintf(out, "Found %d .env file(s):\n", len(files))
+		for _, file := range files {
+			if names := consumers[file]; len(names) > 0 {
+				_, _ = fmt.Fprintf(out, "  %s  (compose: %s)\n", file, strings.Join(names, ", "))
+				continue
 			}
+			_, _ = fmt.Fprintf(out, "  %s\n", file)
 		}
-		return nil
 	}
-
-	var generated, skipped int
-	for _, exampleFile := range exampleFiles {
-		if err := ProcessExampleFile(exampleFile, force, createBackup, &generated, &skipped, fs, in, out); err != nil {
-			return err
+	if len(envrcs) > 0 {
+		_, _ = fmt.Fprintf(out, "Found %d direnv file(s), read-only:\n", len(envrcs))
+		for _, file := range envrcs {
+			_, _ = fmt.Fprintf(out, "  %s\n", file)
 		}
 	}
+	listCompose(services, out)
+	listSkipped(skipped, out)
 
-	_, _ = fmt.Fprintf(out, "Done: %d generated, %d skipped\n", generated, skipped)
 	return nil
 }
 
+// listSkipped warns about the directories a scan could not read.
+func listSkipped(skipped []scanner.Skipped, out io.Writer) {
+	if len(skipped) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Warning: %s:\n", scanner.SkippedSummary(skipped))
+	for _, s := range skipped {
+		_, _ = fmt.Fprintf(out, "  %s\n", s.Path)
+	}
+}
+
+// GenerateAllEnvFiles generates .env files from all .env.example files,
+// printing a progress line per file and a summary table.
+func GenerateAllEnvFiles(ctx context.Context, force bool, createBackup bool, dryRun bool, fs FileSystem, sc DirScanner, in io.Reader, out io.Writer) error {
+	opts := YoloOptions{Force: force, CreateBackup: createBackup, DryRun: dryRun}
+	return GenerateAllEnvFilesWithOptions(ctx, opts, fs, sc, in, out)
+}
+
 // ProcessExampleFile processes a single .env.example file and generates a .env file.
 func ProcessExampleFile(exampleFile string, force bool, createBackup bool, generated, skipped *int, fs FileSystem, in io.Reader, out io.Writer) error {
-	outputPath := strings.TrimSuffix(exampleFile, ".example")
+	outputPath := scanner.ExampleTarget(exampleFile)
 
 	entries, err := parseAndClose(exampleFile, fs)
 	if err != nil {
@@ -221,18 +350,11 @@ func ProcessExampleFile(exampleFile string, force bool, createBackup bool, gener
 		}
 	}
 
-	// Create backup if file exists and backups are enabled
-	if createBackup {
-		backupPath, err := backup.CreateBackupWithFS(outputPath, fsAdapter{fs})
-		if err != nil {
-			return fmt.Errorf("failed to create backup: %w", err)
-		}
-		if backupPath != "" {
-			_, _ = fmt.Fprintf(out, "Backup created: %s\n", backupPath)
-		}
+	backupPath, err := Settings{}.writeExample(outputPath, withoutEncoding(entries), createBackup, fs)
+	if backupPath != "" {
+		_, _ = fmt.Fprintf(out, "Backup created: %s\n", backupPath)
 	}
-
-	if err := writeEntries(outputPath, fs, entries); err != nil {
+	if err != nil {
 		return err
 	}
 
@@ -272,13 +394,26 @@ func parseAndClose(path string, fs FileSystem) ([]parser.Entry, error) {
 	return entries, nil
 }
 
-func writeEntries(path string, fs FileSystem, entries []parser.Entry) error {
+// existingEntries returns the entries of path, or nil if it does not exist
+// or cannot be parsed.
+func existingEntries(path string, fs FileSystem) []parser.Entry {
+	if !fileExists(fs, path) {
+		return nil
+	}
+	entries, err := parseAndClose(path, fs)
+	if err != nil {
+		return nil
+	}
+	return entries
+}
+
+func (s Settings) writeEntries(path string, fs FileSystem, entries []parser.Entry) error {
 	outFile, err := fs.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create %s: %w", path, err)
 	}
 
-	if err := parser.Write(outFile, entries); err != nil {
+	if err := parser.WriteWithOptions(outFile, entries, s.Write); err != nil {
 		_ = outFile.Close()
 		return fmt.Errorf("failed to write %s: %w", path, err)
 	}
@@ -290,7 +425,7 @@ func writeEntries(path string, fs FileSystem, entries []parser.Entry) error {
 	return nil
 }
 
-func previewOutput(outputPath string, entries []parser.Entry, fs FileSystem, out io.Writer) error {
+func (s Settings) previewOutput(outputPath string, entries []parser.Entry, fs FileSystem, out io.Writer) error {
 	_, existsErr := fs.Stat(outputPath)
 	fileExists := existsErr == nil
 
@@ -307,7 +442,7 @@ func previewOutput(outputPath string, entries []parser.Entry, fs FileSystem, out
 	_, _ = fmt.Fprintln(out, "---")
 
 	var buf strings.Builder
-	if err := parser.Write(&nopWriteCloser{&buf}, entries); err != nil {
+	if err := parser.WriteWithOptions(&nopWriteCloser{&buf}, entries, s.Write); err != nil {
 		return fmt.Errorf("failed to generate preview: %w", err)
 	}
 
@@ -2,8 +2,12 @@ package cli
 
 import (
 	"bytes"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/backup"
+	"github.com/jellydn/dotenv-tui/internal/config"
 )
 
 func TestGenerateExampleFileWithBackup(t *testing.T) {
@@ -223,3 +227,31 @@ func TestProcessExampleFileWithBackup(t *testing.T) {
 		})
 	}
 }
+
+func TestBackupUsesDirSettings(t *testing.T) {
+	shared := t.TempDir()
+	settings := Settings{Dir: func(path string) (config.DirSettings, error) {
+		if strings.HasPrefix(path, "/api/") {
+			return config.DirSettings{Backup: true, BackupDir: shared}, nil
+		}
+		return config.DirSettings{Backup: true}, nil
+	}}
+
+	fs := newMockFileSystem()
+	fs.files["/api/.env"] = "PORT=4000\n"
+	fs.files["/web/.env"] = "PORT=3000\n"
+	for _, path := range []string{"/api/.env", "/web/.env"} {
+		if _, err := settings.backupFile(path, fs); err != nil {
+			t.Fatalf("backupFile(%q) error = %v", path, err)
+		}
+	}
+
+	var apiBackup, webBackup bool
+	for path := range fs.files {
+		apiBackup = apiBackup || strings.HasPrefix(path, filepath.Join(shared, backup.FlattenPath("/api/.env")+".bak."))
+		webBackup = webBackup || strings.HasPrefix(path, "/web/.env.bak.")
+	}
+	if !apiBackup || !webBackup {
+		t.Errorf("files = %v, want the /api backup in %s and the /web one next to its file", fs.files, shared)
+	}
+}
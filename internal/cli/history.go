@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// HistoryOptions configures ShowHistory.
+type HistoryOptions struct {
+	// Path is the history log to read.
+	Path string
+	// File, if set, limits the output to writes of this file.
+	File string
+	// Limit, if positive, shows only the most recent records.
+	Limit int
+	// Format is FormatText or FormatJSON. Empty means FormatText.
+	Format string
+}
+
+// ShowHistory prints the write history, oldest first, as a table or as a
+// JSON array.
+func ShowHistory(opts HistoryOptions, out io.Writer) error {
+	format := opts.Format
+	if format == "" {
+		format = FormatText
+	}
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unsupported format %q (use %s or %s)", format, FormatText, FormatJSON)
+	}
+
+	records, err := history.Load(opts.Path)
+	if err != nil {
+		return err
+	}
+	if opts.File != "" {
+		file, err := filepath.Abs(opts.File)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", opts.File, err)
+		}
+		var matching []history.Record
+		for _, r := range records {
+			if r.Path == file {
+				matching = append(matching, r)
+			}
+		}
+		records = matching
+	}
+	if opts.Limit > 0 && len(records) > opts.Limit {
+		records = records[len(records)-opts.Limit:]
+	}
+
+	if format == FormatJSON {
+		if records == nil {
+			records = []history.Record{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(records) == 0 {
+		_, _ = fmt.Fprintln(out, "No writes recorded")
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "TIME\tUSER\tSOURCE\tOPERATION\tFILE\tCHANGES\tBACKUP")
+	for _, r := range records {
+		c := r.Changes
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t+%d ~%d -%d\t%s\n",
+			r.Time.Local().Format("2006-01-02 15:04:05"), r.User, r.Source, r.Operation, r.Path,
+			len(c.Added), len(c.Changed), len(c.Removed), r.Backup)
+	}
+	return tw.Flush()
+}
+
+// recordWrite logs a write of path to the history, reporting a failure to
+// log as a warning on out, if out is not nil.
+func (s Settings) recordWrite(op, path string, before, after []parser.Entry, backupPath string, out io.Writer) {
+	err := history.Append(s.History, history.Record{
+		Source:    history.SourceCLI,
+		Operation: op,
+		Path:      path,
+		Changes:   history.Summarize(before, after),
+		Backup:    backupPath,
+	})
+	if err != nil && out != nil {
+		_, _ = fmt.Fprintf(out, "Warning: %v\n", err)
+	}
+}
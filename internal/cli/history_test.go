@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/history"
+)
+
+func TestGenerateEnvFileRecordsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), history.FileName)
+	fs := newMockFileSystem()
+	fs.files["/test/.env.example"] = "KEY=value\nNEW=x\n"
+	fs.files["/test/.env"] = "KEY=old\nGONE=1\n"
+
+	var out bytes.Buffer
+	if err := GenerateEnvFileWithValues("/test/.env.example", true, false, false, nil, nil, Settings{History: path}, fs, &out); err != nil {
+		t.Fatalf("GenerateEnvFile() unexpected error: %v", err)
+	}
+
+	records, err := history.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %+v", records)
+	}
+	r := records[0]
+	if r.Source != history.SourceCLI || r.Operation != history.OpGenerateEnv || filepath.Base(r.Path) != ".env" {
+		t.Errorf("record = %+v, want a cli generate-env of .env", r)
+	}
+	want := history.Summary{Added: []string{"NEW"}, Removed: []string{"GONE"}, Changed: []string{"KEY"}}
+	if strings.Join(r.Changes.Added, ",") != "NEW" || strings.Join(r.Changes.Removed, ",") != "GONE" || strings.Join(r.Changes.Changed, ",") != "KEY" {
+		t.Errorf("changes = %+v, want %+v", r.Changes, want)
+	}
+}
+
+func TestShowHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), history.FileName)
+	for _, file := range []string{"a/.env", "b/.env", "a/.env"} {
+		if err := history.Append(path, history.Record{Source: history.SourceTUI, Operation: history.OpGenerateEnv, Path: file}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := ShowHistory(HistoryOptions{Path: path}, &out); err != nil {
+		t.Fatalf("ShowHistory() unexpected error: %v", err)
+	}
+	if got := strings.Count(out.String(), "generate-env"); got != 3 {
+		t.Errorf("text output should list 3 writes, got %d:\n%s", got, out.String())
+	}
+
+	out.Reset()
+	if err := ShowHistory(HistoryOptions{Path: path, File: "a/.env", Limit: 1, Format: FormatJSON}, &out); err != nil {
+		t.Fatalf("ShowHistory() unexpected error: %v", err)
+	}
+	var records []history.Record
+	if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	if len(records) != 1 || !strings.HasSuffix(filepath.ToSlash(records[0].Path), "a/.env") {
+		t.Errorf("filtered output = %+v, want the last write of a/.env", records)
+	}
+
+	out.Reset()
+	if err := ShowHistory(HistoryOptions{Path: filepath.Join(t.TempDir(), "none.jsonl")}, &out); err != nil {
+		t.Fatalf("ShowHistory() unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "No writes recorded") {
+		t.Errorf("empty history output = %q", out.String())
+	}
+
+	if err := ShowHistory(HistoryOptions{Path: path, Format: "xml"}, &out); err == nil {
+		t.Error("ShowHistory() expected error for unsupported format")
+	}
+}
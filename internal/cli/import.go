@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/convert"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// ImportOptions configures ImportEnv and ImportFile.
+type ImportOptions struct {
+	// Output is the env file written; a masked .env.example is written
+	// next to it.
+	Output string
+	// Prefix selects the variables ImportEnv imports, e.g. APP_.
+	Prefix string
+	// Convert configures how ImportFile turns nested values into keys.
+	Convert      convert.Options
+	Force        bool
+	CreateBackup bool
+	DryRun       bool
+	// Example configures the masking of the .env.example.
+	Example  generator.Options
+	Settings Settings
+}
+
+// ImportEnv captures the variables of environ, as returned by os.Environ,
+// whose names start with opts.Prefix into a new env file, sorted by name,
+// along with a masked .env.example for it.
+func ImportEnv(opts ImportOptions, environ []string, fs FileSystem, out io.Writer) error {
+	if opts.Prefix == "" {
+		return fmt.Errorf("--prefix is required, e.g. --prefix APP_")
+	}
+	values := make(map[string]string)
+	for _, kv := range environ {
+		key, value, ok := strings.Cut(kv, "=")
+		if ok && strings.HasPrefix(key, opts.Prefix) {
+			values[key] = value
+		}
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("no environment variables start with %s", opts.Prefix)
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]parser.Entry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, importedValue(key, values[key]))
+	}
+	if err := writeImported(opts, entries, fs, out); err != nil {
+		return err
+	}
+	if !opts.DryRun {
+		_, _ = fmt.Fprintf(out, "Imported %d variable(s) starting with %s\n", len(keys), opts.Prefix)
+	}
+	return nil
+}
+
+// ImportFile converts a JSON, YAML or TOML configuration file into a new
+// env file, sorted by key, along with a masked .env.example for it.
+func ImportFile(inputPath string, opts ImportOptions, fs FileSystem, out io.Writer) error {
+	data, err := readFile(inputPath, fs)
+	if err != nil {
+		return err
+	}
+	doc, err := convert.Decode(inputPath, data)
+	if err != nil {
+		return err
+	}
+	values, err := convert.Values(doc, opts.Convert)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s: %w", inputPath, err)
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("no values in %s", inputPath)
+	}
+
+	keys := convert.Keys(values)
+	entries := make([]parser.Entry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, importedValue(key, values[key]))
+	}
+	if err := writeImported(opts, entries, fs, out); err != nil {
+		return err
+	}
+	if !opts.DryRun {
+		_, _ = fmt.Fprintf(out, "Imported %d key(s) from %s\n", len(keys), inputPath)
+	}
+	return nil
+}
+
+// importedValue returns the entry for an imported key, quoted when the
+// value would otherwise be misread.
+func importedValue(key, value string) parser.KeyValue {
+	return parser.QuoteValue(parser.KeyValue{Key: key, Value: value})
+}
+
+// writeImported writes imported entries to opts.Output and their masked
+// example next to it, refusing to replace either without opts.Force.
+func writeImported(opts ImportOptions, entries []parser.Entry, fs FileSystem, out io.Writer) error {
+	examplePath := filepath.Join(filepath.Dir(opts.Output), ".env.example")
+	files := []struct {
+		path    string
+		op      string
+		entries []parser.Entry
+	}{
+		{opts.Output, history.OpImport, entries},
+		{examplePath, history.OpGenerateExample, opts.Settings.generateExample(entries, examplePath, opts.Example, fs)},
+	}
+
+	if !opts.Force && !opts.DryRun {
+		for _, f := range files {
+			if fileExists(fs, f.path) {
+				return fmt.Errorf("%s already exists. Use --force to overwrite", f.path)
+			}
+		}
+	}
+	for _, f := range files {
+		if opts.DryRun {
+			if err := opts.Settings.previewOutput(f.path, f.entries, fs, out); err != nil {
+				return err
+			}
+			continue
+		}
+		before := existingEntries(f.path, fs)
+		var backupPath string
+		if opts.CreateBackup {
+			var err error
+			if backupPath, err = opts.Settings.backupFile(f.path, fs); err != nil {
+				return fmt.Errorf("failed to create backup: %w", err)
+			}
+			if backupPath != "" {
+				_, _ = fmt.Fprintf(out, "Backup created: %s\n", backupPath)
+			}
+		}
+		if err := opts.Settings.writeEntries(f.path, fs, f.entries); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "Generated %s\n", f.path)
+		opts.Settings.recordWrite(f.op, f.path, before, f.entries, backupPath, out)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/convert"
+)
+
+func TestImportEnv(t *testing.T) {
+	fs := newMockFileSystem()
+	environ := []string{
+		"PATH=/usr/bin",
+		"APP_PORT=3000",
+		"APP_SECRET_KEY=sk_live_abcdef1234567890",
+		"APP_GREETING=say \"hi\" there",
+		"APPLE=1",
+	}
+	opts := ImportOptions{Output: filepath.Join("api", ".env"), Prefix: "APP_"}
+
+	var out strings.Builder
+	if err := ImportEnv(opts, environ, fs, &out); err != nil {
+		t.Fatalf("ImportEnv() error = %v", err)
+	}
+	if want := "APP_GREETING='say \"hi\" there'\nAPP_PORT=3000\nAPP_SECRET_KEY=sk_live_abcdef1234567890\n"; fs.files[opts.Output] != want {
+		t.Errorf(".env = %q, want %q", fs.files[opts.Output], want)
+	}
+	example := fs.files[filepath.Join("api", ".env.example")]
+	if !strings.Contains(example, "APP_PORT=3000") || strings.Contains(example, "abcdef1234567890") {
+		t.Errorf(".env.example = %q, want the secret masked", example)
+	}
+	if !strings.Contains(out.String(), "Imported 3 variable(s) starting with APP_") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	err := ImportEnv(opts, environ, fs, &out)
+	if err == nil || !strings.Contains(err.Error(), "already exists. Use --force") {
+		t.Errorf("ImportEnv() over existing files error = %v", err)
+	}
+	opts.Force = true
+	if err := ImportEnv(opts, []string{"APP_PORT=4000"}, fs, &out); err != nil || fs.files[opts.Output] != "APP_PORT=4000\n" {
+		t.Errorf("ImportEnv(force) = %v, .env %q", err, fs.files[opts.Output])
+	}
+}
+
+func TestImportEnvErrors(t *testing.T) {
+	fs := newMockFileSystem()
+	if err := ImportEnv(ImportOptions{Output: ".env"}, nil, fs, &strings.Builder{}); err == nil || !strings.Contains(err.Error(), "--prefix is required") {
+		t.Errorf("ImportEnv() without prefix error = %v", err)
+	}
+	err := ImportEnv(ImportOptions{Output: ".env", Prefix: "APP_"}, []string{"HOME=/root"}, fs, &strings.Builder{})
+	if err == nil || !strings.Contains(err.Error(), "no environment variables start with APP_") {
+		t.Errorf("ImportEnv() without matches error = %v", err)
+	}
+
+	var out strings.Builder
+	if err := ImportEnv(ImportOptions{Output: ".env", Prefix: "APP_", DryRun: true}, []string{"APP_X=1"}, fs, &out); err != nil {
+		t.Fatalf("ImportEnv(dry run) error = %v", err)
+	}
+	if len(fs.files) != 0 || strings.Count(out.String(), "=== DRY RUN PREVIEW ===") != 2 {
+		t.Errorf("dry run wrote %v, output %q", fs.files, out.String())
+	}
+}
+
+func TestImportFile(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["config.yaml"] = "database:\n  host: db.internal\n  password: hunter2hunter2\nlog level: debug info\n"
+
+	var out strings.Builder
+	opts := ImportOptions{Output: ".env", Convert: convert.Options{Flatten: true}}
+	if err := ImportFile("config.yaml", opts, fs, &out); err != nil {
+		t.Fatalf("ImportFile() error = %v", err)
+	}
+	if want := "DATABASE__HOST=db.internal\nDATABASE__PASSWORD=hunter2hunter2\nLOG_LEVEL=\"debug info\"\n"; fs.files[".env"] != want {
+		t.Errorf(".env = %q, want %q", fs.files[".env"], want)
+	}
+	if strings.Contains(fs.files[".env.example"], "hunter2") {
+		t.Errorf(".env.example = %q, want the password masked", fs.files[".env.example"])
+	}
+	if !strings.Contains(out.String(), "Imported 3 key(s) from config.yaml") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	fs.files["empty.json"] = "{}"
+	if err := ImportFile("empty.json", ImportOptions{Output: "other.env"}, fs, &out); err == nil || !strings.Contains(err.Error(), "no values in empty.json") {
+		t.Errorf("ImportFile(empty) error = %v", err)
+	}
+}
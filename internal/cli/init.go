@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/schema"
+)
+
+// InitOptions configures Init. Each optional step runs when its field is
+// set, unless Ask is set, in which case the field is the default answer to
+// a prompt for that step.
+type InitOptions struct {
+	Dir       string
+	Force     bool
+	Example   bool
+	Schema    bool
+	Gitignore bool
+	Ask       bool
+	// ExampleOptions configures the .env.example created from .env.
+	ExampleOptions generator.Options
+	Settings       Settings
+}
+
+// gitignoreRules keep real env files and backups out of version control
+// while keeping the files meant to be shared.
+var gitignoreRules = []string{
+	".env",
+	".env.*",
+	"!.env.example",
+	"!" + schema.FileName,
+	"*.bak.*",
+	".dotenv-tui/",
+}
+
+// Init scaffolds dotenv-tui in opts.Dir: a starter .dotenv-tui.yaml, and
+// optionally a .env.example and .env.schema derived from an existing .env
+// and env rules in .gitignore. Existing files are kept unless opts.Force
+// is set; .gitignore is only ever appended to.
+func Init(opts InitOptions, fs FileSystem, in io.Reader, out io.Writer) error {
+	dir := opts.Dir
+	if dir == "" {
+		dir = "."
+	}
+	reader := bufio.NewReader(in)
+	ask := func(question string, def bool) (bool, error) {
+		if !opts.Ask {
+			return def, nil
+		}
+		return askYesNo(reader, out, question, def)
+	}
+
+	if err := writeIfAbsent(filepath.Join(dir, config.ProjectFileName), 0644, opts.Force, fs, out, func(w io.Writer) error {
+		_, err := io.WriteString(w, config.Starter)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	envPath := filepath.Join(dir, ".env")
+	var entries []parser.Entry
+	if fileExists(fs, envPath) {
+		var err error
+		if entries, err = parseAndClose(envPath, fs); err != nil {
+			return err
+		}
+	}
+
+	if entries != nil {
+		create, err := ask("Create .env.example from .env?", opts.Example)
+		if err != nil {
+			return err
+		}
+		if create {
+			examplePath := filepath.Join(dir, ".env.example")
+			example := opts.Settings.generateExample(entries, examplePath, opts.ExampleOptions, fs)
+			before := existingEntries(examplePath, fs)
+			if err := writeIfAbsent(examplePath, 0600, opts.Force, fs, out, func(w io.Writer) error {
+				if err := parser.WriteWithOptions(w, example, opts.Settings.Write); err != nil {
+					return err
+				}
+				opts.Settings.recordWrite(history.OpGenerateExample, examplePath, before, example, "", out)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	create, err := ask("Create "+schema.FileName+"?", opts.Schema)
+	if err != nil {
+		return err
+	}
+	if create {
+		if entries == nil {
+			_, _ = fmt.Fprintf(out, "Skipped %s: no .env to describe\n", schema.FileName)
+		} else if err := writeIfAbsent(filepath.Join(dir, schema.FileName), 0644, opts.Force, fs, out, func(w io.Writer) error {
+			return schema.Write(w, schema.Infer(entries))
+		}); err != nil {
+			return err
+		}
+	}
+
+	update, err := ask("Add env file rules to .gitignore?", opts.Gitignore)
+	if err != nil {
+		return err
+	}
+	if update {
+		return updateGitignore(filepath.Join(dir, ".gitignore"), fs, out)
+	}
+	return nil
+}
+
+// writeIfAbsent creates path with mode and the content produced by write,
+// unless it already exists and force is not set.
+func writeIfAbsent(path string, mode os.FileMode, force bool, fs FileSystem, out io.Writer, write func(io.Writer) error) error {
+	if !force && fileExists(fs, path) {
+		_, _ = fmt.Fprintf(out, "Skipped %s: already exists\n", path)
+		return nil
+	}
+
+	f, err := fs.CreateWithMode(path, mode)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Created %s\n", path)
+	return nil
+}
+
+// updateGitignore appends the gitignoreRules missing from path.
+func updateGitignore(path string, fs FileSystem, out io.Writer) error {
+	var existing string
+	if f, err := fs.Open(path); err == nil {
+		data, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		existing = string(data)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	present := make(map[string]bool)
+	for _, line := range strings.Split(existing, "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, rule := range gitignoreRules {
+		if !present[rule] {
+			missing = append(missing, rule)
+		}
+	}
+	if len(missing) == 0 {
+		_, _ = fmt.Fprintf(out, "Skipped %s: rules already present\n", path)
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString(existing)
+	if existing != "" && !strings.HasSuffix(existing, "\n") {
+		b.WriteString("\n")
+	}
+	if existing != "" {
+		b.WriteString("\n")
+	}
+	b.WriteString("# dotenv-tui\n")
+	for _, rule := range missing {
+		b.WriteString(rule + "\n")
+	}
+
+	f, err := fs.CreateWithMode(path, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if _, err := io.WriteString(f, b.String()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Updated %s: added %s\n", path, strings.Join(missing, " "))
+	return nil
+}
+
+// askYesNo prompts with question until it reads y, n or an empty answer,
+// which selects def. End of input also selects def.
+func askYesNo(reader *bufio.Reader, out io.Writer, question string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	for {
+		_, _ = fmt.Fprintf(out, "%s %s ", question, hint)
+		response, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, fmt.Errorf("failed to read user input: %w", err)
+		}
+		switch strings.ToLower(strings.TrimSpace(response)) {
+		case "":
+			if errors.Is(err, io.EOF) {
+				_, _ = fmt.Fprintln(out)
+			}
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return def, nil
+		}
+	}
+}
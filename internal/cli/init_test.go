@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/schema"
+)
+
+func TestInit(t *testing.T) {
+	dir := "project"
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	t.Run("creates everything from .env", func(t *testing.T) {
+		fs := newMockFileSystem()
+		fs.files[path(".env")] = "PORT=8080\nAPI_KEY=sk_live_abc123\n"
+		fs.files[path(".gitignore")] = "node_modules\n.env"
+
+		var out bytes.Buffer
+		opts := InitOptions{Dir: dir, Example: true, Schema: true, Gitignore: true}
+		if err := Init(opts, fs, strings.NewReader(""), &out); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+
+		if fs.files[path(config.ProjectFileName)] != config.Starter {
+			t.Error("Init() should write the starter config")
+		}
+		if got := fs.files[path(".env.example")]; got != "PORT=8080\nAPI_KEY=sk_***\n" {
+			t.Errorf(".env.example = %q", got)
+		}
+		if got := fs.files[path(schema.FileName)]; !strings.Contains(got, "PORT=port,required\nAPI_KEY=string,required\n") {
+			t.Errorf("%s = %q", schema.FileName, got)
+		}
+		want := "node_modules\n.env\n\n# dotenv-tui\n.env.*\n!.env.example\n!.env.schema\n*.bak.*\n.dotenv-tui/\n"
+		if got := fs.files[path(".gitignore")]; got != want {
+			t.Errorf(".gitignore = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("keeps existing files without force", func(t *testing.T) {
+		fs := newMockFileSystem()
+		fs.files[path(config.ProjectFileName)] = "theme: light\n"
+		fs.files[path(".gitignore")] = strings.Join(gitignoreRules, "\n")
+
+		var out bytes.Buffer
+		opts := InitOptions{Dir: dir, Example: true, Schema: true, Gitignore: true}
+		if err := Init(opts, fs, strings.NewReader(""), &out); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+
+		if fs.files[path(config.ProjectFileName)] != "theme: light\n" {
+			t.Error("Init() overwrote an existing config")
+		}
+		for _, want := range []string{"already exists", "no .env to describe", "rules already present"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output %q should mention %q", out.String(), want)
+			}
+		}
+	})
+
+	t.Run("asks before optional steps", func(t *testing.T) {
+		fs := newMockFileSystem()
+		fs.files[path(".env")] = "PORT=8080\n"
+
+		var out bytes.Buffer
+		// Example: default yes; schema: answer "maybe" then "y"; gitignore: n.
+		opts := InitOptions{Dir: dir, Example: true, Gitignore: true, Ask: true}
+		if err := Init(opts, fs, strings.NewReader("\nmaybe\ny\nn\n"), &out); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+
+		if _, ok := fs.files[path(".env.example")]; !ok {
+			t.Error("an empty answer should accept the default")
+		}
+		if _, ok := fs.files[path(schema.FileName)]; !ok {
+			t.Error("the schema should be created after re-asking")
+		}
+		if _, ok := fs.files[path(".gitignore")]; ok {
+			t.Error(".gitignore should be left alone after answering n")
+		}
+		if strings.Count(out.String(), "Create .env.schema? [y/N]") != 2 {
+			t.Errorf("output %q should repeat the unanswered question", out.String())
+		}
+	})
+}
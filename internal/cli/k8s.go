@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jellydn/dotenv-tui/internal/diff"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// fieldManager names dotenv-tui as the owner of the fields it applies.
+const fieldManager = "dotenv-tui"
+
+// K8sOptions configures PushK8s and PullK8s.
+type K8sOptions struct {
+	// File is the env file pushed from or pulled into.
+	File string
+	// Namespace of the Secret; empty uses the namespace of the current
+	// kubeconfig context.
+	Namespace string
+	Name      string
+	// DryRun shows a server-side diff on push, and a diff of the env file
+	// on pull, without changing anything.
+	DryRun       bool
+	CreateBackup bool
+	Settings     Settings
+}
+
+// Kubectl runs kubectl with args, feeding it stdin, and returns its
+// output, for testing.
+type Kubectl func(args []string, stdin []byte) ([]byte, error)
+
+// RunKubectl runs the kubectl found on PATH with the current kubeconfig
+// context. Exit status 1 from "kubectl diff" means the objects differ and is
+// not an error.
+func RunKubectl(args []string, stdin []byte) ([]byte, error) {
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		return nil, fmt.Errorf("kubectl is required for --push and --pull k8s: %w", err)
+	}
+	cmd := exec.Command("kubectl", args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(args) > 0 && args[0] == "diff" {
+		err = nil
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// PushK8s creates or updates a Kubernetes Secret holding the keys of an
+// env file, with a server-side apply so a dry run shows exactly what the
+// cluster would change.
+func PushK8s(opts K8sOptions, fs FileSystem, kubectl Kubectl, out io.Writer) error {
+	if opts.Name == "" {
+		return fmt.Errorf("--name is required")
+	}
+	entries, err := parseAndClose(opts.File, fs)
+	if err != nil {
+		return err
+	}
+	data := make(map[string]string)
+	for _, entry := range entries {
+		if kv, ok := entry.(parser.KeyValue); ok {
+			data[kv.Key] = base64.StdEncoding.EncodeToString([]byte(kv.Value))
+		}
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("no keys to push in %s", opts.File)
+	}
+
+	metadata := map[string]any{
+		"name":   opts.Name,
+		"labels": map[string]string{"app.kubernetes.io/managed-by": fieldManager},
+	}
+	if opts.Namespace != "" {
+		metadata["namespace"] = opts.Namespace
+	}
+	manifest, err := json.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   metadata,
+		"type":       "Opaque",
+		"data":       data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode secret: %w", err)
+	}
+
+	verb := "apply"
+	if opts.DryRun {
+		verb = "diff"
+	}
+	args := append([]string{verb, "--server-side", "--field-manager=" + fieldManager, "--force-conflicts"}, namespaceArgs(opts)...)
+	output, err := kubectl(append(args, "-f", "-"), manifest)
+	if err != nil {
+		return fmt.Errorf("kubectl %s failed: %w", verb, err)
+	}
+
+	target := secretName(opts)
+	if opts.DryRun {
+		if len(bytes.TrimSpace(output)) == 0 {
+			_, _ = fmt.Fprintf(out, "Secret %s is up to date with %s\n", target, opts.File)
+			return nil
+		}
+		_, _ = out.Write(output)
+		_, _ = fmt.Fprintf(out, "Would push %d key(s) from %s to Secret %s\n", len(data), opts.File, target)
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "Pushed %d key(s) from %s to Secret %s\n", len(data), opts.File, target)
+	return nil
+}
+
+// PullK8s writes the keys of a Kubernetes Secret into an env file. Keys
+// already in the file are updated in place, keeping its comments and
+// order, and the others are appended; keys missing from the Secret are
+// kept and reported.
+func PullK8s(opts K8sOptions, fs FileSystem, kubectl Kubectl, out io.Writer) error {
+	if opts.Name == "" {
+		return fmt.Errorf("--name is required")
+	}
+	args := append([]string{"get", "secret", opts.Name, "-o", "json"}, namespaceArgs(opts)...)
+	output, err := kubectl(args, nil)
+	if err != nil {
+		return fmt.Errorf("kubectl get failed: %w", err)
+	}
+	var secret struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(output, &secret); err != nil {
+		return fmt.Errorf("failed to parse secret: %w", err)
+	}
+	values := make(map[string]string, len(secret.Data))
+	for key, encoded := range secret.Data {
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+		if !utf8.Valid(raw) || bytes.IndexByte(raw, 0) >= 0 {
+			return fmt.Errorf("key %s holds binary data, which cannot be written to an env file", key)
+		}
+		values[key] = string(raw)
+	}
+
+	var before []parser.Entry
+	if fileExists(fs, opts.File) {
+		if before, err = parseAndClose(opts.File, fs); err != nil {
+			return err
+		}
+	}
+	after, _ := fillValues(before, mapLookup(values))
+	present := keySet(before)
+	var added, kept []string
+	for key := range values {
+		if !present[key] {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	for _, key := range added {
+		after = append(after, parser.QuoteValue(parser.KeyValue{Key: key, Value: values[key]}))
+	}
+	for key := range present {
+		if _, ok := values[key]; !ok {
+			kept = append(kept, key)
+		}
+	}
+	sort.Strings(kept)
+
+	target := secretName(opts)
+	if opts.DryRun {
+		name := filepath.ToSlash(opts.File)
+		_, _ = fmt.Fprint(out, diff.Unified(name, name, opts.Settings.maskedLines(before), opts.Settings.maskedLines(after)))
+		_, _ = fmt.Fprintf(out, "Would pull %d key(s) from Secret %s into %s\n", len(values), target, opts.File)
+		return nil
+	}
+
+	var backupPath string
+	if opts.CreateBackup && fileExists(fs, opts.File) {
+		if backupPath, err = opts.Settings.backupFile(opts.File, fs); err != nil {
+			return fmt.Errorf("failed to create backup: %w", err)
+		}
+		if backupPath != "" {
+			_, _ = fmt.Fprintf(out, "Backup created: %s\n", backupPath)
+		}
+	}
+	if err := opts.Settings.writeEntries(opts.File, fs, after); err != nil {
+		return err
+	}
+	opts.Settings.recordWrite(history.OpPullK8s, opts.File, before, after, backupPath, out)
+
+	_, _ = fmt.Fprintf(out, "Pulled %d key(s) from Secret %s into %s\n", len(values), target, opts.File)
+	if len(kept) > 0 {
+		_, _ = fmt.Fprintf(out, "Kept %d key(s) not in the Secret: %s\n", len(kept), strings.Join(kept, ", "))
+	}
+	return nil
+}
+
+func namespaceArgs(opts K8sOptions) []string {
+	if opts.Namespace == "" {
+		return nil
+	}
+	return []string{"--namespace", opts.Namespace}
+}
+
+// secretName returns the Secret as namespace/name, or name alone for the
+// current namespace.
+func secretName(opts K8sOptions) string {
+	if opts.Namespace == "" {
+		return opts.Name
+	}
+	return opts.Namespace + "/" + opts.Name
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPushK8s(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "# app\nPORT=3000\nDB_PASS=\"hunter 2\"\n"
+
+	var gotArgs []string
+	var manifest struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Data map[string]string `json:"data"`
+	}
+	kubectl := func(args []string, stdin []byte) ([]byte, error) {
+		gotArgs = args
+		if err := json.Unmarshal(stdin, &manifest); err != nil {
+			t.Fatalf("manifest is not JSON: %v", err)
+		}
+		if args[0] == "diff" {
+			return []byte("-  PORT: 8080\n+  PORT: 3000\n"), nil
+		}
+		return []byte("secret/app-env serverside-applied\n"), nil
+	}
+
+	var out strings.Builder
+	opts := K8sOptions{File: ".env", Namespace: "prod", Name: "app-env"}
+	if err := PushK8s(opts, fs, kubectl, &out); err != nil {
+		t.Fatalf("PushK8s() error = %v", err)
+	}
+	want := []string{"apply", "--server-side", "--field-manager=dotenv-tui", "--force-conflicts", "--namespace", "prod", "-f", "-"}
+	if !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("kubectl args = %v, want %v", gotArgs, want)
+	}
+	if manifest.Metadata.Name != "app-env" || manifest.Metadata.Namespace != "prod" || manifest.Data["DB_PASS"] != base64.StdEncoding.EncodeToString([]byte("hunter 2")) {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	if out.String() != "Pushed 2 key(s) from .env to Secret prod/app-env\n" {
+		t.Errorf("output = %q", out.String())
+	}
+
+	out.Reset()
+	opts.DryRun = true
+	if err := PushK8s(opts, fs, kubectl, &out); err != nil {
+		t.Fatalf("PushK8s(dry run) error = %v", err)
+	}
+	if gotArgs[0] != "diff" || !strings.Contains(out.String(), "+  PORT: 3000\nWould push 2 key(s)") {
+		t.Errorf("dry run args %v, output %q", gotArgs, out.String())
+	}
+
+	if err := PushK8s(K8sOptions{File: ".env"}, fs, kubectl, &out); err == nil || !strings.Contains(err.Error(), "--name is required") {
+		t.Errorf("PushK8s() without a name error = %v", err)
+	}
+}
+
+func TestPullK8s(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "# app\nPORT=8080\nLOCAL_ONLY=1\n"
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	secret := `{"data": {"PORT": "` + encode("3000") + `", "DB_PASS": "` + encode("hunter 2") + `"}}`
+
+	var gotArgs []string
+	kubectl := func(args []string, _ []byte) ([]byte, error) {
+		gotArgs = args
+		return []byte(secret), nil
+	}
+
+	var out strings.Builder
+	opts := K8sOptions{File: ".env", Name: "app-env", DryRun: true}
+	if err := PullK8s(opts, fs, kubectl, &out); err != nil {
+		t.Fatalf("PullK8s(dry run) error = %v", err)
+	}
+	if !reflect.DeepEqual(gotArgs, []string{"get", "secret", "app-env", "-o", "json"}) {
+		t.Errorf("kubectl args = %v", gotArgs)
+	}
+	if !strings.Contains(out.String(), "+PORT=3000") || strings.Contains(out.String(), "hunter") || fs.files[".env"] != "# app\nPORT=8080\nLOCAL_ONLY=1\n" {
+		t.Errorf("dry run output %q should mask secrets and leave the file alone", out.String())
+	}
+
+	out.Reset()
+	opts.DryRun = false
+	if err := PullK8s(opts, fs, kubectl, &out); err != nil {
+		t.Fatalf("PullK8s() error = %v", err)
+	}
+	if want := "# app\nPORT=3000\nLOCAL_ONLY=1\nDB_PASS=\"hunter 2\"\n"; fs.files[".env"] != want {
+		t.Errorf(".env = %q, want %q", fs.files[".env"], want)
+	}
+	if !strings.Contains(out.String(), "Pulled 2 key(s) from Secret app-env into .env\nKept 1 key(s) not in the Secret: LOCAL_ONLY") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	secret = `{"data": {"CERT": "` + encode("\x00\xff") + `"}}`
+	if err := PullK8s(opts, fs, kubectl, &out); err == nil || !strings.Contains(err.Error(), "binary data") {
+		t.Errorf("PullK8s() error = %v, want a binary data error", err)
+	}
+}
+
+func TestRunKubectlMissing(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	_, err := RunKubectl([]string{"version"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "kubectl is required") {
+		t.Errorf("RunKubectl() error = %v, want kubectl reported missing", err)
+	}
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jellydn/dotenv-tui/internal/lint"
+	"github.com/jellydn/dotenv-tui/internal/schema"
+)
+
+// ErrLintFindings is returned by LintFiles when error-severity findings
+// exist, so callers can exit non-zero without printing an extra error.
+var ErrLintFindings = errors.New("lint found errors")
+
+// lintReport is the JSON form of a LintFiles run.
+type lintReport struct {
+	FilesChecked int            `json:"files_checked"`
+	Findings     []lint.Finding `json:"findings"`
+}
+
+// LintFiles scans dir for env files and example files and reports style
+// and correctness problems, in text or JSON format. Keys marked required
+// in a .env.schema next to an env file must have a value in it.
+func LintFiles(ctx context.Context, dir string, format string, opts lint.Options, settings Settings, sc DirScanner, fs FileSystem, out io.Writer) error {
+	if dir == "" {
+		dir = "."
+	}
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unsupported format %q (use %s or %s)", format, FormatText, FormatJSON)
+	}
+
+	envFiles, err := sc.Scan(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+	exampleFiles, err := sc.ScanExamples(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan for .env.example files: %w", err)
+	}
+
+	var report lintReport
+	check := func(file string, fileOpts lint.Options) error {
+		data, err := readFile(filepath.Join(dir, file), fs)
+		if err != nil {
+			return err
+		}
+		findings, err := lint.Check(file, data, fileOpts)
+		if err != nil {
+			return err
+		}
+		report.FilesChecked++
+		report.Findings = append(report.Findings, findings...)
+		return nil
+	}
+	for _, file := range envFiles {
+		fileOpts := opts
+		schemaFile, err := settings.schemaPath(filepath.Join(dir, file))
+		if err != nil {
+			return err
+		}
+		fileOpts.Required, err = requiredKeys(schemaFile, fs)
+		if err != nil {
+			return err
+		}
+		if err := check(file, fileOpts); err != nil {
+			return err
+		}
+	}
+	for _, file := range exampleFiles {
+		if err := check(file, opts); err != nil {
+			return err
+		}
+	}
+
+	if format == FormatJSON {
+		if report.Findings == nil {
+			report.Findings = []lint.Finding{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+	} else {
+		writeLintText(report, out)
+	}
+
+	if lint.Count(report.Findings, lint.SeverityError) > 0 {
+		return ErrLintFindings
+	}
+	return nil
+}
+
+func writeLintText(report lintReport, out io.Writer) {
+	if len(report.Findings) == 0 {
+		_, _ = fmt.Fprintf(out, "Linted %d file(s): no problems found\n", report.FilesChecked)
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "Linted %d file(s): %d error(s), %d warning(s), %d info\n\n", report.FilesChecked,
+		lint.Count(report.Findings, lint.SeverityError),
+		lint.Count(report.Findings, lint.SeverityWarning),
+		lint.Count(report.Findings, lint.SeverityInfo))
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, f := range report.Findings {
+		_, _ = fmt.Fprintf(tw, "%s\t%s:%d\t%s\t%s\n", strings.ToUpper(string(f.Severity)), f.File, f.Line, f.Rule, f.Message)
+	}
+	_ = tw.Flush()
+}
+
+// requiredKeys returns the keys the schema at path marks required, or nil
+// if there is no schema.
+func requiredKeys(path string, fs FileSystem) (map[string]bool, error) {
+	if !fileExists(fs, path) {
+		return nil, nil
+	}
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	fields, err := schema.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	required := make(map[string]bool)
+	for _, field := range fields {
+		if field.Required {
+			required[field.Key] = true
+		}
+	}
+	return required, nil
+}
+
+func readFile(path string, fs FileSystem) ([]byte, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
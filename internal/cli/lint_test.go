@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/lint"
+	"github.com/jellydn/dotenv-tui/internal/schema"
+)
+
+func TestLintFiles(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[filepath.Join("proj", ".env")] = "DATABASE_URL=\nPORT=3000 \n"
+	fs.files[filepath.Join("proj", schema.FileName)] = "DATABASE_URL=url,required\n"
+	fs.files[filepath.Join("proj", ".env.example")] = "DATABASE_URL=\nPORT=3000\n"
+
+	sc := &mockDirScanner{
+		scanFiles:    []string{".env"},
+		exampleFiles: []string{".env.example"},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var out strings.Builder
+		err := LintFiles(t.Context(), "proj", FormatText, lint.Options{}, Settings{}, sc, fs, &out)
+		if !errors.Is(err, ErrLintFindings) {
+			t.Errorf("LintFiles() error = %v, want ErrLintFindings", err)
+		}
+		for _, want := range []string{"Linted 2 file(s): 1 error(s), 1 warning(s)", "ERROR", ".env:1", "empty-required", ".env:2", "trailing-whitespace"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out strings.Builder
+		opts := lint.Options{Severities: map[lint.Rule]lint.Severity{lint.RuleEmptyRequired: lint.SeverityWarning}}
+		if err := LintFiles(t.Context(), "proj", FormatJSON, opts, Settings{}, sc, fs, &out); err != nil {
+			t.Fatalf("LintFiles() unexpected error: %v", err)
+		}
+		var report lintReport
+		if err := json.Unmarshal([]byte(out.String()), &report); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+		}
+		if report.FilesChecked != 2 || len(report.Findings) != 2 {
+			t.Errorf("report = %+v, want 2 files and 2 findings", report)
+		}
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		var out strings.Builder
+		clean := &mockDirScanner{exampleFiles: []string{".env.example"}}
+		if err := LintFiles(t.Context(), "proj", FormatText, lint.Options{}, Settings{}, clean, fs, &out); err != nil {
+			t.Fatalf("LintFiles() unexpected error: %v", err)
+		}
+		if !strings.Contains(out.String(), "no problems found") {
+			t.Errorf("output = %q", out.String())
+		}
+	})
+
+	t.Run("schema set for the directory", func(t *testing.T) {
+		shared := filepath.Join("shared", schema.FileName)
+		fs.files[shared] = "PORT=port,required\n"
+		settings := Settings{Dir: func(string) (config.DirSettings, error) {
+			return config.DirSettings{Schema: shared}, nil
+		}}
+
+		var out strings.Builder
+		opts := lint.Options{Severities: map[lint.Rule]lint.Severity{lint.RuleTrailingWhitespace: lint.SeverityOff}}
+		if err := LintFiles(t.Context(), "proj", FormatText, opts, settings, sc, fs, &out); err != nil {
+			t.Fatalf("LintFiles() unexpected error: %v\n%s", err, out.String())
+		}
+		if strings.Contains(out.String(), "empty-required") {
+			t.Errorf("output = %q, want DATABASE_URL not required by the shared schema", out.String())
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		if err := LintFiles(t.Context(), "proj", "xml", lint.Options{}, Settings{}, sc, fs, &strings.Builder{}); err == nil {
+			t.Error("LintFiles() expected error for unsupported format")
+		}
+	})
+}
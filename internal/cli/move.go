@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/diff"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// MoveOptions configures MoveKeys.
+type MoveOptions struct {
+	Keys []string
+	From string
+	To   string
+	// Copy leaves the keys in From instead of removing them.
+	Copy         bool
+	DryRun       bool
+	CreateBackup bool
+	Settings     Settings
+}
+
+// MoveKeys moves opts.Keys, with the comments directly above each, from
+// the env file opts.From to the end of opts.To, creating it if needed. No
+// file is written if a key is missing from From or already set in To, and
+// if writing From fails, To is put back. With opts.DryRun the changes are
+// printed as a unified diff instead, with secret values masked.
+func MoveKeys(opts MoveOptions, fs FileSystem, out io.Writer) error {
+	switch {
+	case len(opts.Keys) == 0:
+		return fmt.Errorf("no keys given to move")
+	case opts.From == "" || opts.To == "":
+		return fmt.Errorf("--from and --to are required")
+	case filepath.Clean(opts.From) == filepath.Clean(opts.To):
+		return fmt.Errorf("--from and --to are the same file %s", opts.From)
+	}
+
+	src, err := parseAndClose(opts.From, fs)
+	if err != nil {
+		return err
+	}
+	var dst []parser.Entry
+	if fileExists(fs, opts.To) {
+		if dst, err = parseAndClose(opts.To, fs); err != nil {
+			return err
+		}
+	}
+
+	newSrc, newDst, err := parser.MoveKeys(src, dst, opts.Keys, opts.Copy)
+	if err != nil {
+		return fmt.Errorf("failed to move keys from %s to %s: %w", opts.From, opts.To, err)
+	}
+
+	// The destination is written first, so that a failure part way never
+	// loses the keys.
+	plans := []filePlan{{path: opts.To, before: dst, after: newDst}}
+	if !opts.Copy {
+		plans = append(plans, filePlan{path: opts.From, before: src, after: newSrc})
+	}
+
+	verb, done, op := "move", "Moved", history.OpMove
+	if opts.Copy {
+		verb, done, op = "copy", "Copied", history.OpCopy
+	}
+
+	if opts.DryRun {
+		for _, plan := range plans {
+			name := filepath.ToSlash(plan.path)
+			_, _ = fmt.Fprint(out, diff.Unified(name, name, opts.Settings.maskedLines(plan.before), opts.Settings.maskedLines(plan.after)))
+		}
+		_, _ = fmt.Fprintf(out, "Would %s %d key(s) from %s to %s\n", verb, len(opts.Keys), opts.From, opts.To)
+		return nil
+	}
+
+	backups := make([]string, len(plans))
+	if opts.CreateBackup {
+		for i, plan := range plans {
+			backups[i], err = opts.Settings.backupFile(plan.path, fs)
+			if err != nil {
+				return fmt.Errorf("failed to create backup: %w", err)
+			}
+			if backups[i] != "" {
+				_, _ = fmt.Fprintf(out, "Backup created: %s\n", backups[i])
+			}
+		}
+	}
+
+	for i, plan := range plans {
+		if err := opts.Settings.writeEntries(plan.path, fs, plan.after); err != nil {
+			return opts.Settings.rollbackWrites(plans[:i+1], fs, err)
+		}
+	}
+	for i, plan := range plans {
+		opts.Settings.recordWrite(op, plan.path, plan.before, plan.after, backups[i], out)
+	}
+
+	_, _ = fmt.Fprintf(out, "%s %d key(s) from %s to %s: %s\n", done, len(opts.Keys), opts.From, opts.To, strings.Join(opts.Keys, ", "))
+	return nil
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMoveKeys(t *testing.T) {
+	billing := filepath.Join("billing", ".env")
+	tests := []struct {
+		name    string
+		copy    bool
+		wantSrc string
+		wantOut string
+	}{
+		{
+			name:    "move",
+			wantSrc: "PORT=3000\n",
+			wantOut: "Moved 1 key(s) from .env to " + billing + ": STRIPE_KEY",
+		},
+		{
+			name:    "copy",
+			copy:    true,
+			wantSrc: "PORT=3000\n# Billing\nSTRIPE_KEY=sk_test\n",
+			wantOut: "Copied 1 key(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newMockFileSystem()
+			fs.files[".env"] = "PORT=3000\n# Billing\nSTRIPE_KEY=sk_test\n"
+			fs.files[billing] = "SERVICE=billing\n"
+
+			var out strings.Builder
+			opts := MoveOptions{Keys: []string{"STRIPE_KEY"}, From: ".env", To: billing, Copy: tt.copy}
+			if err := MoveKeys(opts, fs, &out); err != nil {
+				t.Fatalf("MoveKeys() error = %v", err)
+			}
+			if fs.files[".env"] != tt.wantSrc {
+				t.Errorf(".env = %q, want %q", fs.files[".env"], tt.wantSrc)
+			}
+			if got := fs.files[billing]; got != "SERVICE=billing\n\n# Billing\nSTRIPE_KEY=sk_test\n" {
+				t.Errorf("%s = %q", billing, got)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestMoveKeysNewFileAndDryRun(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "PORT=3000\nREDIS_URL=redis://localhost\n"
+
+	var out strings.Builder
+	opts := MoveOptions{Keys: []string{"REDIS_URL"}, From: ".env", To: "cache.env", DryRun: true}
+	if err := MoveKeys(opts, fs, &out); err != nil {
+		t.Fatalf("MoveKeys() error = %v", err)
+	}
+	if _, ok := fs.files["cache.env"]; ok {
+		t.Error("a dry run should not write")
+	}
+	for _, want := range []string{"+++ cache.env\n@@ -0,0 +1,1 @@\n+REDIS_URL=redis://localhost\n", "-REDIS_URL=redis://localhost\n", "Would move 1 key(s)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	opts.DryRun = false
+	if err := MoveKeys(opts, fs, io.Discard); err != nil {
+		t.Fatalf("MoveKeys() error = %v", err)
+	}
+	if fs.files["cache.env"] != "REDIS_URL=redis://localhost\n" {
+		t.Errorf("cache.env = %q", fs.files["cache.env"])
+	}
+}
+
+func TestMoveKeysErrors(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "PORT=3000\n"
+	fs.files["api.env"] = "PORT=4000\n"
+
+	tests := []struct {
+		name string
+		opts MoveOptions
+		want string
+	}{
+		{"no keys", MoveOptions{From: ".env", To: "api.env"}, "no keys"},
+		{"same file", MoveOptions{Keys: []string{"PORT"}, From: ".env", To: "./.env"}, "same file"},
+		{"missing key", MoveOptions{Keys: []string{"HOST"}, From: ".env", To: "api.env"}, "HOST not found"},
+		{"clash", MoveOptions{Keys: []string{"PORT"}, From: ".env", To: "api.env"}, "PORT already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MoveKeys(tt.opts, fs, io.Discard)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("MoveKeys() error = %v, want %q", err, tt.want)
+			}
+			if fs.files[".env"] != "PORT=3000\n" || fs.files["api.env"] != "PORT=4000\n" {
+				t.Error("a failed move should not write")
+			}
+		})
+	}
+}
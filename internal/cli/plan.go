@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/jellydn/dotenv-tui/internal/backup"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+)
+
+// Kinds of change a dry run reports for a key. Values are never included,
+// since either side may hold secrets.
+const (
+	ChangeAdded     = "added"
+	ChangeRemoved   = "removed"
+	ChangeChanged   = "changed"
+	ChangeUnchanged = "unchanged"
+)
+
+// Actions a dry run reports for a file.
+const (
+	ActionCreate    = "create"
+	ActionOverwrite = "overwrite"
+)
+
+// Plan is the machine-readable form of a dry run.
+type Plan struct {
+	Files []PlannedFile `json:"files"`
+}
+
+// PlannedFile describes one file a real run would write.
+type PlannedFile struct {
+	Source string `json:"source"`
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	// Backup is where the existing file would be backed up. The timestamp
+	// in the name is taken when the plan is made, unless backups are
+	// numbered in deterministic mode.
+	Backup  string      `json:"backup,omitempty"`
+	Changes []KeyChange `json:"changes"`
+}
+
+// KeyChange is the fate of a single key when a file is written.
+type KeyChange struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+}
+
+// keyChanges compares the keys of the current and incoming entries. Keys
+// appear in incoming order, followed by removed keys in current order.
+func keyChanges(current, incoming []parser.Entry) []KeyChange {
+	currentValues := make(map[string]string)
+	for _, e := range current {
+		if kv, ok := e.(parser.KeyValue); ok {
+			currentValues[kv.Key] = kv.Value
+		}
+	}
+
+	changes := []KeyChange{}
+	seen := make(map[string]bool)
+	for _, e := range incoming {
+		kv, ok := e.(parser.KeyValue)
+		if !ok || seen[kv.Key] {
+			continue
+		}
+		seen[kv.Key] = true
+
+		old, exists := currentValues[kv.Key]
+		change := ChangeUnchanged
+		switch {
+		case !exists:
+			change = ChangeAdded
+		case old != kv.Value:
+			change = ChangeChanged
+		}
+		changes = append(changes, KeyChange{Key: kv.Key, Change: change})
+	}
+	for _, e := range current {
+		if kv, ok := e.(parser.KeyValue); ok && !seen[kv.Key] {
+			seen[kv.Key] = true
+			changes = append(changes, KeyChange{Key: kv.Key, Change: ChangeRemoved})
+		}
+	}
+	return changes
+}
+
+// planFile describes writing entries, generated from source, to path.
+func (s Settings) planFile(source, path string, entries []parser.Entry, createBackup bool, fs FileSystem) (PlannedFile, error) {
+	file := PlannedFile{Source: source, Path: path, Action: ActionCreate}
+
+	var current []parser.Entry
+	if fileExists(fs, path) {
+		var err error
+		if current, err = parseAndClose(path, fs); err != nil {
+			return file, err
+		}
+		file.Action = ActionOverwrite
+		if createBackup {
+			var opts backup.Options
+			if opts, err = s.backupOptions(path); err != nil {
+				return file, err
+			}
+			if file.Backup, err = opts.Plan(path, fsAdapter{fs}); err != nil {
+				return file, err
+			}
+		}
+	}
+
+	file.Changes = keyChanges(current, entries)
+	return file, nil
+}
+
+// PlanFile is the dry-run counterpart of GenerateFile: it writes a JSON
+// plan for generating outputFilename from inputPath to out.
+func PlanFile(inputPath string, createBackup bool, outputFilename string, processEntries EntryProcessor, parseErrMsg string, settings Settings, fs FileSystem, out io.Writer) error {
+	file, err := fs.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	entries, err := parser.Parse(file)
+	_ = file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", parseErrMsg, err)
+	}
+
+	outputPath := filepath.Join(filepath.Dir(inputPath), outputFilename)
+	planned, err := settings.planFile(inputPath, outputPath, processEntries(entries), createBackup, fs)
+	if err != nil {
+		return err
+	}
+	return writePlan(Plan{Files: []PlannedFile{planned}}, out)
+}
+
+// PlanExampleFile writes a JSON plan for GenerateExampleFileWithOptions.
+func PlanExampleFile(inputPath string, createBackup bool, opts generator.Options, settings Settings, fs FileSystem, out io.Writer) error {
+	examplePath := filepath.Join(filepath.Dir(inputPath), ".env.example")
+	return PlanFile(inputPath, createBackup, ".env.example", func(entries []parser.Entry) []parser.Entry {
+		return settings.generateExample(entries, examplePath, opts, fs)
+	}, ".env file", settings, fs, out)
+}
+
+// PlanEnvFile writes a JSON plan for GenerateEnvFileWithValues.
+func PlanEnvFile(inputPath string, createBackup bool, values map[string]string, lookup LookupFunc, settings Settings, fs FileSystem, out io.Writer) error {
+	return PlanFile(inputPath, createBackup, ".env", func(entries []parser.Entry) []parser.Entry {
+		entries, _, _ = settings.fillAnswers(entries, values, "--set", lookup)
+		return entries
+	}, ".env.example file", settings, fs, out)
+}
+
+// planYolo writes a JSON plan for generating a .env from every example.
+func planYolo(exampleFiles []string, opts YoloOptions, fs FileSystem, out io.Writer) error {
+	plan := Plan{Files: []PlannedFile{}}
+	for _, exampleFile := range exampleFiles {
+		entries, err := parseAndClose(exampleFile, fs)
+		if err != nil {
+			return err
+		}
+		entries, _, _ = opts.fill(entries)
+		planned, err := opts.Settings.planFile(exampleFile, scanner.ExampleTarget(exampleFile), entries, opts.CreateBackup, fs)
+		if err != nil {
+			return err
+		}
+		plan.Files = append(plan.Files, planned)
+	}
+	return writePlan(plan, out)
+}
+
+func writePlan(plan Plan, out io.Writer) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(plan); err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	return nil
+}
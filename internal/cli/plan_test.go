@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/generator"
+)
+
+func decodePlan(t *testing.T, data []byte) Plan {
+	t.Helper()
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		t.Fatalf("output is not a JSON plan: %v\n%s", err, data)
+	}
+	return plan
+}
+
+func TestPlanExampleFile(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/app/.env"] = "API_KEY=sk_live_abc\nPORT=8080\nNEW=x\n"
+	fs.files["/app/.env.example"] = "API_KEY=old\nPORT=8080\nGONE=1\n"
+
+	var out bytes.Buffer
+	if err := PlanExampleFile("/app/.env", true, generator.Options{}, Settings{}, fs, &out); err != nil {
+		t.Fatalf("PlanExampleFile() error = %v", err)
+	}
+	if strings.Contains(out.String(), "sk_") {
+		t.Errorf("plan must not include values:\n%s", out.String())
+	}
+
+	plan := decodePlan(t, out.Bytes())
+	if len(plan.Files) != 1 {
+		t.Fatalf("plan has %d files, want 1", len(plan.Files))
+	}
+	file := plan.Files[0]
+	if file.Source != "/app/.env" || file.Path != "/app/.env.example" || file.Action != ActionOverwrite {
+		t.Errorf("planned file = %+v", file)
+	}
+	if !strings.HasPrefix(file.Backup, "/app/.env.example.bak.") {
+		t.Errorf("Backup = %q, want a timestamped backup path", file.Backup)
+	}
+	want := []KeyChange{
+		{"API_KEY", ChangeChanged},
+		{"PORT", ChangeUnchanged},
+		{"NEW", ChangeAdded},
+		{"GONE", ChangeRemoved},
+	}
+	if !reflect.DeepEqual(file.Changes, want) {
+		t.Errorf("Changes = %+v, want %+v", file.Changes, want)
+	}
+	if len(fs.files) != 2 || fs.files["/app/.env.example"] != "API_KEY=old\nPORT=8080\nGONE=1\n" {
+		t.Error("a dry run must not write anything")
+	}
+}
+
+func TestPlanEnvFileCreate(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/app/.env.example"] = "KEY=value\n"
+
+	var out bytes.Buffer
+	if err := PlanEnvFile("/app/.env.example", true, nil, nil, Settings{}, fs, &out); err != nil {
+		t.Fatalf("PlanEnvFile() error = %v", err)
+	}
+
+	file := decodePlan(t, out.Bytes()).Files[0]
+	if file.Action != ActionCreate || file.Backup != "" {
+		t.Errorf("planned file = %+v, want a create without backup", file)
+	}
+	if !reflect.DeepEqual(file.Changes, []KeyChange{{"KEY", ChangeAdded}}) {
+		t.Errorf("Changes = %+v", file.Changes)
+	}
+}
+
+func TestGenerateAllEnvFilesJSONPlan(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/a/.env.example"] = "KEY=value\n"
+	fs.files["/b/.env.example"] = "KEY=value\n"
+	fs.files["/b/.env"] = "KEY=old\n"
+	sc := &mockDirScanner{exampleFiles: []string{"/a/.env.example", "/b/.env.example"}}
+
+	var out bytes.Buffer
+	opts := YoloOptions{DryRun: true, Format: FormatJSON}
+	if err := GenerateAllEnvFilesWithOptions(t.Context(), opts, fs, sc, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan := decodePlan(t, out.Bytes())
+	var actions []string
+	for _, f := range plan.Files {
+		actions = append(actions, f.Action+":"+f.Path)
+	}
+	if got := strings.Join(actions, ","); got != "create:/a/.env,overwrite:/b/.env" {
+		t.Errorf("plan actions = %s", got)
+	}
+	if fs.files["/b/.env"] != "KEY=old\n" {
+		t.Error("a dry run must not write anything")
+	}
+}
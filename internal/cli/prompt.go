@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// PromptOptions configures PromptEnvFile.
+type PromptOptions struct {
+	Force        bool
+	CreateBackup bool
+	DryRun       bool
+	// Values and Env fill keys before prompting, as for
+	// GenerateEnvFileWithValues. Keys they fill are not asked for.
+	Values map[string]string
+	Env    LookupFunc
+	// ReadSecret reads one line without echoing it, for secret keys. Nil
+	// reads secrets from the input like any other answer.
+	ReadSecret func() (string, error)
+	Settings   Settings
+}
+
+// PromptEnvFile generates a .env from inputPath like GenerateEnvFile, but
+// first asks on out for the value of every key whose example value is empty
+// or a placeholder, one at a time. An empty answer, or the end of the input,
+// keeps the example's value.
+func PromptEnvFile(inputPath string, opts PromptOptions, fs FileSystem, in io.Reader, out io.Writer) error {
+	outputPath := filepath.Join(filepath.Dir(inputPath), ".env")
+	if !opts.Force && !opts.DryRun && fileExists(fs, outputPath) {
+		return fmt.Errorf("%s already exists. Use --force to overwrite", outputPath)
+	}
+
+	entries, err := parseAndClose(inputPath, fs)
+	if err != nil {
+		return err
+	}
+	entries, set, filled := opts.Settings.fillAnswers(entries, opts.Values, "--set", opts.Env)
+	entries, answered, err := promptValues(entries, slices.Concat(set, filled), opts.Settings.Rules, opts.ReadSecret, bufio.NewReader(in), out)
+	if err != nil {
+		return err
+	}
+	entries = opts.Settings.annotateKeys(entries, answered, parser.SetVia("prompt", opts.Settings.today()))
+
+	err = GenerateFile(inputPath, opts.Force, opts.CreateBackup, opts.DryRun, ".env", func([]parser.Entry) []parser.Entry {
+		return entries
+	}, ".env.example file", opts.Settings, fs, out)
+	if err != nil {
+		return err
+	}
+	if len(answered) > 0 {
+		_, _ = fmt.Fprintf(out, "Answered: %s\n", strings.Join(answered, ", "))
+	}
+	return nil
+}
+
+// promptValues asks for each unfilled placeholder key and returns the
+// entries with the answers applied and the keys that were answered. Keys
+// that are secret under rules are read with readSecret.
+func promptValues(entries []parser.Entry, skip []string, rules detector.Rules, readSecret func() (string, error), reader *bufio.Reader, out io.Writer) ([]parser.Entry, []string, error) {
+	var answered []string
+	result := slices.Clone(entries)
+	eof := false
+	for i, entry := range result {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok || slices.Contains(skip, kv.Key) || (kv.Value != "" && !detector.IsPlaceholder(kv.Value)) {
+			continue
+		}
+		if eof {
+			break
+		}
+
+		secret := rules.IsSecretKey(kv.Key) || detector.HasSecretValue(kv.Value)
+		prompt := kv.Key
+		if kv.Value != "" {
+			prompt += " [" + kv.Value + "]"
+		}
+		if secret {
+			prompt += " (hidden)"
+		}
+		_, _ = fmt.Fprintf(out, "%s: ", prompt)
+
+		var answer string
+		var err error
+		if secret && readSecret != nil {
+			answer, err = readSecret()
+			_, _ = fmt.Fprintln(out)
+		} else {
+			answer, err = reader.ReadString('\n')
+		}
+		if errors.Is(err, io.EOF) {
+			eof = true
+			_, _ = fmt.Fprintln(out)
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("failed to read user input: %w", err)
+		}
+
+		answer = strings.TrimRight(answer, "\r\n")
+		if answer == "" {
+			continue
+		}
+		kv.Value = answer
+		result[i] = parser.QuoteValue(kv)
+		answered = append(answered, kv.Key)
+	}
+	return result, answered, nil
+}
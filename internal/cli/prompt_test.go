@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPromptEnvFile(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/app/.env.example"] = "# Service\nAPI_KEY=***\nDB_URL=your_database_url_here\nREGION=\nNAME=demo\nPORT=3000\n"
+
+	secretReads := 0
+	opts := PromptOptions{
+		Values: map[string]string{"REGION": "eu-west-1"},
+		ReadSecret: func() (string, error) {
+			secretReads++
+			return "sk_live_123", nil
+		},
+	}
+	var out bytes.Buffer
+	// DB_URL gets a value with a space; REGION was set with --set and is not asked.
+	in := strings.NewReader("postgres://db/app?x=a b\n")
+	if err := PromptEnvFile("/app/.env.example", opts, fs, in, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "# Service\nAPI_KEY=sk_live_123\nDB_URL=\"postgres://db/app?x=a b\"\nREGION=eu-west-1\nNAME=demo\nPORT=3000\n"
+	if got := fs.files["/app/.env"]; got != want {
+		t.Errorf("generated .env = %q, want %q", got, want)
+	}
+	if secretReads != 1 {
+		t.Errorf("ReadSecret called %d times, want 1 (for API_KEY)", secretReads)
+	}
+	for _, s := range []string{"API_KEY [***] (hidden): ", "DB_URL [your_database_url_here]: ", "Answered: API_KEY, DB_URL"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("output should contain %q:\n%s", s, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "REGION") || strings.Contains(out.String(), "NAME") {
+		t.Errorf("keys already set or with real values should not be asked:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "sk_live_123") {
+		t.Error("secrets must not be echoed")
+	}
+}
+
+func TestPromptEnvFileDefaults(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/app/.env.example"] = "API_KEY=***\nTOKEN=***\n"
+
+	// Without a terminal, secrets are read from the input; Enter and the end
+	// of the input keep the example value.
+	var out bytes.Buffer
+	if err := PromptEnvFile("/app/.env.example", PromptOptions{}, fs, strings.NewReader("\n"), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fs.files["/app/.env"]; got != "API_KEY=***\nTOKEN=***\n" {
+		t.Errorf("generated .env = %q", got)
+	}
+
+	if err := PromptEnvFile("/app/.env.example", PromptOptions{}, fs, strings.NewReader(""), &out); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("an existing .env should not be overwritten before asking, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/diff"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// RemaskOptions configures RemaskExamples.
+type RemaskOptions struct {
+	// Files are the example files to clean up. When empty, every example
+	// under Dir is used.
+	Files   []string
+	Dir     string
+	Masking detector.Masking
+	DryRun  bool
+	// Force writes without asking for confirmation.
+	Force        bool
+	CreateBackup bool
+	Settings     Settings
+}
+
+// RemaskExamples masks the values of example files that look like real
+// secrets, as generating the example from a .env would, leaving
+// placeholders and other values alone. The changes are shown as a unified
+// diff, with the leaked values partly hidden, and written once confirmed
+// on in, or straight away with opts.Force.
+func RemaskExamples(ctx context.Context, opts RemaskOptions, fs FileSystem, sc DirScanner, in io.Reader, out io.Writer) error {
+	files := opts.Files
+	if len(files) == 0 {
+		dir := opts.Dir
+		if dir == "" {
+			dir = "."
+		}
+		examples, err := sc.ScanExamples(ctx, dir)
+		if err != nil {
+			return fmt.Errorf("failed to scan for .env.example files: %w", err)
+		}
+		for _, file := range examples {
+			files = append(files, filepath.Join(dir, file))
+		}
+	}
+
+	var plans []filePlan
+	for _, path := range files {
+		entries, err := parseAndClose(path, fs)
+		if err != nil {
+			return err
+		}
+		masking, err := opts.Settings.fileMasking(path, opts.Masking)
+		if err != nil {
+			return err
+		}
+		if plan := planRemask(path, entries, masking); len(plan.changes) > 0 {
+			plans = append(plans, plan)
+		}
+	}
+	if len(plans) == 0 {
+		_, _ = fmt.Fprintf(out, "No real-looking secrets in %d example file(s)\n", len(files))
+		return nil
+	}
+
+	for _, plan := range plans {
+		name := filepath.ToSlash(plan.path)
+		_, _ = fmt.Fprint(out, diff.Unified(name, name, opts.Settings.leakedLines(plan.before, plan.changes), opts.Settings.maskedLines(plan.after)))
+	}
+	count := changeCount(plans)
+	if opts.DryRun {
+		_, _ = fmt.Fprintf(out, "Would mask %d value(s) in %d file(s)\n", count, len(plans))
+		return nil
+	}
+	if !opts.Force {
+		ok, err := askYesNo(bufio.NewReader(in), out, fmt.Sprintf("Mask %d value(s) in %d file(s)?", count, len(plans)), false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			_, _ = fmt.Fprintln(out, "No files were changed")
+			return nil
+		}
+	}
+
+	for _, plan := range plans {
+		createBackup, err := opts.Settings.fileBackup(plan.path, opts.CreateBackup)
+		if err != nil {
+			return err
+		}
+		var backupPath string
+		if createBackup {
+			if backupPath, err = opts.Settings.backupFile(plan.path, fs); err != nil {
+				return fmt.Errorf("failed to create backup: %w", err)
+			}
+			if backupPath != "" {
+				_, _ = fmt.Fprintf(out, "Backup created: %s\n", backupPath)
+			}
+		}
+		if err := opts.Settings.writeEntries(plan.path, fs, plan.after); err != nil {
+			return err
+		}
+		opts.Settings.recordWrite(history.OpRemask, plan.path, plan.before, plan.after, backupPath, out)
+		_, _ = fmt.Fprintf(out, "%s: masked %s\n", plan.path, strings.Join(plan.changes, ", "))
+	}
+	_, _ = fmt.Fprintf(out, "Masked %d value(s) in %d file(s). The old values are still in git history: rotate them.\n", count, len(plans))
+	return nil
+}
+
+// planRemask masks the values of entries that look like real secrets.
+// Placeholders are kept as they are, whatever their style.
+func planRemask(path string, entries []parser.Entry, masking detector.Masking) filePlan {
+	plan := filePlan{path: path, before: entries, after: make([]parser.Entry, len(entries))}
+	for i, entry := range entries {
+		plan.after[i] = entry
+		kv, ok := entry.(parser.KeyValue)
+		if !ok || detector.IsPlaceholder(kv.Value) {
+			continue
+		}
+		masked := generator.GenerateExampleWithStyle([]parser.Entry{kv}, masking)[0].(parser.KeyValue)
+		if masked.Value != kv.Value {
+			plan.after[i] = masked
+			plan.changes = append(plan.changes, kv.Key)
+		}
+	}
+	return plan
+}
+
+// leakedLines renders entries as file lines, showing the values of keys
+// only partly, enough to recognize them without repeating the secret.
+func (s Settings) leakedLines(entries []parser.Entry, keys []string) []string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		if kv, ok := entry.(parser.KeyValue); ok && slices.Contains(keys, kv.Key) {
+			kv.Value = detector.PartialPlaceholder(kv.Value, detector.DefaultVisible)
+			entry = kv
+		}
+		lines[i] = parser.EntryToStringWithOptions(entry, s.Write)
+	}
+	return lines
+}
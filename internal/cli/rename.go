@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/diff"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+)
+
+// RenameOptions configures RenamePrefix.
+type RenameOptions struct {
+	From string
+	To   string
+	// Files are the env files to rename keys in, along with their
+	// examples. When empty, every env file and example under Dir is used.
+	Files        []string
+	Dir          string
+	DryRun       bool
+	CreateBackup bool
+	Settings     Settings
+}
+
+// filePlan is the rewrite of one file by an operation spanning several.
+type filePlan struct {
+	path    string
+	before  []parser.Entry
+	after   []parser.Entry
+	changes []string // e.g. "OLD -> NEW" for each renamed key
+}
+
+// RenamePrefix renames every key starting with opts.From so that it starts
+// with opts.To instead, across the env files and examples in opts. Every
+// file is checked before any is written: a new name that is not a valid
+// key or that clashes with a key the file already has aborts the rename.
+// If a write fails, the files already written are put back, so either
+// every file is renamed or none is. With opts.DryRun the changes are
+// printed as a unified diff instead, with secret values masked.
+func RenamePrefix(ctx context.Context, opts RenameOptions, fs FileSystem, sc DirScanner, out io.Writer) error {
+	if opts.From == "" {
+		return fmt.Errorf("--from is required")
+	}
+	if opts.From == opts.To {
+		return fmt.Errorf("--from and --to are the same prefix %q", opts.From)
+	}
+
+	files, err := renameFiles(ctx, opts, fs, sc)
+	if err != nil {
+		return err
+	}
+
+	var plans []filePlan
+	var conflicts []string
+	for _, path := range files {
+		entries, err := parseAndClose(path, fs)
+		if err != nil {
+			return err
+		}
+		plan, clashes := planRename(path, entries, opts.From, opts.To)
+		conflicts = append(conflicts, clashes...)
+		if len(plan.changes) > 0 {
+			plans = append(plans, plan)
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("rename aborted, no files were changed:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+	if len(plans) == 0 {
+		_, _ = fmt.Fprintf(out, "No keys start with %s in %d file(s)\n", opts.From, len(files))
+		return nil
+	}
+
+	if opts.DryRun {
+		for _, plan := range plans {
+			name := filepath.ToSlash(plan.path)
+			_, _ = fmt.Fprint(out, diff.Unified(name, name, opts.Settings.maskedLines(plan.before), opts.Settings.maskedLines(plan.after)))
+		}
+		_, _ = fmt.Fprintf(out, "Would rename %d key(s) in %d file(s)\n", changeCount(plans), len(plans))
+		return nil
+	}
+
+	backups := make([]string, len(plans))
+	if opts.CreateBackup {
+		for i, plan := range plans {
+			backups[i], err = opts.Settings.backupFile(plan.path, fs)
+			if err != nil {
+				return fmt.Errorf("failed to create backup: %w", err)
+			}
+			if backups[i] != "" {
+				_, _ = fmt.Fprintf(out, "Backup created: %s\n", backups[i])
+			}
+		}
+	}
+
+	for i, plan := range plans {
+		if err := opts.Settings.writeEntries(plan.path, fs, plan.after); err != nil {
+			return opts.Settings.rollbackWrites(plans[:i+1], fs, err)
+		}
+	}
+
+	for i, plan := range plans {
+		opts.Settings.recordWrite(history.OpRename, plan.path, plan.before, plan.after, backups[i], out)
+		_, _ = fmt.Fprintf(out, "%s: %s\n", plan.path, strings.Join(plan.changes, ", "))
+	}
+	_, _ = fmt.Fprintf(out, "Renamed %d key(s) in %d file(s)\n", changeCount(plans), len(plans))
+	return nil
+}
+
+// renameFiles returns the files a rename applies to: opts.Files plus the
+// examples of each env file and the env file of each example that exist,
+// or every env file and example under opts.Dir.
+func renameFiles(ctx context.Context, opts RenameOptions, fs FileSystem, sc DirScanner) ([]string, error) {
+	var files []string
+	add := func(path string) {
+		if !slices.Contains(files, path) {
+			files = append(files, path)
+		}
+	}
+
+	if len(opts.Files) == 0 {
+		return scanAllFiles(ctx, opts.Dir, sc)
+	}
+
+	for _, file := range opts.Files {
+		if !fileExists(fs, file) {
+			return nil, fmt.Errorf("file %s does not exist", file)
+		}
+		add(file)
+		related := scanner.ExamplePaths(file)
+		if target := scanner.ExampleTarget(file); target != file {
+			related = []string{target}
+		}
+		for _, path := range related {
+			if fileExists(fs, path) {
+				add(path)
+			}
+		}
+	}
+	return files, nil
+}
+
+// scanAllFiles returns every env file and example under dir, relative to
+// the working directory.
+func scanAllFiles(ctx context.Context, dir string, sc DirScanner) ([]string, error) {
+	if dir == "" {
+		dir = "."
+	}
+	envFiles, err := sc.Scan(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %w", err)
+	}
+	exampleFiles, err := sc.ScanExamples(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for .env.example files: %w", err)
+	}
+	files := make([]string, 0, len(envFiles)+len(exampleFiles))
+	for _, file := range append(envFiles, exampleFiles...) {
+		files = append(files, filepath.Join(dir, file))
+	}
+	return files, nil
+}
+
+// planRename renames the keys of entries starting with from. It returns
+// the plan and a description of each new name that cannot be used.
+func planRename(path string, entries []parser.Entry, from, to string) (filePlan, []string) {
+	plan := filePlan{path: path, before: entries}
+	existing := make(map[string]bool)
+	for _, entry := range entries {
+		if kv, ok := entry.(parser.KeyValue); ok && !strings.HasPrefix(kv.Key, from) {
+			existing[kv.Key] = true
+		}
+	}
+
+	var conflicts []string
+	seen := make(map[string]bool)
+	plan.after = make([]parser.Entry, len(entries))
+	for i, entry := range entries {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok || !strings.HasPrefix(kv.Key, from) {
+			plan.after[i] = entry
+			continue
+		}
+		newKey := to + strings.TrimPrefix(kv.Key, from)
+		switch {
+		case !parser.IsValidKey(newKey):
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s would become %q, which is not a valid key", path, kv.Key, newKey))
+		case existing[newKey]:
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s would become %s, which already exists", path, kv.Key, newKey))
+		}
+		if !seen[kv.Key] {
+			seen[kv.Key] = true
+			plan.changes = append(plan.changes, kv.Key+" -> "+newKey)
+		}
+		kv.Key = newKey
+		plan.after[i] = kv
+	}
+	return plan, conflicts
+}
+
+// rollbackWrites puts back the original contents of the planned files
+// after writing one of them failed with err, so that an operation spanning
+// several files changes all of them or none.
+func (s Settings) rollbackWrites(plans []filePlan, fs FileSystem, err error) error {
+	var failed []string
+	for _, plan := range plans {
+		if restoreErr := s.writeEntries(plan.path, fs, plan.before); restoreErr != nil {
+			failed = append(failed, plan.path)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w (could not restore %s; use the backups to recover)", err, strings.Join(failed, ", "))
+	}
+	return fmt.Errorf("%w (all files were restored)", err)
+}
+
+// maskedLines renders entries as file lines, hiding secret values so a
+// dry run can be shown or logged safely.
+func (s Settings) maskedLines(entries []parser.Entry) []string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		if kv, ok := entry.(parser.KeyValue); ok && s.Rules.IsSecret(kv.Key, kv.Value) && !detector.IsPlaceholder(kv.Value) {
+			kv.Value = detector.GeneratePlaceholder(kv.Key, kv.Value)
+			entry = kv
+		}
+		lines[i] = parser.EntryToStringWithOptions(entry, s.Write)
+	}
+	return lines
+}
+
+func changeCount(plans []filePlan) int {
+	count := 0
+	for _, plan := range plans {
+		count += len(plan.changes)
+	}
+	return count
+}
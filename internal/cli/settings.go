@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// Settings are the choices, taken once from the config files and flags,
+// that shape every file a command writes. Commands that write files take
+// them alongside their own options; the zero value writes files as the
+// parser does by default.
+type Settings struct {
+	// Write controls how written values are quoted.
+	Write parser.WriteOptions
+	// Annotate records where each filled value of a generated .env came
+	// from, in a comment above its key that later runs update.
+	Annotate bool
+	// Deterministic leaves the day out of provenance comments and changelog
+	// blocks, and numbers backups instead of timestamping them, so that the
+	// same input always produces the same files.
+	Deterministic bool
+	// Dir returns the settings for the env file at a path, which a
+	// .dotenv-tui.yaml in its directory or above may change, typically
+	// config.Overrides.For. Commands handling many files, such as --yolo,
+	// --remask, --lint and --audit, then take the masking, backup and
+	// schema of each file from it rather than from their options. Nil uses
+	// the options each command is given.
+	Dir func(path string) (config.DirSettings, error)
+	// History is the log every write is recorded in; empty records none.
+	History string
+	// Rules are the project's extra secret detection rules, which decide
+	// the values dry runs hide.
+	Rules detector.Rules
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/shell"
+)
+
+// ExportShell prints a script that sets the keys of the env file at
+// inputPath as environment variables in sh, for use with eval. Keys that
+// are not valid variable names are listed in a trailing comment.
+func ExportShell(inputPath string, sh shell.Shell, fs FileSystem, out io.Writer) error {
+	entries, err := parseAndClose(inputPath, fs)
+	if err != nil {
+		return err
+	}
+	skipped, err := shell.Write(out, entries, sh)
+	if err != nil {
+		return fmt.Errorf("failed to write script: %w", err)
+	}
+	if len(skipped) > 0 {
+		_, _ = fmt.Fprintf(out, "# Skipped %d key(s) that are not valid variable names: %s\n", len(skipped), strings.Join(skipped, ", "))
+	}
+	return nil
+}
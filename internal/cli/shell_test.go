@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/shell"
+)
+
+func TestExportShell(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[".env"] = "# app\nGREETING=\"hello world\"\napp.port=3000\n"
+
+	var out strings.Builder
+	if err := ExportShell(".env", shell.Fish, fs, &out); err != nil {
+		t.Fatalf("ExportShell() error = %v", err)
+	}
+	want := "set -gx GREETING 'hello world';\n# Skipped 1 key(s) that are not valid variable names: app.port\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+
+	if err := ExportShell("missing.env", shell.Bash, fs, &out); err == nil {
+		t.Error("ExportShell() expected an error for a missing file")
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+)
+
+// largestFiles is how many files the stats list by key count.
+const largestFiles = 5
+
+// fileKeys is an env file and how many keys it defines.
+type fileKeys struct {
+	Path string `json:"path"`
+	Keys int    `json:"keys"`
+}
+
+// duplicateKeys lists the keys an env file defines more than once.
+type duplicateKeys struct {
+	Path string   `json:"path"`
+	Keys []string `json:"keys"`
+}
+
+// statsReport sums up the env files of a directory tree.
+type statsReport struct {
+	EnvFiles        int             `json:"env_files"`
+	ExampleFiles    int             `json:"example_files"`
+	Keys            int             `json:"keys"`
+	UniqueKeys      int             `json:"unique_keys"`
+	Secrets         int             `json:"secrets"`
+	SecretRatio     float64         `json:"secret_ratio"`
+	Duplicates      []duplicateKeys `json:"duplicates"`
+	MissingExamples []string        `json:"missing_examples"`
+	Largest         []fileKeys      `json:"largest"`
+}
+
+// ShowStats scans dir and prints statistics about its env files, in text
+// or JSON format: how many files and keys there are, the share of keys
+// holding secrets, keys defined twice in a file, env files without an
+// example and the files with the most keys. Secrets are told apart by
+// rules. Everything is computed locally; nothing is sent anywhere.
+func ShowStats(ctx context.Context, dir string, format string, rules detector.Rules, sc DirScanner, fs FileSystem, out io.Writer) error {
+	if dir == "" {
+		dir = "."
+	}
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unsupported format %q (use %s or %s)", format, FormatText, FormatJSON)
+	}
+
+	envFiles, err := sc.Scan(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+	exampleFiles, err := sc.ScanExamples(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan for .env.example files: %w", err)
+	}
+
+	report := statsReport{
+		EnvFiles:        len(envFiles),
+		ExampleFiles:    len(exampleFiles),
+		Duplicates:      []duplicateKeys{},
+		MissingExamples: []string{},
+	}
+	examples := make(map[string]bool, len(exampleFiles))
+	for _, f := range exampleFiles {
+		examples[f] = true
+	}
+	unique := make(map[string]bool)
+	var sizes []fileKeys
+	for _, file := range envFiles {
+		entries, err := parseAndClose(filepath.Join(dir, file), fs)
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool)
+		var dups []string
+		keys := 0
+		for _, e := range entries {
+			kv, ok := e.(parser.KeyValue)
+			if !ok {
+				continue
+			}
+			keys++
+			unique[kv.Key] = true
+			if rules.IsSecret(kv.Key, kv.Value) {
+				report.Secrets++
+			}
+			if seen[kv.Key] && !slices.Contains(dups, kv.Key) {
+				dups = append(dups, kv.Key)
+			}
+			seen[kv.Key] = true
+		}
+		report.Keys += keys
+		sizes = append(sizes, fileKeys{Path: file, Keys: keys})
+		if len(dups) > 0 {
+			report.Duplicates = append(report.Duplicates, duplicateKeys{Path: file, Keys: dups})
+		}
+		if !slices.ContainsFunc(scanner.ExamplePaths(file), func(p string) bool { return examples[p] }) {
+			report.MissingExamples = append(report.MissingExamples, file)
+		}
+	}
+	report.UniqueKeys = len(unique)
+	if report.Keys > 0 {
+		report.SecretRatio = float64(report.Secrets) / float64(report.Keys)
+	}
+	// Stable, so files with as many keys keep the scan's order.
+	slices.SortStableFunc(sizes, func(a, b fileKeys) int { return b.Keys - a.Keys })
+	report.Largest = append([]fileKeys{}, sizes[:min(len(sizes), largestFiles)]...)
+
+	if format == FormatJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		return nil
+	}
+	writeStatsText(report, out)
+	return nil
+}
+
+func writeStatsText(r statsReport, out io.Writer) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Env files:\t%d (%d example(s))\n", r.EnvFiles, r.ExampleFiles)
+	_, _ = fmt.Fprintf(tw, "Keys:\t%d (%d unique)\n", r.Keys, r.UniqueKeys)
+	_, _ = fmt.Fprintf(tw, "Secrets:\t%d (%.0f%% of keys)\n", r.Secrets, r.SecretRatio*100)
+	_, _ = fmt.Fprintf(tw, "Duplicate keys:\t%d file(s)\n", len(r.Duplicates))
+	_, _ = fmt.Fprintf(tw, "Missing examples:\t%d file(s)\n", len(r.MissingExamples))
+	_ = tw.Flush()
+
+	if len(r.Duplicates) > 0 {
+		_, _ = fmt.Fprintf(out, "\nDuplicate keys:\n")
+		for _, d := range r.Duplicates {
+			_, _ = fmt.Fprintf(out, "  %s: %s\n", d.Path, strings.Join(d.Keys, ", "))
+		}
+	}
+	if len(r.MissingExamples) > 0 {
+		_, _ = fmt.Fprintf(out, "\nEnv files without an example:\n")
+		for _, path := range r.MissingExamples {
+			_, _ = fmt.Fprintf(out, "  %s\n", path)
+		}
+	}
+	if len(r.Largest) > 0 {
+		_, _ = fmt.Fprintf(out, "\nLargest files:\n")
+		tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, f := range r.Largest {
+			_, _ = fmt.Fprintf(tw, "  %s\t%d key(s)\n", f.Path, f.Keys)
+		}
+		_ = tw.Flush()
+	}
+}
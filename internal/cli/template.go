@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/jellydn/dotenv-tui/internal/templates"
+)
+
+// ListTemplates prints the built-in .env.example templates as a table or
+// as a JSON array.
+func ListTemplates(format string, out io.Writer) error {
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unsupported format %q (use %s or %s)", format, FormatText, FormatJSON)
+	}
+	list, err := templates.List()
+	if err != nil {
+		return err
+	}
+
+	if format == FormatJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, t := range list {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\n", t.Name, t.Description)
+	}
+	return tw.Flush()
+}
+
+// WriteTemplate prints the built-in .env.example template called name.
+func WriteTemplate(name string, out io.Writer) error {
+	content, err := templates.Get(name)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(content); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	return nil
+}
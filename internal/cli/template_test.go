@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/templates"
+)
+
+func TestListTemplates(t *testing.T) {
+	var out strings.Builder
+	if err := ListTemplates(FormatText, &out); err != nil {
+		t.Fatalf("ListTemplates() unexpected error: %v", err)
+	}
+	for _, want := range []string{"nextjs", "rails", "django", "laravel", "supabase", "stripe"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := ListTemplates(FormatJSON, &out); err != nil {
+		t.Fatalf("ListTemplates() unexpected error: %v", err)
+	}
+	var list []templates.Template
+	if err := json.Unmarshal([]byte(out.String()), &list); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(list) == 0 || list[0].Description == "" {
+		t.Errorf("list = %+v, want named and described templates", list)
+	}
+
+	if err := ListTemplates("xml", &strings.Builder{}); err == nil {
+		t.Error("ListTemplates() expected error for unsupported format")
+	}
+}
+
+func TestWriteTemplate(t *testing.T) {
+	var out strings.Builder
+	if err := WriteTemplate("stripe", &out); err != nil {
+		t.Fatalf("WriteTemplate() unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "STRIPE_SECRET_KEY=") {
+		t.Errorf("output = %q, want the stripe template", out.String())
+	}
+
+	if err := WriteTemplate("cobol", &strings.Builder{}); err == nil || !strings.Contains(err.Error(), "template list") {
+		t.Errorf("WriteTemplate() error = %v, want a pointer to template list", err)
+	}
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jellydn/dotenv-tui/internal/usage"
+)
+
+// ErrUsageFindings is returned by FindUnusedKeys when keys are unused or
+// undocumented, so callers can exit non-zero without printing an extra
+// error.
+var ErrUsageFindings = errors.New("found unused or undocumented keys")
+
+// unusedKey is a key defined in env files that no source file reads.
+type unusedKey struct {
+	Key   string   `json:"key"`
+	Files []string `json:"files"`
+}
+
+// undocumentedKey is a key source code reads that no example defines.
+type undocumentedKey struct {
+	Key        string            `json:"key"`
+	References []usage.Reference `json:"references"`
+}
+
+// usageReport is the result of FindUnusedKeys.
+type usageReport struct {
+	SourceFiles  int               `json:"source_files"`
+	Unused       []unusedKey       `json:"unused"`
+	Undocumented []undocumentedKey `json:"missing_from_example"`
+}
+
+// FindUnusedKeys cross-references the keys defined in dir's env and example
+// files with the keys its source code reads. It reports keys that are
+// defined but never read, and keys that are read but missing from every
+// example file, in text or JSON format.
+func FindUnusedKeys(ctx context.Context, dir string, format string, sc DirScanner, src SourceScanner, fs FileSystem, out io.Writer) error {
+	if dir == "" {
+		dir = "."
+	}
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unsupported format %q (use %s or %s)", format, FormatText, FormatJSON)
+	}
+
+	envFiles, err := sc.Scan(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+	exampleFiles, err := sc.ScanExamples(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan for .env.example files: %w", err)
+	}
+	sourceFiles, err := src.ScanSource(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to scan for source files: %w", err)
+	}
+
+	defined := make(map[string][]string)
+	documented := make(map[string]bool)
+	collect := func(files []string, example bool) error {
+		for _, file := range files {
+			entries, err := parseAndClose(filepath.Join(dir, file), fs)
+			if err != nil {
+				return err
+			}
+			for key := range keySet(entries) {
+				defined[key] = append(defined[key], file)
+				if example {
+					documented[key] = true
+				}
+			}
+		}
+		return nil
+	}
+	if err := collect(envFiles, false); err != nil {
+		return err
+	}
+	if err := collect(exampleFiles, true); err != nil {
+		return err
+	}
+
+	used := make(map[string][]usage.Reference)
+	for _, file := range sourceFiles {
+		// Reading every source file of a large tree takes a while.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		refs, err := findReferences(filepath.Join(dir, file), file, fs)
+		if err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			used[ref.Key] = append(used[ref.Key], ref)
+		}
+	}
+
+	report := usageReport{SourceFiles: len(sourceFiles), Unused: []unusedKey{}, Undocumented: []undocumentedKey{}}
+	for _, key := range slices.Sorted(maps.Keys(defined)) {
+		if len(used[key]) == 0 {
+			report.Unused = append(report.Unused, unusedKey{Key: key, Files: defined[key]})
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(used)) {
+		if !documented[key] {
+			report.Undocumented = append(report.Undocumented, undocumentedKey{Key: key, References: used[key]})
+		}
+	}
+
+	if format == FormatJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+	} else {
+		writeUsageText(report, out)
+	}
+
+	if len(report.Unused) > 0 || len(report.Undocumented) > 0 {
+		return ErrUsageFindings
+	}
+	return nil
+}
+
+func writeUsageText(report usageReport, out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Scanned %d source file(s): %d unused key(s), %d key(s) missing from .env.example\n",
+		report.SourceFiles, len(report.Unused), len(report.Undocumented))
+
+	if len(report.Unused) > 0 {
+		_, _ = fmt.Fprintln(out, "\nDefined but never used:")
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, k := range report.Unused {
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\n", k.Key, strings.Join(k.Files, ", "))
+		}
+		_ = tw.Flush()
+	}
+	if len(report.Undocumented) > 0 {
+		_, _ = fmt.Fprintln(out, "\nUsed but missing from .env.example:")
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, k := range report.Undocumented {
+			ref := k.References[0]
+			location := fmt.Sprintf("%s:%d", ref.File, ref.Line)
+			if more := len(k.References) - 1; more > 0 {
+				location += fmt.Sprintf(" (+%d more)", more)
+			}
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\n", k.Key, location)
+		}
+		_ = tw.Flush()
+	}
+}
+
+// findReferences returns the key references in the source file at path,
+// reported under the name file.
+func findReferences(path, file string, fs FileSystem) ([]usage.Reference, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+	return usage.Find(file, f)
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type mockSourceScanner struct {
+	files []string
+	err   error
+}
+
+func (m mockSourceScanner) ScanSource(_ context.Context, _ string) ([]string, error) {
+	return m.files, m.err
+}
+
+func TestFindUnusedKeys(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files[filepath.Join("proj", ".env")] = "DATABASE_URL=postgres://localhost/app\nLEGACY_FLAG=1\n"
+	fs.files[filepath.Join("proj", ".env.example")] = "DATABASE_URL=\n"
+	fs.files[filepath.Join("proj", "src", "db.ts")] = "connect(process.env.DATABASE_URL)\n"
+	fs.files[filepath.Join("proj", "cmd", "main.go")] = "port := os.Getenv(\"PORT\")\n\nhost := os.Getenv(\"PORT\")\n"
+
+	sc := &mockDirScanner{scanFiles: []string{".env"}, exampleFiles: []string{".env.example"}}
+	src := mockSourceScanner{files: []string{filepath.Join("src", "db.ts"), filepath.Join("cmd", "main.go")}}
+
+	t.Run("text", func(t *testing.T) {
+		var out strings.Builder
+		err := FindUnusedKeys(t.Context(), "proj", FormatText, sc, src, fs, &out)
+		if !errors.Is(err, ErrUsageFindings) {
+			t.Errorf("FindUnusedKeys() error = %v, want ErrUsageFindings", err)
+		}
+		for _, want := range []string{
+			"Scanned 2 source file(s): 1 unused key(s), 1 key(s) missing from .env.example",
+			"Defined but never used:",
+			"LEGACY_FLAG",
+			"Used but missing from .env.example:",
+			"PORT  " + filepath.Join("cmd", "main.go") + ":1 (+1 more)",
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+		if strings.Contains(out.String(), "DATABASE_URL") {
+			t.Errorf("DATABASE_URL is used and documented, should not be reported:\n%s", out.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out strings.Builder
+		_ = FindUnusedKeys(t.Context(), "proj", FormatJSON, sc, src, fs, &out)
+		var report usageReport
+		if err := json.Unmarshal([]byte(out.String()), &report); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+		}
+		if len(report.Unused) != 1 || report.Unused[0].Key != "LEGACY_FLAG" || report.Unused[0].Files[0] != ".env" {
+			t.Errorf("unused = %+v", report.Unused)
+		}
+		if len(report.Undocumented) != 1 || len(report.Undocumented[0].References) != 2 {
+			t.Errorf("missing_from_example = %+v", report.Undocumented)
+		}
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		var out strings.Builder
+		clean := &mockDirScanner{exampleFiles: []string{".env.example"}}
+		if err := FindUnusedKeys(t.Context(), "proj", FormatText, clean, mockSourceScanner{files: []string{filepath.Join("src", "db.ts")}}, fs, &out); err != nil {
+			t.Errorf("FindUnusedKeys() unexpected error: %v\n%s", err, out.String())
+		}
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		if err := FindUnusedKeys(t.Context(), "proj", FormatText, sc, mockSourceScanner{err: errors.New("boom")}, fs, &strings.Builder{}); err == nil || errors.Is(err, ErrUsageFindings) {
+			t.Errorf("FindUnusedKeys() error = %v, want scan failure", err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// Why explains whether key, as set in the env file at inputPath, is treated
+// as a secret, why, and what the generated .env.example would hold for it.
+// The last definition of a key repeated in the file is the one explained.
+func Why(inputPath, key string, masking detector.Masking, settings Settings, fs FileSystem, out io.Writer) error {
+	entries, err := parseAndClose(inputPath, fs)
+	if err != nil {
+		return err
+	}
+	var kv parser.KeyValue
+	found := false
+	for _, e := range entries {
+		if v, ok := e.(parser.KeyValue); ok && v.Key == key {
+			kv, found = v, true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is not set in %s", key, inputPath)
+	}
+
+	c := masking.Rules.Classify(kv.Key, kv.Value)
+	verdict := "not a secret"
+	if c.IsSecret {
+		verdict = "secret"
+	}
+	_, _ = fmt.Fprintf(out, "%s in %s: %s (%s confidence)\n", key, inputPath, verdict, c.Confidence)
+	_, _ = fmt.Fprintf(out, "  %s\n", c.Reason)
+
+	example := generator.GenerateExampleWithStyle([]parser.Entry{kv}, masking)[0].(parser.KeyValue)
+	switch {
+	case c.IsSecret && masking.KeepsKey(key):
+		_, _ = fmt.Fprintf(out, "  kept as is in .env.example: key is listed in mask.ignore_keys\n")
+	case example.Value == kv.Value:
+		_, _ = fmt.Fprintf(out, "  kept as is in .env.example\n")
+	default:
+		_, _ = fmt.Fprintf(out, "  written to .env.example as %s\n", parser.EntryToStringWithOptions(example, settings.Write))
+	}
+	return nil
+}
@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+)
+
+// Outcomes of processing a single example file in yolo mode.
+const (
+	StatusGenerated = "generated"
+	StatusSkipped   = "skipped"
+	StatusFailed    = "failed"
+)
+
+// FileResult records what yolo mode did with one example file.
+type FileResult struct {
+	Example  string
+	Target   string
+	Status   string
+	Backup   string   // backup of the previous target, if one was taken
+	Reason   string   // why the file was skipped or failed
+	Answered []string // keys filled from the answers file
+	FromEnv  []string // keys filled from the environment
+}
+
+// Progress receives a result each time yolo mode finishes a file.
+type Progress interface {
+	Update(done, total int, result FileResult)
+	Finish()
+}
+
+// plainProgress prints one progress line per file, suitable for logs and
+// pipes where the output cannot be redrawn in place.
+type plainProgress struct {
+	out io.Writer
+}
+
+// NewPlainProgress returns a Progress that writes a line per file to out.
+func NewPlainProgress(out io.Writer) Progress {
+	return plainProgress{out: out}
+}
+
+func (p plainProgress) Update(done, total int, r FileResult) {
+	_, _ = fmt.Fprintf(p.out, "%s %d/%d %s %s\n", ProgressBar(done, total, 20), done, total, r.Status, r.Target)
+}
+
+func (plainProgress) Finish() {}
+
+// ProgressBar renders a fixed-width ASCII bar such as "[#####-----]".
+func ProgressBar(done, total, width int) string {
+	filled := width
+	if total > 0 {
+		filled = done * width / total
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+// yoloTask is an example file together with the decision made for it before
+// any file is written.
+type yoloTask struct {
+	example string
+	target  string
+	skip    bool
+}
+
+// YoloOptions configures GenerateAllEnvFilesWithOptions.
+type YoloOptions struct {
+	Force        bool
+	CreateBackup bool
+	DryRun       bool
+	// Format selects the dry-run output: FormatText previews each file,
+	// FormatJSON prints a Plan. Empty means FormatText.
+	Format string
+	// Progress receives per-file results; nil prints a plain line per file.
+	Progress Progress
+	// Answers supplies values for keys in the generated files, replacing
+	// whatever the example holds, e.g. loaded with LoadAnswers.
+	Answers map[string]string
+	// Env, when set, fills keys not covered by Answers from the environment,
+	// typically os.LookupEnv for --from-env.
+	Env      LookupFunc
+	Settings Settings
+}
+
+// fill applies answers and then environment values to entries.
+func (o YoloOptions) fill(entries []parser.Entry) (filled []parser.Entry, answered, fromEnv []string) {
+	return o.Settings.fillAnswers(entries, o.Answers, "--answers", o.Env)
+}
+
+// GenerateAllEnvFilesWithOptions is GenerateAllEnvFiles with extra options.
+// Overwrite prompts are all asked before the first file is written, so
+// progress can be rendered without interleaving input. Answering "q" keeps
+// the remaining files; files already accepted are still generated. Once ctx
+// is done no further file is started, and ctx's error is returned after the
+// summary of the files written so far.
+func GenerateAllEnvFilesWithOptions(ctx context.Context, opts YoloOptions, fs FileSystem, sc DirScanner, in io.Reader, out io.Writer) error {
+	progress := opts.Progress
+	if progress == nil {
+		progress = NewPlainProgress(out)
+	}
+
+	exampleFiles, err := sc.ScanExamples(ctx, ".")
+	if err != nil {
+		return fmt.Errorf("failed to scan for .env.example files: %w", err)
+	}
+
+	if len(exampleFiles) == 0 {
+		return fmt.Errorf("no .env.example files found")
+	}
+
+	if opts.DryRun && opts.Format == FormatJSON {
+		return planYolo(exampleFiles, opts, fs, out)
+	}
+
+	_, _ = fmt.Fprintf(out, "Found %d .env.example file(s):\n", len(exampleFiles))
+	for _, file := range exampleFiles {
+		_, _ = fmt.Fprintf(out, "  %s\n", file)
+	}
+
+	if opts.DryRun {
+		_, _ = fmt.Fprintln(out, "\n[DRY RUN MODE - No files will be written]")
+		for _, exampleFile := range exampleFiles {
+			outputPath := scanner.ExampleTarget(exampleFile)
+			entries, err := parseAndClose(exampleFile, fs)
+			if err != nil {
+				return err
+			}
+			entries, _, _ = opts.fill(entries)
+			if err := opts.Settings.previewOutput(outputPath, entries, fs, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	reader := bufio.NewReader(in)
+	overwriteAll, quit := opts.Force, false
+	tasks := make([]yoloTask, 0, len(exampleFiles))
+	for _, exampleFile := range exampleFiles {
+		task := yoloTask{example: exampleFile, target: scanner.ExampleTarget(exampleFile)}
+		if !overwriteAll && fileExists(fs, task.target) {
+			if quit {
+				task.skip = true
+			} else {
+				answer, err := promptOverwrite(task, fs, reader, out)
+				if err != nil {
+					return err
+				}
+				switch answer {
+				case answerAll:
+					overwriteAll = true
+				case answerQuit:
+					quit = true
+					task.skip = true
+				case answerNo:
+					task.skip = true
+				}
+			}
+		}
+		tasks = append(tasks, task)
+	}
+
+	results := make([]FileResult, 0, len(tasks))
+	for i, task := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		result := runYoloTask(task, opts, fs)
+		results = append(results, result)
+		progress.Update(i+1, len(tasks), result)
+	}
+	progress.Finish()
+
+	if err := writeYoloSummary(results, out); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// Answers to the per-file overwrite prompt.
+type overwriteAnswer int
+
+const (
+	answerYes  overwriteAnswer = iota
+	answerNo                   // keep this file
+	answerAll                  // overwrite this and every remaining file
+	answerQuit                 // keep this and every remaining file
+)
+
+const overwriteHelp = `y - overwrite this file
+n - keep the existing file
+d - show which keys differ, then ask again
+a - overwrite this and all remaining files
+q - keep this and all remaining files
+`
+
+// promptOverwrite asks what to do with an existing target, modelled on
+// git add -p. "d" prints a key-level diff and asks again; an empty answer
+// keeps the file.
+func promptOverwrite(task yoloTask, fs FileSystem, reader *bufio.Reader, out io.Writer) (overwriteAnswer, error) {
+	for {
+		_, _ = fmt.Fprintf(out, "%s already exists. Overwrite? [y,n,d,a,q,?] ", task.target)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return answerNo, fmt.Errorf("failed to read user input: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(response)) {
+		case "y", "yes":
+			return answerYes, nil
+		case "", "n", "no":
+			return answerNo, nil
+		case "a":
+			return answerAll, nil
+		case "q":
+			return answerQuit, nil
+		case "d":
+			if err := writeKeyDiff(task, fs, out); err != nil {
+				_, _ = fmt.Fprintf(out, "Cannot show diff: %v\n", err)
+			}
+		default:
+			_, _ = fmt.Fprint(out, overwriteHelp)
+		}
+	}
+}
+
+// writeKeyDiff prints which keys overwriting task.target would add, remove
+// or change. Values are never printed, since the target may hold secrets.
+func writeKeyDiff(task yoloTask, fs FileSystem, out io.Writer) error {
+	current, err := parseAndClose(task.target, fs)
+	if err != nil {
+		return err
+	}
+	incoming, err := parseAndClose(task.example, fs)
+	if err != nil {
+		return err
+	}
+
+	lines := keyDiff(current, incoming)
+	if len(lines) == 0 {
+		_, _ = fmt.Fprintln(out, "  (no key differences)")
+		return nil
+	}
+	for _, line := range lines {
+		_, _ = fmt.Fprintf(out, "  %s\n", line)
+	}
+	return nil
+}
+
+// keyDiff compares the keys of two entry lists and returns one line per
+// difference: "+ KEY" for added, "- KEY" for removed, "~ KEY" for changed.
+func keyDiff(current, incoming []parser.Entry) []string {
+	var lines []string
+	for _, c := range keyChanges(current, incoming) {
+		switch c.Change {
+		case ChangeAdded:
+			lines = append(lines, "+ "+c.Key+" (new)")
+		case ChangeChanged:
+			lines = append(lines, "~ "+c.Key+" (value replaced)")
+		case ChangeRemoved:
+			lines = append(lines, "- "+c.Key+" (removed)")
+		}
+	}
+	return lines
+}
+
+// runYoloTask generates the target of a single task.
+func runYoloTask(task yoloTask, opts YoloOptions, fs FileSystem) FileResult {
+	result := FileResult{Example: task.example, Target: task.target}
+	if task.skip {
+		result.Status, result.Reason = StatusSkipped, "kept existing file"
+		return result
+	}
+
+	entries, err := parseAndClose(task.example, fs)
+	if err == nil {
+		entries, result.Answered, result.FromEnv = opts.fill(entries)
+		var createBackup bool
+		if createBackup, err = opts.Settings.fileBackup(task.target, opts.CreateBackup); err == nil {
+			result.Backup, err = opts.Settings.writeExample(task.target, entries, createBackup, fs)
+		}
+	}
+	if err != nil {
+		result.Status, result.Reason = StatusFailed, err.Error()
+		return result
+	}
+
+	result.Status = StatusGenerated
+	return result
+}
+
+// writeExample writes entries to target, first backing up any existing file
+// when createBackup is set, and records the write in the history. It returns
+// the backup path, if one was created.
+func (s Settings) writeExample(target string, entries []parser.Entry, createBackup bool, fs FileSystem) (string, error) {
+	before := existingEntries(target, fs)
+	var backupPath string
+	if createBackup {
+		path, err := s.backupFile(target, fs)
+		if err != nil {
+			return "", fmt.Errorf("failed to create backup: %w", err)
+		}
+		backupPath = path
+	}
+
+	if err := s.writeEntries(target, fs, entries); err != nil {
+		return backupPath, err
+	}
+	s.recordWrite(history.OpGenerateEnv, target, before, entries, backupPath, nil)
+	return backupPath, nil
+}
+
+// writeYoloSummary prints an aligned table of every result followed by the
+// totals line, and returns an error if any file failed.
+func writeYoloSummary(results []FileResult, out io.Writer) error {
+	var generated, skipped, backedUp, failed int
+
+	_, _ = fmt.Fprintln(out)
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "STATUS\tFILE\tDETAILS")
+	for _, r := range results {
+		details := r.Reason
+		switch r.Status {
+		case StatusGenerated:
+			generated++
+			var parts []string
+			if r.Backup != "" {
+				backedUp++
+				parts = append(parts, "backup: "+r.Backup)
+			}
+			if len(r.Answered) > 0 {
+				parts = append(parts, fmt.Sprintf("%d answered", len(r.Answered)))
+			}
+			if len(r.FromEnv) > 0 {
+				parts = append(parts, "from env: "+strings.Join(r.FromEnv, " "))
+			}
+			details = strings.Join(parts, ", ")
+		case StatusSkipped:
+			skipped++
+		case StatusFailed:
+			failed++
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Status, r.Target, details)
+	}
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintf(out, "\nDone: %d generated, %d skipped", generated, skipped)
+	if backedUp > 0 {
+		_, _ = fmt.Fprintf(out, ", %d backed up", backedUp)
+	}
+	if failed > 0 {
+		_, _ = fmt.Fprintf(out, ", %d failed\n", failed)
+		return fmt.Errorf("%d file(s) failed to generate", failed)
+	}
+	_, _ = fmt.Fprintln(out)
+	return nil
+}
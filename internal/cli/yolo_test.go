@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type recordingProgress struct {
+	updates  []string
+	finished bool
+}
+
+func (p *recordingProgress) Update(_, _ int, r FileResult) {
+	p.updates = append(p.updates, r.Status+":"+r.Target)
+}
+
+func (p *recordingProgress) Finish() {
+	p.finished = true
+}
+
+func TestGenerateAllEnvFilesSummary(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/a/.env.example"] = "KEY=value\n"
+	fs.files["/b/.env.example"] = "KEY=value\n"
+	fs.files["/b/.env"] = "KEY=old\n"
+	fs.files["/c/.env.example"] = "KEY=value\n"
+	fs.files["/c/.env"] = "KEY=old\n"
+	sc := &mockDirScanner{exampleFiles: []string{"/a/.env.example", "/b/.env.example", "/c/.env.example"}}
+
+	var out bytes.Buffer
+	progress := &recordingProgress{}
+	err := GenerateAllEnvFilesWithOptions(t.Context(), YoloOptions{Progress: progress}, fs, sc, strings.NewReader("y\nn\n"), &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"generated:/a/.env", "generated:/b/.env", "skipped:/c/.env"}
+	if strings.Join(progress.updates, ",") != strings.Join(expected, ",") {
+		t.Errorf("progress updates = %v, expected %v", progress.updates, expected)
+	}
+	if !progress.finished {
+		t.Error("progress was not finished")
+	}
+	if fs.files["/b/.env"] != "KEY=value\n" || fs.files["/c/.env"] != "KEY=old\n" {
+		t.Errorf("prompt answers not applied: %v", fs.files)
+	}
+
+	output := out.String()
+	for _, want := range []string{"STATUS", "kept existing file", "Done: 2 generated, 1 skipped"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	// Both prompts must be answered before the first progress update.
+	if strings.Index(output, "Overwrite?") > strings.Index(output, "STATUS") {
+		t.Errorf("prompts should precede the summary:\n%s", output)
+	}
+}
+
+func TestGenerateAllEnvFilesReportsFailures(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/a/.env.example"] = "KEY=value\n"
+	sc := &mockDirScanner{exampleFiles: []string{"/a/.env.example", "/missing/.env.example"}}
+
+	var out bytes.Buffer
+	err := GenerateAllEnvFiles(t.Context(), true, true, false, fs, sc, strings.NewReader(""), &out)
+	if err == nil || !strings.Contains(err.Error(), "1 file(s) failed") {
+		t.Fatalf("expected failure error, got %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{"[##########----------] 1/2 generated /a/.env", "2/2 failed /missing/.env", "Done: 1 generated, 0 skipped, 1 failed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if fs.files["/a/.env"] != "KEY=value\n" {
+		t.Error("successful file should still be generated after a failure")
+	}
+}
+
+func TestGenerateAllEnvFilesReportsBackups(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/a/.env.example"] = "KEY=value\n"
+	fs.files["/a/.env"] = "KEY=old\n"
+	sc := &mockDirScanner{exampleFiles: []string{"/a/.env.example"}}
+
+	var out bytes.Buffer
+	if err := GenerateAllEnvFiles(t.Context(), true, true, false, fs, sc, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "backup: /a/.env.bak.") || !strings.Contains(out.String(), "1 backed up") {
+		t.Errorf("summary should list the backup:\n%s", out.String())
+	}
+}
+
+// cancelingProgress cancels the run once the first file is done.
+type cancelingProgress struct {
+	recordingProgress
+	cancel context.CancelFunc
+}
+
+func (p *cancelingProgress) Update(done, total int, r FileResult) {
+	p.recordingProgress.Update(done, total, r)
+	p.cancel()
+}
+
+func TestGenerateAllEnvFilesCanceled(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/a/.env.example"] = "KEY=value\n"
+	fs.files["/b/.env.example"] = "KEY=value\n"
+	sc := &mockDirScanner{exampleFiles: []string{"/a/.env.example", "/b/.env.example"}}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	progress := &cancelingProgress{cancel: cancel}
+	var out bytes.Buffer
+	err := GenerateAllEnvFilesWithOptions(ctx, YoloOptions{Force: true, Progress: progress}, fs, sc, strings.NewReader(""), &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if _, ok := fs.files["/b/.env"]; ok {
+		t.Error("no file should be started after cancellation")
+	}
+	if !progress.finished || !strings.Contains(out.String(), "Done: 1 generated") {
+		t.Errorf("summary should cover the files written before cancellation:\n%s", out.String())
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		done, total int
+		expected    string
+	}{
+		{0, 4, "[--------]"},
+		{2, 4, "[####----]"},
+		{4, 4, "[########]"},
+		{0, 0, "[########]"},
+	}
+	for _, tt := range tests {
+		if got := ProgressBar(tt.done, tt.total, 8); got != tt.expected {
+			t.Errorf("ProgressBar(%d, %d) = %q, expected %q", tt.done, tt.total, got, tt.expected)
+		}
+	}
+}
+
+func TestGenerateAllEnvFilesPromptAnswers(t *testing.T) {
+	examples := []string{"/a/.env.example", "/b/.env.example", "/c/.env.example"}
+
+	tests := []struct {
+		name        string
+		input       string
+		wantWritten []bool
+		wantOutput  []string
+	}{
+		{"all applies to the rest", "n\na\n", []bool{false, true, true}, nil},
+		{"quit keeps the rest", "y\nq\n", []bool{true, false, false}, nil},
+		{"empty answer keeps the file", "\n\n\n", []bool{false, false, false}, nil},
+		{"diff then decide", "d\ny\nn\nn\n", []bool{true, false, false}, []string{"+ NEW (new)", "~ KEY (value replaced)", "- OLD (removed)"}},
+		{"unknown answer shows help", "x\ny\ny\ny\n", []bool{true, true, true}, []string{"d - show which keys differ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newMockFileSystem()
+			for _, example := range examples {
+				fs.files[example] = "KEY=value\nNEW=1\n"
+				fs.files[strings.TrimSuffix(example, ".example")] = "KEY=old\nOLD=1\n"
+			}
+			sc := &mockDirScanner{exampleFiles: examples}
+
+			var out bytes.Buffer
+			if err := GenerateAllEnvFilesWithOptions(t.Context(), YoloOptions{Progress: &recordingProgress{}}, fs, sc, strings.NewReader(tt.input), &out); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for i, example := range examples {
+				target := strings.TrimSuffix(example, ".example")
+				written := fs.files[target] == "KEY=value\nNEW=1\n"
+				if written != tt.wantWritten[i] {
+					t.Errorf("%s written = %v, expected %v", target, written, tt.wantWritten[i])
+				}
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out.String())
+				}
+			}
+			if strings.Contains(out.String(), "=old") {
+				t.Error("diff must not print values")
+			}
+		})
+	}
+}
+
+func TestGenerateAllEnvFilesPromptEOF(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/a/.env.example"] = "KEY=value\n"
+	fs.files["/a/.env"] = "KEY=old\n"
+	sc := &mockDirScanner{exampleFiles: []string{"/a/.env.example"}}
+
+	err := GenerateAllEnvFiles(t.Context(), false, false, false, fs, sc, strings.NewReader(""), &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "failed to read user input") {
+		t.Errorf("expected input error, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+// Package config loads dotenv-tui settings from layered sources: built-in
+// defaults, the user config file, the project's .dotenv-tui.yaml and
+// DOTENV_TUI_* environment variables, in increasing order of precedence.
+// Command-line flags are applied on top by the caller.
+package config
+
+import (
+	"fmt"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/format"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/keymap"
+	"github.com/jellydn/dotenv-tui/internal/lint"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+// Config holds every setting that can be configured. Each layer only
+// overrides the settings it mentions; lists from a later layer replace
+// earlier ones, while keymap and colors entries are merged by name.
+type Config struct {
+	// Backup creates a backup before overwriting a file.
+	Backup bool `yaml:"backup"`
+	// BackupDir collects backups in one directory instead of next to each
+	// file. Empty keeps them next to each file.
+	BackupDir string `yaml:"backup_dir"`
+	// BackupCompress gzips new backups.
+	BackupCompress bool `yaml:"backup_compress"`
+	// Annotate writes a comment above each value filled in a generated .env
+	// recording where it came from.
+	Annotate bool `yaml:"annotate"`
+	// Schema is the .env.schema describing the env files, which lint and
+	// the form check values against. Empty uses the .env.schema next to
+	// each file.
+	Schema string `yaml:"schema"`
+	// QuotePolicy selects how unquoted values are quoted whenever a file
+	// is written: preserve, always or when-needed.
+	QuotePolicy string              `yaml:"quote_policy"`
+	Example     Example             `yaml:"example"`
+	Mask        Mask                `yaml:"mask"`
+	Format      Format              `yaml:"format"`
+	Scan        Scan                `yaml:"scan"`
+	Theme       string              `yaml:"theme"`
+	Colors      map[string]string   `yaml:"colors"`
+	Keymap      map[string][]string `yaml:"keymap"`
+	Secrets     Secrets             `yaml:"secrets"`
+	Lint        Lint                `yaml:"lint"`
+	Form        Form                `yaml:"form"`
+	Update      Update              `yaml:"update"`
+}
+
+// Example configures generated .env.example files.
+type Example struct {
+	Style         string `yaml:"style"`
+	Visible       int    `yaml:"visible"`
+	Sort          string `yaml:"sort"`
+	GroupByPrefix bool   `yaml:"group_by_prefix"`
+	Changelog     bool   `yaml:"changelog"`
+}
+
+// Mask configures which values generated examples mask.
+type Mask struct {
+	// IgnoreKeys are key patterns, such as PUBLIC_KEY or NEXT_PUBLIC_*,
+	// whose values are copied to examples unmasked even when they look
+	// secret.
+	IgnoreKeys []string `yaml:"ignore_keys"`
+}
+
+// Format configures "dotenv-tui fmt".
+type Format struct {
+	// Quotes is the quote style: preserve, minimal or double.
+	Quotes string `yaml:"quotes"`
+	// Sort orders keys alphabetically within each section.
+	Sort bool `yaml:"sort"`
+}
+
+// Scan configures how directories are searched for .env files.
+type Scan struct {
+	// Root is the directory the TUI picker scans; empty means the working
+	// directory.
+	Root           string   `yaml:"root"`
+	Exclude        []string `yaml:"exclude"`
+	MaxDepth       int      `yaml:"max_depth"`
+	FollowSymlinks bool     `yaml:"follow_symlinks"`
+	OneFilesystem  bool     `yaml:"one_file_system"`
+	ExtendedNames  bool     `yaml:"extended_names"`
+}
+
+// Secrets extends the built-in secret detection.
+type Secrets struct {
+	// Keys are extra key substrings, or globs over the whole key such as
+	// *_SIGNATURE, that mark a value as secret.
+	Keys []string `yaml:"keys"`
+	// Ignore lists key names, or globs such as *_PUBLIC_KEY, that are
+	// never treated as secret.
+	Ignore []string `yaml:"ignore"`
+	// Templates build example placeholders that keep the safe parts of
+	// structured values.
+	Templates []SecretTemplate `yaml:"templates"`
+}
+
+// SecretTemplate is a placeholder template, as in detector.ParseTemplate.
+type SecretTemplate struct {
+	// Match is a regular expression whose named groups capture the parts
+	// of a value to keep.
+	Match string `yaml:"match"`
+	// Placeholder is written instead of matching values, with {name}
+	// replaced by the named group.
+	Placeholder string `yaml:"placeholder"`
+}
+
+// Lint configures --lint and the lint findings shown in the TUI.
+type Lint struct {
+	MaxLineLength int `yaml:"max_line_length"`
+	// Rules sets the severity of individual rules by name, e.g.
+	// "key-naming: error" or "line-length: off".
+	Rules map[string]string `yaml:"rules"`
+}
+
+// Form configures the form that fills in a .env from its example.
+type Form struct {
+	// Preview shows a read-only summary of each example before its form.
+	Preview bool `yaml:"preview"`
+}
+
+// Update configures the startup check for new releases.
+type Update struct {
+	Check bool `yaml:"check"`
+}
+
+// Default returns the built-in settings.
+func Default() Config {
+	return Config{
+		Backup:      true,
+		QuotePolicy: string(parser.QuotePreserve),
+		Example: Example{
+			Style:   string(detector.StyleMask),
+			Visible: detector.DefaultVisible,
+			Sort:    string(generator.SortNone),
+		},
+		Format: Format{Quotes: string(format.QuotePreserve)},
+		Theme:  theme.Auto,
+		Lint:   Lint{MaxLineLength: lint.DefaultMaxLineLength},
+		Update: Update{Check: true},
+	}
+}
+
+// KeyMap returns the default keymap with the configured overrides applied.
+func (c Config) KeyMap() (keymap.KeyMap, error) {
+	km := keymap.Default()
+	if err := km.Apply(c.Keymap); err != nil {
+		return keymap.Default(), fmt.Errorf("invalid keymap: %w", err)
+	}
+	return km, nil
+}
+
+// ResolveTheme resolves the configured theme and applies the color
+// overrides.
+func (c Config) ResolveTheme() (theme.Theme, error) {
+	t, err := theme.Resolve(c.Theme)
+	if err != nil {
+		return t, err
+	}
+	if err := t.Apply(c.Colors); err != nil {
+		return theme.Default(), fmt.Errorf("invalid colors: %w", err)
+	}
+	return t, nil
+}
+
+// ScanOptions returns the configured scan options.
+func (c Config) ScanOptions() (scanner.Options, error) {
+	if c.Scan.MaxDepth < 0 {
+		return scanner.Options{}, fmt.Errorf("invalid scan.max_depth %d: must not be negative", c.Scan.MaxDepth)
+	}
+	return scanner.Options{
+		Exclude:        c.Scan.Exclude,
+		MaxDepth:       c.Scan.MaxDepth,
+		FollowSymlinks: c.Scan.FollowSymlinks,
+		OneFilesystem:  c.Scan.OneFilesystem,
+		ExtendedNames:  c.Scan.ExtendedNames,
+	}, nil
+}
+
+// ExampleOptions returns the configured options for generating examples,
+// with the secret detection rules of SecretRules in their masking.
+func (c Config) ExampleOptions() (generator.Options, error) {
+	style, err := detector.ParsePlaceholderStyle(c.Example.Style)
+	if err != nil {
+		return generator.Options{}, err
+	}
+	if c.Example.Visible < 1 {
+		return generator.Options{}, fmt.Errorf("invalid example.visible %d: must be at least 1", c.Example.Visible)
+	}
+	order, err := generator.ParseSortOrder(c.Example.Sort)
+	if err != nil {
+		return generator.Options{}, err
+	}
+	for _, pattern := range c.Mask.IgnoreKeys {
+		if err := detector.CheckKeyPattern(pattern); err != nil {
+			return generator.Options{}, fmt.Errorf("invalid mask.ignore_keys: %w", err)
+		}
+	}
+	rules, err := c.SecretRules()
+	if err != nil {
+		return generator.Options{}, err
+	}
+	return generator.Options{
+		Masking:       detector.Masking{Style: style, Visible: c.Example.Visible, Unmasked: c.Mask.IgnoreKeys, Rules: rules},
+		Sort:          order,
+		GroupByPrefix: c.Example.GroupByPrefix,
+		Changelog:     c.Example.Changelog,
+	}, nil
+}
+
+// FormatOptions returns the configured options for "dotenv-tui fmt".
+func (c Config) FormatOptions() (format.Options, error) {
+	quotes, err := format.ParseQuoteStyle(c.Format.Quotes)
+	if err != nil {
+		return format.Options{}, err
+	}
+	return format.Options{Quotes: quotes, Sort: c.Format.Sort}, nil
+}
+
+// WriteOptions returns the configured options for writing env files.
+func (c Config) WriteOptions() (parser.WriteOptions, error) {
+	policy, err := parser.ParseQuotePolicy(c.QuotePolicy)
+	if err != nil {
+		return parser.WriteOptions{}, err
+	}
+	return parser.WriteOptions{QuotePolicy: policy}, nil
+}
+
+// LintOptions returns the configured lint options.
+func (c Config) LintOptions() (lint.Options, error) {
+	if c.Lint.MaxLineLength < 1 {
+		return lint.Options{}, fmt.Errorf("invalid lint.max_line_length %d: must be at least 1", c.Lint.MaxLineLength)
+	}
+	return lint.ParseOptions(c.Lint.Rules, c.Lint.MaxLineLength)
+}
+
+// SecretRules returns the configured secret detection rules.
+func (c Config) SecretRules() (detector.Rules, error) {
+	for _, list := range []struct {
+		name     string
+		patterns []string
+	}{{"secrets.keys", c.Secrets.Keys}, {"secrets.ignore", c.Secrets.Ignore}} {
+		for _, pattern := range list.patterns {
+			if err := detector.CheckKeyPattern(pattern); err != nil {
+				return detector.Rules{}, fmt.Errorf("invalid %s: %w", list.name, err)
+			}
+		}
+	}
+	rules := detector.Rules{SecretKeys: c.Secrets.Keys, PublicKeys: c.Secrets.Ignore}
+	for i, t := range c.Secrets.Templates {
+		tmpl, err := detector.ParseTemplate(t.Match, t.Placeholder)
+		if err != nil {
+			return detector.Rules{}, fmt.Errorf("invalid secrets.templates[%d]: %w", i, err)
+		}
+		rules.Templates = append(rules.Templates, tmpl)
+	}
+	return rules, nil
+}
+
+// Starter is the commented .dotenv-tui.yaml written by "dotenv-tui init".
+// Every setting is shown at its default.
+const Starter = `# dotenv-tui project settings. Uncomment a line to change it; see
+# https://github.com/jellydn/dotenv-tui#configuration for every option.
+
+# backup: true               # back up files before overwriting them
+# backup_dir: ""             # e.g. .dotenv-tui/backups; empty keeps backups beside each file
+# backup_compress: false     # gzip backups
+# annotate: false            # note where each filled .env value came from
+# schema: ""                 # e.g. ../shared/.env.schema; empty uses the .env.schema beside each file
+# quote_policy: preserve     # preserve, always or when-needed (quote values with spaces or #)
+
+# example:
+#   style: mask              # mask, descriptive or partial
+#   visible: 4               # trailing characters kept by the partial style
+#   sort: none               # keys or none
+#   group_by_prefix: false
+#   changelog: false         # list keys added and removed at the top when regenerating
+
+# mask:
+#   ignore_keys: []          # keys never masked in examples, e.g. PUBLIC_KEY or NEXT_PUBLIC_*
+
+# format:                    # dotenv-tui fmt
+#   quotes: preserve         # preserve, minimal or double
+#   sort: false              # sort keys within each blank-line separated section
+
+# scan:
+#   root: .                  # directory the TUI picker scans
+#   exclude: []              # gitignore-style patterns to skip
+#   max_depth: 0             # 0 for unlimited
+#   extended_names: false    # also match app.env, env.local, ...
+
+# secrets:
+#   keys: []                 # extra key substrings or globs (*_SIG) treated as secret
+#   ignore: []               # keys or globs (*_PUBLIC_KEY) never treated as secret
+#   templates: []            # placeholders keeping the safe parts of values (match, placeholder)
+
+# lint:
+#   max_line_length: 120
+#   rules: {}                # severities by rule, e.g. key-naming: error or line-length: off
+
+# form:
+#   preview: false           # summarize each example before filling in its .env
+
+# theme: auto                # auto, dark, light, high-contrast, none
+
+# update:
+#   check: true
+`
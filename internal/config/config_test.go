@@ -0,0 +1,355 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/format"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/lint"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func noEnv(string) (string, bool) { return "", false }
+
+func TestLoadFromMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := LoadFrom([]string{filepath.Join(dir, "user.yaml"), filepath.Join(dir, "project.yaml")}, noEnv)
+	if err != nil {
+		t.Fatalf("LoadFrom() unexpected error: %v", err)
+	}
+	if !cfg.Backup || !cfg.Update.Check || cfg.Theme != theme.Auto {
+		t.Errorf("LoadFrom(missing) = %+v, want defaults", cfg)
+	}
+
+	km, err := cfg.KeyMap()
+	if err != nil || km.Menu.Quit.Help().Key != "q" {
+		t.Errorf("KeyMap() = %v, %v; want default keymap", km.Menu.Quit.Help(), err)
+	}
+	opts, err := cfg.ScanOptions()
+	if err != nil || opts.MaxDepth != 0 || opts.ExtendedNames {
+		t.Errorf("ScanOptions() = %+v, %v; want zero options", opts, err)
+	}
+	example, err := cfg.ExampleOptions()
+	if err != nil || example.Masking.Style != detector.StyleMask || example.Sort != generator.SortNone {
+		t.Errorf("ExampleOptions() = %+v, %v; want mask style in original order", example, err)
+	}
+	formatOpts, err := cfg.FormatOptions()
+	if err != nil || formatOpts.Quotes != format.QuotePreserve || formatOpts.Sort {
+		t.Errorf("FormatOptions() = %+v, %v; want quotes preserved, unsorted", formatOpts, err)
+	}
+	writeOpts, err := cfg.WriteOptions()
+	if err != nil || writeOpts.QuotePolicy != parser.QuotePreserve {
+		t.Errorf("WriteOptions() = %+v, %v; want quotes preserved", writeOpts, err)
+	}
+	cfg.QuotePolicy = "sometimes"
+	if _, err := cfg.WriteOptions(); err == nil {
+		t.Error("WriteOptions() should reject an unknown quote policy")
+	}
+}
+
+func TestLoadFromLayers(t *testing.T) {
+	dir := t.TempDir()
+	user := writeConfig(t, dir, "user.yaml", `backup: false
+theme: light
+keymap:
+  menu.quit: ["x"]
+example:
+  style: partial
+  visible: 6
+scan:
+  exclude: [vendor/]
+`)
+	project := writeConfig(t, dir, "project.yaml", `keymap:
+  form.save: ["ctrl+w"]
+example:
+  sort: keys
+scan:
+  exclude: [fixtures/]
+  max_depth: 3
+`)
+	env := map[string]string{EnvTheme: "dark", EnvNoUpdateCheck: "1"}
+	lookup := func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+
+	cfg, err := LoadFrom([]string{user, project}, lookup)
+	if err != nil {
+		t.Fatalf("LoadFrom() unexpected error: %v", err)
+	}
+
+	if cfg.Backup {
+		t.Error("backup: false from the user config should survive the project config")
+	}
+	if cfg.Theme != "dark" {
+		t.Errorf("Theme = %q, want the environment to win", cfg.Theme)
+	}
+	if cfg.Update.Check {
+		t.Errorf("%s should disable the update check", EnvNoUpdateCheck)
+	}
+	if got := strings.Join(cfg.Scan.Exclude, ","); got != "fixtures/" {
+		t.Errorf("Scan.Exclude = %q, want the project list to replace the user list", got)
+	}
+
+	km, err := cfg.KeyMap()
+	if err != nil {
+		t.Fatalf("KeyMap() unexpected error: %v", err)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}, km.Menu.Quit) {
+		t.Error("menu.quit from the user config should be kept")
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlW}, km.Form.Save) {
+		t.Error("form.save from the project config should be merged in")
+	}
+
+	example, err := cfg.ExampleOptions()
+	if err != nil {
+		t.Fatalf("ExampleOptions() unexpected error: %v", err)
+	}
+	want := generator.Options{Masking: detector.Masking{Style: detector.StylePartial, Visible: 6}, Sort: generator.SortKeys}
+	if !reflect.DeepEqual(example, want) {
+		t.Errorf("ExampleOptions() = %+v, want %+v", example, want)
+	}
+}
+
+func TestLoadFromInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	good := writeConfig(t, dir, "user.yaml", "keymap:\n  menu.quit: [\"x\"]\n")
+	bad := writeConfig(t, dir, "project.yaml", "keymap: [\n")
+
+	cfg, err := LoadFrom([]string{good, bad}, noEnv)
+	if err == nil || !strings.Contains(err.Error(), bad) {
+		t.Fatalf("LoadFrom() error = %v, want it to name %s", err, bad)
+	}
+	if got := cfg.Keymap["menu.quit"]; len(got) != 1 || got[0] != "x" {
+		t.Errorf("Keymap = %v, want the layers before the bad file", cfg.Keymap)
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	env := map[string]string{
+		EnvBackup:         "false",
+		EnvBackupDir:      ".dotenv-tui/backups",
+		EnvBackupCompress: "true",
+		EnvMaskStyle:      "descriptive",
+		EnvScanExclude:    "tmp/, build/ ,",
+		EnvMaxDepth:       "2",
+	}
+	lookup := func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+
+	cfg, err := LoadFrom(nil, lookup)
+	if err != nil {
+		t.Fatalf("LoadFrom() unexpected error: %v", err)
+	}
+	if cfg.Backup || cfg.BackupDir != ".dotenv-tui/backups" || !cfg.BackupCompress || cfg.Example.Style != "descriptive" || cfg.Scan.MaxDepth != 2 {
+		t.Errorf("LoadFrom() = %+v, want environment overrides", cfg)
+	}
+	if got := strings.Join(cfg.Scan.Exclude, ","); got != "tmp/,build/" {
+		t.Errorf("Scan.Exclude = %q, want tmp/,build/", got)
+	}
+
+	for _, value := range []string{"0", "false", "FALSE"} {
+		lookup := func(k string) (string, bool) { return value, k == EnvNoUpdateCheck }
+		cfg, err := LoadFrom(nil, lookup)
+		if err != nil {
+			t.Fatalf("LoadFrom() with %s=%q unexpected error: %v", EnvNoUpdateCheck, value, err)
+		}
+		if !cfg.Update.Check {
+			t.Errorf("%s=%q should leave the update check on", EnvNoUpdateCheck, value)
+		}
+	}
+
+	for name, value := range map[string]string{EnvBackup: "maybe", EnvBackupCompress: "yes please", EnvMaxDepth: "deep", EnvNoUpdateCheck: "please"} {
+		lookup := func(k string) (string, bool) { return value, k == name }
+		if _, err := LoadFrom(nil, lookup); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("LoadFrom() with %s=%q error = %v, want it to name the variable", name, value, err)
+		}
+	}
+}
+
+func TestInvalidSettings(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	cfg := Default()
+	cfg.Keymap = map[string][]string{"nope": {"x"}}
+	if _, err := cfg.KeyMap(); err == nil {
+		t.Error("KeyMap() expected error for unknown action")
+	}
+
+	cfg = Default()
+	cfg.Theme = "neon"
+	if _, err := cfg.ResolveTheme(); err == nil || !strings.Contains(err.Error(), "unknown theme") {
+		t.Errorf("ResolveTheme() error = %v, want unknown theme", err)
+	}
+
+	cfg = Default()
+	cfg.Scan.MaxDepth = -1
+	if _, err := cfg.ScanOptions(); err == nil {
+		t.Error("ScanOptions() expected error for negative max_depth")
+	}
+
+	cfg = Default()
+	cfg.Example.Visible = 0
+	if _, err := cfg.ExampleOptions(); err == nil {
+		t.Error("ExampleOptions() expected error for visible 0")
+	}
+
+	cfg = Default()
+	cfg.Mask.IgnoreKeys = []string{"NEXT_[PUBLIC"}
+	if _, err := cfg.ExampleOptions(); err == nil || !strings.Contains(err.Error(), "mask.ignore_keys") {
+		t.Errorf("ExampleOptions() error = %v, want invalid pattern", err)
+	}
+
+	cfg = Default()
+	cfg.Lint.Rules = map[string]string{"no-tabs": "error"}
+	if _, err := cfg.LintOptions(); err == nil || !strings.Contains(err.Error(), "no-tabs") {
+		t.Errorf("LintOptions() error = %v, want unknown rule", err)
+	}
+
+	cfg = Default()
+	cfg.Lint.MaxLineLength = 0
+	if _, err := cfg.LintOptions(); err == nil {
+		t.Error("LintOptions() expected error for max_line_length 0")
+	}
+
+	cfg = Default()
+	cfg.Secrets.Ignore = []string{"[NEXT_*"}
+	if _, err := cfg.SecretRules(); err == nil || !strings.Contains(err.Error(), "secrets.ignore") {
+		t.Errorf("SecretRules() error = %v, want invalid pattern", err)
+	}
+
+	cfg = Default()
+	cfg.Secrets.Templates = []SecretTemplate{{Match: `^(?P<host>.+)$`, Placeholder: "{user}@{host}"}}
+	if _, err := cfg.SecretRules(); err == nil || !strings.Contains(err.Error(), "secrets.templates[0]") {
+		t.Errorf("SecretRules() error = %v, want unknown group", err)
+	}
+}
+
+func TestSecretTemplatesFromFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), ProjectFileName, `secrets:
+  templates:
+    - match: '^postgres://(?P<user>[^:]+):[^@]+@(?P<host>[^/]+)/(?P<db>.+)$'
+      placeholder: 'postgres://{user}:***@{host}/{db}'
+`)
+	cfg, err := LoadFrom([]string{path}, noEnv)
+	if err != nil {
+		t.Fatalf("LoadFrom() unexpected error: %v", err)
+	}
+	rules, err := cfg.SecretRules()
+	if err != nil {
+		t.Fatalf("SecretRules() unexpected error: %v", err)
+	}
+	if len(rules.Templates) != 1 {
+		t.Errorf("SecretRules() templates = %d, want 1", len(rules.Templates))
+	}
+	example, err := cfg.ExampleOptions()
+	if err != nil {
+		t.Fatalf("ExampleOptions() unexpected error: %v", err)
+	}
+	if len(example.Masking.Rules.Templates) != 1 {
+		t.Errorf("ExampleOptions() templates = %d, want the secret rules in the masking", len(example.Masking.Rules.Templates))
+	}
+}
+
+func TestLintOptionsFromFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), ProjectFileName, "lint:\n  max_line_length: 80\n  rules:\n    key-naming: error\n    line-length: off\n")
+	cfg, err := LoadFrom([]string{path}, noEnv)
+	if err != nil {
+		t.Fatalf("LoadFrom() unexpected error: %v", err)
+	}
+	opts, err := cfg.LintOptions()
+	if err != nil {
+		t.Fatalf("LintOptions() unexpected error: %v", err)
+	}
+	want := map[lint.Rule]lint.Severity{lint.RuleKeyNaming: lint.SeverityError, lint.RuleLineLength: lint.SeverityOff}
+	if opts.MaxLineLength != 80 || !reflect.DeepEqual(opts.Severities, want) {
+		t.Errorf("LintOptions() = %+v, want max 80 and %v", opts, want)
+	}
+}
+
+func TestFormPreviewFromFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), ProjectFileName, "form:\n  preview: true\n")
+	cfg, err := LoadFrom([]string{path}, noEnv)
+	if err != nil {
+		t.Fatalf("LoadFrom() unexpected error: %v", err)
+	}
+	if !cfg.Form.Preview {
+		t.Errorf("Form.Preview = false, want true")
+	}
+}
+
+func TestResolveTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	cfg := Default()
+	cfg.Theme = theme.Light
+	cfg.Colors = map[string]string{"error": "#FF0000"}
+	th, err := cfg.ResolveTheme()
+	if err != nil {
+		t.Fatalf("ResolveTheme() unexpected error: %v", err)
+	}
+	if th.Name != theme.Light || th.Error != lipgloss.Color("#FF0000") {
+		t.Errorf("ResolveTheme() = %q with error color %v, want light with override", th.Name, th.Error)
+	}
+}
+
+func TestUserPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join("xdg", "config"))
+	path, err := UserPath()
+	if err != nil || path != filepath.Join("xdg", "config", "dotenv-tui", "config.yaml") {
+		t.Errorf("UserPath() = %q, %v", path, err)
+	}
+}
+
+func TestStarterMatchesDefaults(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, ProjectFileName, Starter)
+
+	cfg, err := LoadFrom([]string{path}, noEnv)
+	if err != nil {
+		t.Fatalf("LoadFrom(Starter) unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("LoadFrom(Starter) = %+v, want defaults", cfg)
+	}
+
+	// Uncommenting every setting must still parse and keep the defaults.
+	var uncommented strings.Builder
+	for _, line := range strings.Split(Starter, "\n") {
+		if strings.HasPrefix(line, "# ") && strings.Contains(line, ":") && !strings.Contains(line, "://") {
+			line = strings.TrimPrefix(line, "# ")
+		}
+		uncommented.WriteString(line + "\n")
+	}
+	path = writeConfig(t, dir, "uncommented.yaml", uncommented.String())
+	cfg, err = LoadFrom([]string{path}, noEnv)
+	if err != nil {
+		t.Fatalf("LoadFrom(uncommented Starter) unexpected error: %v\n%s", err, uncommented.String())
+	}
+	if _, err := cfg.ExampleOptions(); err != nil {
+		t.Errorf("ExampleOptions() error = %v", err)
+	}
+	if _, err := cfg.LintOptions(); err != nil {
+		t.Errorf("LintOptions() error = %v", err)
+	}
+	if cfg.Backup != true || cfg.Example.Visible != 4 || cfg.Theme != theme.Auto {
+		t.Errorf("uncommented Starter = %+v, want defaults", cfg)
+	}
+}
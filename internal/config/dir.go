@@ -0,0 +1,186 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jellydn/dotenv-tui/internal/generator"
+)
+
+// DirSettings are the settings a .dotenv-tui.yaml in a subdirectory can
+// change for the env files under it.
+type DirSettings struct {
+	Example        generator.Options
+	Backup         bool
+	BackupDir      string
+	BackupCompress bool
+	// Schema is the schema file of the env files, or empty for the
+	// .env.schema next to each.
+	Schema string
+}
+
+// DirSettings returns the settings of c that apply per directory.
+func (c Config) DirSettings() (DirSettings, error) {
+	example, err := c.ExampleOptions()
+	if err != nil {
+		return DirSettings{}, err
+	}
+	return DirSettings{
+		Example:        example,
+		Backup:         c.Backup,
+		BackupDir:      c.BackupDir,
+		BackupCompress: c.BackupCompress,
+		Schema:         c.Schema,
+	}, nil
+}
+
+// Overrides finds the .dotenv-tui.yaml files in the subdirectories of a
+// project, so the services of a monorepo can mask, back up and validate
+// their env files their own way. The settings for a file are the project's,
+// overridden by each .dotenv-tui.yaml between the project root and the
+// file's directory, nearest last. Only the settings in DirSettings may be
+// set in those files; others, such as secrets, apply to the whole project
+// and are an error. The environment and command-line flags still override
+// them.
+type Overrides struct {
+	root   string
+	base   Config
+	lookup LookupFunc
+	flags  func(*Config)
+
+	mu    sync.Mutex
+	cache map[string]Config
+}
+
+// NewOverrides looks for nested config files under root, the directory
+// whose .dotenv-tui.yaml base was loaded from. lookup re-applies the
+// DOTENV_TUI_* variables and flags the command-line flags over each
+// directory's settings; either may be nil.
+func NewOverrides(root string, base Config, lookup LookupFunc, flags func(*Config)) (*Overrides, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+	return &Overrides{root: abs, base: base, lookup: lookup, flags: flags, cache: make(map[string]Config)}, nil
+}
+
+// For returns the settings for the env file at path. Files outside the
+// project get the project's settings.
+func (o *Overrides) For(path string) (DirSettings, error) {
+	cfg, err := o.config(filepath.Dir(path))
+	if err != nil {
+		return DirSettings{}, err
+	}
+	if o.lookup != nil {
+		if err := applyEnv(&cfg, o.lookup); err != nil {
+			return DirSettings{}, err
+		}
+	}
+	if o.flags != nil {
+		o.flags(&cfg)
+	}
+	return cfg.DirSettings()
+}
+
+// config returns the file settings of dir: the base, overridden by the
+// nested config files from the root down to dir.
+func (o *Overrides) config(dir string) (Config, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return o.base, nil
+	}
+	rel, err := filepath.Rel(o.root, abs)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return o.base, nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	cfg, cur := o.base, o.root
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		cur = filepath.Join(cur, part)
+		if cached, ok := o.cache[cur]; ok {
+			cfg = cached
+			continue
+		}
+		next, err := cfg.withDirFile(cur)
+		if err != nil {
+			return o.base, err
+		}
+		o.cache[cur] = next
+		cfg = next
+	}
+	return cfg, nil
+}
+
+// withDirFile returns c with the per-directory settings of the
+// .dotenv-tui.yaml in dir, if there is one. Relative backup_dir and schema
+// paths are taken from dir.
+func (c Config) withDirFile(dir string) (Config, error) {
+	path := filepath.Join(dir, ProjectFileName)
+	if _, err := os.Stat(path); err != nil {
+		return c, nil
+	}
+	if err := checkDirKeys(path); err != nil {
+		return c, err
+	}
+	next := c.clone()
+	next.BackupDir, next.Schema = "", ""
+	if err := loadFile(&next, path); err != nil {
+		return c, err
+	}
+
+	result := c
+	result.Example = next.Example
+	result.Mask = next.Mask
+	result.Backup = next.Backup
+	result.BackupCompress = next.BackupCompress
+	if next.BackupDir != "" {
+		result.BackupDir = relativeTo(dir, next.BackupDir)
+	}
+	if next.Schema != "" {
+		result.Schema = relativeTo(dir, next.Schema)
+	}
+	return result, nil
+}
+
+// dirKeys are the settings a nested .dotenv-tui.yaml may set.
+var dirKeys = []string{"example", "mask", "backup", "backup_dir", "backup_compress", "schema"}
+
+// checkDirKeys rejects the settings of the nested config file at path that
+// only the project's config file can set, rather than ignoring them.
+func checkDirKeys(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var settings map[string]any
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	var other []string
+	for key := range settings {
+		if !slices.Contains(dirKeys, key) {
+			other = append(other, key)
+		}
+	}
+	if len(other) == 0 {
+		return nil
+	}
+	slices.Sort(other)
+	return fmt.Errorf("%s: %s can only be set in the project's %s; a nested one may set %s",
+		path, strings.Join(other, ", "), ProjectFileName, strings.Join(dirKeys, ", "))
+}
+
+func relativeTo(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+)
+
+func TestOverridesFor(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "apps", "api")
+	web := filepath.Join(root, "apps", "web")
+	for _, dir := range []string{api, web} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(t, filepath.Join(root, "apps"), ProjectFileName, "backup: false\nmask:\n  ignore_keys: [PUBLIC_*]\n")
+	writeConfig(t, api, ProjectFileName, "example:\n  style: descriptive\nbackup_dir: backups\nschema: ../../shared/.env.schema\n")
+
+	base := Default()
+	base.Schema = "root.schema"
+	o, err := NewOverrides(root, base, noEnv, nil)
+	if err != nil {
+		t.Fatalf("NewOverrides() unexpected error: %v", err)
+	}
+
+	s, err := o.For(filepath.Join(api, ".env"))
+	if err != nil {
+		t.Fatalf("For() unexpected error: %v", err)
+	}
+	if s.Example.Masking.Style != detector.StyleDescriptive {
+		t.Errorf("Masking.Style = %v, want descriptive", s.Example.Masking.Style)
+	}
+	if s.Backup {
+		t.Error("Backup = true, want false from apps/.dotenv-tui.yaml")
+	}
+	if want := filepath.Join(api, "backups"); s.BackupDir != want {
+		t.Errorf("BackupDir = %q, want %q", s.BackupDir, want)
+	}
+	if want := filepath.Join(root, "shared", ".env.schema"); s.Schema != want {
+		t.Errorf("Schema = %q, want %q", s.Schema, want)
+	}
+	if !slices.Equal(s.Example.Masking.Unmasked, []string{"PUBLIC_*"}) {
+		t.Errorf("Masking.Unmasked = %v, want inherited from apps", s.Example.Masking.Unmasked)
+	}
+
+	s, err = o.For(filepath.Join(web, ".env"))
+	if err != nil {
+		t.Fatalf("For() unexpected error: %v", err)
+	}
+	if s.Example.Masking.Style != detector.StyleMask || s.Backup || s.Schema != "root.schema" {
+		t.Errorf("For(web) = %+v, want apps settings over the base", s)
+	}
+
+	s, err = o.For(filepath.Join(t.TempDir(), ".env"))
+	if err != nil {
+		t.Fatalf("For() unexpected error: %v", err)
+	}
+	if !s.Backup || s.Schema != "root.schema" {
+		t.Errorf("For(outside) = %+v, want the base settings", s)
+	}
+}
+
+func TestOverridesForFlagsAndEnvWin(t *testing.T) {
+	root := t.TempDir()
+	writeConfig(t, root, ProjectFileName, "backup: true\nexample:\n  style: descriptive\n")
+
+	env := func(name string) (string, bool) {
+		if name == EnvMaskStyle {
+			return "partial", true
+		}
+		return "", false
+	}
+	flags := func(cfg *Config) { cfg.Backup = false }
+	o, err := NewOverrides(filepath.Dir(root), Default(), env, flags)
+	if err != nil {
+		t.Fatalf("NewOverrides() unexpected error: %v", err)
+	}
+
+	s, err := o.For(filepath.Join(root, ".env"))
+	if err != nil {
+		t.Fatalf("For() unexpected error: %v", err)
+	}
+	if s.Backup {
+		t.Error("Backup = true, want the flag to win")
+	}
+	if s.Example.Masking.Style != detector.StylePartial {
+		t.Errorf("Masking.Style = %v, want partial from the environment", s.Example.Masking.Style)
+	}
+}
+
+func TestOverridesForInvalidFile(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "svc")
+	if err := os.Mkdir(sub, 0750); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(t, sub, ProjectFileName, "example: [\n")
+
+	o, err := NewOverrides(root, Default(), nil, nil)
+	if err != nil {
+		t.Fatalf("NewOverrides() unexpected error: %v", err)
+	}
+	if _, err := o.For(filepath.Join(sub, ".env")); err == nil {
+		t.Error("For() expected an error for an invalid nested config")
+	}
+}
+
+func TestOverridesForProjectOnlySettings(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "svc")
+	if err := os.Mkdir(sub, 0750); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(t, sub, ProjectFileName, "backup: false\nsecrets:\n  keys: [INTERNAL_*]\ntheme: dark\n")
+
+	o, err := NewOverrides(root, Default(), nil, nil)
+	if err != nil {
+		t.Fatalf("NewOverrides() unexpected error: %v", err)
+	}
+	_, err = o.For(filepath.Join(sub, ".env"))
+	if err == nil || !strings.Contains(err.Error(), "secrets, theme can only be set in the project's") {
+		t.Errorf("For() error = %v, want secrets and theme rejected rather than ignored", err)
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileName is the per-project config file, read from the directory
+// dotenv-tui runs in.
+const ProjectFileName = ".dotenv-tui.yaml"
+
+// LookupFunc reports the value of an environment variable, with the same
+// contract as os.LookupEnv.
+type LookupFunc func(key string) (string, bool)
+
+// UserPath returns the path of the user config file, honoring
+// $XDG_CONFIG_HOME and falling back to ~/.config.
+func UserPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "dotenv-tui", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "dotenv-tui", "config.yaml"), nil
+}
+
+// Load reads the user config, the project config in the working directory
+// and the environment. On error it returns the settings loaded so far.
+func Load() (Config, error) {
+	var paths []string
+	if path, err := UserPath(); err == nil {
+		paths = append(paths, path)
+	}
+	paths = append(paths, ProjectFileName)
+	return LoadFrom(paths, os.LookupEnv)
+}
+
+// LoadFrom layers the given YAML files over the defaults, in order, and
+// then the DOTENV_TUI_* variables known to lookup. Missing files are
+// skipped. A nil lookup skips the environment.
+func LoadFrom(paths []string, lookup LookupFunc) (Config, error) {
+	cfg := Default()
+	for _, path := range paths {
+		if err := loadFile(&cfg, path); err != nil {
+			return cfg, err
+		}
+	}
+	if lookup != nil {
+		if err := applyEnv(&cfg, lookup); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// loadFile decodes path over cfg, leaving settings it does not mention
+// untouched. cfg is unchanged if the file cannot be decoded.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	next := cfg.clone()
+	if err := yaml.Unmarshal(data, &next); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	*cfg = next
+	return nil
+}
+
+// clone copies c deeply enough that decoding into the copy, which merges
+// into existing maps, leaves c untouched.
+func (c Config) clone() Config {
+	c.Colors = maps.Clone(c.Colors)
+	c.Keymap = maps.Clone(c.Keymap)
+	c.Lint.Rules = maps.Clone(c.Lint.Rules)
+	return c
+}
+
+// Environment variables read by Load.
+const (
+	EnvBackup         = "DOTENV_TUI_BACKUP"
+	EnvBackupDir      = "DOTENV_TUI_BACKUP_DIR"
+	EnvBackupCompress = "DOTENV_TUI_BACKUP_COMPRESS"
+	EnvMaskStyle      = "DOTENV_TUI_MASK_STYLE"
+	EnvTheme          = "DOTENV_TUI_THEME"
+	EnvScanExclude    = "DOTENV_TUI_SCAN_EXCLUDE"
+	EnvMaxDepth       = "DOTENV_TUI_MAX_DEPTH"
+	EnvNoUpdateCheck  = "DOTENV_TUI_NO_UPDATE_CHECK"
+)
+
+// applyEnv overrides cfg with the DOTENV_TUI_* variables that are set.
+func applyEnv(cfg *Config, lookup LookupFunc) error {
+	if v, ok := lookup(EnvBackup); ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: must be true or false", EnvBackup, v)
+		}
+		cfg.Backup = b
+	}
+	if v, ok := lookup(EnvBackupDir); ok && v != "" {
+		cfg.BackupDir = v
+	}
+	if v, ok := lookup(EnvBackupCompress); ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: must be true or false", EnvBackupCompress, v)
+		}
+		cfg.BackupCompress = b
+	}
+	if v, ok := lookup(EnvMaskStyle); ok && v != "" {
+		cfg.Example.Style = v
+	}
+	if v, ok := lookup(EnvTheme); ok && v != "" {
+		cfg.Theme = v
+	}
+	if v, ok := lookup(EnvScanExclude); ok && v != "" {
+		cfg.Scan.Exclude = nil
+		for _, pattern := range strings.Split(v, ",") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				cfg.Scan.Exclude = append(cfg.Scan.Exclude, pattern)
+			}
+		}
+	}
+	if v, ok := lookup(EnvMaxDepth); ok && v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: must be a number", EnvMaxDepth, v)
+		}
+		cfg.Scan.MaxDepth = n
+	}
+	if v, ok := lookup(EnvNoUpdateCheck); ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: must be true or false", EnvNoUpdateCheck, v)
+		}
+		cfg.Update.Check = !b
+	}
+	return nil
+}
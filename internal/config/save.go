@@ -0,0 +1,100 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetValues sets dotted keys such as "example.style" in the YAML file at
+// path, creating the file if needed. Other settings and comments are kept.
+func SetValues(path string, values map[string]any) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// A file holding only comments, such as the Starter, decodes to an
+	// empty node that would drop them, so new settings go after the text.
+	var prefix []byte
+	if len(doc.Content) == 0 {
+		prefix = data
+		if len(prefix) > 0 && !bytes.HasSuffix(prefix, []byte("\n")) {
+			prefix = append(prefix, '\n')
+		}
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to update %s: top level is not a mapping", path)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var value yaml.Node
+		if err := value.Encode(values[name]); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		if err := setPath(root, strings.Split(name, "."), &value); err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.Write(prefix)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// setPath replaces the value at keys below mapping, creating intermediate
+// mappings as needed. Comments on a replaced value are carried over.
+func setPath(mapping *yaml.Node, keys []string, value *yaml.Node) error {
+	for i := 0; i < len(mapping.Content); i += 2 {
+		k, v := mapping.Content[i], mapping.Content[i+1]
+		if k.Value != keys[0] {
+			continue
+		}
+		if len(keys) == 1 {
+			value.HeadComment, value.LineComment, value.FootComment = v.HeadComment, v.LineComment, v.FootComment
+			mapping.Content[i+1] = value
+			return nil
+		}
+		if v.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a mapping", k.Value)
+		}
+		return setPath(v, keys[1:], value)
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: keys[0]}
+	if len(keys) == 1 {
+		mapping.Content = append(mapping.Content, key, value)
+		return nil
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content, key, child)
+	return setPath(child, keys[1:], value)
+}
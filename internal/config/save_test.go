@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetValues(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, ProjectFileName, `# team settings
+theme: dark # agreed in standup
+keymap:
+  menu.quit: ["x"]
+example:
+  # keep tokens recognizable
+  style: mask
+`)
+
+	err := SetValues(path, map[string]any{
+		"theme":         "light",
+		"example.style": "partial",
+		"example.sort":  "keys",
+		"backup":        false,
+		"scan.root":     "services",
+	})
+	if err != nil {
+		t.Fatalf("SetValues() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"# team settings", "theme: light # agreed in standup", "# keep tokens recognizable", "style: partial", "sort: keys", "backup: false", "root: services", "menu.quit:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("file missing %q:\n%s", want, got)
+		}
+	}
+
+	cfg, err := LoadFrom([]string{path}, noEnv)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if cfg.Theme != "light" || cfg.Example.Style != "partial" || cfg.Example.Sort != "keys" || cfg.Backup || cfg.Scan.Root != "services" {
+		t.Errorf("LoadFrom() = %+v, want the saved values", cfg)
+	}
+}
+
+func TestSetValuesKeepsStarterComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ProjectFileName)
+	if err := SetValues(path, map[string]any{"theme": "dark"}); err != nil {
+		t.Fatalf("SetValues() on a missing file error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "theme: dark\n" {
+		t.Errorf("new file = %q", data)
+	}
+
+	if err := os.WriteFile(path, []byte(Starter), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValues(path, map[string]any{"backup": false}); err != nil {
+		t.Fatalf("SetValues() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), Starter) || !strings.HasSuffix(string(data), "backup: false\n") {
+		t.Errorf("SetValues() should append to a comment-only file, got:\n%s", data)
+	}
+}
+
+func TestSetValuesRejectsNonMapping(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), ProjectFileName, "example: mask\n")
+	if err := SetValues(path, map[string]any{"example.style": "partial"}); err == nil {
+		t.Error("SetValues() expected error when a parent key is not a mapping")
+	}
+}
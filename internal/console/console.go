@@ -0,0 +1,2 @@
+// Package console prepares the terminal for dotenv-tui's colored output.
+package console
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package console
+
+// Setup is a no-op outside Windows, where terminals understand ANSI
+// escape sequences and UTF-8 by default.
+func Setup() {}
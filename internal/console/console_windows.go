@@ -0,0 +1,23 @@
+//go:build windows
+
+package console
+
+import "golang.org/x/sys/windows"
+
+// utf8CodePage is the Windows code page identifier for UTF-8.
+const utf8CodePage = 65001
+
+// Setup enables ANSI escape sequences on stdout and stderr and switches the
+// console to UTF-8, so colors and box-drawing characters are not printed as
+// raw escape codes or mojibake by older consoles such as conhost.
+// Redirected handles are left untouched.
+func Setup() {
+	for _, h := range []windows.Handle{windows.Stdout, windows.Stderr} {
+		var mode uint32
+		if err := windows.GetConsoleMode(h, &mode); err != nil {
+			continue
+		}
+		_ = windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+	}
+	_ = windows.SetConsoleOutputCP(utf8CodePage)
+}
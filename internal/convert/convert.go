@@ -0,0 +1,184 @@
+// Package convert converts between JSON, YAML and TOML configuration
+// documents and env values, with nested values flattened into keys such
+// as DATABASE__HOST.
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSeparator joins the keys of nested values.
+const DefaultSeparator = "__"
+
+// Options configures Values.
+type Options struct {
+	// Flatten expands nested objects and arrays into one key per value.
+	// Otherwise they are kept as JSON under their top-level key.
+	Flatten bool
+	// Separator joins nested keys; empty means DefaultSeparator.
+	Separator string
+}
+
+// Decode parses a configuration document, choosing the format from the
+// extension of name: .json, .yaml, .yml or .toml. The document must be an
+// object at the top level.
+func Decode(name string, data []byte) (map[string]any, error) {
+	var doc map[string]any
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&doc)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("unsupported file type %q (use .json, .yaml, .yml or .toml)", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(name), err)
+	}
+	return doc, nil
+}
+
+// Values returns the env keys and values of doc. Keys are upper-cased,
+// with characters that are not letters, digits or underscores replaced by
+// underscores; array elements are keyed by index. Null values become
+// empty. Two document keys mapping to the same env key
+// are an error.
+func Values(doc map[string]any, opts Options) (map[string]string, error) {
+	sep := opts.Separator
+	if sep == "" {
+		sep = DefaultSeparator
+	}
+	values := make(map[string]string)
+	var walk func(prefix string, v any) error
+	walk = func(prefix string, v any) error {
+		switch t := v.(type) {
+		case map[string]any:
+			if !opts.Flatten && prefix != "" {
+				break
+			}
+			for k, child := range t {
+				if err := walk(join(prefix, envKey(k), sep), child); err != nil {
+					return err
+				}
+			}
+			return nil
+		case []any:
+			if !opts.Flatten {
+				break
+			}
+			for i, child := range t {
+				if err := walk(join(prefix, strconv.Itoa(i), sep), child); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		value, err := scalar(v)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s: %w", prefix, err)
+		}
+		if _, dup := values[prefix]; dup {
+			return fmt.Errorf("more than one value maps to %s", prefix)
+		}
+		values[prefix] = value
+		return nil
+	}
+	if err := walk("", normalize(doc)); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Keys returns the keys of values, sorted.
+func Keys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var nonKeyChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// envKey upper-cases a document key for use in an env key.
+func envKey(k string) string {
+	return nonKeyChars.ReplaceAllString(strings.ToUpper(k), "_")
+}
+
+func join(prefix, key, sep string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + sep + key
+}
+
+// scalar formats a leaf value. Nested values reaching it, when not
+// flattened, are encoded as JSON.
+func scalar(v any) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case bool, int, int64, uint64, float64, json.Number:
+		return fmt.Sprint(t), nil
+	case time.Time:
+		return t.Format(time.RFC3339Nano), nil
+	case fmt.Stringer:
+		return t.String(), nil
+	default:
+		data, err := json.Marshal(normalize(t))
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// normalize converts the map[any]any values YAML can produce, which JSON
+// cannot encode, and TOML arrays of tables to plain arrays.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, child := range t {
+			m[fmt.Sprint(k)] = normalize(child)
+		}
+		return m
+	case map[string]any:
+		for k, child := range t {
+			t[k] = normalize(child)
+		}
+		return t
+	case []any:
+		for i, child := range t {
+			t[i] = normalize(child)
+		}
+		return t
+	case []map[string]any:
+		items := make([]any, len(t))
+		for i, item := range t {
+			items[i] = normalize(item)
+		}
+		return items
+	default:
+		return v
+	}
+}
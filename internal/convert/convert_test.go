@@ -0,0 +1,74 @@
+package convert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeAndValues(t *testing.T) {
+	docs := map[string]string{
+		"config.json": `{"database": {"host": "db", "port": 5432, "replicas": ["r1", "r2"]}, "debug": true, "api-key": null}`,
+		"config.yaml": "database:\n  host: db\n  port: 5432\n  replicas: [r1, r2]\ndebug: true\napi-key:\n",
+		"config.toml": "debug = true\napi-key = \"\"\n\n[database]\nhost = \"db\"\nport = 5432\nreplicas = [\"r1\", \"r2\"]\n",
+	}
+	want := map[string]string{
+		"DATABASE__HOST":        "db",
+		"DATABASE__PORT":        "5432",
+		"DATABASE__REPLICAS__0": "r1",
+		"DATABASE__REPLICAS__1": "r2",
+		"DEBUG":                 "true",
+		"API_KEY":               "",
+	}
+	for name, data := range docs {
+		t.Run(name, func(t *testing.T) {
+			doc, err := Decode(name, []byte(data))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			got, err := Values(doc, Options{Flatten: true})
+			if err != nil {
+				t.Fatalf("Values() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Values() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestValuesWithoutFlatten(t *testing.T) {
+	doc, err := Decode("app.toml", []byte("name = \"app\"\n\n[[servers]]\nhost = \"a\"\n\n[[servers]]\nhost = \"b\"\n"))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	got, err := Values(doc, Options{Separator: "_"})
+	if err != nil {
+		t.Fatalf("Values() error = %v", err)
+	}
+	want := map[string]string{"NAME": "app", "SERVERS": `[{"host":"a"},{"host":"b"}]`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+
+	got, _ = Values(doc, Options{Flatten: true, Separator: "_"})
+	if got["SERVERS_1_HOST"] != "b" {
+		t.Errorf("Values(flatten) = %v, want SERVERS_1_HOST=b", got)
+	}
+	if keys := Keys(got); !reflect.DeepEqual(keys, []string{"NAME", "SERVERS_0_HOST", "SERVERS_1_HOST"}) {
+		t.Errorf("Keys() = %v", keys)
+	}
+}
+
+func TestValuesErrors(t *testing.T) {
+	if _, err := Decode("app.ini", nil); err == nil || !strings.Contains(err.Error(), "unsupported file type") {
+		t.Errorf("Decode(ini) error = %v", err)
+	}
+	if _, err := Decode("app.json", []byte("[1, 2]")); err == nil {
+		t.Error("Decode() expected error for a top-level array")
+	}
+	doc, _ := Decode("app.json", []byte(`{"db": {"host": "a"}, "DB__HOST": "b"}`))
+	if _, err := Values(doc, Options{Flatten: true}); err == nil || !strings.Contains(err.Error(), "more than one value maps to DB__HOST") {
+		t.Errorf("Values() error = %v, want a collision", err)
+	}
+}
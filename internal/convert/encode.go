@@ -0,0 +1,108 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats Encode writes.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// Unflatten reverses the flattening of Values: keys are split on sep into
+// nested objects with lower-cased names, and objects whose keys are the
+// indexes 0 to n-1 become arrays. A key that is both a value and the
+// prefix of other keys is an error.
+func Unflatten(values map[string]string, sep string) (map[string]any, error) {
+	if sep == "" {
+		sep = DefaultSeparator
+	}
+	doc := make(map[string]any)
+	for _, key := range Keys(values) {
+		parts := strings.Split(strings.ToLower(key), sep)
+		node := doc
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				if _, ok := node[part].(map[string]any); ok {
+					return nil, fmt.Errorf("%s is both a value and a section", key)
+				}
+				node[part] = values[key]
+				break
+			}
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				if _, isValue := node[part]; isValue {
+					return nil, fmt.Errorf("%s is both a value and a section", strings.Join(parts[:i+1], sep))
+				}
+				child = make(map[string]any)
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return arrays(doc).(map[string]any), nil
+}
+
+// arrays converts, bottom-up, the objects of doc keyed 0 to n-1 into
+// arrays.
+func arrays(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for k, child := range m {
+		m[k] = arrays(child)
+	}
+	if len(m) == 0 {
+		return m
+	}
+	items := make([]any, len(m))
+	for k, child := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != k {
+			return m
+		}
+		items[i] = child
+	}
+	return items
+}
+
+// Encode writes doc in format, with keys sorted.
+func Encode(w io.Writer, format string, doc map[string]any) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(doc)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatTOML:
+		return toml.NewEncoder(w).Encode(doc)
+	default:
+		return fmt.Errorf("unsupported format %q (use %s, %s or %s)", format, FormatJSON, FormatYAML, FormatTOML)
+	}
+}
+
+// Flat returns values as a document with one entry per key.
+func Flat(values map[string]string) map[string]any {
+	doc := make(map[string]any, len(values))
+	for k, v := range values {
+		doc[k] = v
+	}
+	return doc
+}
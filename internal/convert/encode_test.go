@@ -0,0 +1,64 @@
+package convert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnflatten(t *testing.T) {
+	values := map[string]string{
+		"DATABASE__HOST":        "db",
+		"DATABASE__REPLICAS__0": "r1",
+		"DATABASE__REPLICAS__1": "r2",
+		"SERVERS__0__HOST":      "a",
+		"DEBUG":                 "true",
+	}
+	got, err := Unflatten(values, "")
+	if err != nil {
+		t.Fatalf("Unflatten() error = %v", err)
+	}
+	want := map[string]any{
+		"database": map[string]any{"host": "db", "replicas": []any{"r1", "r2"}},
+		"servers":  []any{map[string]any{"host": "a"}},
+		"debug":    "true",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unflatten() = %#v, want %#v", got, want)
+	}
+
+	// Unflatten reverses Values.
+	back, err := Values(got, Options{Flatten: true})
+	if err != nil || !reflect.DeepEqual(back, values) {
+		t.Errorf("Values(Unflatten()) = %v, %v, want %v", back, err, values)
+	}
+
+	if _, err := Unflatten(map[string]string{"DB": "x", "DB__HOST": "y"}, "__"); err == nil || !strings.Contains(err.Error(), "db is both a value and a section") {
+		t.Errorf("Unflatten() error = %v, want a conflict", err)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	doc := map[string]any{"name": "app", "db": map[string]any{"port": "5432"}, "tags": []any{"a", "b"}}
+	tests := map[string]string{
+		FormatJSON: "{\n  \"db\": {\n    \"port\": \"5432\"\n  },\n  \"name\": \"app\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}\n",
+		FormatYAML: "db:\n  port: \"5432\"\nname: app\ntags:\n  - a\n  - b\n",
+		FormatTOML: "name = \"app\"\ntags = [\"a\", \"b\"]\n\n[db]\n  port = \"5432\"\n",
+	}
+	for format, want := range tests {
+		var b strings.Builder
+		if err := Encode(&b, format, doc); err != nil {
+			t.Fatalf("Encode(%s) error = %v", format, err)
+		}
+		if b.String() != want {
+			t.Errorf("Encode(%s) =\n%s\nwant\n%s", format, b.String(), want)
+		}
+		decoded, err := Decode("out."+format, []byte(b.String()))
+		if err != nil || !reflect.DeepEqual(decoded, doc) {
+			t.Errorf("Decode(Encode(%s)) = %v, %v", format, decoded, err)
+		}
+	}
+	if err := Encode(&strings.Builder{}, "xml", doc); err == nil {
+		t.Error("Encode(xml) expected error")
+	}
+}
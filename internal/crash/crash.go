@@ -0,0 +1,145 @@
+// Package crash turns panics into a diagnostics file and a pointer to the
+// issue tracker, instead of a stack trace in a terminal left in raw mode.
+package crash
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IssueURL is where crashes are reported.
+const IssueURL = "https://github.com/jellydn/dotenv-tui/issues/new"
+
+// trailSize is how many of the latest TUI messages a report lists.
+const trailSize = 20
+
+var (
+	mu    sync.Mutex
+	trail []string
+)
+
+// Record adds an event, such as the type of a TUI message, to the trail
+// listed in reports, keeping the latest few. Events must not hold values
+// from env files.
+func Record(event string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if len(trail) == trailSize {
+		copy(trail, trail[1:])
+		trail = trail[:trailSize-1]
+	}
+	trail = append(trail, event)
+}
+
+// Report is what is known about a panic.
+type Report struct {
+	Time      time.Time
+	Version   string
+	Value     any
+	Stack     []byte
+	Args      []string
+	GoVersion string
+	OS        string
+	Arch      string
+	Trail     []string
+}
+
+// NewReport describes a panic with value in the given version of
+// dotenv-tui, recovered in the goroutine that panicked, so that stack is
+// where it happened.
+func NewReport(value any, stack []byte, version string) Report {
+	mu.Lock()
+	defer mu.Unlock()
+	return Report{
+		Time:      time.Now(),
+		Version:   version,
+		Value:     value,
+		Stack:     stack,
+		Args:      flagNames(os.Args[1:]),
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Trail:     append([]string(nil), trail...),
+	}
+}
+
+// flagNames keeps the flags of args without their values, which may be
+// secrets as in --set KEY=VALUE.
+func flagNames(args []string) []string {
+	var names []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			name, _, _ := strings.Cut(arg, "=")
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// WriteTo writes the report as text.
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "dotenv-tui crash report\n\n")
+	fmt.Fprintf(&b, "Time:    %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version: %s\n", r.Version)
+	fmt.Fprintf(&b, "Go:      %s %s/%s\n", r.GoVersion, r.OS, r.Arch)
+	fmt.Fprintf(&b, "Flags:   %s\n", strings.Join(r.Args, " "))
+	fmt.Fprintf(&b, "Panic:   %v\n", r.Value)
+	if len(r.Trail) > 0 {
+		fmt.Fprintf(&b, "\nLast messages, oldest first:\n")
+		for _, event := range r.Trail {
+			fmt.Fprintf(&b, "  %s\n", event)
+		}
+	}
+	if len(r.Stack) > 0 {
+		fmt.Fprintf(&b, "\nStack:\n%s", r.Stack)
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Write saves the report to a new file in the temporary directory and
+// returns its path.
+func (r Report) Write() (string, error) {
+	f, err := os.CreateTemp("", "dotenv-tui-crash-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create crash report: %w", err)
+	}
+	if _, err := r.WriteTo(f); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// Handle writes the report and tells the user on out where it is and
+// where to report the crash.
+func (r Report) Handle(out io.Writer) {
+	_, _ = fmt.Fprintf(out, "\ndotenv-tui crashed: %v\n", r.Value)
+	if path, err := r.Write(); err == nil {
+		_, _ = fmt.Fprintf(out, "Diagnostics were written to %s\n", path)
+		_, _ = fmt.Fprintf(out, "Please report this at %s and attach that file.\n", IssueURL)
+	} else {
+		_, _ = fmt.Fprintf(out, "Could not save diagnostics (%v); the stack was:\n\n%s\n", err, r.Stack)
+		_, _ = fmt.Fprintf(out, "Please report this at %s with the output above.\n", IssueURL)
+	}
+}
+
+// Recover is deferred at the top of main: on a panic, it writes a report
+// for version, points to it on stderr and exits with status 2, the status
+// Go uses for panics.
+func Recover(version string) {
+	if r := recover(); r != nil {
+		NewReport(r, debug.Stack(), version).Handle(os.Stderr)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,153 @@
+package crash
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// useTempDir points the temporary directory, where reports go, at a
+// fresh directory and returns it.
+func useTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	t.Setenv("TMP", dir)
+	return dir
+}
+
+func reports(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "dotenv-tui-crash-*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return paths
+}
+
+func TestRecordKeepsLatest(t *testing.T) {
+	t.Cleanup(func() { trail = nil })
+	trail = nil
+	for i := 0; i < trailSize+5; i++ {
+		Record(string(rune('a' + i)))
+	}
+	if len(trail) != trailSize {
+		t.Fatalf("len(trail) = %d, want %d", len(trail), trailSize)
+	}
+	if trail[0] != "f" || trail[trailSize-1] != string(rune('a'+trailSize+4)) {
+		t.Errorf("trail = %v, want the latest %d events", trail, trailSize)
+	}
+}
+
+func TestFlagNames(t *testing.T) {
+	got := flagNames([]string{"--set", "API_KEY=secret", "--format=json", "-y", ".env"})
+	want := []string{"--set", "--format", "-y"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("flagNames() = %v, want %v", got, want)
+	}
+}
+
+func TestReportWrite(t *testing.T) {
+	dir := useTempDir(t)
+	r := NewReport("boom", []byte("goroutine 1 [running]:\n"), "v1.2.3")
+	r.Trail = []string{"tea.KeyMsg"}
+	path, err := r.Write()
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if filepath.Dir(path) != dir {
+		t.Errorf("report written to %s, want it in %s", path, dir)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Version: v1.2.3", "Panic:   boom", "  tea.KeyMsg", "goroutine 1 [running]:"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestReportHandle(t *testing.T) {
+	dir := useTempDir(t)
+	var out bytes.Buffer
+	NewReport("boom", nil, "dev").Handle(&out)
+
+	paths := reports(t, dir)
+	if len(paths) != 1 {
+		t.Fatalf("got %d reports, want 1", len(paths))
+	}
+	for _, want := range []string{"dotenv-tui crashed: boom", paths[0], IssueURL} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+type panicMsg struct{}
+
+// panicModel panics in Update on panicMsg, in View once viewPanics is set,
+// or in the command returned by Init when cmdPanics is set.
+type panicModel struct {
+	cmdPanics  bool
+	viewPanics bool
+}
+
+func (m panicModel) Init() tea.Cmd {
+	if m.cmdPanics {
+		return func() tea.Msg { panic("command") }
+	}
+	return func() tea.Msg { return panicMsg{} }
+}
+
+func (m panicModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(panicMsg); ok && !m.viewPanics {
+		panic("update")
+	}
+	return m, nil
+}
+
+func (m panicModel) View() string {
+	if m.viewPanics {
+		panic("view")
+	}
+	return ""
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		model panicModel
+		want  string
+	}{
+		{"update", panicModel{}, "dotenv-tui crashed: update"},
+		{"command", panicModel{cmdPanics: true}, "dotenv-tui crashed: command"},
+		{"view", panicModel{viewPanics: true}, "dotenv-tui crashed: view"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := useTempDir(t)
+			var out bytes.Buffer
+			errOut = &out
+			t.Cleanup(func() { errOut = os.Stderr })
+
+			_, err := Run(tt.model, "dev", tea.WithInput(nil), tea.WithOutput(io.Discard))
+			if !errors.Is(err, ErrCrashed) {
+				t.Fatalf("Run() error = %v, want ErrCrashed", err)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output = %q, want it to contain %q", out.String(), tt.want)
+			}
+			if len(reports(t, dir)) != 1 {
+				t.Errorf("want one report in %s", dir)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package crash
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrCrashed is returned by Run when the program panicked. The user has
+// already been told where the diagnostics are.
+var ErrCrashed = errors.New("dotenv-tui crashed")
+
+// errOut is where crashes are reported; tests replace it.
+var errOut io.Writer = os.Stderr
+
+// guard wraps a model, turning panics in its Init, Update and View and in
+// the commands they return into a report and a clean quit, so the terminal
+// is restored before the report is printed.
+type guard struct {
+	model   tea.Model
+	version string
+	quit    func()
+
+	mu     sync.Mutex
+	report *Report
+}
+
+func (g *guard) Init() (cmd tea.Cmd) {
+	defer g.recover(func() { cmd = tea.Quit })
+	return g.wrap(g.model.Init())
+}
+
+func (g *guard) Update(msg tea.Msg) (_ tea.Model, cmd tea.Cmd) {
+	Record(fmt.Sprintf("%T", msg))
+	defer g.recover(func() { cmd = tea.Quit })
+	model, cmd := g.model.Update(msg)
+	g.model = model
+	return g, g.wrap(cmd)
+}
+
+func (g *guard) View() (view string) {
+	if g.crashed() {
+		return ""
+	}
+	// View cannot return a command, so the program is asked to quit from
+	// another goroutine; doing it here would block the event loop.
+	defer g.recover(func() { go g.quit() })
+	return g.model.View()
+}
+
+// wrap makes cmd, which runs in its own goroutine, report a panic and quit
+// the program. Commands batched inside the message it returns are run by
+// Bubble Tea, which catches their panics itself.
+func (g *guard) wrap(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer g.recover(func() { msg = tea.Quit() })
+		return cmd()
+	}
+}
+
+// recover is deferred: on a panic, it keeps the first report and calls
+// onPanic to quit.
+func (g *guard) recover(onPanic func()) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	report := NewReport(r, debug.Stack(), g.version)
+	g.mu.Lock()
+	if g.report == nil {
+		g.report = &report
+	}
+	g.mu.Unlock()
+	onPanic()
+}
+
+func (g *guard) crashed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.report != nil
+}
+
+// Run runs a Bubble Tea program for m, the given version of dotenv-tui, and
+// returns its final model. When the program panics, the terminal is
+// restored, a report is written as Report.Handle does, and the error is
+// ErrCrashed.
+func Run(m tea.Model, version string, opts ...tea.ProgramOption) (tea.Model, error) {
+	g := &guard{model: m, version: version}
+	p := tea.NewProgram(g, opts...)
+	g.quit = p.Quit
+	_, err := p.Run()
+
+	g.mu.Lock()
+	report := g.report
+	g.mu.Unlock()
+	switch {
+	case report != nil:
+		report.Handle(errOut)
+		return g.model, ErrCrashed
+	case errors.Is(err, tea.ErrProgramPanic):
+		// A panic Bubble Tea caught itself, which printed the stack.
+		NewReport("panic in a batched command (stack printed above)", nil, version).Handle(errOut)
+		return g.model, ErrCrashed
+	}
+	return g.model, err
+}
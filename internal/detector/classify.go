@@ -0,0 +1,67 @@
+package detector
+
+import "github.com/jellydn/dotenv-tui/internal/dotenvx"
+
+// Confidence says how sure a Classification is.
+type Confidence string
+
+// Confidence levels.
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+// Classification is the detector's verdict on a key-value pair, with the
+// reason for it, so users can see why a value was or was not masked.
+type Classification struct {
+	IsSecret   bool       `json:"secret"`
+	Confidence Confidence `json:"confidence"`
+	Reason     string     `json:"reason"`
+}
+
+// Classify decides whether a key-value pair holds a secret and why. Keys
+// that are never secret, dotenvx-encrypted values and empty values are not
+// secrets; otherwise a secret key name or a credential-shaped value makes
+// one. A recognizable value is more certain than a name alone. Keys that
+// frameworks make public are still secrets when their value is a known
+// token, a JWT or a URL with credentials, unless secrets.ignore lists them.
+// It applies the built-in rules only; see Rules.Classify.
+func Classify(key, value string) Classification {
+	return Rules{}.Classify(key, value)
+}
+
+// Classify is the package-level Classify with r's keys added to the
+// built-in ones. Keys are matched case-insensitively; invalid globs, which
+// CheckKeyPattern reports, are ignored.
+func (r Rules) Classify(key, value string) Classification {
+	if reason := r.nonSecretReason(key); reason != "" {
+		_, public := firstName(frameworkPublic, key)
+		_, ignored := firstName(compileKeyPatterns(r.PublicKeys), key)
+		if public && !ignored {
+			if valueReason, confidence := secretValueReason(value); confidence == ConfidenceHigh {
+				return Classification{IsSecret: true, Confidence: ConfidenceHigh, Reason: valueReason + ", although " + reason}
+			}
+		}
+		return Classification{Confidence: ConfidenceHigh, Reason: reason}
+	}
+	if dotenvx.IsEncrypted(value) {
+		return Classification{Confidence: ConfidenceHigh, Reason: "value is encrypted by dotenvx"}
+	}
+	if value == "" {
+		return Classification{Confidence: ConfidenceHigh, Reason: "value is empty"}
+	}
+
+	pattern := r.secretKeyMatch(key)
+	valueReason, confidence := secretValueReason(value)
+	switch {
+	case pattern != "" && valueReason != "":
+		return Classification{IsSecret: true, Confidence: ConfidenceHigh, Reason: "key matches " + pattern + " and " + valueReason}
+	case pattern != "":
+		return Classification{IsSecret: true, Confidence: ConfidenceMedium, Reason: "key matches " + pattern}
+	case valueReason != "":
+		return Classification{IsSecret: true, Confidence: confidence, Reason: valueReason}
+	default:
+		return Classification{Confidence: ConfidenceLow, Reason: "neither the key name nor the value looks secret"}
+	}
+}
@@ -0,0 +1,95 @@
+package detector
+
+import (
+	"regexp"
+	"strings"
+)
+
+// segmentName matches the names of the Name=value segments of connection
+// strings such as Azure's "AccountName=x;AccountKey=y" or ADO.NET's
+// "Server=x;User Id=y;Password=z".
+var segmentName = regexp.MustCompile(`^\s*[A-Za-z][A-Za-z0-9 _.-]*$`)
+
+// listItem matches the items of a value listing several tokens, separated
+// by whitespace or commas.
+var listItem = regexp.MustCompile(`[^\s,]+`)
+
+// secretSegment reports whether a connection string segment name holds a
+// credential. Besides the usual secret names, connection strings name keys
+// like AccountKey and passwords Pwd.
+func (r Rules) secretSegment(name string) bool {
+	key := fieldKey(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+	return r.secretField(key) || strings.HasSuffix(key, "KEY") || key == "PWD" || key == "SHARED_ACCESS_SIGNATURE"
+}
+
+// segments splits a connection string into its Name=value segments. It
+// reports false unless the value has at least two segments and every
+// non-empty one is Name=value.
+func segments(value string) ([]string, bool) {
+	if !strings.Contains(value, ";") {
+		return nil, false
+	}
+	parts := strings.Split(value, ";")
+	n := 0
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		name, _, ok := strings.Cut(part, "=")
+		if !ok || !segmentName.MatchString(name) {
+			return nil, false
+		}
+		n++
+	}
+	return parts, n >= 2
+}
+
+// MaskCompound masks each secret component of a value made of several
+// parts, keeping the rest: the credential segments of a connection string
+// such as "AccountName=x;AccountKey=y", or each token of a value listing
+// two or more, such as a connection string with a token appended. It
+// reports false for other values and when nothing needs masking.
+func MaskCompound(key, value string, m Masking) (string, bool) {
+	if parts, ok := segments(value); ok {
+		masked := false
+		for i, part := range parts {
+			name, v, ok := strings.Cut(part, "=")
+			if !ok || v == "" || IsPlaceholder(v) || !m.Rules.secretSegment(name) && !isSecretValue(v) {
+				continue
+			}
+			parts[i] = name + "=" + m.Placeholder(fieldKey(strings.TrimSpace(name)), v)
+			masked = true
+		}
+		return strings.Join(parts, ";"), masked
+	}
+
+	spans := listItem.FindAllStringIndex(value, -1)
+	if len(spans) < 2 {
+		return "", false
+	}
+	var secrets [][]int
+	for _, span := range spans {
+		if item := value[span[0]:span[1]]; isSecretValue(item) && !IsPlaceholder(item) {
+			secrets = append(secrets, span)
+		}
+	}
+	if len(secrets) < 2 {
+		return "", false
+	}
+	var b strings.Builder
+	last := 0
+	for _, span := range secrets {
+		b.WriteString(value[last:span[0]])
+		b.WriteString(m.Placeholder(key, value[span[0]:span[1]]))
+		last = span[1]
+	}
+	b.WriteString(value[last:])
+	return b.String(), true
+}
+
+// hasCompoundSecret reports whether a connection string has a credential
+// segment with a value, or a value lists two or more secret tokens.
+func hasCompoundSecret(value string) bool {
+	_, ok := MaskCompound("", value, Masking{})
+	return ok
+}
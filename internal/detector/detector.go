@@ -3,26 +3,39 @@ package detector
 
 import (
 	"encoding/base64"
+	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/dotenvx"
 )
 
 var hexPattern = regexp.MustCompile("^[0-9a-fA-F]+$")
 
+// secretPatterns are the key substrings, or globs, that mark a value as
+// secret, and commonNonSecrets the key names, or globs, that never do; see
+// keyPattern.
 var (
-	secretPatterns = []string{
+	secretPatterns = compileKeyPatterns([]string{
 		"SECRET", "TOKEN", "PASSWORD", "PASS",
 		"AUTH", "CREDENTIAL", "PRIVATE",
 		"API_KEY", "ACCESS_KEY",
-	}
-	commonNonSecrets = []string{
+	})
+	commonNonSecrets = compileKeyPatterns([]string{
 		"PORT", "HOST", "NODE_ENV", "APP_NAME",
 		"DEBUG", "LOG_LEVEL",
 		"ENV", "ENVIRONMENT", "VERSION", "LANG",
 		"TIMEZONE", "REGION",
 		"ENDPOINT", "URL", "URI", "DOMAIN",
 		"SERVER", "CLUSTER",
-	}
+	})
+	// frameworkPublic are the keys frameworks bundle into client code, so
+	// they are public by design. A credential-shaped value still counts as
+	// a secret: it was put there by mistake, and is leaking already.
+	frameworkPublic = compileKeyPatterns([]string{
+		"NEXT_PUBLIC_*", "NUXT_PUBLIC_*", "EXPO_PUBLIC_*",
+		"VITE_*", "REACT_APP_*", "GATSBY_*",
+	})
 )
 
 // prefixPlaceholder defines a secret prefix and its placeholder format.
@@ -57,21 +70,58 @@ var knownSecretPrefixes = []prefixPlaceholder{
 	{"ya29.", "ya29.***"},
 }
 
-// IsSecret determines if a key-value pair appears to contain a secret
+// IsSecret determines if a key-value pair appears to contain a secret.
+// Values encrypted by dotenvx are safe to share and never count as one.
 func IsSecret(key string, value string) bool {
-	if isCommonNonSecret(key) {
-		return false
-	}
+	return Rules{}.IsSecret(key, value)
+}
 
-	if len(value) == 0 {
-		return false
+// IsSecret is the package-level IsSecret under r.
+func (r Rules) IsSecret(key string, value string) bool {
+	return r.Classify(key, value).IsSecret
+}
+
+// HasSecretValue reports whether the value itself looks like a credential
+// (known token prefix, JWT, URL with credentials, long base64 or hex, or a
+// connection string with a credential segment), regardless of the key it
+// is assigned to.
+func HasSecretValue(value string) bool {
+	return isSecretValue(value)
+}
+
+// IsSecretKey reports whether key names a secret, whatever its value, so
+// a masked or empty example value can still be recognized as one.
+func IsSecretKey(key string) bool {
+	return Rules{}.IsSecretKey(key)
+}
+
+// IsSecretKey is the package-level IsSecretKey under r.
+func (r Rules) IsSecretKey(key string) bool {
+	return !r.isCommonNonSecret(key) && r.isSecretKey(key)
+}
+
+// IsPlaceholder returns true if the value appears to be a placeholder rather
+// than a real value. It checks for common placeholder patterns like *** masks,
+// descriptive "<kind>" placeholders, "your_*" prefix, and words like
+// "placeholder" or "example" in the value.
+func IsPlaceholder(value string) bool {
+	if strings.Contains(value, "***") || strings.Contains(value, partialMask) {
+		return true
 	}
 
-	if isSecretKey(key) {
+	if _, ok := PlaceholderKind(value); ok {
 		return true
 	}
 
-	if isSecretValue(value) {
+	lower := strings.ToLower(value)
+	placeholderPatterns := []string{"your_", "_here", "placeholder"}
+	for _, pattern := range placeholderPatterns {
+		if strings.Contains(lower, pattern) {
+			return true
+		}
+	}
+
+	if strings.Contains(lower, "example") && !strings.Contains(lower, "://") {
 		return true
 	}
 
@@ -92,8 +142,8 @@ func GeneratePlaceholder(_ string, value string) string {
 	}
 
 	// URL patterns
-	if strings.Contains(value, "://") && strings.Contains(value, "@") {
-		return generateUrlPlaceholder(value)
+	if strings.Contains(value, "://") {
+		return maskURL(value)
 	}
 
 	// Check known prefixes (case-insensitive)
@@ -105,7 +155,8 @@ func GeneratePlaceholder(_ string, value string) string {
 	return "***"
 }
 
-// generateUrlPlaceholder creates a placeholder for URL-style values.
+// generateUrlPlaceholder creates a placeholder for URL-style values that
+// cannot be parsed well enough for maskURL.
 func generateUrlPlaceholder(value string) string {
 	if strings.HasPrefix(value, "http://") {
 		return "http://***"
@@ -126,60 +177,104 @@ func findPrefixPlaceholder(value string) string {
 	return ""
 }
 
-func isSecretKey(key string) bool {
+func (r Rules) isSecretKey(key string) bool {
+	return r.secretKeyMatch(key) != ""
+}
+
+// secretKeyMatch returns the secret pattern key contains, or an empty
+// string.
+func (r Rules) secretKeyMatch(key string) string {
 	keyUpper := strings.ToUpper(key)
-	for _, pattern := range secretPatterns {
-		if strings.Contains(keyUpper, pattern) {
-			return true
+	for _, patterns := range [][]keyPattern{secretPatterns, compileKeyPatterns(r.SecretKeys)} {
+		for _, p := range patterns {
+			if p.matchPart(keyUpper) {
+				return p.text
+			}
 		}
 	}
-	return false
+	return ""
 }
 
 func isSecretValue(value string) bool {
-	if len(value) == 0 {
-		return false
+	reason, _ := secretValueReason(value)
+	return reason != ""
+}
+
+// secretValueReason describes why value looks like a credential, and how
+// sure that is, or returns an empty reason.
+func secretValueReason(value string) (string, Confidence) {
+	if len(value) == 0 || dotenvx.IsEncrypted(value) {
+		return "", ""
 	}
 
 	// URLs with user:pass@ pattern
 	if strings.Contains(value, "://") && strings.Contains(value, "@") {
-		return true
+		return "value is a URL with credentials", ConfidenceHigh
+	}
+
+	// URLs with credentials in the query string
+	if hasSecretQuery(value) {
+		return "value is a URL with a credential in its query", ConfidenceHigh
 	}
 
 	// JWT tokens (must be longer than 50 chars to be considered a real JWT)
 	if strings.HasPrefix(value, "eyJ") && len(value) > 50 {
-		return true
+		return "value is a JWT", ConfidenceHigh
 	}
 
 	// Known secret prefixes
 	lowerValue := strings.ToLower(value)
 	for _, pp := range knownSecretPrefixes {
 		if strings.HasPrefix(lowerValue, pp.prefix) {
-			return true
+			return fmt.Sprintf("value starts with %s", value[:len(pp.prefix)]), ConfidenceHigh
 		}
 	}
 
+	// Hex strings longer than 32 chars
+	if len(value) > 32 && isHex(value) {
+		return "value is a long hex string", ConfidenceMedium
+	}
+
 	// Base64 strings longer than 20 chars (but not JWT tokens)
 	if len(value) > 20 && isBase64(value) && !strings.HasPrefix(value, "eyJ") {
-		return true
+		return "value is a long base64 string", ConfidenceMedium
 	}
 
-	// Hex strings longer than 32 chars
-	if len(value) > 32 && isHex(value) {
-		return true
+	// Connection strings with a credential segment, and lists of tokens
+	if hasCompoundSecret(value) {
+		return "value has secret parts", ConfidenceMedium
 	}
 
-	return false
+	return "", ""
 }
 
-func isCommonNonSecret(key string) bool {
+func (r Rules) isCommonNonSecret(key string) bool {
+	return r.nonSecretReason(key) != ""
+}
+
+// nonSecretReason describes why key is never treated as secret, or returns
+// an empty string.
+func (r Rules) nonSecretReason(key string) string {
 	keyUpper := strings.ToUpper(key)
-	for _, pattern := range commonNonSecrets {
-		if keyUpper == pattern {
-			return true
+	if dotenvx.IsPublicKey(keyUpper) {
+		return "key is a dotenvx public key"
+	}
+	if p, ok := firstName(commonNonSecrets, keyUpper); ok {
+		if p.glob {
+			return fmt.Sprintf("key matches %s, a common non-secret pattern", p.text)
 		}
+		return "key is a common non-secret name"
 	}
-	return false
+	if p, ok := firstName(frameworkPublic, keyUpper); ok {
+		return fmt.Sprintf("key matches %s, which frameworks bundle into client code", p.text)
+	}
+	if p, ok := firstName(compileKeyPatterns(r.PublicKeys), keyUpper); ok {
+		if p.glob {
+			return fmt.Sprintf("key matches %s in secrets.ignore", p.text)
+		}
+		return "key is listed in secrets.ignore"
+	}
+	return ""
 }
 
 func isBase64(s string) bool {
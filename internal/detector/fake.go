@@ -0,0 +1,228 @@
+package detector
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Alphabets fake values are drawn from.
+const (
+	alnumChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	bech32Chars = "023456789ACDEFGHJKLMNPQRSTUVWXYZ"
+	hexChars    = "0123456789abcdef"
+)
+
+// Lengths of fake values of no known format, which match the length of the
+// value they replace within bounds.
+const (
+	minFakeLength     = 16
+	maxFakeLength     = 64
+	defaultFakeLength = 32 // for empty values and placeholders
+)
+
+// FakeValue returns a fake value shaped like value, for test env files: a
+// Stripe key gets a well-formed Stripe test key, a JWT a well-formed JWT,
+// a UUID a UUID and a database URL the same URL pointing at localhost with
+// a fake password. Placeholders such as sk_*** or <your-github-token> get
+// a fake of the kind they stand for. Live Stripe keys become test keys.
+//
+// The value is derived from key alone, so the same key always gets the
+// same fake and nothing about the real value leaks into it.
+func FakeValue(key, value string) string {
+	f := faker{seed: key}
+	if hint, ok := placeholderHint(value); ok {
+		if strings.Contains(hint, "://") {
+			fake := f.chars(alnumChars, minFakeLength)
+			return strings.NewReplacer("***", fake, partialMask, fake).Replace(hint)
+		}
+		return f.hinted(hint)
+	}
+
+	switch {
+	case strings.Contains(value, "://"):
+		return f.url(value)
+	case strings.HasPrefix(value, "eyJ") && len(value) > 50:
+		return f.jwt()
+	case uuidPattern.MatchString(value):
+		return f.uuid()
+	case len(value) > 32 && isHex(value):
+		return f.chars(hexChars, len(value))
+	}
+	return f.token(value)
+}
+
+// placeholderHint returns what a placeholder tells about the value it
+// stands for: the known prefix it keeps, such as "sk_" of "sk_***", or
+// the URL it masks. It reports false for values that are not placeholders.
+func placeholderHint(value string) (string, bool) {
+	if !IsPlaceholder(value) {
+		return "", false
+	}
+	if kind, ok := PlaceholderKind(value); ok {
+		for _, pk := range prefixKinds {
+			if pk.kind == kind {
+				return pk.prefix, true
+			}
+		}
+		if db, ok := strings.CutSuffix(kind, "-connection-url"); ok {
+			return fmt.Sprintf("%s://test:***@localhost:%d/test", db, databasePorts[db]), true
+		}
+		if scheme, ok := strings.CutSuffix(kind, "-url"); ok {
+			return scheme + "://localhost", true
+		}
+		return "", true
+	}
+	if strings.Contains(value, "://") {
+		return value, true
+	}
+	for _, mask := range []string{"***", partialMask} {
+		if i := strings.Index(value, mask); i >= 0 {
+			return value[:i], true
+		}
+	}
+	return "", true
+}
+
+// databasePorts are the default ports of the databases named by
+// databaseSchemes.
+var databasePorts = map[string]int{
+	"postgres": 5432,
+	"mysql":    3306,
+	"mariadb":  3306,
+	"mongodb":  27017,
+	"redis":    6379,
+	"amqp":     5672,
+}
+
+// faker draws deterministic characters from a seed.
+type faker struct {
+	seed    string
+	counter uint32
+}
+
+// chars returns n characters of alphabet.
+func (f *faker) chars(alphabet string, n int) string {
+	var b strings.Builder
+	for b.Len() < n {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], f.counter)
+		f.counter++
+		sum := sha256.Sum256(append([]byte(f.seed+"\x00"), buf[:]...))
+		for _, c := range sum {
+			if b.Len() == n {
+				break
+			}
+			b.WriteByte(alphabet[int(c)%len(alphabet)])
+		}
+	}
+	return b.String()
+}
+
+// token returns a fake for a value starting like value: a well-formed
+// token of the type it starts with, or its known prefix followed by as many
+// random characters as value has, within bounds.
+func (f *faker) token(value string) string {
+	for _, s := range tokenShapes {
+		if strings.HasPrefix(value, s.prefix) {
+			return f.shaped(s)
+		}
+	}
+
+	prefix := ""
+	lower := strings.ToLower(value)
+	for _, pk := range knownSecretPrefixes {
+		if strings.HasPrefix(lower, pk.prefix) {
+			prefix = value[:len(pk.prefix)]
+			break
+		}
+	}
+	n := defaultFakeLength
+	if value != "" {
+		n = min(max(len(value), minFakeLength), maxFakeLength)
+	}
+	return prefix + f.chars(alnumChars, n)
+}
+
+// hinted returns a fake for a placeholder keeping hint, the start of the
+// value it masks, which may be shorter than the prefix of its token type,
+// as "sk_" is of "sk_live_".
+func (f *faker) hinted(hint string) string {
+	if hint != "" {
+		lower := strings.ToLower(hint)
+		for _, s := range tokenShapes {
+			if strings.HasPrefix(strings.ToLower(s.prefix), lower) {
+				return f.shaped(s)
+			}
+		}
+	}
+	return f.token(hint)
+}
+
+// shaped returns a well-formed token of shape s, of the test variant for
+// Stripe keys.
+func (f *faker) shaped(s tokenShape) string {
+	prefix := strings.Replace(s.prefix, "_live_", "_test_", 1)
+	return prefix + f.chars(shapeAlphabet(s), s.min-len(prefix))
+}
+
+// shapeAlphabet returns characters valid in tokens of shape s.
+func shapeAlphabet(s tokenShape) string {
+	switch s.charset {
+	case upperAlnum:
+		return upperChars
+	case bech32Upper:
+		return bech32Chars
+	default:
+		return alnumChars
+	}
+}
+
+// url returns value pointing at localhost, with its password and secret
+// query parameters replaced by fakes.
+func (f *faker) url(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "http://localhost"
+	}
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort("localhost", port)
+	} else {
+		u.Host = "localhost"
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), f.chars(alnumChars, minFakeLength))
+		}
+	}
+	if u.RawQuery != "" {
+		params := strings.Split(u.RawQuery, "&")
+		for i, param := range params {
+			if name, v, ok := strings.Cut(param, "="); ok && v != "" && isSecretParam(name) {
+				params[i] = name + "=" + f.chars(alnumChars, minFakeLength)
+			}
+		}
+		u.RawQuery = strings.Join(params, "&")
+	}
+	return u.String()
+}
+
+// jwt returns a well-formed JWT signed with HS256 over a fake subject. The
+// signature is random, so it verifies against no key.
+func (f *faker) jwt() string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := enc.EncodeToString(fmt.Appendf(nil, `{"sub":"test-%s","iat":1700000000}`, f.chars(hexChars, 8)))
+	return header + "." + payload + "." + f.chars(alnumChars, 43)
+}
+
+// uuid returns a fake version 4 UUID.
+func (f *faker) uuid() string {
+	h := f.chars(hexChars, 32)
+	return h[:8] + "-" + h[8:12] + "-4" + h[13:16] + "-a" + h[17:20] + "-" + h[20:]
+}
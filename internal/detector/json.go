@@ -0,0 +1,199 @@
+package detector
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// jsonMember is one field of a JSON object, kept in document order.
+type jsonMember struct {
+	name  string
+	value any
+}
+
+// jsonObject is a JSON object whose fields keep their order, so a masked
+// document reads like the original.
+type jsonObject []jsonMember
+
+// MaskJSON masks the secrets inside a value holding a JSON object or array,
+// such as a service-account file, keeping its structure: string fields
+// whose names look secret (private_key, client_secret), everything nested
+// under them, and strings that look like credentials are replaced by
+// placeholders, and the rest is kept. A top-level array is masked whole
+// when key names a secret. It reports false for values that are not JSON
+// objects or arrays and for documents with nothing to mask, which callers
+// treat like any other value.
+func MaskJSON(key, value string, m Masking) (string, bool) {
+	trimmed := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return "", false
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	doc, err := decodeJSON(dec)
+	if err != nil {
+		return "", false
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return "", false
+	}
+
+	_, isArray := doc.([]any)
+	masked := false
+	doc = maskJSON(doc, key, isArray && m.Rules.IsSecretKey(key), m, &masked)
+	if !masked {
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, doc); err != nil {
+		return "", false
+	}
+	if strings.Contains(trimmed, "\n") {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, buf.Bytes(), "", "  "); err == nil {
+			return indented.String(), true
+		}
+	}
+	return buf.String(), true
+}
+
+// decodeJSON reads the next value from dec, keeping object fields in order.
+func decodeJSON(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+	switch delim {
+	case '{':
+		obj := jsonObject{}
+		for dec.More() {
+			nameTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			name, _ := nameTok.(string)
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, jsonMember{name, value})
+		}
+		_, err = dec.Token()
+		return obj, err
+	case '[':
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err = dec.Token()
+		return arr, err
+	default:
+		return nil, errors.New("unexpected delimiter")
+	}
+}
+
+var camelBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// fieldKey turns a JSON field name such as "clientSecret" or "api-key"
+// into the key style the secret patterns match, CLIENT_SECRET and API_KEY.
+func fieldKey(name string) string {
+	name = camelBoundary.ReplaceAllString(name, "${1}_${2}")
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// locatorSuffixes end field names that hold where something is rather than
+// a credential, like the auth_uri and token_uri of a service-account file.
+var locatorSuffixes = []string{"_URI", "_URL", "_ENDPOINT"}
+
+// secretField reports whether a JSON field name looks secret under r.
+func (r Rules) secretField(name string) bool {
+	key := fieldKey(name)
+	for _, suffix := range locatorSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return false
+		}
+	}
+	return r.IsSecretKey(key)
+}
+
+// maskJSON masks the strings of v that are secret, either because v is
+// nested under a secret-named field or by their shape. name is the field
+// holding v, used to choose placeholders. masked is set when a string is
+// replaced.
+func maskJSON(v any, name string, secret bool, m Masking, masked *bool) any {
+	switch t := v.(type) {
+	case jsonObject:
+		for i, member := range t {
+			t[i].value = maskJSON(member.value, member.name, secret || m.Rules.secretField(member.name), m, masked)
+		}
+		return t
+	case []any:
+		for i, item := range t {
+			t[i] = maskJSON(item, name, secret, m, masked)
+		}
+		return t
+	case string:
+		if t == "" || IsPlaceholder(t) || !secret && !isSecretValue(t) {
+			return t
+		}
+		*masked = true
+		return m.Placeholder(fieldKey(name), t)
+	default:
+		return v
+	}
+}
+
+// encodeJSON writes v compactly, objects in field order and without
+// escaping HTML characters, so placeholders like "<your-api-key>" read as
+// written.
+func encodeJSON(buf *bytes.Buffer, v any) error {
+	switch t := v.(type) {
+	case jsonObject:
+		buf.WriteByte('{')
+		for i, member := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeJSON(buf, member.name); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := encodeJSON(buf, member.value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+		buf.Truncate(buf.Len() - 1) // Encode's trailing newline
+	}
+	return nil
+}
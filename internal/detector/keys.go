@@ -0,0 +1,70 @@
+package detector
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// keyPattern matches key names, case-insensitively. A pattern holding *, ?
+// or [ is a glob over the whole name in path.Match syntax, such as
+// NEXT_PUBLIC_*; other patterns are plain text, matched as a substring or
+// the whole name depending on the list they are in.
+type keyPattern struct {
+	text string
+	glob bool
+}
+
+// compileKeyPatterns upper-cases patterns once, dropping empty ones and
+// globs path.Match rejects, which CheckKeyPattern reports.
+func compileKeyPatterns(patterns []string) []keyPattern {
+	var result []keyPattern
+	for _, p := range patterns {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if p == "" || CheckKeyPattern(p) != nil {
+			continue
+		}
+		result = append(result, keyPattern{text: p, glob: strings.ContainsAny(p, "*?[")})
+	}
+	return result
+}
+
+// CheckKeyPattern reports whether pattern is a valid key pattern: plain
+// text, or a glob such as NEXT_PUBLIC_* in path.Match syntax.
+func CheckKeyPattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// matchName reports whether keyUpper, an upper-cased key, is the pattern's
+// name or matches its glob.
+func (p keyPattern) matchName(keyUpper string) bool {
+	if p.glob {
+		ok, _ := path.Match(p.text, keyUpper)
+		return ok
+	}
+	return keyUpper == p.text
+}
+
+// matchPart reports whether keyUpper contains the pattern's text or
+// matches its glob.
+func (p keyPattern) matchPart(keyUpper string) bool {
+	if p.glob {
+		ok, _ := path.Match(p.text, keyUpper)
+		return ok
+	}
+	return strings.Contains(keyUpper, p.text)
+}
+
+// firstName returns the first of patterns matching key as a whole name.
+func firstName(patterns []keyPattern, key string) (keyPattern, bool) {
+	keyUpper := strings.ToUpper(key)
+	for _, p := range patterns {
+		if p.matchName(keyUpper) {
+			return p, true
+		}
+	}
+	return keyPattern{}, false
+}
@@ -0,0 +1,218 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PlaceholderStyle selects how secrets are masked in generated examples.
+type PlaceholderStyle string
+
+// Placeholder styles.
+const (
+	// StyleMask keeps a recognizable prefix and masks the rest, e.g. "sk_***".
+	StyleMask PlaceholderStyle = "mask"
+	// StyleDescriptive names the kind of value, e.g. "<your-stripe-secret-key>".
+	StyleDescriptive PlaceholderStyle = "descriptive"
+	// StylePartial keeps the prefix and the last few characters, e.g.
+	// "sk_live_••••••cdef".
+	StylePartial PlaceholderStyle = "partial"
+	// StyleFake replaces secrets with fake values of the same format for
+	// test env files; see FakeValue. ParsePlaceholderStyle rejects it, as
+	// examples should not hold values that look real.
+	StyleFake PlaceholderStyle = "fake"
+)
+
+// DefaultVisible is the number of trailing characters StylePartial shows
+// when Masking.Visible is not set.
+const DefaultVisible = 4
+
+// Masking configures how secrets are turned into placeholders.
+type Masking struct {
+	Style PlaceholderStyle
+	// Visible is the number of trailing characters kept by StylePartial.
+	Visible int
+	// Unmasked are key patterns, such as "PUBLIC_KEY" or "NEXT_PUBLIC_*",
+	// whose values are kept as is even when they look secret. Patterns use
+	// path.Match syntax and ignore case.
+	Unmasked []string
+	// Rules are the project's extra detection rules, used to tell which
+	// values are secret and, by their templates, to build placeholders.
+	Rules Rules
+}
+
+// KeepsKey reports whether key matches one of m.Unmasked, so its value is
+// never masked.
+func (m Masking) KeepsKey(key string) bool {
+	_, ok := firstName(compileKeyPatterns(m.Unmasked), key)
+	return ok
+}
+
+// ParsePlaceholderStyle validates a style name. An empty name selects StyleMask.
+func ParsePlaceholderStyle(name string) (PlaceholderStyle, error) {
+	switch style := PlaceholderStyle(strings.ToLower(strings.TrimSpace(name))); style {
+	case "":
+		return StyleMask, nil
+	case StyleMask, StyleDescriptive, StylePartial:
+		return style, nil
+	default:
+		return StyleMask, fmt.Errorf("unknown placeholder style %q (use %s, %s or %s)", name, StyleMask, StyleDescriptive, StylePartial)
+	}
+}
+
+// Placeholder creates a placeholder for a secret according to m, or from
+// the first of m.Rules.Templates that matches the value. With
+// StyleFake it returns a fake value instead.
+func (m Masking) Placeholder(key, value string) string {
+	if m.Style == StyleFake {
+		return FakeValue(key, value)
+	}
+	if p, ok := m.Rules.templatePlaceholder(value); ok {
+		return p
+	}
+	switch m.Style {
+	case StyleDescriptive:
+		return DescriptivePlaceholder(key, value)
+	case StylePartial:
+		visible := m.Visible
+		if visible <= 0 {
+			visible = DefaultVisible
+		}
+		return PartialPlaceholder(value, visible)
+	default:
+		return GeneratePlaceholder(key, value)
+	}
+}
+
+// partialMask replaces the hidden middle of a partially masked value. It has
+// a fixed length so the placeholder does not reveal the secret's length.
+const partialMask = "••••••"
+
+// PartialPlaceholder keeps a known vendor prefix and the last visible
+// characters of value, e.g. "sk_live_••••••cdef". Values too short to hide
+// at least as much as they show are fully masked. URLs are masked by
+// component, as in GeneratePlaceholder.
+func PartialPlaceholder(value string, visible int) string {
+	if strings.Contains(value, "://") {
+		return maskURL(value)
+	}
+
+	prefix := ""
+	lower := strings.ToLower(value)
+	for _, pk := range prefixKinds {
+		if strings.HasPrefix(lower, pk.prefix) {
+			prefix = value[:len(pk.prefix)]
+			break
+		}
+	}
+
+	rest := []rune(value[len(prefix):])
+	if len(rest) < visible*2 {
+		return prefix + partialMask
+	}
+	return prefix + partialMask + string(rest[len(rest)-visible:])
+}
+
+var (
+	uuidPattern        = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	descriptivePattern = regexp.MustCompile(`^<([a-z0-9]+(?:-[a-z0-9]+)*)>$`)
+	nonAlphanumeric    = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// prefixKinds maps known secret prefixes to the kind named in descriptive
+// placeholders. Keep in sync with knownSecretPrefixes.
+var prefixKinds = []struct {
+	prefix string
+	kind   string
+}{
+	{"age-secret-key-", "age-secret-key"},
+	{"akia", "aws-access-key-id"},
+	{"gho_", "github-oauth-token"},
+	{"ghp_", "github-token"},
+	{"ghs_", "github-token"},
+	{"ghu_", "github-token"},
+	{"github_pat_", "github-token"},
+	{"pk_live_", "stripe-publishable-key"},
+	{"pk_test_", "stripe-publishable-key"},
+	{"rk_live_", "stripe-restricted-key"},
+	{"rk_test_", "stripe-restricted-key"},
+	{"sk_live_", "stripe-secret-key"},
+	{"sk_test_", "stripe-secret-key"},
+	{"ssh-ed25519", "ssh-key"},
+	{"ssh-rsa", "ssh-key"},
+	{"whsec_", "stripe-webhook-secret"},
+	{"xoxa-", "slack-token"},
+	{"xoxb-", "slack-bot-token"},
+	{"xoxp-", "slack-user-token"},
+	{"ya29.", "google-oauth-token"},
+}
+
+// databaseSchemes are URL schemes whose credentials URLs are named
+// "<scheme>-connection-url".
+var databaseSchemes = map[string]string{
+	"postgres":    "postgres",
+	"postgresql":  "postgres",
+	"mysql":       "mysql",
+	"mariadb":     "mariadb",
+	"mongodb":     "mongodb",
+	"mongodb+srv": "mongodb",
+	"redis":       "redis",
+	"rediss":      "redis",
+	"amqp":        "amqp",
+	"amqps":       "amqp",
+}
+
+// DescriptivePlaceholder names the kind of a secret value in angle brackets,
+// e.g. "<your-stripe-secret-key>", "<postgres-connection-url>" or "<uuid>",
+// falling back to a name derived from the key such as "<your-api-key>".
+func DescriptivePlaceholder(key, value string) string {
+	return "<" + placeholderKind(key, value) + ">"
+}
+
+func placeholderKind(key, value string) string {
+	lower := strings.ToLower(value)
+	for _, pk := range prefixKinds {
+		if strings.HasPrefix(lower, pk.prefix) {
+			return "your-" + pk.kind
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(value, "eyJ") && len(value) > 50:
+		return "jwt"
+	case strings.Contains(value, "://"):
+		scheme := lower[:strings.Index(lower, "://")]
+		if db, ok := databaseSchemes[scheme]; ok {
+			return db + "-connection-url"
+		}
+		return kebab(scheme) + "-url"
+	case uuidPattern.MatchString(value):
+		return "uuid"
+	case len(value) > 32 && isHex(value):
+		return "hex-string"
+	case len(value) > 20 && isBase64(value):
+		return "base64-string"
+	}
+
+	if name := kebab(key); name != "" {
+		return "your-" + name
+	}
+	return "value"
+}
+
+// kebab lower-cases s and joins its alphanumeric runs with dashes.
+func kebab(s string) string {
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// PlaceholderKind extracts the kind from a descriptive placeholder, e.g.
+// "stripe-secret-key" from "<your-stripe-secret-key>". It reports false for
+// values that are not descriptive placeholders.
+func PlaceholderKind(value string) (string, bool) {
+	m := descriptivePattern.FindStringSubmatch(value)
+	if m == nil {
+		return "", false
+	}
+	return strings.TrimPrefix(m[1], "your-"), true
+}
@@ -0,0 +1,71 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Rules extends the built-in secret detection with project-specific keys.
+// The zero value applies the built-in rules only.
+type Rules struct {
+	// SecretKeys are extra key substrings, such as "SIGNING", or globs
+	// over the whole key, such as "*_SIGNATURE", that mark a value as
+	// secret.
+	SecretKeys []string
+	// PublicKeys are key names, such as "SENTRY_DSN", or globs, such as
+	// "*_PUBLIC_KEY", that are never treated as secret.
+	PublicKeys []string
+	// Templates build the placeholders of values they match, in order,
+	// instead of the placeholder style.
+	Templates []Template
+}
+
+// Template is a placeholder built from the parts of a value that are safe
+// to share, such as the host of a database URL.
+type Template struct {
+	match       *regexp.Regexp
+	placeholder string
+}
+
+// templateField matches the {name} references of a template placeholder.
+var templateField = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ParseTemplate compiles a template. match is a regular expression for the
+// values the template applies to, and placeholder is the text written
+// instead of them, in which {name} stands for the named group name of
+// match, e.g. "postgres://{user}:***@{host}:{port}/{db}".
+func ParseTemplate(match, placeholder string) (Template, error) {
+	re, err := regexp.Compile(match)
+	if err != nil {
+		return Template{}, fmt.Errorf("invalid template pattern %q: %w", match, err)
+	}
+	for _, ref := range templateField.FindAllStringSubmatch(placeholder, -1) {
+		if re.SubexpIndex(ref[1]) < 0 {
+			return Template{}, fmt.Errorf("template %q uses {%s}, which %q does not capture", placeholder, ref[1], match)
+		}
+	}
+	return Template{match: re, placeholder: placeholder}, nil
+}
+
+// apply returns the placeholder for value, or false if the template does
+// not match it.
+func (t Template) apply(value string) (string, bool) {
+	m := t.match.FindStringSubmatch(value)
+	if m == nil {
+		return "", false
+	}
+	return templateField.ReplaceAllStringFunc(t.placeholder, func(ref string) string {
+		return m[t.match.SubexpIndex(ref[1:len(ref)-1])]
+	}), true
+}
+
+// templatePlaceholder returns the placeholder of the first of r.Templates
+// matching value.
+func (r Rules) templatePlaceholder(value string) (string, bool) {
+	for _, t := range r.Templates {
+		if p, ok := t.apply(value); ok {
+			return p, true
+		}
+	}
+	return "", false
+}
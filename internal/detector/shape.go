@@ -0,0 +1,92 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tokenShape describes the length and characters of a well-known token
+// type, so a truncated or mangled copy can be told apart from the real
+// thing. Lengths count the whole value, prefix included; max 0 means no
+// upper bound.
+type tokenShape struct {
+	prefix  string
+	name    string
+	min     int
+	max     int
+	charset *regexp.Regexp
+	chars   string
+}
+
+var (
+	alnum       = regexp.MustCompile(`^[A-Za-z0-9]*$`)
+	upperAlnum  = regexp.MustCompile(`^[A-Z0-9]*$`)
+	alnumUnder  = regexp.MustCompile(`^[A-Za-z0-9_]*$`)
+	alnumDash   = regexp.MustCompile(`^[A-Za-z0-9-]*$`)
+	base64Chars = regexp.MustCompile(`^[A-Za-z0-9+/=]*$`)
+	bech32Upper = regexp.MustCompile(`^[02-9AC-HJ-NP-Z]*$`)
+)
+
+// tokenShapes lists the token types whose shape is checked, matched by
+// prefix case-sensitively. Longer prefixes come before their own prefixes.
+var tokenShapes = []tokenShape{
+	{"AGE-SECRET-KEY-1", "age secret key", 74, 74, bech32Upper, "upper-case bech32"},
+	{"AKIA", "AWS access key ID", 20, 20, upperAlnum, "A-Z and 0-9"},
+	{"ASIA", "AWS temporary access key ID", 20, 20, upperAlnum, "A-Z and 0-9"},
+	{"github_pat_", "GitHub fine-grained token", 93, 93, alnumUnder, "letters, digits and _"},
+	{"ghp_", "GitHub token", 40, 40, alnum, "letters and digits"},
+	{"gho_", "GitHub OAuth token", 40, 40, alnum, "letters and digits"},
+	{"ghs_", "GitHub app token", 40, 40, alnum, "letters and digits"},
+	{"ghu_", "GitHub user-to-server token", 40, 40, alnum, "letters and digits"},
+	{"pk_live_", "Stripe publishable key", 32, 0, alnum, "letters and digits"},
+	{"pk_test_", "Stripe publishable key", 32, 0, alnum, "letters and digits"},
+	{"rk_live_", "Stripe restricted key", 32, 0, alnum, "letters and digits"},
+	{"rk_test_", "Stripe restricted key", 32, 0, alnum, "letters and digits"},
+	{"sk_live_", "Stripe secret key", 32, 0, alnum, "letters and digits"},
+	{"sk_test_", "Stripe secret key", 32, 0, alnum, "letters and digits"},
+	{"whsec_", "Stripe webhook secret", 38, 0, base64Chars, "base64"},
+	{"xoxa-", "Slack token", 30, 0, alnumDash, "letters, digits and -"},
+	{"xoxb-", "Slack bot token", 30, 0, alnumDash, "letters, digits and -"},
+	{"xoxp-", "Slack user token", 30, 0, alnumDash, "letters, digits and -"},
+}
+
+// ShapeProblem describes what is implausible about a value that starts like
+// a known token type, such as a Stripe key too short to be real or an AWS
+// key ID with lower-case letters, which usually means a truncated or
+// mangled copy-paste. It returns an empty string for values that look
+// right, placeholders and values of no known type.
+func ShapeProblem(value string) string {
+	if IsPlaceholder(value) {
+		return ""
+	}
+	for _, s := range tokenShapes {
+		rest, ok := strings.CutPrefix(value, s.prefix)
+		if !ok {
+			continue
+		}
+		n := len(value)
+		switch {
+		case s.min == s.max && n != s.min:
+			return fmt.Sprintf("is %d characters, but %ss are %d", n, s.name, s.min)
+		case n < s.min:
+			return fmt.Sprintf("is %d characters, but %ss are at least %d", n, s.name, s.min)
+		case s.max > 0 && n > s.max:
+			return fmt.Sprintf("is %d characters, but %ss are at most %d", n, s.name, s.max)
+		case !s.charset.MatchString(rest):
+			return fmt.Sprintf("contains %q, but %ss only use %s", firstMismatch(rest, s.charset), s.name, s.chars)
+		}
+		return ""
+	}
+	return ""
+}
+
+// firstMismatch returns the first character of s outside charset.
+func firstMismatch(s string, charset *regexp.Regexp) string {
+	for _, r := range s {
+		if !charset.MatchString(string(r)) {
+			return string(r)
+		}
+	}
+	return ""
+}
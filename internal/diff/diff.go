@@ -0,0 +1,125 @@
+// Package diff renders line-by-line differences between two files.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is how many unchanged lines surround each change in a hunk.
+const context = 3
+
+// line is one line of a line-by-line diff: ' ' kept, '-' removed or
+// '+' added.
+type line struct {
+	op   byte
+	text string
+}
+
+// edits returns the shortest edit turning a into b, found through their
+// longest common subsequence. Env files are small enough for the quadratic
+// table.
+func edits(a, b []string) []line {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []line
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, line{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, line{'-', a[i]})
+			i++
+		default:
+			diff = append(diff, line{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, line{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, line{'+', b[j]})
+	}
+	return diff
+}
+
+// Unified renders the changes turning a, the lines of the file named
+// aName, into b as a unified diff, or returns "" if they are equal.
+func Unified(aName, bName string, a, b []string) string {
+	diff := edits(a, b)
+
+	var changes []int
+	for i, d := range diff {
+		if d.op != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	_, _ = fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+	for first := 0; first < len(changes); {
+		// A hunk takes in every change closer to the previous one than
+		// the context on both sides would span.
+		last := first
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context {
+			last++
+		}
+		start := max(changes[first]-context, 0)
+		end := min(changes[last]+context+1, len(diff))
+
+		aStart, bStart := 0, 0
+		for _, d := range diff[:start] {
+			if d.op != '+' {
+				aStart++
+			}
+			if d.op != '-' {
+				bStart++
+			}
+		}
+		aLen, bLen := 0, 0
+		for _, d := range diff[start:end] {
+			if d.op != '+' {
+				aLen++
+			}
+			if d.op != '-' {
+				bLen++
+			}
+		}
+		// Ranges are 1-based, except that an empty range names the line
+		// before it.
+		if aLen > 0 {
+			aStart++
+		}
+		if bLen > 0 {
+			bStart++
+		}
+
+		_, _ = fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+		for _, d := range diff[start:end] {
+			out.WriteByte(d.op)
+			out.WriteString(d.text)
+			out.WriteByte('\n')
+		}
+		first = last + 1
+	}
+	return out.String()
+}
@@ -0,0 +1,35 @@
+package diff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	a := []string{"# app", "HOST=staging", "PORT=3000", "A=1", "B=2", "C=3", "D=4", "E=5", "F=6", "G=7", "DEBUG=true"}
+	b := []string{"# app", "HOST=prod", "PORT=3000", "A=1", "B=2", "C=3", "D=4", "E=5", "F=6", "G=7", "SENTRY=on"}
+
+	want := `--- .env.staging
++++ .env.production
+@@ -1,5 +1,5 @@
+ # app
+-HOST=staging
++HOST=prod
+ PORT=3000
+ A=1
+ B=2
+@@ -8,4 +8,4 @@
+ E=5
+ F=6
+ G=7
+-DEBUG=true
++SENTRY=on
+`
+	if got := Unified(".env.staging", ".env.production", a, b); got != want {
+		t.Errorf("Unified() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := Unified("a", "b", nil, []string{"KEY=1"}); got != "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+KEY=1\n" {
+		t.Errorf("Unified() into an empty file = %q", got)
+	}
+	if got := Unified("a", "b", a, a); got != "" {
+		t.Errorf("Unified() of equal files = %q, want empty", got)
+	}
+}
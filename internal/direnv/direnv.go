@@ -0,0 +1,164 @@
+// Package direnv converts env files to and from the .envrc files read by
+// direnv (https://direnv.net). An .envrc is a shell script, so values are
+// written as "export KEY=value" lines quoted for the shell, and only such
+// assignments are read back; any other command in the script is skipped.
+package direnv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// FileName is the name of the file direnv loads.
+const FileName = ".envrc"
+
+// IsDirenvFile reports whether path is a direnv .envrc file.
+func IsDirenvFile(path string) bool {
+	return filepath.Base(path) == FileName
+}
+
+// Write writes entries as an .envrc, headed by a comment naming source.
+// Comments and blank lines are kept, and every key is exported with its
+// value quoted for the shell. Keys that are not valid variable names, which
+// the shell would run as commands, are skipped and returned. Lines the
+// parser kept as comments without a leading "#" are commented out.
+func Write(w io.Writer, entries []parser.Entry, source string) ([]string, error) {
+	bw := bufio.NewWriter(w)
+	var skipped []string
+	_, _ = fmt.Fprintf(bw, "# Generated by dotenv-tui from %s; edit that file and export again instead.\n", source)
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case parser.KeyValue:
+			if !parser.IsValidKey(e.Key) {
+				skipped = append(skipped, e.Key)
+				continue
+			}
+			_, _ = fmt.Fprintf(bw, "export %s=%s\n", e.Key, Quote(e.Value))
+		case parser.Comment:
+			if !strings.HasPrefix(e.Text, "#") {
+				_, _ = fmt.Fprintln(bw, "# "+e.Text)
+				continue
+			}
+			_, _ = fmt.Fprintln(bw, e.Text)
+		case parser.BlankLine:
+			_, _ = fmt.Fprintln(bw)
+		case parser.Encoding:
+			// The shell reads .envrc files as plain UTF-8.
+		default:
+			return nil, fmt.Errorf("unknown entry type %T", entry)
+		}
+	}
+	return skipped, bw.Flush()
+}
+
+// safeWord matches values the shell reads literally without quotes.
+var safeWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// Quote quotes value so the shell reads it literally: values made only of
+// safe characters are left bare, anything else is single-quoted, with
+// each embedded single quote ending the quotes, escaped, and reopening
+// them.
+func Quote(value string) string {
+	if safeWord.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// assignment matches the start of a variable assignment, optionally
+// exported.
+var assignment = regexp.MustCompile(`^(export\s+)?([A-Za-z_][A-Za-z0-9_]*)=`)
+
+// Parse reads the variable assignments of an .envrc, along with comment and
+// blank lines. Single-quoted, double-quoted and bare values are unquoted as
+// the shell would, without expanding variables or commands; quoted values
+// may span lines. Other commands, such as "dotenv" or "PATH_add", are
+// skipped.
+func Parse(r io.Reader) ([]parser.Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	var entries []parser.Entry
+	for line := 1; text != ""; line++ {
+		current, rest, _ := strings.Cut(text, "\n")
+		trimmed := strings.TrimSpace(current)
+		m := assignment.FindStringSubmatch(trimmed)
+		switch {
+		case trimmed == "":
+			entries = append(entries, parser.BlankLine{})
+		case strings.HasPrefix(trimmed, "#"):
+			entries = append(entries, parser.Comment{Text: trimmed})
+		case m != nil:
+			start := strings.Index(text, m[0]) + len(m[0])
+			value, n, err := shellWord(text[start:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			entries = append(entries, parser.KeyValue{
+				Key:      m[2],
+				Value:    value,
+				Exported: m[1] != "",
+			})
+			consumed := text[start : start+n]
+			line += strings.Count(consumed, "\n")
+			_, rest, _ = strings.Cut(text[start+n:], "\n")
+		}
+		text = rest
+	}
+	return entries, nil
+}
+
+// shellWord reads one shell word from the start of s and returns its
+// unquoted value and the number of bytes read.
+func shellWord(s string) (string, int, error) {
+	var b strings.Builder
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == ';':
+			return b.String(), i, nil
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return "", 0, fmt.Errorf("unclosed single quote")
+			}
+			b.WriteString(s[i+1 : i+1+end])
+			i += end + 2
+		case c == '"':
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' && j+1 < len(s) && strings.IndexByte("\"\\$`\n", s[j+1]) >= 0 {
+					j++
+					if s[j] != '\n' {
+						b.WriteByte(s[j])
+					}
+					continue
+				}
+				b.WriteByte(s[j])
+			}
+			if j == len(s) {
+				return "", 0, fmt.Errorf("unclosed double quote")
+			}
+			i = j + 1
+		case c == '\\' && i+1 < len(s):
+			if s[i+1] != '\n' {
+				b.WriteByte(s[i+1])
+			}
+			i += 2
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), i, nil
+}
@@ -0,0 +1,105 @@
+package direnv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func TestWrite(t *testing.T) {
+	entries, err := parser.Parse(strings.NewReader("# Server\nPORT=3000\nNAME=\"my app\"\n\nQUOTE=it's $HOME\nURL=https://example.com/a?b=c&d=e\nEMPTY=\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var out strings.Builder
+	if _, err := Write(&out, entries, ".env"); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := "# Generated by dotenv-tui from .env; edit that file and export again instead.\n" +
+		"# Server\nexport PORT=3000\nexport NAME='my app'\n\nexport QUOTE='it'\\''s $HOME'\n" +
+		"export URL='https://example.com/a?b=c&d=e'\nexport EMPTY=''\n"
+	if out.String() != want {
+		t.Errorf("Write() =\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	// Parsing the .envrc back gives the original values.
+	back, err := Parse(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for _, entry := range back {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok {
+			continue
+		}
+		for _, orig := range entries {
+			if o, ok := orig.(parser.KeyValue); ok && o.Key == kv.Key && o.Value != kv.Value {
+				t.Errorf("%s = %q after a round trip, want %q", kv.Key, kv.Value, o.Value)
+			}
+		}
+	}
+}
+
+func TestWriteSkipsInvalidKeys(t *testing.T) {
+	entries, err := parser.Parse(strings.NewReader("$(echo pwned>&2) X=1\nOK=2\ntouch /tmp/pwned\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var out strings.Builder
+	skipped, err := Write(&out, entries, ".env")
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != "$(echo pwned>&2) X" {
+		t.Errorf("Write() skipped = %q, want the malicious key", skipped)
+	}
+	want := "# Generated by dotenv-tui from .env; edit that file and export again instead.\n" +
+		"export OK=2\n# touch /tmp/pwned\n"
+	if out.String() != want {
+		t.Errorf("Write() =\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	input := "# direnv\nsource_up\ndotenv .env.local\nexport A=plain # note\nB='single $X'\nexport C=\"double \\\"q\\\" \\$Y\"\nexport D=\"line one\nline two\"\nPATH_add bin\nexporter=1\nE=a\\ b'c'\"d\"\n"
+	entries, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[string]string{
+		"A":        "plain",
+		"B":        "single $X",
+		"C":        `double "q" $Y`,
+		"D":        "line one\nline two",
+		"exporter": "1",
+		"E":        "a bcd",
+	}
+	var keys []string
+	for _, entry := range entries {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok {
+			continue
+		}
+		keys = append(keys, kv.Key)
+		if kv.Value != want[kv.Key] {
+			t.Errorf("%s = %q, want %q", kv.Key, kv.Value, want[kv.Key])
+		}
+		if kv.Exported != (kv.Key == "A" || kv.Key == "C" || kv.Key == "D") {
+			t.Errorf("%s exported = %v", kv.Key, kv.Exported)
+		}
+	}
+	if got := strings.Join(keys, ","); got != "A,B,C,D,exporter,E" {
+		t.Errorf("keys = %s", got)
+	}
+	if c, ok := entries[0].(parser.Comment); !ok || c.Text != "# direnv" {
+		t.Errorf("first entry = %#v, want the comment", entries[0])
+	}
+
+	if _, err := Parse(strings.NewReader("A=ok\nB='open\n")); err == nil || !strings.Contains(err.Error(), "line 2: unclosed single quote") {
+		t.Errorf("Parse() error = %v, want an unclosed quote on line 2", err)
+	}
+}
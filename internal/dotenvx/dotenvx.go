@@ -0,0 +1,136 @@
+// Package dotenvx reads and writes values encrypted the way dotenvx
+// (https://dotenvx.com) does: "encrypted:" followed by the base64 of an
+// ECIES message for the secp256k1 public key stored in the file as
+// DOTENV_PUBLIC_KEY. The matching private key is DOTENV_PRIVATE_KEY, set
+// in the environment or kept in a .env.keys file next to the env file.
+// Files for other environments use suffixed names, e.g.
+// DOTENV_PUBLIC_KEY_PRODUCTION for .env.production.
+package dotenvx
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// Prefix starts every encrypted value.
+const Prefix = "encrypted:"
+
+// Key name conventions.
+const (
+	PublicKeyName  = "DOTENV_PUBLIC_KEY"
+	PrivateKeyName = "DOTENV_PRIVATE_KEY"
+	// KeysFile holds the private keys of the env files in its directory.
+	// It must never be committed.
+	KeysFile = ".env.keys"
+)
+
+// IsEncrypted reports whether value is a dotenvx encrypted value.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// IsPublicKey reports whether key names a dotenvx public key, which is
+// safe to share and is kept as it is.
+func IsPublicKey(key string) bool {
+	return key == PublicKeyName || strings.HasPrefix(key, PublicKeyName+"_")
+}
+
+// KeyNames returns the public and private key names dotenvx uses for the
+// env file at path: DOTENV_PUBLIC_KEY and DOTENV_PRIVATE_KEY for .env, and
+// names suffixed with the upper-cased environment for the others, from at
+// most two name parts, e.g. _PRODUCTION_LOCAL for .env.production.local.
+func KeyNames(path string) (public, private string) {
+	parts := strings.Split(filepath.Base(path), ".")
+	public, private = PublicKeyName, PrivateKeyName
+	if len(parts) > 2 {
+		env := parts[2:]
+		if len(env) > 2 {
+			env = env[:2]
+		}
+		suffix := "_" + strings.ToUpper(strings.Join(env, "_"))
+		public, private = public+suffix, private+suffix
+	}
+	return public, private
+}
+
+// KeysPath returns the path of the .env.keys file for the env file at path.
+func KeysPath(path string) string {
+	return filepath.Join(filepath.Dir(path), KeysFile)
+}
+
+// Lookup returns the value of key in entries.
+func Lookup(entries []parser.Entry, key string) (string, bool) {
+	value, found := "", false
+	for _, entry := range entries {
+		if kv, ok := entry.(parser.KeyValue); ok && kv.Key == key {
+			value, found = kv.Value, true
+		}
+	}
+	return value, found
+}
+
+// DecryptEntries returns entries with the encrypted values of keys, or of
+// every key when keys is empty, decrypted with privateKey, and the keys it
+// decrypted.
+func DecryptEntries(entries []parser.Entry, privateKey string, keys []string) ([]parser.Entry, []string, error) {
+	result := make([]parser.Entry, len(entries))
+	var decrypted []string
+	for i, entry := range entries {
+		result[i] = entry
+		kv, ok := entry.(parser.KeyValue)
+		if !ok || !IsEncrypted(kv.Value) || (len(keys) > 0 && !slices.Contains(keys, kv.Key)) {
+			continue
+		}
+		value, err := Decrypt(privateKey, kv.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decrypt %s: %w", kv.Key, err)
+		}
+		kv.Value, kv.Quoted = value, quoteFor(value)
+		result[i] = kv
+		decrypted = append(decrypted, kv.Key)
+	}
+	return result, decrypted, nil
+}
+
+// EncryptEntries returns entries with the values of keys, or of every key
+// when keys is empty, encrypted for publicKey, and the keys it encrypted.
+// Values already encrypted, empty values and public keys are left as they
+// are.
+func EncryptEntries(entries []parser.Entry, publicKey string, keys []string) ([]parser.Entry, []string, error) {
+	result := make([]parser.Entry, len(entries))
+	var encrypted []string
+	for i, entry := range entries {
+		result[i] = entry
+		kv, ok := entry.(parser.KeyValue)
+		if !ok || kv.Value == "" || IsEncrypted(kv.Value) || IsPublicKey(kv.Key) || (len(keys) > 0 && !slices.Contains(keys, kv.Key)) {
+			continue
+		}
+		value, err := Encrypt(publicKey, kv.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encrypt %s: %w", kv.Key, err)
+		}
+		kv.Value, kv.Quoted = value, `"`
+		result[i] = kv
+		encrypted = append(encrypted, kv.Key)
+	}
+	return result, encrypted, nil
+}
+
+// quoteFor picks quotes that keep a decrypted value intact.
+func quoteFor(value string) string {
+	switch {
+	case !strings.ContainsAny(value, "\"\\\n"):
+		if strings.ContainsAny(value, " \t#'$") {
+			return `"`
+		}
+		return ""
+	case !strings.Contains(value, "'"):
+		return "'"
+	default:
+		return `"`
+	}
+}
@@ -0,0 +1,119 @@
+package dotenvx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// A value encrypted for the key pair below.
+const (
+	testPublicKey  = "031f233cf0ce6e3676382d1ab265fc912e1e40ab911d7fd247706b8a50ef271a37"
+	testPrivateKey = "765c46f29b40e6ab9fa2b3090de228b6ee29cebf96954fddbd5d59f834ae1b4a"
+	testEncrypted  = "encrypted:BM6Mq03riTfUlcBXa14G8V0MmtprZ+b+2Jg5MAw0+hld0s8WGWB87N+4PPLGwnVBBdczFQhl7lSxqRXaEaGBtIaRrtflDa3kwb8DMeG5VCNEhsxXw5aqofVz1U16KTR1n2GxdvFnund6ktiq"
+)
+
+func TestDecrypt(t *testing.T) {
+	got, err := Decrypt(testPrivateKey, testEncrypted)
+	if err != nil || got != "Hello World" {
+		t.Errorf("Decrypt() = %q, %v; want \"Hello World\"", got, err)
+	}
+	if got, err := Decrypt(testPrivateKey, "plain"); err != nil || got != "plain" {
+		t.Errorf("Decrypt(plain) = %q, %v", got, err)
+	}
+
+	_, other, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+	if _, err := Decrypt(other, testEncrypted); err == nil {
+		t.Error("Decrypt() with the wrong key should fail")
+	}
+	if _, err := Decrypt(testPrivateKey, "encrypted:AAAA"); err == nil {
+		t.Error("Decrypt() of a truncated value should fail")
+	}
+	if _, err := Decrypt("xyz", testEncrypted); err == nil {
+		t.Error("Decrypt() with an invalid key should fail")
+	}
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	public, private, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+	for _, value := range []string{"", "s3cret", "multi\nline with \"quotes\""} {
+		encrypted, err := Encrypt(public, value)
+		if err != nil || !IsEncrypted(encrypted) {
+			t.Fatalf("Encrypt(%q) = %q, %v", value, encrypted, err)
+		}
+		if got, err := Decrypt(private, encrypted); err != nil || got != value {
+			t.Errorf("Decrypt(Encrypt(%q)) = %q, %v", value, got, err)
+		}
+	}
+	if got, err := PublicKey(private); err != nil || got != public {
+		t.Errorf("PublicKey() = %q, %v; want %q", got, err, public)
+	}
+	if _, err := Encrypt("02abcd", "x"); err == nil {
+		t.Error("Encrypt() with an invalid public key should fail")
+	}
+}
+
+func TestKeyNames(t *testing.T) {
+	tests := []struct{ path, public, private string }{
+		{".env", "DOTENV_PUBLIC_KEY", "DOTENV_PRIVATE_KEY"},
+		{"api/.env.production", "DOTENV_PUBLIC_KEY_PRODUCTION", "DOTENV_PRIVATE_KEY_PRODUCTION"},
+		{".env.production.local", "DOTENV_PUBLIC_KEY_PRODUCTION_LOCAL", "DOTENV_PRIVATE_KEY_PRODUCTION_LOCAL"},
+		{".env.a.b.c", "DOTENV_PUBLIC_KEY_A_B", "DOTENV_PRIVATE_KEY_A_B"},
+	}
+	for _, tt := range tests {
+		public, private := KeyNames(tt.path)
+		if public != tt.public || private != tt.private {
+			t.Errorf("KeyNames(%q) = %s, %s; want %s, %s", tt.path, public, private, tt.public, tt.private)
+		}
+	}
+	if !IsPublicKey("DOTENV_PUBLIC_KEY_CI") || IsPublicKey("DOTENV_PUBLIC_KEYS") {
+		t.Error("IsPublicKey() should match DOTENV_PUBLIC_KEY and its suffixed names only")
+	}
+}
+
+func TestEncryptDecryptEntries(t *testing.T) {
+	public, private, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+	entries, err := parser.Parse(strings.NewReader("DOTENV_PUBLIC_KEY=\"" + public + "\"\n# db\nDB_PASS=hunter 2\nPORT=3000\nEMPTY=\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	encrypted, keys, err := EncryptEntries(entries, public, []string{"DB_PASS", "EMPTY"})
+	if err != nil || strings.Join(keys, ",") != "DB_PASS" {
+		t.Fatalf("EncryptEntries() keys = %v, %v; want [DB_PASS]", keys, err)
+	}
+	if v, _ := Lookup(encrypted, "DB_PASS"); !IsEncrypted(v) {
+		t.Errorf("DB_PASS = %q, want it encrypted", v)
+	}
+	if v, _ := Lookup(encrypted, "PORT"); v != "3000" {
+		t.Errorf("PORT = %q, only the keys asked for should be encrypted", v)
+	}
+
+	all, keys, err := EncryptEntries(encrypted, public, nil)
+	if err != nil || strings.Join(keys, ",") != "PORT" {
+		t.Errorf("EncryptEntries(all) keys = %v, %v; want [PORT]", keys, err)
+	}
+
+	decrypted, keys, err := DecryptEntries(all, private, nil)
+	if err != nil || strings.Join(keys, ",") != "DB_PASS,PORT" {
+		t.Fatalf("DecryptEntries() keys = %v, %v", keys, err)
+	}
+	var out strings.Builder
+	if err := parser.Write(&out, decrypted); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := "DOTENV_PUBLIC_KEY=\"" + public + "\"\n# db\nDB_PASS=\"hunter 2\"\nPORT=3000\nEMPTY=\n"
+	if out.String() != want {
+		t.Errorf("decrypted file =\n%q\nwant:\n%q", out.String(), want)
+	}
+}
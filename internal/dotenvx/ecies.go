@@ -0,0 +1,151 @@
+package dotenvx
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Layout of an encrypted message, as written by eciesjs with its default
+// settings, which dotenvx uses: the sender's ephemeral public key
+// (uncompressed), then the AES-256-GCM nonce, tag and ciphertext.
+const (
+	ephemeralKeyLen = 65
+	nonceLen        = 16
+	tagLen          = 16
+)
+
+// GenerateKeyPair returns a new key pair as dotenvx stores it: the
+// compressed public key and the private key, both hex encoded.
+func GenerateKeyPair() (public, private string, err error) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return hex.EncodeToString(key.PubKey().SerializeCompressed()), hex.EncodeToString(key.Serialize()), nil
+}
+
+// PublicKey returns the hex encoded compressed public key of the hex
+// encoded privateKey.
+func PublicKey(privateKey string) (string, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key.PubKey().SerializeCompressed()), nil
+}
+
+// Encrypt encrypts value for the hex encoded publicKey and returns it with
+// the "encrypted:" prefix.
+func Encrypt(publicKey, value string) (string, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	receiver, err := secp256k1.ParsePubKey(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	ephemeral, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	sender := ephemeral.PubKey().SerializeUncompressed()
+	gcm, err := newGCM(sharedKey(sender, ephemeral, receiver))
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, nonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nil, nonce, []byte(value), nil)
+	ciphertext, tag := sealed[:len(sealed)-tagLen], sealed[len(sealed)-tagLen:]
+
+	message := make([]byte, 0, len(sender)+nonceLen+len(sealed))
+	message = append(message, sender...)
+	message = append(message, nonce...)
+	message = append(message, tag...)
+	message = append(message, ciphertext...)
+	return Prefix + base64.StdEncoding.EncodeToString(message), nil
+}
+
+// Decrypt decrypts an encrypted value with the hex encoded privateKey.
+// Values without the "encrypted:" prefix are returned as they are.
+func Decrypt(privateKey, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	receiver, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	message, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	if len(message) < ephemeralKeyLen+nonceLen+tagLen {
+		return "", errors.New("invalid encrypted value: too short")
+	}
+	sender := message[:ephemeralKeyLen]
+	senderKey, err := secp256k1.ParsePubKey(sender)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	nonce := message[ephemeralKeyLen : ephemeralKeyLen+nonceLen]
+	tag := message[ephemeralKeyLen+nonceLen : ephemeralKeyLen+nonceLen+tagLen]
+	ciphertext := message[ephemeralKeyLen+nonceLen+tagLen:]
+
+	gcm, err := newGCM(sharedKey(sender, receiver, senderKey))
+	if err != nil {
+		return "", err
+	}
+	plain, err := gcm.Open(nil, nonce, append(append([]byte{}, ciphertext...), tag...), nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt: wrong private key or corrupted value")
+	}
+	return string(plain), nil
+}
+
+func parsePrivateKey(privateKey string) (*secp256k1.PrivateKey, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("invalid private key: want 64 hex characters")
+	}
+	return secp256k1.PrivKeyFromBytes(raw), nil
+}
+
+// sharedKey derives the AES key from the sender's uncompressed public key
+// and the uncompressed ECDH point of priv and pub, with HKDF-SHA256.
+func sharedKey(sender []byte, priv *secp256k1.PrivateKey, pub *secp256k1.PublicKey) []byte {
+	var point, shared secp256k1.JacobianPoint
+	pub.AsJacobian(&point)
+	secp256k1.ScalarMultNonConst(&priv.Key, &point, &shared)
+	shared.ToAffine()
+	master := append(append([]byte{}, sender...), secp256k1.NewPublicKey(&shared.X, &shared.Y).SerializeUncompressed()...)
+	key, _ := hkdf.Key(sha256.New, master, nil, "", 32)
+	return key
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, nonceLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return gcm, nil
+}
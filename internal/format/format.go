@@ -0,0 +1,176 @@
+// Package format normalizes the layout of env files: spacing around "=",
+// blank lines, quoting and, optionally, key order. Formatting is
+// idempotent, so formatted files are left unchanged by another run.
+package format
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// QuoteStyle selects how values are quoted.
+type QuoteStyle string
+
+// Quote styles.
+const (
+	// QuotePreserve keeps each value quoted as it is.
+	QuotePreserve QuoteStyle = "preserve"
+	// QuoteMinimal quotes only values that need it: quotes are dropped
+	// where they make no difference and added around unquoted spaces.
+	QuoteMinimal QuoteStyle = "minimal"
+	// QuoteDouble double-quotes every value it safely can.
+	QuoteDouble QuoteStyle = "double"
+)
+
+// ParseQuoteStyle validates a quote style name. An empty name selects
+// QuotePreserve.
+func ParseQuoteStyle(name string) (QuoteStyle, error) {
+	switch style := QuoteStyle(strings.ToLower(strings.TrimSpace(name))); style {
+	case "":
+		return QuotePreserve, nil
+	case QuotePreserve, QuoteMinimal, QuoteDouble:
+		return style, nil
+	default:
+		return QuotePreserve, fmt.Errorf("unknown quote style %q (use %s, %s or %s)", name, QuotePreserve, QuoteMinimal, QuoteDouble)
+	}
+}
+
+// Options configures Format.
+type Options struct {
+	Quotes QuoteStyle
+	// Sort orders keys alphabetically within each section.
+	Sort bool
+}
+
+// Format returns entries with the spaces around "=" removed, runs of blank
+// lines collapsed to one, leading and trailing blank lines dropped, values
+// quoted per opts.Quotes and, with opts.Sort, keys sorted within sections.
+func Format(entries []parser.Entry, opts Options) []parser.Entry {
+	var result []parser.Entry
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case parser.BlankLine:
+			if len(result) == 0 || isBlank(result[len(result)-1]) || isEncoding(result[len(result)-1]) {
+				continue
+			}
+		case parser.KeyValue:
+			entry = quote(trimValue(e), opts.Quotes)
+		}
+		result = append(result, entry)
+	}
+	for len(result) > 0 && isBlank(result[len(result)-1]) {
+		result = result[:len(result)-1]
+	}
+	if opts.Sort {
+		result = sortSections(result)
+	}
+	return result
+}
+
+// trimValue drops the spaces a "KEY = value" line leaves around an
+// unquoted value, which may turn out to be quoted once they are gone.
+func trimValue(kv parser.KeyValue) parser.KeyValue {
+	if kv.Quoted != "" {
+		return kv
+	}
+	kv.Value = strings.TrimSpace(kv.Value)
+	if v := kv.Value; len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		kv.Quoted, kv.Value = v[:1], v[1:len(v)-1]
+	}
+	return kv
+}
+
+// quote requotes kv per style. Values whose meaning could change are left
+// alone: multiline values, single-quoted values holding "$" or "\" (which
+// single quotes keep literal), and values that contain the quote to add.
+func quote(kv parser.KeyValue, style QuoteStyle) parser.KeyValue {
+	if strings.Contains(kv.Value, "\n") {
+		return kv
+	}
+	literal := kv.Quoted == "'" && strings.ContainsAny(kv.Value, "$\\`")
+	switch style {
+	case QuoteMinimal:
+		switch {
+		case kv.Quoted != "" && !literal && !needsQuotes(kv.Value):
+			kv.Quoted = ""
+		case kv.Quoted == "" && strings.ContainsAny(kv.Value, " \t") && canDoubleQuote(kv.Value):
+			kv.Quoted = `"`
+		}
+	case QuoteDouble:
+		switch {
+		case kv.Quoted == "'" && !literal && !strings.Contains(kv.Value, `"`):
+			kv.Quoted = `"`
+		case kv.Quoted == "" && canDoubleQuote(kv.Value):
+			kv.Quoted = `"`
+		}
+	}
+	return kv
+}
+
+// needsQuotes reports whether a value would read differently unquoted.
+func needsQuotes(value string) bool {
+	return strings.ContainsAny(value, " \t#'\"\\$`")
+}
+
+// canDoubleQuote reports whether an unquoted value means the same inside
+// double quotes. " #" may start an inline comment, which quotes would turn
+// into part of the value.
+func canDoubleQuote(value string) bool {
+	return !strings.ContainsAny(value, `"\`) && !strings.Contains(value, " #")
+}
+
+// sortSections sorts the keys of each section, a run of lines between
+// blank lines. Comments directly above a key move with it, except those
+// opening the section, which stay as its header, and any after the last
+// key. Sorting is stable, so a key set twice keeps its last value.
+func sortSections(entries []parser.Entry) []parser.Entry {
+	type block struct {
+		key   string
+		lines []parser.Entry
+	}
+	result := make([]parser.Entry, 0, len(entries))
+	var blocks []block
+	var pending []parser.Entry
+	flush := func() {
+		slices.SortStableFunc(blocks, func(a, b block) int { return strings.Compare(a.key, b.key) })
+		for _, b := range blocks {
+			result = append(result, b.lines...)
+		}
+		result = append(result, pending...)
+		blocks, pending = nil, nil
+	}
+
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case parser.BlankLine:
+			flush()
+			result = append(result, e)
+		case parser.Encoding:
+			result = append(result, e)
+		case parser.Comment:
+			if len(blocks) == 0 {
+				result = append(result, e) // section header
+				continue
+			}
+			pending = append(pending, e)
+		case parser.KeyValue:
+			blocks = append(blocks, block{key: e.Key, lines: append(pending, e)})
+			pending = nil
+		}
+	}
+	flush()
+	return result
+}
+
+func isBlank(entry parser.Entry) bool {
+	_, ok := entry.(parser.BlankLine)
+	return ok
+}
+
+func isEncoding(entry parser.Entry) bool {
+	_, ok := entry.(parser.Encoding)
+	return ok
+}
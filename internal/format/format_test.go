@@ -0,0 +1,95 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func formatString(t *testing.T, input string, opts Options) string {
+	t.Helper()
+	entries, err := parser.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var b strings.Builder
+	if err := parser.Write(&b, Format(entries, opts)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return b.String()
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  Options
+		want  string
+	}{
+		{
+			name:  "spacing and blank lines",
+			input: "\n\nHOST = localhost\nNAME =  \"my app\"\n\n\n\nexport PORT= 3000\n\n",
+			want:  "HOST=localhost\nNAME=\"my app\"\n\nexport PORT=3000\n",
+		},
+		{
+			name:  "byte order mark kept",
+			input: "\xef\xbb\xbf\n\nB=2\nA=1\n",
+			opts:  Options{Sort: true},
+			want:  "\xef\xbb\xbfA=1\nB=2\n",
+		},
+		{
+			name:  "preserve keeps quotes",
+			input: "A=\"plain\"\nB='x'\nC=two words\n",
+			want:  "A=\"plain\"\nB='x'\nC=two words\n",
+		},
+		{
+			name:  "minimal quotes",
+			input: "A=\"plain\"\nB='x'\nC=two words\nD='$HOME'\nE=\"a#b\"\nF=x # note\nG=\"\"\n",
+			opts:  Options{Quotes: QuoteMinimal},
+			want:  "A=plain\nB=x\nC=\"two words\"\nD='$HOME'\nE=\"a#b\"\nF=x # note\nG=\n",
+		},
+		{
+			name:  "double quotes",
+			input: "A=plain\nB='x'\nC='$HOME'\nD=say \"hi\"\nE=\n",
+			opts:  Options{Quotes: QuoteDouble},
+			want:  "A=\"plain\"\nB=\"x\"\nC='$HOME'\nD=say \"hi\"\nE=\"\"\n",
+		},
+		{
+			name:  "sort within sections",
+			input: "# Web\nPORT=3000\n# public URL\nHOST=example.com\n\n# Database\nDB_USER=app\nDB_HOST=db\n# end of database\n",
+			opts:  Options{Sort: true},
+			want:  "# Web\n# public URL\nHOST=example.com\nPORT=3000\n\n# Database\nDB_HOST=db\nDB_USER=app\n# end of database\n",
+		},
+		{
+			name:  "multiline values untouched",
+			input: "CERT=\"line one\nline two\"\n",
+			opts:  Options{Quotes: QuoteMinimal},
+			want:  "CERT=\"line one\nline two\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatString(t, tt.input, tt.opts)
+			if got != tt.want {
+				t.Errorf("Format() =\n%q\nwant:\n%q", got, tt.want)
+			}
+			if again := formatString(t, got, tt.opts); again != got {
+				t.Errorf("Format() is not idempotent:\n%q\nthen:\n%q", got, again)
+			}
+		})
+	}
+}
+
+func TestParseQuoteStyle(t *testing.T) {
+	if style, err := ParseQuoteStyle(""); err != nil || style != QuotePreserve {
+		t.Errorf("ParseQuoteStyle(\"\") = %q, %v", style, err)
+	}
+	if style, err := ParseQuoteStyle("Minimal"); err != nil || style != QuoteMinimal {
+		t.Errorf("ParseQuoteStyle(\"Minimal\") = %q, %v", style, err)
+	}
+	if _, err := ParseQuoteStyle("single"); err == nil {
+		t.Error("ParseQuoteStyle(\"single\") should fail")
+	}
+}
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// ChangelogPrefix starts the comment block WithChangelog puts at the top of
+// a regenerated example, followed by the day of the regeneration.
+const ChangelogPrefix = "# dotenv-tui changelog"
+
+// changelogItem starts the lines of a changelog block listing keys.
+const changelogItem = "#   "
+
+// WithChangelog puts a dated comment block at the top of generated, the new
+// entries of an example, listing the keys added and removed since previous,
+// the entries it replaces, e.g.
+//
+//	# dotenv-tui changelog 2026-06-01
+//	#   added: SENTRY_DSN
+//	#   removed: LEGACY_TOKEN
+//
+// A block left by an earlier regeneration is replaced, or kept when no key
+// was added or removed. Without previous, a new example, no block is added.
+// A zero day leaves the date out of the block.
+func WithChangelog(previous, generated []parser.Entry, on time.Time) []parser.Entry {
+	oldBlock, previous := splitChangelog(previous)
+	_, generated = splitChangelog(generated)
+	if previous == nil && oldBlock == nil {
+		return generated
+	}
+
+	added, removed := keyChanges(previous, generated)
+	var block []parser.Entry
+	switch {
+	case len(added) > 0 || len(removed) > 0:
+		heading := ChangelogPrefix
+		if !on.IsZero() {
+			heading += " " + on.Format(time.DateOnly)
+		}
+		block = append(block, parser.Comment{Text: heading})
+		if len(added) > 0 {
+			block = append(block, parser.Comment{Text: changelogItem + "added: " + strings.Join(added, ", ")})
+		}
+		if len(removed) > 0 {
+			block = append(block, parser.Comment{Text: changelogItem + "removed: " + strings.Join(removed, ", ")})
+		}
+		block = append(block, parser.BlankLine{})
+	case oldBlock != nil:
+		block = oldBlock
+	default:
+		return generated
+	}
+
+	result := make([]parser.Entry, 0, len(block)+len(generated))
+	result = append(result, block...)
+	return append(result, generated...)
+}
+
+// splitChangelog separates the changelog block at the top of entries, with
+// the blank line after it, from the rest. block is nil when there is none.
+func splitChangelog(entries []parser.Entry) (block, rest []parser.Entry) {
+	if len(entries) == 0 {
+		return nil, entries
+	}
+	if c, ok := entries[0].(parser.Comment); !ok || !strings.HasPrefix(c.Text, ChangelogPrefix) {
+		return nil, entries
+	}
+	n := 1
+	for n < len(entries) {
+		c, ok := entries[n].(parser.Comment)
+		if !ok || !strings.HasPrefix(c.Text, changelogItem) {
+			break
+		}
+		n++
+	}
+	if n < len(entries) {
+		if _, ok := entries[n].(parser.BlankLine); ok {
+			n++
+		}
+	}
+	return entries[:n], entries[n:]
+}
+
+// keyChanges returns the keys of generated missing from previous, in
+// generated's order, and the keys of previous missing from generated, in
+// previous's order.
+func keyChanges(previous, generated []parser.Entry) (added, removed []string) {
+	before := entryKeys(previous)
+	after := entryKeys(generated)
+	for _, entry := range generated {
+		if kv, ok := entry.(parser.KeyValue); ok && !before[kv.Key] && !slices.Contains(added, kv.Key) {
+			added = append(added, kv.Key)
+		}
+	}
+	for _, entry := range previous {
+		if kv, ok := entry.(parser.KeyValue); ok && !after[kv.Key] && !slices.Contains(removed, kv.Key) {
+			removed = append(removed, kv.Key)
+		}
+	}
+	return added, removed
+}
+
+func entryKeys(entries []parser.Entry) map[string]bool {
+	keys := make(map[string]bool)
+	for _, entry := range entries {
+		if kv, ok := entry.(parser.KeyValue); ok {
+			keys[kv.Key] = true
+		}
+	}
+	return keys
+}
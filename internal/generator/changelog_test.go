@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func TestWithChangelog(t *testing.T) {
+	on := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		previous  string
+		generated string
+		want      string
+	}{
+		{
+			name:      "new example",
+			generated: "PORT=3000\n",
+			want:      "PORT=3000\n",
+		},
+		{
+			name:      "added and removed keys",
+			previous:  "PORT=3000\nOLD_KEY=***\n",
+			generated: "PORT=3000\nAPI_KEY=***\nDB_URL=postgres://***\n",
+			want:      "# dotenv-tui changelog 2026-10-15\n#   added: API_KEY, DB_URL\n#   removed: OLD_KEY\n\nPORT=3000\nAPI_KEY=***\nDB_URL=postgres://***\n",
+		},
+		{
+			name:      "only removed keys",
+			previous:  "PORT=3000\nOLD_KEY=***\n",
+			generated: "PORT=3000\n",
+			want:      "# dotenv-tui changelog 2026-10-15\n#   removed: OLD_KEY\n\nPORT=3000\n",
+		},
+		{
+			name:      "replaces an earlier block",
+			previous:  "# dotenv-tui changelog 2026-01-02\n#   added: PORT\n\nPORT=3000\n",
+			generated: "PORT=3000\nAPI_KEY=***\n",
+			want:      "# dotenv-tui changelog 2026-10-15\n#   added: API_KEY\n\nPORT=3000\nAPI_KEY=***\n",
+		},
+		{
+			name:      "keeps an earlier block without changes",
+			previous:  "# dotenv-tui changelog 2026-01-02\n#   added: PORT\n\nPORT=3000\n",
+			generated: "PORT=3000\n",
+			want:      "# dotenv-tui changelog 2026-01-02\n#   added: PORT\n\nPORT=3000\n",
+		},
+		{
+			name:      "no block without changes",
+			previous:  "# App\nPORT=1\n",
+			generated: "# App\nPORT=3000\n",
+			want:      "# App\nPORT=3000\n",
+		},
+		{
+			name:      "drops a block copied into the source",
+			previous:  "PORT=3000\n",
+			generated: "# dotenv-tui changelog 2026-01-02\n#   added: PORT\n\nPORT=3000\n",
+			want:      "PORT=3000\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var previous []parser.Entry
+			if tt.previous != "" {
+				previous = parseEntries(t, tt.previous)
+			}
+			var buf bytes.Buffer
+			if err := parser.Write(&buf, WithChangelog(previous, parseEntries(t, tt.generated), on)); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WithChangelog() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithChangelogUndated(t *testing.T) {
+	previous := parseEntries(t, "# dotenv-tui changelog\n#   added: PORT\n\nPORT=3000\n")
+	var buf bytes.Buffer
+	if err := parser.Write(&buf, WithChangelog(previous, parseEntries(t, "PORT=3000\nAPI_KEY=***\n"), time.Time{})); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := "# dotenv-tui changelog\n#   added: API_KEY\n\nPORT=3000\nAPI_KEY=***\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WithChangelog() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func parseEntries(t *testing.T, input string) []parser.Entry {
+	t.Helper()
+	entries, err := parser.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return entries
+}
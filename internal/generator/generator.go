@@ -8,13 +8,47 @@ import (
 
 // GenerateExample creates a .env.example from .env entries by masking secrets
 func GenerateExample(entries []parser.Entry) []parser.Entry {
+	return GenerateExampleWithStyle(entries, detector.Masking{Style: detector.StyleMask})
+}
+
+// Options configures GenerateExampleWithOptions. The zero value masks
+// secrets like GenerateExample and keeps the original key order.
+type Options struct {
+	Masking       detector.Masking
+	Sort          SortOrder
+	GroupByPrefix bool
+	// Changelog puts a block listing the keys added and removed at the top
+	// of a regenerated example; see WithChangelog.
+	Changelog bool
+}
+
+// GenerateExampleWithOptions masks secrets and then arranges keys as
+// configured by opts.
+func GenerateExampleWithOptions(entries []parser.Entry, opts Options) []parser.Entry {
+	return Arrange(GenerateExampleWithStyle(entries, opts.Masking), opts.Sort, opts.GroupByPrefix)
+}
+
+// GenerateExampleWithStyle is GenerateExample with a choice of placeholder style.
+func GenerateExampleWithStyle(entries []parser.Entry, masking detector.Masking) []parser.Entry {
 	var result []parser.Entry
 
 	for _, entry := range entries {
 		switch e := entry.(type) {
 		case parser.KeyValue:
-			if detector.IsSecret(e.Key, e.Value) {
-				placeholder := detector.GeneratePlaceholder(e.Key, e.Value)
+			if masking.KeepsKey(e.Key) {
+				result = append(result, e)
+			} else if masked, ok := detector.MaskJSON(e.Key, e.Value, masking); ok {
+				// JSON keeps its shape and quotes, with only its secret
+				// fields masked.
+				e.Value = masked
+				result = append(result, e)
+			} else if masked, ok := detector.MaskCompound(e.Key, e.Value, masking); ok {
+				// So do connection strings and token lists, with each
+				// secret part masked on its own.
+				e.Value = masked
+				result = append(result, e)
+			} else if masking.Rules.IsSecret(e.Key, e.Value) {
+				placeholder := masking.Placeholder(e.Key, e.Value)
 				newKV := parser.KeyValue{
 					Key:      e.Key,
 					Value:    placeholder,
@@ -26,7 +60,13 @@ func GenerateExample(entries []parser.Entry) []parser.Entry {
 				result = append(result, e)
 			}
 
-		case parser.Comment, parser.BlankLine:
+		case parser.Comment:
+			// Provenance notes describe this .env's values, not the example's.
+			if !parser.IsProvenance(e) {
+				result = append(result, e)
+			}
+
+		case parser.BlankLine:
 			result = append(result, e)
 		}
 	}
@@ -0,0 +1,178 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// SortOrder selects how keys are ordered in generated examples.
+type SortOrder string
+
+// Sort orders.
+const (
+	// SortNone keeps keys in their original order.
+	SortNone SortOrder = "none"
+	// SortKeys orders keys alphabetically.
+	SortKeys SortOrder = "keys"
+)
+
+// ParseSortOrder validates a sort order name. An empty name selects SortNone.
+func ParseSortOrder(name string) (SortOrder, error) {
+	switch order := SortOrder(strings.ToLower(strings.TrimSpace(name))); order {
+	case "":
+		return SortNone, nil
+	case SortNone, SortKeys:
+		return order, nil
+	default:
+		return SortNone, fmt.Errorf("unknown sort order %q (use %s or %s)", name, SortKeys, SortNone)
+	}
+}
+
+// blockGroup is a run of key blocks written under an optional section comment.
+type blockGroup struct {
+	section string
+	blocks  []keyBlock
+}
+
+// keyBlock is a key together with the comments written directly above it,
+// which move with the key when entries are reordered.
+type keyBlock struct {
+	comments []parser.Entry
+	kv       parser.KeyValue
+}
+
+// Arrange reorders entries by key and, with groupByPrefix, gathers keys that
+// share a prefix such as DB_ or AWS_ under a "# DB" style section comment.
+// Comments directly above a key move with it; a header separated from the
+// first key by a blank line and any comments after the last key stay in
+// place. With SortNone and no grouping, entries are returned unchanged.
+func Arrange(entries []parser.Entry, order SortOrder, groupByPrefix bool) []parser.Entry {
+	if order != SortKeys && !groupByPrefix {
+		return entries
+	}
+
+	header, blocks, trailer := splitBlocks(entries)
+	if order == SortKeys {
+		slices.SortStableFunc(blocks, func(a, b keyBlock) int {
+			return strings.Compare(a.kv.Key, b.kv.Key)
+		})
+	}
+
+	groups := []blockGroup{{blocks: blocks}}
+	if groupByPrefix {
+		groups = groupBlocks(blocks, order)
+	}
+
+	result := append([]parser.Entry{}, header...)
+	for _, group := range groups {
+		if len(result) > 0 {
+			result = append(result, parser.BlankLine{})
+		}
+		if group.section != "" {
+			result = append(result, parser.Comment{Text: group.section})
+		}
+		for _, b := range group.blocks {
+			for _, c := range b.comments {
+				if c, ok := c.(parser.Comment); ok && group.section != "" && c.Text == group.section {
+					continue // written by an earlier run
+				}
+				result = append(result, c)
+			}
+			result = append(result, b.kv)
+		}
+	}
+	if len(trailer) > 0 {
+		result = append(result, parser.BlankLine{})
+		result = append(result, trailer...)
+	}
+	return result
+}
+
+// splitBlocks separates entries into a leading header, key blocks and
+// trailing comments. Blank lines between keys are dropped.
+func splitBlocks(entries []parser.Entry) (header []parser.Entry, blocks []keyBlock, trailer []parser.Entry) {
+	var pending []parser.Entry
+	seenKey := false
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case parser.KeyValue:
+			blocks = append(blocks, keyBlock{comments: pending, kv: e})
+			pending, seenKey = nil, true
+		case parser.Comment:
+			pending = append(pending, e)
+		case parser.BlankLine:
+			if !seenKey && len(pending) > 0 {
+				header = append(header, pending...)
+				header = append(header, e)
+			}
+			pending = nil
+		}
+	}
+	header = trimBlankLines(header)
+	return header, blocks, pending
+}
+
+// groupBlocks gathers blocks by key prefix. Groups appear in the order their
+// prefix is first seen, or alphabetically with SortKeys; keys whose prefix
+// is not shared with another key are collected in a final unlabelled group.
+func groupBlocks(blocks []keyBlock, order SortOrder) []blockGroup {
+	counts := make(map[string]int)
+	for _, b := range blocks {
+		counts[keyPrefix(b.kv.Key)]++
+	}
+
+	var prefixes []string
+	byPrefix := make(map[string][]keyBlock)
+	var ungrouped []keyBlock
+	for _, b := range blocks {
+		prefix := keyPrefix(b.kv.Key)
+		if prefix == "" || counts[prefix] < 2 {
+			ungrouped = append(ungrouped, b)
+			continue
+		}
+		if _, ok := byPrefix[prefix]; !ok {
+			prefixes = append(prefixes, prefix)
+		}
+		byPrefix[prefix] = append(byPrefix[prefix], b)
+	}
+	if order == SortKeys {
+		slices.Sort(prefixes)
+	}
+
+	groups := make([]blockGroup, 0, len(prefixes)+1)
+	for _, prefix := range prefixes {
+		groups = append(groups, blockGroup{section: sectionComment(prefix), blocks: byPrefix[prefix]})
+	}
+	if len(ungrouped) > 0 {
+		groups = append(groups, blockGroup{blocks: ungrouped})
+	}
+	return groups
+}
+
+// sectionComment is the comment written above the keys sharing prefix.
+func sectionComment(prefix string) string {
+	return "# " + strings.TrimSuffix(prefix, "_")
+}
+
+// keyPrefix returns the part of key up to and including its first
+// underscore, e.g. "DB_" for "DB_HOST", or "" if key has no such prefix.
+func keyPrefix(key string) string {
+	i := strings.IndexByte(key, '_')
+	if i <= 0 || i == len(key)-1 {
+		return ""
+	}
+	return key[:i+1]
+}
+
+func trimBlankLines(entries []parser.Entry) []parser.Entry {
+	for len(entries) > 0 {
+		if _, ok := entries[len(entries)-1].(parser.BlankLine); !ok {
+			break
+		}
+		entries = entries[:len(entries)-1]
+	}
+	return entries
+}
@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func arrangeString(t *testing.T, input string, order SortOrder, group bool) string {
+	t.Helper()
+	entries, err := parser.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := parser.Write(&buf, Arrange(entries, order, group)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestArrange(t *testing.T) {
+	input := `# App settings
+
+PORT=3000
+# primary database
+DB_HOST=localhost
+AWS_REGION=us-east-1
+
+DB_NAME=app
+AWS_BUCKET=assets
+DEBUG=true
+# end of file
+`
+
+	tests := []struct {
+		name     string
+		order    SortOrder
+		group    bool
+		expected string
+	}{
+		{
+			name:     "none keeps original",
+			order:    SortNone,
+			expected: input,
+		},
+		{
+			name:  "sort keys",
+			order: SortKeys,
+			expected: `# App settings
+
+AWS_BUCKET=assets
+AWS_REGION=us-east-1
+# primary database
+DB_HOST=localhost
+DB_NAME=app
+DEBUG=true
+PORT=3000
+
+# end of file
+`,
+		},
+		{
+			name:  "group by prefix keeps first-seen order",
+			order: SortNone,
+			group: true,
+			expected: `# App settings
+
+# DB
+# primary database
+DB_HOST=localhost
+DB_NAME=app
+
+# AWS
+AWS_REGION=us-east-1
+AWS_BUCKET=assets
+
+PORT=3000
+DEBUG=true
+
+# end of file
+`,
+		},
+		{
+			name:  "group and sort",
+			order: SortKeys,
+			group: true,
+			expected: `# App settings
+
+# AWS
+AWS_BUCKET=assets
+AWS_REGION=us-east-1
+
+# DB
+# primary database
+DB_HOST=localhost
+DB_NAME=app
+
+DEBUG=true
+PORT=3000
+
+# end of file
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := arrangeString(t, input, tt.order, tt.group); got != tt.expected {
+				t.Errorf("Arrange() =\n%s\nwant\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestArrangeIsIdempotent(t *testing.T) {
+	input := "DB_HOST=localhost\nPORT=3000\nDB_NAME=app\n"
+	once := arrangeString(t, input, SortKeys, true)
+	if twice := arrangeString(t, once, SortKeys, true); twice != once {
+		t.Errorf("second Arrange() changed output:\n%s\nwant\n%s", twice, once)
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	for input, expected := range map[string]SortOrder{"": SortNone, "none": SortNone, "Keys": SortKeys} {
+		got, err := ParseSortOrder(input)
+		if err != nil || got != expected {
+			t.Errorf("ParseSortOrder(%q) = %q, %v; want %q", input, got, err, expected)
+		}
+	}
+	if _, err := ParseSortOrder("values"); err == nil {
+		t.Error("expected error for unknown sort order")
+	}
+}
@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// GenerateTest creates a .env.test from the entries of a .env or a
+// .env.example. Secrets, and the placeholders and empty secret keys an
+// example leaves, get deterministic fake values in the same format (see
+// detector.FakeValue), so test suites that validate formats run without
+// real credentials. Other values, and keys masking keeps unmasked, are
+// copied as they are.
+func GenerateTest(entries []parser.Entry, masking detector.Masking) []parser.Entry {
+	masking.Style = detector.StyleFake
+	var result []parser.Entry
+
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case parser.KeyValue:
+			if masking.KeepsKey(e.Key) {
+				result = append(result, e)
+				continue
+			}
+			if faked, ok := detector.MaskJSON(e.Key, e.Value, masking); ok {
+				e.Value = faked
+			} else if faked, ok := detector.MaskCompound(e.Key, e.Value, masking); ok {
+				e.Value = faked
+			} else if masking.Rules.IsSecret(e.Key, e.Value) || detector.IsPlaceholder(e.Value) || e.Value == "" && masking.Rules.IsSecretKey(e.Key) {
+				e.Value, e.Quoted = detector.FakeValue(e.Key, e.Value), ""
+			}
+			result = append(result, e)
+
+		case parser.Comment:
+			if !parser.IsProvenance(e) {
+				result = append(result, e)
+			}
+
+		case parser.BlankLine:
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,211 @@
+// Package history keeps an append-only log of the env files dotenv-tui
+// writes, so teams can see which keys changed, when, and by whom.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/state"
+)
+
+// FileName is the name of the log inside the state directory.
+const FileName = "history.jsonl"
+
+// Operations recorded in the log.
+const (
+	OpGenerateExample = "generate-example"
+	OpGenerateEnv     = "generate-env"
+	OpGenerateTest    = "generate-test"
+	OpRestore         = "restore"
+	OpRename          = "rename"
+	OpMove            = "move"
+	OpCopy            = "copy"
+	OpFormat          = "format"
+	OpEncrypt         = "encrypt"
+	OpDecrypt         = "decrypt"
+	OpExportDirenv    = "export-direnv"
+	OpPullK8s         = "pull-k8s"
+	OpImport          = "import"
+	OpRemask          = "remask"
+)
+
+// Sources of a write.
+const (
+	SourceCLI = "cli"
+	SourceTUI = "tui"
+)
+
+// Summary lists the keys a write added, removed or changed. Values are
+// never recorded.
+type Summary struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// Record is a single line of the log.
+type Record struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user,omitempty"`
+	Source    string    `json:"source"`
+	Operation string    `json:"operation"`
+	Path      string    `json:"path"`
+	Changes   Summary   `json:"changes"`
+	Backup    string    `json:"backup,omitempty"`
+}
+
+// DefaultPath returns the path of the log in the state directory, or an
+// empty string if the state directory cannot be determined.
+func DefaultPath() string {
+	dir, err := state.Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, FileName)
+}
+
+// mu keeps concurrent writes from interleaving their lines.
+var mu sync.Mutex
+
+// LastChanged returns, for each key of the file at path, the time of the
+// latest record that added or changed it. path is made absolute to match
+// the recorded paths.
+func LastChanged(records []Record, path string) map[string]time.Time {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	changed := make(map[string]time.Time)
+	for _, r := range records {
+		if r.Path != path {
+			continue
+		}
+		for _, keys := range [][]string{r.Changes.Added, r.Changes.Changed} {
+			for _, key := range keys {
+				if r.Time.After(changed[key]) {
+					changed[key] = r.Time
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// Summarize compares the keys of an env file before and after a write.
+func Summarize(before, after []parser.Entry) Summary {
+	old := make(map[string]string)
+	for _, e := range before {
+		if kv, ok := e.(parser.KeyValue); ok {
+			old[kv.Key] = kv.Value
+		}
+	}
+
+	var s Summary
+	seen := make(map[string]bool)
+	for _, e := range after {
+		kv, ok := e.(parser.KeyValue)
+		if !ok || seen[kv.Key] {
+			continue
+		}
+		seen[kv.Key] = true
+		if value, exists := old[kv.Key]; !exists {
+			s.Added = append(s.Added, kv.Key)
+		} else if value != kv.Value {
+			s.Changed = append(s.Changed, kv.Key)
+		}
+	}
+	for _, e := range before {
+		if kv, ok := e.(parser.KeyValue); ok && !seen[kv.Key] {
+			seen[kv.Key] = true
+			s.Removed = append(s.Removed, kv.Key)
+		}
+	}
+	return s
+}
+
+// Append adds r to the log at logPath, filling in the time, the current
+// user and an absolute path. It does nothing when logPath is empty, which
+// turns logging off. Callers treat a failure as a warning: the write being
+// logged has already happened.
+func Append(logPath string, r Record) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if logPath == "" {
+		return nil
+	}
+
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	if r.User == "" {
+		if u, err := user.Current(); err == nil {
+			r.User = u.Username
+		}
+	}
+	if abs, err := filepath.Abs(r.Path); err == nil {
+		r.Path = abs
+	}
+	if r.Backup != "" {
+		if abs, err := filepath.Abs(r.Backup); err == nil {
+			r.Backup = abs
+		}
+	}
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close history: %w", err)
+	}
+	return nil
+}
+
+// Load reads every record from the log at path, oldest first. A missing
+// log yields no records.
+func Load(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var records []Record
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("failed to parse history line %d: %w", n, err)
+		}
+		records = append(records, r)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,108 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func TestSummarize(t *testing.T) {
+	before := []parser.Entry{
+		parser.KeyValue{Key: "KEEP", Value: "1"},
+		parser.KeyValue{Key: "EDIT", Value: "old"},
+		parser.KeyValue{Key: "DROP", Value: "x"},
+	}
+	after := []parser.Entry{
+		parser.Comment{Text: "# app"},
+		parser.KeyValue{Key: "KEEP", Value: "1"},
+		parser.KeyValue{Key: "EDIT", Value: "new"},
+		parser.KeyValue{Key: "NEW", Value: "y"},
+	}
+
+	got := Summarize(before, after)
+
+	want := Summary{Added: []string{"NEW"}, Removed: []string{"DROP"}, Changed: []string{"EDIT"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", FileName)
+	for _, op := range []string{OpGenerateEnv, OpRestore} {
+		err := Append(path, Record{Source: SourceCLI, Operation: op, Path: ".env", Changes: Summary{Added: []string{"KEY"}}})
+		if err != nil {
+			t.Fatalf("Append() unexpected error: %v", err)
+		}
+	}
+
+	records, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if len(records) != 2 || records[0].Operation != OpGenerateEnv || records[1].Operation != OpRestore {
+		t.Fatalf("Load() = %+v, want both records in order", records)
+	}
+	r := records[0]
+	if r.Time.IsZero() || !filepath.IsAbs(r.Path) || r.Changes.Added[0] != "KEY" {
+		t.Errorf("record = %+v, want time, absolute path and changes filled in", r)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm != 0600 {
+		t.Errorf("history mode = %v, want 0600", perm)
+	}
+}
+
+func TestLastChanged(t *testing.T) {
+	abs, err := filepath.Abs(".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	records := []Record{
+		{Time: jan, Path: abs, Changes: Summary{Added: []string{"API_KEY", "PORT"}}},
+		{Time: feb, Path: abs, Changes: Summary{Changed: []string{"API_KEY"}, Removed: []string{"PORT"}}},
+		{Time: feb, Path: "/elsewhere/.env", Changes: Summary{Changed: []string{"PORT"}}},
+	}
+
+	got := LastChanged(records, ".env")
+
+	want := map[string]time.Time{"API_KEY": feb, "PORT": jan}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LastChanged() = %v, want %v", got, want)
+	}
+}
+
+func TestAppendDisabled(t *testing.T) {
+	if err := Append("", Record{Operation: OpGenerateEnv, Path: ".env"}); err != nil {
+		t.Errorf("Append() with logging off unexpected error: %v", err)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	records, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil || records != nil {
+		t.Errorf("Load(missing) = %v, %v; want no records", records, err)
+	}
+}
+
+func TestLoadRejectsCorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("{\"operation\":\"restore\"}\nnot json\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Load() error = %v, want it to name line 2", err)
+	}
+}
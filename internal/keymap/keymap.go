@@ -0,0 +1,341 @@
+// Package keymap defines the keybindings used by every TUI screen and lets
+// users override them from the project config file.
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Menu holds the main menu bindings.
+type Menu struct {
+	Up       key.Binding
+	Down     key.Binding
+	Backup   key.Binding
+	Settings key.Binding
+	Select   key.Binding
+	Quit     key.Binding
+}
+
+// Picker holds the file picker bindings.
+type Picker struct {
+	Up       key.Binding
+	Down     key.Binding
+	Collapse key.Binding
+	Expand   key.Binding
+	Toggle   key.Binding
+	All      key.Binding
+	Pattern  key.Binding
+	Confirm  key.Binding
+	Back     key.Binding
+	Quit     key.Binding
+	// Cancel closes the pattern prompt, where Back's "q" is typed instead.
+	Cancel key.Binding
+}
+
+// Preview holds the .env.example preview bindings.
+type Preview struct {
+	Up       key.Binding
+	Down     key.Binding
+	NextFile key.Binding
+	PrevFile key.Binding
+	Left     key.Binding
+	Right    key.Binding
+	Write    key.Binding
+	Backup   key.Binding
+	Editor   key.Binding
+	Cancel   key.Binding
+	Done     key.Binding
+}
+
+// Form holds the .env form bindings.
+type Form struct {
+	Up      key.Binding
+	Down    key.Binding
+	Next    key.Binding
+	Prev    key.Binding
+	Submit  key.Binding
+	Save    key.Binding
+	Cancel  key.Binding
+	Help    key.Binding
+	Done    key.Binding
+	Undo    key.Binding
+	Redo    key.Binding
+	Restore key.Binding
+	Backup  key.Binding
+	Source  key.Binding
+	// FirstError moves to the first field with an invalid value.
+	FirstError key.Binding
+	// Multiline opens the multiline editor on a field, and applies its
+	// changes when pressed again.
+	Multiline key.Binding
+	// Editor opens the values in $EDITOR.
+	Editor key.Binding
+	// Reveal shows a secret field's value until the cursor leaves it.
+	Reveal key.Binding
+}
+
+// Compare holds the bindings of the screen comparing two env files. Confirm
+// and Cancel apply while the patch path is being typed.
+type Compare struct {
+	Up       key.Binding
+	Down     key.Binding
+	NextDiff key.Binding
+	PrevDiff key.Binding
+	Filter   key.Binding
+	Reveal   key.Binding
+	Export   key.Binding
+	Confirm  key.Binding
+	Cancel   key.Binding
+	Back     key.Binding
+}
+
+// Move holds the bindings of the screen moving keys between env files.
+// Confirm and Cancel apply while the destination path is being typed.
+type Move struct {
+	Up      key.Binding
+	Down    key.Binding
+	Toggle  key.Binding
+	All     key.Binding
+	Copy    key.Binding
+	Next    key.Binding
+	Confirm key.Binding
+	Cancel  key.Binding
+	Back    key.Binding
+}
+
+// Settings holds the settings screen bindings. Confirm and Cancel apply
+// while a text setting is being edited.
+type Settings struct {
+	Up      key.Binding
+	Down    key.Binding
+	Change  key.Binding
+	Prev    key.Binding
+	Confirm key.Binding
+	Cancel  key.Binding
+	Back    key.Binding
+}
+
+// KeyMap is the full set of bindings for the application.
+type KeyMap struct {
+	Help key.Binding
+	// Interrupt quits from any screen, asking first if there are unsaved
+	// edits.
+	Interrupt key.Binding
+	Menu      Menu
+	Picker    Picker
+	Preview   Preview
+	Form      Form
+	Compare   Compare
+	Move      Move
+	Settings  Settings
+}
+
+// Default returns the built-in keymap.
+func Default() KeyMap {
+	return KeyMap{
+		Help:      key.NewBinding(key.WithKeys("?", "f1"), key.WithHelp("?", "help")),
+		Interrupt: key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("Ctrl+C", "quit")),
+		Menu: Menu{
+			Up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			Down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+			Backup:   key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "toggle backup")),
+			Settings: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "settings")),
+			Select:   key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("Enter", "select")),
+			Quit:     key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+		},
+		Picker: Picker{
+			Up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			Down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+			Collapse: key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "collapse")),
+			Expand:   key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "expand")),
+			Toggle:   key.NewBinding(key.WithKeys(" "), key.WithHelp("Space", "toggle")),
+			All:      key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "all")),
+			Pattern:  key.NewBinding(key.WithKeys("*"), key.WithHelp("*", "select by pattern")),
+			Confirm:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "confirm")),
+			Back:     key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q", "back")),
+			Quit:     key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("Ctrl+C", "quit")),
+			Cancel:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel")),
+		},
+		Preview: Preview{
+			Up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			Down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+			NextFile: key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "next file")),
+			PrevFile: key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("Shift+Tab", "prev file")),
+			Left:     key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "scroll left")),
+			Right:    key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "scroll right")),
+			Write:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "write all")),
+			Backup:   key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "toggle backup for this file")),
+			Editor:   key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit in $EDITOR")),
+			Cancel:   key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q/Esc", "cancel")),
+			Done:     key.NewBinding(key.WithKeys("enter", "q", "esc"), key.WithHelp("Enter", "return to menu")),
+		},
+		Form: Form{
+			Up:     key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "up")),
+			Down:   key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "down")),
+			Next:   key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "next")),
+			Prev:   key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("Shift+Tab", "prev")),
+			Submit: key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "next/submit")),
+			Save:   key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("Ctrl+S", "save")),
+			Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel")),
+			// "?" is a valid character in field values, so the form only uses F1.
+			Help:    key.NewBinding(key.WithKeys("f1"), key.WithHelp("F1", "help")),
+			Done:    key.NewBinding(key.WithKeys("enter", "q", "esc"), key.WithHelp("Enter", "done")),
+			Undo:    key.NewBinding(key.WithKeys("ctrl+z"), key.WithHelp("Ctrl+Z", "undo")),
+			Redo:    key.NewBinding(key.WithKeys("ctrl+y"), key.WithHelp("Ctrl+Y", "redo")),
+			Restore: key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "restore backup")),
+			Backup:  key.NewBinding(key.WithKeys("ctrl+b"), key.WithHelp("Ctrl+B", "toggle backup for this file")),
+			Source:  key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("Ctrl+T", "keep current/use example/enter new")),
+			// Ctrl+E moves to the end of the line in text inputs.
+			FirstError: key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("Ctrl+G", "go to first error")),
+			Multiline:  key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("Ctrl+O", "edit multiline/apply")),
+			Editor:     key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("Ctrl+X", "edit in $EDITOR")),
+			Reveal:     key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("Ctrl+R", "show/hide secret")),
+		},
+		Compare: Compare{
+			Up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			Down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+			NextDiff: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next difference")),
+			PrevDiff: key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "prev difference")),
+			Filter:   key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "only differences/all keys")),
+			Reveal:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "show/hide secrets")),
+			Export:   key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "export patch")),
+			Confirm:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "write patch")),
+			Cancel:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel")),
+			Back:     key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q", "back")),
+		},
+		Move: Move{
+			Up:      key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			Down:    key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+			Toggle:  key.NewBinding(key.WithKeys(" "), key.WithHelp("Space", "select key")),
+			All:     key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "select all/none")),
+			Copy:    key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "move/copy")),
+			Next:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "choose destination")),
+			Confirm: key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "write")),
+			Cancel:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel")),
+			Back:    key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q", "back")),
+		},
+		Settings: Settings{
+			Up:      key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			Down:    key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+			Change:  key.NewBinding(key.WithKeys("enter", " ", "right", "l"), key.WithHelp("Enter/→", "change")),
+			Prev:    key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←", "previous option")),
+			Confirm: key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "confirm")),
+			Cancel:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel edit")),
+			Back:    key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q", "back")),
+		},
+	}
+}
+
+// actions maps the user-facing action names used in config files to the
+// bindings they control.
+func (km *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"help":             &km.Help,
+		"interrupt":        &km.Interrupt,
+		"menu.up":          &km.Menu.Up,
+		"menu.down":        &km.Menu.Down,
+		"menu.backup":      &km.Menu.Backup,
+		"menu.settings":    &km.Menu.Settings,
+		"menu.select":      &km.Menu.Select,
+		"menu.quit":        &km.Menu.Quit,
+		"picker.up":        &km.Picker.Up,
+		"picker.down":      &km.Picker.Down,
+		"picker.collapse":  &km.Picker.Collapse,
+		"picker.expand":    &km.Picker.Expand,
+		"picker.toggle":    &km.Picker.Toggle,
+		"picker.all":       &km.Picker.All,
+		"picker.pattern":   &km.Picker.Pattern,
+		"picker.cancel":    &km.Picker.Cancel,
+		"picker.confirm":   &km.Picker.Confirm,
+		"picker.back":      &km.Picker.Back,
+		"picker.quit":      &km.Picker.Quit,
+		"preview.up":       &km.Preview.Up,
+		"preview.down":     &km.Preview.Down,
+		"preview.nextfile": &km.Preview.NextFile,
+		"preview.prevfile": &km.Preview.PrevFile,
+		"preview.left":     &km.Preview.Left,
+		"preview.right":    &km.Preview.Right,
+		"preview.write":    &km.Preview.Write,
+		"preview.backup":   &km.Preview.Backup,
+		"preview.editor":   &km.Preview.Editor,
+		"preview.cancel":   &km.Preview.Cancel,
+		"preview.done":     &km.Preview.Done,
+		"form.up":          &km.Form.Up,
+		"form.down":        &km.Form.Down,
+		"form.next":        &km.Form.Next,
+		"form.prev":        &km.Form.Prev,
+		"form.submit":      &km.Form.Submit,
+		"form.save":        &km.Form.Save,
+		"form.cancel":      &km.Form.Cancel,
+		"form.help":        &km.Form.Help,
+		"form.done":        &km.Form.Done,
+		"form.undo":        &km.Form.Undo,
+		"form.redo":        &km.Form.Redo,
+		"form.restore":     &km.Form.Restore,
+		"form.backup":      &km.Form.Backup,
+		"form.source":      &km.Form.Source,
+		"form.firsterror":  &km.Form.FirstError,
+		"form.multiline":   &km.Form.Multiline,
+		"form.editor":      &km.Form.Editor,
+		"form.reveal":      &km.Form.Reveal,
+		"compare.up":       &km.Compare.Up,
+		"compare.down":     &km.Compare.Down,
+		"compare.nextdiff": &km.Compare.NextDiff,
+		"compare.prevdiff": &km.Compare.PrevDiff,
+		"compare.filter":   &km.Compare.Filter,
+		"compare.reveal":   &km.Compare.Reveal,
+		"compare.export":   &km.Compare.Export,
+		"compare.confirm":  &km.Compare.Confirm,
+		"compare.cancel":   &km.Compare.Cancel,
+		"compare.back":     &km.Compare.Back,
+		"move.up":          &km.Move.Up,
+		"move.down":        &km.Move.Down,
+		"move.toggle":      &km.Move.Toggle,
+		"move.all":         &km.Move.All,
+		"move.copy":        &km.Move.Copy,
+		"move.next":        &km.Move.Next,
+		"move.confirm":     &km.Move.Confirm,
+		"move.cancel":      &km.Move.Cancel,
+		"move.back":        &km.Move.Back,
+		"settings.up":      &km.Settings.Up,
+		"settings.down":    &km.Settings.Down,
+		"settings.change":  &km.Settings.Change,
+		"settings.prev":    &km.Settings.Prev,
+		"settings.confirm": &km.Settings.Confirm,
+		"settings.cancel":  &km.Settings.Cancel,
+		"settings.back":    &km.Settings.Back,
+	}
+}
+
+// Actions returns the sorted list of action names accepted by Apply.
+func Actions() []string {
+	km := Default()
+	var names []string
+	for name := range km.actions() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply replaces the keys of the named actions, e.g. {"form.save": {"ctrl+w"}}.
+// The help text of each remapped binding is updated to show the new keys.
+func (km *KeyMap) Apply(overrides map[string][]string) error {
+	actions := km.actions()
+	for name, keys := range overrides {
+		binding, ok := actions[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("unknown keymap action %q", name)
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("keymap action %q has no keys", name)
+		}
+		binding.SetKeys(keys...)
+		binding.SetHelp(strings.Join(keys, "/"), binding.Help().Desc)
+	}
+	return nil
+}
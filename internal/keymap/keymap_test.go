@@ -0,0 +1,78 @@
+package keymap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDefaultBindingsHaveHelp(t *testing.T) {
+	km := Default()
+	for name, b := range km.actions() {
+		if len(b.Keys()) == 0 {
+			t.Errorf("action %q has no keys", name)
+		}
+		if b.Help().Desc == "" {
+			t.Errorf("action %q has no help text", name)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   string
+	}{
+		{
+			name:      "remaps a binding",
+			overrides: map[string][]string{"picker.up": {"up"}, "FORM.SAVE": {"ctrl+w"}},
+		},
+		{
+			name:      "unknown action",
+			overrides: map[string][]string{"picker.jump": {"g"}},
+			wantErr:   "unknown keymap action",
+		},
+		{
+			name:      "empty key list",
+			overrides: map[string][]string{"menu.quit": {}},
+			wantErr:   "has no keys",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km := Default()
+			err := km.Apply(tt.overrides)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Apply() error = %v, expected %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() unexpected error: %v", err)
+			}
+
+			if key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}}, km.Picker.Up) {
+				t.Error("picker.up should no longer match 'k'")
+			}
+			if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlW}, km.Form.Save) {
+				t.Error("form.save should match ctrl+w")
+			}
+			if km.Form.Save.Help().Key != "ctrl+w" {
+				t.Errorf("help key = %q, expected ctrl+w", km.Form.Save.Help().Key)
+			}
+		})
+	}
+}
+
+func TestActions(t *testing.T) {
+	names := Actions()
+	if len(names) == 0 || names[0] != "compare.back" {
+		t.Errorf("Actions() = %v, expected sorted action names", names)
+	}
+}
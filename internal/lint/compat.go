@@ -0,0 +1,92 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// Target is a dotenv loader whose support RuleCompat checks a file
+// against.
+type Target string
+
+// Targets.
+const (
+	// TargetSystemd is the EnvironmentFile= directive of systemd units.
+	TargetSystemd Target = "systemd"
+	// TargetPHP is vlucas/phpdotenv.
+	TargetPHP Target = "php"
+	// TargetRuby is the dotenv gem.
+	TargetRuby Target = "ruby"
+	// TargetPython is python-dotenv.
+	TargetPython Target = "python"
+)
+
+// targetNames are the loaders as named in findings.
+var targetNames = map[Target]string{
+	TargetSystemd: "systemd EnvironmentFile",
+	TargetPHP:     "phpdotenv",
+	TargetRuby:    "Ruby dotenv",
+	TargetPython:  "python-dotenv",
+}
+
+// ParseTarget validates a compatibility target name.
+func ParseTarget(name string) (Target, error) {
+	t := Target(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := targetNames[t]; !ok {
+		return "", fmt.Errorf("unknown compatibility target %q (use %s, %s, %s or %s)", name, TargetSystemd, TargetPHP, TargetRuby, TargetPython)
+	}
+	return t, nil
+}
+
+var (
+	// variableRef matches $VAR and ${VAR} references.
+	variableRef = regexp.MustCompile(`\$\{?[A-Za-z_]`)
+	// bareVariableRef matches $VAR references without braces.
+	bareVariableRef = regexp.MustCompile(`\$[A-Za-z_]`)
+	// defaultValueRef matches ${VAR:-default} and ${VAR-default}.
+	defaultValueRef = regexp.MustCompile(`\$\{[A-Za-z_][A-Za-z0-9_]*:?-`)
+)
+
+// compatProblems returns what the target loader would read differently
+// from the way kv is written, or reject.
+func compatProblems(target Target, kv parser.KeyValue) []string {
+	expands := kv.Quoted != "'"
+	var problems []string
+	switch target {
+	case TargetSystemd:
+		if kv.Exported {
+			problems = append(problems, `the "export" prefix is not supported and the line is ignored`)
+		}
+		if strings.Contains(kv.Value, "\n") {
+			problems = append(problems, "multiline values are not supported")
+		}
+		if expands && variableRef.MatchString(kv.Value) {
+			problems = append(problems, "variables are not expanded, so the reference is kept literally")
+		}
+		if kv.Quoted == "" && strings.Contains(kv.Value, " #") {
+			problems = append(problems, "comments are only recognized at the start of a line, so the inline comment becomes part of the value")
+		}
+	case TargetPHP:
+		if kv.Quoted == "" && strings.ContainsAny(kv.Value, " \t") {
+			problems = append(problems, "unquoted values containing whitespace are rejected")
+		}
+		if expands && bareVariableRef.MatchString(kv.Value) {
+			problems = append(problems, "only ${VAR} references are expanded, so $VAR is kept literally")
+		}
+	case TargetRuby:
+		if expands && strings.Contains(kv.Value, "$(") {
+			problems = append(problems, "$(...) is run as a shell command")
+		}
+		if expands && defaultValueRef.MatchString(kv.Value) {
+			problems = append(problems, "default values such as ${VAR:-default} are not supported")
+		}
+	case TargetPython:
+		if expands && bareVariableRef.MatchString(kv.Value) {
+			problems = append(problems, "only ${VAR} references are expanded, so $VAR is kept literally")
+		}
+	}
+	return problems
+}
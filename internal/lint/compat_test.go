@@ -0,0 +1,77 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompatTargets(t *testing.T) {
+	content := strings.Join([]string{
+		"export PORT=3000",
+		"CERT=\"line1\nline2\"",
+		"URL=http://$HOST/${API_PATH}",
+		"GREETING=hello world",
+		"NAME=app # inline",
+		"TODAY=$(date)",
+		"REGION=${REGION:-us-east-1}",
+		"RAW='$HOST $(date)'",
+	}, "\n") + "\n"
+
+	tests := []struct {
+		target Target
+		want   []string
+	}{
+		{TargetSystemd, []string{
+			`1: systemd EnvironmentFile: the "export" prefix is not supported`,
+			"2: systemd EnvironmentFile: multiline values are not supported",
+			"4: systemd EnvironmentFile: variables are not expanded",
+			"6: systemd EnvironmentFile: comments are only recognized at the start of a line",
+			"8: systemd EnvironmentFile: variables are not expanded",
+		}},
+		{TargetPHP, []string{
+			"4: phpdotenv: only ${VAR} references are expanded",
+			"5: phpdotenv: unquoted values containing whitespace are rejected",
+			"6: phpdotenv: unquoted values containing whitespace are rejected",
+		}},
+		{TargetRuby, []string{
+			"7: Ruby dotenv: $(...) is run as a shell command",
+			"8: Ruby dotenv: default values such as ${VAR:-default} are not supported",
+		}},
+		{TargetPython, []string{
+			"4: python-dotenv: only ${VAR} references are expanded",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.target), func(t *testing.T) {
+			findings, err := Check(".env", []byte(content), Options{Compat: tt.target})
+			if err != nil {
+				t.Fatalf("Check() unexpected error: %v", err)
+			}
+			var got []string
+			for _, f := range findings {
+				if f.Rule == RuleCompat {
+					got = append(got, strings.TrimPrefix(f.String(), ".env:"))
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("compat findings = %q, want %d", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				line, msg, _ := strings.Cut(want, ": ")
+				target, problem, _ := strings.Cut(msg, ": ")
+				if !strings.HasPrefix(got[i], line+": error: "+target+", key ") || !strings.Contains(got[i], problem) {
+					t.Errorf("finding %d = %q, want %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	if got, err := ParseTarget(" PHP "); err != nil || got != TargetPHP {
+		t.Errorf("ParseTarget(PHP) = %q, %v", got, err)
+	}
+	if _, err := ParseTarget("node"); err == nil || !strings.Contains(err.Error(), "use systemd, php, ruby or python") {
+		t.Errorf("ParseTarget(node) error = %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+// Package lint checks env files for style and correctness problems, such
+// as misnamed or duplicate keys, with a configurable severity per rule.
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// Severity ranks how serious a finding is. SeverityOff disables a rule.
+type Severity string
+
+// Severities, from most to least serious.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	SeverityOff     Severity = "off"
+)
+
+// ParseSeverity validates a severity name.
+func ParseSeverity(name string) (Severity, error) {
+	switch s := Severity(strings.ToLower(strings.TrimSpace(name))); s {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown severity %q (use %s, %s, %s or %s)", name, SeverityError, SeverityWarning, SeverityInfo, SeverityOff)
+	}
+}
+
+// Rule names a check.
+type Rule string
+
+// Rules.
+const (
+	// RuleKeyNaming flags keys that are not UPPER_SNAKE_CASE.
+	RuleKeyNaming Rule = "key-naming"
+	// RuleDuplicateKey flags keys defined more than once.
+	RuleDuplicateKey Rule = "duplicate-key"
+	// RuleEmptyRequired flags keys the schema marks required but that have
+	// no value.
+	RuleEmptyRequired Rule = "empty-required"
+	// RuleTrailingWhitespace flags lines ending in spaces or tabs.
+	RuleTrailingWhitespace Rule = "trailing-whitespace"
+	// RuleUnquotedSpaces flags values containing spaces that are not quoted,
+	// which loaders disagree on.
+	RuleUnquotedSpaces Rule = "unquoted-spaces"
+	// RuleLineLength flags lines longer than Options.MaxLineLength.
+	RuleLineLength Rule = "line-length"
+	// RuleCompat flags constructs the loader named by Options.Compat does
+	// not support. It only runs when Options.Compat is set.
+	RuleCompat Rule = "compat"
+	// RuleRotation flags keys whose rotate-by annotation has passed or does
+	// not hold a valid date.
+	RuleRotation Rule = "rotation-overdue"
+	// RuleTokenShape flags values that start like a known token type but
+	// have the wrong length or characters, usually a truncated copy-paste.
+	RuleTokenShape Rule = "token-shape"
+	// RuleEncoding flags files that are not plain UTF-8: those starting
+	// with a byte order mark, UTF-16 ones and lines of invalid UTF-8.
+	RuleEncoding Rule = "encoding"
+)
+
+// defaultSeverities are used for rules Options.Severities does not mention.
+var defaultSeverities = map[Rule]Severity{
+	RuleKeyNaming:          SeverityWarning,
+	RuleDuplicateKey:       SeverityError,
+	RuleEmptyRequired:      SeverityError,
+	RuleTrailingWhitespace: SeverityWarning,
+	RuleUnquotedSpaces:     SeverityWarning,
+	RuleLineLength:         SeverityInfo,
+	RuleCompat:             SeverityError,
+	RuleRotation:           SeverityWarning,
+	RuleTokenShape:         SeverityWarning,
+	RuleEncoding:           SeverityWarning,
+}
+
+// Rules returns every rule name, sorted.
+func Rules() []Rule {
+	rules := make([]Rule, 0, len(defaultSeverities))
+	for r := range defaultSeverities {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i] < rules[j] })
+	return rules
+}
+
+// DefaultMaxLineLength is the line length limit when Options.MaxLineLength
+// is not set.
+const DefaultMaxLineLength = 120
+
+// Options configures Check. The zero value uses the default severities.
+type Options struct {
+	// Severities overrides the severity of individual rules.
+	Severities map[Rule]Severity
+	// MaxLineLength is the longest line RuleLineLength allows.
+	MaxLineLength int
+	// Required lists the keys that must have a value, typically taken from
+	// the project's .env.schema.
+	Required map[string]bool
+	// Compat is the loader the file must load in; empty skips RuleCompat.
+	Compat Target
+	// Now is the time RuleRotation compares rotate-by dates with; zero
+	// means the current time.
+	Now time.Time
+}
+
+// ParseOptions builds Options from rule and severity names, as written in
+// the config file.
+func ParseOptions(rules map[string]string, maxLineLength int) (Options, error) {
+	opts := Options{MaxLineLength: maxLineLength}
+	for _, name := range slices.Sorted(maps.Keys(rules)) {
+		sev := rules[name]
+		rule := Rule(name)
+		if _, ok := defaultSeverities[rule]; !ok {
+			return Options{}, fmt.Errorf("unknown lint rule %q", name)
+		}
+		s, err := ParseSeverity(sev)
+		if err != nil {
+			return Options{}, fmt.Errorf("lint rule %s: %w", name, err)
+		}
+		if opts.Severities == nil {
+			opts.Severities = make(map[Rule]Severity)
+		}
+		opts.Severities[rule] = s
+	}
+	return opts, nil
+}
+
+func (o Options) severity(r Rule) Severity {
+	if s, ok := o.Severities[r]; ok {
+		return s
+	}
+	return defaultSeverities[r]
+}
+
+// Finding is a single problem found in a file.
+type Finding struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Rule     Rule     `json:"rule"`
+	Severity Severity `json:"severity"`
+	Key      string   `json:"key,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// String formats f as "file:line: severity: message (rule)".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s (%s)", f.File, f.Line, f.Severity, f.Message, f.Rule)
+}
+
+var upperSnake = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// Check lints the content of one env file. Findings are ordered by line.
+// Content that does not parse is reported by the parser, not here.
+func Check(file string, data []byte, opts Options) ([]Finding, error) {
+	data, enc, err := parser.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	entries, err := parser.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	var findings []Finding
+	add := func(rule Rule, line int, key, format string, args ...any) {
+		sev := opts.severity(rule)
+		if sev == SeverityOff {
+			return
+		}
+		findings = append(findings, Finding{File: file, Line: line, Rule: rule, Severity: sev, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case enc == parser.UTF8BOM:
+		add(RuleEncoding, 1, "", "file starts with a UTF-8 byte order mark, which many loaders read as part of the first key")
+	case enc != "":
+		add(RuleEncoding, 1, "", "file is encoded as %s; most loaders only read UTF-8", enc)
+	case bytes.IndexByte(data, 0) >= 0:
+		add(RuleEncoding, 1, "", "file contains NUL bytes; it may be UTF-16 without a byte order mark")
+	}
+
+	maxLen := opts.MaxLineLength
+	if maxLen <= 0 {
+		maxLen = DefaultMaxLineLength
+	}
+	for i, line := range lines {
+		if strings.TrimRight(line, " \t") != line {
+			add(RuleTrailingWhitespace, i+1, "", "trailing whitespace")
+		}
+		if !utf8.ValidString(line) {
+			add(RuleEncoding, i+1, "", "line is not valid UTF-8")
+		}
+		if n := utf8.RuneCountInString(line); n > maxLen {
+			add(RuleLineLength, i+1, "", "line is %d characters long (max %d)", n, maxLen)
+		}
+	}
+
+	rotateBy, badRotateBy := parser.RotateBy(entries)
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	firstLine := make(map[string]int)
+	next := 0
+	for _, entry := range entries {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok {
+			continue
+		}
+		line := 0
+		if i := findKeyLine(lines, next, kv.Key); i >= 0 {
+			line, next = i+1, i+1
+		}
+
+		if !upperSnake.MatchString(kv.Key) {
+			add(RuleKeyNaming, line, kv.Key, "key %q is not UPPER_SNAKE_CASE", kv.Key)
+		}
+		if first, dup := firstLine[kv.Key]; dup {
+			add(RuleDuplicateKey, line, kv.Key, "key %q is already defined on line %d", kv.Key, first)
+		} else {
+			firstLine[kv.Key] = line
+		}
+		if opts.Required[kv.Key] && strings.TrimSpace(kv.Value) == "" {
+			add(RuleEmptyRequired, line, kv.Key, "required key %q has no value", kv.Key)
+		}
+		if kv.Quoted == "" && strings.ContainsAny(kv.Value, " \t") {
+			add(RuleUnquotedSpaces, line, kv.Key, "value of %q contains spaces but is not quoted", kv.Key)
+		}
+		if problem := detector.ShapeProblem(kv.Value); problem != "" {
+			add(RuleTokenShape, line, kv.Key, "value of %q %s", kv.Key, problem)
+		}
+		if opts.Compat != "" {
+			for _, problem := range compatProblems(opts.Compat, kv) {
+				add(RuleCompat, line, kv.Key, "%s, key %q: %s", targetNames[opts.Compat], kv.Key, problem)
+			}
+		}
+		if day, ok := rotateBy[kv.Key]; ok && parser.Overdue(day, now) {
+			add(RuleRotation, line, kv.Key, "key %q was due for rotation on %s", kv.Key, day.Format(time.DateOnly))
+		} else if value, ok := badRotateBy[kv.Key]; ok {
+			add(RuleRotation, line, kv.Key, "key %q has an invalid rotate-by date %q (use YYYY-MM-DD)", kv.Key, value)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings, nil
+}
+
+// findKeyLine returns the index of the first line at or after start that
+// assigns key, or -1.
+func findKeyLine(lines []string, start int, key string) int {
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		line = strings.TrimPrefix(line, "export ")
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), key); ok && strings.HasPrefix(strings.TrimSpace(rest), "=") {
+			return i
+		}
+	}
+	return -1
+}
+
+// Count returns how many findings have severity s.
+func Count(findings []Finding, s Severity) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == s {
+			n++
+		}
+	}
+	return n
+}
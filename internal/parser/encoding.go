@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Encoding is the first entry Parse returns for a file that starts with a
+// byte order mark, as those saved by Notepad do. The other entries are
+// decoded to UTF-8 either way; Write stores the file in the same encoding
+// again when the entries start with an Encoding.
+type Encoding string
+
+// Encodings.
+const (
+	// UTF8BOM is UTF-8 preceded by a byte order mark.
+	UTF8BOM Encoding = "UTF-8 with BOM"
+	// UTF16LE is little-endian UTF-16, Notepad's "Unicode".
+	UTF16LE Encoding = "UTF-16LE"
+	// UTF16BE is big-endian UTF-16.
+	UTF16BE Encoding = "UTF-16BE"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DetectEncoding returns the encoding whose byte order mark data starts
+// with, or "" when there is none.
+func DetectEncoding(data []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return UTF8BOM
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return UTF16LE
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return UTF16BE
+	}
+	return ""
+}
+
+// Decode returns data as UTF-8 without a byte order mark, and the encoding
+// it was stored in, as DetectEncoding returns it.
+func Decode(data []byte) ([]byte, Encoding, error) {
+	enc := DetectEncoding(data)
+	switch enc {
+	case UTF8BOM:
+		return data[len(bomUTF8):], enc, nil
+	case UTF16LE, UTF16BE:
+		decoded, _, err := transform.Bytes(enc.utf16().NewDecoder(), data)
+		if err != nil {
+			return nil, enc, fmt.Errorf("failed to decode %s: %w", enc, err)
+		}
+		return decoded, enc, nil
+	}
+	return data, "", nil
+}
+
+// decode returns a reader of reader's content as UTF-8 without a byte
+// order mark, and the encoding it was stored in.
+func decode(reader io.Reader) (io.Reader, Encoding, error) {
+	br := bufio.NewReader(reader)
+	head, err := br.Peek(len(bomUTF8))
+	if err != nil && err != io.EOF {
+		return nil, "", err
+	}
+	enc := DetectEncoding(head)
+	switch enc {
+	case UTF8BOM:
+		_, _ = br.Discard(len(bomUTF8))
+	case UTF16LE, UTF16BE:
+		return transform.NewReader(br, enc.utf16().NewDecoder()), enc, nil
+	}
+	return br, enc, nil
+}
+
+// utf16 returns the UTF-16 encoding of e, which reads and writes a byte
+// order mark.
+func (e Encoding) utf16() encoding.Encoding {
+	if e == UTF16BE {
+		return unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+	}
+	return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// utf16Bytes encodes s as UTF-16 with a byte order mark.
+func utf16Bytes(s string, bigEndian bool) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune("\ufeff" + s)) {
+		if bigEndian {
+			b = append(b, byte(u>>8), byte(u))
+		} else {
+			b = append(b, byte(u), byte(u>>8))
+		}
+	}
+	return b
+}
+
+func TestParseEncodings(t *testing.T) {
+	content := "# café\nAPI_KEY=abc\n\nGREETING=\"hello\nworld\"\n"
+	want := []Entry{
+		Comment{Text: "# café"},
+		KeyValue{Key: "API_KEY", Value: "abc"},
+		BlankLine{},
+		KeyValue{Key: "GREETING", Value: "hello\nworld", Quoted: `"`},
+	}
+	crlf := strings.ReplaceAll(content, "\n", "\r\n")
+	tests := []struct {
+		name string
+		data []byte
+		text string // data decoded
+		enc  Encoding
+	}{
+		{"plain utf-8", []byte(content), content, ""},
+		{"utf-8 with bom", append([]byte("\xef\xbb\xbf"), content...), content, UTF8BOM},
+		{"utf-16le", utf16Bytes(content, false), content, UTF16LE},
+		{"utf-16be", utf16Bytes(content, true), content, UTF16BE},
+		{"utf-16le with crlf", utf16Bytes(crlf, false), crlf, UTF16LE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectEncoding(tt.data); got != tt.enc {
+				t.Errorf("DetectEncoding() = %q, want %q", got, tt.enc)
+			}
+
+			entries, err := Parse(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			wantEntries := want
+			if tt.enc != "" {
+				wantEntries = append([]Entry{tt.enc}, want...)
+			}
+			if !reflect.DeepEqual(entries, wantEntries) {
+				t.Fatalf("Parse() = %#v, want %#v", entries, wantEntries)
+			}
+
+			decoded, enc, err := Decode(tt.data)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if enc != tt.enc || string(decoded) != tt.text {
+				t.Errorf("Decode() = %q, %q", decoded, enc)
+			}
+
+			// Writing the entries back stores the file as it was read,
+			// except for line endings, which are always written as LF.
+			if tt.text != content {
+				return
+			}
+			var out bytes.Buffer
+			if err := Write(&out, entries); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), tt.data) {
+				t.Errorf("Write() = %q, want %q", out.Bytes(), tt.data)
+			}
+		})
+	}
+}
+
+func TestParseFuncUTF16Large(t *testing.T) {
+	var content strings.Builder
+	for i := range 5000 {
+		content.WriteString("KEY_" + strings.Repeat("é", i%7) + "=value\n")
+	}
+	data := utf16Bytes(content.String(), false)
+
+	var out bytes.Buffer
+	ew := NewEntryWriter(&out)
+	if err := ParseFunc(bytes.NewReader(data), ew.Write); err != nil {
+		t.Fatalf("ParseFunc() error = %v", err)
+	}
+	if err := ew.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("round trip changed %d bytes into %d", len(data), out.Len())
+	}
+}
+
+func TestWriteEncodingNotFirst(t *testing.T) {
+	var out bytes.Buffer
+	err := Write(&out, []Entry{KeyValue{Key: "A", Value: "1"}, UTF8BOM})
+	if err == nil || !strings.Contains(err.Error(), "must be the first entry") {
+		t.Errorf("Write() error = %v, want encoding rejected after other entries", err)
+	}
+	if err := Write(&out, []Entry{Encoding("latin1")}); err == nil {
+		t.Error("Write() accepted an unknown encoding")
+	}
+}
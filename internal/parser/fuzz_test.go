@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"bytes"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// fuzzSeeds are inputs around the multiline quote state machine that have
+// caused trouble before.
+var fuzzSeeds = []string{
+	"",
+	"KEY=value\n",
+	"export KEY=\"quoted\"\n# comment\n\n",
+	"KEY=\"line1\nline2\"\n",
+	"KEY='a\\'b'\n",
+	"KEY=\"escaped \\\" quote\"\n",
+	"KEY=\"unclosed\n",
+	"KEY=\"\"\"\n",
+	"KEY=\"a\"b\"\n",
+	"=value\n",
+	"no equals\n",
+	"KEY=value\r\nOTHER=x\r\n",
+	"KEY=\"multi\r\nline\"\r\n",
+	"KEY=\"trailing  \nspace\"\n",
+}
+
+// addTestdataSeeds adds every .env file under the repository testdata
+// directory to the fuzz corpus.
+func addTestdataSeeds(f *testing.F) {
+	_, filename, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(filename), "..", "..", "testdata")
+
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasPrefix(d.Name(), ".env") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			f.Add(data)
+		}
+		return nil
+	})
+}
+
+// FuzzParseRoundTrip checks that Parse never panics and that anything it
+// accepts survives Write and a second Parse unchanged.
+func FuzzParseRoundTrip(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	addTestdataSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		entries, err := Parse(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := Write(&buf, entries); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		reparsed, err := Parse(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("Parse(Write(entries)) error = %v\nwritten:\n%q", err, buf.String())
+		}
+		if !reflect.DeepEqual(entries, reparsed) {
+			t.Fatalf("round trip changed entries\ninput:   %q\nwritten: %q\nfirst:   %#v\nsecond:  %#v", data, buf.String(), entries, reparsed)
+		}
+	})
+}
+
+// FuzzParseStrict checks that ParseStrict never panics and agrees with
+// Parse on the entries of well-formed input.
+func FuzzParseStrict(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	addTestdataSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		strict, strictErr := ParseStrict(bytes.NewReader(data))
+		if strictErr != nil {
+			return
+		}
+		lenient, err := Parse(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Parse() error = %v, but ParseStrict accepted the input", err)
+		}
+		if !reflect.DeepEqual(strict, lenient) {
+			t.Fatalf("ParseStrict and Parse disagree on %q:\nstrict:  %#v\nlenient: %#v", data, strict, lenient)
+		}
+	})
+}
+
+// envDoc is a randomly generated list of entries that Write can represent.
+// Quoted values never contain their own quote character or a backslash,
+// and unquoted values never start with a quote or end in whitespace, since
+// the format has no way to express those.
+type envDoc []Entry
+
+const (
+	keyChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ_0123456789"
+	valueChars = "abcXYZ019 _-=#:/.@$%"
+)
+
+func randString(r *rand.Rand, chars string, n int) string {
+	var b strings.Builder
+	for range n {
+		b.WriteByte(chars[r.Intn(len(chars))])
+	}
+	return b.String()
+}
+
+// Generate implements quick.Generator.
+func (envDoc) Generate(r *rand.Rand, size int) reflect.Value {
+	doc := make(envDoc, r.Intn(size+1))
+	for i := range doc {
+		switch r.Intn(5) {
+		case 0:
+			doc[i] = BlankLine{}
+		case 1:
+			doc[i] = Comment{Text: strings.TrimRight("# "+randString(r, valueChars, r.Intn(20)), " ")}
+		default:
+			kv := KeyValue{
+				Key:      string(keyChars[r.Intn(27)]) + randString(r, keyChars, r.Intn(10)),
+				Exported: r.Intn(4) == 0,
+			}
+			switch r.Intn(3) {
+			case 0:
+				kv.Value = strings.TrimRight(randString(r, valueChars, r.Intn(20)), " ")
+			case 1:
+				kv.Quoted = "'"
+				kv.Value = randString(r, valueChars+"\"", r.Intn(20))
+			default:
+				kv.Quoted = "\""
+				lines := make([]string, 1+r.Intn(3))
+				for j := range lines {
+					lines[j] = randString(r, valueChars+"'", r.Intn(15))
+				}
+				kv.Value = strings.Join(lines, "\n")
+			}
+			doc[i] = kv
+		}
+	}
+	return reflect.ValueOf(doc)
+}
+
+// TestRoundTripProperty checks that any representable document survives
+// Write followed by Parse unchanged.
+func TestRoundTripProperty(t *testing.T) {
+	roundTrips := func(doc envDoc) bool {
+		var buf bytes.Buffer
+		if err := Write(&buf, doc); err != nil {
+			t.Logf("Write() error = %v", err)
+			return false
+		}
+		parsed, err := Parse(&buf)
+		if err != nil {
+			t.Logf("Parse() error = %v", err)
+			return false
+		}
+		if len(parsed) == 0 && len(doc) == 0 {
+			return true
+		}
+		return reflect.DeepEqual([]Entry(doc), parsed)
+	}
+
+	if err := quick.Check(roundTrips, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MoveKeys moves the given keys from src to the end of dst, each with the
+// comments directly above it, and returns both files' new entries. With
+// keep set the keys are copied and src is returned unchanged. A comment
+// separated from the next key by a blank line, such as a section header,
+// belongs to no key and stays where it is.
+//
+// Every key must be in src, and none may already be in dst.
+func MoveKeys(src, dst []Entry, keys []string, keep bool) ([]Entry, []Entry, error) {
+	present := make(map[string]bool)
+	for _, entry := range src {
+		if kv, ok := entry.(KeyValue); ok {
+			present[kv.Key] = true
+		}
+	}
+	for _, key := range keys {
+		if !present[key] {
+			return nil, nil, fmt.Errorf("key %s not found", key)
+		}
+	}
+	for _, entry := range dst {
+		if kv, ok := entry.(KeyValue); ok && slices.Contains(keys, kv.Key) {
+			return nil, nil, fmt.Errorf("key %s already exists in the destination", kv.Key)
+		}
+	}
+
+	var taken, rest, pending []Entry
+	tookLast := false
+	for _, entry := range src {
+		switch e := entry.(type) {
+		case Comment:
+			pending = append(pending, e)
+			continue
+		case KeyValue:
+			if slices.Contains(keys, e.Key) {
+				taken = append(append(taken, pending...), e)
+				pending = nil
+				tookLast = true
+				continue
+			}
+		case BlankLine:
+			// Taking a key out from between two blank lines would
+			// leave both behind.
+			if tookLast && len(pending) == 0 && (len(rest) == 0 || isBlank(rest[len(rest)-1])) {
+				continue
+			}
+		}
+		rest = append(append(rest, pending...), entry)
+		pending = nil
+		tookLast = false
+	}
+	rest = append(rest, pending...)
+	// Nor should taking the last keys leave the blank line before them.
+	if len(src) > 0 && !isBlank(src[len(src)-1]) {
+		for len(rest) > 0 && isBlank(rest[len(rest)-1]) {
+			rest = rest[:len(rest)-1]
+		}
+	}
+
+	newDst := slices.Clone(dst)
+	if len(newDst) > 0 && !isBlank(newDst[len(newDst)-1]) {
+		newDst = append(newDst, BlankLine{})
+	}
+	newDst = append(newDst, taken...)
+
+	if keep {
+		return src, newDst, nil
+	}
+	return rest, newDst, nil
+}
+
+func isBlank(entry Entry) bool {
+	_, ok := entry.(BlankLine)
+	return ok
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMoveKeys(t *testing.T) {
+	src, err := Parse(strings.NewReader(`# Web
+PORT=3000
+
+# Billing service
+# set via --set 2026-02-08
+STRIPE_KEY=sk_test
+STRIPE_WEBHOOK=whsec
+
+HOST=localhost
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	dst := []Entry{KeyValue{Key: "SERVICE", Value: "billing"}}
+
+	rest, moved, err := MoveKeys(src, dst, []string{"STRIPE_KEY", "STRIPE_WEBHOOK"}, false)
+	if err != nil {
+		t.Fatalf("MoveKeys: %v", err)
+	}
+	compareEntries(t, rest, []Entry{
+		Comment{Text: "# Web"},
+		KeyValue{Key: "PORT", Value: "3000"},
+		BlankLine{},
+		KeyValue{Key: "HOST", Value: "localhost"},
+	})
+	compareEntries(t, moved, []Entry{
+		KeyValue{Key: "SERVICE", Value: "billing"},
+		BlankLine{},
+		Comment{Text: "# Billing service"},
+		Comment{Text: "# set via --set 2026-02-08"},
+		KeyValue{Key: "STRIPE_KEY", Value: "sk_test"},
+		KeyValue{Key: "STRIPE_WEBHOOK", Value: "whsec"},
+	})
+
+	kept, _, err := MoveKeys(src, nil, []string{"PORT"}, true)
+	if err != nil {
+		t.Fatalf("MoveKeys copy: %v", err)
+	}
+	if len(kept) != len(src) {
+		t.Errorf("copying should leave the source unchanged")
+	}
+}
+
+func TestMoveKeysBlankLines(t *testing.T) {
+	src := []Entry{
+		KeyValue{Key: "A", Value: "1"},
+		BlankLine{},
+		KeyValue{Key: "B", Value: "2"},
+		BlankLine{},
+		KeyValue{Key: "C", Value: "3"},
+	}
+	rest, _, err := MoveKeys(src, nil, []string{"B"}, false)
+	if err != nil {
+		t.Fatalf("MoveKeys: %v", err)
+	}
+	compareEntries(t, rest, []Entry{
+		KeyValue{Key: "A", Value: "1"},
+		BlankLine{},
+		KeyValue{Key: "C", Value: "3"},
+	})
+
+	rest, _, err = MoveKeys(src, nil, []string{"C"}, false)
+	if err != nil {
+		t.Fatalf("MoveKeys: %v", err)
+	}
+	compareEntries(t, rest, []Entry{
+		KeyValue{Key: "A", Value: "1"},
+		BlankLine{},
+		KeyValue{Key: "B", Value: "2"},
+	})
+}
+
+func TestMoveKeysErrors(t *testing.T) {
+	src := []Entry{KeyValue{Key: "A", Value: "1"}}
+	if _, _, err := MoveKeys(src, nil, []string{"MISSING"}, false); err == nil || !strings.Contains(err.Error(), "MISSING not found") {
+		t.Errorf("moving a missing key error = %v", err)
+	}
+	if _, _, err := MoveKeys(src, src, []string{"A"}, false); err == nil || !strings.Contains(err.Error(), "A already exists") {
+		t.Errorf("moving onto an existing key error = %v", err)
+	}
+}
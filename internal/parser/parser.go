@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"strings"
+
+	"golang.org/x/text/transform"
 )
 
 // Entry represents a line in a .env file
@@ -34,45 +36,129 @@ const (
 
 // Parse reads a .env file and returns ordered entries
 func Parse(reader io.Reader) ([]Entry, error) {
-	var entries []Entry
+	entries := make([]Entry, 0, sizeHint(reader))
+	_, err := parse(reader, false, func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ParseFunc reads a .env file like Parse but calls fn with each entry as
+// soon as it is read, so large files can be transformed without holding
+// every entry in memory. Parsing stops at the first error fn returns, and
+// that error is returned unchanged.
+func ParseFunc(reader io.Reader, fn func(Entry) error) error {
+	_, err := parse(reader, false, fn)
+	return err
+}
+
+// ParseStrict reads a .env file like Parse but reports malformed lines
+// instead of silently treating them as comments. When problems are found,
+// the returned error is a ParseErrors value listing each one; the entries
+// parsed so far are still returned.
+func ParseStrict(reader io.Reader) ([]Entry, error) {
+	entries := make([]Entry, 0, sizeHint(reader))
+	lineErrs, err := parse(reader, true, func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(lineErrs) > 0 {
+		return entries, lineErrs
+	}
+	return entries, nil
+}
+
+// bytesPerEntry is a typical .env line length, used to estimate how many
+// entries a reader of known length holds.
+const bytesPerEntry = 32
+
+// sizeHint estimates the number of entries in reader when its length is
+// known, as for strings.Reader and bytes.Buffer, so the entries slice can be
+// allocated once.
+func sizeHint(reader io.Reader) int {
+	if r, ok := reader.(interface{ Len() int }); ok {
+		return r.Len() / bytesPerEntry
+	}
+	return 0
+}
+
+// parse implements Parse, ParseFunc and ParseStrict, passing each entry to
+// emit, after an Encoding when the content starts with a byte order mark.
+// In strict mode, malformed lines are collected as LineErrors rather
+// than being classified as comments.
+func parse(reader io.Reader, strict bool, emit func(Entry) error) (ParseErrors, error) {
+	reader, enc, err := decode(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading: %w", err)
+	}
+	if enc != "" {
+		if err := emit(enc); err != nil {
+			return nil, err
+		}
+	}
+
+	var lineErrs ParseErrors
 	scanner := bufio.NewScanner(reader)
 	scanner.Buffer(make([]byte, initialBufferSize), maxBufferSize)
 
-	var accumulated string
+	// A multiline value is accumulated in a builder, and its quotes are
+	// counted line by line, so long values are not rescanned on every line.
+	var accumulated strings.Builder
 	var inQuote rune // 0 if not in quote, '"' or '\'' if inside quote
+	quotes := 0
+	lineNum, startLine := 0, 0
 
 	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Trim trailing carriage return to handle CRLF inputs consistently
-		line = strings.TrimRight(line, "\r")
+		raw := scanner.Text()
+		lineNum++
 
 		// If we're accumulating a multiline value
 		if inQuote != 0 {
-			accumulated += "\n" + line
-			if isMultilineClosed(accumulated, inQuote) {
+			// Trim trailing carriage return to handle CRLF inputs consistently
+			raw = strings.TrimRight(raw, "\r")
+			accumulated.WriteByte('\n')
+			accumulated.WriteString(raw)
+			quotes += countUnescapedQuotes(raw, inQuote)
+			if quotes%2 == 0 {
 				inQuote = 0
-				trimmed := strings.TrimRight(accumulated, " \t\r\n")
+				trimmed := strings.TrimRight(accumulated.String(), " \t\r\n")
 				kv, err := parseKeyValue(trimmed)
 				if err != nil {
 					return nil, fmt.Errorf("parsing multiline value %q: %w", trimmed, err)
 				}
-				entries = append(entries, kv)
-				accumulated = ""
+				if strict {
+					lineErrs = append(lineErrs, validateKeyValue(startLine, trimmed, kv)...)
+				}
+				if err := emit(kv); err != nil {
+					return nil, err
+				}
+				accumulated.Reset()
 			}
 			continue
 		}
 
-		// Not in a multiline value, process line normally
-		line = strings.TrimRight(line, " \t\r\n")
+		// Not in a multiline value, process line normally. The untrimmed line
+		// is kept in case it opens a multiline value, whose whitespace is data.
+		line := strings.TrimRight(raw, " \t\r")
 
 		if line == "" {
-			entries = append(entries, BlankLine{})
+			if err := emit(BlankLine{}); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
 		if strings.HasPrefix(line, "#") {
-			entries = append(entries, Comment{Text: line})
+			if err := emit(Comment{Text: line}); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
@@ -82,7 +168,9 @@ func Parse(reader io.Reader) ([]Entry, error) {
 			if quoteStart != 0 {
 				// Start accumulating multiline value
 				inQuote = quoteStart
-				accumulated = line
+				quotes = countUnescapedQuotes(extractValuePart(line), inQuote)
+				accumulated.WriteString(strings.TrimRight(raw, "\r"))
+				startLine = lineNum
 				continue
 			}
 
@@ -91,11 +179,21 @@ func Parse(reader io.Reader) ([]Entry, error) {
 			if err != nil {
 				return nil, fmt.Errorf("parsing line %q: %w", line, err)
 			}
-			entries = append(entries, kv)
+			if strict {
+				lineErrs = append(lineErrs, validateKeyValue(lineNum, line, kv)...)
+			}
+			if err := emit(kv); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
-		entries = append(entries, Comment{Text: line})
+		if strict {
+			lineErrs = append(lineErrs, &LineError{Line: lineNum, Text: line, Reason: "missing '=' in key-value line"})
+		}
+		if err := emit(Comment{Text: line}); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -106,22 +204,31 @@ func Parse(reader io.Reader) ([]Entry, error) {
 	if inQuote != 0 {
 		// Extract key name for better error context
 		key := "<unknown>"
-		if eq := strings.Index(accumulated, "="); eq != -1 {
-			key = strings.TrimSpace(accumulated[:eq])
+		if k, _, ok := strings.Cut(accumulated.String(), "="); ok {
+			key = strings.TrimSpace(k)
 		}
 
 		// Create truncated snippet for error message
-		snippet := accumulated
+		snippet := accumulated.String()
 		const maxSnippetLen = 80
 		if len(snippet) > maxSnippetLen {
 			snippet = snippet[:maxSnippetLen-3] + "..."
 		}
 
+		if strict {
+			lineErrs = append(lineErrs, &LineError{
+				Line:   startLine,
+				Text:   snippet,
+				Reason: fmt.Sprintf("unclosed %q quote in multiline value for key %q", string(inQuote), key),
+			})
+			return lineErrs, nil
+		}
+
 		return nil, fmt.Errorf("unclosed %q quote in multiline value for key %q starting with %q",
 			string(inQuote), key, snippet)
 	}
 
-	return entries, nil
+	return lineErrs, nil
 }
 
 // countUnescapedQuotes counts the number of unescaped quote characters in a string
@@ -178,17 +285,6 @@ func findUnclosedQuote(line string) rune {
 	return 0
 }
 
-// isMultilineClosed checks if the accumulated multiline value has a closing quote.
-func isMultilineClosed(accumulated string, quote rune) bool {
-	valuePart := extractValuePart(accumulated)
-	if valuePart == "" || !strings.HasPrefix(valuePart, string(quote)) {
-		return false
-	}
-
-	count := countUnescapedQuotes(valuePart, quote)
-	return count > 0 && count%2 == 0
-}
-
 // parseKeyValue parses a single key-value line
 func parseKeyValue(line string) (KeyValue, error) {
 	var kv KeyValue
@@ -198,13 +294,12 @@ func parseKeyValue(line string) (KeyValue, error) {
 		line = strings.TrimSpace(line[7:])
 	}
 
-	parts := strings.SplitN(line, "=", 2)
-	if len(parts) != 2 {
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
 		return KeyValue{}, fmt.Errorf("invalid key-value format")
 	}
 
-	kv.Key = strings.TrimSpace(parts[0])
-	value := parts[1]
+	kv.Key = strings.TrimSpace(key)
 
 	// Check if value is quoted
 	if len(value) >= 2 {
@@ -236,37 +331,123 @@ func formatKeyValue(kv KeyValue) string {
 	return line
 }
 
-// Write writes entries to a writer, preserving the original structure
+// Write writes entries to a writer, preserving the original structure and
+// quoting. Use WriteWithOptions to quote values per a QuotePolicy.
 func Write(writer io.Writer, entries []Entry) error {
-	for _, entry := range entries {
-		switch e := entry.(type) {
-		case KeyValue:
-			if _, err := fmt.Fprintln(writer, formatKeyValue(e)); err != nil {
-				return err
-			}
+	_, err := Entries(entries).WriteTo(writer)
+	return err
+}
 
-		case Comment:
-			if _, err := fmt.Fprintln(writer, e.Text); err != nil {
-				return err
-			}
+// Entries is a list of entries that implements io.WriterTo.
+type Entries []Entry
 
-		case BlankLine:
-			if _, err := fmt.Fprintln(writer); err != nil {
-				return err
-			}
-
-		default:
-			return fmt.Errorf("unknown entry type: %T", e)
+// WriteTo implements io.WriterTo, writing the entries in .env format.
+func (e Entries) WriteTo(writer io.Writer) (int64, error) {
+	cw := &countingWriter{w: writer}
+	ew := NewEntryWriter(cw)
+	for _, entry := range e {
+		if err := ew.Write(entry); err != nil {
+			return cw.n, err
 		}
 	}
+	err := ew.Flush()
+	return cw.n, err
+}
+
+// EntryWriter writes entries one at a time through a buffer, pairing with
+// ParseFunc to transform files without holding them in memory. Call Flush
+// after the last entry.
+type EntryWriter struct {
+	w     *bufio.Writer
+	dst   io.Writer
+	opts  WriteOptions
+	wrote bool
+}
+
+// NewEntryWriter returns an EntryWriter that writes to writer, keeping
+// values quoted as they are.
+func NewEntryWriter(writer io.Writer) *EntryWriter {
+	return newEntryWriter(writer, WriteOptions{})
+}
+
+// NewEntryWriterWithOptions returns an EntryWriter that writes to writer,
+// quoting values per opts.
+func NewEntryWriterWithOptions(writer io.Writer, opts WriteOptions) *EntryWriter {
+	return newEntryWriter(writer, opts)
+}
+
+func newEntryWriter(writer io.Writer, opts WriteOptions) *EntryWriter {
+	return &EntryWriter{w: bufio.NewWriter(writer), dst: writer, opts: opts}
+}
+
+// Write writes a single entry followed by a newline. An Encoding writes
+// nothing itself but stores the entries after it in that encoding; it must
+// come first.
+func (ew *EntryWriter) Write(entry Entry) error {
+	if enc, ok := entry.(Encoding); ok {
+		return ew.setEncoding(enc)
+	}
+	ew.wrote = true
+	// bufio.Writer errors are sticky, so any failure surfaces from WriteByte.
+	switch e := entry.(type) {
+	case KeyValue:
+		_, _ = ew.w.WriteString(formatKeyValue(applyQuotePolicy(e, ew.opts.QuotePolicy)))
+	case Comment:
+		_, _ = ew.w.WriteString(e.Text)
+	case BlankLine:
+	default:
+		return fmt.Errorf("unknown entry type: %T", e)
+	}
+	return ew.w.WriteByte('\n')
+}
+
+// setEncoding starts the output with the byte order mark of enc, and
+// encodes what follows as UTF-16 when enc is.
+func (ew *EntryWriter) setEncoding(enc Encoding) error {
+	if ew.wrote {
+		return fmt.Errorf("encoding %s must be the first entry", enc)
+	}
+	switch enc {
+	case UTF8BOM:
+		_, _ = ew.w.Write(bomUTF8)
+	case UTF16LE, UTF16BE:
+		ew.w = bufio.NewWriter(transform.NewWriter(ew.dst, enc.utf16().NewEncoder()))
+	default:
+		return fmt.Errorf("unknown encoding %q", string(enc))
+	}
+	ew.wrote = true
 	return nil
 }
 
-// EntryToString converts an Entry to its string representation.
+// Flush writes any buffered entries to the underlying writer.
+func (ew *EntryWriter) Flush() error {
+	return ew.w.Flush()
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// EntryToString converts an Entry to its string representation, as Write
+// would write it.
 func EntryToString(entry Entry) string {
+	return EntryToStringWithOptions(entry, WriteOptions{})
+}
+
+// EntryToStringWithOptions converts an Entry to its string representation,
+// as WriteWithOptions would write it with opts.
+func EntryToStringWithOptions(entry Entry, opts WriteOptions) string {
 	switch e := entry.(type) {
 	case KeyValue:
-		return formatKeyValue(e)
+		return formatKeyValue(applyQuotePolicy(e, opts.QuotePolicy))
 	case Comment:
 		return e.Text
 	case BlankLine:
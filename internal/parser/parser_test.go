@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -221,6 +224,81 @@ API_URL=https://api.example.com
 	}
 }
 
+func TestParseFunc(t *testing.T) {
+	input := "# header\nA=1\n\nB=\"multi\nline\"\nC=3\n"
+
+	var streamed []Entry
+	err := ParseFunc(strings.NewReader(input), func(e Entry) error {
+		streamed = append(streamed, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParseFunc() error = %v", err)
+	}
+	parsed, _ := Parse(strings.NewReader(input))
+	compareEntries(t, streamed, parsed)
+}
+
+func TestParseFuncStopsOnError(t *testing.T) {
+	errStop := errors.New("stop")
+	var keys []string
+	err := ParseFunc(strings.NewReader("A=1\nB=2\nC=3\n"), func(e Entry) error {
+		kv := e.(KeyValue)
+		keys = append(keys, kv.Key)
+		if kv.Key == "B" {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("ParseFunc() error = %v, want %v", err, errStop)
+	}
+	if strings.Join(keys, ",") != "A,B" {
+		t.Errorf("visited keys = %v, want [A B]", keys)
+	}
+}
+
+func TestEntriesWriteTo(t *testing.T) {
+	entries := Entries{Comment{Text: "# c"}, KeyValue{Key: "K", Value: "v", Quoted: "\""}, BlankLine{}}
+	want := "# c\nK=\"v\"\n\n"
+
+	var _ io.WriterTo = entries
+
+	var buf strings.Builder
+	n, err := entries.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if buf.String() != want || n != int64(len(want)) {
+		t.Errorf("WriteTo() wrote %q (%d bytes), want %q (%d bytes)", buf.String(), n, want, len(want))
+	}
+}
+
+func TestEntryWriterStreamsTransform(t *testing.T) {
+	var out strings.Builder
+	ew := NewEntryWriter(&out)
+	err := ParseFunc(strings.NewReader("# keep\nA=1\nB=2\n"), func(e Entry) error {
+		if kv, ok := e.(KeyValue); ok {
+			kv.Value = "x"
+			e = kv
+		}
+		return ew.Write(e)
+	})
+	if err != nil {
+		t.Fatalf("ParseFunc() error = %v", err)
+	}
+	if err := ew.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got, want := out.String(), "# keep\nA=x\nB=x\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	if err := ew.Write(42); err == nil {
+		t.Error("expected error for unknown entry type")
+	}
+}
+
 func TestParseMultiline(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -641,3 +719,59 @@ func TestEntryToString(t *testing.T) {
 		})
 	}
 }
+
+// largeEnv builds a .env file of roughly n lines mixing comments, blank
+// lines, quoted values and a multiline value every 100 keys.
+func largeEnv(n int) string {
+	var b strings.Builder
+	for i := 0; b.Len() == 0 || i < n; i++ {
+		switch {
+		case i%50 == 0:
+			fmt.Fprintf(&b, "# section %d\n\n", i)
+		case i%100 == 1:
+			fmt.Fprintf(&b, "CERT_%d=\"-----BEGIN CERT-----\nMIIB%d\nQUJD\n-----END CERT-----\"\n", i, i)
+			i += 3
+		case i%3 == 0:
+			fmt.Fprintf(&b, "export QUOTED_%d=\"value with spaces %d\"\n", i, i)
+		default:
+			fmt.Fprintf(&b, "KEY_%d=value_%d\n", i, i)
+		}
+	}
+	return b.String()
+}
+
+// longMultiline builds a single quoted value spanning n lines.
+func longMultiline(n int) string {
+	return "BLOB=\"" + strings.Repeat("0123456789abcdef0123456789abcdef\n", n) + "\"\n"
+}
+
+func benchmarkParse(b *testing.B, input string) {
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Parse(strings.NewReader(input)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParse100kLines(b *testing.B) {
+	benchmarkParse(b, largeEnv(100_000))
+}
+
+func BenchmarkParseLongMultiline(b *testing.B) {
+	benchmarkParse(b, longMultiline(20_000))
+}
+
+func BenchmarkWrite100kLines(b *testing.B) {
+	entries, err := Parse(strings.NewReader(largeEnv(100_000)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := Write(io.Discard, entries); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"strings"
+	"time"
+)
+
+// Provenance comments record where a value came from. They sit directly
+// above their key, as "# set via ..." or "# sourced from ...", and are
+// metadata about the value rather than part of the file's own comments.
+var provenancePrefixes = []string{"# set via ", "# sourced from "}
+
+// IsProvenance reports whether c is a provenance comment.
+func IsProvenance(c Comment) bool {
+	for _, prefix := range provenancePrefixes {
+		if strings.HasPrefix(c.Text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetVia returns the provenance note for a value set through via on the
+// given day, e.g. "set via dotenv-tui form 2026-02-08". A zero day leaves
+// the date out.
+func SetVia(via string, on time.Time) string {
+	return dated("set via "+via, on)
+}
+
+// SourcedFrom returns the provenance note for a value read from source on
+// the given day, e.g. "sourced from environment 2026-02-08". A zero day
+// leaves the date out.
+func SourcedFrom(source string, on time.Time) string {
+	return dated("sourced from "+source, on)
+}
+
+func dated(note string, on time.Time) string {
+	if on.IsZero() {
+		return note
+	}
+	return note + " " + on.Format(time.DateOnly)
+}
+
+// Provenance returns the provenance note of each key that has one, without
+// the leading "# ".
+func Provenance(entries []Entry) map[string]string {
+	notes := make(map[string]string)
+	var pending string
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case Comment:
+			if IsProvenance(e) {
+				pending = strings.TrimPrefix(e.Text, "# ")
+				continue
+			}
+		case KeyValue:
+			if pending != "" {
+				notes[e.Key] = pending
+			}
+		}
+		pending = ""
+	}
+	return notes
+}
+
+// Annotate writes a provenance comment with the note for each key in notes
+// directly above that key, replacing any provenance comments already there
+// so that regenerating a file updates its notes instead of stacking them.
+// Keys not in notes keep their comments unchanged.
+func Annotate(entries []Entry, notes map[string]string) []Entry {
+	if len(notes) == 0 {
+		return entries
+	}
+	result := make([]Entry, 0, len(entries)+len(notes))
+	for _, entry := range entries {
+		if kv, ok := entry.(KeyValue); ok {
+			if note, ok := notes[kv.Key]; ok {
+				for len(result) > 0 {
+					c, ok := result[len(result)-1].(Comment)
+					if !ok || !IsProvenance(c) {
+						break
+					}
+					result = result[:len(result)-1]
+				}
+				result = append(result, Comment{Text: "# " + note})
+			}
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// StripProvenance returns entries without their provenance comments.
+func StripProvenance(entries []Entry) []Entry {
+	result := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if c, ok := entry.(Comment); ok && IsProvenance(c) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnnotate(t *testing.T) {
+	input := `# Database
+# set via dotenv-tui form 2026-01-02
+DB_HOST=localhost
+# sourced from AWS SM
+# set via --set 2026-01-01
+API_KEY=old
+PORT=3000
+`
+	entries, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	on := time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC)
+	got := Annotate(entries, map[string]string{
+		"API_KEY": SetVia("dotenv-tui form", on),
+		"PORT":    SourcedFrom("environment", on),
+	})
+
+	var b strings.Builder
+	if err := Write(&b, got); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	want := `# Database
+# set via dotenv-tui form 2026-01-02
+DB_HOST=localhost
+# set via dotenv-tui form 2026-02-08
+API_KEY=old
+# sourced from environment 2026-02-08
+PORT=3000
+`
+	if b.String() != want {
+		t.Errorf("Annotate wrote:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	// Annotating again updates the notes instead of adding more.
+	again := Annotate(got, map[string]string{"API_KEY": SetVia("--set", on)})
+	if len(again) != len(got) {
+		t.Errorf("re-annotating changed the entry count from %d to %d", len(got), len(again))
+	}
+	notes := Provenance(again)
+	if notes["API_KEY"] != "set via --set 2026-02-08" || notes["PORT"] != "sourced from environment 2026-02-08" {
+		t.Errorf("Provenance = %v", notes)
+	}
+	if _, ok := notes["DB_HOST"]; !ok {
+		t.Error("DB_HOST lost its note")
+	}
+
+	if note := SetVia("--set", time.Time{}); note != "set via --set" {
+		t.Errorf("SetVia() without a day = %q, want no date", note)
+	}
+}
+
+func TestStripProvenance(t *testing.T) {
+	entries := []Entry{
+		Comment{Text: "# Settings"},
+		Comment{Text: "# set via --set 2026-02-08"},
+		KeyValue{Key: "A", Value: "1"},
+		Comment{Text: "# sourced from AWS SM"},
+		KeyValue{Key: "B", Value: "2"},
+	}
+	got := StripProvenance(entries)
+	want := []Entry{
+		Comment{Text: "# Settings"},
+		KeyValue{Key: "A", Value: "1"},
+		KeyValue{Key: "B", Value: "2"},
+	}
+	compareEntries(t, got, want)
+}
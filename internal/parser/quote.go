@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// QuotePolicy selects how unquoted values are quoted when entries are
+// written. Values already quoted are always written as they are.
+type QuotePolicy string
+
+// Quote policies.
+const (
+	// QuotePreserve writes values exactly as they are.
+	QuotePreserve QuotePolicy = "preserve"
+	// QuoteAlways quotes every value.
+	QuoteAlways QuotePolicy = "always"
+	// QuoteWhenNeeded quotes values containing spaces or "#", or with
+	// leading or trailing whitespace, which some runtimes misread unquoted.
+	QuoteWhenNeeded QuotePolicy = "when-needed"
+)
+
+// ParseQuotePolicy validates a quote policy name. An empty name selects
+// QuotePreserve.
+func ParseQuotePolicy(name string) (QuotePolicy, error) {
+	switch policy := QuotePolicy(strings.ToLower(strings.TrimSpace(name))); policy {
+	case "":
+		return QuotePreserve, nil
+	case QuotePreserve, QuoteAlways, QuoteWhenNeeded:
+		return policy, nil
+	default:
+		return QuotePreserve, fmt.Errorf("unknown quote policy %q (use %s, %s or %s)", name, QuotePreserve, QuoteAlways, QuoteWhenNeeded)
+	}
+}
+
+// WriteOptions controls how entries are written.
+type WriteOptions struct {
+	QuotePolicy QuotePolicy
+}
+
+// WriteWithOptions writes entries to a writer like Write, quoting values
+// per opts.
+func WriteWithOptions(writer io.Writer, entries []Entry, opts WriteOptions) error {
+	ew := newEntryWriter(writer, opts)
+	for _, entry := range entries {
+		if err := ew.Write(entry); err != nil {
+			return err
+		}
+	}
+	return ew.Flush()
+}
+
+// applyQuotePolicy returns kv with its value quoted per policy. The value
+// is double-quoted unless it contains a double quote or a backslash, which
+// double quotes could change the meaning of; it is then single-quoted,
+// unless it contains a single quote or "$" too, and left as it is. Values
+// holding " #" are left alone too: most runtimes read the rest as an inline
+// comment, which quotes would turn into part of the value.
+func applyQuotePolicy(kv KeyValue, policy QuotePolicy) KeyValue {
+	if kv.Quoted != "" || strings.Contains(kv.Value, "\n") || strings.Contains(kv.Value, " #") {
+		return kv
+	}
+	switch policy {
+	case QuoteAlways:
+	case QuoteWhenNeeded:
+		if !needsQuotes(kv.Value) {
+			return kv
+		}
+	default:
+		return kv
+	}
+	kv.Quoted = quoteFor(kv.Value)
+	return kv
+}
+
+// QuoteValue returns kv quoted as the when-needed policy quotes it, for a
+// value dotenv-tui sets itself, such as an answer to a prompt. Such values
+// are meant literally, so those holding " #" or a line break are quoted
+// too. Values already quoted are returned as they are.
+func QuoteValue(kv KeyValue) KeyValue {
+	if kv.Quoted == "" && (needsQuotes(kv.Value) || strings.Contains(kv.Value, "\n")) {
+		kv.Quoted = quoteFor(kv.Value)
+	}
+	return kv
+}
+
+// quoteFor returns the quote that keeps value's meaning: a double quote
+// unless value contains a double quote or a backslash, then a single quote
+// unless it contains a single quote or "$" too, and otherwise none.
+func quoteFor(value string) string {
+	switch {
+	case !strings.ContainsAny(value, `"\`):
+		return `"`
+	case !strings.ContainsAny(value, `'$`):
+		return "'"
+	}
+	return ""
+}
+
+// needsQuotes reports whether an unquoted value may be misread: it holds
+// a space, tab or "#", starts or ends with whitespace, or starts with a
+// quote.
+func needsQuotes(value string) bool {
+	return strings.ContainsAny(value, " \t#") || strings.TrimSpace(value) != value ||
+		strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'")
+}
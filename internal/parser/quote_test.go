@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteWithOptions(t *testing.T) {
+	input := "A=plain\nB=two words\nC= padded\nD=a#b\nE=x # note\nF=\"kept\"\nG='$HOME x'\nH=say \"hi\" now\nI=it's \"x\" y\nJ=\n"
+	tests := []struct {
+		policy QuotePolicy
+		want   string
+	}{
+		{QuotePreserve, input},
+		{QuoteWhenNeeded, "A=plain\nB=\"two words\"\nC=\" padded\"\nD=\"a#b\"\nE=x # note\nF=\"kept\"\nG='$HOME x'\nH='say \"hi\" now'\nI=it's \"x\" y\nJ=\n"},
+		{QuoteAlways, "A=\"plain\"\nB=\"two words\"\nC=\" padded\"\nD=\"a#b\"\nE=x # note\nF=\"kept\"\nG='$HOME x'\nH='say \"hi\" now'\nI=it's \"x\" y\nJ=\"\"\n"},
+	}
+
+	entries, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			var out strings.Builder
+			if err := WriteWithOptions(&out, entries, WriteOptions{QuotePolicy: tt.policy}); err != nil {
+				t.Fatalf("WriteWithOptions() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("WriteWithOptions() =\n%q\nwant:\n%q", out.String(), tt.want)
+			}
+
+			// Quoted output parses back to the same values.
+			again, err := Parse(strings.NewReader(out.String()))
+			if err != nil {
+				t.Fatalf("Parse() of output error = %v", err)
+			}
+			for i, entry := range again {
+				if kv, ok := entry.(KeyValue); ok && kv.Value != entries[i].(KeyValue).Value {
+					t.Errorf("%s = %q after writing, want %q", kv.Key, kv.Value, entries[i].(KeyValue).Value)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteKeepsInlineComments(t *testing.T) {
+	input := "PORT=3000 # dev port\nNAME='my app' # shown in the title\nexport HOST=localhost  # local only\n"
+	entries, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for _, policy := range []QuotePolicy{QuotePreserve, QuoteWhenNeeded, QuoteAlways} {
+		t.Run(string(policy), func(t *testing.T) {
+			var out strings.Builder
+			if err := WriteWithOptions(&out, entries, WriteOptions{QuotePolicy: policy}); err != nil {
+				t.Fatalf("WriteWithOptions() error = %v", err)
+			}
+			if out.String() != input {
+				t.Errorf("WriteWithOptions() = %q, want the inline comments kept outside any quotes: %q", out.String(), input)
+			}
+		})
+	}
+}
+
+func TestQuoteValue(t *testing.T) {
+	tests := []struct {
+		value, quoted, want string
+	}{
+		{"plain", "", ""},
+		{"two words", "", `"`},
+		{" padded", "", `"`},
+		{"red # not blue", "", `"`},
+		{"line\nbreak", "", `"`},
+		{`"quoted"`, "", "'"},
+		{"it's", "", ""},
+		{`it's "$x"`, "", ""},
+		{"kept as is", "'", "'"},
+	}
+	for _, tt := range tests {
+		got := QuoteValue(KeyValue{Key: "K", Value: tt.value, Quoted: tt.quoted})
+		if got.Quoted != tt.want || got.Value != tt.value {
+			t.Errorf("QuoteValue(%q).Quoted = %q, want %q", tt.value, got.Quoted, tt.want)
+		}
+	}
+}
+
+func TestWriteOptionsAreExplicit(t *testing.T) {
+	opts := WriteOptions{QuotePolicy: QuoteWhenNeeded}
+	kv := KeyValue{Key: "NAME", Value: "my app"}
+
+	var out strings.Builder
+	ew := NewEntryWriterWithOptions(&out, opts)
+	if err := ew.Write(kv); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := ew.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := out.String(); got != "NAME=\"my app\"\n" {
+		t.Errorf("EntryWriter with options wrote %q", got)
+	}
+	if got := EntryToStringWithOptions(kv, opts); got != `NAME="my app"` {
+		t.Errorf("EntryToStringWithOptions() = %q", got)
+	}
+
+	// Without options, values are written as they are.
+	out.Reset()
+	if err := Write(&out, []Entry{kv}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := out.String(); got != "NAME=my app\n" {
+		t.Errorf("Write() = %q", got)
+	}
+	if got := EntryToString(kv); got != "NAME=my app" {
+		t.Errorf("EntryToString() = %q", got)
+	}
+}
+
+func TestParseQuotePolicy(t *testing.T) {
+	if policy, err := ParseQuotePolicy(""); err != nil || policy != QuotePreserve {
+		t.Errorf("ParseQuotePolicy(\"\") = %q, %v", policy, err)
+	}
+	if policy, err := ParseQuotePolicy("When-Needed"); err != nil || policy != QuoteWhenNeeded {
+		t.Errorf("ParseQuotePolicy(\"When-Needed\") = %q, %v", policy, err)
+	}
+	if _, err := ParseQuotePolicy("never"); err == nil {
+		t.Error("ParseQuotePolicy(\"never\") should fail")
+	}
+}
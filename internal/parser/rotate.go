@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"strings"
+	"time"
+)
+
+// RotatePrefix starts a rotation annotation, a comment in the comment
+// block above a key giving the day its value must be rotated by, e.g.
+// "# dotenv-tui: rotate-by=2026-06-01".
+const RotatePrefix = "# dotenv-tui: rotate-by="
+
+// RotateBy returns the rotation day annotated for each key, in the local
+// time zone, and the annotations whose date does not parse, keyed the same
+// way. An annotation applies to the first key after it, across other
+// comments but not across blank lines.
+func RotateBy(entries []Entry) (dates map[string]time.Time, invalid map[string]string) {
+	dates = make(map[string]time.Time)
+	invalid = make(map[string]string)
+	var pending string
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case Comment:
+			if value, ok := strings.CutPrefix(e.Text, RotatePrefix); ok {
+				pending = strings.TrimSpace(value)
+			}
+		case KeyValue:
+			if pending != "" {
+				if day, err := time.ParseInLocation(time.DateOnly, pending, time.Local); err == nil {
+					dates[e.Key] = day
+				} else {
+					invalid[e.Key] = pending
+				}
+			}
+			pending = ""
+		default:
+			pending = ""
+		}
+	}
+	return dates, invalid
+}
+
+// Overdue reports whether the rotation day has passed at now.
+func Overdue(day, now time.Time) bool {
+	return !now.Before(day.AddDate(0, 0, 1))
+}
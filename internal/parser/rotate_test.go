@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotateBy(t *testing.T) {
+	input := `# dotenv-tui: rotate-by=2026-06-01
+# Stripe live key
+STRIPE_KEY=sk_live_x
+# dotenv-tui: rotate-by=soon
+API_KEY=abc
+# dotenv-tui: rotate-by=2026-07-01
+
+PORT=3000
+`
+	entries, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	dates, invalid := RotateBy(entries)
+	want := time.Date(2026, 6, 1, 0, 0, 0, 0, time.Local)
+	if len(dates) != 1 || !dates["STRIPE_KEY"].Equal(want) {
+		t.Errorf("dates = %v, want STRIPE_KEY on %v", dates, want)
+	}
+	if len(invalid) != 1 || invalid["API_KEY"] != "soon" {
+		t.Errorf("invalid = %v, want API_KEY: soon", invalid)
+	}
+}
+
+func TestOverdue(t *testing.T) {
+	day := time.Date(2026, 6, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2026, 5, 31, 23, 0, 0, 0, time.Local), false},
+		{time.Date(2026, 6, 1, 23, 59, 0, 0, time.Local), false},
+		{time.Date(2026, 6, 2, 0, 0, 0, 0, time.Local), true},
+	}
+	for _, tt := range tests {
+		if got := Overdue(day, tt.now); got != tt.want {
+			t.Errorf("Overdue(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
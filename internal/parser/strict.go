@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LineError describes a single malformed line reported by ParseStrict.
+type LineError struct {
+	Line   int    // 1-based line number where the problem starts
+	Text   string // offending line content
+	Reason string // human-readable description of the problem
+}
+
+// Error implements the error interface.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// ParseErrors is a multi-error listing every malformed line in a file.
+type ParseErrors []*LineError
+
+// Error joins all line errors, one per line.
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, le := range e {
+		msgs[i] = le.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap exposes the individual line errors to errors.Is and errors.As.
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, le := range e {
+		errs[i] = le
+	}
+	return errs
+}
+
+// AsParseErrors extracts the line errors from an error returned by ParseStrict.
+func AsParseErrors(err error) (ParseErrors, bool) {
+	var pe ParseErrors
+	if errors.As(err, &pe) {
+		return pe, true
+	}
+	return nil, false
+}
+
+// validateKeyValue checks a parsed key-value line for strict-mode problems.
+func validateKeyValue(lineNum int, line string, kv KeyValue) []*LineError {
+	var errs []*LineError
+
+	switch {
+	case kv.Key == "":
+		errs = append(errs, &LineError{Line: lineNum, Text: line, Reason: "empty key"})
+	case !IsValidKey(kv.Key):
+		errs = append(errs, &LineError{Line: lineNum, Text: line, Reason: fmt.Sprintf("invalid characters in key %q", kv.Key)})
+	}
+
+	if stray := strayAfterQuote(extractValuePart(line)); stray != "" {
+		errs = append(errs, &LineError{Line: lineNum, Text: line, Reason: fmt.Sprintf("unexpected text %q after closing quote", stray)})
+	}
+
+	return errs
+}
+
+// IsValidKey reports whether key is a portable environment variable name:
+// letters, digits and underscores, not starting with a digit.
+func IsValidKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, ch := range key {
+		switch {
+		case ch == '_', ch >= 'A' && ch <= 'Z', ch >= 'a' && ch <= 'z':
+		case ch >= '0' && ch <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// strayAfterQuote returns any non-comment text following the closing quote of
+// a quoted value, or an empty string if there is none.
+func strayAfterQuote(value string) string {
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		return ""
+	}
+	quote := value[0]
+
+	escaped := false
+	for i := 1; i < len(value); i++ {
+		ch := value[i]
+		if ch == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		if ch == quote && !escaped {
+			rest := strings.TrimSpace(value[i+1:])
+			if rest == "" || strings.HasPrefix(rest, "#") {
+				return ""
+			}
+			return rest
+		}
+		escaped = false
+	}
+	return ""
+}
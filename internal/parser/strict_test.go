@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseStrict(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantLines   []int
+		wantReasons []string
+	}{
+		{
+			name:  "valid file has no errors",
+			input: "# comment\nKEY=value\n\nexport OTHER=\"quoted\" # trailing comment\n",
+		},
+		{
+			name:        "missing equals sign",
+			input:       "KEY=value\nstray text\n",
+			wantLines:   []int{2},
+			wantReasons: []string{"missing '='"},
+		},
+		{
+			name:        "invalid key characters",
+			input:       "MY KEY=value\nKEY-NAME=value\n1KEY=value\n",
+			wantLines:   []int{1, 2, 3},
+			wantReasons: []string{"invalid characters", "invalid characters", "invalid characters"},
+		},
+		{
+			name:        "empty key",
+			input:       "=value\n",
+			wantLines:   []int{1},
+			wantReasons: []string{"empty key"},
+		},
+		{
+			name:        "text after closing quote",
+			input:       "KEY=\"value\" junk\n",
+			wantLines:   []int{1},
+			wantReasons: []string{"unexpected text"},
+		},
+		{
+			name:        "unclosed multiline quote",
+			input:       "A=1\nKEY=\"line1\nline2\n",
+			wantLines:   []int{2},
+			wantReasons: []string{"unclosed"},
+		},
+		{
+			name:        "multiline value reports its starting line",
+			input:       "A=1\nBAD KEY=\"line1\nline2\"\n",
+			wantLines:   []int{2},
+			wantReasons: []string{"invalid characters"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseStrict(strings.NewReader(tt.input))
+
+			if len(tt.wantLines) == 0 {
+				if err != nil {
+					t.Fatalf("ParseStrict() unexpected error: %v", err)
+				}
+				return
+			}
+
+			lineErrs, ok := AsParseErrors(err)
+			if !ok {
+				t.Fatalf("ParseStrict() error = %v, expected ParseErrors", err)
+			}
+			if len(lineErrs) != len(tt.wantLines) {
+				t.Fatalf("ParseStrict() got %d errors, expected %d: %v", len(lineErrs), len(tt.wantLines), err)
+			}
+			for i, le := range lineErrs {
+				if le.Line != tt.wantLines[i] {
+					t.Errorf("error %d line = %d, expected %d", i, le.Line, tt.wantLines[i])
+				}
+				if !strings.Contains(le.Reason, tt.wantReasons[i]) {
+					t.Errorf("error %d reason = %q, expected to contain %q", i, le.Reason, tt.wantReasons[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseStrictKeepsEntries(t *testing.T) {
+	entries, err := ParseStrict(strings.NewReader("KEY=value\nstray\n"))
+	if err == nil {
+		t.Fatal("ParseStrict() expected error for stray line")
+	}
+
+	expected := []Entry{
+		KeyValue{Key: "KEY", Value: "value"},
+		Comment{Text: "stray"},
+	}
+	compareEntries(t, entries, expected)
+}
+
+func TestParseErrorsUnwrap(t *testing.T) {
+	_, err := ParseStrict(strings.NewReader("one\ntwo\n"))
+
+	var lineErr *LineError
+	if !errors.As(err, &lineErr) {
+		t.Fatalf("errors.As(*LineError) failed for %v", err)
+	}
+	if lineErr.Line != 1 {
+		t.Errorf("first LineError line = %d, expected 1", lineErr.Line)
+	}
+	if !strings.Contains(err.Error(), "line 2:") {
+		t.Errorf("Error() = %q, expected all lines listed", err.Error())
+	}
+}
+
+func TestParseUnchangedByStrictMode(t *testing.T) {
+	entries, err := Parse(strings.NewReader("stray text\nMY KEY=value\n"))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	expected := []Entry{
+		Comment{Text: "stray text"},
+		KeyValue{Key: "MY KEY", Value: "value"},
+	}
+	compareEntries(t, entries, expected)
+}
+
+func TestIsValidKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{"KEY", true},
+		{"_PRIVATE", true},
+		{"key_2", true},
+		{"", false},
+		{"2KEY", false},
+		{"MY-KEY", false},
+		{"MY KEY", false},
+		{"KEY.NAME", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := IsValidKey(tt.key); got != tt.expected {
+				t.Errorf("IsValidKey(%q) = %v, expected %v", tt.key, got, tt.expected)
+			}
+		})
+	}
+}
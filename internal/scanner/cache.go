@@ -0,0 +1,146 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheFileName is the file name of the scan cache, stored in the
+// dotenv-tui state directory.
+const CacheFileName = "scan-cache.json"
+
+// cacheVersion is bumped whenever the cache format or what it keeps
+// changes, so older caches are discarded rather than misread.
+const cacheVersion = 1
+
+// scanCache remembers the listing of each scanned directory along with its
+// modification time. A directory's mtime changes whenever an entry is
+// added, removed or renamed in it, so a listing stays valid for as long as
+// the mtime does, and unchanged directories need a stat instead of a read.
+type scanCache struct {
+	Version int                  `json:"version"`
+	Dirs    map[string]cachedDir `json:"dirs"`
+
+	path  string
+	seen  map[string]bool
+	dirty bool
+}
+
+// cachedDir is the listing of one directory, keeping only what a scan for
+// env files looks at: subdirectories, symlinks and candidate files.
+type cachedDir struct {
+	ModTime int64            `json:"mtime"`
+	Entries []cachedDirEntry `json:"entries"`
+}
+
+type cachedDirEntry struct {
+	Name string `json:"name"`
+	Dir  bool   `json:"dir,omitempty"`
+	Link bool   `json:"link,omitempty"`
+}
+
+// loadCache reads the cache at path. A missing, unreadable or outdated
+// cache yields an empty one, since it only saves work; an empty path
+// disables caching.
+func loadCache(path string) *scanCache {
+	if path == "" {
+		return nil
+	}
+	c := &scanCache{path: path, seen: make(map[string]bool)}
+	if data, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(data, c) != nil || c.Version != cacheVersion {
+			c.Dirs = nil
+		}
+	}
+	if c.Dirs == nil {
+		c.Dirs = make(map[string]cachedDir)
+	}
+	c.Version = cacheVersion
+	return c
+}
+
+// list returns the entries of dir, whose absolute path is key, from the
+// cache while dir's mtime is unchanged and from disk otherwise.
+func (c *scanCache) list(dir, key string) ([]cachedDirEntry, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	c.seen[key] = true
+	mtime := info.ModTime().UnixNano()
+	if cached, ok := c.Dirs[key]; ok && cached.ModTime == mtime {
+		return cached.Entries, nil
+	}
+
+	entries, err := readDir(dir, cacheCandidate)
+	if err != nil {
+		return nil, err
+	}
+	c.Dirs[key] = cachedDir{ModTime: mtime, Entries: entries}
+	c.dirty = true
+	return entries, nil
+}
+
+// save drops the cached directories under root that the scan did not
+// visit, such as deleted or newly ignored ones, and writes the cache back.
+// Failures only cost a full scan next time, so they are ignored.
+func (c *scanCache) save(root string) {
+	prefix := root + string(filepath.Separator)
+	for key := range c.Dirs {
+		if !c.seen[key] && (key == root || strings.HasPrefix(key, prefix)) {
+			delete(c.Dirs, key)
+			c.dirty = true
+		}
+	}
+	if !c.dirty {
+		return
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return
+	}
+	// Write to a temporary file first, so concurrent scans never read a
+	// partly written cache.
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), CacheFileName+".*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	if cerr := tmp.Close(); werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// readDir lists dir, keeping subdirectories, symlinks and the files keep
+// accepts.
+func readDir(dir string, keep func(fileName string) bool) ([]cachedDirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]cachedDirEntry, 0, len(entries))
+	for _, entry := range entries {
+		e := cachedDirEntry{Name: entry.Name(), Dir: entry.IsDir(), Link: entry.Type()&os.ModeSymlink != 0}
+		if e.Dir || e.Link || keep(e.Name) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// cacheCandidate reports whether a file may be matched by any of the scans
+// using the cache: env files, examples and .envrc files, with extended
+// names included.
+func cacheCandidate(fileName string) bool {
+	return isEnvFile(fileName, true) || isExampleFile(fileName, true) || fileName == ".envrc"
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestScanCache(t *testing.T) {
+	root := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), "state", CacheFileName)
+	opts := Options{CachePath: cachePath}
+	writeFile(t, root, ".env", "A=1")
+	mkdir(t, root, "api")
+	writeFile(t, root, "api/.env", "B=2")
+	writeFile(t, root, "api/.env.example", "B=")
+
+	scan := func() []string {
+		t.Helper()
+		result, err := ScanWithOptions(t.Context(), root, opts)
+		if err != nil {
+			t.Fatalf("ScanWithOptions() error = %v", err)
+		}
+		sort.Strings(result.Files)
+		return result.Files
+	}
+	// setMtime pins a directory's mtime, so tests do not depend on the
+	// filesystem's timestamp resolution.
+	setMtime := func(dir string, mtime time.Time) {
+		t.Helper()
+		if err := os.Chtimes(filepath.Join(root, dir), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	setMtime("api", old)
+
+	want := []string{".env", filepath.Join("api", ".env")}
+	if got := scan(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("first scan = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("cache not written: %v", err)
+	}
+
+	// While the directory's mtime is unchanged, its cached listing is used.
+	writeFile(t, root, "api/.env.local", "C=3")
+	setMtime("api", old)
+	if got := scan(); !reflect.DeepEqual(got, want) {
+		t.Errorf("cached scan = %v, want %v", got, want)
+	}
+	examples, err := ScanExamplesWithOptions(t.Context(), root, opts)
+	if err != nil {
+		t.Fatalf("ScanExamplesWithOptions() error = %v", err)
+	}
+	if wantExamples := []string{filepath.Join("api", ".env.example")}; !reflect.DeepEqual(examples.Files, wantExamples) {
+		t.Errorf("cached example scan = %v, want %v", examples.Files, wantExamples)
+	}
+
+	// A new mtime invalidates that directory.
+	setMtime("api", old.Add(time.Hour))
+	want = []string{".env", filepath.Join("api", ".env"), filepath.Join("api", ".env.local")}
+	if got := scan(); !reflect.DeepEqual(got, want) {
+		t.Errorf("scan after change = %v, want %v", got, want)
+	}
+
+	// Without a cache path, the directory is read from disk.
+	if err := os.Remove(filepath.Join(root, "api", ".env.local")); err != nil {
+		t.Fatal(err)
+	}
+	setMtime("api", old.Add(time.Hour))
+	opts.CachePath = ""
+	want = []string{".env", filepath.Join("api", ".env")}
+	if got := scan(); !reflect.DeepEqual(got, want) {
+		t.Errorf("uncached scan = %v, want %v", got, want)
+	}
+}
+
+func TestScanCacheCorrupt(t *testing.T) {
+	root := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), CacheFileName)
+	writeFile(t, root, ".env", "A=1")
+	if err := os.WriteFile(cachePath, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanWithOptions(t.Context(), root, Options{CachePath: cachePath})
+	if err != nil {
+		t.Fatalf("ScanWithOptions() error = %v", err)
+	}
+	if want := []string{".env"}; !reflect.DeepEqual(result.Files, want) {
+		t.Errorf("ScanWithOptions() = %v, want %v", result.Files, want)
+	}
+	if c := loadCache(cachePath); len(c.Dirs) != 1 {
+		t.Errorf("rewritten cache has %d directories, want 1", len(c.Dirs))
+	}
+}
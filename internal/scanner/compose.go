@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeFiles are the Docker Compose files read at the scan root, in the
+// order docker compose looks for them, followed by their overrides.
+var composeFiles = []string{
+	"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml",
+	"compose.override.yaml", "compose.override.yml", "docker-compose.override.yaml", "docker-compose.override.yml",
+}
+
+// ComposeService is a Docker Compose service and the environment it is
+// given.
+type ComposeService struct {
+	Name string
+	// File is the path of the compose file declaring the service.
+	File string
+	// EnvFiles are the files of its env_file attribute, in order.
+	EnvFiles []ComposeEnvFile
+	// Environment lists the keys set by its environment attribute.
+	Environment []string
+}
+
+// ComposeEnvFile is a file referenced by a service's env_file attribute.
+type ComposeEnvFile struct {
+	// Path is the file path joined with the scan root, as Scan returns it.
+	Path string
+	// Required is false for entries marked "required: false", which
+	// docker compose skips when the file is missing.
+	Required bool
+	// Exists reports whether the file exists. Paths using variable
+	// interpolation cannot be checked and always exist.
+	Exists bool
+}
+
+// FindComposeServices returns the services of the Docker Compose files at
+// root (compose.yaml, docker-compose.yml and their variants and
+// overrides), sorted by file and then by name. A root without compose
+// files yields none.
+func FindComposeServices(root string) ([]ComposeService, error) {
+	var services []ComposeService
+	for _, name := range composeFiles {
+		path := filepath.Join(root, name)
+		data, err := readManifest(path)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			continue
+		}
+		var manifest struct {
+			Services map[string]struct {
+				EnvFile     composeEnvFiles    `yaml:"env_file"`
+				Environment composeEnvironment `yaml:"environment"`
+			} `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		names := make([]string, 0, len(manifest.Services))
+		for service := range manifest.Services {
+			names = append(names, service)
+		}
+		sort.Strings(names)
+		for _, service := range names {
+			def := manifest.Services[service]
+			s := ComposeService{Name: service, File: path, Environment: def.Environment}
+			for _, f := range def.EnvFile {
+				if !filepath.IsAbs(f.Path) {
+					f.Path = filepath.Join(root, filepath.FromSlash(f.Path))
+				}
+				f.Exists = strings.Contains(f.Path, "$") || fileExists(f.Path)
+				s.EnvFiles = append(s.EnvFiles, f)
+			}
+			services = append(services, s)
+		}
+	}
+	return services, nil
+}
+
+// composeEnvFiles reads an env_file attribute: a path, or a list of paths
+// and {path, required} mappings.
+type composeEnvFiles []ComposeEnvFile
+
+func (e *composeEnvFiles) UnmarshalYAML(node *yaml.Node) error {
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+	for _, item := range items {
+		f := ComposeEnvFile{Required: true}
+		switch item.Kind {
+		case yaml.ScalarNode:
+			f.Path = item.Value
+		case yaml.MappingNode:
+			var long struct {
+				Path     string `yaml:"path"`
+				Required *bool  `yaml:"required"`
+			}
+			if err := item.Decode(&long); err != nil {
+				return err
+			}
+			f.Path = long.Path
+			if long.Required != nil {
+				f.Required = *long.Required
+			}
+		default:
+			return fmt.Errorf("line %d: env_file must be a path or a list of paths", item.Line)
+		}
+		if f.Path != "" {
+			*e = append(*e, f)
+		}
+	}
+	return nil
+}
+
+// composeEnvironment reads the keys of an environment attribute, written
+// either as a mapping or as a list of KEY=VALUE or KEY items.
+type composeEnvironment []string
+
+func (e *composeEnvironment) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			*e = append(*e, node.Content[i].Value)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			key, _, _ := strings.Cut(item.Value, "=")
+			*e = append(*e, key)
+		}
+	default:
+		return fmt.Errorf("line %d: environment must be a mapping or a list", node.Line)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindComposeServices(t *testing.T) {
+	dir := t.TempDir()
+	mkdir(t, dir, "api")
+	writeFile(t, dir, ".env", "PORT=3000\n")
+	writeFile(t, dir, filepath.Join("api", ".env"), "TOKEN=abc\n")
+	writeFile(t, dir, "compose.yaml", `services:
+  worker:
+    image: worker
+    env_file: .env
+    environment:
+      - QUEUE=jobs
+      - DEBUG
+  api:
+    build: ./api
+    env_file:
+      - ./.env
+      - api/.env
+      - path: ./api/.env.local
+        required: false
+      - ./api/.env.${STAGE}
+    environment:
+      DATABASE_URL: postgres://db
+  db:
+    image: postgres
+`)
+	writeFile(t, dir, "docker-compose.override.yml", "services:\n  api:\n    env_file: [secrets.env]\n")
+
+	got, err := FindComposeServices(dir)
+	if err != nil {
+		t.Fatalf("FindComposeServices() unexpected error: %v", err)
+	}
+	compose, override := filepath.Join(dir, "compose.yaml"), filepath.Join(dir, "docker-compose.override.yml")
+	want := []ComposeService{
+		{Name: "api", File: compose, Environment: []string{"DATABASE_URL"}, EnvFiles: []ComposeEnvFile{
+			{Path: filepath.Join(dir, ".env"), Required: true, Exists: true},
+			{Path: filepath.Join(dir, "api", ".env"), Required: true, Exists: true},
+			{Path: filepath.Join(dir, "api", ".env.local")},
+			{Path: filepath.Join(dir, "api", ".env.${STAGE}"), Required: true, Exists: true},
+		}},
+		{Name: "db", File: compose},
+		{Name: "worker", File: compose, Environment: []string{"QUEUE", "DEBUG"}, EnvFiles: []ComposeEnvFile{
+			{Path: filepath.Join(dir, ".env"), Required: true, Exists: true},
+		}},
+		{Name: "api", File: override, EnvFiles: []ComposeEnvFile{
+			{Path: filepath.Join(dir, "secrets.env"), Required: true},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindComposeServices() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestFindComposeServicesNone(t *testing.T) {
+	got, err := FindComposeServices(t.TempDir())
+	if err != nil || got != nil {
+		t.Errorf("FindComposeServices() = %v, %v, want none", got, err)
+	}
+
+	dir := t.TempDir()
+	writeFile(t, dir, "docker-compose.yml", "services:\n  api:\n    env_file: {path: 1}\n    environment: 3\n")
+	if _, err := FindComposeServices(dir); err == nil {
+		t.Error("FindComposeServices() expected error for an invalid environment")
+	}
+}
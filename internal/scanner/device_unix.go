@@ -0,0 +1,21 @@
+//go:build !windows
+
+package scanner
+
+import (
+	"os"
+	"syscall"
+)
+
+// deviceID returns the ID of the filesystem holding path.
+func deviceID(path string) (uint64, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, false
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(st.Dev), true //nolint:unconvert // Dev is int32 on some platforms
+}
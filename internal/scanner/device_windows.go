@@ -0,0 +1,8 @@
+//go:build windows
+
+package scanner
+
+// deviceID is not supported on Windows, so OneFilesystem has no effect there.
+func deviceID(string) (uint64, bool) {
+	return 0, false
+}
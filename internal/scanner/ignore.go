@@ -0,0 +1,155 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the gitignore-style file read from the scan root.
+const IgnoreFileName = ".dotenvtuiignore"
+
+// ignoreRule is a single parsed gitignore-style pattern.
+type ignoreRule struct {
+	pattern  string
+	segments []string
+	negate   bool
+	dirOnly  bool
+	anchored bool // matched against the full relative path instead of the base name
+}
+
+// ignoreMatcher applies ignore rules in order; the last matching rule wins,
+// so later negated patterns can re-include earlier exclusions.
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// newIgnoreMatcher parses patterns using gitignore syntax: blank lines and
+// "#" comments are skipped, "!" negates, a trailing "/" only matches
+// directories, a leading or inner "/" anchors the pattern to the scan root,
+// and "**" matches any number of directories.
+func newIgnoreMatcher(patterns []string) (*ignoreMatcher, error) {
+	m := &ignoreMatcher{}
+
+	for _, raw := range patterns {
+		p := strings.TrimRight(raw, " \t\r")
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+
+		rule := ignoreRule{pattern: p}
+		if strings.HasPrefix(p, "!") {
+			rule.negate = true
+			p = p[1:]
+		} else if strings.HasPrefix(p, `\#`) || strings.HasPrefix(p, `\!`) {
+			p = p[1:]
+		}
+		if strings.HasSuffix(p, "/") {
+			rule.dirOnly = true
+			p = strings.TrimRight(p, "/")
+		}
+		if strings.Contains(p, "/") {
+			rule.anchored = true
+			p = strings.TrimPrefix(p, "/")
+		}
+		if p == "" {
+			continue
+		}
+
+		rule.segments = strings.Split(p, "/")
+		for _, seg := range rule.segments {
+			if _, err := path.Match(seg, ""); err != nil {
+				return nil, fmt.Errorf("invalid ignore pattern %q: %w", rule.pattern, err)
+			}
+		}
+		m.rules = append(m.rules, rule)
+	}
+
+	return m, nil
+}
+
+// loadIgnoreFile reads the patterns of the ignore file at root. A missing
+// file yields no patterns.
+func loadIgnoreFile(root string) ([]string, error) {
+	file, err := os.Open(filepath.Join(root, IgnoreFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", IgnoreFileName, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var patterns []string
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		patterns = append(patterns, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	return patterns, nil
+}
+
+// ignored reports whether the slash-separated path relative to the scan root
+// is excluded.
+func (m *ignoreMatcher) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.matches(rel) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+func (r ignoreRule) matches(rel string) bool {
+	parts := strings.Split(rel, "/")
+	if !r.anchored {
+		ok, _ := path.Match(r.segments[0], parts[len(parts)-1])
+		return ok
+	}
+	return matchSegments(r.segments, parts)
+}
+
+// MatchGlob reports whether the slash-separated path rel matches pattern,
+// where "*" matches within a path segment and "**" matches any number of
+// directories. A pattern without "/" is matched against the base name only.
+func MatchGlob(pattern, rel string) bool {
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "./")
+	parts := strings.Split(rel, "/")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, parts[len(parts)-1])
+		return ok
+	}
+	return matchSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), parts)
+}
+
+// matchSegments matches path segments against pattern segments, where a "**"
+// segment matches zero or more path segments.
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
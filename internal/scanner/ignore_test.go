@@ -0,0 +1,82 @@
+package scanner
+
+import "testing"
+
+func TestIgnoreMatcher(t *testing.T) {
+	m, err := newIgnoreMatcher([]string{
+		"# fixtures are never real config",
+		"fixtures/",
+		"examples/demo",
+		"*.local",
+		"!keep/.env.local",
+		"/top-only",
+		"**/generated/**",
+		"",
+	})
+	if err != nil {
+		t.Fatalf("newIgnoreMatcher() error = %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"fixtures", true, true},
+		{"app/fixtures", true, true},
+		{"fixtures", false, false},
+		{"examples/demo", true, true},
+		{"app/examples/demo", true, false},
+		{"examples/other", true, false},
+		{".env.local", false, true},
+		{"app/.env.local", false, true},
+		{"keep/.env.local", false, false},
+		{"top-only", true, true},
+		{"app/top-only", true, false},
+		{"app/generated/.env", false, true},
+		{".env", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := m.ignored(tt.path, tt.isDir); got != tt.expected {
+				t.Errorf("ignored(%q, %v) = %v, expected %v", tt.path, tt.isDir, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIgnoreMatcherInvalidPattern(t *testing.T) {
+	if _, err := newIgnoreMatcher([]string{"[unclosed"}); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+}
+
+func TestLoadIgnoreFileMissing(t *testing.T) {
+	patterns, err := loadIgnoreFile(t.TempDir())
+	if err != nil || patterns != nil {
+		t.Errorf("loadIgnoreFile() = %v, %v; expected no patterns and no error", patterns, err)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		expected      bool
+	}{
+		{"services/*/.env", "services/api/.env", true},
+		{"services/*/.env", "services/api/.env.local", false},
+		{"services/*/.env", "apps/web/.env", false},
+		{"./services/*/.env*", "services/api/.env.local", true},
+		{"**/.env.production", "apps/web/config/.env.production", true},
+		{"services/**", "services/api/nested/.env", true},
+		{".env.local", "apps/web/.env.local", true},
+		{"/.env", ".env", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.path); got != tt.expected {
+			t.Errorf("MatchGlob(%q, %q) = %v, expected %v", tt.pattern, tt.path, got, tt.expected)
+		}
+	}
+}
@@ -2,9 +2,14 @@
 package scanner
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -17,73 +22,373 @@ var skipDirs = map[string]bool{
 	".next":        true,
 	".nuxt":        true,
 	"__pycache__":  true,
+	".dotenv-tui":  true,
+}
+
+// Options tunes a scan beyond the built-in skip list.
+type Options struct {
+	// Exclude holds extra gitignore-style patterns, applied after those in
+	// the root's .dotenvtuiignore file.
+	Exclude []string
+	// MaxDepth limits how many directory levels below root are entered.
+	// Files directly in root are always scanned; 0 means no limit.
+	MaxDepth int
+	// FollowSymlinks descends into symlinked directories. Each real
+	// directory is visited once, so symlink cycles terminate.
+	FollowSymlinks bool
+	// OneFilesystem stops the scan from entering directories on a different
+	// filesystem than root, such as mounted volumes.
+	OneFilesystem bool
+	// ExtendedNames also matches env files that don't start with ".env",
+	// such as app.env, env.local and env.list.
+	ExtendedNames bool
+	// CachePath is the file keeping directory listings between scans,
+	// each reused while the directory's mtime is unchanged. Empty disables
+	// caching.
+	CachePath string
+}
+
+// Result is what a scan found, along with the directories it could not read.
+type Result struct {
+	// Files holds the matching files, relative to the root.
+	Files []string
+	// Skipped holds the directories left out because they could not be
+	// listed, such as ones the user has no permission to read.
+	Skipped []Skipped
+}
+
+// Skipped is a directory a scan could not read.
+type Skipped struct {
+	// Path is the directory relative to the root, "." for the root itself.
+	Path string
+	// Reason says why, such as "permission denied".
+	Reason string
+}
+
+// skipReason describes err briefly, without the path it carries.
+func skipReason(err error) string {
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return "permission denied"
+	case errors.Is(err, fs.ErrNotExist):
+		return "not found"
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// SkippedSummary describes skipped directories by reason, such as
+// "3 directories skipped (permission denied)", or returns "" when none were.
+func SkippedSummary(skipped []Skipped) string {
+	counts := make(map[string]int)
+	for _, s := range skipped {
+		counts[s.Reason]++
+	}
+	parts := make([]string, 0, len(counts))
+	for _, reason := range slices.Sorted(maps.Keys(counts)) {
+		noun := "directories"
+		if counts[reason] == 1 {
+			noun = "directory"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s skipped (%s)", counts[reason], noun, reason))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// walker holds the state of a single scan.
+type walker struct {
+	ctx     context.Context
+	err     error // set when ctx is done, ending the walk
+	opts    Options
+	ignore  *ignoreMatcher
+	match   func(fileName string) bool
+	rootDev uint64
+	hasDev  bool
+	visited map[string]bool
+	files   []string
+	skipped []Skipped
+	cache   *scanCache
+	absRoot string
 }
 
 // scanFiles is a helper function that walks a directory tree and collects files
-// matching the provided predicate function.
-func scanFiles(root string, match func(fileName string) bool) ([]string, error) {
-	var files []string
+// matching the provided predicate function. It stops with ctx's error as soon
+// as ctx is done.
+func scanFiles(ctx context.Context, root string, opts Options, match func(fileName string) bool) (Result, error) {
+	patterns, err := loadIgnoreFile(root)
+	if err != nil {
+		return Result{}, err
+	}
+	ignore, err := newIgnoreMatcher(append(patterns, opts.Exclude...))
+	if err != nil {
+		return Result{}, err
+	}
 
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil
+	w := &walker{
+		ctx:     ctx,
+		opts:    opts,
+		ignore:  ignore,
+		match:   match,
+		visited: make(map[string]bool),
+	}
+	if opts.OneFilesystem {
+		w.rootDev, w.hasDev = deviceID(root)
+	}
+	if w.cache = loadCache(opts.CachePath); w.cache != nil {
+		if w.absRoot, err = filepath.Abs(root); err != nil {
+			w.cache = nil
 		}
+	}
+	w.enter(root)
+
+	w.walk(root, "", 0)
+	if w.err != nil {
+		// A partial walk would drop the directories it never reached from
+		// the cache, so it is not saved.
+		return Result{}, w.err
+	}
+	if w.cache != nil {
+		w.cache.save(w.absRoot)
+	}
+	return Result{Files: w.files, Skipped: w.skipped}, nil
+}
+
+// enter records dir as visited and reports whether it was new. Directories
+// are only tracked when following symlinks, the one case where the same
+// directory can be reached twice.
+func (w *walker) enter(dir string) bool {
+	if !w.opts.FollowSymlinks {
+		return true
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false
+	}
+	if w.visited[resolved] {
+		return false
+	}
+	w.visited[resolved] = true
+	return true
+}
 
-		relPath, err := filepath.Rel(root, path)
-		if err != nil {
-			return err
+// walk scans dir, whose path relative to the root is rel, at the given depth.
+// Unreadable directories are skipped rather than failing the whole scan, and
+// recorded in w.skipped.
+func (w *walker) walk(dir, rel string, depth int) {
+	if w.err != nil {
+		return
+	}
+	if err := w.ctx.Err(); err != nil {
+		w.err = err
+		return
+	}
+	entries, err := w.list(dir, rel)
+	if err != nil {
+		path := rel
+		if path == "" {
+			path = "."
 		}
+		w.skipped = append(w.skipped, Skipped{Path: path, Reason: skipReason(err)})
+		return
+	}
 
-		pathParts := strings.Split(relPath, string(filepath.Separator))
-		for _, part := range pathParts {
-			if skipDirs[part] {
-				if d.IsDir() {
-					return fs.SkipDir
-				}
-				return nil
-			}
+	for _, entry := range entries {
+		name := entry.Name
+		if skipDirs[name] {
+			continue
 		}
 
-		if d.IsDir() {
-			return nil
+		path := filepath.Join(dir, name)
+		relPath := name
+		if rel != "" {
+			relPath = filepath.Join(rel, name)
 		}
 
-		fileName := d.Name()
-		if match(fileName) {
-			files = append(files, relPath)
+		isDir := entry.Dir
+		if entry.Link && w.opts.FollowSymlinks {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			isDir = info.IsDir()
 		}
 
-		return nil
-	})
+		if w.ignore.ignored(filepath.ToSlash(relPath), isDir) {
+			continue
+		}
 
-	if err != nil {
-		return nil, fmt.Errorf("error scanning directory: %w", err)
+		if !isDir {
+			if w.match(name) {
+				w.files = append(w.files, relPath)
+			}
+			continue
+		}
+
+		if w.opts.MaxDepth > 0 && depth+1 > w.opts.MaxDepth {
+			continue
+		}
+		if w.hasDev {
+			if dev, ok := deviceID(path); ok && dev != w.rootDev {
+				continue
+			}
+		}
+		if !w.enter(path) {
+			continue
+		}
+		w.walk(path, relPath, depth+1)
 	}
+}
 
-	return files, nil
+// list returns the entries of dir, whose path relative to the root is rel,
+// through the cache when there is one.
+func (w *walker) list(dir, rel string) ([]cachedDirEntry, error) {
+	if w.cache == nil {
+		return readDir(dir, w.match)
+	}
+	return w.cache.list(dir, filepath.Join(w.absRoot, rel))
 }
 
-// Scan recursively finds .env files in a project tree, skipping dependency directories.
+// Scan recursively finds .env files in a project tree, skipping dependency
+// directories and paths listed in the root's .dotenvtuiignore.
 func Scan(root string) ([]string, error) {
-	return scanFiles(root, isEnvFile)
+	result, err := ScanWithOptions(context.Background(), root, Options{})
+	return result.Files, err
 }
 
-// ScanExamples finds .env.example files in a project tree, skipping dependency directories.
+// ScanExamples finds .env.example files (and .sample, .template and .dist
+// variants) in a project tree, skipping dependency
+// directories and paths listed in the root's .dotenvtuiignore.
 func ScanExamples(root string) ([]string, error) {
-	return scanFiles(root, isExampleFile)
+	result, err := ScanExamplesWithOptions(context.Background(), root, Options{})
+	return result.Files, err
+}
+
+// ScanWithOptions is like Scan but also applies opts, reports the
+// directories it skipped, and stops with ctx's error when ctx is done.
+func ScanWithOptions(ctx context.Context, root string, opts Options) (Result, error) {
+	return scanFiles(ctx, root, opts, func(name string) bool {
+		return isEnvFile(name, opts.ExtendedNames)
+	})
+}
+
+// ScanExamplesWithOptions is like ScanExamples but also applies opts, reports the
+// directories it skipped, and stops with ctx's error when ctx is done.
+func ScanExamplesWithOptions(ctx context.Context, root string, opts Options) (Result, error) {
+	return scanFiles(ctx, root, opts, func(name string) bool {
+		return isExampleFile(name, opts.ExtendedNames)
+	})
+}
+
+// ScanDirenv finds the .envrc files direnv loads in a project tree,
+// skipping dependency directories and paths listed in the root's
+// .dotenvtuiignore. They are shell scripts, so they are only read from.
+func ScanDirenv(root string) ([]string, error) {
+	result, err := ScanDirenvWithOptions(context.Background(), root, Options{})
+	return result.Files, err
+}
+
+// ScanDirenvWithOptions is like ScanDirenv but also applies opts, reports the
+// directories it skipped, and stops with ctx's error when ctx is done.
+func ScanDirenvWithOptions(ctx context.Context, root string, opts Options) (Result, error) {
+	return scanFiles(ctx, root, opts, func(name string) bool {
+		return name == ".envrc"
+	})
+}
+
+// sourceExtensions are the file extensions ScanSource treats as source code
+// that may read environment variables.
+var sourceExtensions = map[string]bool{
+	".js": true, ".jsx": true, ".mjs": true, ".cjs": true, ".ts": true, ".tsx": true,
+	".vue": true, ".svelte": true, ".go": true, ".py": true, ".rb": true, ".php": true,
+	".rs": true, ".java": true, ".kt": true, ".cs": true, ".ex": true, ".exs": true,
+	".prisma": true,
+}
+
+// ScanSource finds source files in a project tree, skipping dependency
+// directories and paths listed in the root's .dotenvtuiignore.
+func ScanSource(root string) ([]string, error) {
+	result, err := ScanSourceWithOptions(context.Background(), root, Options{})
+	return result.Files, err
+}
+
+// ScanSourceWithOptions is like ScanSource but also applies opts, reports the
+// directories it skipped, and stops with ctx's error when ctx is done.
+func ScanSourceWithOptions(ctx context.Context, root string, opts Options) (Result, error) {
+	// The cache only keeps env file names, not source files.
+	opts.CachePath = ""
+	return scanFiles(ctx, root, opts, func(name string) bool {
+		return sourceExtensions[strings.ToLower(filepath.Ext(name))]
+	})
+}
+
+// exampleSuffixes are the template suffixes that mark a file as an example
+// rather than a real env file.
+var exampleSuffixes = []string{".example", ".sample", ".template", ".dist"}
+
+// exampleSuffix returns the template suffix of fileName, or "" if it has none.
+func exampleSuffix(fileName string) string {
+	for _, suffix := range exampleSuffixes {
+		if strings.HasSuffix(fileName, suffix) {
+			return suffix
+		}
+	}
+	return ""
+}
+
+// ExampleTarget returns the env file path generated from an example file by
+// stripping its template suffix, e.g. "api/.env.sample" becomes "api/.env".
+func ExampleTarget(examplePath string) string {
+	return strings.TrimSuffix(examplePath, exampleSuffix(examplePath))
+}
+
+// ExamplePaths returns the example files that would generate envPath, one
+// per template suffix, e.g. "api/.env.example" and "api/.env.sample" for
+// "api/.env".
+func ExamplePaths(envPath string) []string {
+	paths := make([]string, 0, len(exampleSuffixes))
+	for _, suffix := range exampleSuffixes {
+		paths = append(paths, envPath+suffix)
+	}
+	return paths
 }
 
 // isEnvFile returns true if the filename represents a .env file.
-// It excludes .env.example files and only matches .env or .env.* patterns.
-func isEnvFile(fileName string) bool {
-	if strings.HasSuffix(fileName, ".example") {
+// It excludes example files and the .env.keys file dotenvx keeps private
+// keys in, and only matches .env or .env.* patterns, plus the conventions
+// accepted by isExtendedEnvName when extended is set.
+func isEnvFile(fileName string, extended bool) bool {
+	if exampleSuffix(fileName) != "" || fileName == ".env.keys" {
 		return false
 	}
 
-	return strings.HasPrefix(fileName, ".env") && (fileName == ".env" || (len(fileName) > 4 && fileName[4] == '.'))
+	if strings.HasPrefix(fileName, ".env") && (fileName == ".env" || (len(fileName) > 4 && fileName[4] == '.')) {
+		return true
+	}
+	return extended && isExtendedEnvName(fileName)
 }
 
-// isExampleFile returns true if the filename represents a .env.example file.
-func isExampleFile(fileName string) bool {
-	return strings.HasPrefix(fileName, ".env") && strings.HasSuffix(fileName, ".example")
+// isExtendedEnvName matches env files outside the .env.* prefix rule:
+// "app.env"-style names and the env.local / env.list files commonly passed
+// to docker --env-file.
+func isExtendedEnvName(fileName string) bool {
+	if fileName == "env.local" || fileName == "env.list" {
+		return true
+	}
+	return len(fileName) > len(".env") && strings.HasSuffix(fileName, ".env")
+}
+
+// isExampleFile returns true if the filename represents a .env.example file
+// or one of its .sample, .template and .dist variants.
+func isExampleFile(fileName string, extended bool) bool {
+	suffix := exampleSuffix(fileName)
+	if suffix == "" {
+		return false
+	}
+	if strings.HasPrefix(fileName, ".env") {
+		return true
+	}
+	return extended && isExtendedEnvName(strings.TrimSuffix(fileName, suffix))
 }
@@ -1,8 +1,13 @@
 package scanner
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
 	"strings"
 	"testing"
 )
@@ -43,6 +48,7 @@ func TestScan(t *testing.T) {
 		// Should not match
 		writeFile(t, tmpDir, ".env.example", "EXAMPLE=value")
 		writeFile(t, tmpDir, ".env.local.example", "LOCAL_EXAMPLE=value")
+		writeFile(t, tmpDir, ".env.keys", "DOTENV_PRIVATE_KEY=value")
 
 		results, err := Scan(tmpDir)
 		if err != nil {
@@ -362,6 +368,310 @@ func TestScanExamples(t *testing.T) {
 	})
 }
 
+func TestScanIgnoreFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeFile(t, tmpDir, IgnoreFileName, "fixtures/\nexamples/demo\n")
+	writeFile(t, tmpDir, ".env", "KEY=value")
+	mkdir(t, tmpDir, "fixtures")
+	writeFile(t, tmpDir, "fixtures/.env", "KEY=value")
+	writeFile(t, tmpDir, "fixtures/.env.example", "KEY=value")
+	mkdir(t, tmpDir, "examples/demo")
+	mkdir(t, tmpDir, "examples/basic")
+	writeFile(t, tmpDir, "examples/demo/.env.example", "KEY=value")
+	writeFile(t, tmpDir, "examples/basic/.env.example", "KEY=value")
+	mkdir(t, tmpDir, "tmp")
+	writeFile(t, tmpDir, "tmp/.env", "KEY=value")
+
+	results, err := Scan(tmpDir)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if strings.Join(results, ",") != ".env,"+filepath.Join("tmp", ".env") {
+		t.Errorf("Scan() = %v, expected [.env tmp/.env]", results)
+	}
+
+	examples, err := ScanExamples(tmpDir)
+	if err != nil {
+		t.Fatalf("ScanExamples() error = %v", err)
+	}
+	if len(examples) != 1 || examples[0] != filepath.Join("examples", "basic", ".env.example") {
+		t.Errorf("ScanExamples() = %v, expected only examples/basic/.env.example", examples)
+	}
+
+	result, err := ScanWithOptions(t.Context(), tmpDir, Options{Exclude: []string{"tmp"}})
+	if err != nil {
+		t.Fatalf("ScanWithOptions() error = %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0] != ".env" {
+		t.Errorf("ScanWithOptions() = %v, expected [.env]", result.Files)
+	}
+
+	if _, err := ScanWithOptions(t.Context(), tmpDir, Options{Exclude: []string{"[bad"}}); err == nil {
+		t.Error("expected error for malformed exclude pattern")
+	}
+}
+
+func TestScanCanceled(t *testing.T) {
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), CacheFileName)
+	writeFile(t, tmpDir, ".env", "KEY=value")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	result, err := ScanWithOptions(ctx, tmpDir, Options{CachePath: cachePath})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ScanWithOptions() error = %v, want context.Canceled", err)
+	}
+	if result.Files != nil {
+		t.Errorf("ScanWithOptions() = %v, want no files", result.Files)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("canceled scan wrote the cache: %v", err)
+	}
+}
+
+func TestScanReportsSkippedDirectories(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced")
+	}
+	tmpDir := t.TempDir()
+	writeFile(t, tmpDir, ".env", "KEY=value")
+	mkdir(t, tmpDir, "locked")
+	writeFile(t, tmpDir, "locked/.env", "KEY=value")
+	locked := filepath.Join(tmpDir, "locked")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(locked, 0755) })
+
+	result, err := ScanWithOptions(t.Context(), tmpDir, Options{})
+	if err != nil {
+		t.Fatalf("ScanWithOptions() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Files, []string{".env"}) {
+		t.Errorf("ScanWithOptions() = %v, expected [.env]", result.Files)
+	}
+	want := []Skipped{{Path: "locked", Reason: "permission denied"}}
+	if !reflect.DeepEqual(result.Skipped, want) {
+		t.Errorf("Skipped = %v, expected %v", result.Skipped, want)
+	}
+}
+
+func TestSkippedSummary(t *testing.T) {
+	tests := []struct {
+		skipped  []Skipped
+		expected string
+	}{
+		{nil, ""},
+		{[]Skipped{{Path: "a", Reason: "permission denied"}}, "1 directory skipped (permission denied)"},
+		{
+			[]Skipped{
+				{Path: "a", Reason: "permission denied"},
+				{Path: "b", Reason: "not found"},
+				{Path: "c", Reason: "permission denied"},
+			},
+			"1 directory skipped (not found), 2 directories skipped (permission denied)",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := SkippedSummary(tt.skipped); got != tt.expected {
+			t.Errorf("SkippedSummary(%v) = %q, expected %q", tt.skipped, got, tt.expected)
+		}
+	}
+}
+
+func TestScanMaxDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeFile(t, tmpDir, ".env", "KEY=value")
+	mkdir(t, tmpDir, "a/b/c")
+	writeFile(t, tmpDir, "a/.env", "KEY=value")
+	writeFile(t, tmpDir, "a/b/.env", "KEY=value")
+	writeFile(t, tmpDir, "a/b/c/.env", "KEY=value")
+
+	tests := []struct {
+		maxDepth int
+		expected int
+	}{
+		{0, 4},
+		{1, 2},
+		{2, 3},
+	}
+
+	for _, tt := range tests {
+		result, err := ScanWithOptions(t.Context(), tmpDir, Options{MaxDepth: tt.maxDepth})
+		if err != nil {
+			t.Fatalf("ScanWithOptions() error = %v", err)
+		}
+		if len(result.Files) != tt.expected {
+			t.Errorf("MaxDepth %d: got %d files %v, expected %d", tt.maxDepth, len(result.Files), result.Files, tt.expected)
+		}
+	}
+}
+
+func TestScanSymlinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	shared := t.TempDir()
+
+	writeFile(t, shared, ".env", "KEY=value")
+	mkdir(t, tmpDir, "app")
+	writeFile(t, tmpDir, "app/.env", "KEY=value")
+	if err := os.Symlink(shared, filepath.Join(tmpDir, "shared")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	// A link back to the root would loop forever without cycle detection.
+	if err := os.Symlink(tmpDir, filepath.Join(tmpDir, "app", "loop")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	results, err := Scan(tmpDir)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Scan() without FollowSymlinks = %v, expected only app/.env", results)
+	}
+
+	result, err := ScanWithOptions(t.Context(), tmpDir, Options{FollowSymlinks: true})
+	if err != nil {
+		t.Fatalf("ScanWithOptions() error = %v", err)
+	}
+	expected := []string{filepath.Join("app", ".env"), filepath.Join("shared", ".env")}
+	if strings.Join(result.Files, ",") != strings.Join(expected, ",") {
+		t.Errorf("ScanWithOptions(FollowSymlinks) = %v, expected %v", result.Files, expected)
+	}
+}
+
+func TestScanOneFilesystem(t *testing.T) {
+	tmpDir := t.TempDir()
+	mkdir(t, tmpDir, "sub")
+	writeFile(t, tmpDir, "sub/.env", "KEY=value")
+
+	result, err := ScanWithOptions(t.Context(), tmpDir, Options{OneFilesystem: true})
+	if err != nil {
+		t.Fatalf("ScanWithOptions() error = %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Errorf("OneFilesystem should still scan directories on the same device, got %v", result.Files)
+	}
+}
+
+func TestScanNamingConventions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, name := range []string{
+		".env", "app.env", "secrets.env", "env.local", "env.list", "environment.go",
+		".env.sample", ".env.template", ".env.dist", ".env.example", "app.env.example",
+	} {
+		writeFile(t, tmpDir, name, "KEY=value")
+	}
+
+	tests := []struct {
+		name     string
+		examples bool
+		opts     Options
+		expected []string
+	}{
+		{"strict env files", false, Options{}, []string{".env"}},
+		{"extended env files", false, Options{ExtendedNames: true}, []string{".env", "app.env", "env.list", "env.local", "secrets.env"}},
+		{"strict examples", true, Options{}, []string{".env.dist", ".env.example", ".env.sample", ".env.template"}},
+		{"extended examples", true, Options{ExtendedNames: true}, []string{".env.dist", ".env.example", ".env.sample", ".env.template", "app.env.example"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scan := ScanWithOptions
+			if tt.examples {
+				scan = ScanExamplesWithOptions
+			}
+			result, err := scan(t.Context(), tmpDir, tt.opts)
+			if err != nil {
+				t.Fatalf("scan error = %v", err)
+			}
+			if strings.Join(result.Files, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("got %v, expected %v", result.Files, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExampleTarget(t *testing.T) {
+	tests := map[string]string{
+		".env.example":                               ".env",
+		".env.local.sample":                          ".env.local",
+		filepath.Join("api", ".env.template"):        filepath.Join("api", ".env"),
+		".env.dist":                                  ".env",
+		"app.env.example":                            "app.env",
+		filepath.Join("web", ".env.staging.example"): filepath.Join("web", ".env.staging"),
+	}
+
+	for input, expected := range tests {
+		if got := ExampleTarget(input); got != expected {
+			t.Errorf("ExampleTarget(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestExamplePaths(t *testing.T) {
+	env := filepath.Join("api", ".env.local")
+	paths := ExamplePaths(env)
+	if len(paths) != len(exampleSuffixes) {
+		t.Fatalf("ExamplePaths(%q) returned %d paths, expected %d", env, len(paths), len(exampleSuffixes))
+	}
+	for _, p := range paths {
+		if got := ExampleTarget(p); got != env {
+			t.Errorf("ExampleTarget(%q) = %q, expected %q", p, got, env)
+		}
+	}
+}
+
+func TestScanSource(t *testing.T) {
+	dir := t.TempDir()
+	mkdir(t, dir, "src")
+	mkdir(t, dir, "node_modules")
+	writeFile(t, dir, filepath.Join("src", "app.ts"), "process.env.PORT")
+	writeFile(t, dir, filepath.Join("src", "README.md"), "process.env.PORT")
+	writeFile(t, dir, "main.go", "os.Getenv(\"PORT\")")
+	writeFile(t, dir, filepath.Join("node_modules", "dep.js"), "process.env.X")
+	writeFile(t, dir, ".env", "PORT=3000")
+
+	files, err := ScanSource(dir)
+	if err != nil {
+		t.Fatalf("ScanSource() unexpected error: %v", err)
+	}
+	sort.Strings(files)
+	want := []string{"main.go", filepath.Join("src", "app.ts")}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("ScanSource() = %v, want %v", files, want)
+	}
+}
+
+func TestScanDirenv(t *testing.T) {
+	dir := t.TempDir()
+	mkdir(t, dir, "api")
+	mkdir(t, dir, "node_modules")
+	writeFile(t, dir, ".envrc", "dotenv")
+	writeFile(t, dir, filepath.Join("api", ".envrc"), "export PORT=3000")
+	writeFile(t, dir, filepath.Join("node_modules", ".envrc"), "export X=1")
+	writeFile(t, dir, ".env", "PORT=3000")
+
+	files, err := ScanDirenv(dir)
+	if err != nil {
+		t.Fatalf("ScanDirenv() unexpected error: %v", err)
+	}
+	sort.Strings(files)
+	want := []string{".envrc", filepath.Join("api", ".envrc")}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("ScanDirenv() = %v, want %v", files, want)
+	}
+
+	if files, _ := Scan(dir); !reflect.DeepEqual(files, []string{".env"}) {
+		t.Errorf("Scan() = %v, .envrc files are not env files", files)
+	}
+}
+
 // Helper functions for test setup
 func writeFile(t *testing.T, base, name, content string) {
 	t.Helper()
@@ -0,0 +1,303 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace is a package of a monorepo, as declared by a workspace manifest
+// at the scan root.
+type Workspace struct {
+	// Name is the package name from the package's own manifest, or its
+	// directory name if it has none.
+	Name string
+	// Dir is the package directory relative to the root, slash-separated.
+	Dir string
+}
+
+// maxWorkspaceDepth bounds how many directory levels below the root are
+// matched against workspace member patterns.
+const maxWorkspaceDepth = 4
+
+// packageManifests are the files that mark a directory as a package, in the
+// order their names are preferred.
+var packageManifests = []string{"project.json", "package.json", "Cargo.toml", "go.mod"}
+
+// FindWorkspaces returns the packages declared by the workspace manifests at
+// root: pnpm-workspace.yaml, the "workspaces" field of package.json (used by
+// npm, Yarn and Turborepo), go.work, a Cargo.toml [workspace] and, for Nx,
+// every directory with a project.json. Packages are sorted by directory. A
+// root without workspace manifests yields none.
+func FindWorkspaces(root string) ([]Workspace, error) {
+	var patterns, dirs []string
+	add := func(found []string, err error) error {
+		patterns = append(patterns, found...)
+		return err
+	}
+	if err := add(pnpmWorkspaces(root)); err != nil {
+		return nil, err
+	}
+	if err := add(npmWorkspaces(root)); err != nil {
+		return nil, err
+	}
+	if err := add(cargoWorkspaces(root)); err != nil {
+		return nil, err
+	}
+	dirs, err := goWorkspaces(root)
+	if err != nil {
+		return nil, err
+	}
+	nx := fileExists(filepath.Join(root, "nx.json"))
+
+	if len(patterns) > 0 || nx {
+		walkWorkspaceDirs(root, "", 1, func(rel string) {
+			if (nx && fileExists(filepath.Join(root, rel, "project.json"))) || matchesWorkspace(patterns, rel) {
+				dirs = append(dirs, rel)
+			}
+		})
+	}
+
+	seen := make(map[string]bool)
+	var workspaces []Workspace
+	for _, dir := range dirs {
+		dir = path.Clean(filepath.ToSlash(dir))
+		if dir == "." || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		workspaces = append(workspaces, Workspace{Name: packageName(filepath.Join(root, dir)), Dir: dir})
+	}
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].Dir < workspaces[j].Dir })
+	return workspaces, nil
+}
+
+// walkWorkspaceDirs calls fn with the slash-separated path of every
+// directory below dir that contains a package manifest, down to
+// maxWorkspaceDepth, skipping dependency and hidden directories.
+func walkWorkspaceDirs(root, rel string, depth int, fn func(rel string)) {
+	if depth > maxWorkspaceDepth {
+		return
+	}
+	entries, err := os.ReadDir(filepath.Join(root, rel))
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || skipDirs[name] || strings.HasPrefix(name, ".") {
+			continue
+		}
+		child := path.Join(rel, name)
+		for _, manifest := range packageManifests {
+			if fileExists(filepath.Join(root, child, manifest)) {
+				fn(child)
+				break
+			}
+		}
+		walkWorkspaceDirs(root, child, depth+1, fn)
+	}
+}
+
+// matchesWorkspace reports whether rel matches the member patterns, where a
+// later "!" pattern excludes directories an earlier one included.
+func matchesWorkspace(patterns []string, rel string) bool {
+	parts := strings.Split(rel, "/")
+	matched := false
+	for _, p := range patterns {
+		negate := strings.HasPrefix(p, "!")
+		p = path.Clean(strings.TrimPrefix(strings.TrimPrefix(p, "!"), "./"))
+		if matchSegments(strings.Split(p, "/"), parts) {
+			matched = !negate
+		}
+	}
+	return matched
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// pnpmWorkspaces returns the package patterns of pnpm-workspace.yaml.
+func pnpmWorkspaces(root string) ([]string, error) {
+	data, err := readManifest(filepath.Join(root, "pnpm-workspace.yaml"))
+	if data == nil || err != nil {
+		return nil, err
+	}
+	var manifest struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse pnpm-workspace.yaml: %w", err)
+	}
+	return manifest.Packages, nil
+}
+
+// npmWorkspaces returns the "workspaces" patterns of package.json, written
+// either as a list or, by Yarn, as {"packages": [...]}.
+func npmWorkspaces(root string) ([]string, error) {
+	data, err := readManifest(filepath.Join(root, "package.json"))
+	if data == nil || err != nil {
+		return nil, err
+	}
+	var manifest struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	if len(manifest.Workspaces) == 0 {
+		return nil, nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(manifest.Workspaces, &patterns); err == nil {
+		return patterns, nil
+	}
+	var yarn struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(manifest.Workspaces, &yarn); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json workspaces: %w", err)
+	}
+	return yarn.Packages, nil
+}
+
+var (
+	goUse        = regexp.MustCompile(`^use\s+(\S+)`)
+	tomlSection  = regexp.MustCompile(`^\[([^\]]+)\]`)
+	tomlStrings  = regexp.MustCompile(`"([^"]*)"`)
+	tomlNameLine = regexp.MustCompile(`^name\s*=\s*"([^"]*)"`)
+)
+
+// goWorkspaces returns the module directories listed by go.work "use"
+// directives, in both the single-line and block forms.
+func goWorkspaces(root string) ([]string, error) {
+	data, err := readManifest(filepath.Join(root, "go.work"))
+	if data == nil || err != nil {
+		return nil, err
+	}
+	var dirs []string
+	inBlock := false
+	sc := bufio.NewScanner(strings.NewReader(string(data)))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(strings.TrimSpace(sc.Text()), "//")
+		line = strings.TrimSpace(line)
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			dirs = append(dirs, strings.Trim(line, `"`))
+		case line == "use (":
+			inBlock = true
+		default:
+			if m := goUse.FindStringSubmatch(line); m != nil {
+				dirs = append(dirs, strings.Trim(m[1], `"`))
+			}
+		}
+	}
+	return dirs, nil
+}
+
+// cargoWorkspaces returns the members of the [workspace] table of
+// Cargo.toml, whose array may span several lines.
+func cargoWorkspaces(root string) ([]string, error) {
+	data, err := readManifest(filepath.Join(root, "Cargo.toml"))
+	if data == nil || err != nil {
+		return nil, err
+	}
+	var members []string
+	section, inMembers := "", false
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(strings.TrimSpace(line), "#")
+		if m := tomlSection.FindStringSubmatch(line); m != nil {
+			section, inMembers = strings.TrimSpace(m[1]), false
+			continue
+		}
+		if section != "workspace" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "members"); ok && strings.HasPrefix(strings.TrimSpace(rest), "=") {
+			inMembers = true
+		}
+		if inMembers {
+			for _, m := range tomlStrings.FindAllStringSubmatch(line, -1) {
+				members = append(members, m[1])
+			}
+			if strings.Contains(line, "]") {
+				inMembers = false
+			}
+		}
+	}
+	return members, nil
+}
+
+// packageName returns the name declared by the first package manifest in
+// dir, or the directory's base name.
+func packageName(dir string) string {
+	for _, manifest := range packageManifests {
+		data, err := readManifest(filepath.Join(dir, manifest))
+		if data == nil || err != nil {
+			continue
+		}
+		var name string
+		switch manifest {
+		case "project.json", "package.json":
+			var m struct {
+				Name string `json:"name"`
+			}
+			if json.Unmarshal(data, &m) == nil {
+				name = m.Name
+			}
+		case "Cargo.toml":
+			name = cargoPackageName(string(data))
+		case "go.mod":
+			for _, line := range strings.Split(string(data), "\n") {
+				if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+					name = strings.Trim(strings.TrimSpace(rest), `"`)
+					break
+				}
+			}
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return filepath.Base(dir)
+}
+
+// cargoPackageName returns the name in the [package] table of a Cargo.toml.
+func cargoPackageName(data string) string {
+	section := ""
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if m := tomlSection.FindStringSubmatch(line); m != nil {
+			section = strings.TrimSpace(m[1])
+			continue
+		}
+		if m := tomlNameLine.FindStringSubmatch(line); m != nil && section == "package" {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// readManifest returns the content of path, or nil if it does not exist.
+func readManifest(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	return data, nil
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindWorkspaces(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []Workspace
+	}{
+		{
+			name: "pnpm",
+			files: map[string]string{
+				"pnpm-workspace.yaml":             "packages:\n  - apps/*\n  - packages/**\n  - '!packages/internal'\n",
+				"apps/api/package.json":           `{"name": "@acme/api"}`,
+				"apps/docs/README.md":             "not a package",
+				"packages/ui/button/package.json": `{"name": "@acme/button"}`,
+				"packages/internal/package.json":  `{"name": "@acme/internal"}`,
+			},
+			want: []Workspace{
+				{Name: "@acme/api", Dir: "apps/api"},
+				{Name: "@acme/button", Dir: "packages/ui/button"},
+			},
+		},
+		{
+			name: "npm and yarn workspaces",
+			files: map[string]string{
+				"package.json":               `{"workspaces": {"packages": ["services/*"]}}`,
+				"services/auth/package.json": `{}`,
+			},
+			want: []Workspace{{Name: "auth", Dir: "services/auth"}},
+		},
+		{
+			name: "go.work",
+			files: map[string]string{
+				"go.work":       "go 1.25\n\nuse (\n\t./api // HTTP server\n\t./worker\n)\nuse ./tools\n",
+				"api/go.mod":    "module example.com/api\n",
+				"worker/go.mod": "module example.com/worker\n",
+				"tools/main.go": "package main\n",
+			},
+			want: []Workspace{
+				{Name: "example.com/api", Dir: "api"},
+				{Name: "tools", Dir: "tools"},
+				{Name: "example.com/worker", Dir: "worker"},
+			},
+		},
+		{
+			name: "cargo",
+			files: map[string]string{
+				"Cargo.toml":             "[workspace]\nmembers = [\n  \"crates/*\", # all crates\n]\n\n[workspace.package]\nname = \"ignored\"\n",
+				"crates/core/Cargo.toml": "[package]\nname = \"acme-core\"\nversion = \"0.1.0\"\n",
+			},
+			want: []Workspace{{Name: "acme-core", Dir: "crates/core"}},
+		},
+		{
+			name: "nx",
+			files: map[string]string{
+				"nx.json":                "{}",
+				"apps/shop/project.json": `{"name": "shop"}`,
+				"libs/util/package.json": `{"name": "util"}`,
+			},
+			want: []Workspace{{Name: "shop", Dir: "apps/shop"}},
+		},
+		{
+			name:  "no manifests",
+			files: map[string]string{"api/package.json": `{"name": "api"}`},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				mkdir(t, dir, filepath.Dir(filepath.FromSlash(name)))
+				writeFile(t, dir, filepath.FromSlash(name), content)
+			}
+
+			got, err := FindWorkspaces(dir)
+			if err != nil {
+				t.Fatalf("FindWorkspaces() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindWorkspaces() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindWorkspacesInvalidManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "package.json", "{")
+	if _, err := FindWorkspaces(dir); err == nil {
+		t.Error("FindWorkspaces() expected error for invalid package.json")
+	}
+}
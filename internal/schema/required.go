@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// Requirement annotations in an example: a comment starting with the word
+// "required" or "optional", in the comment block above a key, e.g.
+// "# optional: only needed for error reporting".
+const (
+	requiredWord = "required"
+	optionalWord = "optional"
+)
+
+// Required returns the keys an env file generated from the example entries
+// must give a value: the keys the example gives no default, being empty
+// or a placeholder such as sk_***, unless annotated "# optional", and the
+// keys annotated "# required". An annotation applies to the first key
+// after it, across other comments but not across blank lines.
+func Required(entries []parser.Entry) map[string]bool {
+	required := make(map[string]bool)
+	var pending string
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case parser.Comment:
+			if word := annotation(e.Text); word != "" {
+				pending = word
+			}
+		case parser.KeyValue:
+			switch pending {
+			case requiredWord:
+				required[e.Key] = true
+			case optionalWord:
+				delete(required, e.Key)
+			default:
+				if HasNoDefault(e.Value) {
+					required[e.Key] = true
+				}
+			}
+			pending = ""
+		default:
+			pending = ""
+		}
+	}
+	return required
+}
+
+// HasNoDefault reports whether an example value leaves the key to be
+// filled in: it is empty or a placeholder.
+func HasNoDefault(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "" || detector.IsPlaceholder(value)
+}
+
+// annotation returns requiredWord or optionalWord if the comment starts
+// with that word, or "".
+func annotation(comment string) string {
+	text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(comment), "#"))
+	word, _, _ := strings.Cut(text, " ")
+	switch word = strings.ToLower(strings.TrimRight(word, ":,.")); word {
+	case requiredWord, optionalWord:
+		return word
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func TestRequired(t *testing.T) {
+	example := `PORT=3000
+API_KEY=
+STRIPE_KEY=sk_***
+# optional: only needed for error reporting
+SENTRY_DSN=
+# Region to deploy to
+# Required
+REGION=eu-west-1
+# optional
+
+ANALYTICS_ID=
+LOG_LEVEL=info # required later
+`
+	entries, err := parser.Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"API_KEY": true, "STRIPE_KEY": true, "REGION": true, "ANALYTICS_ID": true}
+	if got := Required(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("Required() = %v, want %v", got, want)
+	}
+}
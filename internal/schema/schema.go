@@ -0,0 +1,158 @@
+// Package schema describes the keys a project's .env files are expected to
+// define. A schema is itself a .env-format file in which each value names
+// the key's type, optionally followed by ",required":
+//
+//	DATABASE_URL=url,required
+//	PORT=port
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// FileName is the conventional name of a project's schema file.
+const FileName = ".env.schema"
+
+// Type is the kind of value a key holds.
+type Type string
+
+// Value types.
+const (
+	String Type = "string"
+	Number Type = "number"
+	Bool   Type = "bool"
+	Port   Type = "port"
+	URL    Type = "url"
+)
+
+// Field describes a single key.
+type Field struct {
+	Key      string
+	Type     Type
+	Required bool
+}
+
+// Validate reports whether value is acceptable for the field: a required
+// field must not be empty, and a non-empty value must match the type.
+func (f Field) Validate(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		if f.Required {
+			return errors.New("required")
+		}
+		return nil
+	}
+	switch f.Type {
+	case Number:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.New("not a number")
+		}
+	case Bool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.New("not true or false")
+		}
+	case Port:
+		if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+			return errors.New("not a port (1-65535)")
+		}
+	case URL:
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "" && u.Path == "") {
+			return errors.New("not a URL")
+		}
+	}
+	return nil
+}
+
+// Infer derives a schema from the keys in entries, guessing each type from
+// its current value. Keys with a value are marked required.
+func Infer(entries []parser.Entry) []Field {
+	var fields []Field
+	for _, entry := range entries {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok {
+			continue
+		}
+		fields = append(fields, Field{Key: kv.Key, Type: inferType(kv.Key, kv.Value), Required: kv.Value != ""})
+	}
+	return fields
+}
+
+func inferType(key, value string) Type {
+	switch lower := strings.ToLower(value); {
+	case value == "":
+		return String
+	case lower == "true" || lower == "false":
+		return Bool
+	case strings.Contains(value, "://"):
+		return URL
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return Number
+		}
+		return String
+	}
+	if strings.Contains(strings.ToUpper(key), "PORT") && n > 0 && n <= 65535 {
+		return Port
+	}
+	return Number
+}
+
+// Parse reads a schema file.
+func Parse(r io.Reader) ([]Field, error) {
+	entries, err := parser.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []Field
+	for _, entry := range entries {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok {
+			continue
+		}
+		typ, rest, _ := strings.Cut(kv.Value, ",")
+		field := Field{Key: kv.Key, Type: Type(strings.TrimSpace(typ))}
+		switch field.Type {
+		case "":
+			field.Type = String
+		case String, Number, Bool, Port, URL:
+		default:
+			return nil, fmt.Errorf("unknown type %q for %s", typ, kv.Key)
+		}
+		switch strings.TrimSpace(rest) {
+		case "":
+		case "required":
+			field.Required = true
+		default:
+			return nil, fmt.Errorf("unknown option %q for %s", rest, kv.Key)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Write writes fields as a schema file with a short explanatory header.
+func Write(w io.Writer, fields []Field) error {
+	entries := []parser.Entry{
+		parser.Comment{Text: "# Expected keys: KEY=type[,required]"},
+		parser.Comment{Text: "# Types: string, number, bool, port, url"},
+		parser.BlankLine{},
+	}
+	for _, f := range fields {
+		value := string(f.Type)
+		if f.Required {
+			value += ",required"
+		}
+		entries = append(entries, parser.KeyValue{Key: f.Key, Value: value})
+	}
+	return parser.Write(w, entries)
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func TestInfer(t *testing.T) {
+	entries, err := parser.Parse(strings.NewReader(`# app
+DATABASE_URL=postgres://localhost/app
+PORT=8080
+WORKERS=4
+RATIO=0.5
+DEBUG=false
+NAME=demo
+OPTIONAL=
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Field{
+		{"DATABASE_URL", URL, true},
+		{"PORT", Port, true},
+		{"WORKERS", Number, true},
+		{"RATIO", Number, true},
+		{"DEBUG", Bool, true},
+		{"NAME", String, true},
+		{"OPTIONAL", String, false},
+	}
+	if got := Infer(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("Infer() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteParseRoundTrip(t *testing.T) {
+	fields := []Field{{"DATABASE_URL", URL, true}, {"PORT", Port, false}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, fields); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "DATABASE_URL=url,required\nPORT=port\n") {
+		t.Errorf("Write() = %q", buf.String())
+	}
+
+	got, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, fields) {
+		t.Errorf("Parse(Write()) = %+v, want %+v", got, fields)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{"KEY=uuid\n", "KEY=string,optional\n"} {
+		if _, err := Parse(strings.NewReader(input)); err == nil {
+			t.Errorf("Parse(%q) expected error", input)
+		}
+	}
+	fields, err := Parse(strings.NewReader("KEY=\n"))
+	if err != nil || len(fields) != 1 || fields[0].Type != String {
+		t.Errorf("Parse(empty type) = %+v, %v; want string", fields, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		field Field
+		value string
+		ok    bool
+	}{
+		{Field{"NAME", String, false}, "", true},
+		{Field{"NAME", String, true}, "  ", false},
+		{Field{"WORKERS", Number, false}, "0.5", true},
+		{Field{"WORKERS", Number, false}, "four", false},
+		{Field{"DEBUG", Bool, false}, "TRUE", true},
+		{Field{"DEBUG", Bool, false}, "yes", false},
+		{Field{"PORT", Port, false}, "8080", true},
+		{Field{"PORT", Port, false}, "70000", false},
+		{Field{"PORT", Port, false}, "http", false},
+		{Field{"DATABASE_URL", URL, true}, "postgres://localhost/app", true},
+		{Field{"DATABASE_URL", URL, true}, "sqlite:app.db", true},
+		{Field{"DATABASE_URL", URL, true}, "not a url", false},
+	}
+	for _, tt := range tests {
+		if err := tt.field.Validate(tt.value); (err == nil) != tt.ok {
+			t.Errorf("%s (%s).Validate(%q) = %v, want ok=%v", tt.field.Key, tt.field.Type, tt.value, err, tt.ok)
+		}
+	}
+}
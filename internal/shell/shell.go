@@ -0,0 +1,88 @@
+// Package shell writes env entries as scripts that set environment
+// variables in a shell, for use with eval or source.
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// Shell is a shell dialect a script is written for.
+type Shell string
+
+// Shells.
+const (
+	// Bash covers POSIX shells, including sh and zsh.
+	Bash       Shell = "bash"
+	Fish       Shell = "fish"
+	PowerShell Shell = "pwsh"
+)
+
+// Parse validates a shell name, accepting sh and zsh for Bash and
+// powershell for PowerShell.
+func Parse(name string) (Shell, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "bash", "sh", "zsh":
+		return Bash, nil
+	case "fish":
+		return Fish, nil
+	case "pwsh", "powershell":
+		return PowerShell, nil
+	default:
+		return "", fmt.Errorf("unknown shell %q (use bash, fish or pwsh)", name)
+	}
+}
+
+// variableName matches the keys every supported shell accepts as a
+// variable name.
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Write writes a script that sets each key of entries as an environment
+// variable in sh. Values are quoted so the shell reads them literally,
+// without expanding variables or commands. Keys that are not valid
+// variable names are skipped and returned.
+func Write(w io.Writer, entries []parser.Entry, sh Shell) ([]string, error) {
+	bw := bufio.NewWriter(w)
+	var skipped []string
+	for _, entry := range entries {
+		kv, ok := entry.(parser.KeyValue)
+		if !ok {
+			continue
+		}
+		if !variableName.MatchString(kv.Key) {
+			skipped = append(skipped, kv.Key)
+			continue
+		}
+		switch sh {
+		case Fish:
+			_, _ = fmt.Fprintf(bw, "set -gx %s %s;\n", kv.Key, Quote(sh, kv.Value))
+		case PowerShell:
+			_, _ = fmt.Fprintf(bw, "$env:%s = %s\n", kv.Key, Quote(sh, kv.Value))
+		default:
+			_, _ = fmt.Fprintf(bw, "export %s=%s\n", kv.Key, Quote(sh, kv.Value))
+		}
+	}
+	return skipped, bw.Flush()
+}
+
+// Quote single-quotes value for sh, the only quoting none of the shells
+// expand inside. Bash cannot escape a single quote within single quotes,
+// so each one closes the quotes, is escaped, and reopens them; fish
+// escapes single quotes and backslashes, and PowerShell doubles single
+// quotes.
+func Quote(sh Shell, value string) string {
+	switch sh {
+	case Fish:
+		value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	case PowerShell:
+		value = strings.ReplaceAll(value, "'", "''")
+	default:
+		value = strings.ReplaceAll(value, "'", `'\''`)
+	}
+	return "'" + value + "'"
+}
@@ -0,0 +1,74 @@
+package shell
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func TestWrite(t *testing.T) {
+	entries := []parser.Entry{
+		parser.Comment{Text: "# app"},
+		parser.KeyValue{Key: "NAME", Value: "it's $HOME"},
+		parser.BlankLine{},
+		parser.KeyValue{Key: "PATH_LIKE", Value: `C:\tools`},
+		parser.KeyValue{Key: "app.port", Value: "3000"},
+	}
+
+	tests := []struct {
+		shell Shell
+		want  string
+	}{
+		{Bash, "export NAME='it'\\''s $HOME'\nexport PATH_LIKE='C:\\tools'\n"},
+		{Fish, "set -gx NAME 'it\\'s $HOME';\nset -gx PATH_LIKE 'C:\\\\tools';\n"},
+		{PowerShell, "$env:NAME = 'it''s $HOME'\n$env:PATH_LIKE = 'C:\\tools'\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.shell), func(t *testing.T) {
+			var b strings.Builder
+			skipped, err := Write(&b, entries, tt.shell)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Write() =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+			if !reflect.DeepEqual(skipped, []string{"app.port"}) {
+				t.Errorf("skipped = %v, want [app.port]", skipped)
+			}
+		})
+	}
+}
+
+func TestWriteEvaluatesInSh(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	value := "a 'quoted' $(echo no) `echo no` \\ \"x\"\nline two"
+	var b strings.Builder
+	if _, err := Write(&b, []parser.Entry{parser.KeyValue{Key: "VALUE", Value: value}}, Bash); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out, err := exec.Command(sh, "-c", b.String()+`printf %s "$VALUE"`).Output()
+	if err != nil {
+		t.Fatalf("sh error = %v", err)
+	}
+	if string(out) != value {
+		t.Errorf("sh read %q, want %q", out, value)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for name, want := range map[string]Shell{"zsh": Bash, "FISH": Fish, "powershell": PowerShell} {
+		if got, err := Parse(name); err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := Parse("tcsh"); err == nil {
+		t.Error("Parse(tcsh) expected error")
+	}
+}
@@ -0,0 +1,114 @@
+// Package state persists TUI session progress between runs.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Session records the files a user was working through and which of them
+// have been saved, so the TUI can offer to resume after a restart.
+type Session struct {
+	Root      string          `json:"root"`
+	Mode      string          `json:"mode"`
+	Files     []string        `json:"files"`
+	Saved     map[string]bool `json:"saved"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Session modes.
+const (
+	ModeExample = "example"
+	ModeEnv     = "env"
+)
+
+// Dir returns the dotenv-tui state directory, honoring $XDG_STATE_HOME and
+// falling back to ~/.local/state.
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "dotenv-tui"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "dotenv-tui"), nil
+}
+
+// DefaultPath returns the path of the session file, or an empty string if the
+// state directory cannot be determined.
+func DefaultPath() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "state.json")
+}
+
+// Load reads a session from path. A missing file yields an empty session.
+func Load(path string) (Session, error) {
+	var s Session
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Session{}, fmt.Errorf("failed to parse state: %w", err)
+	}
+	return s, nil
+}
+
+// Save writes the session to path, creating the parent directory if needed.
+func Save(path string, s Session) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
+
+// Resumable reports whether the session has files left to process.
+func (s Session) Resumable() bool {
+	return len(s.Pending()) > 0
+}
+
+// Pending returns the session files that have not been saved yet.
+func (s Session) Pending() []string {
+	var pending []string
+	for _, f := range s.Files {
+		if !s.Saved[f] {
+			pending = append(pending, f)
+		}
+	}
+	return pending
+}
+
+// MarkUnsaved clears the saved status of file, e.g. after a rollback.
+func (s *Session) MarkUnsaved(file string) {
+	delete(s.Saved, file)
+}
+
+// MarkSaved records that file has been written successfully.
+func (s *Session) MarkSaved(file string) {
+	if s.Saved == nil {
+		s.Saved = make(map[string]bool)
+	}
+	s.Saved[file] = true
+}
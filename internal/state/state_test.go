@@ -0,0 +1,101 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	t.Run("honors XDG_STATE_HOME", func(t *testing.T) {
+		t.Setenv("XDG_STATE_HOME", "/tmp/xdg-state")
+		dir, err := Dir()
+		if err != nil {
+			t.Fatalf("Dir() unexpected error: %v", err)
+		}
+		if dir != filepath.Join("/tmp/xdg-state", "dotenv-tui") {
+			t.Errorf("Dir() = %q", dir)
+		}
+	})
+
+	t.Run("falls back to ~/.local/state", func(t *testing.T) {
+		t.Setenv("XDG_STATE_HOME", "")
+		t.Setenv("HOME", "/home/tester")
+		dir, err := Dir()
+		if err != nil {
+			t.Fatalf("Dir() unexpected error: %v", err)
+		}
+		if dir != filepath.Join("/home/tester", ".local", "state", "dotenv-tui") {
+			t.Errorf("Dir() = %q", dir)
+		}
+	})
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+
+	session := Session{Root: "/repo", Mode: ModeEnv, Files: []string{"/repo/a/.env.example", "/repo/b/.env.example"}}
+	session.MarkSaved("/repo/a/.env.example")
+
+	if err := Save(path, session); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("state file not written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("state file mode = %v, expected 0600", info.Mode().Perm())
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if loaded.Root != "/repo" || loaded.Mode != ModeEnv || len(loaded.Files) != 2 {
+		t.Errorf("Load() = %+v", loaded)
+	}
+	if !loaded.Saved["/repo/a/.env.example"] {
+		t.Errorf("Load() lost saved status")
+	}
+	if loaded.UpdatedAt.IsZero() {
+		t.Errorf("Save() should stamp UpdatedAt")
+	}
+}
+
+func TestLoadMissingAndCorrupt(t *testing.T) {
+	dir := t.TempDir()
+
+	session, err := Load(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("Load() missing file should not error: %v", err)
+	}
+	if session.Resumable() {
+		t.Errorf("empty session should not be resumable")
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(corrupt); err == nil {
+		t.Errorf("Load() expected error for corrupt state")
+	}
+}
+
+func TestPending(t *testing.T) {
+	session := Session{Files: []string{"a", "b", "c"}}
+	session.MarkSaved("b")
+
+	pending := session.Pending()
+	if len(pending) != 2 || pending[0] != "a" || pending[1] != "c" {
+		t.Errorf("Pending() = %v, expected [a c]", pending)
+	}
+
+	session.MarkSaved("a")
+	session.MarkSaved("c")
+	if session.Resumable() {
+		t.Errorf("fully saved session should not be resumable")
+	}
+}
@@ -0,0 +1,165 @@
+package templates
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// stackDeps maps each template to the dependencies that mark a project using
+// it, most specific first: a framework wins over the services it is used
+// with, so a Next.js app using Stripe gets the Next.js template.
+var stackDeps = []struct {
+	template string
+	deps     []string
+}{
+	{"nextjs", []string{"next"}},
+	{"rails", []string{"rails"}},
+	{"django", []string{"django"}},
+	{"laravel", []string{"laravel/framework"}},
+	{"supabase", []string{"@supabase/supabase-js", "@supabase/ssr", "supabase", "github.com/supabase-community/supabase-go"}},
+	{"stripe", []string{"stripe", "@stripe/stripe-js", "stripe/stripe-php", "github.com/stripe/stripe-go"}},
+}
+
+// manifests read the dependency names of a manifest file.
+var manifests = []struct {
+	file string
+	deps func(data []byte) []string
+}{
+	{"package.json", jsonDeps("dependencies", "devDependencies")},
+	{"composer.json", jsonDeps("require", "require-dev")},
+	{"Gemfile", gemfileDeps},
+	{"requirements.txt", requirementsDeps},
+	{"go.mod", goModDeps},
+}
+
+// Detect returns the template matching the project in dir, judged by the
+// dependencies in its package.json, composer.json, Gemfile,
+// requirements.txt or go.mod. It reports false if none matches.
+func Detect(dir string) (Template, bool, error) {
+	deps := make(map[string]bool)
+	for _, m := range manifests {
+		data, err := os.ReadFile(filepath.Join(dir, m.file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return Template{}, false, fmt.Errorf("failed to read %s: %w", m.file, err)
+		}
+		for _, dep := range m.deps(data) {
+			deps[strings.ToLower(dep)] = true
+		}
+	}
+
+	for _, stack := range stackDeps {
+		for _, dep := range stack.deps {
+			if deps[dep] {
+				content, err := Get(stack.template)
+				if err != nil {
+					return Template{}, false, err
+				}
+				return newTemplate(stack.template, content), true, nil
+			}
+		}
+	}
+	return Template{}, false, nil
+}
+
+// jsonDeps reads the keys of the given objects of a JSON manifest. A file
+// that is not valid JSON has no dependencies.
+func jsonDeps(fields ...string) func([]byte) []string {
+	return func(data []byte) []string {
+		var manifest map[string]json.RawMessage
+		if json.Unmarshal(data, &manifest) != nil {
+			return nil
+		}
+		var deps []string
+		for _, field := range fields {
+			var names map[string]json.RawMessage
+			if json.Unmarshal(manifest[field], &names) != nil {
+				continue
+			}
+			for name := range names {
+				deps = append(deps, name)
+			}
+		}
+		return deps
+	}
+}
+
+// gemLine matches a gem declaration, capturing the gem's name.
+var gemLine = regexp.MustCompile(`^\s*gem\s+["']([^"']+)["']`)
+
+func gemfileDeps(data []byte) []string {
+	var deps []string
+	for _, line := range lines(data) {
+		if m := gemLine.FindStringSubmatch(line); m != nil {
+			deps = append(deps, m[1])
+		}
+	}
+	return deps
+}
+
+// requirementsDeps reads the package names of a pip requirements file,
+// dropping versions, extras and environment markers.
+func requirementsDeps(data []byte) []string {
+	var deps []string
+	for _, line := range lines(data) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if i := strings.IndexAny(line, "=<>~!;[ @"); i >= 0 {
+			line = line[:i]
+		}
+		deps = append(deps, line)
+	}
+	return deps
+}
+
+// goModDeps reads the required module paths of a go.mod, without their
+// major version suffix.
+func goModDeps(data []byte) []string {
+	var deps []string
+	inBlock := false
+	for _, line := range lines(data) {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			inBlock = true
+			continue
+		case fields[0] == "require" && len(fields) > 1:
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+		deps = append(deps, trimMajorVersion(fields[0]))
+	}
+	return deps
+}
+
+// majorVersion matches the /vN suffix of a module path.
+var majorVersion = regexp.MustCompile(`/v[0-9]+$`)
+
+func trimMajorVersion(module string) string {
+	return majorVersion.ReplaceAllString(module, "")
+}
+
+func lines(data []byte) []string {
+	var result []string
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		result = append(result, s.Text())
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"next.js", map[string]string{"package.json": `{"dependencies": {"next": "15.0.0", "react": "19.0.0"}}`}, "nextjs"},
+		{"framework beats service", map[string]string{"package.json": `{"dependencies": {"stripe": "17.0.0"}, "devDependencies": {"next": "15.0.0"}}`}, "nextjs"},
+		{"supabase", map[string]string{"package.json": `{"dependencies": {"@supabase/supabase-js": "2.0.0"}}`}, "supabase"},
+		{"rails", map[string]string{"Gemfile": "source \"https://rubygems.org\"\ngem \"rails\", \"~> 7.1\"\ngem 'pg'\n"}, "rails"},
+		{"django", map[string]string{"requirements.txt": "# web\nDjango>=5.0\npsycopg[binary]==3.1\n"}, "django"},
+		{"laravel", map[string]string{"composer.json": `{"require": {"php": "^8.2", "laravel/framework": "^11.0"}}`}, "laravel"},
+		{"go stripe", map[string]string{"go.mod": "module example.com/shop\n\ngo 1.25\n\nrequire (\n\tgithub.com/stripe/stripe-go/v81 v81.0.0\n)\n"}, "stripe"},
+		{"go single require", map[string]string{"go.mod": "module example.com/app\n\nrequire github.com/supabase-community/supabase-go v0.0.4\n"}, "supabase"},
+		{"unknown stack", map[string]string{"package.json": `{"dependencies": {"express": "4.0.0"}}`}, ""},
+		{"invalid manifest", map[string]string{"package.json": `{`}, ""},
+		{"no manifests", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, ok, err := Detect(dir)
+			if err != nil {
+				t.Fatalf("Detect() unexpected error: %v", err)
+			}
+			if got.Name != tt.want || ok != (tt.want != "") {
+				t.Errorf("Detect() = %q, %v, want %q", got.Name, ok, tt.want)
+			}
+			if ok && got.Title == "" {
+				t.Error("Detect() template has no title")
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Package templates holds curated .env.example files for common stacks,
+// embedded in the binary so new projects can start from one.
+package templates
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// suffix ends the name of every embedded template file.
+const suffix = ".env.example"
+
+//go:embed stacks/*.env.example
+var stacks embed.FS
+
+// Template is an embedded .env.example.
+type Template struct {
+	// Name selects the template, e.g. "nextjs".
+	Name string `json:"name"`
+	// Title names the stack for people, e.g. "Next.js".
+	Title string `json:"title"`
+	// Description is the first comment line of the template.
+	Description string `json:"description"`
+}
+
+// List returns the embedded templates, sorted by name.
+func List() ([]Template, error) {
+	entries, err := fs.ReadDir(stacks, "stacks")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	var result []Template
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), suffix)
+		content, err := Get(name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, newTemplate(name, content))
+	}
+	return result, nil
+}
+
+// Get returns the content of the template called name, matched
+// case-insensitively.
+func Get(name string) ([]byte, error) {
+	content, err := stacks.ReadFile("stacks/" + strings.ToLower(name) + suffix)
+	if err != nil {
+		return nil, fmt.Errorf("unknown template %q (run \"dotenv-tui template list\" to see them)", name)
+	}
+	return content, nil
+}
+
+// titles name the stacks of the templates.
+var titles = map[string]string{
+	"django":   "Django",
+	"laravel":  "Laravel",
+	"nextjs":   "Next.js",
+	"rails":    "Rails",
+	"stripe":   "Stripe",
+	"supabase": "Supabase",
+}
+
+func newTemplate(name string, content []byte) Template {
+	title, ok := titles[name]
+	if !ok {
+		title = name
+	}
+	return Template{Name: name, Title: title, Description: description(content)}
+}
+
+// description returns the text of the first comment line of content.
+func description(content []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(content))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if text, ok := strings.CutPrefix(line, "#"); ok {
+			return strings.TrimSpace(text)
+		}
+		if line != "" {
+			break
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+func TestList(t *testing.T) {
+	list, err := List()
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	var names []string
+	for _, tmpl := range list {
+		names = append(names, tmpl.Name)
+		if tmpl.Description == "" {
+			t.Errorf("template %s has no description", tmpl.Name)
+		}
+	}
+	want := []string{"django", "laravel", "nextjs", "rails", "stripe", "supabase"}
+	if !slices.Equal(names, want) {
+		t.Errorf("List() names = %v, want %v", names, want)
+	}
+}
+
+func TestTemplatesParse(t *testing.T) {
+	list, err := List()
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	for _, tmpl := range list {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			content, err := Get(tmpl.Name)
+			if err != nil {
+				t.Fatalf("Get() unexpected error: %v", err)
+			}
+			entries, err := parser.Parse(bytes.NewReader(content))
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			seen := make(map[string]bool)
+			for _, e := range entries {
+				kv, ok := e.(parser.KeyValue)
+				if !ok {
+					continue
+				}
+				if seen[kv.Key] {
+					t.Errorf("duplicate key %s", kv.Key)
+				}
+				seen[kv.Key] = true
+			}
+			if len(seen) == 0 {
+				t.Error("template has no keys")
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	if _, err := Get("NextJS"); err != nil {
+		t.Errorf("Get(NextJS) unexpected error: %v", err)
+	}
+	for _, name := range []string{"unknown", "../templates", ""} {
+		if _, err := Get(name); err == nil {
+			t.Errorf("Get(%q) expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package theme defines the color palettes used by the TUI.
+package theme
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a named set of colors used across all screens.
+type Theme struct {
+	Name      string
+	Primary   lipgloss.TerminalColor // accents, cursor, title backgrounds
+	OnPrimary lipgloss.TerminalColor // text drawn on a Primary background
+	Text      lipgloss.TerminalColor // emphasized body text
+	Muted     lipgloss.TerminalColor // secondary text such as taglines
+	Success   lipgloss.TerminalColor
+	Warning   lipgloss.TerminalColor
+	Error     lipgloss.TerminalColor
+	LogoFrame lipgloss.TerminalColor
+	LogoLabel lipgloss.TerminalColor
+	LogoLock  lipgloss.TerminalColor
+}
+
+// Built-in theme names.
+const (
+	Auto         = "auto"
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+	None         = "none"
+)
+
+var builtins = map[string]Theme{
+	Dark: {
+		Name:      Dark,
+		Primary:   lipgloss.Color("#7D56F4"),
+		OnPrimary: lipgloss.Color("#FAFAFA"),
+		Text:      lipgloss.Color("#FAFAFA"),
+		Muted:     lipgloss.Color("#93A3B8"),
+		Success:   lipgloss.Color("#00FF00"),
+		Warning:   lipgloss.Color("#FFFF00"),
+		Error:     lipgloss.Color("#FF5F56"),
+		LogoFrame: lipgloss.Color("#0F766E"),
+		LogoLabel: lipgloss.Color("#34D399"),
+		LogoLock:  lipgloss.Color("#93C5FD"),
+	},
+	Light: {
+		Name:      Light,
+		Primary:   lipgloss.Color("#5B34D6"),
+		OnPrimary: lipgloss.Color("#FFFFFF"),
+		Text:      lipgloss.Color("#1F2937"),
+		Muted:     lipgloss.Color("#4B5563"),
+		Success:   lipgloss.Color("#047857"),
+		Warning:   lipgloss.Color("#B45309"),
+		Error:     lipgloss.Color("#B91C1C"),
+		LogoFrame: lipgloss.Color("#0F766E"),
+		LogoLabel: lipgloss.Color("#047857"),
+		LogoLock:  lipgloss.Color("#1D4ED8"),
+	},
+	HighContrast: {
+		Name:      HighContrast,
+		Primary:   lipgloss.ANSIColor(12),
+		OnPrimary: lipgloss.ANSIColor(15),
+		Text:      lipgloss.ANSIColor(15),
+		Muted:     lipgloss.ANSIColor(7),
+		Success:   lipgloss.ANSIColor(10),
+		Warning:   lipgloss.ANSIColor(11),
+		Error:     lipgloss.ANSIColor(9),
+		LogoFrame: lipgloss.ANSIColor(14),
+		LogoLabel: lipgloss.ANSIColor(10),
+		LogoLock:  lipgloss.ANSIColor(12),
+	},
+	None: {
+		Name:      None,
+		Primary:   lipgloss.NoColor{},
+		OnPrimary: lipgloss.NoColor{},
+		Text:      lipgloss.NoColor{},
+		Muted:     lipgloss.NoColor{},
+		Success:   lipgloss.NoColor{},
+		Warning:   lipgloss.NoColor{},
+		Error:     lipgloss.NoColor{},
+		LogoFrame: lipgloss.NoColor{},
+		LogoLabel: lipgloss.NoColor{},
+		LogoLock:  lipgloss.NoColor{},
+	},
+}
+
+// Default returns the dark theme, which matches the original color scheme.
+func Default() Theme {
+	return builtins[Dark]
+}
+
+// Names returns the names accepted by Resolve, sorted alphabetically.
+func Names() []string {
+	names := []string{Auto}
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the theme for the given name. NO_COLOR always wins, and
+// "auto" (or an empty name) picks light or dark from the terminal background.
+func Resolve(name string) (Theme, error) {
+	if noColor() {
+		return builtins[None], nil
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == Auto {
+		if lipgloss.HasDarkBackground() {
+			return builtins[Dark], nil
+		}
+		return builtins[Light], nil
+	}
+
+	t, ok := builtins[name]
+	if !ok {
+		return Default(), fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return t, nil
+}
+
+// noColor reports whether the NO_COLOR convention (https://no-color.org) is in effect.
+func noColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// Apply overrides individual colors by role name, e.g. {"primary": "#FF0000"}.
+// Values may be hex colors or ANSI color numbers.
+func (t *Theme) Apply(overrides map[string]string) error {
+	if t.Name == None {
+		return nil
+	}
+
+	roles := map[string]*lipgloss.TerminalColor{
+		"primary":   &t.Primary,
+		"onprimary": &t.OnPrimary,
+		"text":      &t.Text,
+		"muted":     &t.Muted,
+		"success":   &t.Success,
+		"warning":   &t.Warning,
+		"error":     &t.Error,
+		"logoframe": &t.LogoFrame,
+		"logolabel": &t.LogoLabel,
+		"logolock":  &t.LogoLock,
+	}
+
+	for role, value := range overrides {
+		normalized := strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(role))
+		target, ok := roles[normalized]
+		if !ok {
+			return fmt.Errorf("unknown theme color %q", role)
+		}
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("empty value for theme color %q", role)
+		}
+		*target = lipgloss.Color(value)
+	}
+	return nil
+}
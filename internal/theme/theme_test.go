@@ -0,0 +1,90 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		noColor  string
+		expected string
+		wantErr  bool
+	}{
+		{name: "dark", input: "dark", expected: Dark},
+		{name: "light is case-insensitive", input: " Light ", expected: Light},
+		{name: "high contrast", input: "high-contrast", expected: HighContrast},
+		{name: "NO_COLOR overrides explicit theme", input: "dark", noColor: "1", expected: None},
+		{name: "unknown theme falls back to default", input: "solarized", expected: Dark, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+
+			result, err := Resolve(tt.input)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if result.Name != tt.expected {
+				t.Errorf("Resolve(%q) = %q, expected %q", tt.input, result.Name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinThemesAreComplete(t *testing.T) {
+	for name, th := range builtins {
+		t.Run(name, func(t *testing.T) {
+			colors := []lipgloss.TerminalColor{
+				th.Primary, th.OnPrimary, th.Text, th.Muted, th.Success,
+				th.Warning, th.Error, th.LogoFrame, th.LogoLabel, th.LogoLock,
+			}
+			for i, c := range colors {
+				if c == nil {
+					t.Errorf("color %d is not set", i)
+				}
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	th := Default()
+
+	if err := th.Apply(map[string]string{"primary": "#123456", "on_primary": "15"}); err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+	if th.Primary != lipgloss.Color("#123456") {
+		t.Errorf("Primary = %v, expected #123456", th.Primary)
+	}
+	if th.OnPrimary != lipgloss.Color("15") {
+		t.Errorf("OnPrimary = %v, expected 15", th.OnPrimary)
+	}
+
+	if err := th.Apply(map[string]string{"sparkle": "#fff"}); err == nil {
+		t.Error("Apply() expected error for unknown role")
+	}
+}
+
+func TestApplyIgnoredWithoutColor(t *testing.T) {
+	th := builtins[None]
+	if err := th.Apply(map[string]string{"primary": "#123456"}); err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+	if th.Primary != (lipgloss.NoColor{}) {
+		t.Errorf("colors should stay disabled when NO_COLOR is in effect")
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := strings.Join(Names(), ",")
+	if names != "auto,dark,high-contrast,light,none" {
+		t.Errorf("Names() = %q", names)
+	}
+}
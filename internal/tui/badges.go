@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jellydn/dotenv-tui/internal/audit"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+	"github.com/jellydn/dotenv-tui/internal/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fileBadges is the metadata shown next to a file in the picker.
+type fileBadges struct {
+	exists  bool
+	keys    int
+	sibling string // the counterpart .env or .env.example, if it exists
+	modTime time.Time
+	tracked bool
+}
+
+// badgeLookahead is how many screens of rows past the visible ones get
+// their badges loaded, so scrolling does not load them one row at a time.
+const badgeLookahead = 2
+
+// loadBadges computes the badges of the files about to be shown that do
+// not have them yet. Badges are cached for the picker's lifetime.
+func (m *PickerModel) loadBadges() {
+	rows := m.rows()
+	end := min(len(rows), m.offset+max(m.visibleLines(), 1)*badgeLookahead)
+	var files []string
+	for _, i := range rows[min(m.offset, end):end] {
+		item := m.items[i]
+		if _, ok := m.badges[item.filePath]; item.isFile() && !ok {
+			files = append(files, item.filePath)
+		}
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	if m.badges == nil {
+		m.badges = make(map[string]fileBadges)
+	}
+	root := m.rootDir
+	if root == "" {
+		root = "."
+	}
+	tracked, _ := audit.GitStatus(root, files)
+	for _, f := range files {
+		m.badges[f] = readBadges(m.mode, filepath.Join(root, f), tracked[f])
+	}
+}
+
+func readBadges(mode MenuChoice, path string, tracked bool) fileBadges {
+	mode = badgeMode(mode, path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileBadges{}
+	}
+	b := fileBadges{exists: true, modTime: info.ModTime(), tracked: tracked}
+	for _, e := range readEntries(path) {
+		if _, ok := e.(parser.KeyValue); ok {
+			b.keys++
+		}
+	}
+
+	siblings := scanner.ExamplePaths(path)
+	if mode == GenerateEnv {
+		siblings = []string{scanner.ExampleTarget(path)}
+	}
+	for _, s := range siblings {
+		if _, err := os.Stat(s); err == nil {
+			b.sibling = filepath.Base(s)
+			break
+		}
+	}
+	return b
+}
+
+// badgeMode returns the mode whose badges suit path. The compare and move
+// pickers list env files and examples together, so each is badged as the
+// picker listing only its kind would.
+func badgeMode(mode MenuChoice, path string) MenuChoice {
+	if mode != CompareFiles && mode != MoveKeys {
+		return mode
+	}
+	if scanner.ExampleTarget(path) != path {
+		return GenerateEnv
+	}
+	return GenerateExample
+}
+
+// render formats the badges as a faint suffix, with a warning for env
+// files committed to git.
+func (b fileBadges) render(mode MenuChoice, palette theme.Theme) string {
+	if !b.exists {
+		return ""
+	}
+	faint := lipgloss.NewStyle().Faint(true)
+
+	parts := []string{fmt.Sprintf("%d keys", b.keys)}
+	switch {
+	case b.sibling != "":
+		parts = append(parts, "✓ "+b.sibling)
+	case mode == GenerateEnv:
+		parts = append(parts, "no .env")
+	default:
+		parts = append(parts, "no .env.example")
+	}
+	parts = append(parts, ago(b.modTime))
+
+	out := "  " + faint.Render(strings.Join(parts, " · "))
+	// Examples are meant to be committed; real env files are not.
+	if b.tracked && mode != GenerateEnv {
+		out += " " + lipgloss.NewStyle().Foreground(palette.Warning).Render("⚠ tracked by git")
+	}
+	return out
+}
+
+// ago formats t relative to now, e.g. "5m ago", falling back to the date
+// for anything older than a week.
+func ago(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 7*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	default:
+		return t.Format("2006-01-02")
+	}
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+func TestPickerBadges(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"api/.env":         "A=1\nB=2\n# comment\n",
+		"api/.env.example": "A=\nB=\n",
+		"web/.env":         "C=3\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	updated, _ := PickerModel{}.Update(NewPickerModel(GenerateExample, dir, DefaultOptions())())
+	m := updated.(PickerModel)
+	updated, _ = m.Update(tea.WindowSizeMsg{Height: 30})
+	m = updated.(PickerModel)
+
+	view := m.View()
+	for _, want := range []string{"2 keys · ✓ .env.example · just now", "1 keys · no .env.example"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing badge %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestFileBadgesRender(t *testing.T) {
+	b := fileBadges{exists: true, keys: 3, modTime: time.Now().Add(-3 * time.Hour), tracked: true}
+	if got := b.render(GenerateExample, theme.Default()); !strings.Contains(got, "3 keys · no .env.example · 3h ago") || !strings.Contains(got, "tracked by git") {
+		t.Errorf("render() = %q", got)
+	}
+	if got := b.render(GenerateEnv, theme.Default()); strings.Contains(got, "tracked by git") || !strings.Contains(got, "no .env ·") {
+		t.Errorf("render(GenerateEnv) = %q, examples are meant to be tracked", got)
+	}
+	if got := (fileBadges{}).render(GenerateExample, theme.Default()); got != "" {
+		t.Errorf("render() of a missing file = %q, want empty", got)
+	}
+}
+
+func TestAgo(t *testing.T) {
+	now := time.Now()
+	tests := map[time.Duration]string{
+		10 * time.Second:    "just now",
+		5 * time.Minute:     "5m ago",
+		2 * time.Hour:       "2h ago",
+		3 * 24 * time.Hour:  "3d ago",
+		30 * 24 * time.Hour: now.Add(-30 * 24 * time.Hour).Format("2006-01-02"),
+	}
+	for d, want := range tests {
+		if got := ago(now.Add(-d)); got != want {
+			t.Errorf("ago(-%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,510 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/diff"
+	"github.com/jellydn/dotenv-tui/internal/direnv"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// compareKind says how a key differs between the two compared files.
+type compareKind int
+
+const (
+	compareSame compareKind = iota
+	compareChanged
+	compareLeftOnly
+	compareRightOnly
+)
+
+// compareRow is one key of a comparison.
+type compareRow struct {
+	key         string
+	kind        compareKind
+	left, right string
+	secret      bool
+}
+
+// marker returns the sign shown before the row: "~" for a changed value,
+// "-" for a key only the left file has and "+" for one only the right has.
+func (r compareRow) marker() string {
+	switch r.kind {
+	case compareChanged:
+		return "~"
+	case compareLeftOnly:
+		return "-"
+	case compareRightOnly:
+		return "+"
+	default:
+		return " "
+	}
+}
+
+// CompareModel is the Bubble Tea model for comparing the keys of two env
+// files side by side.
+type CompareModel struct {
+	left, right  string
+	rows         []compareRow
+	leftLines    []string // file contents, for the exported patch
+	rightLines   []string
+	errMsg       string
+	onlyDiffs    bool // hide the keys both files agree on
+	reveal       bool // show secret values instead of masking them
+	cursor       int  // position in shown()
+	offset       int  // first visible position in shown()
+	windowHeight int
+	windowWidth  int
+	prompting    bool // the patch path prompt is open
+	patchPath    textinput.Model
+	showHelp     bool
+	opts         Options
+}
+
+// CompareFinishedMsg signals the user left the compare screen.
+type CompareFinishedMsg struct{}
+
+// PatchWrittenMsg reports that a comparison was exported as a patch.
+type PatchWrittenMsg struct {
+	Path string
+}
+
+type compareInitMsg struct {
+	left, right           string
+	rows                  []compareRow
+	leftLines, rightLines []string
+	errMsg                string
+	opts                  Options
+}
+
+// NewCompareModel loads the files left and right for comparison.
+func NewCompareModel(left, right string, opts Options) tea.Cmd {
+	return func() tea.Msg {
+		msg := compareInitMsg{left: left, right: right, opts: opts}
+		leftEntries, leftLines, err := readCompared(left)
+		if err != nil {
+			msg.errMsg = err.Error()
+			return msg
+		}
+		rightEntries, rightLines, err := readCompared(right)
+		if err != nil {
+			msg.errMsg = err.Error()
+			return msg
+		}
+		msg.rows = compareEntries(leftEntries, rightEntries, opts.Example.Masking.Rules)
+		msg.leftLines, msg.rightLines = leftLines, rightLines
+		return msg
+	}
+}
+
+// readCompared parses the file at path, reading the exports of a direnv
+// .envrc, and also returns its lines.
+func readCompared(path string) ([]parser.Entry, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	parse := parser.Parse
+	if direnv.IsDirenvFile(path) {
+		parse = direnv.Parse
+	}
+	entries, err := parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if text == "" {
+		return entries, nil, nil
+	}
+	return entries, strings.Split(strings.TrimSuffix(text, "\n"), "\n"), nil
+}
+
+// compareEntries lines up the keys of left and right: left's keys in its
+// order, then the keys only right has in right's order. A key set twice is
+// compared by its last value, the one that takes effect. Rows whose values
+// are secret under rules are marked so.
+func compareEntries(left, right []parser.Entry, rules detector.Rules) []compareRow {
+	leftKeys, leftValues := keyValues(left)
+	rightKeys, rightValues := keyValues(right)
+
+	var rows []compareRow
+	for _, k := range leftKeys {
+		row := compareRow{key: k, left: leftValues[k]}
+		if v, ok := rightValues[k]; !ok {
+			row.kind = compareLeftOnly
+		} else if row.right = v; v != row.left {
+			row.kind = compareChanged
+		}
+		rows = append(rows, row)
+	}
+	for _, k := range rightKeys {
+		if _, ok := leftValues[k]; !ok {
+			rows = append(rows, compareRow{key: k, kind: compareRightOnly, right: rightValues[k]})
+		}
+	}
+	for i := range rows {
+		rows[i].secret = rules.IsSecret(rows[i].key, rows[i].left) || rules.IsSecret(rows[i].key, rows[i].right)
+	}
+	return rows
+}
+
+// keyValues returns the keys of entries in the order they first appear,
+// with the last value of each.
+func keyValues(entries []parser.Entry) ([]string, map[string]string) {
+	var keys []string
+	values := make(map[string]string)
+	for _, e := range entries {
+		kv, ok := e.(parser.KeyValue)
+		if !ok {
+			continue
+		}
+		if _, seen := values[kv.Key]; !seen {
+			keys = append(keys, kv.Key)
+		}
+		values[kv.Key] = kv.Value
+	}
+	return keys, values
+}
+
+// Init initializes the compare model.
+func (m CompareModel) Init() tea.Cmd {
+	return nil
+}
+
+// SetWindowHeight sets the terminal height for scroll calculations.
+func (m *CompareModel) SetWindowHeight(h int) {
+	m.windowHeight = h
+}
+
+// SetWindowWidth sets the terminal width the columns are fitted to.
+func (m *CompareModel) SetWindowWidth(w int) {
+	m.windowWidth = w
+}
+
+// Differences returns how many keys differ between the two files.
+func (m CompareModel) Differences() int {
+	n := 0
+	for _, r := range m.rows {
+		if r.kind != compareSame {
+			n++
+		}
+	}
+	return n
+}
+
+// shown returns the indexes of the rows listed, which are only the
+// differences while filtering.
+func (m CompareModel) shown() []int {
+	idx := make([]int, 0, len(m.rows))
+	for i, r := range m.rows {
+		if !m.onlyDiffs || r.kind != compareSame {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// compareOverheadLines is everything but the rows: blank line, title,
+// summary, blank line, column header, scroll info and blank line.
+const compareOverheadLines = 7
+
+func (m CompareModel) visibleLines() int {
+	overhead := compareOverheadLines + lipgloss.Height(shortHelp(compareKeys(m.opts.Keys, m.prompting).short, m.windowWidth))
+	if m.prompting {
+		overhead += 2 // blank line + prompt
+	}
+	if m.windowHeight <= overhead {
+		return 10 // fallback to default if window is too small
+	}
+	return m.windowHeight - overhead
+}
+
+func (m *CompareModel) adjustScroll() {
+	visible := m.visibleLines()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+// move moves the cursor by delta rows, staying within the list.
+func (m *CompareModel) move(delta int) {
+	m.cursor = max(min(m.cursor+delta, len(m.shown())-1), 0)
+	m.adjustScroll()
+}
+
+// jumpDiff moves the cursor to the next difference in direction dir.
+func (m *CompareModel) jumpDiff(dir int) tea.Cmd {
+	shown := m.shown()
+	for p := m.cursor + dir; p >= 0 && p < len(shown); p += dir {
+		if m.rows[shown[p]].kind != compareSame {
+			m.cursor = p
+			m.adjustScroll()
+			return nil
+		}
+	}
+	return statusCmd("No more differences", false)
+}
+
+// toggleFilter switches between all keys and only the differences, keeping
+// the cursor on the same key, or the next one still shown.
+func (m *CompareModel) toggleFilter() {
+	current := -1
+	if shown := m.shown(); m.cursor < len(shown) {
+		current = shown[m.cursor]
+	}
+	m.onlyDiffs = !m.onlyDiffs
+	shown := m.shown()
+	m.cursor = max(len(shown)-1, 0)
+	for p, i := range shown {
+		if i >= current {
+			m.cursor = p
+			break
+		}
+	}
+	m.adjustScroll()
+}
+
+// openPrompt starts asking where to write the patch.
+func (m *CompareModel) openPrompt() tea.Cmd {
+	m.patchPath = textinput.New()
+	m.patchPath.Prompt = "Write patch to: "
+	m.patchPath.Width = 40
+	m.patchPath.SetValue(patchName(m.left, m.right))
+	m.prompting = true
+	return m.patchPath.Focus()
+}
+
+// patchName suggests a file name for the patch between left and right, e.g.
+// "env.staging-vs-env.production.patch". It does not start with ".env", so
+// scans do not mistake it for an env file.
+func patchName(left, right string) string {
+	name := func(path string) string { return strings.TrimPrefix(filepath.Base(path), ".") }
+	return name(left) + "-vs-" + name(right) + ".patch"
+}
+
+// writePatch writes a unified diff turning the left file into the right
+// one to path.
+func (m CompareModel) writePatch(path string) tea.Cmd {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil
+	}
+	patch := diff.Unified(filepath.ToSlash(m.left), filepath.ToSlash(m.right), m.leftLines, m.rightLines)
+	if patch == "" {
+		return statusCmd("The files are identical; no patch written", false)
+	}
+	return func() tea.Msg {
+		// The patch holds both files' values, secrets included.
+		if err := os.WriteFile(path, []byte(patch), 0600); err != nil {
+			return StatusMsg{Text: fmt.Sprintf("Failed to write patch: %v", err), Error: true}
+		}
+		return PatchWrittenMsg{Path: path}
+	}
+}
+
+// Update handles messages and updates the compare model.
+func (m CompareModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case compareInitMsg:
+		m.opts = msg.opts
+		m.left, m.right = msg.left, msg.right
+		m.rows = msg.rows
+		m.leftLines, m.rightLines = msg.leftLines, msg.rightLines
+		m.errMsg = msg.errMsg
+		m.cursor, m.offset = 0, 0
+		m.onlyDiffs, m.reveal, m.prompting = false, false, false
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.windowHeight = msg.Height
+		m.windowWidth = msg.Width
+		m.adjustScroll()
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.prompting {
+			switch {
+			case key.Matches(msg, m.opts.Keys.Compare.Confirm):
+				m.prompting = false
+				return m, m.writePatch(m.patchPath.Value())
+			case key.Matches(msg, m.opts.Keys.Compare.Cancel):
+				m.prompting = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.patchPath, cmd = m.patchPath.Update(msg)
+			return m, cmd
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.opts.Keys.Compare.Up):
+			m.move(-1)
+		case key.Matches(msg, m.opts.Keys.Compare.Down):
+			m.move(1)
+		case key.Matches(msg, m.opts.Keys.Compare.NextDiff):
+			return m, m.jumpDiff(1)
+		case key.Matches(msg, m.opts.Keys.Compare.PrevDiff):
+			return m, m.jumpDiff(-1)
+		case key.Matches(msg, m.opts.Keys.Compare.Filter):
+			m.toggleFilter()
+		case key.Matches(msg, m.opts.Keys.Compare.Reveal):
+			m.reveal = !m.reveal
+		case key.Matches(msg, m.opts.Keys.Compare.Export):
+			if m.errMsg == "" {
+				return m, m.openPrompt()
+			}
+		case key.Matches(msg, m.opts.Keys.Help):
+			m.showHelp = true
+		case key.Matches(msg, m.opts.Keys.Compare.Back):
+			return m, func() tea.Msg { return CompareFinishedMsg{} }
+		}
+	}
+	return m, nil
+}
+
+// maxCompareKeyWidth caps the key column so long keys leave room for values.
+const maxCompareKeyWidth = 32
+
+// defaultCompareWidth lays out the columns until the terminal width is known.
+const defaultCompareWidth = 100
+
+// columns returns the widths of the key column and of each value column.
+func (m CompareModel) columns() (keyWidth, valueWidth int) {
+	keyWidth = len("KEY")
+	for _, r := range m.rows {
+		keyWidth = max(keyWidth, ansi.StringWidth(r.key))
+	}
+	keyWidth = min(keyWidth, maxCompareKeyWidth)
+	width := m.windowWidth
+	if width <= 0 {
+		width = defaultCompareWidth
+	}
+	// "> ~ " before the key, and two spaces before each value.
+	return keyWidth, max((width-4-keyWidth-4)/2, 8)
+}
+
+// cell formats one side of a row: missing and empty values are spelled
+// out, and secrets are masked unless revealed.
+func (m CompareModel) cell(r compareRow, value string, present bool) string {
+	switch {
+	case !present:
+		return "(missing)"
+	case value == "":
+		return "(empty)"
+	case r.secret && !m.reveal:
+		return strings.Repeat("•", 8)
+	default:
+		return value
+	}
+}
+
+// summary counts the differences by kind.
+func (m CompareModel) summary() string {
+	var changed, leftOnly, rightOnly int
+	for _, r := range m.rows {
+		switch r.kind {
+		case compareChanged:
+			changed++
+		case compareLeftOnly:
+			leftOnly++
+		case compareRightOnly:
+			rightOnly++
+		}
+	}
+	if changed+leftOnly+rightOnly == 0 {
+		return fmt.Sprintf("%d keys, no differences", len(m.rows))
+	}
+	return fmt.Sprintf("%d changed, %d only left, %d only right", changed, leftOnly, rightOnly)
+}
+
+// View renders the comparison.
+func (m CompareModel) View() string {
+	if m.showHelp {
+		return helpOverlay(compareKeys(m.opts.Keys, false), m.opts.Theme)
+	}
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.opts.Theme.OnPrimary).
+		Background(m.opts.Theme.Primary).
+		Padding(0, 1).
+		Render("Compare env files")
+	faint := lipgloss.NewStyle().Faint(true)
+
+	if m.errMsg != "" {
+		errText := lipgloss.NewStyle().Foreground(m.opts.Theme.Error).Render(fitWidth(m.errMsg, m.windowWidth))
+		return "\n" + title + "\n\n" + errText + "\n\n" + shortHelp([]key.Binding{m.opts.Keys.Compare.Back}, m.windowWidth) + "\n"
+	}
+
+	filter := "all keys"
+	if m.onlyDiffs {
+		filter = "only differences"
+	}
+	summary := faint.Render(fitWidth(m.summary()+" · showing "+filter, m.windowWidth))
+
+	keyWidth, valueWidth := m.columns()
+	header := faint.Bold(true).Render("    " + padWidth("KEY", keyWidth) +
+		"  " + padWidth(filepath.ToSlash(m.left), valueWidth) +
+		"  " + fitWidth(filepath.ToSlash(m.right), valueWidth))
+
+	var list strings.Builder
+	shown := m.shown()
+	visible := m.visibleLines()
+	end := min(m.offset+visible, len(shown))
+	if len(shown) == 0 {
+		list.WriteString(faint.Render("  No differences") + "\n")
+	}
+	for p := m.offset; p < end; p++ {
+		r := m.rows[shown[p]]
+		cursor := " "
+		if p == m.cursor {
+			cursor = ">"
+		}
+		line := cursor + " " + r.marker() + " " + padWidth(r.key, keyWidth) +
+			"  " + padWidth(m.cell(r, r.left, r.kind != compareRightOnly), valueWidth) +
+			"  " + fitWidth(m.cell(r, r.right, r.kind != compareLeftOnly), valueWidth)
+
+		style := lipgloss.NewStyle()
+		switch r.kind {
+		case compareChanged:
+			style = style.Foreground(m.opts.Theme.Warning)
+		case compareLeftOnly:
+			style = style.Foreground(m.opts.Theme.Error)
+		case compareRightOnly:
+			style = style.Foreground(m.opts.Theme.Success)
+		default:
+			style = style.Faint(true)
+		}
+		if p == m.cursor {
+			style = style.Bold(true).Background(m.opts.Theme.Primary)
+		}
+		list.WriteString(style.Render(line) + "\n")
+	}
+	if len(shown) > visible {
+		list.WriteString(faint.Render(fmt.Sprintf("Key %d/%d", m.cursor+1, len(shown))) + "\n")
+	}
+
+	if m.prompting {
+		list.WriteString("\n" + m.patchPath.View() + "\n")
+	}
+
+	help := shortHelp(compareKeys(m.opts.Keys, m.prompting).short, m.windowWidth)
+
+	return "\n" + title + "\n" + summary + "\n\n" + header + "\n" + list.String() + "\n" + help + "\n"
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCompareEntries(t *testing.T) {
+	left := []parser.Entry{
+		parser.Comment{Text: "# staging"},
+		parser.KeyValue{Key: "HOST", Value: "staging.example.com"},
+		parser.KeyValue{Key: "PORT", Value: "3000"},
+		parser.KeyValue{Key: "DEBUG", Value: "true"},
+		parser.KeyValue{Key: "PORT", Value: "8080"},
+	}
+	right := []parser.Entry{
+		parser.KeyValue{Key: "SENTRY_DSN", Value: "https://sentry.example.com/1"},
+		parser.KeyValue{Key: "PORT", Value: "8080"},
+		parser.KeyValue{Key: "HOST", Value: "example.com"},
+	}
+
+	got := compareEntries(left, right, detector.Rules{})
+	want := []struct {
+		key  string
+		kind compareKind
+	}{
+		{"HOST", compareChanged},
+		{"PORT", compareSame},
+		{"DEBUG", compareLeftOnly},
+		{"SENTRY_DSN", compareRightOnly},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("compareEntries() = %+v, want %d rows", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].key != w.key || got[i].kind != w.kind {
+			t.Errorf("row %d = %s %v, want %s %v", i, got[i].key, got[i].kind, w.key, w.kind)
+		}
+	}
+}
+
+// loadCompare writes the two files into a temp directory and opens them on
+// the compare screen.
+func loadCompare(t *testing.T, left, right string) (CompareModel, string) {
+	t.Helper()
+	dir := t.TempDir()
+	leftPath, rightPath := filepath.Join(dir, ".env.staging"), filepath.Join(dir, ".env.production")
+	if err := os.WriteFile(leftPath, []byte(left), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(rightPath, []byte(right), 0600); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := CompareModel{}.Update(NewCompareModel(leftPath, rightPath, DefaultOptions())())
+	return updated.(CompareModel), dir
+}
+
+func compareKey(m CompareModel, r rune) (CompareModel, tea.Cmd) {
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	return updated.(CompareModel), cmd
+}
+
+func TestCompareModel(t *testing.T) {
+	m, _ := loadCompare(t,
+		"HOST=staging\nPORT=3000\nAPI_KEY=sk_live_staging1234\nDEBUG=true\n",
+		"HOST=prod\nPORT=3000\nAPI_KEY=sk_live_prod5678\nSENTRY_DSN=https://sentry\n")
+
+	if m.Differences() != 4 {
+		t.Errorf("Differences() = %d, want 4", m.Differences())
+	}
+	view := m.View()
+	for _, want := range []string{"Compare env files", "2 changed, 1 only left, 1 only right", "~ HOST", "+ SENTRY_DSN", "(missing)", "••••••••"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "sk_live") {
+		t.Errorf("View() should mask secret values until revealed")
+	}
+
+	m, _ = compareKey(m, 'r')
+	if !strings.Contains(m.View(), "sk_live_prod5678") {
+		t.Errorf("r should reveal secret values")
+	}
+
+	// n skips PORT, which is the same in both files.
+	m, _ = compareKey(m, 'n')
+	if got := m.rows[m.shown()[m.cursor]].key; got != "API_KEY" {
+		t.Errorf("n moved to %s, want API_KEY", got)
+	}
+
+	m, _ = compareKey(m, 'd')
+	if len(m.shown()) != 4 || strings.Contains(m.View(), "PORT") {
+		t.Errorf("d should list only the differences:\n%s", m.View())
+	}
+	if got := m.rows[m.shown()[m.cursor]].key; got != "API_KEY" {
+		t.Errorf("filtering moved the cursor to %s, want it kept on API_KEY", got)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Esc should leave the compare screen")
+	}
+	if _, ok := cmd().(CompareFinishedMsg); !ok || updated == nil {
+		t.Errorf("Esc = %T, want CompareFinishedMsg", cmd())
+	}
+}
+
+func TestCompareExportPatch(t *testing.T) {
+	m, dir := loadCompare(t, "HOST=staging\nPORT=3000\n", "HOST=prod\nPORT=3000\n")
+
+	m, _ = compareKey(m, 'x')
+	if !m.prompting || m.patchPath.Value() != "env.staging-vs-env.production.patch" {
+		t.Fatalf("x should ask for the patch path, got %q", m.patchPath.Value())
+	}
+	path := filepath.Join(dir, "staging.patch")
+	m.patchPath.SetValue(path)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if updated.(CompareModel).prompting || cmd == nil {
+		t.Fatal("Enter should close the prompt and write the patch")
+	}
+	if written, ok := cmd().(PatchWrittenMsg); !ok || written.Path != path {
+		t.Fatalf("Enter = %+v, want PatchWrittenMsg for %s", written, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "-HOST=staging\n+HOST=prod\n") {
+		t.Errorf("patch = %q, want the HOST change", data)
+	}
+
+	same, _ := loadCompare(t, "PORT=3000\n", "PORT=3000\n")
+	same, _ = compareKey(same, 'x')
+	_, cmd = same.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if status := statusOf(t, cmd); !strings.Contains(status.Text, "identical") {
+		t.Errorf("exporting identical files = %q, want a note that nothing was written", status.Text)
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"path/filepath"
+
+	"github.com/jellydn/dotenv-tui/internal/backup"
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/schema"
+)
+
+// settingsFor returns the settings for the file at path. Without
+// per-directory settings, backups are left to the toggle.
+func (o Options) settingsFor(path string) (config.DirSettings, error) {
+	if o.Dir == nil {
+		return config.DirSettings{Example: o.Example, Backup: true}, nil
+	}
+	return o.Dir(path)
+}
+
+// backupOptions returns the backup directory and compression set for the
+// file at path.
+func (o Options) backupOptions(path string) backup.Options {
+	if o.Dir == nil {
+		return backup.Options{Deterministic: o.Deterministic}
+	}
+	s, err := o.Dir(path)
+	if err != nil {
+		return backup.Options{Deterministic: o.Deterministic}
+	}
+	return backup.Options{Dir: s.BackupDir, Compress: s.BackupCompress, Deterministic: o.Deterministic}
+}
+
+// schemaPath returns the schema file describing the env file generated
+// from the example at examplePath: the one set for its directory, or the
+// .env.schema next to it.
+func (o Options) schemaPath(examplePath string) string {
+	if s, err := o.settingsFor(examplePath); err == nil && s.Schema != "" {
+		return s.Schema
+	}
+	return filepath.Join(filepath.Dir(examplePath), schema.FileName)
+}
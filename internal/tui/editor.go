@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// editorFinishedMsg reports that the external editor opened by editEntries
+// has exited.
+type editorFinishedMsg struct {
+	path string
+	err  error
+}
+
+// editorCommand returns the command that opens path in $EDITOR, which may
+// include arguments such as "code --wait". Without $EDITOR it falls back to
+// vi, or notepad on Windows.
+func editorCommand(path string) *exec.Cmd {
+	args := strings.Fields(os.Getenv("EDITOR"))
+	if len(args) == 0 {
+		args = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			args = []string{"notepad"}
+		}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// editEntries writes entries to a private temporary file and opens it in
+// the user's editor, suspending the TUI until the editor exits.
+func (o Options) editEntries(entries []parser.Entry) tea.Cmd {
+	f, err := os.CreateTemp("", "dotenv-tui-*.env")
+	if err != nil {
+		return func() tea.Msg {
+			return editorFinishedMsg{err: fmt.Errorf("failed to create temporary file: %w", err)}
+		}
+	}
+	path := f.Name()
+	err = parser.WriteWithOptions(f, entries, o.Write)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return func() tea.Msg {
+			return editorFinishedMsg{path: path, err: fmt.Errorf("failed to write temporary file: %w", err)}
+		}
+	}
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		return editorFinishedMsg{path: path, err: err}
+	})
+}
+
+// readEdited parses the file saved by the editor and removes it.
+func readEdited(msg editorFinishedMsg) ([]parser.Entry, error) {
+	if msg.path != "" {
+		defer func() { _ = os.Remove(msg.path) }()
+	}
+	if msg.err != nil {
+		return nil, fmt.Errorf("editor failed: %w", msg.err)
+	}
+	f, err := os.Open(msg.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	entries, err := parser.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse edited file: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("EDITOR", "code --wait")
+	cmd := editorCommand("/tmp/x.env")
+	if want := []string{"code", "--wait", "/tmp/x.env"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("editorCommand() args = %v, want %v", cmd.Args, want)
+	}
+}
+
+// editedFile writes content where the editor would have saved it.
+func editedFile(t *testing.T, content string) editorFinishedMsg {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "edited.env")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return editorFinishedMsg{path: path}
+}
+
+func TestReadEdited(t *testing.T) {
+	msg := editedFile(t, "A=1\n")
+	entries, err := readEdited(msg)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("readEdited() = %v, %v", entries, err)
+	}
+	if _, err := os.Stat(msg.path); !os.IsNotExist(err) {
+		t.Errorf("readEdited() should remove the temporary file")
+	}
+
+	if _, err := readEdited(editorFinishedMsg{err: errors.New("exit status 1")}); err == nil || !strings.Contains(err.Error(), "editor failed") {
+		t.Errorf("readEdited() error = %v, want editor failure", err)
+	}
+}
+
+func TestFormExternalEditor(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("# app\nPORT=3000\nNAME=demo\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+	m := updated.(FormModel)
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlX}); cmd == nil {
+		t.Fatal("Ctrl+X should open the editor")
+	}
+
+	updated, _ = m.Update(editedFile(t, "# app\nPORT=8080\nNAME=demo\nEXTRA=1\n"))
+	m = updated.(FormModel)
+	if len(m.fields) != 3 || m.fields[0].value() != "8080" || m.fields[0].source != sourceNew || m.fields[2].Key != "EXTRA" {
+		t.Fatalf("fields after editing = %+v", m.fields)
+	}
+	if !m.fields[0].hasRule {
+		t.Error("edited fields should keep their validation rule")
+	}
+
+	saved := m.saveForm()().(FormSavedMsg)
+	if !saved.Success {
+		t.Fatalf("saveForm() failed: %s", saved.Error)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# app\nPORT=8080\nNAME=demo\nEXTRA=1\n"; string(content) != want {
+		t.Errorf(".env = %q, want %q", content, want)
+	}
+
+	_, cmd := m.Update(editedFile(t, "BROKEN=\"unclosed\n"))
+	if status := statusOf(t, cmd); !status.Error || !strings.Contains(status.Text, "failed to parse edited file") {
+		t.Errorf("status = %+v, want the parse error", status)
+	}
+}
+
+func TestPreviewExternalEditor(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envPath, []byte("PORT=3000\nAPI_KEY=sk_live_abcdef\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := PreviewModel{}.Update(NewPreviewModel([]string{envPath}, false, DefaultOptions())())
+	m := updated.(PreviewModel)
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}}); cmd == nil {
+		t.Fatal("e should open the editor")
+	}
+
+	updated, cmd := m.Update(editedFile(t, "PORT=3000\nAPI_KEY=\n"))
+	m = updated.(PreviewModel)
+	if got := m.files[0].diffLines; len(got) != 2 || !strings.Contains(got[1], "API_KEY= [masked: key matches API_KEY") {
+		t.Errorf("diffLines after editing = %q", got)
+	}
+	if status := statusOf(t, cmd); !strings.HasPrefix(status.Text, "Edited") {
+		t.Errorf("status = %+v, want the edit confirmed", status)
+	}
+
+	m.writeAllFiles()
+	content, err := os.ReadFile(filepath.Join(dir, ".env.example"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "PORT=3000\nAPI_KEY=\n" {
+		t.Errorf(".env.example = %q, want the edited content", content)
+	}
+}
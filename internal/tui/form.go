@@ -2,14 +2,23 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/jellydn/dotenv-tui/internal/backup"
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/history"
 	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/schema"
+	"github.com/jellydn/dotenv-tui/internal/theme"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -22,6 +31,109 @@ type FormField struct {
 	Placeholder   string
 	Input         textinput.Model
 	IsPlaceholder bool
+	// Current is the key's value in the existing .env, if HasCurrent.
+	Current    string
+	HasCurrent bool
+	source     valueSource
+	// rule validates the value, from .env.schema or, without one, the type
+	// of the example value.
+	rule    schema.Field
+	hasRule bool
+	// text holds a value spanning several lines, which Input cannot; such
+	// fields are edited with the multiline editor.
+	text      string
+	multiline bool
+	// secret fields are masked while typing, and a typed value must be
+	// entered twice; confirmed is the last value accepted that way.
+	secret    bool
+	confirmed string
+	// note is the provenance comment above the key in the existing .env.
+	note string
+}
+
+// value returns the field's current value.
+func (f FormField) value() string {
+	if f.multiline {
+		return f.text
+	}
+	return f.Input.Value()
+}
+
+// setValue replaces the field's value, moving it in or out of the
+// multiline editor as needed.
+func (f *FormField) setValue(v string) {
+	f.multiline = strings.Contains(v, "\n")
+	if f.multiline {
+		f.text = v
+		f.Input.SetValue("")
+		return
+	}
+	f.text = ""
+	f.Input.SetValue(v)
+}
+
+// validate returns the problem with the field's value, if any.
+func (f FormField) validate() error {
+	if f.hasRule {
+		if err := f.rule.Validate(f.value()); err != nil {
+			return err
+		}
+	}
+	if f.needsConfirm() {
+		return errNotConfirmed
+	}
+	return nil
+}
+
+// warning returns a doubt about the field's value that does not block
+// saving, such as a token too short to be real.
+func (f FormField) warning() string {
+	if problem := detector.ShapeProblem(f.value()); problem != "" {
+		return "value " + problem
+	}
+	return ""
+}
+
+// errNotConfirmed marks a secret typed once but not yet entered again.
+var errNotConfirmed = errors.New("not confirmed (Enter to type it again)")
+
+// needsConfirm reports whether the field is a secret whose value was typed
+// but not yet confirmed.
+func (f FormField) needsConfirm() bool {
+	return f.secret && !f.multiline && f.value() != "" && f.value() != f.confirmed
+}
+
+// mask hides or shows the typed characters of a secret field.
+func (f *FormField) mask(hidden bool) {
+	if !f.secret {
+		return
+	}
+	f.Input.EchoMode = textinput.EchoNormal
+	if hidden {
+		f.Input.EchoMode = textinput.EchoPassword
+		f.Input.EchoCharacter = '•'
+	}
+}
+
+// valueSource is where a field's value comes from when regenerating over an
+// existing .env.
+type valueSource int
+
+const (
+	sourceExample valueSource = iota
+	sourceCurrent
+	sourceNew
+)
+
+func (s valueSource) String() string {
+	switch s {
+	case sourceCurrent:
+		return "current"
+	case sourceNew:
+		return "new"
+	default:
+		return "example"
+	}
 }
 
 // FormModel is the Bubble Tea model for the interactive form component.
@@ -37,10 +149,46 @@ type FormModel struct {
 	totalFiles      int
 	savedFiles      map[int]bool
 	enableBackup    bool
+	showHelp        bool
+	undoStack       []fieldEdit
+	redoStack       []fieldEdit
+	coalesceEdits   bool
+	backupPath      string
+	restoreMsg      string
+	regenerating    bool // an existing .env was loaded alongside the example
+	editor          textarea.Model
+	baseline        []parser.Entry // entries as loaded, to detect unsaved edits
+	busy            bool           // a save or restore is being written
+	editing         bool           // the multiline editor is open for the focused field
+	reviewing       bool           // showing the read-only summary before the form
+	overwrite       bool           // whether saving replaces an existing .env
+	windowWidth     int
+	confirmInput    textinput.Model
+	confirming      bool // a secret is being typed again to confirm it
+	discarding      bool // asking whether to leave with unsaved edits
+	exitAfterSave   bool // leave the form once the pending save succeeds
+	opts            Options
+}
+
+// fieldEdit records a change to one field's value for undo and redo.
+type fieldEdit struct {
+	field        int
+	before       string
+	after        string
+	beforeSource valueSource
+	afterSource  valueSource
 }
 
 // FormSavedMsg signals the form save operation has completed.
 type FormSavedMsg struct {
+	Success    bool
+	Error      string
+	Path       string // the .env written
+	BackupPath string // backup of the overwritten .env, if one was created
+}
+
+// FormRestoredMsg signals the saved .env was rolled back from its backup.
+type FormRestoredMsg struct {
 	Success bool
 	Error   string
 }
@@ -60,11 +208,19 @@ type formInitMsg struct {
 	totalFiles      int
 	savedFiles      map[int]bool
 	enableBackup    bool
+	overwrite       bool
+	opts            Options
 }
 
 // NewFormModel creates a new form model for collecting environment variables.
-func NewFormModel(exampleFilePath string, fileIndex, totalFiles int, savedFiles map[int]bool, enableBackup bool) tea.Cmd {
+func NewFormModel(exampleFilePath string, fileIndex, totalFiles int, savedFiles map[int]bool, enableBackup bool, opts Options) tea.Cmd {
 	return func() tea.Msg {
+		if s, err := opts.settingsFor(exampleFilePath); err == nil && !s.Backup {
+			enableBackup = false
+		}
+		_, statErr := os.Stat(formOutputPath(exampleFilePath))
+		overwrite := statErr == nil
+
 		file, err := os.Open(exampleFilePath)
 		if err != nil {
 			return formInitMsg{
@@ -74,6 +230,8 @@ func NewFormModel(exampleFilePath string, fileIndex, totalFiles int, savedFiles
 				totalFiles:   totalFiles,
 				savedFiles:   savedFiles,
 				enableBackup: enableBackup,
+				overwrite:    overwrite,
+				opts:         opts,
 			}
 		}
 		defer func() { _ = file.Close() }()
@@ -87,9 +245,25 @@ func NewFormModel(exampleFilePath string, fileIndex, totalFiles int, savedFiles
 				totalFiles:   totalFiles,
 				savedFiles:   savedFiles,
 				enableBackup: enableBackup,
+				overwrite:    overwrite,
+				opts:         opts,
+			}
+		}
+
+		current := make(map[string]string)
+		var notes map[string]string
+		if overwrite {
+			currentEntries := readEntries(formOutputPath(exampleFilePath))
+			for _, e := range currentEntries {
+				if kv, ok := e.(parser.KeyValue); ok {
+					current[kv.Key] = kv.Value
+				}
 			}
+			notes = parser.Provenance(currentEntries)
 		}
 
+		rules := opts.formRules(exampleFilePath, entries)
+
 		var fields []FormField
 		for _, entry := range entries {
 			if kv, ok := entry.(parser.KeyValue); ok {
@@ -102,18 +276,36 @@ func NewFormModel(exampleFilePath string, fileIndex, totalFiles int, savedFiles
 					value = kv.Value
 				}
 
-				input := textinput.New()
-				input.SetValue(value)
-				input.Placeholder = placeholder
-				input.Width = 50
-
-				fields = append(fields, FormField{
+				// Values already in the .env are kept unless the user
+				// chooses otherwise.
+				currentValue, hasCurrent := current[kv.Key]
+				source := sourceExample
+				if hasCurrent {
+					source = sourceCurrent
+				}
+				rule, hasRule := rules[kv.Key]
+				field := FormField{
 					Key:           kv.Key,
 					Value:         value,
 					Placeholder:   placeholder,
-					Input:         input,
+					Input:         newFieldInput(placeholder),
 					IsPlaceholder: isPlaceholder,
-				})
+					Current:       currentValue,
+					HasCurrent:    hasCurrent,
+					note:          notes[kv.Key],
+					source:        source,
+					rule:          rule,
+					hasRule:       hasRule,
+				}
+				if hasCurrent {
+					field.setValue(currentValue)
+				} else {
+					field.setValue(value)
+				}
+				field.secret = opts.Example.Masking.Rules.IsSecretKey(kv.Key) || detector.HasSecretValue(kv.Value)
+				field.confirmed = field.value()
+				field.mask(true)
+				fields = append(fields, field)
 			}
 		}
 
@@ -125,31 +317,81 @@ func NewFormModel(exampleFilePath string, fileIndex, totalFiles int, savedFiles
 			totalFiles:      totalFiles,
 			savedFiles:      savedFiles,
 			enableBackup:    enableBackup,
+			overwrite:       overwrite,
+			opts:            opts,
 		}
 	}
 }
 
-// isPlaceholderValue returns true if the value appears to be a placeholder.
-// It checks for common placeholder patterns like *** suffix, "your_*" prefix,
-// and words like "placeholder" or "example" in the value.
-func isPlaceholderValue(value string) bool {
-	if strings.HasSuffix(value, "***") {
-		return true
+func newFieldInput(placeholder string) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.Width = defaultInputWidth
+	return input
+}
+
+// defaultInputWidth is the width of a field's input until the terminal
+// width is known.
+const defaultInputWidth = 50
+
+// SetWindowWidth sets the terminal width, which long values scroll within
+// and labels are cut to.
+func (m *FormModel) SetWindowWidth(w int) {
+	m.windowWidth = w
+	width := defaultInputWidth
+	if w > 0 {
+		// Leave room for the "> " prompt and the cursor.
+		width = max(w-4, 10)
 	}
+	for i := range m.fields {
+		m.fields[i].Input.Width = width
+	}
+}
 
-	lower := strings.ToLower(value)
-	placeholderPatterns := []string{"your_", "_here", "placeholder"}
-	for _, pattern := range placeholderPatterns {
-		if strings.Contains(lower, pattern) {
-			return true
+// formRules returns the validation rule for each key: the fields of the
+// schema set for the example's directory, by default the .env.schema next
+// to it, or, if there is none, the types inferred
+// from the example's concrete values. Inferred rules never require a value,
+// but the example does for the keys it gives no default and does not mark
+// "# optional", unless the schema lists them.
+func (o Options) formRules(examplePath string, entries []parser.Entry) map[string]schema.Field {
+	rules := make(map[string]schema.Field)
+	fromSchema := false
+	if f, err := os.Open(o.schemaPath(examplePath)); err == nil {
+		defer func() { _ = f.Close() }()
+		if fields, err := schema.Parse(f); err == nil {
+			for _, field := range fields {
+				rules[field.Key] = field
+			}
+			fromSchema = true
 		}
 	}
-
-	if strings.Contains(lower, "example") && !strings.Contains(lower, "://") {
-		return true
+	if !fromSchema {
+		for _, field := range schema.Infer(entries) {
+			if field.Type == schema.String {
+				continue
+			}
+			field.Required = false
+			rules[field.Key] = field
+		}
 	}
+	for key := range schema.Required(entries) {
+		field, ok := rules[key]
+		if ok && fromSchema {
+			continue
+		}
+		if !ok {
+			field = schema.Field{Key: key, Type: schema.String}
+		}
+		field.Required = true
+		rules[key] = field
+	}
+	return rules
+}
 
-	return false
+// isPlaceholderValue returns true if the value appears to be a placeholder.
+func isPlaceholderValue(value string) bool {
+	return detector.IsPlaceholder(value)
 }
 
 // generateHint creates a context-aware placeholder hint for a given key.
@@ -199,7 +441,9 @@ const (
 // moveCursor moves the cursor to a new position and updates the scroll offset
 // to keep the cursor visible within the visible fields window.
 func (m *FormModel) moveCursor(newCursor int) {
+	m.coalesceEdits = false
 	m.fields[m.cursor].Input.Blur()
+	m.fields[m.cursor].mask(true)
 	m.cursor = newCursor
 	m.fields[m.cursor].Input.Focus()
 
@@ -219,10 +463,86 @@ func (m *FormModel) moveCursorByDirection(dir int) {
 	}
 }
 
+// recordEdit pushes a field change onto the undo stack. Consecutive edits to
+// the same field are merged so undo reverts a whole burst of typing.
+func (m *FormModel) recordEdit(edit fieldEdit) {
+	m.redoStack = nil
+	if n := len(m.undoStack); m.coalesceEdits && n > 0 && m.undoStack[n-1].field == edit.field {
+		m.undoStack[n-1].after = edit.after
+		m.undoStack[n-1].afterSource = edit.afterSource
+		return
+	}
+	m.undoStack = append(m.undoStack, edit)
+	m.coalesceEdits = true
+}
+
+// cycleSource switches the focused field to its next value source: the
+// current .env value (if it has one), the example value, or a new value
+// typed by the user, which starts empty.
+func (m *FormModel) cycleSource() {
+	f := &m.fields[m.cursor]
+	order := []valueSource{sourceExample, sourceNew}
+	if f.HasCurrent {
+		order = []valueSource{sourceCurrent, sourceExample, sourceNew}
+	}
+	next := order[0]
+	for i, s := range order {
+		if s == f.source {
+			next = order[(i+1)%len(order)]
+		}
+	}
+
+	var value string
+	switch next {
+	case sourceCurrent:
+		value = f.Current
+	case sourceExample:
+		value = f.Value
+	}
+	edit := fieldEdit{field: m.cursor, before: f.value(), after: value, beforeSource: f.source, afterSource: next}
+	f.setValue(value)
+	f.source = next
+	// Values from the .env or the example were not typed, so there is
+	// nothing to mistype.
+	f.confirmed = value
+	m.coalesceEdits = false
+	m.recordEdit(edit)
+	m.coalesceEdits = false
+}
+
+// undo reverts the most recent field edit and focuses that field.
+func (m *FormModel) undo() {
+	n := len(m.undoStack)
+	if n == 0 {
+		return
+	}
+	edit := m.undoStack[n-1]
+	m.undoStack = m.undoStack[:n-1]
+	m.redoStack = append(m.redoStack, edit)
+	m.fields[edit.field].setValue(edit.before)
+	m.fields[edit.field].source = edit.beforeSource
+	m.moveCursor(edit.field)
+}
+
+// redo reapplies the most recently undone field edit.
+func (m *FormModel) redo() {
+	n := len(m.redoStack)
+	if n == 0 {
+		return
+	}
+	edit := m.redoStack[n-1]
+	m.redoStack = m.redoStack[:n-1]
+	m.undoStack = append(m.undoStack, edit)
+	m.fields[edit.field].setValue(edit.after)
+	m.fields[edit.field].source = edit.afterSource
+	m.moveCursor(edit.field)
+}
+
 // Update handles messages and updates the form model.
 func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case formInitMsg:
+		m.opts = msg.opts
 		m.fields = msg.fields
 		m.originalEntries = msg.originalEntries
 		m.filePath = msg.filePath
@@ -234,14 +554,42 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.scroll = 0
 		m.confirmed = false
 		m.errorMsg = ""
+		m.undoStack = nil
+		m.redoStack = nil
+		m.backupPath = ""
+		m.restoreMsg = ""
+		m.overwrite = msg.overwrite
+		m.regenerating = false
+		for _, f := range m.fields {
+			if f.HasCurrent {
+				m.regenerating = true
+			}
+		}
+		m.reviewing = m.opts.FormPreview && len(m.fields) > 0
+		m.baseline = m.entries()
+		m.busy = false
+		m.discarding = false
+		m.exitAfterSave = false
+		m.confirming = false
+		m.SetWindowWidth(m.windowWidth)
 
 		if len(m.fields) > 0 {
 			m.fields[0].Input.Focus()
 		}
 		return m, nil
 
+	case tea.WindowSizeMsg:
+		m.SetWindowWidth(msg.Width)
+		return m, nil
+
 	case FormSavedMsg:
+		m.busy = false
+		if m.exitAfterSave && msg.Success {
+			return m, finishForm(true, "", 0)
+		}
+		m.exitAfterSave = false
 		m.confirmed = true
+		m.backupPath = msg.BackupPath
 		if msg.Success {
 			m.errorMsg = ""
 		} else {
@@ -249,81 +597,452 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case editorFinishedMsg:
+		entries, err := readEdited(msg)
+		if err != nil {
+			return m, statusCmd(err.Error(), true)
+		}
+		m.applyEdited(entries)
+		return m, statusCmd("Applied changes from editor", false)
+
+	case FormRestoredMsg:
+		m.busy = false
+		m.backupPath = ""
+		if msg.Success {
+			m.restoreMsg = "Restored previous " + m.outputPath() + " from backup"
+		} else {
+			m.restoreMsg = "Restore failed: " + msg.Error
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		if m.confirmed {
-			switch msg.String() {
-			case "tab":
+			switch {
+			case key.Matches(msg, m.opts.Keys.Form.Next):
 				if m.totalFiles > 1 {
-					return m, func() tea.Msg {
-						return FormFinishedMsg{Success: m.errorMsg == "", Error: m.errorMsg, Dir: 1}
-					}
+					return m, finishForm(m.errorMsg == "", m.errorMsg, 1)
 				}
-			case "enter", "q", "esc":
-				return m, func() tea.Msg {
-					return FormFinishedMsg{Success: m.errorMsg == "", Error: m.errorMsg, Dir: 0}
+			case key.Matches(msg, m.opts.Keys.Form.Restore):
+				if m.backupPath != "" && m.errorMsg == "" {
+					m.busy = true
+					return m, m.restoreBackup()
 				}
+			case key.Matches(msg, m.opts.Keys.Form.Done):
+				return m, finishForm(m.errorMsg == "", m.errorMsg, 0)
 			}
 			return m, nil
 		}
 
-		switch msg.String() {
-		case "up", "shift+tab":
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		if m.discarding {
+			m.discarding = false
+			switch msg.String() {
+			case "y", "Y":
+				return m, finishForm(false, "cancelled", 0)
+			case "s", "S":
+				m.exitAfterSave = true
+				updated, cmd := m.trySave()
+				if fm := updated.(FormModel); !fm.busy {
+					fm.exitAfterSave = false
+					return fm, cmd
+				}
+				return updated, cmd
+			}
+			return m, nil
+		}
+
+		if m.editing {
+			switch {
+			case key.Matches(msg, m.opts.Keys.Form.Multiline):
+				m.closeEditor(true)
+				return m, nil
+			case key.Matches(msg, m.opts.Keys.Form.Cancel):
+				m.closeEditor(false)
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.editor, cmd = m.editor.Update(msg)
+			return m, cmd
+		}
+
+		if m.confirming {
+			switch {
+			case key.Matches(msg, m.opts.Keys.Form.Submit):
+				return m.checkConfirm()
+			case key.Matches(msg, m.opts.Keys.Form.Cancel):
+				m.confirming = false
+				m.fields[m.cursor].Input.Focus()
+				return m, nil
+			}
+			if isPaste(msg) {
+				clean, _ := cleanPaste(string(msg.Runes))
+				msg.Runes = []rune(clean)
+			}
+			var cmd tea.Cmd
+			m.confirmInput, cmd = m.confirmInput.Update(msg)
+			return m, cmd
+		}
+
+		if m.reviewing {
+			switch {
+			case key.Matches(msg, m.opts.Keys.Form.Help):
+				m.showHelp = true
+			case key.Matches(msg, m.opts.Keys.Form.Submit):
+				m.reviewing = false
+			case key.Matches(msg, m.opts.Keys.Form.Cancel):
+				return m, finishForm(false, "cancelled", 0)
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, m.opts.Keys.Form.Help):
+			m.showHelp = true
+			return m, nil
+		case key.Matches(msg, m.opts.Keys.Form.Up, m.opts.Keys.Form.Prev):
 			m.moveCursorByDirection(directionUp)
-		case "down", "tab":
+		case key.Matches(msg, m.opts.Keys.Form.Down, m.opts.Keys.Form.Next):
 			m.moveCursorByDirection(directionDown)
-		case "enter":
+		case key.Matches(msg, m.opts.Keys.Form.Multiline):
+			if len(m.fields) > 0 {
+				return m, m.openEditor()
+			}
+			return m, nil
+		case key.Matches(msg, m.opts.Keys.Form.Editor):
+			return m, m.opts.editEntries(m.entries())
+		case key.Matches(msg, m.opts.Keys.Form.Reveal):
+			if len(m.fields) > 0 {
+				f := &m.fields[m.cursor]
+				f.mask(f.Input.EchoMode == textinput.EchoNormal)
+			}
+			return m, nil
+		case key.Matches(msg, m.opts.Keys.Form.Submit):
+			// Multiline values cannot be edited inline.
+			if len(m.fields) > 0 && m.fields[m.cursor].multiline {
+				return m, m.openEditor()
+			}
+			if len(m.fields) > 0 && m.fields[m.cursor].needsConfirm() {
+				return m, m.openConfirm()
+			}
 			if m.cursor == len(m.fields)-1 {
-				return m, m.saveForm()
+				return m.trySave()
 			}
 			m.moveCursorByDirection(directionDown)
-		case "esc":
-			return m, func() tea.Msg {
-				return FormFinishedMsg{Success: false, Error: "cancelled", Dir: 0}
+		case key.Matches(msg, m.opts.Keys.Form.Save):
+			return m.trySave()
+		case key.Matches(msg, m.opts.Keys.Form.FirstError):
+			if i := m.firstError(); i >= 0 {
+				m.moveCursor(i)
+			}
+			return m, nil
+		case key.Matches(msg, m.opts.Keys.Form.Backup):
+			m.enableBackup = !m.enableBackup
+			return m, nil
+		case key.Matches(msg, m.opts.Keys.Form.Undo):
+			m.undo()
+			return m, nil
+		case key.Matches(msg, m.opts.Keys.Form.Redo):
+			m.redo()
+			return m, nil
+		case key.Matches(msg, m.opts.Keys.Form.Source):
+			if m.regenerating && len(m.fields) > 0 {
+				m.cycleSource()
+			}
+			return m, nil
+		case key.Matches(msg, m.opts.Keys.Form.Cancel):
+			if m.Dirty() {
+				m.discarding = true
+				return m, nil
 			}
+			return m, finishForm(false, "cancelled", 0)
 		}
 	}
 
 	// Update the currently focused field
-	if len(m.fields) > 0 && m.cursor >= 0 && m.cursor < len(m.fields) {
-		updatedInput, cmd := m.fields[m.cursor].Input.Update(msg)
-		m.fields[m.cursor].Input = updatedInput
+	if len(m.fields) > 0 && m.cursor >= 0 && m.cursor < len(m.fields) && !m.fields[m.cursor].multiline {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && isPaste(keyMsg) {
+			return m, m.paste(keyMsg)
+		}
+		field := &m.fields[m.cursor]
+		before := field.Input.Value()
+		updatedInput, cmd := field.Input.Update(msg)
+		field.Input = updatedInput
+		if after := updatedInput.Value(); after != before {
+			m.recordEdit(fieldEdit{field: m.cursor, before: before, after: after, beforeSource: field.source, afterSource: sourceNew})
+			field.source = sourceNew
+		}
 		return m, cmd
 	}
 
 	return m, nil
 }
 
-// saveForm processes the form fields and writes the resulting .env file.
-// It returns a command that emits a FormSavedMsg upon completion.
-func (m FormModel) saveForm() tea.Cmd {
+// paste inserts pasted text into the focused field after cleaning it with
+// cleanPaste, and reports what was changed. Text spanning several lines
+// makes the field multiline.
+func (m *FormModel) paste(msg tea.KeyMsg) tea.Cmd {
+	field := &m.fields[m.cursor]
+	raw := string(msg.Runes)
+	clean, hadControl := cleanPaste(raw)
+	before := field.value()
+
+	var cmd tea.Cmd
+	if strings.Contains(clean, "\n") {
+		field.setValue(before + clean)
+	} else {
+		msg.Runes = []rune(clean)
+		field.Input, cmd = field.Input.Update(msg)
+	}
+	if after := field.value(); after != before {
+		m.coalesceEdits = false
+		m.recordEdit(fieldEdit{field: m.cursor, before: before, after: after, beforeSource: field.source, afterSource: sourceNew})
+		m.coalesceEdits = false
+		field.source = sourceNew
+	}
+
+	var status tea.Cmd
+	switch {
+	case hadControl:
+		status = statusCmd(fmt.Sprintf("Removed control characters from the text pasted into %s", field.Key), true)
+	case field.multiline:
+		status = statusCmd(fmt.Sprintf("Pasted %d lines into %s (%s to edit)", strings.Count(clean, "\n")+1, field.Key, m.opts.Keys.Form.Multiline.Help().Key), false)
+	case clean != raw:
+		status = statusCmd(fmt.Sprintf("Trimmed whitespace around the text pasted into %s", field.Key), false)
+	}
+	return tea.Batch(status, cmd)
+}
+
+// openEditor opens the multiline editor on the focused field's value.
+func (m *FormModel) openEditor() tea.Cmd {
+	m.editor = textarea.New()
+	m.editor.ShowLineNumbers = false
+	m.editor.SetWidth(60)
+	if m.windowWidth > 0 {
+		m.editor.SetWidth(min(60, max(m.windowWidth-2, 10)))
+	}
+	m.editor.SetHeight(8)
+	m.editor.SetValue(m.fields[m.cursor].value())
+	m.editing = true
+	return m.editor.Focus()
+}
+
+// openConfirm asks for the focused secret field's value a second time.
+func (m *FormModel) openConfirm() tea.Cmd {
+	m.confirmInput = newFieldInput("type it again")
+	m.confirmInput.Width = m.fields[m.cursor].Input.Width
+	m.confirmInput.EchoMode = textinput.EchoPassword
+	m.confirmInput.EchoCharacter = '•'
+	m.fields[m.cursor].Input.Blur()
+	m.confirming = true
+	return m.confirmInput.Focus()
+}
+
+// checkConfirm accepts the focused secret if the second entry matches it
+// and moves on as Enter would; otherwise it asks again.
+func (m FormModel) checkConfirm() (tea.Model, tea.Cmd) {
+	f := &m.fields[m.cursor]
+	if m.confirmInput.Value() != f.value() {
+		m.confirmInput.SetValue("")
+		return m, statusCmd(fmt.Sprintf("%s: the values do not match, type it again (Esc to go back)", f.Key), true)
+	}
+	f.confirmed = f.value()
+	m.confirming = false
+	f.Input.Focus()
+	if m.cursor == len(m.fields)-1 {
+		return m.trySave()
+	}
+	m.moveCursorByDirection(directionDown)
+	return m, nil
+}
+
+// closeEditor closes the multiline editor, keeping its text as the focused
+// field's value if apply is set.
+func (m *FormModel) closeEditor(apply bool) {
+	m.editing = false
+	m.editor.Blur()
+	f := &m.fields[m.cursor]
+	if after := m.editor.Value(); apply && after != f.value() {
+		m.coalesceEdits = false
+		m.recordEdit(fieldEdit{field: m.cursor, before: f.value(), after: after, beforeSource: f.source, afterSource: sourceNew})
+		m.coalesceEdits = false
+		f.setValue(after)
+		f.source = sourceNew
+		// The editor shows the value, so it needs no second entry.
+		f.confirmed = after
+	}
+}
+
+// firstError returns the index of the first field with an invalid value, or
+// -1.
+func (m FormModel) firstError() int {
+	for i, f := range m.fields {
+		if f.validate() != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// trySave saves the form unless a field is invalid, in which case it stays
+// on the form and says how to find the problem.
+func (m FormModel) trySave() (tea.Model, tea.Cmd) {
+	invalid := 0
+	for _, f := range m.fields {
+		if f.validate() != nil {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return m, statusCmd(fmt.Sprintf("Cannot save: %d field(s) have errors (%s: go to first error)", invalid, m.opts.Keys.Form.FirstError.Help().Key), true)
+	}
+	m.busy = true
+	return m, m.saveForm()
+}
+
+// viewDiscard renders the prompt shown when leaving the form with unsaved
+// edits.
+func (m FormModel) viewDiscard() string {
+	changed := 0
+	for i, e := range m.entries() {
+		if i < len(m.baseline) && !reflect.DeepEqual(e, m.baseline[i]) {
+			changed++
+		}
+	}
+	title := lipgloss.NewStyle().Foreground(m.opts.Theme.Warning).Bold(true).Render("Discard changes? y/N")
+	body := lipgloss.NewStyle().Foreground(m.opts.Theme.Text).
+		Render(fmt.Sprintf("%d value(s) edited in %s have not been saved.", changed, m.outputPath()))
+	help := lipgloss.NewStyle().Faint(true).Render("y: discard and leave • s: save and exit • any other key: keep editing")
+	return "\n" + lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.opts.Theme.Warning).
+		Padding(1, 2).
+		Render(title+"\n\n"+body+"\n\n"+help) + "\n"
+}
+
+// finishForm returns a command that leaves the form, moving dir files on.
+func finishForm(success bool, errMsg string, dir int) tea.Cmd {
 	return func() tea.Msg {
-		outputPath := filepath.Join(filepath.Dir(m.filePath), ".env")
-
-		fieldIndex := 0
-		var entries []parser.Entry
-		for _, entry := range m.originalEntries {
-			switch e := entry.(type) {
-			case parser.KeyValue:
-				if fieldIndex < len(m.fields) {
-					newValue := m.fields[fieldIndex].Input.Value()
-					entries = append(entries, parser.KeyValue{
-						Key:      e.Key,
-						Value:    newValue,
-						Quoted:   e.Quoted,
-						Exported: e.Exported,
-					})
-					fieldIndex++
+		return FormFinishedMsg{Success: success, Error: errMsg, Dir: dir}
+	}
+}
+
+// Dirty reports whether the form has edits that have not been saved.
+func (m FormModel) Dirty() bool {
+	return !m.confirmed && !reflect.DeepEqual(m.entries(), m.baseline)
+}
+
+// Busy reports whether a save or restore is being written, so quitting
+// should wait for it to finish.
+func (m FormModel) Busy() bool {
+	return m.busy
+}
+
+// entries returns the example's entries with the form's values filled in.
+func (m FormModel) entries() []parser.Entry {
+	fieldIndex := 0
+	var entries []parser.Entry
+	for _, entry := range m.originalEntries {
+		switch e := entry.(type) {
+		case parser.KeyValue:
+			if fieldIndex < len(m.fields) {
+				kv := e
+				if newValue := m.fields[fieldIndex].value(); newValue != e.Value {
+					// Typed values are meant literally, as the CLI's are.
+					kv.Value = newValue
+					kv = parser.QuoteValue(kv)
 				}
-			case parser.Comment, parser.BlankLine:
-				entries = append(entries, e)
+				entries = append(entries, kv)
+				fieldIndex++
 			}
+		case parser.Comment, parser.BlankLine:
+			entries = append(entries, e)
 		}
+	}
+	return entries
+}
+
+// applyEdited replaces the form's content with entries returned from the
+// external editor. Fields keep their rules and current values by key, and
+// keys added in the editor become new fields. The undo history is dropped
+// since it refers to the old field positions.
+func (m *FormModel) applyEdited(entries []parser.Entry) {
+	byKey := make(map[string]FormField)
+	for _, f := range m.fields {
+		if _, ok := byKey[f.Key]; !ok {
+			byKey[f.Key] = f
+		}
+	}
+
+	var fields []FormField
+	for _, e := range entries {
+		kv, ok := e.(parser.KeyValue)
+		if !ok {
+			continue
+		}
+		f, ok := byKey[kv.Key]
+		if !ok {
+			f = FormField{Key: kv.Key, Input: newFieldInput(""), source: sourceNew}
+		}
+		if f.value() != kv.Value {
+			f.setValue(kv.Value)
+			f.source = sourceNew
+			f.confirmed = kv.Value
+		}
+		f.Input.Blur()
+		fields = append(fields, f)
+	}
+
+	m.originalEntries = entries
+	m.fields = fields
+	m.undoStack, m.redoStack = nil, nil
+	m.coalesceEdits = false
+	m.cursor = min(m.cursor, max(len(fields)-1, 0))
+	m.scroll = min(m.scroll, m.cursor)
+	if len(fields) > 0 {
+		m.fields[m.cursor].Input.Focus()
+	}
+}
+
+// provenance returns the provenance note for each field: fields set in the
+// form are dated on, and fields kept from the existing .env keep its note.
+func (m FormModel) provenance(on time.Time) map[string]string {
+	notes := make(map[string]string)
+	for _, f := range m.fields {
+		switch {
+		case f.source == sourceNew:
+			notes[f.Key] = parser.SetVia("dotenv-tui form", on)
+		case f.source == sourceCurrent && f.note != "":
+			notes[f.Key] = f.note
+		}
+	}
+	return notes
+}
 
-		if m.enableBackup {
+// saveForm processes the form fields and writes the resulting .env file.
+// It returns a command that emits a FormSavedMsg upon completion. The
+// entries are built before the command runs, since the fields keep
+// changing on the event loop while it does.
+func (m FormModel) saveForm() tea.Cmd {
+	outputPath, enableBackup := m.outputPath(), m.enableBackup
+	backupOpts := m.opts.backupOptions(outputPath)
+	entries := m.entries()
+	if m.opts.Annotate {
+		entries = parser.Annotate(entries, m.provenance(m.opts.today()))
+	}
+	return func() tea.Msg {
+		before := readEntries(outputPath)
+		var backupPath string
+		if enableBackup {
 			if _, err := os.Stat(outputPath); err == nil {
-				if _, err := backup.CreateBackup(outputPath); err != nil {
+				path, err := backupOpts.Create(outputPath)
+				if err != nil {
 					return FormSavedMsg{Success: false, Error: fmt.Sprintf("Failed to create backup: %v", err)}
 				}
+				backupPath = path
 			}
 		}
 
@@ -333,11 +1052,34 @@ func (m FormModel) saveForm() tea.Cmd {
 		}
 		defer func() { _ = file.Close() }()
 
-		if err := parser.Write(file, entries); err != nil {
+		if err := parser.WriteWithOptions(file, entries, m.opts.Write); err != nil {
 			return FormSavedMsg{Success: false, Error: fmt.Sprintf("Failed to write file: %v", err)}
 		}
 
-		return FormSavedMsg{Success: true}
+		m.opts.recordWrite(history.OpGenerateEnv, outputPath, before, entries, backupPath)
+		return FormSavedMsg{Success: true, Path: outputPath, BackupPath: backupPath}
+	}
+}
+
+// outputPath returns the .env path written next to the example file.
+func (m FormModel) outputPath() string {
+	return formOutputPath(m.filePath)
+}
+
+func formOutputPath(examplePath string) string {
+	return filepath.Join(filepath.Dir(examplePath), ".env")
+}
+
+// restoreBackup rolls the just-saved .env back to the backup taken before saving.
+func (m FormModel) restoreBackup() tea.Cmd {
+	backupPath, outputPath := m.backupPath, m.outputPath()
+	return func() tea.Msg {
+		before := readEntries(outputPath)
+		if err := backup.Restore(backupPath, outputPath); err != nil {
+			return FormRestoredMsg{Success: false, Error: err.Error()}
+		}
+		m.opts.recordWrite(history.OpRestore, outputPath, before, readEntries(outputPath), backupPath)
+		return FormRestoredMsg{Success: true}
 	}
 }
 
@@ -355,15 +1097,18 @@ func (m FormModel) View() string {
 		default:
 			helpText = "Enter: done"
 		}
+		if m.backupPath != "" && m.errorMsg == "" {
+			helpText += " • " + m.opts.Keys.Form.Restore.Help().Key + ": restore previous version"
+		}
 
 		if m.errorMsg != "" {
 			title := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FF5F56")).
+				Foreground(m.opts.Theme.Error).
 				Bold(true).
 				Render("Error")
 
 			message := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FAFAFA")).
+				Foreground(m.opts.Theme.Text).
 				Render(m.errorMsg)
 
 			help := lipgloss.NewStyle().
@@ -379,14 +1124,17 @@ func (m FormModel) View() string {
 		}
 
 		title := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#5FAF5F")).
+			Foreground(m.opts.Theme.Success).
 			Bold(true).
 			Render("Success!")
 
-		outputPath := filepath.Join(filepath.Dir(m.filePath), ".env")
+		messageText := fmt.Sprintf("Successfully wrote %s", m.outputPath())
+		if m.restoreMsg != "" {
+			messageText = m.restoreMsg
+		}
 		message := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Render(fmt.Sprintf("Successfully wrote %s", outputPath))
+			Foreground(m.opts.Theme.Text).
+			Render(messageText)
 
 		help := lipgloss.NewStyle().
 			Faint(true).
@@ -400,16 +1148,31 @@ func (m FormModel) View() string {
 		)
 	}
 
+	if m.showHelp {
+		if m.reviewing {
+			return helpOverlay(formReviewKeys(m.opts.Keys), m.opts.Theme)
+		}
+		return helpOverlay(formKeys(m.opts.Keys, m.regenerating), m.opts.Theme)
+	}
+
+	if m.reviewing {
+		return m.viewReview()
+	}
+
+	if m.discarding {
+		return m.viewDiscard()
+	}
+
 	title := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7D56F4")).
+		Foreground(m.opts.Theme.Primary).
 		Bold(true).
 		Render("Edit Environment Variables")
 
 	savedCount := len(m.savedFiles)
-	positionText := fmt.Sprintf("[%d/%d] %s  (%d/%d saved)", m.fileIndex+1, m.totalFiles, m.filePath, savedCount, m.totalFiles)
+	positionText := fmt.Sprintf("[%d/%d] %s  (%d/%d saved)  backup: %s", m.fileIndex+1, m.totalFiles, m.filePath, savedCount, m.totalFiles, onOff(m.enableBackup))
 	subtitle := lipgloss.NewStyle().
 		Faint(true).
-		Render(positionText)
+		Render(fitWidth(positionText, m.windowWidth))
 
 	var form strings.Builder
 
@@ -427,22 +1190,43 @@ func (m FormModel) View() string {
 		var label string
 		if i == m.cursor {
 			label = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#7D56F4")).
+				Foreground(m.opts.Theme.Primary).
 				Bold(true).
 				Render(field.Key + ":")
 		} else {
 			label = field.Key + ":"
 		}
+		if m.regenerating {
+			label += " " + sourceBadge(field, m.opts.Theme)
+		}
+		if err := field.validate(); err != nil {
+			label += " " + lipgloss.NewStyle().Foreground(m.opts.Theme.Error).Render("✗ "+err.Error())
+		} else if warning := field.warning(); warning != "" {
+			label += " " + lipgloss.NewStyle().Foreground(m.opts.Theme.Warning).Render("⚠ "+warning)
+		} else if field.hasRule && strings.TrimSpace(field.value()) != "" {
+			label += " " + lipgloss.NewStyle().Foreground(m.opts.Theme.Success).Render("✓")
+		}
+		label = fitWidth(label, m.windowWidth)
 
 		// Input field
 		input := field.Input.View()
+		switch {
+		case m.editing && i == m.cursor:
+			input = m.editor.View() + "\n" + shortHelp([]key.Binding{m.opts.Keys.Form.Multiline, m.opts.Keys.Form.Cancel}, m.windowWidth)
+		case m.confirming && i == m.cursor:
+			input += "\n" + lipgloss.NewStyle().Faint(true).Render("  confirm:") + "\n" + m.confirmInput.View()
+		case field.multiline:
+			lines := strings.Split(field.text, "\n")
+			summary := lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf(" (%d lines, %s to edit)", len(lines), m.opts.Keys.Form.Multiline.Help().Key))
+			input = "  " + fitWidth(oneLine(field.text), m.windowWidth-2-lipgloss.Width(summary)) + summary
+		}
 
 		// Add hint text for placeholder fields if empty
 		if field.IsPlaceholder && field.Input.Value() == "" && field.Input.Placeholder != "" {
 			hint := lipgloss.NewStyle().
 				Faint(true).
 				Italic(true).
-				Render("  (" + field.Input.Placeholder + ")")
+				Render(fitWidth("  ("+field.Input.Placeholder+")", m.windowWidth))
 			form.WriteString(fmt.Sprintf("%s\n%s\n%s\n", label, input, hint))
 		} else {
 			form.WriteString(fmt.Sprintf("%s\n%s\n", label, input))
@@ -457,9 +1241,7 @@ func (m FormModel) View() string {
 		form.WriteString("\n" + scrollInfo + "\n")
 	}
 
-	help := lipgloss.NewStyle().
-		Faint(true).
-		Render("↑: up • ↓: down • Tab: next • Shift+Tab: prev • Enter: next/submit • Esc: cancel")
+	help := shortHelp(formKeys(m.opts.Keys, m.regenerating).short, m.windowWidth)
 
 	return fmt.Sprintf(
 		"\n%s\n%s\n\n%s\n\n%s\n",
@@ -469,3 +1251,105 @@ func (m FormModel) View() string {
 		help,
 	)
 }
+
+// maxReviewKeys is the most keys listed on the review screen.
+const maxReviewKeys = 12
+
+// viewReview renders the read-only summary shown before the form.
+func (m FormModel) viewReview() string {
+	title := lipgloss.NewStyle().
+		Foreground(m.opts.Theme.Primary).
+		Bold(true).
+		Render("Review .env generation")
+
+	subtitle := lipgloss.NewStyle().
+		Faint(true).
+		Render(fmt.Sprintf("[%d/%d] %s", m.fileIndex+1, m.totalFiles, m.filePath))
+
+	needInput := 0
+	for _, f := range m.fields {
+		if f.IsPlaceholder {
+			needInput++
+		}
+	}
+	concrete := len(m.fields) - needInput
+	summary := fmt.Sprintf("%d/%d keys have values (%d%% complete), %d need input",
+		concrete, len(m.fields), concrete*100/len(m.fields), needInput)
+
+	if m.regenerating {
+		kept := 0
+		for _, f := range m.fields {
+			if f.HasCurrent {
+				kept++
+			}
+		}
+		summary += fmt.Sprintf("\n%d key(s) keep their current value (%s in the form to change)", kept, m.opts.Keys.Form.Source.Help().Key)
+	}
+
+	var target string
+	if m.overwrite {
+		target = lipgloss.NewStyle().
+			Foreground(m.opts.Theme.Warning).
+			Render(fmt.Sprintf("⚠ Overwrites existing %s (backup: %s)", m.outputPath(), onOff(m.enableBackup)))
+	} else {
+		target = lipgloss.NewStyle().
+			Foreground(m.opts.Theme.Success).
+			Render("Creates " + m.outputPath())
+	}
+
+	var list strings.Builder
+	for i, f := range m.fields {
+		if i == maxReviewKeys {
+			list.WriteString(lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("  … and %d more", len(m.fields)-maxReviewKeys)) + "\n")
+			break
+		}
+		if f.IsPlaceholder {
+			list.WriteString(lipgloss.NewStyle().Foreground(m.opts.Theme.Warning).Render("  ○ "+f.Key+"  needs input") + "\n")
+		} else {
+			list.WriteString(lipgloss.NewStyle().Faint(true).Render("  ● "+f.Key+"="+oneLine(f.Value)) + "\n")
+		}
+	}
+
+	help := shortHelp(formReviewKeys(m.opts.Keys).short, m.windowWidth)
+
+	return fmt.Sprintf(
+		"\n%s\n%s\n\n%s\n%s\n\n%s\n%s\n",
+		title,
+		subtitle,
+		summary,
+		target,
+		list.String(),
+		help,
+	)
+}
+
+// sourceBadge marks where a field's value comes from, with the value in the
+// existing .env when it is not the one being kept.
+func sourceBadge(f FormField, palette theme.Theme) string {
+	var style lipgloss.Style
+	switch f.source {
+	case sourceCurrent:
+		style = lipgloss.NewStyle().Foreground(palette.Success)
+	case sourceNew:
+		style = lipgloss.NewStyle().Foreground(palette.Primary)
+	default:
+		style = lipgloss.NewStyle().Faint(true)
+	}
+	badge := style.Render("[" + f.source.String() + "]")
+	switch {
+	case !f.HasCurrent:
+		badge += lipgloss.NewStyle().Faint(true).Render(" not in .env")
+	case f.source != sourceCurrent:
+		badge += lipgloss.NewStyle().Faint(true).Render(" current: " + oneLine(f.Current))
+	}
+	return badge
+}
+
+// oneLine returns the first line of a multiline value, marking that more
+// follows.
+func oneLine(v string) string {
+	if first, _, ok := strings.Cut(v, "\n"); ok {
+		return first + " …"
+	}
+	return v
+}
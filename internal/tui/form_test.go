@@ -1,10 +1,16 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/jellydn/dotenv-tui/internal/parser"
 )
@@ -222,7 +228,7 @@ func TestGenerateHint(t *testing.T) {
 
 func TestFormModelInit(t *testing.T) {
 	// Arrange
-	model := FormModel{fields: []FormField{}, cursor: 0}
+	model := FormModel{fields: []FormField{}, cursor: 0, opts: DefaultOptions()}
 
 	// Act
 	cmd := model.Init()
@@ -241,6 +247,7 @@ func TestFormModelInitEmptyFields(t *testing.T) {
 		filePath:  "/test/.env.example",
 		confirmed: false,
 		errorMsg:  "",
+		opts:      DefaultOptions(),
 	}
 
 	// Act
@@ -299,6 +306,7 @@ func TestFormModelUpdateWithFormSavedMsg(t *testing.T) {
 				cursor:    0,
 				confirmed: tt.confirmedBefore,
 				errorMsg:  tt.errorMsgBefore,
+				opts:      DefaultOptions(),
 			}
 
 			// Act
@@ -380,6 +388,7 @@ func TestFormModelNavigationAfterConfirmation(t *testing.T) {
 				confirmed:  true,
 				totalFiles: tt.totalFiles,
 				savedFiles: make(map[int]bool),
+				opts:       DefaultOptions(),
 			}
 
 			// Act
@@ -496,6 +505,7 @@ func TestFormModelViewInitProgressDisplay(t *testing.T) {
 				totalFiles: tt.totalFiles,
 				savedFiles: tt.savedFiles,
 				confirmed:  tt.confirmed,
+				opts:       DefaultOptions(),
 			}
 			model.fields[0].Input.Focus()
 
@@ -528,6 +538,7 @@ func TestFormModelInitWithSavedFiles(t *testing.T) {
 		fileIndex:       1,
 		totalFiles:      3,
 		savedFiles:      map[int]bool{0: true, 2: true},
+		opts:            DefaultOptions(),
 	}
 	model := FormModel{}
 
@@ -574,3 +585,679 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func newUndoTestForm() FormModel {
+	m := FormModel{
+		fields: []FormField{
+			{Key: "A", Input: textinput.New()},
+			{Key: "B", Input: textinput.New()},
+		},
+		opts: DefaultOptions(),
+	}
+	m.fields[0].Input.Focus()
+	return m
+}
+
+func typeText(m FormModel, text string) FormModel {
+	for _, r := range text {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(FormModel)
+	}
+	return m
+}
+
+func pressKey(m FormModel, keyType tea.KeyType) FormModel {
+	updated, _ := m.Update(tea.KeyMsg{Type: keyType})
+	return updated.(FormModel)
+}
+
+func TestFormUndoRedo(t *testing.T) {
+	m := newUndoTestForm()
+
+	m = typeText(m, "abc")
+	m = pressKey(m, tea.KeyTab)
+	m = typeText(m, "xyz")
+
+	if len(m.undoStack) != 2 {
+		t.Fatalf("undo stack = %d entries, expected typing bursts to coalesce into 2", len(m.undoStack))
+	}
+
+	m = pressKey(m, tea.KeyCtrlZ)
+	if m.fields[1].Input.Value() != "" {
+		t.Errorf("undo should clear field B, got %q", m.fields[1].Input.Value())
+	}
+
+	m = pressKey(m, tea.KeyCtrlZ)
+	if m.fields[0].Input.Value() != "" || m.cursor != 0 {
+		t.Errorf("second undo should clear field A and focus it, got %q cursor %d", m.fields[0].Input.Value(), m.cursor)
+	}
+
+	m = pressKey(m, tea.KeyCtrlY)
+	if m.fields[0].Input.Value() != "abc" {
+		t.Errorf("redo should restore field A, got %q", m.fields[0].Input.Value())
+	}
+
+	m = typeText(m, "d")
+	if len(m.redoStack) != 0 {
+		t.Errorf("a new edit should clear the redo stack")
+	}
+}
+
+func TestFormUndoWithEmptyStack(t *testing.T) {
+	m := newUndoTestForm()
+	m = pressKey(m, tea.KeyCtrlZ)
+	m = pressKey(m, tea.KeyCtrlY)
+
+	if m.fields[0].Input.Value() != "" {
+		t.Errorf("undo/redo on empty stacks should be no-ops")
+	}
+}
+
+func TestFormRestoreAfterSave(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	envPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(examplePath, []byte("KEY=example\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(envPath, []byte("KEY=original\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	initMsg := NewFormModel(examplePath, 0, 1, map[int]bool{}, true, DefaultOptions())()
+	updated, _ := FormModel{}.Update(initMsg)
+	m := updated.(FormModel)
+
+	saved := m.saveForm()().(FormSavedMsg)
+	if !saved.Success || saved.BackupPath == "" {
+		t.Fatalf("saveForm() = %+v, expected success with backup", saved)
+	}
+	updated, _ = m.Update(saved)
+	m = updated.(FormModel)
+
+	if !strings.Contains(m.View(), "restore previous version") {
+		t.Errorf("View() should offer restoring the backup")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if cmd == nil {
+		t.Fatal("'u' should trigger a restore")
+	}
+	restored := cmd().(FormRestoredMsg)
+	if !restored.Success {
+		t.Fatalf("restore failed: %s", restored.Error)
+	}
+
+	content, err := os.ReadFile(envPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "KEY=original\n" {
+		t.Errorf(".env content = %q, expected original", content)
+	}
+
+	updated, _ = m.Update(restored)
+	m = updated.(FormModel)
+	if m.backupPath != "" || !strings.Contains(m.View(), "Restored previous") {
+		t.Errorf("View() should confirm the restore and stop offering it")
+	}
+}
+
+func TestFormBackupToggle(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	envPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(examplePath, []byte("KEY=example\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(envPath, []byte("KEY=original\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	initMsg := NewFormModel(examplePath, 0, 1, map[int]bool{}, true, DefaultOptions())()
+	updated, _ := FormModel{}.Update(initMsg)
+	updated, _ = updated.(FormModel).Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	m := updated.(FormModel)
+
+	if !strings.Contains(m.View(), "backup: off") {
+		t.Errorf("View() should show backup off after Ctrl+B")
+	}
+	saved := m.saveForm()().(FormSavedMsg)
+	if !saved.Success || saved.BackupPath != "" {
+		t.Errorf("saveForm() = %+v, expected success without a backup", saved)
+	}
+}
+
+func TestFormReview(t *testing.T) {
+	opts := DefaultOptions()
+	opts.FormPreview = true
+
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("PORT=3000\nAPI_KEY=your_api_key_here\nDEBUG=false\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, opts)())
+	m := updated.(FormModel)
+	if !m.reviewing {
+		t.Fatal("form should open on the review screen")
+	}
+	view := m.View()
+	for _, want := range []string{"2/3 keys have values (66% complete), 1 need input", "Creates " + filepath.Join(dir, ".env"), "API_KEY  needs input", "PORT=3000"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q:\n%s", want, view)
+		}
+	}
+
+	// Typing on the review screen must not edit the first field.
+	m = typeText(m, "x")
+	if m.fields[0].Input.Value() != "3000" {
+		t.Errorf("review screen edited a field: %q", m.fields[0].Input.Value())
+	}
+
+	m = pressKey(m, tea.KeyEnter)
+	if m.reviewing || !strings.Contains(m.View(), "Edit Environment Variables") {
+		t.Error("Enter should open the form")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=4000\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ = FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, true, opts)())
+	if view := updated.View(); !strings.Contains(view, "Overwrites existing") || !strings.Contains(view, "backup: on") {
+		t.Errorf("View() should warn about overwriting .env:\n%s", view)
+	}
+
+	_, cmd := updated.(FormModel).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Esc should cancel from the review screen")
+	}
+	if finished, ok := cmd().(FormFinishedMsg); !ok || finished.Success {
+		t.Errorf("Esc = %+v, want a cancelled FormFinishedMsg", finished)
+	}
+}
+
+func TestFormValueSources(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("PORT=3000\nAPI_KEY=your_api_key_here\nDEBUG=false\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=4000\nAPI_KEY=sk_live_1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+	m := updated.(FormModel)
+	if got := m.fields[0].Input.Value(); got != "4000" || m.fields[0].source != sourceCurrent {
+		t.Fatalf("PORT = %q from %v, want the current value kept", got, m.fields[0].source)
+	}
+	if got := m.fields[2].Input.Value(); got != "false" || m.fields[2].source != sourceExample {
+		t.Errorf("DEBUG = %q from %v, want the example value", got, m.fields[2].source)
+	}
+	view := m.View()
+	for _, want := range []string{"[current]", "[example] not in .env", "Ctrl+T"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q:\n%s", want, view)
+		}
+	}
+
+	// current -> example -> new -> current
+	ctrlT := tea.KeyMsg{Type: tea.KeyCtrlT}
+	wants := []struct {
+		value  string
+		source valueSource
+	}{{"3000", sourceExample}, {"", sourceNew}, {"4000", sourceCurrent}}
+	for _, want := range wants {
+		updated, _ = m.Update(ctrlT)
+		m = updated.(FormModel)
+		if got := m.fields[0]; got.Input.Value() != want.value || got.source != want.source {
+			t.Errorf("after Ctrl+T PORT = %q from %v, want %q from %v", got.Input.Value(), got.source, want.value, want.source)
+		}
+	}
+	if !strings.Contains(m.View(), "[current]") {
+		t.Errorf("View() should mark PORT as current again")
+	}
+
+	m = typeText(m, "1")
+	if m.fields[0].source != sourceNew {
+		t.Errorf("typing should mark the field as new, got %v", m.fields[0].source)
+	}
+	if !strings.Contains(m.View(), "current: 4000") {
+		t.Errorf("View() should show the current value of an edited field")
+	}
+	m = pressKey(m, tea.KeyCtrlZ)
+	if m.fields[0].Input.Value() != "4000" || m.fields[0].source != sourceCurrent {
+		t.Errorf("undo = %q from %v, want the current value back", m.fields[0].Input.Value(), m.fields[0].source)
+	}
+
+	saved := m.saveForm()().(FormSavedMsg)
+	if !saved.Success {
+		t.Fatalf("saveForm() failed: %s", saved.Error)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "PORT=4000\nAPI_KEY=sk_live_1\nDEBUG=false\n"; string(content) != want {
+		t.Errorf(".env = %q, want %q", content, want)
+	}
+}
+
+func TestFormAnnotates(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Annotate = true
+
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("PORT=3000\nAPI_KEY=your_api_key_here\nDEBUG=false\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	current := "# sourced from AWS SM\nPORT=4000\n# set via dotenv-tui form 2026-01-01\nAPI_KEY=sk_live_1\n"
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(current), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, opts)())
+	m := updated.(FormModel)
+	m.fields[2].setValue("true")
+	m.fields[2].source = sourceNew
+
+	want := "# sourced from AWS SM\nPORT=4000\n# set via dotenv-tui form 2026-01-01\nAPI_KEY=sk_live_1\n" +
+		"# set via dotenv-tui form " + time.Now().Format(time.DateOnly) + "\nDEBUG=true\n"
+	// Saving again from the written .env keeps the notes instead of adding more.
+	for range 2 {
+		if saved := m.saveForm()().(FormSavedMsg); !saved.Success {
+			t.Fatalf("saveForm() failed: %s", saved.Error)
+		}
+		content, err := os.ReadFile(filepath.Join(dir, ".env"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != want {
+			t.Errorf(".env = %q, want %q", content, want)
+		}
+		updated, _ = FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, opts)())
+		m = updated.(FormModel)
+	}
+}
+
+func TestFormQuotesTypedValues(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("PORT=3000 # dev port\nCOLOR=\nNAME=\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+	m := updated.(FormModel)
+	m.fields[1].setValue("red # not blue")
+	m.fields[2].setValue(`"quoted"`)
+
+	var lines []string
+	for _, entry := range m.entries() {
+		lines = append(lines, parser.EntryToString(entry))
+	}
+	want := []string{"PORT=3000 # dev port", `COLOR="red # not blue"`, `NAME='"quoted"'`}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("entries() = %q, want typed values quoted as parser.QuoteValue quotes them and the rest kept", lines)
+	}
+}
+
+func TestFormValidation(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("DATABASE_URL=your_database_url_here\nPORT=3000\nDEBUG=false\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env.schema"), []byte("DATABASE_URL=url,required\nPORT=port\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+	m := updated.(FormModel)
+	if m.fields[2].hasRule {
+		t.Errorf("DEBUG should not be validated when .env.schema does not list it")
+	}
+	view := m.View()
+	if !strings.Contains(view, "✗ required") || !strings.Contains(view, "✓") {
+		t.Errorf("View() should mark the empty required URL and the valid port:\n%s", view)
+	}
+
+	// Break the port too, then try to save from the last field.
+	m = pressKey(m, tea.KeyDown)
+	m = pressKey(m, tea.KeyBackspace)
+	m = typeText(m, "0000")
+	if !strings.Contains(m.View(), "✗ not a port") {
+		t.Errorf("View() should flag the invalid port:\n%s", m.View())
+	}
+	m = pressKey(m, tea.KeyDown)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(FormModel)
+	if status := statusOf(t, cmd); !status.Error || !strings.Contains(status.Text, "Cannot save: 2 field(s) have errors") {
+		t.Errorf("Enter should not save while fields are invalid, status = %+v", status)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS}); !statusOf(t, cmd).Error {
+		t.Error("Ctrl+S should not save while fields are invalid")
+	}
+
+	m = pressKey(m, tea.KeyCtrlG)
+	if m.cursor != 0 {
+		t.Fatalf("Ctrl+G moved to field %d, want 0", m.cursor)
+	}
+	m = typeText(m, "postgres://localhost/app")
+	m = pressKey(m, tea.KeyCtrlG)
+	if m.cursor != 1 {
+		t.Fatalf("Ctrl+G moved to field %d, want 1", m.cursor)
+	}
+	for range "0000" {
+		m = pressKey(m, tea.KeyBackspace)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS}); cmd == nil {
+		t.Error("Ctrl+S should save once every field is valid")
+	}
+}
+
+func TestFormTokenShapeWarning(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("STRIPE_KEY=sk_live_4eC3\nSTRIPE_PUBLIC=pk_live_4eC39HqLyjWDarjtT1zdp7dc\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+	m := updated.(FormModel)
+	view := m.View()
+	if !strings.Contains(view, "⚠ value is 12 characters, but Stripe secret keys are at least 32") {
+		t.Errorf("View() should warn about the truncated key:\n%s", view)
+	}
+	if strings.Count(view, "⚠") != 1 {
+		t.Errorf("View() should only warn about STRIPE_KEY:\n%s", view)
+	}
+	if m.firstError() != -1 {
+		t.Error("a token shape warning should not block saving")
+	}
+}
+
+func TestFormRulesInferredFromExample(t *testing.T) {
+	entries, err := parser.Parse(strings.NewReader("PORT=3000\nAPI_KEY=your_api_key_here\nDEBUG=false\n# optional\nSENTRY_DSN=\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := DefaultOptions().formRules(filepath.Join(t.TempDir(), ".env.example"), entries)
+	if len(rules) != 3 || rules["PORT"].Required || rules["DEBUG"].Type != "bool" {
+		t.Errorf("formRules() = %+v, want optional PORT and DEBUG rules", rules)
+	}
+	if !rules["API_KEY"].Required {
+		t.Errorf("formRules() = %+v, want API_KEY required, having no default", rules)
+	}
+	if _, ok := rules["SENTRY_DSN"]; ok {
+		t.Errorf("formRules() = %+v, want no rule for the optional SENTRY_DSN", rules)
+	}
+}
+
+func TestFormBlocksEmptyRequiredKeys(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("PORT=3000\nSERVICE_NAME=\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+	m := updated.(FormModel)
+	if m.firstError() != 1 {
+		t.Fatalf("firstError() = %d, want the empty SERVICE_NAME to block saving", m.firstError())
+	}
+	if !strings.Contains(m.View(), "✗ required") {
+		t.Errorf("View() should mark SERVICE_NAME as required:\n%s", m.View())
+	}
+
+	m = pressKey(m, tea.KeyDown)
+	m = typeText(m, "orders")
+	if m.firstError() != -1 {
+		t.Errorf("firstError() = %d, want no errors once SERVICE_NAME is filled in", m.firstError())
+	}
+}
+
+func TestFormMultilineEditor(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("CERT=\"-----BEGIN-----\nabc\n-----END-----\"\nCONFIG=\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+	m := updated.(FormModel)
+	if !m.fields[0].multiline {
+		t.Fatal("CERT should be edited as a multiline value")
+	}
+	if view := m.View(); !strings.Contains(view, "-----BEGIN----- …") || !strings.Contains(view, "3 lines") {
+		t.Errorf("View() should summarize the multiline value:\n%s", view)
+	}
+
+	// Typing cannot touch a multiline value; Enter opens the editor.
+	m = typeText(m, "x")
+	if m.fields[0].value() != "-----BEGIN-----\nabc\n-----END-----" {
+		t.Fatalf("typing changed the multiline value to %q", m.fields[0].value())
+	}
+	m = pressKey(m, tea.KeyEnter)
+	if !m.editing {
+		t.Fatal("Enter should open the editor on a multiline value")
+	}
+	m = pressKey(m, tea.KeyEsc)
+	if m.editing || m.fields[0].value() != "-----BEGIN-----\nabc\n-----END-----" {
+		t.Fatal("Esc should close the editor without changes")
+	}
+
+	// Ctrl+O turns a single-line field into a multiline one.
+	m = pressKey(m, tea.KeyDown)
+	m = pressKey(m, tea.KeyCtrlO)
+	if !m.editing {
+		t.Fatal("Ctrl+O should open the editor")
+	}
+	m = typeText(m, "{")
+	m = pressKey(m, tea.KeyEnter)
+	m = typeText(m, "}")
+	m = pressKey(m, tea.KeyCtrlO)
+	if m.editing || m.fields[1].value() != "{\n}" || !m.fields[1].multiline {
+		t.Fatalf("Ctrl+O should apply the edit, got %q", m.fields[1].value())
+	}
+
+	m = pressKey(m, tea.KeyCtrlZ)
+	if m.fields[1].value() != "" || m.fields[1].multiline {
+		t.Errorf("undo = %q, want the empty value back", m.fields[1].value())
+	}
+	m = pressKey(m, tea.KeyCtrlY)
+
+	saved := m.saveForm()().(FormSavedMsg)
+	if !saved.Success {
+		t.Fatalf("saveForm() failed: %s", saved.Error)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "CERT=\"-----BEGIN-----\nabc\n-----END-----\"\nCONFIG=\"{\n}\"\n"; string(content) != want {
+		t.Errorf(".env = %q, want %q", content, want)
+	}
+}
+
+func TestFormDiscardGuard(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("APP_NAME=your_app_name_here\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	load := func() FormModel {
+		updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+		return updated.(FormModel)
+	}
+	finished := func(cmd tea.Cmd) (FormFinishedMsg, bool) {
+		if cmd == nil {
+			return FormFinishedMsg{}, false
+		}
+		msg, ok := cmd().(FormFinishedMsg)
+		return msg, ok
+	}
+
+	// Leaving an untouched form does not ask.
+	if _, cmd := load().Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd == nil {
+		t.Fatal("Esc without edits should leave the form")
+	} else if _, ok := finished(cmd); !ok {
+		t.Fatal("Esc without edits should leave the form")
+	}
+
+	m := typeText(load(), "secret")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(FormModel)
+	if _, ok := finished(cmd); ok || !strings.Contains(m.View(), "Discard changes? y/N") {
+		t.Fatalf("Esc with edits should ask first:\n%s", m.View())
+	}
+	m = typeText(m, "n")
+	if m.discarding || !strings.Contains(m.View(), "secret") || strings.Contains(m.View(), "secretn") {
+		t.Fatalf("n should return to the form without typing:\n%s", m.View())
+	}
+
+	m = pressKey(m, tea.KeyEsc)
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil {
+		t.Fatal("y should discard and leave")
+	} else if msg, ok := finished(cmd); !ok || msg.Success {
+		t.Errorf("y should leave without saving, got %+v", msg)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".env")); !os.IsNotExist(err) {
+		t.Error("discarding should not write .env")
+	}
+
+	// m is still at the prompt, since the y result was dropped; save and
+	// exit writes the file, then leaves.
+	updated, save := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(FormModel)
+	if save == nil {
+		t.Fatal("s should save")
+	}
+	_, cmd = m.Update(save())
+	if msg, ok := finished(cmd); !ok || !msg.Success {
+		t.Fatalf("s should leave once saved, got %+v", msg)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil || !strings.Contains(string(data), "APP_NAME=secret") {
+		t.Errorf(".env = %q, %v", data, err)
+	}
+}
+
+func TestFormFitsWindowWidth(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("A_VERY_LONG_KEY_NAME_FOR_A_NARROW_TERMINAL=your_value_here\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The width set before the file loads applies to its fields.
+	var m FormModel
+	m.SetWindowWidth(30)
+	updated, _ := m.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+	m = updated.(FormModel)
+	if got := m.fields[0].Input.Width; got != 26 {
+		t.Errorf("input width = %d, want 26", got)
+	}
+
+	m = typeText(m, strings.Repeat("v", 80))
+	for _, line := range strings.Split(m.View(), "\n") {
+		if w := lipgloss.Width(line); w > 30 {
+			t.Errorf("line %q is %d columns wide in a 30-column terminal", line, w)
+		}
+	}
+
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	if got := updated.(FormModel).fields[0].Input.Width; got != 96 {
+		t.Errorf("input width after resize = %d, want 96", got)
+	}
+}
+
+func TestFormSecretFields(t *testing.T) {
+	dir := t.TempDir()
+	examplePath := filepath.Join(dir, ".env.example")
+	if err := os.WriteFile(examplePath, []byte("API_TOKEN=your_api_token_here\nPORT=3000\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := FormModel{}.Update(NewFormModel(examplePath, 0, 1, map[int]bool{}, false, DefaultOptions())())
+	m := updated.(FormModel)
+	if !m.fields[0].secret || m.fields[1].secret {
+		t.Fatalf("only API_TOKEN should be a secret")
+	}
+
+	m = typeText(m, "tok_123")
+	if view := m.View(); strings.Contains(view, "tok_123") || !strings.Contains(view, "✗ not confirmed") {
+		t.Fatalf("a typed secret should be masked and unconfirmed:\n%s", view)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS}); !statusOf(t, cmd).Error {
+		t.Error("Ctrl+S should not save an unconfirmed secret")
+	}
+
+	m = pressKey(m, tea.KeyCtrlR)
+	if !strings.Contains(m.View(), "tok_123") {
+		t.Errorf("Ctrl+R should reveal the secret:\n%s", m.View())
+	}
+
+	// A mismatched second entry is rejected and asked for again.
+	m = pressKey(m, tea.KeyEnter)
+	if !m.confirming {
+		t.Fatal("Enter on an unconfirmed secret should ask for it again")
+	}
+	m = typeText(m, "tok_12")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(FormModel)
+	if status := statusOf(t, cmd); !status.Error || !m.confirming || m.confirmInput.Value() != "" {
+		t.Fatalf("a mismatch should ask again, status = %+v", status)
+	}
+
+	m = typeText(m, "tok_123")
+	m = pressKey(m, tea.KeyEnter)
+	if m.confirming || m.cursor != 1 || m.fields[0].validate() != nil {
+		t.Fatalf("a matching entry should confirm the secret and move on (cursor %d)", m.cursor)
+	}
+	if strings.Contains(m.View(), "tok_123") {
+		t.Error("leaving a revealed secret should mask it again")
+	}
+
+	// Editing a confirmed secret needs confirming again.
+	m = pressKey(m, tea.KeyUp)
+	m = typeText(m, "4")
+	if !m.fields[0].needsConfirm() {
+		t.Error("changing a confirmed secret should need confirming again")
+	}
+}
+
+func TestFormPasteIsCleaned(t *testing.T) {
+	paste := func(m FormModel, text string) (FormModel, StatusMsg) {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true})
+		return updated.(FormModel), statusOf(t, cmd)
+	}
+
+	m, status := paste(newUndoTestForm(), "  sk_live_123\n")
+	if got := m.fields[0].value(); got != "sk_live_123" {
+		t.Errorf("value = %q, want the trimmed token", got)
+	}
+	if status.Error || !strings.Contains(status.Text, "Trimmed whitespace") {
+		t.Errorf("status = %+v, want a note about trimming", status)
+	}
+	m = pressKey(m, tea.KeyCtrlZ)
+	if got := m.fields[0].value(); got != "" {
+		t.Errorf("undo should remove the whole paste, value = %q", got)
+	}
+
+	m, status = paste(m, "tok\x07en")
+	if got := m.fields[0].value(); got != "token" || !status.Error {
+		t.Errorf("value = %q, status = %+v; want control characters removed with a warning", got, status)
+	}
+
+	m = pressKey(m, tea.KeyDown)
+	m, status = paste(m, "-----BEGIN KEY-----\nabc\n-----END KEY-----\n")
+	if !m.fields[1].multiline || m.fields[1].value() != "-----BEGIN KEY-----\nabc\n-----END KEY-----" {
+		t.Errorf("a multi-line paste should become a multiline value, got %q", m.fields[1].value())
+	}
+	if !strings.Contains(status.Text, "Pasted 3 lines into B") {
+		t.Errorf("status = %+v", status)
+	}
+}
@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"os"
+
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+)
+
+// readEntries returns the entries of the env file at path, or nil if it
+// does not exist or cannot be parsed.
+func readEntries(path string) []parser.Entry {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = file.Close() }()
+	entries, err := parser.Parse(file)
+	if err != nil {
+		return nil
+	}
+	return entries
+}
+
+// recordWrite logs a write made from the TUI. A failure to log is ignored:
+// the write itself succeeded and the screens have nowhere to warn about it.
+func (o Options) recordWrite(op, path string, before, after []parser.Entry, backupPath string) {
+	_ = history.Append(o.History, history.Record{
+		Source:    history.SourceTUI,
+		Operation: op,
+		Path:      path,
+		Changes:   history.Summarize(before, after),
+		Backup:    backupPath,
+	})
+}
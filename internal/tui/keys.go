@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jellydn/dotenv-tui/internal/keymap"
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+// screenKeys describes the keybindings of a single screen. The short list is
+// rendered as the one-line footer, while full groups every binding for the
+// help overlay.
+type screenKeys struct {
+	title string
+	short []key.Binding
+	full  [][]key.Binding
+}
+
+func menuKeys(km keymap.KeyMap) screenKeys {
+	k := km.Menu
+	return screenKeys{
+		title: "Menu",
+		short: []key.Binding{k.Up, k.Down, k.Backup, k.Settings, k.Select, km.Help, k.Quit},
+		full: [][]key.Binding{
+			{k.Up, k.Down, k.Select},
+			{k.Backup, k.Settings, km.Help, k.Quit},
+		},
+	}
+}
+
+func pickerKeys(km keymap.KeyMap, prompting bool) screenKeys {
+	k := km.Picker
+	if prompting {
+		return screenKeys{
+			title: "File picker",
+			short: []key.Binding{k.Confirm, k.Cancel},
+			full:  [][]key.Binding{{k.Confirm, k.Cancel}},
+		}
+	}
+	return screenKeys{
+		title: "File picker",
+		short: []key.Binding{k.Up, k.Down, k.Collapse, k.Expand, k.Toggle, k.All, k.Pattern, k.Confirm, km.Help, k.Back},
+		full: [][]key.Binding{
+			{k.Up, k.Down, k.Collapse, k.Expand},
+			{k.Toggle, k.All, k.Pattern},
+			{k.Confirm, k.Back, km.Help, k.Quit},
+		},
+	}
+}
+
+func previewKeys(km keymap.KeyMap, multiFile bool) screenKeys {
+	k := km.Preview
+	k.NextFile.SetEnabled(multiFile)
+	k.PrevFile.SetEnabled(multiFile)
+	return screenKeys{
+		title: "Preview",
+		short: []key.Binding{k.Up, k.Down, k.NextFile, k.PrevFile, k.Backup, k.Editor, k.Write, km.Help, k.Cancel},
+		full: [][]key.Binding{
+			{k.Up, k.Down, k.Left, k.Right, k.NextFile, k.PrevFile},
+			{k.Write, k.Backup, k.Editor, k.Cancel, km.Help},
+		},
+	}
+}
+
+func formKeys(km keymap.KeyMap, regenerating bool) screenKeys {
+	k := km.Form
+	k.Source.SetEnabled(regenerating)
+	return screenKeys{
+		title: "Form",
+		short: []key.Binding{k.Up, k.Down, k.Next, k.Prev, k.Submit, k.Source, k.Help, k.Cancel},
+		full: [][]key.Binding{
+			{k.Up, k.Down, k.Next, k.Prev},
+			{k.Submit, k.Save, k.Undo, k.Redo, k.Source, k.FirstError, k.Multiline, k.Editor, k.Reveal},
+			{k.Backup, k.Cancel, k.Restore, k.Help},
+		},
+	}
+}
+
+// formReviewKeys describes the summary shown before a form, where Enter
+// starts editing rather than moving to the next field.
+func formReviewKeys(km keymap.KeyMap) screenKeys {
+	k := km.Form
+	k.Submit.SetHelp(k.Submit.Help().Key, "edit values")
+	return screenKeys{
+		title: "Review",
+		short: []key.Binding{k.Submit, k.Help, k.Cancel},
+		full:  [][]key.Binding{{k.Submit, k.Cancel, k.Help}},
+	}
+}
+
+func compareKeys(km keymap.KeyMap, prompting bool) screenKeys {
+	k := km.Compare
+	if prompting {
+		return screenKeys{
+			title: "Compare",
+			short: []key.Binding{k.Confirm, k.Cancel},
+			full:  [][]key.Binding{{k.Confirm, k.Cancel}},
+		}
+	}
+	return screenKeys{
+		title: "Compare",
+		short: []key.Binding{k.Up, k.Down, k.NextDiff, k.Filter, k.Reveal, k.Export, km.Help, k.Back},
+		full: [][]key.Binding{
+			{k.Up, k.Down, k.NextDiff, k.PrevDiff},
+			{k.Filter, k.Reveal, k.Export},
+			{km.Help, k.Back},
+		},
+	}
+}
+
+func moveKeys(km keymap.KeyMap, prompting bool) screenKeys {
+	k := km.Move
+	if prompting {
+		return screenKeys{
+			title: "Move keys",
+			short: []key.Binding{k.Confirm, k.Cancel},
+			full:  [][]key.Binding{{k.Confirm, k.Cancel}},
+		}
+	}
+	return screenKeys{
+		title: "Move keys",
+		short: []key.Binding{k.Up, k.Down, k.Toggle, k.All, k.Copy, k.Next, km.Help, k.Back},
+		full: [][]key.Binding{
+			{k.Up, k.Down, k.Toggle, k.All},
+			{k.Copy, k.Next},
+			{km.Help, k.Back},
+		},
+	}
+}
+
+func settingsKeys(km keymap.KeyMap, editing bool) screenKeys {
+	k := km.Settings
+	if editing {
+		return screenKeys{
+			title: "Settings",
+			short: []key.Binding{k.Confirm, k.Cancel},
+			full:  [][]key.Binding{{k.Confirm, k.Cancel}},
+		}
+	}
+	return screenKeys{
+		title: "Settings",
+		short: []key.Binding{k.Up, k.Down, k.Change, k.Prev, km.Help, k.Back},
+		full: [][]key.Binding{
+			{k.Up, k.Down, k.Change, k.Prev},
+			{k.Confirm, k.Cancel},
+			{km.Help, k.Back},
+		},
+	}
+}
+
+// shortHelp renders enabled bindings as "key: desc • key: desc", wrapped
+// onto more lines where they would not fit in width.
+func shortHelp(bindings []key.Binding, width int) string {
+	var lines []string
+	line := ""
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		h := b.Help()
+		part := h.Key + ": " + h.Desc
+		switch {
+		case line == "":
+			line = part
+		case width > 0 && lipgloss.Width(line+" • "+part) > width:
+			lines = append(lines, line)
+			line = part
+		default:
+			line += " • " + part
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	style := lipgloss.NewStyle().Faint(true)
+	for i := range lines {
+		lines[i] = style.Render(lines[i])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// helpOverlay renders a bordered box listing every enabled binding of a
+// screen, as bubbles/help does; keys that do nothing on the screen as it
+// is are left out.
+func helpOverlay(sk screenKeys, palette theme.Theme) string {
+	keyStyle := lipgloss.NewStyle().Foreground(palette.Primary).Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(palette.Text)
+
+	var groups [][]key.Binding
+	keyWidth := 0
+	for _, group := range sk.full {
+		var enabled []key.Binding
+		for _, b := range group {
+			if !b.Enabled() {
+				continue
+			}
+			enabled = append(enabled, b)
+			if w := lipgloss.Width(b.Help().Key); w > keyWidth {
+				keyWidth = w
+			}
+		}
+		if len(enabled) > 0 {
+			groups = append(groups, enabled)
+		}
+	}
+
+	var body strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		for _, b := range group {
+			h := b.Help()
+			padded := h.Key + strings.Repeat(" ", keyWidth-lipgloss.Width(h.Key))
+			body.WriteString(keyStyle.Render(padded) + "  " + descStyle.Render(h.Desc) + "\n")
+		}
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Render("Keybindings — " + sk.title)
+	footer := lipgloss.NewStyle().Faint(true).Render("Press any key to close")
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1, 2).
+		Render(title + "\n\n" + body.String() + "\n" + footer)
+
+	return "\n" + box + "\n"
+}
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jellydn/dotenv-tui/internal/keymap"
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+func TestShortHelp(t *testing.T) {
+	disabled := key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "hidden"))
+	disabled.SetEnabled(false)
+	keys := keymap.Default()
+
+	result := shortHelp([]key.Binding{keys.Menu.Up, disabled, keys.Menu.Quit}, 0)
+
+	if !strings.Contains(result, "↑/k: up • q: quit") {
+		t.Errorf("shortHelp() = %q, expected joined bindings", result)
+	}
+	if strings.Contains(result, "hidden") {
+		t.Errorf("shortHelp() = %q, should skip disabled bindings", result)
+	}
+}
+
+func TestShortHelpWraps(t *testing.T) {
+	keys := keymap.Default()
+	bindings := []key.Binding{keys.Menu.Up, keys.Menu.Down, keys.Menu.Select, keys.Menu.Quit}
+
+	if got := shortHelp(bindings, 0); strings.Contains(got, "\n") {
+		t.Errorf("shortHelp() without a width should stay on one line, got %q", got)
+	}
+	got := shortHelp(bindings, 24)
+	lines := strings.Split(got, "\n")
+	if len(lines) < 2 {
+		t.Fatalf("shortHelp() should wrap at 24 columns, got %q", got)
+	}
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > 24 {
+			t.Errorf("line %q is %d columns wide, want at most 24", line, w)
+		}
+	}
+}
+
+func TestHelpOverlayListsAllBindings(t *testing.T) {
+	km := keymap.Default()
+	for _, sk := range []screenKeys{menuKeys(km), pickerKeys(km, false), pickerKeys(km, true), previewKeys(km, true), formKeys(km, true), formReviewKeys(km), compareKeys(km, false), compareKeys(km, true), moveKeys(km, false), moveKeys(km, true)} {
+		t.Run(sk.title, func(t *testing.T) {
+			view := helpOverlay(sk, theme.Default())
+
+			if !strings.Contains(view, sk.title) {
+				t.Errorf("helpOverlay() missing title %q", sk.title)
+			}
+			for _, group := range sk.full {
+				for _, b := range group {
+					if !strings.Contains(view, b.Help().Desc) {
+						t.Errorf("helpOverlay() missing binding %q", b.Help().Desc)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHelpOverlaySkipsDisabledBindings(t *testing.T) {
+	km := keymap.Default()
+	tests := []struct {
+		sk     screenKeys
+		hidden []string
+	}{
+		{previewKeys(km, false), []string{"next file", "prev file"}},
+		{formKeys(km, false), []string{"keep current/use example/enter new"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sk.title, func(t *testing.T) {
+			view := helpOverlay(tt.sk, theme.Default())
+			for _, desc := range tt.hidden {
+				if strings.Contains(view, desc) {
+					t.Errorf("helpOverlay() lists the disabled binding %q", desc)
+				}
+			}
+		})
+	}
+}
+
+func TestHelpOverlayToggle(t *testing.T) {
+	question := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}}
+	f1 := tea.KeyMsg{Type: tea.KeyF1}
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+
+	tests := []struct {
+		name    string
+		model   tea.Model
+		open    tea.KeyMsg
+		visible func(tea.Model) bool
+	}{
+		{
+			name:    "menu",
+			model:   NewMenuModel(DefaultOptions()),
+			open:    question,
+			visible: func(m tea.Model) bool { return m.(MenuModel).showHelp },
+		},
+		{
+			name:    "picker",
+			model:   PickerModel{selected: map[int]bool{}, opts: DefaultOptions()},
+			open:    question,
+			visible: func(m tea.Model) bool { return m.(PickerModel).showHelp },
+		},
+		{
+			name:    "preview",
+			model:   PreviewModel{files: []filePreview{{filePath: ".env"}}, opts: DefaultOptions()},
+			open:    question,
+			visible: func(m tea.Model) bool { return m.(PreviewModel).showHelp },
+		},
+		{
+			name:    "form",
+			model:   FormModel{opts: DefaultOptions()},
+			open:    f1,
+			visible: func(m tea.Model) bool { return m.(FormModel).showHelp },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened, _ := tt.model.Update(tt.open)
+			if !tt.visible(opened) {
+				t.Fatalf("help overlay should open")
+			}
+			if !strings.Contains(opened.View(), "Keybindings") {
+				t.Errorf("View() should render the overlay while open")
+			}
+
+			closed, cmd := opened.Update(esc)
+			if tt.visible(closed) {
+				t.Errorf("help overlay should close on any key")
+			}
+			if cmd != nil {
+				t.Errorf("closing the overlay should not emit a command")
+			}
+		})
+	}
+}
+
+func TestFormQuestionMarkIsTyped(t *testing.T) {
+	m := FormModel{fields: []FormField{{Key: "URL", Input: textinput.New()}}, opts: DefaultOptions()}
+	m.fields[0].Input.Focus()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	form := updated.(FormModel)
+
+	if form.showHelp {
+		t.Error("'?' should not open help in the form")
+	}
+	if form.fields[0].Input.Value() != "?" {
+		t.Errorf("input value = %q, expected '?'", form.fields[0].Input.Value())
+	}
+}
+
+func TestKeyMapRemapsModels(t *testing.T) {
+	km := keymap.Default()
+	if err := km.Apply(map[string][]string{"menu.down": {"n"}}); err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+
+	m := NewMenuModel(Options{Keys: km})
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if updated.(MenuModel).choice != GenerateExample {
+		t.Errorf("'j' should no longer move down after remapping")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if updated.(MenuModel).choice != GenerateEnv {
+		t.Errorf("'n' should move down after remapping")
+	}
+
+	if !strings.Contains(updated.View(), "n: down") {
+		t.Errorf("footer should show the remapped key")
+	}
+}
+
+func TestFormSaveKey(t *testing.T) {
+	m := FormModel{fields: []FormField{{Key: "A", Input: textinput.New()}, {Key: "B", Input: textinput.New()}}, opts: DefaultOptions()}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Error("ctrl+s should trigger a save from any field")
+	}
+}
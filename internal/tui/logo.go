@@ -1,26 +1,26 @@
 // Package tui provides Bubble Tea components for the terminal UI.
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
 
-// Logo returns the ASCII art logo for the application.
-func Logo() string {
-	teal := lipgloss.Color("#0F766E")
-	green := lipgloss.Color("#34D399")
-	blue := lipgloss.Color("#93C5FD")
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
 
+// Logo returns the ASCII art logo for the application in palette.
+func Logo(palette theme.Theme) string {
 	frame := lipgloss.NewStyle().
-		Foreground(teal)
+		Foreground(palette.LogoFrame)
 
 	envLabel := lipgloss.NewStyle().
-		Foreground(green).
+		Foreground(palette.LogoLabel).
 		Bold(true)
 
 	lockShackle := lipgloss.NewStyle().
-		Foreground(blue)
+		Foreground(palette.LogoLock)
 
 	lockBody := lipgloss.NewStyle().
-		Foreground(green)
+		Foreground(palette.LogoLabel)
 
 	lines := []string{
 		frame.Render("  ╭──────────────────╮"),
@@ -43,15 +43,15 @@ func Logo() string {
 	return logo
 }
 
-// Wordmark returns the styled application name and tagline.
-func Wordmark() string {
+// Wordmark returns the application name and tagline styled in palette.
+func Wordmark(palette theme.Theme) string {
 	title := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#E5E7EB")).
+		Foreground(palette.Text).
 		Render("dotenv-tui")
 
 	tagline := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#93A3B8")).
+		Foreground(palette.Muted).
 		Italic(true).
 		Render("secure .env workflows in your terminal")
 
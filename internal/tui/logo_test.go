@@ -3,17 +3,21 @@ package tui
 import (
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jellydn/dotenv-tui/internal/theme"
 )
 
 func TestLogo(t *testing.T) {
 	// Arrange: No preconditions needed
 
 	// Act
-	result := Logo()
+	result := Logo(theme.Default())
 
 	// Assert
 	if result == "" {
-		t.Errorf("Logo() returned empty string")
+		t.Errorf("Logo(theme.Default()) returned empty string")
 	}
 
 	// Verify the logo contains expected elements
@@ -42,7 +46,7 @@ func TestWordmark(t *testing.T) {
 	// Arrange: No preconditions needed
 
 	// Act
-	result := Wordmark()
+	result := Wordmark(theme.Default())
 
 	// Assert
 	if result == "" {
@@ -64,3 +68,23 @@ func TestWordmark(t *testing.T) {
 		t.Errorf("Wordmark() should have exactly 2 lines, got %d", len(lines))
 	}
 }
+
+func TestLogoWithoutColors(t *testing.T) {
+	none := theme.Theme{
+		Name:      theme.None,
+		Primary:   lipgloss.NoColor{},
+		OnPrimary: lipgloss.NoColor{},
+		Text:      lipgloss.NoColor{},
+		Muted:     lipgloss.NoColor{},
+		Success:   lipgloss.NoColor{},
+		Warning:   lipgloss.NoColor{},
+		Error:     lipgloss.NoColor{},
+		LogoFrame: lipgloss.NoColor{},
+		LogoLabel: lipgloss.NoColor{},
+		LogoLock:  lipgloss.NoColor{},
+	}
+
+	if !strings.Contains(Logo(none), ".env") {
+		t.Errorf("Logo() should still render without colors")
+	}
+}
@@ -2,6 +2,7 @@
 package tui
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -14,19 +15,31 @@ const (
 	GenerateExample MenuChoice = iota
 	// GenerateEnv creates .env files from .env.example.
 	GenerateEnv
+	// CompareFiles shows the key differences between any two env files.
+	CompareFiles
+	// MoveKeys moves or copies keys from one env file to another.
+	MoveKeys
+	// ResumeSession continues the files left over from the previous run.
+	ResumeSession
 )
 
 // MenuModel is the Bubble Tea model for the main menu.
 type MenuModel struct {
 	choice       MenuChoice
 	enableBackup bool
+	showHelp     bool
+	resumeLabel  string
+	updateNotice string
+	windowWidth  int
+	opts         Options
 }
 
 // NewMenuModel creates a new menu model with default selection.
-func NewMenuModel() MenuModel {
+func NewMenuModel(opts Options) MenuModel {
 	return MenuModel{
 		choice:       GenerateExample,
 		enableBackup: true,
+		opts:         opts,
 	}
 }
 
@@ -40,6 +53,44 @@ func (m MenuModel) EnableBackup() bool {
 	return m.enableBackup
 }
 
+// SetEnableBackup sets whether backups start out enabled.
+func (m *MenuModel) SetEnableBackup(enabled bool) {
+	m.enableBackup = enabled
+}
+
+// SetWindowWidth sets the terminal width the screen is laid out for.
+func (m *MenuModel) SetWindowWidth(w int) {
+	m.windowWidth = w
+}
+
+// SetResume offers a "Resume last session" entry described by label.
+// An empty label hides the entry.
+func (m *MenuModel) SetResume(label string) {
+	m.resumeLabel = label
+	if label == "" && m.choice == ResumeSession {
+		m.choice = MoveKeys
+	}
+}
+
+// SetUpdateAvailable shows a banner announcing that version can be
+// installed with --upgrade. An empty version hides the banner.
+func (m *MenuModel) SetUpdateAvailable(version string) {
+	m.updateNotice = version
+}
+
+// lastChoice returns the bottom-most selectable menu entry.
+func (m MenuModel) lastChoice() MenuChoice {
+	if m.resumeLabel != "" {
+		return ResumeSession
+	}
+	return MoveKeys
+}
+
+// HelpVisible reports whether the keybinding overlay is open.
+func (m MenuModel) HelpVisible() bool {
+	return m.showHelp
+}
+
 // Init initializes the menu model.
 func (m MenuModel) Init() tea.Cmd {
 	return nil
@@ -47,22 +98,32 @@ func (m MenuModel) Init() tea.Cmd {
 
 // Update handles messages and updates the menu model.
 func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.SetWindowWidth(size.Width)
+		return m, nil
+	}
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if ok {
-		switch keyMsg.String() {
-		case "up", "k":
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		switch {
+		case key.Matches(keyMsg, m.opts.Keys.Menu.Up):
 			if m.choice > GenerateExample {
 				m.choice--
 			}
-		case "down", "j":
-			if m.choice < GenerateEnv {
+		case key.Matches(keyMsg, m.opts.Keys.Menu.Down):
+			if m.choice < m.lastChoice() {
 				m.choice++
 			}
-		case "b":
+		case key.Matches(keyMsg, m.opts.Keys.Menu.Backup):
 			m.enableBackup = !m.enableBackup
-		case "q", "ctrl+c":
+		case key.Matches(keyMsg, m.opts.Keys.Help):
+			m.showHelp = true
+		case key.Matches(keyMsg, m.opts.Keys.Menu.Quit):
 			return m, tea.Quit
-		case "enter", " ":
+		case key.Matches(keyMsg, m.opts.Keys.Menu.Select):
 			return m, nil
 		}
 	}
@@ -71,14 +132,28 @@ func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the menu UI.
 func (m MenuModel) View() string {
-	logo := Logo()
-	wordmark := Wordmark()
+	if m.showHelp {
+		return helpOverlay(menuKeys(m.opts.Keys), m.opts.Theme)
+	}
+
+	logo := Logo(m.opts.Theme)
+	wordmark := Wordmark(m.opts.Theme)
 
 	header := lipgloss.JoinHorizontal(lipgloss.Top, logo, "  "+wordmark)
+	if m.updateNotice != "" {
+		header += "\n\n" + lipgloss.NewStyle().
+			Foreground(m.opts.Theme.Muted).
+			Render(m.updateNotice+" available — run dotenv-tui --upgrade")
+	}
 
 	choices := []string{
 		"Generate .env.example from .env",
 		"Generate .env from .env.example",
+		"Compare two env files",
+		"Move keys between env files",
+	}
+	if m.resumeLabel != "" {
+		choices = append(choices, "Resume last session "+m.resumeLabel)
 	}
 
 	var renderedChoices string
@@ -87,7 +162,7 @@ func (m MenuModel) View() string {
 		if MenuChoice(i) == m.choice {
 			cursor = ">"
 			renderedChoices += lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#7D56F4")).
+				Foreground(m.opts.Theme.Primary).
 				Bold(true).
 				Render(cursor+" "+choice) + "\n"
 		} else {
@@ -98,17 +173,15 @@ func (m MenuModel) View() string {
 	var backupStatus string
 	if m.enableBackup {
 		backupStatus = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FF00")).
+			Foreground(m.opts.Theme.Success).
 			Render("[B] Backup: ON")
 	} else {
 		backupStatus = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF5F56")).
+			Foreground(m.opts.Theme.Error).
 			Render("[B] Backup: OFF")
 	}
 
-	help := lipgloss.NewStyle().
-		Faint(true).
-		Render("↑/k: up • ↓/j: down • b: toggle backup • Enter: select • q: quit")
+	help := shortHelp(menuKeys(m.opts.Keys).short, m.windowWidth)
 
 	return "\n" + header + "\n\n" + renderedChoices + "\n" + backupStatus + "\n\n" + help + "\n"
 }
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,11 +11,11 @@ func TestNewMenuModel(t *testing.T) {
 	// Arrange: No preconditions needed
 
 	// Act
-	model := NewMenuModel()
+	model := NewMenuModel(DefaultOptions())
 
 	// Assert
 	if model.choice != GenerateExample {
-		t.Errorf("NewMenuModel() choice = %v, expected %v", model.choice, GenerateExample)
+		t.Errorf("NewMenuModel(DefaultOptions()) choice = %v, expected %v", model.choice, GenerateExample)
 	}
 }
 
@@ -39,7 +40,7 @@ func TestMenuModelChoice(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
-			model := MenuModel{choice: tt.choice}
+			model := MenuModel{choice: tt.choice, opts: DefaultOptions()}
 
 			// Act
 			result := model.Choice()
@@ -54,7 +55,7 @@ func TestMenuModelChoice(t *testing.T) {
 
 func TestMenuModelInit(t *testing.T) {
 	// Arrange
-	model := NewMenuModel()
+	model := NewMenuModel(DefaultOptions())
 
 	// Act
 	cmd := model.Init()
@@ -103,10 +104,22 @@ func TestMenuModelUpdateNavigation(t *testing.T) {
 			expectedChoice: GenerateExample,
 		},
 		{
-			name:           "down key at GenerateEnv stays at GenerateEnv",
+			name:           "down key from GenerateEnv moves to CompareFiles",
 			initialChoice:  GenerateEnv,
 			keyMsg:         "down",
-			expectedChoice: GenerateEnv,
+			expectedChoice: CompareFiles,
+		},
+		{
+			name:           "down key from CompareFiles moves to MoveKeys",
+			initialChoice:  CompareFiles,
+			keyMsg:         "down",
+			expectedChoice: MoveKeys,
+		},
+		{
+			name:           "down key at MoveKeys stays at MoveKeys",
+			initialChoice:  MoveKeys,
+			keyMsg:         "down",
+			expectedChoice: MoveKeys,
 		},
 		{
 			name:           "enter key does not change choice",
@@ -125,7 +138,7 @@ func TestMenuModelUpdateNavigation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
-			model := MenuModel{choice: tt.initialChoice}
+			model := MenuModel{choice: tt.initialChoice, opts: DefaultOptions()}
 			msg := tea.KeyMsg{}
 
 			// Set key type based on message string
@@ -186,7 +199,7 @@ func TestMenuModelUpdateQuit(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
-			model := NewMenuModel()
+			model := NewMenuModel(DefaultOptions())
 
 			// Act
 			_, cmd := model.Update(tt.keyMsg)
@@ -202,7 +215,7 @@ func TestMenuModelUpdateQuit(t *testing.T) {
 
 func TestMenuModelUpdateUnknownMessage(t *testing.T) {
 	// Arrange
-	model := NewMenuModel()
+	model := NewMenuModel(DefaultOptions())
 	initialChoice := model.choice
 	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}
 
@@ -223,3 +236,54 @@ func TestMenuModelUpdateUnknownMessage(t *testing.T) {
 		t.Errorf("Update(unknown key) should return nil command, got %v", cmd)
 	}
 }
+
+func TestMenuResumeEntry(t *testing.T) {
+	m := NewMenuModel(DefaultOptions())
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	updated, _ := m.Update(down)
+	updated, _ = updated.Update(down)
+	updated, _ = updated.Update(down)
+	updated, _ = updated.Update(down)
+	if updated.(MenuModel).Choice() != MoveKeys {
+		t.Fatalf("without a session the cursor should stop at MoveKeys")
+	}
+
+	m.SetResume("(2 files, 1 saved)")
+	updated, _ = m.Update(down)
+	updated, _ = updated.Update(down)
+	updated, _ = updated.Update(down)
+	updated, _ = updated.Update(down)
+	menu := updated.(MenuModel)
+	if menu.Choice() != ResumeSession {
+		t.Errorf("Choice() = %v, expected ResumeSession", menu.Choice())
+	}
+	if !strings.Contains(menu.View(), "Resume last session (2 files, 1 saved)") {
+		t.Errorf("View() should list the resume entry")
+	}
+
+	menu.SetResume("")
+	if menu.Choice() != MoveKeys {
+		t.Errorf("hiding the resume entry should move the cursor back")
+	}
+}
+
+func TestMenuUpdateBanner(t *testing.T) {
+	m := NewMenuModel(DefaultOptions())
+	if strings.Contains(m.View(), "available") {
+		t.Fatalf("View() should not show a banner before an update is found")
+	}
+
+	m.SetUpdateAvailable("v1.4.0")
+	if !strings.Contains(m.View(), "v1.4.0 available — run dotenv-tui --upgrade") {
+		t.Errorf("View() should announce the available update")
+	}
+}
+
+func TestMenuSetEnableBackup(t *testing.T) {
+	m := NewMenuModel(DefaultOptions())
+	m.SetEnableBackup(false)
+	if m.EnableBackup() {
+		t.Error("SetEnableBackup(false) should start with backups off")
+	}
+}
@@ -0,0 +1,366 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MoveModel is the Bubble Tea model for moving or copying keys from one
+// env file to another, along with the comments directly above them.
+type MoveModel struct {
+	from         string
+	keys         []string // in file order
+	values       map[string]string
+	selected     map[string]bool
+	keep         bool // copy the keys instead of moving them
+	createBackup bool
+	errMsg       string
+	cursor       int
+	offset       int
+	windowHeight int
+	windowWidth  int
+	prompting    bool // the destination prompt is open
+	dest         textinput.Model
+	showHelp     bool
+	opts         Options
+}
+
+// MoveFinishedMsg signals the user left the move screen.
+type MoveFinishedMsg struct{}
+
+// KeysMovedMsg reports that keys were moved or copied between env files.
+type KeysMovedMsg struct {
+	From, To string
+	Keys     []string
+	Copy     bool
+}
+
+type moveInitMsg struct {
+	from         string
+	keys         []string
+	values       map[string]string
+	createBackup bool
+	errMsg       string
+	opts         Options
+}
+
+// NewMoveModel loads the env file keys are moved from. createBackup backs
+// up both files before they are written.
+func NewMoveModel(from string, createBackup bool, opts Options) tea.Cmd {
+	return func() tea.Msg {
+		msg := moveInitMsg{from: from, createBackup: createBackup, opts: opts}
+		entries, _, err := readCompared(from)
+		if err != nil {
+			msg.errMsg = err.Error()
+			return msg
+		}
+		msg.keys, msg.values = keyValues(entries)
+		return msg
+	}
+}
+
+// Init initializes the move model.
+func (m MoveModel) Init() tea.Cmd {
+	return nil
+}
+
+// SetWindowHeight sets the terminal height for scroll calculations.
+func (m *MoveModel) SetWindowHeight(h int) {
+	m.windowHeight = h
+}
+
+// SetWindowWidth sets the terminal width the list is fitted to.
+func (m *MoveModel) SetWindowWidth(w int) {
+	m.windowWidth = w
+}
+
+// Selected returns the selected keys in file order.
+func (m MoveModel) Selected() []string {
+	var selected []string
+	for _, k := range m.keys {
+		if m.selected[k] {
+			selected = append(selected, k)
+		}
+	}
+	return selected
+}
+
+// moveOverheadLines is everything but the keys: blank line, title,
+// summary, blank line, scroll info and blank line.
+const moveOverheadLines = 6
+
+func (m MoveModel) visibleLines() int {
+	overhead := moveOverheadLines + lipgloss.Height(shortHelp(moveKeys(m.opts.Keys, m.prompting).short, m.windowWidth))
+	if m.prompting {
+		overhead += 2 // blank line + prompt
+	}
+	if m.windowHeight <= overhead {
+		return 10 // fallback to default if window is too small
+	}
+	return m.windowHeight - overhead
+}
+
+func (m *MoveModel) adjustScroll() {
+	visible := m.visibleLines()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+// openPrompt starts asking which file the selected keys go to.
+func (m *MoveModel) openPrompt() tea.Cmd {
+	if len(m.Selected()) == 0 {
+		return statusCmd("Select the keys to move with Space", true)
+	}
+	m.dest = textinput.New()
+	m.dest.Prompt = "Destination file: "
+	m.dest.Placeholder = filepath.Join("services", "api", ".env")
+	m.dest.Width = 40
+	m.prompting = true
+	return m.dest.Focus()
+}
+
+// Update handles messages and updates the move model.
+func (m MoveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case moveInitMsg:
+		m.opts = msg.opts
+		m.from, m.createBackup = msg.from, msg.createBackup
+		m.keys, m.values = msg.keys, msg.values
+		m.selected = make(map[string]bool)
+		m.errMsg = msg.errMsg
+		m.cursor, m.offset = 0, 0
+		m.prompting = false
+		return m, nil
+
+	case KeysMovedMsg:
+		// Reload, so the moved keys leave the list.
+		return m, NewMoveModel(m.from, m.createBackup, m.opts)
+
+	case tea.WindowSizeMsg:
+		m.windowHeight = msg.Height
+		m.windowWidth = msg.Width
+		m.adjustScroll()
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.prompting {
+			switch {
+			case key.Matches(msg, m.opts.Keys.Move.Confirm):
+				to := strings.TrimSpace(m.dest.Value())
+				if to == "" {
+					return m, nil
+				}
+				m.prompting = false
+				return m, m.opts.moveKeysCmd(m.from, to, m.Selected(), m.keep, m.createBackup)
+			case key.Matches(msg, m.opts.Keys.Move.Cancel):
+				m.prompting = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.dest, cmd = m.dest.Update(msg)
+			return m, cmd
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.opts.Keys.Move.Up):
+			if m.cursor > 0 {
+				m.cursor--
+				m.adjustScroll()
+			}
+		case key.Matches(msg, m.opts.Keys.Move.Down):
+			if m.cursor < len(m.keys)-1 {
+				m.cursor++
+				m.adjustScroll()
+			}
+		case key.Matches(msg, m.opts.Keys.Move.Toggle):
+			if m.cursor < len(m.keys) {
+				k := m.keys[m.cursor]
+				m.selected[k] = !m.selected[k]
+			}
+		case key.Matches(msg, m.opts.Keys.Move.All):
+			all := len(m.Selected()) < len(m.keys)
+			for _, k := range m.keys {
+				m.selected[k] = all
+			}
+		case key.Matches(msg, m.opts.Keys.Move.Copy):
+			m.keep = !m.keep
+		case key.Matches(msg, m.opts.Keys.Move.Next):
+			if m.errMsg == "" {
+				return m, m.openPrompt()
+			}
+		case key.Matches(msg, m.opts.Keys.Help):
+			m.showHelp = true
+		case key.Matches(msg, m.opts.Keys.Move.Back):
+			return m, func() tea.Msg { return MoveFinishedMsg{} }
+		}
+	}
+	return m, nil
+}
+
+// moveKeysCmd moves, or with keep copies, keys from the env file from to
+// the end of to, creating it if needed. The destination is written first
+// and put back if writing the source then fails, so a failure part way
+// never loses the keys.
+func (o Options) moveKeysCmd(from, to string, keys []string, keep, createBackup bool) tea.Cmd {
+	return func() tea.Msg {
+		if filepath.Clean(from) == filepath.Clean(to) {
+			return StatusMsg{Text: "The destination is the file the keys are in", Error: true}
+		}
+		src, _, err := readCompared(from)
+		if err != nil {
+			return StatusMsg{Text: err.Error(), Error: true}
+		}
+		var dst []parser.Entry
+		if _, statErr := os.Stat(to); statErr == nil {
+			if dst, _, err = readCompared(to); err != nil {
+				return StatusMsg{Text: err.Error(), Error: true}
+			}
+		}
+		newSrc, newDst, err := parser.MoveKeys(src, dst, keys, keep)
+		if err != nil {
+			return StatusMsg{Text: fmt.Sprintf("Failed to move keys: %v", err), Error: true}
+		}
+
+		op := history.OpMove
+		if keep {
+			op = history.OpCopy
+		}
+		dstBackup, err := o.backupIfExists(to, createBackup)
+		if err != nil {
+			return StatusMsg{Text: err.Error(), Error: true}
+		}
+		var srcBackup string
+		if !keep {
+			if srcBackup, err = o.backupIfExists(from, createBackup); err != nil {
+				return StatusMsg{Text: err.Error(), Error: true}
+			}
+		}
+
+		if err := o.writeEnvFile(to, newDst); err != nil {
+			return StatusMsg{Text: fmt.Sprintf("Failed to write %s: %v", to, err), Error: true}
+		}
+		if !keep {
+			if err := o.writeEnvFile(from, newSrc); err != nil {
+				if restoreErr := o.writeEnvFile(to, dst); restoreErr != nil {
+					err = errors.Join(err, restoreErr)
+				}
+				return StatusMsg{Text: fmt.Sprintf("Failed to write %s: %v", from, err), Error: true}
+			}
+			o.recordWrite(op, from, src, newSrc, srcBackup)
+		}
+		o.recordWrite(op, to, dst, newDst, dstBackup)
+		return KeysMovedMsg{From: from, To: to, Keys: keys, Copy: keep}
+	}
+}
+
+// backupIfExists backs up path when enabled and the file exists, and
+// returns the backup path.
+func (o Options) backupIfExists(path string, enabled bool) (string, error) {
+	if !enabled {
+		return "", nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", nil
+	}
+	backupPath, err := o.backupOptions(path).Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create backup: %w", err)
+	}
+	return backupPath, nil
+}
+
+// writeEnvFile writes entries to path, creating it readable only by the
+// owner since it may hold secrets.
+func (o Options) writeEnvFile(path string, entries []parser.Entry) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := parser.WriteWithOptions(file, entries, o.Write); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// View renders the key list.
+func (m MoveModel) View() string {
+	if m.showHelp {
+		return helpOverlay(moveKeys(m.opts.Keys, false), m.opts.Theme)
+	}
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.opts.Theme.OnPrimary).
+		Background(m.opts.Theme.Primary).
+		Padding(0, 1).
+		Render("Move keys from " + filepath.ToSlash(m.from))
+	faint := lipgloss.NewStyle().Faint(true)
+
+	if m.errMsg != "" {
+		errText := lipgloss.NewStyle().Foreground(m.opts.Theme.Error).Render(fitWidth(m.errMsg, m.windowWidth))
+		return "\n" + title + "\n\n" + errText + "\n\n" + shortHelp([]key.Binding{m.opts.Keys.Move.Back}, m.windowWidth) + "\n"
+	}
+
+	mode := "move"
+	if m.keep {
+		mode = "copy"
+	}
+	summary := faint.Render(fitWidth(fmt.Sprintf("%d of %d key(s) selected · %s, with their comments", len(m.Selected()), len(m.keys), mode), m.windowWidth))
+
+	var list strings.Builder
+	visible := m.visibleLines()
+	end := min(m.offset+visible, len(m.keys))
+	if len(m.keys) == 0 {
+		list.WriteString(faint.Render("  No keys in this file") + "\n")
+	}
+	for i := m.offset; i < end; i++ {
+		k := m.keys[i]
+		cursor := " "
+		if i == m.cursor {
+			cursor = ">"
+		}
+		check := "[ ]"
+		if m.selected[k] {
+			check = "[x]"
+		}
+		value := m.values[k]
+		if m.opts.Example.Masking.Rules.IsSecret(k, value) {
+			value = strings.Repeat("•", 8)
+		}
+		line := fitWidth(cursor+" "+check+" "+k+"="+value, m.windowWidth)
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			style = style.Bold(true).Background(m.opts.Theme.Primary)
+		}
+		list.WriteString(style.Render(line) + "\n")
+	}
+	if len(m.keys) > visible {
+		list.WriteString(faint.Render(fmt.Sprintf("Key %d/%d", m.cursor+1, len(m.keys))) + "\n")
+	}
+
+	if m.prompting {
+		list.WriteString("\n" + m.dest.View() + "\n")
+	}
+
+	help := shortHelp(moveKeys(m.opts.Keys, m.prompting).short, m.windowWidth)
+
+	return "\n" + title + "\n" + summary + "\n\n" + list.String() + "\n" + help + "\n"
+}
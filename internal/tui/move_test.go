@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loadMove writes content to a .env in a temp directory and opens it on
+// the move screen.
+func loadMove(t *testing.T, content string) (MoveModel, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := MoveModel{}.Update(NewMoveModel(path, false, DefaultOptions())())
+	return updated.(MoveModel), dir
+}
+
+func moveKey(m MoveModel, msg tea.KeyMsg) (MoveModel, tea.Cmd) {
+	updated, cmd := m.Update(msg)
+	return updated.(MoveModel), cmd
+}
+
+func TestMoveModel(t *testing.T) {
+	m, dir := loadMove(t, "PORT=3000\n\n# Billing\nSTRIPE_KEY=sk_live_abcdef123456\nSTRIPE_HOOK=whsec\n")
+
+	view := m.View()
+	for _, want := range []string{"Move keys from", "0 of 3 key(s) selected · move", "[ ] PORT=3000", "STRIPE_KEY=••••••••"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q:\n%s", want, view)
+		}
+	}
+
+	_, cmd := moveKey(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if status := statusOf(t, cmd); !status.Error {
+		t.Errorf("Enter with nothing selected = %+v, want an error", status)
+	}
+
+	m, _ = moveKey(m, tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = moveKey(m, tea.KeyMsg{Type: tea.KeySpace})
+	m, _ = moveKey(m, tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = moveKey(m, tea.KeyMsg{Type: tea.KeySpace})
+	if got := m.Selected(); len(got) != 2 || got[0] != "STRIPE_KEY" || got[1] != "STRIPE_HOOK" {
+		t.Fatalf("Selected() = %v", got)
+	}
+
+	m, _ = moveKey(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.prompting {
+		t.Fatal("Enter should ask for the destination")
+	}
+	dest := filepath.Join(dir, "billing", ".env")
+	if err := os.Mkdir(filepath.Dir(dest), 0700); err != nil {
+		t.Fatal(err)
+	}
+	m.dest.SetValue(dest)
+	m, cmd = moveKey(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.prompting || cmd == nil {
+		t.Fatal("Enter should close the prompt and move the keys")
+	}
+	moved, ok := cmd().(KeysMovedMsg)
+	if !ok || moved.To != dest || len(moved.Keys) != 2 || moved.Copy {
+		t.Fatalf("Enter = %+v, want KeysMovedMsg", moved)
+	}
+
+	src, _ := os.ReadFile(filepath.Join(dir, ".env"))
+	if string(src) != "PORT=3000\n" {
+		t.Errorf(".env = %q, want only PORT left", src)
+	}
+	dst, _ := os.ReadFile(dest)
+	if string(dst) != "# Billing\nSTRIPE_KEY=sk_live_abcdef123456\nSTRIPE_HOOK=whsec\n" {
+		t.Errorf("destination = %q, want the keys with their comment", dst)
+	}
+
+	// The screen reloads, so the moved keys leave the list.
+	_, cmd = m.Update(moved)
+	updated, _ := m.Update(cmd())
+	if keys := updated.(MoveModel).keys; len(keys) != 1 || keys[0] != "PORT" {
+		t.Errorf("after the move the list shows %v", keys)
+	}
+}
+
+func TestMoveModelCopy(t *testing.T) {
+	m, dir := loadMove(t, "PORT=3000\n")
+	other := filepath.Join(dir, ".env.local")
+	if err := os.WriteFile(other, []byte("PORT=4000\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m, _ = moveKey(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m, _ = moveKey(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if !strings.Contains(m.View(), "1 of 1 key(s) selected · copy") {
+		t.Errorf("c should switch to copying:\n%s", m.View())
+	}
+
+	m, _ = moveKey(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m.dest.SetValue(other)
+	_, cmd := moveKey(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if status, ok := cmd().(StatusMsg); !ok || !status.Error || !strings.Contains(status.Text, "already exists") {
+		t.Errorf("copying onto an existing key = %+v, want an error", status)
+	}
+	if data, _ := os.ReadFile(other); string(data) != "PORT=4000\n" {
+		t.Errorf("a failed copy should not write, got %q", data)
+	}
+}
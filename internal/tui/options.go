@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/keymap"
+	"github.com/jellydn/dotenv-tui/internal/lint"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+// Options are the choices, taken once from the config files and flags,
+// that shape every screen. Each screen takes them from the constructor
+// that opens it.
+type Options struct {
+	// Keys are the keybindings of every screen.
+	Keys keymap.KeyMap
+	// Theme colors every view.
+	Theme theme.Theme
+	// Scan limits the files the picker finds.
+	Scan scanner.Options
+	// Example controls how the preview masks secrets and arranges keys in
+	// the examples it generates. The detection rules of its masking also
+	// decide which values the other screens hide.
+	Example generator.Options
+	// Lint sets the rules the preview lints files with.
+	Lint lint.Options
+	// FormPreview opens each form with a summary of the example first:
+	// which keys still need a value, how complete it already is, and
+	// whether an existing .env would be overwritten.
+	FormPreview bool
+	// Annotate makes saved forms record where each value came from: values
+	// typed in the form are noted as set via the form on the day they were
+	// saved, and values kept from the existing .env keep their note.
+	Annotate bool
+	// Write controls how written values are quoted.
+	Write parser.WriteOptions
+	// Dir returns the settings for the file at a path, which a
+	// .dotenv-tui.yaml in its directory or above may change, typically
+	// config.Overrides.For. The preview and the form then take the masking,
+	// backup and schema of each file from it; the backup toggle still turns
+	// backups off for every file. Nil uses Example for every file.
+	Dir func(path string) (config.DirSettings, error)
+	// Deterministic leaves the day out of provenance comments and changelog
+	// blocks, and numbers backups instead of timestamping them.
+	Deterministic bool
+	// History is the log every write is recorded in; empty records none.
+	History string
+}
+
+// DefaultOptions returns the options used without a config file.
+func DefaultOptions() Options {
+	return Options{Keys: keymap.Default(), Theme: theme.Default()}
+}
+
+// today returns the day to date provenance comments and changelog blocks
+// with, or the zero time, which leaves the date out, in deterministic mode.
+func (o Options) today() time.Time {
+	if o.Deterministic {
+		return time.Time{}
+	}
+	return time.Now()
+}
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// isPaste reports whether msg carries pasted text: a bracketed paste, or,
+// in terminals without bracketed paste, several characters arriving at once.
+func isPaste(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyRunes && (msg.Paste || len(msg.Runes) > 1)
+}
+
+// cleanPaste trims the whitespace around pasted text, such as the trailing
+// newline copied along with a token, and removes control characters other
+// than line breaks, reporting whether it found any.
+func cleanPaste(s string) (clean string, hadControl bool) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "\r\n", "\n")
+	clean = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' {
+			hadControl = true
+			return -1
+		}
+		return r
+	}, s)
+	return clean, hadControl
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIsPaste(t *testing.T) {
+	tests := []struct {
+		msg  tea.KeyMsg
+		want bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}, false},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a"), Paste: true}, true},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("abc")}, true},
+		{tea.KeyMsg{Type: tea.KeyEnter}, false},
+	}
+	for _, tt := range tests {
+		if got := isPaste(tt.msg); got != tt.want {
+			t.Errorf("isPaste(%+v) = %v, want %v", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestCleanPaste(t *testing.T) {
+	tests := []struct {
+		in          string
+		want        string
+		wantControl bool
+	}{
+		{"sk_live_123\n", "sk_live_123", false},
+		{"  token \r\n", "token", false},
+		{"line one\r\nline two\n", "line one\nline two", false},
+		{"to\x1bken\x00", "token", true},
+		{"a\tb", "ab", true},
+	}
+	for _, tt := range tests {
+		got, control := cleanPaste(tt.in)
+		if got != tt.want || control != tt.wantControl {
+			t.Errorf("cleanPaste(%q) = %q, %v; want %q, %v", tt.in, got, control, tt.want, tt.wantControl)
+		}
+	}
+}
@@ -2,22 +2,38 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
+	"strings"
 
+	"github.com/jellydn/dotenv-tui/internal/history"
 	"github.com/jellydn/dotenv-tui/internal/scanner"
+	"github.com/jellydn/dotenv-tui/internal/templates"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// pickerItem represents an item in the picker list (either a header or a file).
+// pickerItem represents an item in the picker list: a header, a directory
+// node of the tree or a file.
 type pickerItem struct {
 	text     string
-	filePath string // empty for headers
+	filePath string // empty for headers and directories
 	isHeader bool
+	isDir    bool   // collapsible directory node
+	dir      string // directory a header or directory node stands for
+	depth    int    // nesting level in the tree
+}
+
+// isFile reports whether the item is a selectable file.
+func (it pickerItem) isFile() bool {
+	return !it.isHeader && !it.isDir
 }
 
 // PickerModel is the Bubble Tea model for selecting .env files.
@@ -28,7 +44,16 @@ type PickerModel struct {
 	mode         MenuChoice
 	rootDir      string
 	windowHeight int
-	offset       int // scroll offset (first visible item index)
+	windowWidth  int
+	offset       int             // scroll offset (first visible row)
+	collapsed    map[string]bool // directories collapsed in the tree, kept across scans
+	prompting    bool            // the select-by-pattern prompt is open
+	pattern      textinput.Model
+	badges       map[string]fileBadges // by file path, loaded as rows come into view
+	showHelp     bool
+	suggested    templates.Template // offered when no example was found; empty Name for none
+	skipped      string             // summary of the directories the scan could not read; empty for none
+	opts         Options
 }
 
 // PickerFinishedMsg signals file selection is complete.
@@ -39,12 +64,20 @@ type PickerFinishedMsg struct {
 
 // groupFilesByDirectory organizes files into a list of pickerItem structs,
 // grouping them by their parent directory with non-selectable headers.
-func groupFilesByDirectory(files []string) []pickerItem {
+// Files inside a workspace package are grouped under the package instead,
+// with its name as the header.
+func groupFilesByDirectory(files []string, workspaces []scanner.Workspace) []pickerItem {
 	dirGroups := make(map[string][]string)
+	headers := make(map[string]string)
 	for _, file := range files {
-		dir := filepath.Dir(file)
-		if dir == "." {
-			dir = "Current Directory"
+		dir := filepath.ToSlash(filepath.Dir(file))
+		if ws, ok := workspaceOf(dir, workspaces); ok {
+			dir = ws.Dir
+			headers[dir] = ws.Name
+		} else if dir == "." {
+			headers[dir] = "Current Directory"
+		} else {
+			headers[dir] = dir
 		}
 		dirGroups[dir] = append(dirGroups[dir], file)
 	}
@@ -58,15 +91,16 @@ func groupFilesByDirectory(files []string) []pickerItem {
 	var items []pickerItem
 	for _, dir := range dirs {
 		items = append(items, pickerItem{
-			text:     dir,
+			text:     headers[dir],
 			filePath: "",
 			isHeader: true,
+			dir:      dir,
 		})
 
 		sort.Strings(dirGroups[dir])
 		for _, file := range dirGroups[dir] {
 			items = append(items, pickerItem{
-				text:     file,
+				text:     filepath.ToSlash(file),
 				filePath: file,
 				isHeader: false,
 			})
@@ -76,46 +110,197 @@ func groupFilesByDirectory(files []string) []pickerItem {
 	return items
 }
 
+// workspaceOf returns the innermost workspace package containing dir, a
+// slash-separated path relative to the scan root.
+func workspaceOf(dir string, workspaces []scanner.Workspace) (scanner.Workspace, bool) {
+	var found scanner.Workspace
+	ok := false
+	for _, ws := range workspaces {
+		if (dir == ws.Dir || strings.HasPrefix(dir, ws.Dir+"/")) && len(ws.Dir) > len(found.Dir) {
+			found, ok = ws, true
+		}
+	}
+	return found, ok
+}
+
+// treeNode is a directory while buildTree assembles the picker tree.
+type treeNode struct {
+	path     string
+	label    string // workspace package name, if any
+	files    []string
+	children map[string]*treeNode
+}
+
+// child returns the node for dir below n, creating it and its parents.
+func (n *treeNode) child(dir string) *treeNode {
+	if dir == "." {
+		return n
+	}
+	node := n
+	for _, seg := range strings.Split(dir, "/") {
+		next, ok := node.children[seg]
+		if !ok {
+			next = &treeNode{path: path.Join(node.path, seg), children: make(map[string]*treeNode)}
+			node.children[seg] = next
+		}
+		node = next
+	}
+	return node
+}
+
+// emit appends n's files, then its subdirectories, to items.
+func (n *treeNode) emit(items *[]pickerItem, depth int) {
+	for _, file := range n.files {
+		text := filepath.ToSlash(file)
+		if rel, err := filepath.Rel(filepath.FromSlash(n.path), file); err == nil {
+			text = filepath.ToSlash(rel)
+		}
+		*items = append(*items, pickerItem{text: text, filePath: file, depth: depth})
+	}
+
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c, text := n.children[name], name
+		for len(c.files) == 0 && len(c.children) == 1 && c.label == "" {
+			for seg, only := range c.children {
+				c, text = only, text+"/"+seg
+			}
+		}
+		if c.label != "" {
+			text = c.label
+		}
+		*items = append(*items, pickerItem{text: text, isDir: true, dir: c.path, depth: depth})
+		c.emit(items, depth+1)
+	}
+}
+
+// buildTree turns the groups made by groupFilesByDirectory into a tree of
+// directory nodes. A directory holding nothing but one subdirectory is
+// merged with it into a single node, e.g. "services/backend".
+func buildTree(groups []pickerItem) []pickerItem {
+	root := &treeNode{path: ".", children: make(map[string]*treeNode)}
+	node := root
+	for _, item := range groups {
+		if item.isHeader {
+			node = root.child(item.dir)
+			if item.dir != "." && item.text != item.dir {
+				node.label = item.text
+			}
+			continue
+		}
+		node.files = append(node.files, item.filePath)
+	}
+
+	var items []pickerItem
+	root.emit(&items, 0)
+	return items
+}
+
 // NewPickerModel creates a file picker for selecting .env files.
-func NewPickerModel(mode MenuChoice, rootDir string) tea.Cmd {
-	var files []string
+func NewPickerModel(mode MenuChoice, rootDir string, opts Options) tea.Cmd {
+	var result scanner.Result
 	var err error
 
-	if mode == GenerateEnv {
-		files, err = scanner.ScanExamples(rootDir)
-	} else {
-		files, err = scanner.Scan(rootDir)
+	switch mode {
+	case GenerateEnv:
+		result, err = scanner.ScanExamplesWithOptions(context.Background(), rootDir, opts.Scan)
+	case CompareFiles:
+		result, err = scanComparableFiles(rootDir, opts.Scan)
+	case MoveKeys:
+		result, err = scanAllEnvFiles(rootDir, opts.Scan)
+	default:
+		result, err = scanner.ScanWithOptions(context.Background(), rootDir, opts.Scan)
 	}
 
+	files := result.Files
 	if err != nil {
 		files = []string{}
 		fmt.Fprintf(os.Stderr, "Warning: failed to scan directory: %v\n", err)
 	}
 
-	items := groupFilesByDirectory(files)
+	workspaces, err := scanner.FindWorkspaces(rootDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read workspace manifests: %v\n", err)
+	}
+
+	items := buildTree(groupFilesByDirectory(files, workspaces))
 
 	selected := make(map[int]bool)
 	for i, item := range items {
-		if !item.isHeader {
+		if item.isFile() {
 			selected[i] = false
 		}
 	}
 
+	// Without any example to fill in, offer the template of the stack
+	// the project uses.
+	var suggested templates.Template
+	if mode == GenerateEnv && len(files) == 0 {
+		if t, ok, err := templates.Detect(rootDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to detect the project's stack: %v\n", err)
+		} else if ok {
+			suggested = t
+		}
+	}
+
 	return func() tea.Msg {
 		return pickerInitMsg{
-			items:    items,
-			selected: selected,
-			mode:     mode,
-			rootDir:  rootDir,
+			items:     items,
+			selected:  selected,
+			mode:      mode,
+			rootDir:   rootDir,
+			suggested: suggested,
+			skipped:   scanner.SkippedSummary(result.Skipped),
+			opts:      opts,
 		}
 	}
 }
 
+// scanAllEnvFiles finds both env files and examples, since any two of them
+// can be compared. Both scans walk the same directories, so the skipped ones
+// are those of the first.
+func scanAllEnvFiles(rootDir string, scanOpts scanner.Options) (scanner.Result, error) {
+	result, err := scanner.ScanWithOptions(context.Background(), rootDir, scanOpts)
+	if err != nil {
+		return scanner.Result{}, err
+	}
+	examples, err := scanner.ScanExamplesWithOptions(context.Background(), rootDir, scanOpts)
+	if err != nil {
+		return scanner.Result{}, err
+	}
+	result.Files = append(result.Files, examples.Files...)
+	sort.Strings(result.Files)
+	return result, nil
+}
+
+// scanComparableFiles adds direnv .envrc files to scanAllEnvFiles, since
+// comparing only reads them.
+func scanComparableFiles(rootDir string, scanOpts scanner.Options) (scanner.Result, error) {
+	result, err := scanAllEnvFiles(rootDir, scanOpts)
+	if err != nil {
+		return scanner.Result{}, err
+	}
+	envrcs, err := scanner.ScanDirenvWithOptions(context.Background(), rootDir, scanOpts)
+	if err != nil {
+		return scanner.Result{}, err
+	}
+	result.Files = append(result.Files, envrcs.Files...)
+	sort.Strings(result.Files)
+	return result, nil
+}
+
 type pickerInitMsg struct {
-	items    []pickerItem
-	selected map[int]bool
-	mode     MenuChoice
-	rootDir  string
+	items     []pickerItem
+	selected  map[int]bool
+	mode      MenuChoice
+	rootDir   string
+	suggested templates.Template
+	skipped   string
+	opts      Options
 }
 
 // SetWindowHeight sets the terminal height for scroll calculations.
@@ -123,6 +308,86 @@ func (m *PickerModel) SetWindowHeight(h int) {
 	m.windowHeight = h
 }
 
+// SetWindowWidth sets the terminal width rows are truncated to.
+func (m *PickerModel) SetWindowWidth(w int) {
+	m.windowWidth = w
+}
+
+// HelpVisible reports whether the keybinding overlay is open.
+func (m PickerModel) HelpVisible() bool {
+	return m.showHelp
+}
+
+// PromptVisible reports whether the select-by-pattern prompt is open, so
+// keys are typed into it rather than acted on.
+func (m PickerModel) PromptVisible() bool {
+	return m.prompting
+}
+
+// Counts returns how many files are listed and how many are selected.
+func (m PickerModel) Counts() (files, selected int) {
+	for i, item := range m.items {
+		if item.isFile() {
+			files++
+			if m.selected[i] {
+				selected++
+			}
+		}
+	}
+	return files, selected
+}
+
+// openPrompt starts asking for a glob to select files by.
+func (m *PickerModel) openPrompt() tea.Cmd {
+	m.pattern = textinput.New()
+	m.pattern.Placeholder = "services/*/.env"
+	m.pattern.Prompt = "Select files matching: "
+	m.pattern.Width = 40
+	m.prompting = true
+	return m.pattern.Focus()
+}
+
+// selectPattern selects every file whose path matches the glob pattern,
+// keeping the files already selected, and reports how many matched.
+func (m *PickerModel) selectPattern(pattern string) tea.Cmd {
+	if strings.TrimSpace(pattern) == "" {
+		return nil
+	}
+	matched := 0
+	for i, item := range m.items {
+		if item.isFile() && scanner.MatchGlob(pattern, filepath.ToSlash(item.filePath)) {
+			m.selected[i] = true
+			matched++
+		}
+	}
+	if matched == 0 {
+		return statusCmd(fmt.Sprintf("No files match %s", pattern), true)
+	}
+	return statusCmd(fmt.Sprintf("Selected %d file(s) matching %s", matched, pattern), false)
+}
+
+// createFromTemplate writes the suggested template as the .env.example of
+// the scan root and opens it, as if it had been picked.
+func (m PickerModel) createFromTemplate() tea.Cmd {
+	path := filepath.Join(m.rootDir, ".env.example")
+	if _, err := os.Stat(path); err == nil {
+		return statusCmd(fmt.Sprintf("%s already exists", path), true)
+	}
+	content, err := templates.Get(m.suggested.Name)
+	if err != nil {
+		return statusCmd(err.Error(), true)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return statusCmd(fmt.Sprintf("Failed to write %s: %v", path, err), true)
+	}
+	m.opts.recordWrite(history.OpGenerateExample, path, nil, readEntries(path), "")
+
+	return tea.Batch(
+		statusCmd(fmt.Sprintf("Created %s from the %s template", path, m.suggested.Title), false),
+		func() tea.Msg { return PickerFinishedMsg{Selected: []string{path}, Mode: m.mode} },
+	)
+}
+
 // Init initializes the picker model.
 func (m PickerModel) Init() tea.Cmd {
 	return nil
@@ -130,13 +395,52 @@ func (m PickerModel) Init() tea.Cmd {
 
 const pickerOverheadLines = 6 // title + padding + help + surrounding newlines
 
+// rows returns the indexes of the items not hidden inside a collapsed
+// directory, in display order.
+func (m PickerModel) rows() []int {
+	rows := make([]int, 0, len(m.items))
+	hideBelow := -1
+	for i, item := range m.items {
+		if hideBelow >= 0 {
+			if item.depth > hideBelow {
+				continue
+			}
+			hideBelow = -1
+		}
+		rows = append(rows, i)
+		if item.isDir && m.collapsed[item.dir] {
+			hideBelow = item.depth
+		}
+	}
+	return rows
+}
+
+// rowOf returns the row showing item i, or 0 if it is hidden.
+func rowOf(rows []int, i int) int {
+	for r, idx := range rows {
+		if idx == i {
+			return r
+		}
+	}
+	return 0
+}
+
 func (m PickerModel) visibleLines() int {
-	if m.windowHeight <= pickerOverheadLines {
-		return len(m.items)
+	n := len(m.rows())
+	overhead := pickerOverheadLines
+	if m.prompting {
+		overhead += 2 // blank line + prompt
+	}
+	if m.skipped != "" {
+		overhead += 2 // blank line + skipped directories warning
+	}
+	overhead += lipgloss.Height(shortHelp(pickerKeys(m.opts.Keys, m.prompting).short, m.windowWidth)) - 1
+	if m.windowHeight <= overhead {
+		return n
 	}
-	maxVisible := m.windowHeight - pickerOverheadLines
-	if maxVisible > len(m.items) {
-		return len(m.items)
+	maxVisible := m.windowHeight - overhead
+	if maxVisible > n {
+		return n
 	}
 	return maxVisible
 }
@@ -146,91 +450,232 @@ func (m *PickerModel) ensureCursorVisible() {
 	if visible <= 0 {
 		return
 	}
-	if m.cursor < m.offset {
-		m.offset = m.cursor
+	rows := m.rows()
+	row := rowOf(rows, m.cursor)
+	if row < m.offset {
+		m.offset = row
 	}
-	if m.cursor >= m.offset+visible {
-		m.offset = m.cursor - visible + 1
+	if row >= m.offset+visible {
+		m.offset = row - visible + 1
 	}
-	maxOffset := len(m.items) - visible
+	maxOffset := len(rows) - visible
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
 	if m.offset > maxOffset {
 		m.offset = maxOffset
 	}
+	m.loadBadges()
+}
+
+// moveCursor moves the cursor to the next visible item in the given
+// direction that is not a header, staying put if there is none.
+func (m *PickerModel) moveCursor(direction int) {
+	rows := m.rows()
+	for r := rowOf(rows, m.cursor) + direction; r >= 0 && r < len(rows); r += direction {
+		if !m.items[rows[r]].isHeader {
+			m.cursor = rows[r]
+			break
+		}
+	}
+	m.ensureCursorVisible()
 }
 
-// findNextSelectableItem finds the next item in the given direction
-// that is not a header, starting from the given index.
-func (m PickerModel) findNextSelectableItem(from int, direction int) int {
-	for i := from; i >= 0 && i < len(m.items); i += direction {
+// firstSelectable returns the first visible item that is not a header.
+func (m PickerModel) firstSelectable() int {
+	for _, i := range m.rows() {
 		if !m.items[i].isHeader {
 			return i
 		}
 	}
-	return from
+	return 0
+}
+
+// descendants returns the indexes of the items inside the directory node
+// at i.
+func (m PickerModel) descendants(i int) []int {
+	var idx []int
+	for j := i + 1; j < len(m.items) && m.items[j].depth > m.items[i].depth; j++ {
+		idx = append(idx, j)
+	}
+	return idx
+}
+
+// parentDir returns the directory node containing item i, or -1.
+func (m PickerModel) parentDir(i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if m.items[j].isDir && m.items[j].depth < m.items[i].depth {
+			return j
+		}
+	}
+	return -1
+}
+
+// toggle flips the selection of the file at i, or of every file inside the
+// directory at i: all of them are selected unless they already are.
+func (m *PickerModel) toggle(i int) {
+	item := m.items[i]
+	if item.isFile() {
+		m.selected[i] = !m.selected[i]
+		return
+	}
+	if !item.isDir {
+		return
+	}
+	files, selected := m.fileCounts(i)
+	for _, j := range m.descendants(i) {
+		if m.items[j].isFile() {
+			m.selected[j] = selected < files
+		}
+	}
+}
+
+// fileCounts returns how many files the directory node at i contains, and
+// how many of them are selected.
+func (m PickerModel) fileCounts(i int) (files, selected int) {
+	for _, j := range m.descendants(i) {
+		if m.items[j].isFile() {
+			files++
+			if m.selected[j] {
+				selected++
+			}
+		}
+	}
+	return files, selected
+}
+
+// collapse folds the directory at the cursor, or moves to the directory
+// containing the cursor if it is already folded or is a file.
+func (m *PickerModel) collapse() {
+	item := m.items[m.cursor]
+	if item.isDir && !m.collapsed[item.dir] {
+		if m.collapsed == nil {
+			m.collapsed = make(map[string]bool)
+		}
+		m.collapsed[item.dir] = true
+	} else if parent := m.parentDir(m.cursor); parent >= 0 {
+		m.cursor = parent
+	}
+	m.ensureCursorVisible()
+}
+
+// expand unfolds the directory at the cursor, or moves into it if it is
+// already unfolded.
+func (m *PickerModel) expand() {
+	item := m.items[m.cursor]
+	if !item.isDir {
+		return
+	}
+	if m.collapsed[item.dir] {
+		delete(m.collapsed, item.dir)
+	} else if m.cursor+1 < len(m.items) && m.items[m.cursor+1].depth > item.depth {
+		m.cursor++
+	}
+	m.ensureCursorVisible()
 }
 
 // Update handles messages and updates the picker model.
 func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case pickerInitMsg:
+		m.opts = msg.opts
 		m.items = msg.items
 		m.selected = msg.selected
 		m.mode = msg.mode
 		m.rootDir = msg.rootDir
+		m.suggested = msg.suggested
+		m.skipped = msg.skipped
+		m.badges = nil
+		m.cursor = 0
+		m.offset = 0
 		if len(m.items) > 0 {
-			m.cursor = m.findNextSelectableItem(0, 1)
+			m.cursor = m.firstSelectable()
 		}
 		m.ensureCursorVisible()
 		return m, nil
 
 	case tea.WindowSizeMsg:
 		m.windowHeight = msg.Height
+		m.windowWidth = msg.Width
 		m.ensureCursorVisible()
 		return m, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
-			if m.cursor > 0 {
-				newCursor := m.cursor - 1
-				m.cursor = m.findNextSelectableItem(newCursor, -1)
-				m.ensureCursorVisible()
+		if m.prompting {
+			switch {
+			case key.Matches(msg, m.opts.Keys.Picker.Confirm):
+				m.prompting = false
+				return m, m.selectPattern(m.pattern.Value())
+			case key.Matches(msg, m.opts.Keys.Picker.Cancel):
+				m.prompting = false
+				return m, nil
 			}
-		case "down", "j":
-			if m.cursor < len(m.items)-1 {
-				newCursor := m.cursor + 1
-				m.cursor = m.findNextSelectableItem(newCursor, 1)
-				m.ensureCursorVisible()
+			var cmd tea.Cmd
+			m.pattern, cmd = m.pattern.Update(msg)
+			return m, cmd
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.opts.Keys.Picker.Pattern):
+			if len(m.items) > 0 {
+				return m, m.openPrompt()
 			}
-		case " ":
-			if len(m.items) > 0 && !m.items[m.cursor].isHeader {
-				m.selected[m.cursor] = !m.selected[m.cursor]
+		case key.Matches(msg, m.opts.Keys.Picker.Up):
+			m.moveCursor(-1)
+		case key.Matches(msg, m.opts.Keys.Picker.Down):
+			m.moveCursor(1)
+		case key.Matches(msg, m.opts.Keys.Picker.Collapse):
+			if len(m.items) > 0 {
+				m.collapse()
 			}
-		case "a":
+		case key.Matches(msg, m.opts.Keys.Picker.Expand):
+			if len(m.items) > 0 {
+				m.expand()
+			}
+		case key.Matches(msg, m.opts.Keys.Picker.Toggle):
+			if len(m.items) > 0 {
+				m.toggle(m.cursor)
+			}
+		case key.Matches(msg, m.opts.Keys.Picker.All):
 			if len(m.items) > 0 {
 				allSelected := true
 				for i := range m.items {
-					if !m.items[i].isHeader && !m.selected[i] {
+					if m.items[i].isFile() && !m.selected[i] {
 						allSelected = false
 						break
 					}
 				}
 				for i := range m.items {
-					if !m.items[i].isHeader {
+					if m.items[i].isFile() {
 						m.selected[i] = !allSelected
 					}
 				}
 			}
-		case "enter":
+		case key.Matches(msg, m.opts.Keys.Picker.Confirm):
+			if len(m.items) == 0 && m.suggested.Name != "" {
+				return m, m.createFromTemplate()
+			}
 			var selectedFiles []string
 			for i := 0; i < len(m.items); i++ {
-				if !m.items[i].isHeader && m.selected[i] {
-					selectedFiles = append(selectedFiles, m.items[i].filePath)
+				if m.items[i].isFile() && m.selected[i] {
+					// Scans are relative to the root; the other screens
+					// expect paths relative to the working directory.
+					path := m.items[i].filePath
+					if m.rootDir != "" {
+						path = filepath.Join(m.rootDir, path)
+					}
+					selectedFiles = append(selectedFiles, path)
 				}
 			}
+			if m.mode == CompareFiles && len(selectedFiles) != 2 {
+				return m, statusCmd(fmt.Sprintf("Select exactly two files to compare (%d selected)", len(selectedFiles)), true)
+			}
+			if m.mode == MoveKeys && len(selectedFiles) != 1 {
+				return m, statusCmd(fmt.Sprintf("Select one file to move keys from (%d selected)", len(selectedFiles)), true)
+			}
 			if len(selectedFiles) > 0 {
 				return m, func() tea.Msg {
 					return PickerFinishedMsg{
@@ -239,9 +684,11 @@ func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
-		case "q", "esc":
+		case key.Matches(msg, m.opts.Keys.Help):
+			m.showHelp = true
+		case key.Matches(msg, m.opts.Keys.Picker.Back):
 			return m, nil
-		case "ctrl+c":
+		case key.Matches(msg, m.opts.Keys.Picker.Quit):
 			return m, tea.Quit
 		}
 	}
@@ -250,33 +697,52 @@ func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the file picker UI.
 func (m PickerModel) View() string {
+	if m.showHelp {
+		return helpOverlay(pickerKeys(m.opts.Keys, false), m.opts.Theme)
+	}
+
 	titleText := "Select .env files"
-	if m.mode == GenerateEnv {
+	switch m.mode {
+	case GenerateEnv:
 		titleText = "Select .env.example files"
+	case CompareFiles:
+		titleText = "Select two files to compare"
+	case MoveKeys:
+		titleText = "Select the file to move keys from"
 	}
 
 	title := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(m.opts.Theme.OnPrimary).
+		Background(m.opts.Theme.Primary).
 		Padding(0, 1).
 		Render(titleText)
 
 	fileCount := 0
 	for _, item := range m.items {
-		if !item.isHeader {
+		if item.isFile() {
 			fileCount++
 		}
 	}
 
 	if fileCount == 0 {
 		noFilesText := "No .env files found in current directory"
-		if m.mode == GenerateEnv {
+		switch m.mode {
+		case GenerateEnv:
 			noFilesText = "No .env.example files found in current directory"
+		case CompareFiles, MoveKeys:
+			noFilesText = "No env files found in current directory"
 		}
 		noFiles := lipgloss.NewStyle().
 			Faint(true).
 			Render(noFilesText)
+		if m.skipped != "" {
+			noFiles += "\n\n" + m.skippedWarning()
+		}
+		if m.suggested.Name != "" {
+			offer := fmt.Sprintf("No example found — create one from the %s template?", m.suggested.Title)
+			return "\n" + title + "\n\n" + noFiles + "\n\n" + offer + "\n\nPress Enter to create .env.example, q to return to menu"
+		}
 		return "\n" + title + "\n\n" + noFiles + "\n\nPress q to return to menu"
 	}
 
@@ -284,8 +750,11 @@ func (m PickerModel) View() string {
 
 	if fileCount == 1 {
 		fileType := ".env"
-		if m.mode == GenerateEnv {
+		switch m.mode {
+		case GenerateEnv:
 			fileType = ".env.example"
+		case CompareFiles, MoveKeys:
+			fileType = "env"
 		}
 		singleFileIndicator := lipgloss.NewStyle().
 			Faint(true).
@@ -293,10 +762,11 @@ func (m PickerModel) View() string {
 		list += singleFileIndicator + "\n\n"
 	}
 
+	rows := m.rows()
 	visible := m.visibleLines()
 	end := m.offset + visible
-	if end > len(m.items) {
-		end = len(m.items)
+	if end > len(rows) {
+		end = len(rows)
 	}
 
 	faintStyle := lipgloss.NewStyle().Faint(true)
@@ -305,41 +775,64 @@ func (m PickerModel) View() string {
 		list += faintStyle.Render("  ↑ more items above") + "\n"
 	}
 
-	for i := m.offset; i < end; i++ {
+	for _, i := range rows[m.offset:end] {
 		item := m.items[i]
+		indent := strings.Repeat("  ", item.depth)
 		if item.isHeader {
 			headerStyle := lipgloss.NewStyle().
 				Bold(true).
 				Faint(true).
 				PaddingLeft(2)
-			list += headerStyle.Render(item.text) + "\n"
-		} else {
-			cursor := " "
-			if i == m.cursor {
-				cursor = ">"
-			}
+			list += fitWidth(headerStyle.Render(item.text), m.windowWidth) + "\n"
+			continue
+		}
 
-			checkbox := "[ ]"
-			if m.selected[i] {
-				checkbox = "[x]"
-			}
+		cursor := " "
+		if i == m.cursor {
+			cursor = ">"
+		}
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			style = style.Foreground(m.opts.Theme.Primary).Bold(true)
+		}
 
-			style := lipgloss.NewStyle()
-			if i == m.cursor {
-				style = style.Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+		if item.isDir {
+			arrow := "▾"
+			if m.collapsed[item.dir] {
+				arrow = "▸"
 			}
+			files, selected := m.fileCounts(i)
+			count := faintStyle.Render(fmt.Sprintf("(%d/%d)", selected, files))
+			list += fitWidth(style.Render(cursor+" "+indent+arrow+" "+item.text)+" "+count, m.windowWidth) + "\n"
+			continue
+		}
 
-			list += style.Render(cursor+" "+checkbox+" "+item.text) + "\n"
+		checkbox := "[ ]"
+		if m.selected[i] {
+			checkbox = "[x]"
 		}
+		list += fitWidth(style.Render(cursor+" "+indent+checkbox+" "+item.text)+m.badges[item.filePath].render(badgeMode(m.mode, item.filePath), m.opts.Theme), m.windowWidth) + "\n"
 	}
 
-	if end < len(m.items) {
+	if end < len(rows) {
 		list += faintStyle.Render("  ↓ more items below") + "\n"
 	}
 
-	help := lipgloss.NewStyle().
-		Faint(true).
-		Render("↑/k: up • ↓/j: down • Space: toggle • a: all • Enter: confirm • q: back")
+	if m.skipped != "" {
+		list += "\n" + m.skippedWarning() + "\n"
+	}
+
+	if m.prompting {
+		list += "\n" + m.pattern.View() + "\n"
+	}
+
+	help := shortHelp(pickerKeys(m.opts.Keys, m.prompting).short, m.windowWidth)
 
 	return "\n" + title + "\n\n" + list + "\n" + help + "\n"
 }
+
+// skippedWarning tells the user that directories were left out of the scan,
+// so their files are not taken to be missing.
+func (m PickerModel) skippedWarning() string {
+	return fitWidth(lipgloss.NewStyle().Foreground(m.opts.Theme.Warning).Render("⚠ "+m.skipped), m.windowWidth)
+}
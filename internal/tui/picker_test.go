@@ -2,9 +2,13 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -18,6 +22,7 @@ func TestPickerModelInit(t *testing.T) {
 		cursor:   0,
 		mode:     GenerateExample,
 		rootDir:  "/test",
+		opts:     DefaultOptions(),
 	}
 
 	cmd := model.Init()
@@ -37,6 +42,7 @@ func TestPickerModelUpdateWithInitMsg(t *testing.T) {
 		selected: map[int]bool{0: true, 1: true},
 		mode:     GenerateEnv,
 		rootDir:  "/project",
+		opts:     DefaultOptions(),
 	}
 
 	newModel, cmd := model.Update(initMsg)
@@ -129,6 +135,7 @@ func TestPickerModelUpdateNavigation(t *testing.T) {
 				items:    tt.initialItems,
 				selected: make(map[int]bool),
 				cursor:   tt.initialCursor,
+				opts:     DefaultOptions(),
 			}
 
 			newModel, cmd := model.Update(tt.keyMsg)
@@ -154,6 +161,7 @@ func TestPickerModelUpdateToggleSelection(t *testing.T) {
 		},
 		selected: map[int]bool{0: true, 1: false},
 		cursor:   1,
+		opts:     DefaultOptions(),
 	}
 
 	spaceKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}
@@ -178,6 +186,7 @@ func TestPickerModelUpdateToggleSelectionFromTrue(t *testing.T) {
 		},
 		selected: map[int]bool{0: true, 1: true},
 		cursor:   1,
+		opts:     DefaultOptions(),
 	}
 
 	spaceKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}
@@ -204,6 +213,7 @@ func TestPickerModelUpdateEnterWithSelection(t *testing.T) {
 		selected: map[int]bool{0: true, 1: false, 2: true},
 		cursor:   1,
 		mode:     GenerateExample,
+		opts:     DefaultOptions(),
 	}
 
 	enterKey := tea.KeyMsg{Type: tea.KeyEnter}
@@ -235,9 +245,10 @@ func TestPickerModelUpdateEnterWithSelection(t *testing.T) {
 
 func TestGroupFilesByDirectory(t *testing.T) {
 	tests := []struct {
-		name     string
-		files    []string
-		expected []pickerItem
+		name       string
+		files      []string
+		workspaces []scanner.Workspace
+		expected   []pickerItem
 	}{
 		{
 			name:  "files in different directories",
@@ -262,6 +273,25 @@ func TestGroupFilesByDirectory(t *testing.T) {
 				{text: ".env.local", filePath: ".env.local", isHeader: false},
 			},
 		},
+		{
+			name:  "files grouped by workspace package",
+			files: []string{"apps/api/.env", "apps/api/config/.env.test", "apps/web/.env", "tools/.env", ".env"},
+			workspaces: []scanner.Workspace{
+				{Name: "@acme/api", Dir: "apps/api"},
+				{Name: "@acme/web", Dir: "apps/web"},
+			},
+			expected: []pickerItem{
+				{text: "Current Directory", filePath: "", isHeader: true},
+				{text: ".env", filePath: ".env", isHeader: false},
+				{text: "@acme/api", filePath: "", isHeader: true},
+				{text: "apps/api/.env", filePath: "apps/api/.env", isHeader: false},
+				{text: "apps/api/config/.env.test", filePath: "apps/api/config/.env.test", isHeader: false},
+				{text: "@acme/web", filePath: "", isHeader: true},
+				{text: "apps/web/.env", filePath: "apps/web/.env", isHeader: false},
+				{text: "tools", filePath: "", isHeader: true},
+				{text: "tools/.env", filePath: "tools/.env", isHeader: false},
+			},
+		},
 		{
 			name:     "empty list",
 			files:    []string{},
@@ -271,7 +301,7 @@ func TestGroupFilesByDirectory(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := groupFilesByDirectory(tt.files)
+			result := groupFilesByDirectory(tt.files, tt.workspaces)
 
 			if len(result) != len(tt.expected) {
 				t.Errorf("groupFilesByDirectory() returned %d items, expected %d", len(result), len(tt.expected))
@@ -301,6 +331,7 @@ func TestPickerModelUpdateEnterWithNoSelection(t *testing.T) {
 		selected: map[int]bool{0: false},
 		cursor:   0,
 		mode:     GenerateEnv,
+		opts:     DefaultOptions(),
 	}
 
 	enterKey := tea.KeyMsg{Type: tea.KeyEnter}
@@ -316,6 +347,7 @@ func TestPickerModelUpdateEmptyFilesNavigation(t *testing.T) {
 		items:    []pickerItem{},
 		selected: map[int]bool{},
 		cursor:   0,
+		opts:     DefaultOptions(),
 	}
 
 	tests := []struct {
@@ -364,6 +396,7 @@ func TestPickerModelScrolling(t *testing.T) {
 			selected:     selected,
 			cursor:       0,
 			windowHeight: 12,
+			opts:         DefaultOptions(),
 		}
 
 		for range 8 {
@@ -393,6 +426,7 @@ func TestPickerModelScrolling(t *testing.T) {
 			cursor:       10,
 			offset:       8,
 			windowHeight: 12,
+			opts:         DefaultOptions(),
 		}
 
 		for range 5 {
@@ -418,6 +452,7 @@ func TestPickerModelScrolling(t *testing.T) {
 			cursor:       19,
 			offset:       18,
 			windowHeight: 12,
+			opts:         DefaultOptions(),
 		}
 
 		newModel, _ := m.Update(tea.WindowSizeMsg{Height: 30})
@@ -440,6 +475,7 @@ func TestPickerModelScrolling(t *testing.T) {
 			selected:     selected,
 			cursor:       0,
 			windowHeight: 30,
+			opts:         DefaultOptions(),
 		}
 
 		view := m.View()
@@ -461,6 +497,7 @@ func TestPickerModelScrolling(t *testing.T) {
 			cursor:       10,
 			offset:       5,
 			windowHeight: 12,
+			opts:         DefaultOptions(),
 		}
 
 		view := m.View()
@@ -485,6 +522,7 @@ func TestPickerModelScrolling(t *testing.T) {
 			cursor:       19,
 			offset:       14,
 			windowHeight: 12,
+			opts:         DefaultOptions(),
 		}
 
 		view := m.View()
@@ -509,6 +547,7 @@ func TestPickerModelScrolling(t *testing.T) {
 			cursor:       5,
 			offset:       0,
 			windowHeight: 12,
+			opts:         DefaultOptions(),
 		}
 
 		view := m.View()
@@ -524,7 +563,7 @@ func TestPickerModelScrolling(t *testing.T) {
 
 func TestPickerModelSetWindowHeight(t *testing.T) {
 	t.Run("window height is set correctly", func(t *testing.T) {
-		m := &PickerModel{}
+		m := &PickerModel{opts: DefaultOptions()}
 
 		m.SetWindowHeight(42)
 
@@ -574,6 +613,7 @@ func TestPickerModelVisibleLines(t *testing.T) {
 			m := PickerModel{
 				items:        items,
 				windowHeight: tt.windowHeight,
+				opts:         DefaultOptions(),
 			}
 
 			visible := m.visibleLines()
@@ -658,6 +698,7 @@ func TestPickerModelUpdateSelectAllToggle(t *testing.T) {
 				items:    tt.items,
 				selected: tt.initialSelection,
 				cursor:   1,
+				opts:     DefaultOptions(),
 			}
 
 			aKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}
@@ -731,6 +772,7 @@ func TestPickerModelNavigationWithHeaders(t *testing.T) {
 				items:    tt.initialItems,
 				selected: make(map[int]bool),
 				cursor:   tt.initialCursor,
+				opts:     DefaultOptions(),
 			}
 
 			newModel, cmd := model.Update(tt.keyMsg)
@@ -795,6 +837,7 @@ func TestPickerModelInitMsgPositionsCursorAtFirstSelectable(t *testing.T) {
 				selected: selected,
 				mode:     GenerateEnv,
 				rootDir:  "/test",
+				opts:     DefaultOptions(),
 			}
 
 			newModel, cmd := model.Update(initMsg)
@@ -830,6 +873,7 @@ func TestPickerModelUpdateQuit(t *testing.T) {
 				},
 				selected: map[int]bool{0: true},
 				cursor:   0,
+				opts:     DefaultOptions(),
 			}
 
 			newModel, cmd := model.Update(tt.keyMsg)
@@ -842,3 +886,263 @@ func TestPickerModelUpdateQuit(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildTree(t *testing.T) {
+	files := []string{".env", "apps/api/.env", "apps/api/config/.env.test", "apps/web/.env", "services/backend/auth/.env"}
+	workspaces := []scanner.Workspace{{Name: "@acme/api", Dir: "apps/api"}}
+
+	got := buildTree(groupFilesByDirectory(files, workspaces))
+	want := []pickerItem{
+		{text: ".env", filePath: ".env"},
+		{text: "apps", isDir: true, dir: "apps"},
+		{text: "@acme/api", isDir: true, dir: "apps/api", depth: 1},
+		{text: ".env", filePath: "apps/api/.env", depth: 2},
+		{text: "config/.env.test", filePath: "apps/api/config/.env.test", depth: 2},
+		{text: "web", isDir: true, dir: "apps/web", depth: 1},
+		{text: ".env", filePath: "apps/web/.env", depth: 2},
+		{text: "services/backend/auth", isDir: true, dir: "services/backend/auth"},
+		{text: ".env", filePath: "services/backend/auth/.env", depth: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("buildTree() returned %d items, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("item %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPickerModelTree(t *testing.T) {
+	items := buildTree(groupFilesByDirectory([]string{"apps/api/.env", "apps/web/.env", "tools/.env"}, nil))
+	// apps, api, .env, web, .env, tools, .env
+	updated, _ := PickerModel{}.Update(pickerInitMsg{items: items, selected: map[int]bool{}, opts: DefaultOptions()})
+	m := updated.(PickerModel)
+	press := func(k tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(k)
+		m = updated.(PickerModel)
+	}
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	space := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}
+
+	if m.cursor != 0 || !m.items[0].isDir {
+		t.Fatalf("cursor = %d, want the first directory", m.cursor)
+	}
+
+	press(space)
+	if !m.selected[2] || !m.selected[4] || m.selected[6] {
+		t.Errorf("Space on a directory should select the files inside it, got %v", m.selected)
+	}
+	if !strings.Contains(m.View(), "apps (2/2)") {
+		t.Errorf("View() should count selected files per directory:\n%s", m.View())
+	}
+	press(space)
+	if m.selected[2] || m.selected[4] {
+		t.Errorf("Space on a fully selected directory should deselect it, got %v", m.selected)
+	}
+
+	press(left)
+	if !m.collapsed["apps"] || len(m.rows()) != 3 {
+		t.Fatalf("Left should collapse apps, rows = %v", m.rows())
+	}
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	if m.cursor != 5 {
+		t.Errorf("Down should skip the collapsed directory's items, cursor = %d", m.cursor)
+	}
+	if !strings.Contains(m.View(), "▸ apps (0/2)") {
+		t.Errorf("View() should mark apps collapsed:\n%s", m.View())
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyUp})
+	press(right)
+	if m.collapsed["apps"] {
+		t.Error("Right should expand apps")
+	}
+	press(right)
+	if m.cursor != 1 {
+		t.Errorf("Right on an expanded directory should move into it, cursor = %d", m.cursor)
+	}
+	press(right)
+	press(left)
+	if m.cursor != 1 {
+		t.Errorf("Left on a file should move to its directory, cursor = %d", m.cursor)
+	}
+
+	// Collapsed directories are remembered when the picker is rescanned.
+	press(left)
+	updated, _ = m.Update(pickerInitMsg{items: items, selected: map[int]bool{}, opts: DefaultOptions()})
+	m = updated.(PickerModel)
+	if !m.collapsed["apps/api"] {
+		t.Error("collapsed state should survive a rescan")
+	}
+}
+
+func TestPickerModelSelectByPattern(t *testing.T) {
+	items := buildTree(groupFilesByDirectory([]string{".env", "services/api/.env", "services/web/.env", "services/web/.env.local"}, nil))
+	updated, _ := PickerModel{}.Update(pickerInitMsg{items: items, selected: map[int]bool{}, opts: DefaultOptions()})
+	m := updated.(PickerModel)
+	var last tea.Cmd
+	press := func(msgs ...tea.KeyMsg) {
+		t.Helper()
+		for _, k := range msgs {
+			var updated tea.Model
+			updated, last = m.Update(k)
+			m = updated.(PickerModel)
+		}
+	}
+	typed := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	press(typed("*"))
+	if !m.PromptVisible() || !strings.Contains(m.View(), "Select files matching") {
+		t.Fatalf("'*' should open the pattern prompt:\n%s", m.View())
+	}
+	press(typed("services/*/.env"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.PromptVisible() {
+		t.Fatal("Enter should close the prompt")
+	}
+
+	var selected []string
+	for i, item := range m.items {
+		if m.selected[i] {
+			selected = append(selected, item.filePath)
+		}
+	}
+	if want := []string{"services/api/.env", "services/web/.env"}; strings.Join(selected, ",") != strings.Join(want, ",") {
+		t.Errorf("selected = %v, want %v", selected, want)
+	}
+	if status := statusOf(t, last); status.Text != "Selected 2 file(s) matching services/*/.env" || status.Error {
+		t.Errorf("status = %+v, want the match count", status)
+	}
+
+	// "q" and "a" are typed into the prompt rather than acted on.
+	press(typed("*"), typed("qa"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.PromptVisible() || m.pattern.Value() != "qa" {
+		t.Errorf("Esc should cancel the prompt after typing, value = %q", m.pattern.Value())
+	}
+
+	press(typed("*"), typed("nothing/*"), tea.KeyMsg{Type: tea.KeyEnter})
+	if status := statusOf(t, last); status.Text != "No files match nothing/*" || !status.Error {
+		t.Errorf("status = %+v, want an error for no matches", status)
+	}
+}
+
+func TestPickerCompareMode(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".env", ".env.example", ".env.production", ".envrc"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("KEY=value\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	updated, _ := PickerModel{}.Update(NewPickerModel(CompareFiles, dir, DefaultOptions())())
+	m := updated.(PickerModel)
+	if files, _ := m.Counts(); files != 4 {
+		t.Fatalf("compare picker lists %d files, want env files, examples and .envrc", files)
+	}
+	if !strings.Contains(m.View(), "Select two files to compare") {
+		t.Errorf("View() should ask for two files:\n%s", m.View())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if status := statusOf(t, cmd); !status.Error || !strings.Contains(status.Text, "exactly two") {
+		t.Errorf("confirming one file = %+v, want an error", status)
+	}
+
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeySpace})
+	_, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if finished, ok := cmd().(PickerFinishedMsg); !ok || len(finished.Selected) != 2 || finished.Mode != CompareFiles {
+		t.Errorf("confirming two files = %+v, want both selected for comparison", finished)
+	}
+}
+
+func TestPickerMoveMode(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".env", ".env.example", ".envrc"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("KEY=value\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	updated, _ := PickerModel{}.Update(NewPickerModel(MoveKeys, dir, DefaultOptions())())
+	m := updated.(PickerModel)
+	if files, _ := m.Counts(); files != 2 {
+		t.Errorf("move picker lists %d files, a read-only .envrc should not be one", files)
+	}
+	if !strings.Contains(m.View(), "Select the file to move keys from") {
+		t.Errorf("View() should ask for the source file:\n%s", m.View())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	_, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if status := statusOf(t, cmd); !status.Error || !strings.Contains(status.Text, "Select one file") {
+		t.Errorf("confirming two files = %+v, want an error", status)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	_, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if finished, ok := cmd().(PickerFinishedMsg); !ok || len(finished.Selected) != 1 || finished.Mode != MoveKeys {
+		t.Errorf("confirming one file = %+v, want it picked as the source", finished)
+	}
+}
+
+func TestPickerSuggestsTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"dependencies": {"next": "15.0.0", "stripe": "17.0.0"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := PickerModel{}.Update(NewPickerModel(GenerateEnv, dir, DefaultOptions())())
+	m := updated.(PickerModel)
+	if !strings.Contains(m.View(), "create one from the Next.js template?") {
+		t.Fatalf("View() should offer the Next.js template:\n%s", m.View())
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("confirming = %T, want a status and the picked example", cmd())
+	}
+	path := filepath.Join(dir, ".env.example")
+	var finished PickerFinishedMsg
+	for _, c := range batch {
+		if msg, ok := c().(PickerFinishedMsg); ok {
+			finished = msg
+		}
+	}
+	if len(finished.Selected) != 1 || finished.Selected[0] != path || finished.Mode != GenerateEnv {
+		t.Errorf("finished = %+v, want the new example picked", finished)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(content), "NEXTAUTH_SECRET=") {
+		t.Errorf(".env.example = %q, %v, want the Next.js template", content, err)
+	}
+
+	updated, _ = PickerModel{}.Update(NewPickerModel(GenerateEnv, t.TempDir(), DefaultOptions())())
+	if view := updated.(PickerModel).View(); strings.Contains(view, "template") {
+		t.Errorf("View() should not offer a template without a known stack:\n%s", view)
+	}
+}
+
+func TestPickerWarnsAboutSkippedDirectories(t *testing.T) {
+	const warning = "2 directories skipped (permission denied)"
+	items := buildTree(groupFilesByDirectory([]string{".env"}, nil))
+	updated, _ := PickerModel{}.Update(pickerInitMsg{items: items, selected: map[int]bool{}, skipped: warning, opts: DefaultOptions()})
+	if view := updated.(PickerModel).View(); !strings.Contains(view, warning) {
+		t.Errorf("View() should warn about skipped directories:\n%s", view)
+	}
+
+	updated, _ = PickerModel{}.Update(pickerInitMsg{selected: map[int]bool{}, skipped: warning, opts: DefaultOptions()})
+	view := updated.(PickerModel).View()
+	if !strings.Contains(view, "No .env files found") || !strings.Contains(view, warning) {
+		t.Errorf("View() without files should still warn about skipped directories:\n%s", view)
+	}
+
+	updated, _ = PickerModel{}.Update(pickerInitMsg{items: items, selected: map[int]bool{}, opts: DefaultOptions()})
+	if view := updated.(PickerModel).View(); strings.Contains(view, "skipped") {
+		t.Errorf("View() should not warn when nothing was skipped:\n%s", view)
+	}
+}
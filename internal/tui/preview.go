@@ -2,15 +2,18 @@
 package tui
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/jellydn/dotenv-tui/internal/backup"
 	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/lint"
 	"github.com/jellydn/dotenv-tui/internal/parser"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -18,9 +21,14 @@ import (
 type filePreview struct {
 	filePath         string
 	outputPath       string
+	originalEntries  []parser.Entry
 	generatedEntries []parser.Entry
 	diffLines        []string
+	lint             []lint.Finding
 	errMsg           string
+	// backup is whether the settings for the file's directory back up
+	// the example it replaces.
+	backup bool
 }
 
 // PreviewModel is the Bubble Tea model for previewing .env.example diffs.
@@ -32,7 +40,11 @@ type PreviewModel struct {
 	written      bool
 	writeResults []writeResult
 	windowHeight int
-	enableBackup bool
+	windowWidth  int
+	xOffset      int    // columns the diff is scrolled right by
+	backups      []bool // whether to back up each file before writing
+	showHelp     bool
+	opts         Options
 }
 
 type writeResult struct {
@@ -49,23 +61,24 @@ type PreviewFinishedMsg struct {
 type previewInitMsg struct {
 	files        []filePreview
 	enableBackup bool
+	opts         Options
 }
 
 // NewPreviewModel creates a preview for multiple files at once.
-func NewPreviewModel(filePaths []string, enableBackup bool) tea.Cmd {
+func NewPreviewModel(filePaths []string, enableBackup bool, opts Options) tea.Cmd {
 	return func() tea.Msg {
 		var files []filePreview
 		for _, fp := range filePaths {
-			files = append(files, loadFilePreview(fp))
+			files = append(files, opts.loadFilePreview(fp))
 		}
-		return previewInitMsg{files: files, enableBackup: enableBackup}
+		return previewInitMsg{files: files, enableBackup: enableBackup, opts: opts}
 	}
 }
 
-func loadFilePreview(filePath string) filePreview {
+func (o Options) loadFilePreview(filePath string) filePreview {
 	outputPath := filepath.Join(filepath.Dir(filePath), ".env.example")
 
-	file, err := os.Open(filePath)
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return filePreview{
 			filePath:   filePath,
@@ -74,9 +87,8 @@ func loadFilePreview(filePath string) filePreview {
 			errMsg:     err.Error(),
 		}
 	}
-	defer func() { _ = file.Close() }()
 
-	originalEntries, err := parser.Parse(file)
+	originalEntries, err := parser.Parse(bytes.NewReader(data))
 	if err != nil {
 		return filePreview{
 			filePath:   filePath,
@@ -86,28 +98,73 @@ func loadFilePreview(filePath string) filePreview {
 		}
 	}
 
-	generatedEntries := generator.GenerateExample(originalEntries)
-
-	var diffLines []string
-	for i, orig := range originalEntries {
-		if i < len(generatedEntries) {
-			origLine := parser.EntryToString(orig)
-			genLine := parser.EntryToString(generatedEntries[i])
-
-			if origLine == genLine {
-				diffLines = append(diffLines, fmt.Sprintf("  %s", origLine))
-			} else {
-				diffLines = append(diffLines, fmt.Sprintf("  %s [masked]", genLine))
-			}
+	settings, err := o.settingsFor(filePath)
+	if err != nil {
+		return filePreview{
+			filePath:   filePath,
+			outputPath: outputPath,
+			diffLines:  []string{fmt.Sprintf("Error loading settings: %v", err)},
+			errMsg:     err.Error(),
 		}
 	}
+	generatedEntries := generator.GenerateExampleWithOptions(originalEntries, settings.Example)
+	if settings.Example.Changelog {
+		generatedEntries = generator.WithChangelog(currentExample(outputPath), generatedEntries, o.today())
+	}
+	findings, _ := lint.Check(filepath.Base(filePath), data, o.Lint)
 
 	return filePreview{
 		filePath:         filePath,
 		outputPath:       outputPath,
+		originalEntries:  originalEntries,
 		generatedEntries: generatedEntries,
-		diffLines:        diffLines,
+		diffLines:        o.diffLines(originalEntries, generatedEntries),
+		lint:             findings,
+		backup:           settings.Backup,
+	}
+}
+
+// currentExample returns the entries of the example at path, or nil when
+// it does not exist or cannot be parsed.
+func currentExample(path string) []parser.Entry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	entries, err := parser.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil
 	}
+	return entries
+}
+
+// diffLines renders the generated entries, marking values that differ from
+// the original file's with the detector's reason for masking them.
+func (o Options) diffLines(original, generated []parser.Entry) []string {
+	values := make(map[string]parser.KeyValue)
+	for _, e := range original {
+		if kv, ok := e.(parser.KeyValue); ok {
+			if _, seen := values[kv.Key]; !seen {
+				values[kv.Key] = kv
+			}
+		}
+	}
+	var lines []string
+	for _, e := range generated {
+		line := parser.EntryToStringWithOptions(e, o.Write)
+		if kv, ok := e.(parser.KeyValue); ok {
+			if orig, found := values[kv.Key]; found && parser.EntryToStringWithOptions(orig, o.Write) != line {
+				if c := o.Example.Masking.Rules.Classify(orig.Key, orig.Value); c.IsSecret {
+					lines = append(lines, fmt.Sprintf("  %s [masked: %s]", line, c.Reason))
+				} else {
+					lines = append(lines, fmt.Sprintf("  %s [masked]", line))
+				}
+				continue
+			}
+		}
+		lines = append(lines, fmt.Sprintf("  %s", line))
+	}
+	return lines
 }
 
 // Init initializes the preview model.
@@ -120,13 +177,65 @@ func (m *PreviewModel) SetWindowHeight(h int) {
 	m.windowHeight = h
 }
 
+// SetWindowWidth sets the terminal width diff lines are cut to.
+func (m *PreviewModel) SetWindowWidth(w int) {
+	m.windowWidth = w
+}
+
+// horizontalScrollStep is how many columns ←/→ scroll the diff by.
+const horizontalScrollStep = 8
+
+// diffWidth is the width left to diff lines after the cursor column, or
+// zero if the terminal width is not known.
+func (m PreviewModel) diffWidth() int {
+	if m.windowWidth <= 0 {
+		return 0
+	}
+	return max(m.windowWidth-2, 1)
+}
+
+// maxXOffset is the furthest the current file's diff can scroll right while
+// its longest line still fills the screen.
+func (m PreviewModel) maxXOffset() int {
+	if len(m.files) == 0 || m.diffWidth() == 0 {
+		return 0
+	}
+	longest := 0
+	for _, line := range m.files[m.currentFile].diffLines {
+		longest = max(longest, lipgloss.Width(line))
+	}
+	return max(longest-m.diffWidth()+1, 0)
+}
+
 const previewOverheadLines = 8 // title + position + 2 newlines + scroll info + help + 2 newlines
 
+// maxLintLines is the most lint findings shown below the diff.
+const maxLintLines = 3
+
 func (m PreviewModel) visibleLines() int {
-	if m.windowHeight <= previewOverheadLines {
+	overhead := previewOverheadLines + lipgloss.Height(shortHelp(previewKeys(m.opts.Keys, len(m.files) > 1).short, m.windowWidth)) - 1
+	if len(m.files) > 0 {
+		if n := len(lintLines(m.files[m.currentFile].lint)); n > 0 {
+			overhead += n + 1
+		}
+	}
+	if m.windowHeight <= overhead {
 		return 10 // fallback to default if window is too small
 	}
-	return m.windowHeight - previewOverheadLines
+	return m.windowHeight - overhead
+}
+
+// lintLines formats up to maxLintLines findings, plus a count of the rest.
+func lintLines(findings []lint.Finding) []string {
+	var lines []string
+	for i, f := range findings {
+		if i == maxLintLines {
+			lines = append(lines, fmt.Sprintf("  … and %d more (run --lint for all)", len(findings)-maxLintLines))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  line %d %s: %s", f.Line, f.Rule, f.Message))
+	}
+	return lines
 }
 
 func (m *PreviewModel) adjustScroll() {
@@ -146,37 +255,60 @@ func (m *PreviewModel) switchFile(dir int) {
 	m.currentFile = (m.currentFile + dir + n) % n
 	m.cursor = 0
 	m.scrollOffset = 0
+	m.xOffset = 0
 }
 
 // Update handles messages and updates the preview model.
 func (m PreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case previewInitMsg:
+		m.opts = msg.opts
 		m.files = msg.files
 		m.currentFile = 0
 		m.cursor = 0
 		m.scrollOffset = 0
 		m.written = false
 		m.writeResults = nil
-		m.enableBackup = msg.enableBackup
+		m.backups = make([]bool, len(msg.files))
+		for i := range m.backups {
+			m.backups[i] = msg.enableBackup && msg.files[i].backup
+		}
 		return m, nil
 
 	case tea.WindowSizeMsg:
 		m.windowHeight = msg.Height
+		m.windowWidth = msg.Width
+		m.xOffset = min(m.xOffset, m.maxXOffset())
 		m.adjustScroll()
 		return m, nil
 
+	case editorFinishedMsg:
+		entries, err := readEdited(msg)
+		if err != nil {
+			return m, statusCmd(err.Error(), true)
+		}
+		f := &m.files[m.currentFile]
+		f.generatedEntries = entries
+		f.diffLines = m.opts.diffLines(f.originalEntries, entries)
+		m.cursor = min(m.cursor, max(len(f.diffLines)-1, 0))
+		m.adjustScroll()
+		return m, statusCmd("Edited "+filepath.ToSlash(f.outputPath), false)
+
 	case tea.KeyMsg:
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
 		if len(m.files) == 0 {
-			if msg.String() == "q" || msg.String() == "esc" {
+			if key.Matches(msg, m.opts.Keys.Preview.Cancel) {
 				return m, func() tea.Msg { return PreviewFinishedMsg{} }
 			}
 			return m, nil
 		}
 
 		if m.written {
-			switch msg.String() {
-			case "enter", "q", "esc":
+			if key.Matches(msg, m.opts.Keys.Preview.Done) {
 				return m, func() tea.Msg {
 					return PreviewFinishedMsg{Results: m.writeResults}
 				}
@@ -184,26 +316,38 @@ func (m PreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		switch msg.String() {
-		case "tab":
+		switch {
+		case key.Matches(msg, m.opts.Keys.Preview.NextFile):
 			m.switchFile(1)
-		case "shift+tab":
+		case key.Matches(msg, m.opts.Keys.Preview.PrevFile):
 			m.switchFile(-1)
-		case "up", "k":
+		case key.Matches(msg, m.opts.Keys.Preview.Up):
 			if m.cursor > 0 {
 				m.cursor--
 				m.adjustScroll()
 			}
-		case "down", "j":
+		case key.Matches(msg, m.opts.Keys.Preview.Down):
 			f := m.files[m.currentFile]
 			if m.cursor < len(f.diffLines)-1 {
 				m.cursor++
 				m.adjustScroll()
 			}
-		case "enter":
+		case key.Matches(msg, m.opts.Keys.Preview.Left):
+			m.xOffset = max(m.xOffset-horizontalScrollStep, 0)
+		case key.Matches(msg, m.opts.Keys.Preview.Right):
+			m.xOffset = min(m.xOffset+horizontalScrollStep, m.maxXOffset())
+		case key.Matches(msg, m.opts.Keys.Preview.Backup):
+			m.toggleBackup()
+		case key.Matches(msg, m.opts.Keys.Preview.Editor):
+			if f := m.files[m.currentFile]; f.errMsg == "" {
+				return m, m.opts.editEntries(f.generatedEntries)
+			}
+		case key.Matches(msg, m.opts.Keys.Preview.Write):
 			m.writeResults = m.writeAllFiles()
 			m.written = true
-		case "q", "esc":
+		case key.Matches(msg, m.opts.Keys.Help):
+			m.showHelp = true
+		case key.Matches(msg, m.opts.Keys.Preview.Cancel):
 			return m, func() tea.Msg {
 				return PreviewFinishedMsg{}
 			}
@@ -212,9 +356,22 @@ func (m PreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// backupFor reports whether the file at index i is backed up before writing.
+func (m PreviewModel) backupFor(i int) bool {
+	return i < len(m.backups) && m.backups[i]
+}
+
+// toggleBackup flips the backup choice for the file being previewed.
+func (m *PreviewModel) toggleBackup() {
+	if len(m.backups) < len(m.files) {
+		m.backups = append(m.backups, make([]bool, len(m.files)-len(m.backups))...)
+	}
+	m.backups[m.currentFile] = !m.backups[m.currentFile]
+}
+
 func (m PreviewModel) writeAllFiles() []writeResult {
 	var results []writeResult
-	for _, f := range m.files {
+	for i, f := range m.files {
 		if f.errMsg != "" {
 			results = append(results, writeResult{
 				OutputPath: f.outputPath,
@@ -223,7 +380,7 @@ func (m PreviewModel) writeAllFiles() []writeResult {
 			})
 			continue
 		}
-		err := m.writePreviewFile(f.outputPath, f.generatedEntries)
+		err := m.opts.writePreviewFile(f.outputPath, f.generatedEntries, m.backupFor(i))
 		if err != nil {
 			results = append(results, writeResult{
 				OutputPath: f.outputPath,
@@ -240,10 +397,12 @@ func (m PreviewModel) writeAllFiles() []writeResult {
 	return results
 }
 
-func (m PreviewModel) writePreviewFile(outputPath string, entries []parser.Entry) error {
-	if m.enableBackup {
+func (o Options) writePreviewFile(outputPath string, entries []parser.Entry, createBackup bool) error {
+	before := readEntries(outputPath)
+	var backupPath string
+	if createBackup {
 		if _, err := os.Stat(outputPath); err == nil {
-			if _, err := backup.CreateBackup(outputPath); err != nil {
+			if backupPath, err = o.backupOptions(outputPath).Create(outputPath); err != nil {
 				return fmt.Errorf("failed to create backup: %w", err)
 			}
 		}
@@ -254,11 +413,19 @@ func (m PreviewModel) writePreviewFile(outputPath string, entries []parser.Entry
 		return err
 	}
 	defer func() { _ = file.Close() }()
-	return parser.Write(file, entries)
+	if err := parser.WriteWithOptions(file, entries, o.Write); err != nil {
+		return err
+	}
+	o.recordWrite(history.OpGenerateExample, outputPath, before, entries, backupPath)
+	return nil
 }
 
 // View renders the diff preview UI.
 func (m PreviewModel) View() string {
+	if m.showHelp {
+		return helpOverlay(previewKeys(m.opts.Keys, len(m.files) > 1), m.opts.Theme)
+	}
+
 	if len(m.files) == 0 {
 		return "\nNo files to preview\n"
 	}
@@ -269,17 +436,17 @@ func (m PreviewModel) View() string {
 
 	f := m.files[m.currentFile]
 
-	positionText := fmt.Sprintf("[%d/%d] %s", m.currentFile+1, len(m.files), f.filePath)
+	positionText := fmt.Sprintf("[%d/%d] %s  backup: %s", m.currentFile+1, len(m.files), filepath.ToSlash(f.filePath), onOff(m.backupFor(m.currentFile)))
 	title := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(m.opts.Theme.OnPrimary).
+		Background(m.opts.Theme.Primary).
 		Padding(0, 1).
 		Render("Preview .env.example generation")
 
 	position := lipgloss.NewStyle().
 		Faint(true).
-		Render(positionText)
+		Render(fitWidth(positionText, m.windowWidth))
 
 	var diff strings.Builder
 
@@ -298,17 +465,17 @@ func (m PreviewModel) View() string {
 		}
 
 		style := lipgloss.NewStyle()
-		if strings.Contains(line, "[masked]") {
-			style = style.Foreground(lipgloss.Color("#FFFF00"))
+		if strings.Contains(line, " [masked") {
+			style = style.Foreground(m.opts.Theme.Warning)
 		} else {
-			style = style.Foreground(lipgloss.Color("#00FF00"))
+			style = style.Foreground(m.opts.Theme.Success)
 		}
 
 		if i == m.cursor {
-			style = style.Bold(true).Background(lipgloss.Color("#7D56F4"))
+			style = style.Bold(true).Background(m.opts.Theme.Primary)
 		}
 
-		diff.WriteString(style.Render(cursor+" "+line) + "\n")
+		diff.WriteString(style.Render(cursor+" "+scrollWidth(line, m.xOffset, m.diffWidth())) + "\n")
 	}
 
 	if len(f.diffLines) > visible {
@@ -316,15 +483,21 @@ func (m PreviewModel) View() string {
 		diff.WriteString(lipgloss.NewStyle().Faint(true).Render(scrollInfo) + "\n")
 	}
 
-	helpParts := []string{"↑/k: up", "↓/j: down"}
-	if len(m.files) > 1 {
-		helpParts = append(helpParts, "Tab: next file", "Shift+Tab: prev file")
+	if lines := lintLines(f.lint); len(lines) > 0 {
+		diff.WriteString("\n")
+		for i, line := range lines {
+			style := lipgloss.NewStyle().Faint(true)
+			if i < len(f.lint) && i < maxLintLines {
+				style = lipgloss.NewStyle().Foreground(m.opts.Theme.Warning)
+				if f.lint[i].Severity == lint.SeverityError {
+					style = style.Foreground(m.opts.Theme.Error)
+				}
+			}
+			diff.WriteString(style.Render(fitWidth(line, m.windowWidth)) + "\n")
+		}
 	}
-	helpParts = append(helpParts, "Enter: write all", "q/Esc: cancel")
 
-	help := lipgloss.NewStyle().
-		Faint(true).
-		Render(strings.Join(helpParts, " • "))
+	help := shortHelp(previewKeys(m.opts.Keys, len(m.files) > 1).short, m.windowWidth)
 
 	return "\n" + title + "\n" + position + "\n\n" + diff.String() + "\n" + help + "\n"
 }
@@ -332,8 +505,8 @@ func (m PreviewModel) View() string {
 func (m PreviewModel) viewWriteResults() string {
 	title := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(m.opts.Theme.OnPrimary).
+		Background(m.opts.Theme.Primary).
 		Padding(0, 1).
 		Render(".env.example Generation Complete")
 
@@ -343,13 +516,13 @@ func (m PreviewModel) viewWriteResults() string {
 		if r.Success {
 			successCount++
 			line := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#00FF00")).
-				Render(fmt.Sprintf("  ✓ %s", r.OutputPath))
+				Foreground(m.opts.Theme.Success).
+				Render(fmt.Sprintf("  ✓ %s", filepath.ToSlash(r.OutputPath)))
 			lines.WriteString(line + "\n")
 		} else {
 			line := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FF5F56")).
-				Render(fmt.Sprintf("  ✗ %s: %s", r.OutputPath, r.Error))
+				Foreground(m.opts.Theme.Error).
+				Render(fmt.Sprintf("  ✗ %s: %s", filepath.ToSlash(r.OutputPath), r.Error))
 			lines.WriteString(line + "\n")
 		}
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/jellydn/dotenv-tui/internal/parser"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestEntryToString(t *testing.T) {
@@ -89,7 +90,7 @@ func TestPreviewModelUpdateWithInitMsg(t *testing.T) {
 		},
 	}
 
-	initMsg := previewInitMsg{files: files}
+	initMsg := previewInitMsg{files: files, opts: DefaultOptions()}
 
 	newModel, cmd := model.Update(initMsg)
 
@@ -120,6 +121,7 @@ func TestPreviewModelUpdateNavigation(t *testing.T) {
 		currentFile:  0,
 		cursor:       1,
 		scrollOffset: 0,
+		opts:         DefaultOptions(),
 	}
 
 	tests := []struct {
@@ -226,6 +228,7 @@ func TestPreviewModelUpdateEnterWritesAll(t *testing.T) {
 		currentFile:  0,
 		cursor:       0,
 		scrollOffset: 0,
+		opts:         DefaultOptions(),
 	}
 
 	enterKey := tea.KeyMsg{Type: tea.KeyEnter}
@@ -268,6 +271,7 @@ func TestPreviewModelUpdateQuit(t *testing.T) {
 				currentFile:  0,
 				cursor:       0,
 				scrollOffset: 0,
+				opts:         DefaultOptions(),
 			}
 
 			_, cmd := model.Update(tt.keyMsg)
@@ -295,6 +299,7 @@ func TestPreviewModelTabSwitchesFile(t *testing.T) {
 		currentFile:  0,
 		cursor:       0,
 		scrollOffset: 0,
+		opts:         DefaultOptions(),
 	}
 
 	tabKey := tea.KeyMsg{Type: tea.KeyTab}
@@ -322,6 +327,7 @@ func TestPreviewModelShiftTabSwitchesFile(t *testing.T) {
 		currentFile:  0,
 		cursor:       0,
 		scrollOffset: 0,
+		opts:         DefaultOptions(),
 	}
 
 	shiftTabKey := tea.KeyMsg{Type: tea.KeyShiftTab}
@@ -343,6 +349,7 @@ func TestPreviewModelWrittenStateEnterFinishes(t *testing.T) {
 		writeResults: []writeResult{
 			{OutputPath: "/test/.env.example", Success: true},
 		},
+		opts: DefaultOptions(),
 	}
 
 	enterKey := tea.KeyMsg{Type: tea.KeyEnter}
@@ -404,7 +411,7 @@ func TestEntryToStringComplex(t *testing.T) {
 
 func TestPreviewModelSetWindowHeight(t *testing.T) {
 	t.Run("window height is set correctly", func(t *testing.T) {
-		m := &PreviewModel{}
+		m := &PreviewModel{opts: DefaultOptions()}
 
 		m.SetWindowHeight(50)
 
@@ -424,6 +431,7 @@ func TestPreviewModelWindowSizeMsg(t *testing.T) {
 			cursor:       0,
 			scrollOffset: 0,
 			windowHeight: 20,
+			opts:         DefaultOptions(),
 		}
 
 		newModel, _ := model.Update(tea.WindowSizeMsg{Height: 40})
@@ -448,6 +456,7 @@ func TestPreviewModelWindowSizeMsg(t *testing.T) {
 			cursor:       30,
 			scrollOffset: 25,
 			windowHeight: 20,
+			opts:         DefaultOptions(),
 		}
 
 		newModel, _ := model.Update(tea.WindowSizeMsg{Height: 40})
@@ -495,7 +504,7 @@ func TestPreviewModelVisibleLines(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := PreviewModel{windowHeight: tt.windowHeight}
+			m := PreviewModel{windowHeight: tt.windowHeight, opts: DefaultOptions()}
 			visible := m.visibleLines()
 
 			if visible != tt.expected {
@@ -504,3 +513,92 @@ func TestPreviewModelVisibleLines(t *testing.T) {
 		})
 	}
 }
+
+func TestPreviewModelBackupTogglePerFile(t *testing.T) {
+	tempDir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"api", "web"} {
+		dir := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("KEY=value\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, ".env.example"), []byte("KEY=old\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, filepath.Join(dir, ".env"))
+	}
+
+	updated, _ := PreviewModel{}.Update(NewPreviewModel(paths, true, DefaultOptions())())
+	m := updated.(PreviewModel)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	m = updated.(PreviewModel)
+	if !strings.Contains(m.View(), "backup: off") {
+		t.Errorf("View() should show backup off for the toggled file")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(PreviewModel)
+
+	for i, name := range []string{"api", "web"} {
+		backups, _ := filepath.Glob(filepath.Join(tempDir, name, ".env.example.bak.*"))
+		if want := i == 1; (len(backups) > 0) != want {
+			t.Errorf("%s: backups = %v, want backup %v", name, backups, want)
+		}
+	}
+}
+
+func TestPreviewModelShowsLintFindings(t *testing.T) {
+	tempDir := t.TempDir()
+	envPath := filepath.Join(tempDir, ".env")
+	if err := os.WriteFile(envPath, []byte("api_key=secret\nPORT=3000 \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := PreviewModel{}.Update(NewPreviewModel([]string{envPath}, false, DefaultOptions())())
+	m := updated.(PreviewModel)
+	m.SetWindowHeight(30)
+
+	view := m.View()
+	for _, want := range []string{"line 1 key-naming", "line 2 trailing-whitespace"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q:\n%s", want, view)
+		}
+	}
+	if got, want := m.visibleLines(), 30-previewOverheadLines-3; got != want {
+		t.Errorf("visibleLines() = %d, want %d", got, want)
+	}
+}
+
+func TestPreviewHorizontalScroll(t *testing.T) {
+	long := "SECRET=" + strings.Repeat("x", 60) + "END"
+	m := PreviewModel{files: []filePreview{{diffLines: []string{"A=1", long}}}, opts: DefaultOptions()}
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 40, Height: 20})
+	m = updated.(PreviewModel)
+
+	for _, line := range strings.Split(m.View(), "\n") {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Errorf("line %q is %d columns wide in a 40-column terminal", line, w)
+		}
+	}
+	if !strings.Contains(m.View(), "…") || strings.Contains(m.View(), "END") {
+		t.Fatalf("View() should cut the long value with an ellipsis:\n%s", m.View())
+	}
+
+	for range 10 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+		m = updated.(PreviewModel)
+	}
+	if m.xOffset != m.maxXOffset() || !strings.Contains(m.View(), "END") {
+		t.Errorf("scrolling right should reach the end of the longest line (offset %d):\n%s", m.xOffset, m.View())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = updated.(PreviewModel)
+	if m.xOffset != m.maxXOffset()-horizontalScrollStep {
+		t.Errorf("scrolling left moved to offset %d", m.xOffset)
+	}
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+const progressBarWidth = 30
+
+// ProgressMsg reports that done of total items have been processed.
+type ProgressMsg struct {
+	Done  int
+	Total int
+	Label string
+}
+
+type progressFinishedMsg struct{}
+
+// ProgressModel renders a single progress bar that is redrawn in place.
+type ProgressModel struct {
+	done    int
+	total   int
+	label   string
+	palette theme.Theme
+}
+
+// Init implements tea.Model.
+func (m ProgressModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ProgressMsg:
+		m.done, m.total, m.label = msg.Done, msg.Total, msg.Label
+	case progressFinishedMsg:
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// View implements tea.Model.
+func (m ProgressModel) View() string {
+	filled := progressBarWidth
+	if m.total > 0 {
+		filled = m.done * progressBarWidth / m.total
+	}
+
+	bar := lipgloss.NewStyle().Foreground(m.palette.Primary).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Faint(true).Render(strings.Repeat("░", progressBarWidth-filled))
+	count := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%d/%d", m.done, m.total))
+	label := lipgloss.NewStyle().Foreground(m.palette.Muted).Render(m.label)
+
+	return bar + " " + count + " " + label + "\n"
+}
+
+// ProgressRunner drives a ProgressModel in the background while the caller
+// does the work. It does not read from stdin, so it is safe to run after any
+// interactive prompts have been answered.
+type ProgressRunner struct {
+	program *tea.Program
+	done    chan struct{}
+}
+
+// StartProgress starts rendering a progress bar in palette to out.
+func StartProgress(out io.Writer, palette theme.Theme) *ProgressRunner {
+	r := &ProgressRunner{
+		program: tea.NewProgram(ProgressModel{palette: palette}, tea.WithInput(nil), tea.WithOutput(out)),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		_, _ = r.program.Run()
+		close(r.done)
+	}()
+	return r
+}
+
+// Update redraws the bar with new counts.
+func (r *ProgressRunner) Update(done, total int, label string) {
+	r.program.Send(ProgressMsg{Done: done, Total: total, Label: label})
+}
+
+// Finish stops rendering, leaving the final bar on screen.
+func (r *ProgressRunner) Finish() {
+	r.program.Send(progressFinishedMsg{})
+	<-r.done
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+func TestProgressModelView(t *testing.T) {
+	m := ProgressModel{palette: theme.Default()}
+	updated, cmd := m.Update(ProgressMsg{Done: 1, Total: 2, Label: "generated api/.env"})
+	if cmd != nil {
+		t.Error("progress updates should not return a command")
+	}
+
+	view := updated.(ProgressModel).View()
+	if !strings.Contains(view, "1/2") || !strings.Contains(view, "generated api/.env") {
+		t.Errorf("View() = %q, expected count and label", view)
+	}
+	if strings.Count(view, "█") != progressBarWidth/2 {
+		t.Errorf("View() should fill half the bar, got %q", view)
+	}
+
+	if _, cmd := updated.Update(progressFinishedMsg{}); cmd == nil {
+		t.Error("finishing should quit the program")
+	}
+}
+
+func TestProgressRunner(t *testing.T) {
+	var out bytes.Buffer
+	r := StartProgress(&out, theme.Default())
+	r.Update(1, 1, "generated .env")
+	r.Finish()
+
+	if !strings.Contains(out.String(), "1/1") {
+		t.Errorf("runner output missing final count: %q", out.String())
+	}
+}
@@ -0,0 +1,257 @@
+package tui
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+// Settings are the preferences editable from the settings screen.
+type Settings struct {
+	Backup   bool
+	Style    detector.PlaceholderStyle
+	Theme    string
+	Sort     generator.SortOrder
+	ScanRoot string
+}
+
+// SettingsChangedMsg reports an edit made on the settings screen. The
+// receiver applies and persists it, then reports back with SetStatus.
+type SettingsChangedMsg struct {
+	Settings Settings
+}
+
+// SettingsFinishedMsg signals that the user left the settings screen.
+type SettingsFinishedMsg struct{}
+
+// settingRow identifies a row of the settings screen.
+type settingRow int
+
+const (
+	rowBackup settingRow = iota
+	rowStyle
+	rowTheme
+	rowSort
+	rowScanRoot
+)
+
+var settingLabels = []string{"Backup", "Mask style", "Theme", "Sort order", "Scan root"}
+
+var (
+	styleOptions = []string{string(detector.StyleMask), string(detector.StyleDescriptive), string(detector.StylePartial)}
+	sortOptions  = []string{string(generator.SortNone), string(generator.SortKeys)}
+)
+
+// SettingsModel is the Bubble Tea model for the settings screen.
+type SettingsModel struct {
+	settings    Settings
+	cursor      settingRow
+	editing     bool
+	input       textinput.Model
+	status      string
+	showHelp    bool
+	windowWidth int
+	opts        Options
+}
+
+// NewSettingsModel creates a settings screen showing s.
+func NewSettingsModel(s Settings, opts Options) SettingsModel {
+	input := textinput.New()
+	input.Width = 40
+	return SettingsModel{settings: s, input: input, opts: opts}
+}
+
+// SetStatus shows a one-line message, such as where settings were saved.
+func (m *SettingsModel) SetStatus(status string) {
+	m.status = status
+}
+
+// SetTheme redraws the screen in t, so a theme picked on it shows at once.
+func (m *SettingsModel) SetTheme(t theme.Theme) {
+	m.opts.Theme = t
+}
+
+// SetWindowWidth sets the terminal width the screen is laid out for.
+func (m *SettingsModel) SetWindowWidth(w int) {
+	m.windowWidth = w
+	if w > 0 {
+		m.input.Width = min(40, max(w-settingsLabelWidth()-5, 10))
+	}
+}
+
+// Init initializes the settings model.
+func (m SettingsModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the settings model.
+func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.SetWindowWidth(size.Width)
+		return m, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	k := m.opts.Keys.Settings
+
+	if m.editing {
+		switch {
+		case key.Matches(keyMsg, k.Confirm):
+			m.editing = false
+			m.input.Blur()
+			m.settings.ScanRoot = m.input.Value()
+			return m, m.changed()
+		case key.Matches(keyMsg, k.Cancel):
+			m.editing = false
+			m.input.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	if m.showHelp {
+		m.showHelp = false
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, k.Up):
+		if m.cursor > rowBackup {
+			m.cursor--
+		}
+	case key.Matches(keyMsg, k.Down):
+		if m.cursor < rowScanRoot {
+			m.cursor++
+		}
+	case key.Matches(keyMsg, k.Change):
+		if m.cursor == rowScanRoot {
+			m.editing = true
+			m.input.SetValue(m.settings.ScanRoot)
+			m.input.CursorEnd()
+			return m, m.input.Focus()
+		}
+		m.cycle(1)
+		return m, m.changed()
+	case key.Matches(keyMsg, k.Prev):
+		if m.cursor != rowScanRoot {
+			m.cycle(-1)
+			return m, m.changed()
+		}
+	case key.Matches(keyMsg, m.opts.Keys.Help):
+		m.showHelp = true
+	case key.Matches(keyMsg, k.Back):
+		return m, func() tea.Msg { return SettingsFinishedMsg{} }
+	}
+	return m, nil
+}
+
+// cycle moves the setting under the cursor to the next or previous option.
+func (m *SettingsModel) cycle(step int) {
+	switch m.cursor {
+	case rowBackup:
+		m.settings.Backup = !m.settings.Backup
+	case rowStyle:
+		m.settings.Style = detector.PlaceholderStyle(nextOption(styleOptions, string(m.settings.Style), step))
+	case rowTheme:
+		m.settings.Theme = nextOption(theme.Names(), m.settings.Theme, step)
+	case rowSort:
+		m.settings.Sort = generator.SortOrder(nextOption(sortOptions, string(m.settings.Sort), step))
+	}
+}
+
+// nextOption returns the option step places after current, wrapping
+// around. An unknown current value starts from the first option.
+func nextOption(options []string, current string, step int) string {
+	i := slices.Index(options, current)
+	if i < 0 {
+		return options[0]
+	}
+	return options[(i+step+len(options))%len(options)]
+}
+
+func (m SettingsModel) changed() tea.Cmd {
+	s := m.settings
+	return func() tea.Msg { return SettingsChangedMsg{Settings: s} }
+}
+
+// value renders the current value of row.
+func (m SettingsModel) value(row settingRow) string {
+	switch row {
+	case rowBackup:
+		return onOff(m.settings.Backup)
+	case rowStyle:
+		return string(m.settings.Style)
+	case rowTheme:
+		return m.settings.Theme
+	case rowSort:
+		return string(m.settings.Sort)
+	default:
+		if m.settings.ScanRoot == "" {
+			return "."
+		}
+		return m.settings.ScanRoot
+	}
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
+
+// settingsLabelWidth is the width of the longest setting label.
+func settingsLabelWidth() int {
+	width := 0
+	for _, label := range settingLabels {
+		width = max(width, lipgloss.Width(label))
+	}
+	return width
+}
+
+// View renders the settings UI.
+func (m SettingsModel) View() string {
+	if m.showHelp {
+		return helpOverlay(settingsKeys(m.opts.Keys, false), m.opts.Theme)
+	}
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.opts.Theme.OnPrimary).
+		Background(m.opts.Theme.Primary).
+		Padding(0, 1).
+		Render("Settings")
+
+	labelWidth := settingsLabelWidth()
+	var rows string
+	for i, label := range settingLabels {
+		row := settingRow(i)
+		padded := label + lipgloss.NewStyle().Width(labelWidth-lipgloss.Width(label)+2).Render("")
+		value := fitWidth(m.value(row), m.windowWidth-labelWidth-4)
+		if row == rowScanRoot && m.editing {
+			value = m.input.View()
+		}
+		if row == m.cursor {
+			rows += lipgloss.NewStyle().Foreground(m.opts.Theme.Primary).Bold(true).Render("> "+padded) + value + "\n"
+		} else {
+			rows += "  " + padded + value + "\n"
+		}
+	}
+
+	view := "\n" + title + "\n\n" + rows
+	if m.status != "" {
+		view += "\n" + lipgloss.NewStyle().Foreground(m.opts.Theme.Muted).Render(m.status) + "\n"
+	}
+	return view + "\n" + shortHelp(settingsKeys(m.opts.Keys, m.editing).short, m.windowWidth) + "\n"
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+)
+
+func testSettings() Settings {
+	return Settings{
+		Backup: true,
+		Style:  detector.StyleMask,
+		Theme:  "default",
+		Sort:   generator.SortNone,
+	}
+}
+
+// pressSettings sends key to m and returns the updated model and the
+// message produced by its command, if any.
+func pressSettings(m SettingsModel, msg tea.KeyMsg) (SettingsModel, tea.Msg) {
+	updated, cmd := m.Update(msg)
+	var out tea.Msg
+	if cmd != nil {
+		out = cmd()
+	}
+	return updated.(SettingsModel), out
+}
+
+func TestSettingsModelCyclesOptions(t *testing.T) {
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+
+	m := NewSettingsModel(testSettings(), DefaultOptions())
+
+	m, msg := pressSettings(m, enter)
+	changed, ok := msg.(SettingsChangedMsg)
+	if !ok || changed.Settings.Backup {
+		t.Fatalf("enter on backup should turn it off, got %#v", msg)
+	}
+
+	m, _ = pressSettings(m, down)
+	m, msg = pressSettings(m, enter)
+	if got := msg.(SettingsChangedMsg).Settings.Style; got != detector.StyleDescriptive {
+		t.Errorf("style after one step = %q, want %q", got, detector.StyleDescriptive)
+	}
+	m, msg = pressSettings(m, left)
+	m, msg = pressSettings(m, left)
+	if got := msg.(SettingsChangedMsg).Settings.Style; got != detector.StylePartial {
+		t.Errorf("style should wrap backwards to %q, got %q", detector.StylePartial, got)
+	}
+
+	m, _ = pressSettings(m, down)
+	m, _ = pressSettings(m, down)
+	_, msg = pressSettings(m, enter)
+	if got := msg.(SettingsChangedMsg).Settings.Sort; got != generator.SortKeys {
+		t.Errorf("sort after one step = %q, want %q", got, generator.SortKeys)
+	}
+}
+
+func TestSettingsModelEditsScanRoot(t *testing.T) {
+	m := NewSettingsModel(testSettings(), DefaultOptions())
+	m.cursor = rowScanRoot
+
+	m, msg := pressSettings(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.editing {
+		t.Fatalf("enter on scan root should start editing")
+	}
+	if _, ok := msg.(SettingsChangedMsg); ok {
+		t.Fatalf("starting to edit should not report a change")
+	}
+
+	for _, r := range "apps" {
+		m, _ = pressSettings(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m, msg = pressSettings(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.editing {
+		t.Errorf("enter should stop editing")
+	}
+	changed, ok := msg.(SettingsChangedMsg)
+	if !ok || changed.Settings.ScanRoot != "apps" {
+		t.Errorf("confirming should report scan root %q, got %#v", "apps", msg)
+	}
+}
+
+func TestSettingsModelCancelEditKeepsValue(t *testing.T) {
+	s := testSettings()
+	s.ScanRoot = "services"
+	m := NewSettingsModel(s, DefaultOptions())
+	m.cursor = rowScanRoot
+
+	m, _ = pressSettings(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = pressSettings(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m, msg := pressSettings(m, tea.KeyMsg{Type: tea.KeyEsc})
+
+	if msg != nil {
+		t.Errorf("cancel should not report a change, got %#v", msg)
+	}
+	if m.settings.ScanRoot != "services" {
+		t.Errorf("cancel should keep scan root %q, got %q", "services", m.settings.ScanRoot)
+	}
+}
+
+func TestSettingsModelBack(t *testing.T) {
+	m := NewSettingsModel(testSettings(), DefaultOptions())
+
+	_, msg := pressSettings(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	if _, ok := msg.(SettingsFinishedMsg); !ok {
+		t.Errorf("q should leave the settings screen, got %#v", msg)
+	}
+}
+
+func TestSettingsModelView(t *testing.T) {
+	m := NewSettingsModel(testSettings(), DefaultOptions())
+	m.SetStatus("Saved to .dotenv-tui.yaml")
+
+	view := m.View()
+
+	for _, want := range []string{"Settings", "Backup", "on", "Mask style", "mask", "Theme", "Sort order", "Scan root", "Saved to .dotenv-tui.yaml"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q:\n%s", want, view)
+		}
+	}
+}
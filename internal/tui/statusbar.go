@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+// StatusBarHeight is the number of lines the status bar takes below each
+// screen.
+const StatusBarHeight = 1
+
+// errorStatusTimeout is how long an error stays in the status bar.
+const errorStatusTimeout = 5 * time.Second
+
+// StatusMsg reports the result of an action for the status bar. Errors
+// are shown as transient toasts; other messages stay until replaced.
+type StatusMsg struct {
+	Text  string
+	Error bool
+	// expire clears the error toast with this sequence number.
+	expire int
+}
+
+// statusCmd returns a command that sends text to the status bar.
+func statusCmd(text string, isError bool) tea.Cmd {
+	return func() tea.Msg {
+		return StatusMsg{Text: text, Error: isError}
+	}
+}
+
+// StatusBar is the one-line bar shown below every screen with the current
+// mode, file counts and the result of the last action.
+type StatusBar struct {
+	message string // last non-error message
+	toast   string // current error, if any
+	seq     int
+	width   int
+	palette theme.Theme
+}
+
+// SetWidth sets the terminal width the bar is cut to, so it stays on one
+// line.
+func (s *StatusBar) SetWidth(w int) {
+	s.width = w
+}
+
+// SetTheme sets the colors the bar is drawn in.
+func (s *StatusBar) SetTheme(t theme.Theme) {
+	s.palette = t
+}
+
+// Update applies a StatusMsg. An error schedules its own removal, after
+// which the last non-error message shows again.
+func (s StatusBar) Update(msg StatusMsg) (StatusBar, tea.Cmd) {
+	switch {
+	case msg.expire != 0:
+		if msg.expire == s.seq {
+			s.toast = ""
+		}
+		return s, nil
+	case msg.Error:
+		s.seq++
+		s.toast = msg.Text
+		seq := s.seq
+		return s, tea.Tick(errorStatusTimeout, func(time.Time) tea.Msg {
+			return StatusMsg{expire: seq}
+		})
+	default:
+		s.message, s.toast = msg.Text, ""
+		return s, nil
+	}
+}
+
+// Set shows text as the result of the last action.
+func (s *StatusBar) Set(text string) {
+	s.message, s.toast = text, ""
+}
+
+// View renders the bar for the given mode and counts, either of which may
+// be empty.
+func (s StatusBar) View(mode, counts string) string {
+	var parts []string
+	if mode != "" {
+		parts = append(parts, lipgloss.NewStyle().
+			Bold(true).
+			Foreground(s.palette.OnPrimary).
+			Background(s.palette.Primary).
+			Padding(0, 1).
+			Render(mode))
+	}
+	if counts != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(s.palette.Muted).Render(counts))
+	}
+	switch {
+	case s.toast != "":
+		parts = append(parts, lipgloss.NewStyle().Foreground(s.palette.Error).Render("✗ "+s.toast))
+	case s.message != "":
+		parts = append(parts, lipgloss.NewStyle().Foreground(s.palette.Text).Render(s.message))
+	}
+	return fitWidth(strings.Join(parts, "  "), s.width)
+}
+
+// Prompt renders text in place of the bar, for questions and notices that
+// must not be missed, such as confirming to quit.
+func (s StatusBar) Prompt(text string) string {
+	return lipgloss.NewStyle().Foreground(s.palette.Warning).Render(fitWidth(text, s.width))
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jellydn/dotenv-tui/internal/theme"
+)
+
+// statusOf runs cmd and returns the StatusMsg it sends.
+func statusOf(t *testing.T, cmd tea.Cmd) StatusMsg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a status command, got nil")
+	}
+	switch msg := cmd().(type) {
+	case StatusMsg:
+		return msg
+	case tea.BatchMsg:
+		for _, c := range msg {
+			if c == nil {
+				continue
+			}
+			if status, ok := c().(StatusMsg); ok {
+				return status
+			}
+		}
+		t.Fatal("batch has no StatusMsg")
+	default:
+		t.Fatalf("command sent %T, want StatusMsg", msg)
+	}
+	return StatusMsg{}
+}
+
+func TestStatusBar(t *testing.T) {
+	var s StatusBar
+	s.SetTheme(theme.Default())
+	s.Set("Saved .env")
+	view := s.View("Generate .env", "file 1/2")
+	for _, want := range []string{"Generate .env", "file 1/2", "Saved .env"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q: %q", want, view)
+		}
+	}
+
+	s, cmd := s.Update(StatusMsg{Text: "write failed", Error: true})
+	if cmd == nil {
+		t.Fatal("an error should schedule its removal")
+	}
+	if view := s.View("", ""); !strings.Contains(view, "✗ write failed") || strings.Contains(view, "Saved .env") {
+		t.Errorf("View() = %q, want the error toast", view)
+	}
+
+	// A newer error outlives the removal scheduled for an older one.
+	expireFirst := StatusMsg{expire: s.seq}
+	s, _ = s.Update(StatusMsg{Text: "second", Error: true})
+	s, _ = s.Update(expireFirst)
+	if !strings.Contains(s.View("", ""), "second") {
+		t.Error("an old expiry should not clear a newer error")
+	}
+	s, _ = s.Update(StatusMsg{expire: s.seq})
+	if view := s.View("", ""); !strings.Contains(view, "Saved .env") {
+		t.Errorf("View() = %q, want the last message back after the error expires", view)
+	}
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ellipsis marks where a line was cut to fit the terminal.
+const ellipsis = "…"
+
+// fitWidth truncates s, which may contain styles, to width cells, ending it
+// with an ellipsis if it was cut. A width of zero means the terminal size is
+// not known yet and leaves s as is.
+func fitWidth(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	return ansi.Truncate(s, width, ellipsis)
+}
+
+// scrollWidth returns the width cells of s starting at column offset, with
+// an ellipsis at each end where s continues out of view.
+func scrollWidth(s string, offset, width int) string {
+	if width <= 0 {
+		return s
+	}
+	if offset <= 0 {
+		return fitWidth(s, width)
+	}
+	if offset >= ansi.StringWidth(s) {
+		return ellipsis
+	}
+	return ellipsis + fitWidth(ansi.Cut(s, offset+1, ansi.StringWidth(s)), width-1)
+}
+
+// padWidth fits s to exactly width cells, cutting it like fitWidth or
+// padding it with spaces, so columns line up.
+func padWidth(s string, width int) string {
+	s = fitWidth(s, width)
+	return s + strings.Repeat(" ", max(width-ansi.StringWidth(s), 0))
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFitWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"API_KEY=abc", 0, "API_KEY=abc"},
+		{"API_KEY=abc", 20, "API_KEY=abc"},
+		{"API_KEY=abcdef", 10, "API_KEY=a…"},
+		{"KEY=日本語", 7, "KEY=日…"},
+	}
+	for _, tt := range tests {
+		if got := fitWidth(tt.s, tt.width); got != tt.want {
+			t.Errorf("fitWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+
+	styled := lipgloss.NewStyle().Bold(true).Render("DATABASE_URL=postgres://localhost")
+	if got := lipgloss.Width(fitWidth(styled, 12)); got != 12 {
+		t.Errorf("fitWidth() of a styled string is %d cells wide, want 12", got)
+	}
+}
+
+func TestScrollWidth(t *testing.T) {
+	tests := []struct {
+		offset int
+		want   string
+	}{
+		{0, "0123456…"},
+		{4, "…56789a…"},
+		{6, "…789abc"},
+		{20, "…"},
+	}
+	for _, tt := range tests {
+		if got := scrollWidth("0123456789abc", tt.offset, 8); got != tt.want {
+			t.Errorf("scrollWidth(offset %d) = %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestPadWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"PORT", 6, "PORT  "},
+		{"DATABASE_URL", 6, "DATAB…"},
+		{"日本", 5, "日本 "},
+	}
+	for _, tt := range tests {
+		if got := padWidth(tt.s, tt.width); got != tt.want {
+			t.Errorf("padWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
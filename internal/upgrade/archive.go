@@ -0,0 +1,107 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// binaryName is the name of the executable inside release archives.
+func binaryName(osType string) string {
+	if osType == "windows" {
+		return "dotenv-tui.exe"
+	}
+	return "dotenv-tui"
+}
+
+// extractBinary returns the path of the executable called name in the
+// downloaded asset at archivePath, unpacking it to a temporary file when
+// the asset, named assetName, is a .tar.gz or .zip archive. Plain binaries
+// are returned as they are.
+func extractBinary(archivePath, assetName, name string) (string, error) {
+	lower := strings.ToLower(assetName)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return extractTarGz(archivePath, name)
+	case strings.HasSuffix(lower, ".zip"):
+		return extractZip(archivePath, name)
+	default:
+		return archivePath, nil
+	}
+}
+
+func extractTarGz(archivePath, name string) (string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return "", fmt.Errorf("archive has no %s", name)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == name {
+			return writeExtracted(tr)
+		}
+	}
+}
+
+func extractZip(archivePath, name string) (string, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || path.Base(f.Name) != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer func() { _ = rc.Close() }()
+		return writeExtracted(rc)
+	}
+	return "", fmt.Errorf("archive has no %s", name)
+}
+
+// writeExtracted copies an archived binary to an executable temporary file.
+func writeExtracted(r io.Reader) (string, error) {
+	tmp, err := os.CreateTemp("", "dotenv-tui-upgrade-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to extract binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
@@ -0,0 +1,106 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractBinary(t *testing.T) {
+	content := []byte("#!/bin/sh\necho dotenv-tui\n")
+	dir := t.TempDir()
+
+	var tgz bytes.Buffer
+	gz := gzip.NewWriter(&tgz)
+	tw := tar.NewWriter(gz)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{{"README.md", []byte("readme")}, {"dotenv-tui_1.2.0_linux_amd64/dotenv-tui", content}} {
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0755, Size: int64(len(f.data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tgzPath := filepath.Join(dir, "download-tgz")
+	if err := os.WriteFile(tgzPath, tgz.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	w, err := zw.Create("dotenv-tui.exe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zipPath := filepath.Join(dir, "download-zip")
+	if err := os.WriteFile(zipPath, zipped.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		assetName string
+		binary    string
+	}{
+		{"tar.gz", tgzPath, "dotenv-tui_1.2.0_linux_amd64.tar.gz", "dotenv-tui"},
+		{"zip", zipPath, "dotenv-tui_1.2.0_windows_amd64.zip", "dotenv-tui.exe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractBinary(tt.path, tt.assetName, tt.binary)
+			if err != nil {
+				t.Fatalf("extractBinary() error = %v", err)
+			}
+			defer func() { _ = os.Remove(got) }()
+			data, err := os.ReadFile(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, content) {
+				t.Errorf("extracted %q, want %q", data, content)
+			}
+		})
+	}
+
+	t.Run("plain binary", func(t *testing.T) {
+		got, err := extractBinary(tgzPath, "dotenv-tui-linux-amd64", "dotenv-tui")
+		if err != nil || got != tgzPath {
+			t.Errorf("extractBinary() = %q, %v; want the download itself", got, err)
+		}
+	})
+
+	t.Run("missing binary", func(t *testing.T) {
+		if _, err := extractBinary(tgzPath, "dotenv-tui.tar.gz", "dotenv-tui.exe"); err == nil {
+			t.Error("extractBinary() expected error for an archive without the binary, got nil")
+		}
+	})
+}
+
+func TestBinaryName(t *testing.T) {
+	if got := binaryName("windows"); got != "dotenv-tui.exe" {
+		t.Errorf("binaryName(windows) = %q", got)
+	}
+	if got := binaryName("linux"); got != "dotenv-tui" {
+		t.Errorf("binaryName(linux) = %q", got)
+	}
+}
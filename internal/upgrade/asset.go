@@ -0,0 +1,189 @@
+package upgrade
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// osAliases and archAliases list the names release assets use for each
+// platform returned by detectPlatform.
+var (
+	osAliases = map[string][]string{
+		"linux":   {"linux"},
+		"darwin":  {"darwin", "macos", "mac", "osx"},
+		"windows": {"windows", "win"},
+	}
+	archAliases = map[string][]string{
+		"amd64": {"amd64", "x64"},
+		"arm64": {"arm64", "aarch64"},
+		"386":   {"386", "i386", "i686", "x86"},
+		"arm":   {"arm", "armv6", "armv7", "armhf"},
+	}
+)
+
+// nameSeparators split asset names into the words platforms are matched
+// against, e.g. "dotenv-tui_1.2.3_linux_amd64.tar.gz".
+var nameSeparators = regexp.MustCompile(`[-_.\s]+`)
+
+// archiveSuffixes are the archive formats extractBinary can unpack.
+var archiveSuffixes = []string{".tar.gz", ".tgz", ".zip"}
+
+// metadataSuffixes end the names of assets that describe other assets
+// rather than hold the binary.
+var metadataSuffixes = []string{".sha256", ".sig", ".pem", ".asc", ".sbom", ".json", ".txt", ".deb", ".rpm", ".apk"}
+
+// assetName returns the name of the plain binary asset for osType and
+// arch, the naming the release workflow has always used.
+func assetName(osType, arch string) string {
+	name := fmt.Sprintf("dotenv-tui-%s-%s", osType, arch)
+	if osType == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// selectAsset picks the asset of release to install on osType and arch
+// with variant v: a dotenv-tui binary or archive whose name mentions the
+// platform, such as "dotenv-tui-linux-amd64" or goreleaser's
+// "dotenv-tui_1.2.3_linux_amd64.tar.gz". Builds for the system's libc and
+// ARM version come first, then the plain binary named by assetName, then
+// other plain binaries before archives. ARM builds for a newer version
+// than the processor's are never picked.
+//
+// When the only builds left are for another libc, one is still picked,
+// since Go binaries rarely depend on it, and note says so. When nothing
+// matches, the error lists the assets the release has.
+func selectAsset(release Release, osType, arch string, v variant) (asset Asset, note string, err error) {
+	bestRank := 0
+	for _, a := range release.Assets {
+		if rank := assetRank(a.Name, osType, arch, v); rank > bestRank {
+			asset, bestRank = a, rank
+		}
+	}
+	if bestRank == 0 {
+		names := make([]string, 0, len(release.Assets))
+		for _, a := range release.Assets {
+			names = append(names, a.Name)
+		}
+		listed := "none"
+		if len(names) > 0 {
+			listed = strings.Join(names, ", ")
+		}
+		platform := osType + "/" + arch
+		if s := v.String(); s != "" {
+			platform += " (" + s + ")"
+		}
+		return Asset{}, "", fmt.Errorf("no %s asset in release %s (assets: %s)", platform, release.TagName, listed)
+	}
+	if v.musl && !assetWords(asset.Name)["musl"] {
+		note = fmt.Sprintf("%s has no musl build; installing %s, built for glibc, which may not run on this system", release.TagName, asset.Name)
+	}
+	return asset, note, nil
+}
+
+// assetRank scores how well an asset name fits osType, arch and v, higher
+// being better: 0 when it is not a dotenv-tui binary that can run there.
+func assetRank(name, osType, arch string, v variant) int {
+	lower := strings.ToLower(name)
+	if !strings.HasPrefix(lower, "dotenv-tui") || hasAnySuffix(lower, metadataSuffixes) {
+		return 0
+	}
+	words := assetWords(name)
+	if !anyWord(words, osAliases[osType]) || !anyWord(words, archAliases[arch]) {
+		return 0
+	}
+
+	rank := 1
+	if !isArchive(lower) {
+		rank++
+	}
+	if name == assetName(osType, arch) {
+		rank += 10
+	}
+	if words["musl"] == v.musl {
+		rank += 100
+	}
+	if arch == "arm" {
+		assetARM := armWordVersion(words)
+		switch {
+		case assetARM == v.arm:
+			rank += 50
+		case v.arm == 0:
+			// On an unknown processor, the oldest version is the safest.
+			rank += 40 - assetARM
+		case assetARM > v.arm:
+			return 0
+		case assetARM == 0:
+			rank += 30
+		default:
+			// An older ARM version still runs, the newer the better.
+			rank += 30 + assetARM
+		}
+	}
+	return rank
+}
+
+// assetWords splits an asset name into its lower-case words.
+func assetWords(name string) map[string]bool {
+	// x86_64 would otherwise split into x86, a 32-bit alias, and 64.
+	normalized := strings.NewReplacer("x86_64", "amd64", "x86-64", "amd64").Replace(strings.ToLower(name))
+	words := make(map[string]bool)
+	for _, w := range nameSeparators.Split(normalized, -1) {
+		words[w] = true
+	}
+	return words
+}
+
+// armWordVersion returns the ARM version an asset name's words give, such
+// as 6 for armv6 and 7 for armhf, or 0 for a plain "arm".
+func armWordVersion(words map[string]bool) int {
+	switch {
+	case words["armv7"] || words["armhf"]:
+		return 7
+	case words["armv6"]:
+		return 6
+	default:
+		return 0
+	}
+}
+
+// checksumAsset returns the asset holding the checksum of a: a.Name with a
+// .sha256 suffix, or a checksums file covering every asset as goreleaser
+// publishes.
+func checksumAsset(release Release, a Asset) (Asset, bool) {
+	if c, ok := release.asset(a.Name + ".sha256"); ok {
+		return c, true
+	}
+	for _, c := range release.Assets {
+		lower := strings.ToLower(c.Name)
+		if strings.HasSuffix(lower, "checksums.txt") || strings.HasSuffix(lower, "sha256sums") || strings.HasSuffix(lower, "sha256sums.txt") {
+			return c, true
+		}
+	}
+	return Asset{}, false
+}
+
+// isArchive reports whether an asset name is an archive extractBinary
+// unpacks.
+func isArchive(name string) bool {
+	return hasAnySuffix(strings.ToLower(name), archiveSuffixes)
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyWord(words map[string]bool, candidates []string) bool {
+	for _, c := range candidates {
+		if words[c] {
+			return true
+		}
+	}
+	return false
+}
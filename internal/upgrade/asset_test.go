@@ -0,0 +1,145 @@
+package upgrade
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssetName(t *testing.T) {
+	if got := assetName("linux", "arm64"); got != "dotenv-tui-linux-arm64" {
+		t.Errorf("assetName(linux, arm64) = %q", got)
+	}
+	if got := assetName("windows", "amd64"); got != "dotenv-tui-windows-amd64.exe" {
+		t.Errorf("assetName(windows, amd64) = %q", got)
+	}
+}
+
+func TestSelectAsset(t *testing.T) {
+	goreleaser := []Asset{
+		{Name: "checksums.txt"},
+		{Name: "dotenv-tui_1.2.0_darwin_arm64.tar.gz"},
+		{Name: "dotenv-tui_1.2.0_linux_386.tar.gz"},
+		{Name: "dotenv-tui_1.2.0_linux_x86_64.tar.gz"},
+		{Name: "dotenv-tui_1.2.0_linux_amd64.deb"},
+		{Name: "dotenv-tui_1.2.0_windows_amd64.zip"},
+	}
+	tests := []struct {
+		name   string
+		assets []Asset
+		os     string
+		arch   string
+		want   string
+	}{
+		{
+			name:   "plain binary",
+			assets: []Asset{{Name: "dotenv-tui-linux-amd64"}, {Name: "dotenv-tui-linux-amd64.sha256"}, {Name: "dotenv-tui_linux_amd64.tar.gz"}},
+			os:     "linux",
+			arch:   "amd64",
+			want:   "dotenv-tui-linux-amd64",
+		},
+		{name: "goreleaser x86_64", assets: goreleaser, os: "linux", arch: "amd64", want: "dotenv-tui_1.2.0_linux_x86_64.tar.gz"},
+		{name: "goreleaser 386", assets: goreleaser, os: "linux", arch: "386", want: "dotenv-tui_1.2.0_linux_386.tar.gz"},
+		{name: "goreleaser darwin", assets: goreleaser, os: "darwin", arch: "arm64", want: "dotenv-tui_1.2.0_darwin_arm64.tar.gz"},
+		{name: "goreleaser windows", assets: goreleaser, os: "windows", arch: "amd64", want: "dotenv-tui_1.2.0_windows_amd64.zip"},
+		{
+			name:   "binary preferred to archive",
+			assets: []Asset{{Name: "dotenv-tui-macos-aarch64.zip"}, {Name: "dotenv-tui-macos-aarch64"}},
+			os:     "darwin",
+			arch:   "arm64",
+			want:   "dotenv-tui-macos-aarch64",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, note, err := selectAsset(Release{TagName: "v1.2.0", Assets: tt.assets}, tt.os, tt.arch, variant{})
+			if err != nil {
+				t.Fatalf("selectAsset() error = %v", err)
+			}
+			if got.Name != tt.want || note != "" {
+				t.Errorf("selectAsset() = %q, %q; want %q without a note", got.Name, note, tt.want)
+			}
+		})
+	}
+
+	_, _, err := selectAsset(Release{TagName: "v1.2.0", Assets: goreleaser}, "linux", "arm64", variant{})
+	if err == nil {
+		t.Fatal("selectAsset() expected error for a missing platform, got nil")
+	}
+	for _, want := range []string{"linux/arm64", "v1.2.0", "dotenv-tui_1.2.0_linux_386.tar.gz"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("selectAsset() error %q does not contain %q", err, want)
+		}
+	}
+	if _, _, err := selectAsset(Release{TagName: "v1.2.0"}, "linux", "amd64", variant{}); err == nil || !strings.Contains(err.Error(), "assets: none") {
+		t.Errorf("selectAsset() error = %v, want one saying there are no assets", err)
+	}
+}
+
+func TestSelectAssetVariant(t *testing.T) {
+	assets := []Asset{
+		{Name: "dotenv-tui-linux-amd64"},
+		{Name: "dotenv-tui-linux-amd64-musl"},
+		{Name: "dotenv-tui_1.2.0_linux_armv6.tar.gz"},
+		{Name: "dotenv-tui_1.2.0_linux_armv7.tar.gz"},
+	}
+	tests := []struct {
+		name     string
+		assets   []Asset
+		arch     string
+		v        variant
+		want     string
+		wantNote bool
+	}{
+		{name: "glibc", assets: assets, arch: "amd64", want: "dotenv-tui-linux-amd64"},
+		{name: "musl", assets: assets, arch: "amd64", v: variant{musl: true}, want: "dotenv-tui-linux-amd64-musl"},
+		{name: "musl without a musl build", assets: assets[:1], arch: "amd64", v: variant{musl: true}, want: "dotenv-tui-linux-amd64", wantNote: true},
+		{name: "armv6", assets: assets, arch: "arm", v: variant{arm: 6}, want: "dotenv-tui_1.2.0_linux_armv6.tar.gz"},
+		{name: "armv7", assets: assets, arch: "arm", v: variant{arm: 7}, want: "dotenv-tui_1.2.0_linux_armv7.tar.gz"},
+		{name: "armv7 runs armv6", assets: assets[:3], arch: "arm", v: variant{arm: 7}, want: "dotenv-tui_1.2.0_linux_armv6.tar.gz"},
+		{name: "unknown arm version", assets: assets, arch: "arm", want: "dotenv-tui_1.2.0_linux_armv6.tar.gz"},
+		{
+			name:   "plain arm build",
+			assets: []Asset{{Name: "dotenv-tui-linux-arm"}, {Name: "dotenv-tui-linux-armv7"}},
+			arch:   "arm",
+			v:      variant{arm: 6},
+			want:   "dotenv-tui-linux-arm",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, note, err := selectAsset(Release{TagName: "v1.2.0", Assets: tt.assets}, "linux", tt.arch, tt.v)
+			if err != nil {
+				t.Fatalf("selectAsset() error = %v", err)
+			}
+			if got.Name != tt.want {
+				t.Errorf("selectAsset() = %q, want %q", got.Name, tt.want)
+			}
+			if (note != "") != tt.wantNote {
+				t.Errorf("selectAsset() note = %q, want a note: %v", note, tt.wantNote)
+			}
+		})
+	}
+
+	_, _, err := selectAsset(Release{TagName: "v1.2.0", Assets: assets[3:]}, "linux", "arm", variant{arm: 6})
+	if err == nil || !strings.Contains(err.Error(), "linux/arm (armv6)") {
+		t.Errorf("selectAsset() error = %v, want one naming linux/arm (armv6)", err)
+	}
+}
+
+func TestChecksumAsset(t *testing.T) {
+	binary := Asset{Name: "dotenv-tui-linux-amd64"}
+	release := Release{Assets: []Asset{binary, {Name: "dotenv-tui-linux-amd64.sha256"}, {Name: "checksums.txt"}}}
+	if c, ok := checksumAsset(release, binary); !ok || c.Name != "dotenv-tui-linux-amd64.sha256" {
+		t.Errorf("checksumAsset() = %q, %v; want the .sha256 asset", c.Name, ok)
+	}
+
+	archive := Asset{Name: "dotenv-tui_1.2.0_linux_amd64.tar.gz"}
+	release = Release{Assets: []Asset{archive, {Name: "dotenv-tui_1.2.0_checksums.txt"}}}
+	if c, ok := checksumAsset(release, archive); !ok || c.Name != "dotenv-tui_1.2.0_checksums.txt" {
+		t.Errorf("checksumAsset() = %q, %v; want the checksums list", c.Name, ok)
+	}
+
+	if _, ok := checksumAsset(Release{Assets: []Asset{archive}}, archive); ok {
+		t.Error("checksumAsset() found a checksum in a release without one")
+	}
+}
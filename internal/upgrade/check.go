@@ -0,0 +1,89 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// UpdateCheckInterval is how long a cached update check stays fresh.
+const UpdateCheckInterval = 24 * time.Hour
+
+// UpdateCacheName is the file name of the update check cache, stored in the
+// dotenv-tui state directory.
+const UpdateCacheName = "update-check.json"
+
+// updateCheckTimeout bounds the background check, which should give up
+// quickly on slow networks rather than linger.
+const updateCheckTimeout = 10 * time.Second
+
+// updateCache records the result of the last update check.
+type updateCache struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// CheckForUpdate reports the newest stable release when it is newer than
+// currentVersion. GitHub is queried at most once per UpdateCheckInterval;
+// in between, the answer is read from cachePath. An empty cachePath
+// disables caching. Development builds never report updates.
+func CheckForUpdate(ctx context.Context, currentVersion, cachePath string) (string, bool, error) {
+	if currentVersion == "dev" {
+		return "", false, nil
+	}
+
+	latest, ok := readUpdateCache(cachePath, time.Now())
+	if !ok {
+		ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+		defer cancel()
+		var err error
+		latest, err = getLatestVersion(ctx, ChannelStable)
+		if err != nil {
+			return "", false, err
+		}
+		writeUpdateCache(cachePath, updateCache{CheckedAt: time.Now(), Latest: latest})
+	}
+
+	if !isNewer(latest, currentVersion) {
+		return "", false, nil
+	}
+	return latest, true, nil
+}
+
+// readUpdateCache returns the cached latest version if the cache at path was
+// written less than UpdateCheckInterval before now.
+func readUpdateCache(path string, now time.Time) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	var c updateCache
+	if err := json.Unmarshal(data, &c); err != nil || c.Latest == "" {
+		return "", false
+	}
+	if now.Sub(c.CheckedAt) >= UpdateCheckInterval || c.CheckedAt.After(now) {
+		return "", false
+	}
+	return c.Latest, true
+}
+
+// writeUpdateCache saves c to path. Failures only cost an extra check next
+// time, so they are ignored.
+func writeUpdateCache(path string, c updateCache) {
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0600)
+}
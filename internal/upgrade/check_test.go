@@ -0,0 +1,72 @@
+package upgrade
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stubReleases points githubAPIURL at a server returning tag and counts the
+// requests it receives.
+func stubReleases(t *testing.T, tag string) *int {
+	t.Helper()
+	calls := new(int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"tag_name": "` + tag + `"}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	original := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = original })
+	return calls
+}
+
+func TestCheckForUpdate(t *testing.T) {
+	calls := stubReleases(t, "v1.4.0")
+	cachePath := filepath.Join(t.TempDir(), "state", UpdateCacheName)
+
+	latest, ok, err := CheckForUpdate(t.Context(), "1.3.0", cachePath)
+	if err != nil || !ok || latest != "v1.4.0" {
+		t.Fatalf("CheckForUpdate() = %q, %v, %v; want v1.4.0, true, nil", latest, ok, err)
+	}
+
+	// A fresh cache answers without another request.
+	if _, _, err := CheckForUpdate(t.Context(), "1.3.0", cachePath); err != nil {
+		t.Fatalf("CheckForUpdate() error = %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("GitHub queried %d times, want 1", *calls)
+	}
+
+	if _, ok, _ := CheckForUpdate(t.Context(), "1.4.0", cachePath); ok {
+		t.Error("expected no update when already on the latest version")
+	}
+	if _, ok, _ := CheckForUpdate(t.Context(), "dev", cachePath); ok {
+		t.Error("expected no update for dev builds")
+	}
+}
+
+func TestCheckForUpdateStaleCache(t *testing.T) {
+	calls := stubReleases(t, "v2.0.0")
+	cachePath := filepath.Join(t.TempDir(), UpdateCacheName)
+
+	stale, _ := json.Marshal(updateCache{CheckedAt: time.Now().Add(-25 * time.Hour), Latest: "v1.4.0"})
+	if err := os.WriteFile(cachePath, stale, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	latest, ok, err := CheckForUpdate(t.Context(), "1.3.0", cachePath)
+	if err != nil || !ok || latest != "v2.0.0" {
+		t.Fatalf("CheckForUpdate() = %q, %v, %v; want v2.0.0, true, nil", latest, ok, err)
+	}
+	if *calls != 1 {
+		t.Errorf("GitHub queried %d times, want 1", *calls)
+	}
+}
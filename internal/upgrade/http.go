@@ -0,0 +1,213 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Default time limits for the two kinds of requests the upgrader makes.
+// Both cover every retry of the request, not just one attempt.
+const (
+	DefaultAPITimeout      = 30 * time.Second
+	DefaultDownloadTimeout = 10 * time.Minute
+)
+
+// Retry policy for transient failures: connection errors, 429 and 5xx.
+const retryAttempts = 3
+
+// retryDelay is the wait before the first retry; it doubles on each retry.
+var retryDelay = time.Second
+
+// httpClient honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY. It has no overall
+// timeout, since downloads may legitimately take minutes; callers bound
+// each request with a context instead, while the transport limits how long
+// a stalled connection or silent server can hold things up.
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   15 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   15 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+	},
+}
+
+// getWithRetry sends a GET request for url, retrying transient failures
+// with exponential backoff until ctx is done. Any other response, including
+// client errors such as 404, is returned for the caller to inspect.
+func getWithRetry(ctx context.Context, url string) (*http.Response, error) {
+	return getHeadersWithRetry(ctx, url, nil)
+}
+
+// getRangeWithRetry is getWithRetry asking for the bytes of url from offset
+// on, with a Range header, when offset is positive. Servers may ignore the
+// range and send everything with a 200.
+func getRangeWithRetry(ctx context.Context, url string, offset int64) (*http.Response, error) {
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	return getHeadersWithRetry(ctx, url, header)
+}
+
+// getAPIWithRetry is getWithRetry for the GitHub API, authenticated with
+// $GITHUB_TOKEN when it is set, which raises the rate limit from 60
+// requests an hour per IP address to 5,000 per token.
+func getAPIWithRetry(ctx context.Context, url string) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return getHeadersWithRetry(ctx, url, header)
+}
+
+// getHeadersWithRetry is getWithRetry sending header with each request.
+// Rate-limited responses are returned straight away, since retrying cannot
+// succeed before the limit resets.
+func getHeadersWithRetry(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	delay := retryDelay
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+
+		resp, err := httpClient.Do(req)
+		switch {
+		case err != nil:
+			lastErr = err
+		case rateLimited(resp):
+			return resp, nil
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			_ = resp.Body.Close()
+			lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		default:
+			return resp, nil
+		}
+
+		if attempt == retryAttempts || ctx.Err() != nil {
+			return nil, retryError(ctx, lastErr)
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return nil, retryError(ctx, lastErr)
+		}
+	}
+}
+
+// retryError is the error ending a retry loop whose last attempt failed
+// with lastErr: a timeout when ctx's deadline passed, an interruption when
+// ctx was cancelled, such as by Ctrl+C, and lastErr otherwise.
+func retryError(ctx context.Context, lastErr error) error {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("timed out: %w", lastErr)
+	case ctx.Err() != nil:
+		return fmt.Errorf("interrupted: %w", ctx.Err())
+	}
+	return lastErr
+}
+
+// rateLimitError reports that the GitHub API refused a request because the
+// caller ran out of requests for the current window.
+type rateLimitError struct {
+	// reset is when the window ends, or zero if GitHub did not say.
+	reset         time.Time
+	authenticated bool
+}
+
+func (e *rateLimitError) Error() string {
+	msg := "GitHub API rate limit exceeded"
+	if !e.reset.IsZero() {
+		minutes := int(math.Ceil(time.Until(e.reset).Minutes()))
+		msg += fmt.Sprintf("; it resets at %s (in %d minute(s))", e.reset.Local().Format("15:04"), max(minutes, 1))
+	}
+	if !e.authenticated {
+		msg += "; set GITHUB_TOKEN to a GitHub token to raise the limit"
+	}
+	return msg
+}
+
+// rateLimited reports whether resp is GitHub refusing a request for
+// exceeding the rate limit: a 403 or 429 with no requests remaining.
+func rateLimited(resp *http.Response) bool {
+	return (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// checkRateLimit returns a rateLimitError if resp was rate-limited.
+func checkRateLimit(resp *http.Response) error {
+	if !rateLimited(resp) {
+		return nil
+	}
+	err := &rateLimitError{authenticated: resp.Request != nil && resp.Request.Header.Get("Authorization") != ""}
+	if reset, perr := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); perr == nil {
+		err.reset = time.Unix(reset, 0)
+	}
+	return err
+}
+
+// progressWriter reports download progress to out as bytes pass through it,
+// redrawing a single line at most every tenth of a second.
+type progressWriter struct {
+	out     io.Writer
+	total   int64 // expected size, or -1 if unknown
+	written int64
+	drawn   time.Time
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+	if time.Since(p.drawn) >= 100*time.Millisecond {
+		p.draw()
+	}
+	return len(b), nil
+}
+
+func (p *progressWriter) draw() {
+	p.drawn = time.Now()
+	if p.total > 0 {
+		_, _ = fmt.Fprintf(p.out, "\r  %3d%%  %s / %s", p.written*100/p.total, formatBytes(p.written), formatBytes(p.total))
+		return
+	}
+	_, _ = fmt.Fprintf(p.out, "\r  %s", formatBytes(p.written))
+}
+
+// finish draws the final state and ends the progress line.
+func (p *progressWriter) finish() {
+	p.draw()
+	_, _ = fmt.Fprintln(p.out)
+}
+
+// formatBytes renders n as a human-readable size such as "4.2 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,239 @@
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries shortens the backoff between retries for the duration of t.
+func fastRetries(t *testing.T) {
+	t.Helper()
+	orig := retryDelay
+	retryDelay = time.Millisecond
+	t.Cleanup(func() { retryDelay = orig })
+}
+
+func TestGetWithRetry(t *testing.T) {
+	fastRetries(t)
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < retryAttempts {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		resp, err := getWithRetry(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("getWithRetry() error = %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d, want 200", resp.StatusCode)
+		}
+		if got := calls.Load(); got != retryAttempts {
+			t.Errorf("server called %d times, want %d", got, retryAttempts)
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		if _, err := getWithRetry(context.Background(), server.URL); err == nil {
+			t.Fatal("expected error after repeated 429 responses")
+		}
+		if got := calls.Load(); got != retryAttempts {
+			t.Errorf("server called %d times, want %d", got, retryAttempts)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		resp, err := getWithRetry(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("getWithRetry() error = %v", err)
+		}
+		_ = resp.Body.Close()
+		if got := calls.Load(); got != 1 {
+			t.Errorf("server called %d times, want 1", got)
+		}
+	})
+
+	t.Run("stops at the context deadline", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := getWithRetry(ctx, server.URL)
+		if err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Errorf("getWithRetry() error = %v, want a timeout", err)
+		}
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		_, err := getWithRetry(ctx, server.URL)
+		if !errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "timed out") {
+			t.Errorf("getWithRetry() error = %v, want an interruption", err)
+		}
+	})
+}
+
+func TestGetAPIWithRetry(t *testing.T) {
+	fastRetries(t)
+
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_TOKEN", "")
+	resp, err := getAPIWithRetry(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("getAPIWithRetry() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if auth != "" {
+		t.Errorf("Authorization = %q without GITHUB_TOKEN, want none", auth)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "ghp_test")
+	resp, err = getAPIWithRetry(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("getAPIWithRetry() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if auth != "Bearer ghp_test" {
+		t.Errorf("Authorization = %q, want the GITHUB_TOKEN bearer token", auth)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	fastRetries(t)
+	reset := time.Now().Add(10 * time.Minute)
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	for _, token := range []string{"", "ghp_test"} {
+		t.Setenv("GITHUB_TOKEN", token)
+		calls.Store(0)
+		resp, err := getAPIWithRetry(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("getAPIWithRetry() error = %v", err)
+		}
+		_ = resp.Body.Close()
+		if got := calls.Load(); got != 1 {
+			t.Errorf("server called %d times, want 1: rate limits are not retried", got)
+		}
+
+		err = checkRateLimit(resp)
+		if err == nil {
+			t.Fatal("checkRateLimit() = nil, want a rate limit error")
+		}
+		msg := err.Error()
+		for _, want := range []string{"rate limit exceeded", "resets at " + reset.Local().Format("15:04"), "in 10 minute(s)"} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("error %q does not contain %q", msg, want)
+			}
+		}
+		if hint := strings.Contains(msg, "set GITHUB_TOKEN"); hint != (token == "") {
+			t.Errorf("error %q: GITHUB_TOKEN hint = %v with token %q", msg, hint, token)
+		}
+	}
+}
+
+func TestCheckRateLimitOtherResponses(t *testing.T) {
+	for _, resp := range []*http.Response{
+		{StatusCode: http.StatusOK, Header: http.Header{"X-Ratelimit-Remaining": {"0"}}},
+		{StatusCode: http.StatusForbidden, Header: http.Header{"X-Ratelimit-Remaining": {"12"}}},
+		{StatusCode: http.StatusForbidden, Header: http.Header{}},
+	} {
+		if err := checkRateLimit(resp); err != nil {
+			t.Errorf("checkRateLimit(%d, %v) = %v, want nil", resp.StatusCode, resp.Header, err)
+		}
+	}
+}
+
+func TestHTTPClientUsesProxy(t *testing.T) {
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("httpClient.Transport is %T, want *http.Transport", httpClient.Transport)
+	}
+	// ProxyFromEnvironment caches the environment on first use, so compare
+	// the function itself rather than the proxy it picks.
+	if reflect.ValueOf(transport.Proxy).Pointer() != reflect.ValueOf(http.ProxyFromEnvironment).Pointer() {
+		t.Error("transport does not use http.ProxyFromEnvironment")
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	var out bytes.Buffer
+	pw := &progressWriter{out: &out, total: 2048}
+	_, _ = pw.Write(make([]byte, 1024))
+	_, _ = pw.Write(make([]byte, 1024))
+	pw.finish()
+
+	got := out.String()
+	if !strings.Contains(got, "100%  2.0 KB / 2.0 KB") {
+		t.Errorf("progress output = %q, want final 100%% line", got)
+	}
+	if !strings.HasSuffix(got, "\n") {
+		t.Errorf("progress output = %q, want trailing newline", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 B",
+		1536:            "1.5 KB",
+		5 * 1024 * 1024: "5.0 MB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
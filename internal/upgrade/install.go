@@ -0,0 +1,65 @@
+package upgrade
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// packageManager describes a tool that owns the installed binary and must
+// be used to upgrade it instead of replacing the file in place.
+type packageManager struct {
+	Name    string
+	Command string
+}
+
+// detectPackageManager reports which package manager installed the binary
+// at execPath, if any. goBinDirs are the directories `go install` writes to.
+func detectPackageManager(execPath string, goBinDirs []string) (packageManager, bool) {
+	// Normalise separators by hand: filepath.ToSlash only converts the
+	// separator of the OS running the code.
+	p := strings.ReplaceAll(execPath, `\`, "/")
+	lower := strings.ToLower(p)
+
+	switch {
+	case strings.Contains(p, "/Cellar/") || strings.Contains(p, "/homebrew/") || strings.Contains(p, "/.linuxbrew/"):
+		return packageManager{Name: "Homebrew", Command: "brew upgrade dotenv-tui"}, true
+	case strings.Contains(lower, "/scoop/apps/") || strings.Contains(lower, "/scoop/shims/"):
+		return packageManager{Name: "Scoop", Command: "scoop update dotenv-tui"}, true
+	}
+
+	dir := filepath.Clean(filepath.Dir(execPath))
+	for _, binDir := range goBinDirs {
+		if binDir != "" && filepath.Clean(binDir) == dir {
+			return packageManager{Name: "go install", Command: "go install github.com/" + repoOwner + "/" + repoName + "@latest"}, true
+		}
+	}
+	return packageManager{}, false
+}
+
+// goBinDirs returns where `go install` puts binaries: $GOBIN if set,
+// otherwise the bin directory of each GOPATH entry.
+func goBinDirs() []string {
+	if gobin := os.Getenv("GOBIN"); gobin != "" {
+		return []string{gobin}
+	}
+	var dirs []string
+	for _, p := range filepath.SplitList(build.Default.GOPATH) {
+		dirs = append(dirs, filepath.Join(p, "bin"))
+	}
+	return dirs
+}
+
+// resolvedExecutable returns the path of the running binary with symlinks
+// resolved, so a Homebrew symlink in /usr/local/bin points into the Cellar.
+func resolvedExecutable() (string, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(execPath); err == nil {
+		return resolved, nil
+	}
+	return execPath, nil
+}
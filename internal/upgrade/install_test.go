@@ -0,0 +1,39 @@
+package upgrade
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectPackageManager(t *testing.T) {
+	goBin := filepath.Join("home", "me", "go", "bin")
+
+	tests := []struct {
+		name     string
+		execPath string
+		want     string
+	}{
+		{"homebrew cellar", "/opt/homebrew/Cellar/dotenv-tui/1.2.0/bin/dotenv-tui", "Homebrew"},
+		{"homebrew intel cellar", "/usr/local/Cellar/dotenv-tui/1.2.0/bin/dotenv-tui", "Homebrew"},
+		{"linuxbrew", "/home/linuxbrew/.linuxbrew/Cellar/dotenv-tui/1.2.0/bin/dotenv-tui", "Homebrew"},
+		{"scoop", `C:\Users\me\scoop\apps\dotenv-tui\current\dotenv-tui.exe`, "Scoop"},
+		{"go install", filepath.Join(goBin, "dotenv-tui"), "go install"},
+		{"manual install", "/usr/local/bin/dotenv-tui", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm, ok := detectPackageManager(tt.execPath, []string{goBin})
+			if pm.Name != tt.want || ok != (tt.want != "") {
+				t.Errorf("detectPackageManager(%q) = %q, %v; want %q", tt.execPath, pm.Name, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoBinDirs(t *testing.T) {
+	t.Setenv("GOBIN", "/custom/bin")
+	if dirs := goBinDirs(); len(dirs) != 1 || dirs[0] != "/custom/bin" {
+		t.Errorf("goBinDirs() = %v, want [/custom/bin]", dirs)
+	}
+}
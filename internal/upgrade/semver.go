@@ -0,0 +1,101 @@
+package upgrade
+
+import (
+	"strconv"
+	"strings"
+)
+
+// version is a parsed semantic version such as 1.4.0-rc.1.
+type version struct {
+	major, minor, patch int
+	pre                 []string // dot-separated prerelease identifiers
+}
+
+// parseVersion parses a semantic version with an optional "v" prefix.
+// Build metadata after '+' is ignored, and a missing minor or patch number
+// is read as zero.
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(s, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return version{}, false
+	}
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		nums[i] = n
+	}
+
+	v := version{major: nums[0], minor: nums[1], patch: nums[2]}
+	if hasPre {
+		if pre == "" {
+			return version{}, false
+		}
+		v.pre = strings.Split(pre, ".")
+	}
+	return v, true
+}
+
+// isPrerelease reports whether v has a prerelease suffix such as -rc.1.
+func (v version) isPrerelease() bool {
+	return len(v.pre) > 0
+}
+
+// compare returns -1, 0 or +1 as v is lower than, equal to or higher than
+// o, following semver precedence: a prerelease sorts before its release,
+// numeric identifiers compare numerically and sort before alphanumeric ones.
+func (v version) compare(o version) int {
+	for _, d := range [][2]int{{v.major, o.major}, {v.minor, o.minor}, {v.patch, o.patch}} {
+		if d[0] != d[1] {
+			return cmpInt(d[0], d[1])
+		}
+	}
+
+	switch {
+	case !v.isPrerelease() && !o.isPrerelease():
+		return 0
+	case !v.isPrerelease():
+		return 1
+	case !o.isPrerelease():
+		return -1
+	}
+
+	for i := 0; i < len(v.pre) && i < len(o.pre); i++ {
+		if c := comparePreIdent(v.pre[i], o.pre[i]); c != 0 {
+			return c
+		}
+	}
+	return cmpInt(len(v.pre), len(o.pre))
+}
+
+func comparePreIdent(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmpInt(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
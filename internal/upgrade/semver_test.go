@@ -0,0 +1,58 @@
+package upgrade
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"v1.2.3", true},
+		{"1.2.3", true},
+		{"1.2", true},
+		{"v1.4.0-rc.1", true},
+		{"1.0.0+build.5", true},
+		{"dev", false},
+		{"1.2.3.4", false},
+		{"1.x.0", false},
+		{"1.0.0-", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := parseVersion(tt.input); ok != tt.ok {
+			t.Errorf("parseVersion(%q) ok = %v, want %v", tt.input, ok, tt.ok)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	// Each version is lower than the next, per the semver spec examples.
+	ordered := []string{
+		"0.9.0",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.2.0",
+		"1.10.0",
+		"2.0.0",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := parseVersion(ordered[i])
+		b, _ := parseVersion(ordered[i+1])
+		if a.compare(b) != -1 || b.compare(a) != 1 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	a, _ := parseVersion("v1.2.3")
+	b, _ := parseVersion("1.2.3+meta")
+	if a.compare(b) != 0 {
+		t.Error("expected build metadata to be ignored")
+	}
+}
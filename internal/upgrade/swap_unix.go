@@ -0,0 +1,11 @@
+//go:build !windows
+
+package upgrade
+
+import "os"
+
+// swapBinary moves the new binary at src over dst. Renaming over a running
+// executable is safe on Unix: the old inode stays alive until it exits.
+func swapBinary(src, dst string) error {
+	return os.Rename(src, dst)
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package upgrade
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// swapBinary moves the new binary at src over dst. Windows refuses to
+// overwrite or delete a running executable but allows renaming it, so the
+// old binary is moved aside first and deleted, or, while it is still
+// locked, scheduled for deletion at the next reboot.
+func swapBinary(src, dst string) error {
+	old := dst + ".old"
+	_ = os.Remove(old) // left over from a previous upgrade
+
+	if err := os.Rename(dst, old); err != nil {
+		return fmt.Errorf("failed to move running binary aside: %w", err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		_ = os.Rename(old, dst)
+		return err
+	}
+
+	if err := os.Remove(old); err != nil {
+		if p, err := windows.UTF16PtrFromString(old); err == nil {
+			_ = windows.MoveFileEx(p, nil, windows.MOVEFILE_DELAY_UNTIL_REBOOT)
+		}
+	}
+	return nil
+}
@@ -2,44 +2,129 @@
 package upgrade
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 )
 
-var httpClient = &http.Client{
-	Timeout: 30 * time.Second,
-}
-
 const (
 	repoOwner       = "jellydn"
 	repoName        = "dotenv-tui"
 	downloadBaseURL = "https://github.com/" + repoOwner + "/" + repoName + "/releases/download"
 )
 
-var githubAPIURL = "https://api.github.com/repos/" + repoOwner + "/" + repoName + "/releases/latest"
+var githubAPIURL = "https://api.github.com/repos/" + repoOwner + "/" + repoName + "/releases"
 
 // Release represents a GitHub release.
 type Release struct {
-	TagName string `json:"tag_name"`
+	TagName    string  `json:"tag_name"`
+	Body       string  `json:"body"`
+	Draft      bool    `json:"draft"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a GitHub release.
+type Asset struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	URL  string `json:"browser_download_url"`
+}
+
+// downloadURL returns where to download the asset, which GitHub lists with
+// it, or the standard location of release assets for tag if it does not.
+func (a Asset) downloadURL(tag string) string {
+	if a.URL != "" {
+		return a.URL
+	}
+	return fmt.Sprintf("%s/%s/%s", downloadBaseURL, tag, a.Name)
+}
+
+// asset returns the release asset called name, if GitHub listed it.
+func (r Release) asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
 }
 
-// Upgrade performs the upgrade to the latest version.
-func Upgrade(currentVersion string) error {
-	latestVersion, err := getLatestVersion()
+// Channel selects which releases are considered when upgrading.
+type Channel string
+
+// Release channels.
+const (
+	// ChannelStable only considers full releases.
+	ChannelStable Channel = "stable"
+	// ChannelPrerelease also considers release candidates and other
+	// prereleases.
+	ChannelPrerelease Channel = "prerelease"
+)
+
+// ParseChannel validates a channel name. An empty name selects ChannelStable.
+func ParseChannel(name string) (Channel, error) {
+	switch c := Channel(strings.ToLower(strings.TrimSpace(name))); c {
+	case "":
+		return ChannelStable, nil
+	case ChannelStable, ChannelPrerelease:
+		return c, nil
+	default:
+		return ChannelStable, fmt.Errorf("unknown release channel %q (use %s or %s)", name, ChannelStable, ChannelPrerelease)
+	}
+}
+
+// Options configures UpgradeWithOptions.
+type Options struct {
+	Channel Channel
+	// DryRun reports what would be upgraded, with the release notes, and
+	// stops before prompting or downloading anything.
+	DryRun bool
+	// Timeout bounds each network operation, including its retries; zero
+	// uses DefaultAPITimeout for release lookups and DefaultDownloadTimeout
+	// for downloads.
+	Timeout time.Duration
+}
+
+// timeout returns opts.Timeout, or def when it is not set.
+func (o Options) timeout(def time.Duration) time.Duration {
+	if o.Timeout > 0 {
+		return o.Timeout
+	}
+	return def
+}
+
+// Upgrade performs the upgrade to the latest stable version.
+func Upgrade(ctx context.Context, currentVersion string) error {
+	return UpgradeWithOptions(ctx, currentVersion, Options{})
+}
+
+// UpgradeWithOptions performs the upgrade to the newest release on the
+// configured channel. It never downgrades: a release that is not newer
+// than currentVersion by semver precedence is reported as up to date.
+// Cancelling ctx stops the release lookup or download; a partial download
+// is kept so the next upgrade resumes it.
+func UpgradeWithOptions(ctx context.Context, currentVersion string, opts Options) error {
+	lookupCtx, cancel := context.WithTimeout(ctx, opts.timeout(DefaultAPITimeout))
+	release, err := getLatestRelease(lookupCtx, opts.Channel)
+	cancel()
 	if err != nil {
 		return fmt.Errorf("failed to get latest version: %w", err)
 	}
 
 	currentVersion = strings.TrimPrefix(currentVersion, "v")
-	latestVersion = strings.TrimPrefix(latestVersion, "v")
+	latestVersion := strings.TrimPrefix(release.TagName, "v")
 
 	if currentVersion == "dev" {
 		fmt.Printf("Current version: dev\n")
@@ -51,11 +136,42 @@ func Upgrade(currentVersion string) error {
 	fmt.Printf("Current version: %s\n", currentVersion)
 	fmt.Printf("Latest version: %s\n", latestVersion)
 
-	if currentVersion == latestVersion {
+	if !isNewer(latestVersion, currentVersion) {
 		fmt.Println("Already up to date!")
 		return nil
 	}
 
+	if opts.DryRun {
+		printReleaseNotes(os.Stdout, release)
+	}
+
+	execPath, err := resolvedExecutable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable path: %w", err)
+	}
+
+	// Replacing a package-managed binary would confuse the package manager
+	// on its next upgrade, so defer to it instead.
+	if pm, ok := detectPackageManager(execPath, goBinDirs()); ok {
+		fmt.Printf("dotenv-tui was installed with %s. Upgrade it with:\n  %s\n", pm.Name, pm.Command)
+		return nil
+	}
+
+	osType, arch := detectPlatform()
+	asset, note, err := selectAsset(release, osType, arch, detectVariant(osType, arch))
+	if err != nil {
+		return err
+	}
+	if note != "" {
+		fmt.Printf("Note: %s\n", note)
+	}
+	checksum, _ := checksumAsset(release, asset)
+
+	if opts.DryRun {
+		printPlannedDownload(os.Stdout, release, asset, checksum, execPath)
+		return nil
+	}
+
 	fmt.Printf("Upgrade to %s? [y/N] ", latestVersion)
 	var response string
 	if _, err := fmt.Scanln(&response); err != nil {
@@ -67,42 +183,33 @@ func Upgrade(currentVersion string) error {
 		return nil
 	}
 
-	osType, arch := detectPlatform()
-
-	binaryName := fmt.Sprintf("dotenv-tui-%s-%s", osType, arch)
-	if osType == "windows" {
-		binaryName += ".exe"
-	}
-	checksumName := binaryName + ".sha256"
-
-	downloadURL := fmt.Sprintf("%s/v%s/%s", downloadBaseURL, latestVersion, binaryName)
-	checksumURL := fmt.Sprintf("%s/v%s/%s", downloadBaseURL, latestVersion, checksumName)
+	fmt.Printf("Downloading %s...\n", asset.Name)
 
-	fmt.Printf("Downloading %s...\n", binaryName)
-
-	tmpFile, tmpChecksum, err := downloadBinaryAndChecksum(downloadURL, checksumURL)
+	downloadCtx, cancel := context.WithTimeout(ctx, opts.timeout(DefaultDownloadTimeout))
+	defer cancel()
+	download, err := downloadBinaryAndChecksum(downloadCtx, release.TagName, asset, checksum)
 	if err != nil {
-		return fmt.Errorf("failed to download binary: %w", err)
-	}
-	defer func() { _ = os.Remove(tmpFile) }()
-	if tmpChecksum != "" {
-		defer func() { _ = os.Remove(tmpChecksum) }()
+		return fmt.Errorf("failed to download binary (run upgrade again to resume): %w", err)
 	}
+	defer func() { _ = os.Remove(download.path) }()
 
-	if tmpChecksum != "" {
+	if download.expected != "" {
 		fmt.Println("Verifying checksum...")
-		if err := verifyChecksum(tmpFile, tmpChecksum); err != nil {
+		if err := verifyChecksum(download.sha256, download.expected); err != nil {
 			return fmt.Errorf("checksum verification failed: %w", err)
 		}
 		fmt.Println("Checksum verified!")
 	}
 
-	execPath, err := os.Executable()
+	binaryPath, err := extractBinary(download.path, asset.Name, binaryName(osType))
 	if err != nil {
-		return fmt.Errorf("failed to get executable path: %w", err)
+		return fmt.Errorf("failed to unpack %s: %w", asset.Name, err)
+	}
+	if binaryPath != download.path {
+		defer func() { _ = os.Remove(binaryPath) }()
 	}
 
-	if err := replaceBinary(tmpFile, execPath); err != nil {
+	if err := replaceBinary(binaryPath, execPath); err != nil {
 		return fmt.Errorf("failed to replace binary: %w", err)
 	}
 
@@ -110,6 +217,35 @@ func Upgrade(currentVersion string) error {
 	return nil
 }
 
+// printReleaseNotes writes the notes GitHub has for release.
+func printReleaseNotes(w io.Writer, release Release) {
+	_, _ = fmt.Fprintf(w, "\nRelease notes for %s:\n", release.TagName)
+	_, _ = fmt.Fprintln(w, "---")
+	if notes := strings.TrimSpace(strings.ReplaceAll(release.Body, "\r\n", "\n")); notes != "" {
+		_, _ = fmt.Fprintln(w, notes)
+	} else {
+		_, _ = fmt.Fprintln(w, "(no release notes)")
+	}
+	_, _ = fmt.Fprintln(w, "---")
+	_, _ = fmt.Fprintln(w)
+}
+
+// printPlannedDownload describes the asset a real upgrade would fetch, the
+// checksum it would be verified against, if any, and the binary it would
+// replace.
+func printPlannedDownload(w io.Writer, release Release, asset, checksum Asset, execPath string) {
+	_, _ = fmt.Fprintln(w, "=== DRY RUN ===")
+	_, _ = fmt.Fprintf(w, "Asset: %s (%s)\n", asset.Name, formatBytes(asset.Size))
+	_, _ = fmt.Fprintf(w, "URL: %s\n", asset.downloadURL(release.TagName))
+	if checksum.Name != "" {
+		_, _ = fmt.Fprintf(w, "Checksum: %s\n", checksum.Name)
+	} else {
+		_, _ = fmt.Fprintln(w, "Checksum: none published, the download would not be verified")
+	}
+	_, _ = fmt.Fprintf(w, "Would replace: %s\n", execPath)
+	_, _ = fmt.Fprintln(w, "Nothing was downloaded or changed.")
+}
+
 func detectPlatform() (string, string) {
 	osType := runtime.GOOS
 	arch := runtime.GOARCH
@@ -133,53 +269,169 @@ func detectPlatform() (string, string) {
 	return osType, arch
 }
 
-// getLatestVersion fetches the latest release version from GitHub.
-func getLatestVersion() (string, error) {
-	resp, err := httpClient.Get(githubAPIURL)
+// isNewer reports whether latest should replace current. Versions that do
+// not parse as semver fall back to a plain inequality check.
+func isNewer(latest, current string) bool {
+	lv, lok := parseVersion(latest)
+	cv, cok := parseVersion(current)
+	if !lok || !cok {
+		return latest != current
+	}
+	return lv.compare(cv) > 0
+}
+
+// getLatestVersion returns the tag of the newest release on channel.
+func getLatestVersion(ctx context.Context, channel Channel) (string, error) {
+	release, err := getLatestRelease(ctx, channel)
 	if err != nil {
 		return "", err
 	}
+	return release.TagName, nil
+}
+
+// getLatestRelease fetches the releases from GitHub and returns the newest
+// one on channel by semver precedence. Drafts and tags that are not
+// semantic versions are ignored.
+func getLatestRelease(ctx context.Context, channel Channel) (Release, error) {
+	resp, err := getAPIWithRetry(ctx, githubAPIURL+"?per_page=100")
+	if err != nil {
+		return Release{}, err
+	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := checkRateLimit(resp); err != nil {
+		return Release{}, err
+	}
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return Release{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	var release Release
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		return "", err
+	var releases []Release
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return Release{}, err
 	}
 
-	if release.TagName == "" {
-		return "", fmt.Errorf("empty tag name in release")
+	var (
+		bestRelease Release
+		best        version
+	)
+	for _, r := range releases {
+		v, ok := parseVersion(r.TagName)
+		if !ok || r.Draft {
+			continue
+		}
+		if channel != ChannelPrerelease && (r.Prerelease || v.isPrerelease()) {
+			continue
+		}
+		if bestRelease.TagName == "" || v.compare(best) > 0 {
+			bestRelease, best = r, v
+		}
 	}
 
-	return release.TagName, nil
+	if bestRelease.TagName == "" {
+		if channel == "" {
+			channel = ChannelStable
+		}
+		return Release{}, fmt.Errorf("no %s releases found", channel)
+	}
+	return bestRelease, nil
 }
 
-func downloadBinaryAndChecksum(binaryURL, checksumURL string) (string, string, error) {
-	binaryFile, err := downloadFile(binaryURL, "dotenv-tui-upgrade-*")
+// downloaded is a binary fetched by downloadBinaryAndChecksum.
+type downloaded struct {
+	path string
+	// sha256 is the digest of the file, computed as it was written.
+	sha256 string
+	// expected is the digest published alongside the binary, or empty when
+	// the checksum file is not available.
+	expected string
+}
+
+// downloadBinaryAndChecksum fetches the binary asset of the release tagged
+// tag and the checksum asset listing its digest at the same time; checksum
+// is the zero Asset when the release publishes none. The binary is written
+// to a file named after its URL in a private cache directory, so an
+// interrupted download is resumed by the next attempt rather than started
+// over. Only a release with a checksum is resumed, since resumed bytes must
+// be verified; if the checksum then cannot be fetched, the download fails.
+func downloadBinaryAndChecksum(ctx context.Context, tag string, binary, checksum Asset) (downloaded, error) {
+	binaryURL := binary.downloadURL(tag)
+	path, err := partialPath(binaryURL)
+	if err != nil {
+		return downloaded{}, err
+	}
+	resume := checksum.Name != ""
+	resumed := false
+	if info, err := os.Lstat(path); err == nil && resume && info.Size() > 0 {
+		resumed = true
+	}
+
+	var (
+		expected    string
+		checksumErr = errors.New("no checksum published")
+		done        = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		if checksum.Name != "" {
+			expected, checksumErr = fetchChecksum(ctx, checksum.downloadURL(tag), binary.Name)
+		}
+	}()
+
+	sum, err := downloadFile(ctx, binaryURL, path, resume, os.Stdout)
+	<-done
 	if err != nil {
-		return "", "", err
+		return downloaded{}, err
 	}
 
-	if err := os.Chmod(binaryFile, 0755); err != nil {
-		_ = os.Remove(binaryFile)
-		return "", "", err
+	if checksumErr != nil && resumed {
+		_ = os.Remove(path)
+		return downloaded{}, fmt.Errorf("cannot verify the resumed download: %w", checksumErr)
 	}
 
-	checksumFile, err := downloadFile(checksumURL, "dotenv-tui-upgrade-checksum-*")
-	if err != nil {
+	if err := os.Chmod(path, 0755); err != nil {
+		_ = os.Remove(path)
+		return downloaded{}, err
+	}
+
+	if checksumErr != nil {
 		fmt.Println("Warning: Checksum file not available, skipping verification")
-		return binaryFile, "", nil
 	}
+	return downloaded{path: path, sha256: sum, expected: expected}, nil
+}
 
-	return binaryFile, checksumFile, nil
+// partialPath returns where the download of url is kept until it
+// completes. It depends only on url, which names the release and asset, so
+// a later attempt finds the bytes an interrupted one left. The file lives
+// in a directory under the user's cache directory that only the user can
+// enter, so other users cannot plant bytes or links there.
+func partialPath(url string) (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find a directory for the download: %w", err)
+	}
+	dir := filepath.Join(cache, "dotenv-tui", "upgrade")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create download directory: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to create download directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("download directory %s is not a directory", dir)
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to restrict download directory: %w", err)
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:6])+".partial"), nil
 }
 
-// downloadFile downloads a file from the given URL and saves it to a temp file.
-func downloadFile(url, pattern string) (string, error) {
-	resp, err := httpClient.Get(url)
+// fetchChecksum downloads the checksum file at url and returns the digest
+// it lists for the asset called name.
+func fetchChecksum(ctx context.Context, url, name string) (string, error) {
+	resp, err := getWithRetry(ctx, url)
 	if err != nil {
 		return "", err
 	}
@@ -188,69 +440,196 @@ func downloadFile(url, pattern string) (string, error) {
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
-
-	tmpFile, err := os.CreateTemp("", pattern)
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
 	if err != nil {
 		return "", err
 	}
+	return parseChecksum(data, name)
+}
 
-	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
-		_ = tmpFile.Close()
+// downloadFile downloads url to path and returns the SHA256 of the whole
+// file, computed while it is written. With resume, the bytes already in
+// path are kept and the rest is requested with a Range request; otherwise
+// path is emptied first. A connection that drops mid-transfer is resumed
+// the same way, up to retryAttempts times. Progress is reported to progress
+// when it is not nil. On failure path is kept, so a later call can resume
+// it.
+func downloadFile(ctx context.Context, url, path string, resume bool, progress io.Writer) (string, error) {
+	file, err := openPartial(path, resume)
+	if err != nil {
 		return "", err
 	}
+	defer func() { _ = file.Close() }()
 
-	if err := tmpFile.Close(); err != nil {
+	// Hash what an earlier attempt left, so the digest covers the whole file.
+	digest := sha256.New()
+	offset, err := io.Copy(digest, file)
+	if err != nil {
 		return "", err
 	}
 
-	return tmpFile.Name(), nil
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		retry, fetchErr := fetchFrom(ctx, url, file, offset, digest, progress)
+		if fetchErr == nil {
+			break
+		}
+		if !retry || attempt == retryAttempts {
+			return "", fetchErr
+		}
+		if ctx.Err() != nil {
+			return "", retryError(ctx, fetchErr)
+		}
+		if offset, err = file.Seek(0, io.SeekCurrent); err != nil {
+			return "", err
+		}
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return "", retryError(ctx, fetchErr)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
 }
 
-func verifyChecksum(binaryPath, checksumPath string) error {
-	expectedChecksum, err := readChecksumFile(checksumPath)
+// openPartial opens the partial download at path for reading and writing,
+// creating it if needed, and empties it unless resume is set. Anything but
+// a regular file, such as a symlink planted to redirect the write, is
+// refused.
+func openPartial(path string, resume bool) (*os.File, error) {
+	if info, err := os.Lstat(path); err == nil && !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("refusing to download to %s: not a regular file", path)
+	}
+	flags := os.O_RDWR | os.O_CREATE
+	if !resume {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0600)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	// The path could have been swapped for a link between the check and
+	// the open; make sure the file opened is the one at path.
+	opened, err := file.Stat()
+	if err == nil {
+		var info os.FileInfo
+		if info, err = os.Lstat(path); err == nil && !os.SameFile(info, opened) {
+			err = fmt.Errorf("refusing to download to %s: the file was replaced", path)
+		}
 	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
 
-	actualChecksum, err := calculateFileSHA256(binaryPath)
+// fetchFrom requests url from offset on and appends the response to file,
+// which is positioned at offset, and to digest. A server that ignores the
+// range sends the whole file, which is then written from the start. It
+// reports whether a failed fetch is worth resuming: the transfer broke
+// off, or the partial file could not be resumed and was emptied.
+func fetchFrom(ctx context.Context, url string, file *os.File, offset int64, digest hash.Hash, progress io.Writer) (bool, error) {
+	resp, err := getRangeWithRetry(ctx, url, offset)
 	if err != nil {
-		return err
+		return false, err
 	}
+	defer func() { _ = resp.Body.Close() }()
 
-	if expectedChecksum != actualChecksum {
-		return fmt.Errorf("checksum mismatch: expected %s, got %s", expectedChecksum, actualChecksum)
+	total := resp.ContentLength
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent && rangeStart(resp) == offset:
+		if total >= 0 {
+			total += offset
+		}
+	case resp.StatusCode == http.StatusOK:
+		if offset > 0 {
+			if err := restart(file, digest); err != nil {
+				return false, err
+			}
+			offset = 0
+		}
+	case offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is no prefix of the asset; start over.
+		if err := restart(file, digest); err != nil {
+			return false, err
+		}
+		return true, fmt.Errorf("cannot resume download: unexpected status code: %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	return nil
+	var dst = io.MultiWriter(file, digest)
+	var pw *progressWriter
+	if progress != nil {
+		pw = &progressWriter{out: progress, total: total, written: offset}
+		dst = io.MultiWriter(dst, pw)
+	}
+	_, err = io.Copy(dst, resp.Body)
+	if pw != nil {
+		pw.finish()
+	}
+	return err != nil, err
 }
 
-func readChecksumFile(path string) (string, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return "", err
+// rangeStart returns the first byte of a partial response, from its
+// Content-Range header, or -1 if the header is missing or malformed.
+func rangeStart(resp *http.Response) int64 {
+	var start, end int64
+	var size string
+	if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d/%s", &start, &end, &size); err != nil {
+		return -1
 	}
+	return start
+}
 
-	fields := strings.Fields(string(data))
-	if len(fields) == 0 {
-		return "", fmt.Errorf("empty checksum file")
+// restart empties file and digest for a download from the beginning.
+func restart(file *os.File, digest hash.Hash) error {
+	digest.Reset()
+	if err := file.Truncate(0); err != nil {
+		return err
 	}
-	return fields[0], nil
+	_, err := file.Seek(0, io.SeekStart)
+	return err
 }
 
-// calculateFileSHA256 calculates the SHA256 hash of a file.
-func calculateFileSHA256(path string) (string, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return "", err
+// verifyChecksum compares the digest computed while downloading with the
+// published one.
+func verifyChecksum(actual, expected string) error {
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", expected, actual)
 	}
-	defer func() { _ = file.Close() }()
+	return nil
+}
 
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", err
+// parseChecksum returns the digest for the file called name from a
+// checksum file in sha256sum format, "<digest>  <name>" per line. A file
+// with a single line is taken to be about name whatever it calls it.
+func parseChecksum(data []byte, name string) (string, error) {
+	var lines [][]string
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
 	}
-
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	switch len(lines) {
+	case 0:
+		return "", fmt.Errorf("empty checksum file")
+	case 1:
+		return lines[0][0], nil
+	}
+	for _, fields := range lines {
+		// sha256sum marks files hashed in binary mode with a '*'.
+		if len(fields) > 1 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("checksum file lists no digest for %s", name)
 }
 
 func replaceBinary(src, dst string) error {
@@ -263,7 +642,7 @@ func replaceBinary(src, dst string) error {
 		_ = os.Remove(tmpDst)
 		return err
 	}
-	if err := os.Rename(tmpDst, dst); err != nil {
+	if err := swapBinary(tmpDst, dst); err != nil {
 		_ = os.Remove(tmpDst)
 		return err
 	}
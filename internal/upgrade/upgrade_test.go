@@ -3,15 +3,23 @@ package upgrade
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestDetectPlatform(t *testing.T) {
@@ -91,11 +99,13 @@ func TestDetectPlatformAliases(t *testing.T) {
 }
 
 func TestGetLatestVersion(t *testing.T) {
+	fastRetries(t)
+
 	t.Run("successful version fetch", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"tag_name": "v1.2.3"}`))
+			_, _ = w.Write([]byte(`[{"tag_name": "v1.2.3"}]`))
 		}))
 		defer server.Close()
 
@@ -103,7 +113,7 @@ func TestGetLatestVersion(t *testing.T) {
 		githubAPIURL = server.URL
 		defer func() { githubAPIURL = original }()
 
-		version, err := getLatestVersion()
+		version, err := getLatestVersion(context.Background(), ChannelStable)
 		if err != nil {
 			t.Fatalf("getLatestVersion() unexpected error: %v", err)
 		}
@@ -117,7 +127,7 @@ func TestGetLatestVersion(t *testing.T) {
 		githubAPIURL = "http://localhost:1" // connection refused
 		defer func() { githubAPIURL = original }()
 
-		_, err := getLatestVersion()
+		_, err := getLatestVersion(context.Background(), ChannelStable)
 		if err == nil {
 			t.Error("getLatestVersion() expected error for network failure, got nil")
 		}
@@ -133,7 +143,7 @@ func TestGetLatestVersion(t *testing.T) {
 		githubAPIURL = server.URL
 		defer func() { githubAPIURL = original }()
 
-		_, err := getLatestVersion()
+		_, err := getLatestVersion(context.Background(), ChannelStable)
 		if err == nil {
 			t.Error("getLatestVersion() expected error for non-200 status, got nil")
 		}
@@ -143,7 +153,7 @@ func TestGetLatestVersion(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"tag_name": ""}`))
+			_, _ = w.Write([]byte(`[{"tag_name": ""}]`))
 		}))
 		defer server.Close()
 
@@ -151,7 +161,7 @@ func TestGetLatestVersion(t *testing.T) {
 		githubAPIURL = server.URL
 		defer func() { githubAPIURL = original }()
 
-		_, err := getLatestVersion()
+		_, err := getLatestVersion(context.Background(), ChannelStable)
 		if err == nil {
 			t.Error("getLatestVersion() expected error for empty tag name, got nil")
 		}
@@ -169,48 +179,217 @@ func TestGetLatestVersion(t *testing.T) {
 		githubAPIURL = server.URL
 		defer func() { githubAPIURL = original }()
 
-		_, err := getLatestVersion()
+		_, err := getLatestVersion(context.Background(), ChannelStable)
 		if err == nil {
 			t.Error("getLatestVersion() expected error for invalid JSON, got nil")
 		}
 	})
 }
 
+func TestGetLatestVersionChannels(t *testing.T) {
+	// Deliberately out of order: v1.10.0 must beat v1.9.0 even though it
+	// sorts lower as a string.
+	releases := `[
+		{"tag_name": "v1.9.0"},
+		{"tag_name": "v2.0.0-rc.1", "prerelease": true},
+		{"tag_name": "v1.10.0"},
+		{"tag_name": "v3.0.0", "draft": true},
+		{"tag_name": "nightly"},
+		{"tag_name": "v2.0.0-beta.1"}
+	]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(releases))
+	}))
+	defer server.Close()
+
+	original := githubAPIURL
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = original }()
+
+	tests := []struct {
+		channel Channel
+		want    string
+	}{
+		{ChannelStable, "v1.10.0"},
+		{ChannelPrerelease, "v2.0.0-rc.1"},
+	}
+	for _, tt := range tests {
+		got, err := getLatestVersion(context.Background(), tt.channel)
+		if err != nil {
+			t.Fatalf("getLatestVersion(%s) unexpected error: %v", tt.channel, err)
+		}
+		if got != tt.want {
+			t.Errorf("getLatestVersion(%s) = %q, want %q", tt.channel, got, tt.want)
+		}
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"1.10.0", "1.9.0", true},
+		{"1.9.0", "1.10.0", false},
+		{"1.2.3", "1.2.3", false},
+		{"1.3.0-rc.1", "1.2.3", true},
+		{"1.3.0", "1.3.0-rc.1", true},
+		{"1.3.0-rc.1", "1.3.0", false},
+		{"1.2.3", "abc1234", true},
+	}
+	for _, tt := range tests {
+		if got := isNewer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("isNewer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestParseChannel(t *testing.T) {
+	for input, want := range map[string]Channel{"": ChannelStable, "stable": ChannelStable, "Prerelease": ChannelPrerelease} {
+		got, err := ParseChannel(input)
+		if err != nil || got != want {
+			t.Errorf("ParseChannel(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseChannel("beta"); err == nil {
+		t.Error("expected error for unknown channel")
+	}
+}
+
+// sha256Hex returns the hex SHA256 digest of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 func TestDownloadFile(t *testing.T) {
+	fastRetries(t)
+	content := []byte(strings.Repeat("test binary content ", 100))
+
+	download := func(t *testing.T, url, path string) string {
+		t.Helper()
+		sum, err := downloadFile(context.Background(), url, path, true, nil)
+		if err != nil {
+			t.Fatalf("downloadFile() error = %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read downloaded file: %v", err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Errorf("downloadFile() content = %q, expected %q", got, content)
+		}
+		if want := sha256Hex(content); sum != want {
+			t.Errorf("downloadFile() sha256 = %s, want %s", sum, want)
+		}
+		return sum
+	}
+
 	t.Run("successful download", func(t *testing.T) {
-		expectedContent := []byte("test binary content")
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write(expectedContent)
+			_, _ = w.Write(content)
 		}))
 		defer server.Close()
 
-		tmpPath, err := downloadFile(server.URL, "test-download-*")
+		download(t, server.URL, filepath.Join(t.TempDir(), "download"))
+	})
 
-		if err != nil {
-			t.Fatalf("downloadFile() error = %v", err)
+	t.Run("resumes a partial file", func(t *testing.T) {
+		var ranges []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ranges = append(ranges, r.Header.Get("Range"))
+			http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(content))
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "download")
+		if err := os.WriteFile(path, content[:700], 0600); err != nil {
+			t.Fatal(err)
+		}
+		download(t, server.URL, path)
+		if !reflect.DeepEqual(ranges, []string{"bytes=700-"}) {
+			t.Errorf("Range headers = %q, want one request from byte 700", ranges)
 		}
+	})
 
-		if tmpPath == "" {
-			t.Fatal("downloadFile() returned empty path")
+	t.Run("starts over when the range is ignored", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(content)
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "download")
+		if err := os.WriteFile(path, []byte("stale bytes"), 0600); err != nil {
+			t.Fatal(err)
 		}
+		download(t, server.URL, path)
+	})
 
-		actualContent, err := os.ReadFile(tmpPath)
-		if err != nil {
-			t.Fatalf("Failed to read downloaded file: %v", err)
+	t.Run("resumes a dropped connection", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				// Promise the whole file but stop halfway through.
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				_, _ = w.Write(content[:len(content)/2])
+				return
+			}
+			http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(content))
+		}))
+		defer server.Close()
+
+		download(t, server.URL, filepath.Join(t.TempDir(), "download"))
+		if got := calls.Load(); got != 2 {
+			t.Errorf("server called %d times, want 2", got)
 		}
+	})
 
-		if !bytes.Equal(actualContent, expectedContent) {
-			t.Errorf("downloadFile() content = %q, expected %q", actualContent, expectedContent)
+	t.Run("empties the file when not resuming", func(t *testing.T) {
+		var ranges []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ranges = append(ranges, r.Header.Get("Range"))
+			http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(content))
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "download")
+		if err := os.WriteFile(path, []byte("planted bytes"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		sum, err := downloadFile(context.Background(), server.URL, path, false, nil)
+		if err != nil {
+			t.Fatalf("downloadFile() error = %v", err)
+		}
+		if got, _ := os.ReadFile(path); !bytes.Equal(got, content) || sum != sha256Hex(content) {
+			t.Errorf("downloadFile() kept the planted bytes")
 		}
+		if !reflect.DeepEqual(ranges, []string{""}) {
+			t.Errorf("Range headers = %q, want the whole file requested", ranges)
+		}
+	})
 
-		_ = os.Remove(tmpPath)
+	t.Run("refuses a symlink", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "target")
+		if err := os.WriteFile(target, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, "download")
+		if err := os.Symlink(target, path); err != nil {
+			t.Skipf("cannot create symlinks: %v", err)
+		}
+		_, err := downloadFile(context.Background(), "http://127.0.0.1:0", path, true, nil)
+		if err == nil || !strings.Contains(err.Error(), "not a regular file") {
+			t.Errorf("downloadFile() error = %v, want the symlink refused", err)
+		}
 	})
 
 	t.Run("network error", func(t *testing.T) {
 		invalidURL := "http://invalid-url-that-does-not-exist-12345.com"
 
-		_, err := downloadFile(invalidURL, "test-download-*")
+		_, err := downloadFile(context.Background(), invalidURL, filepath.Join(t.TempDir(), "download"), true, nil)
 
 		if err == nil {
 			t.Error("downloadFile() expected error for invalid URL, got nil")
@@ -223,7 +402,7 @@ func TestDownloadFile(t *testing.T) {
 		}))
 		defer server.Close()
 
-		_, err := downloadFile(server.URL, "test-download-*")
+		_, err := downloadFile(context.Background(), server.URL, filepath.Join(t.TempDir(), "download"), true, nil)
 
 		if err == nil {
 			t.Error("downloadFile() expected error for 404, got nil")
@@ -231,7 +410,7 @@ func TestDownloadFile(t *testing.T) {
 	})
 }
 
-func TestReadChecksumFile(t *testing.T) {
+func TestParseChecksum(t *testing.T) {
 	tests := []struct {
 		name        string
 		content     string
@@ -271,243 +450,43 @@ func TestReadChecksumFile(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tmpFile, err := os.CreateTemp("", "checksum-*")
-			if err != nil {
-				t.Fatalf("Failed to create temp file: %v", err)
-			}
-			defer func() { _ = os.Remove(tmpFile.Name()) }()
-
-			if _, err := tmpFile.WriteString(tt.content); err != nil {
-				t.Fatalf("Failed to write to temp file: %v", err)
-			}
-			if err := tmpFile.Close(); err != nil {
-				t.Fatalf("Failed to close temp file: %v", err)
-			}
-
-			result, err := readChecksumFile(tmpFile.Name())
+			result, err := parseChecksum([]byte(tt.content), "dotenv-tui-linux-amd64")
 
 			if tt.expectError && err == nil {
-				t.Error("readChecksumFile() expected error, got nil")
+				t.Error("parseChecksum() expected error, got nil")
 			}
 			if !tt.expectError && err != nil {
-				t.Errorf("readChecksumFile() unexpected error = %v", err)
+				t.Errorf("parseChecksum() unexpected error = %v", err)
 			}
 			if !tt.expectError && result != tt.expected {
-				t.Errorf("readChecksumFile() = %q, expected %q", result, tt.expected)
+				t.Errorf("parseChecksum() = %q, expected %q", result, tt.expected)
 			}
 		})
 	}
-
-	t.Run("file not found", func(t *testing.T) {
-		nonExistentPath := "/tmp/non-existent-checksum-file-12345"
-
-		_, err := readChecksumFile(nonExistentPath)
-
-		if err == nil {
-			t.Error("readChecksumFile() expected error for non-existent file, got nil")
-		}
-	})
 }
 
-func TestCalculateFileSHA256(t *testing.T) {
-	tests := []struct {
-		name        string
-		content     string
-		expectError bool
-	}{
-		{
-			name:        "empty file",
-			content:     "",
-			expectError: false,
-		},
-		{
-			name:        "simple text",
-			content:     "hello world",
-			expectError: false,
-		},
-		{
-			name:        "binary data",
-			content:     "\x00\x01\x02\x03\xff\xfe\xfd",
-			expectError: false,
-		},
-		{
-			name:        "larger content",
-			content:     strings.Repeat("a", 1000),
-			expectError: false,
-		},
+func TestParseChecksumList(t *testing.T) {
+	list := []byte("1111  dotenv-tui_1.2.0_darwin_arm64.tar.gz\n2222 *dotenv-tui_1.2.0_linux_amd64.tar.gz\n\n")
+	if got, err := parseChecksum(list, "dotenv-tui_1.2.0_linux_amd64.tar.gz"); err != nil || got != "2222" {
+		t.Errorf("parseChecksum() = %q, %v; want 2222", got, err)
 	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tmpFile, err := os.CreateTemp("", "sha256-*")
-			if err != nil {
-				t.Fatalf("Failed to create temp file: %v", err)
-			}
-			defer func() { _ = os.Remove(tmpFile.Name()) }()
-
-			if _, err := tmpFile.WriteString(tt.content); err != nil {
-				t.Fatalf("Failed to write to temp file: %v", err)
-			}
-			if err := tmpFile.Close(); err != nil {
-				t.Fatalf("Failed to close temp file: %v", err)
-			}
-
-			result, err := calculateFileSHA256(tmpFile.Name())
-
-			if tt.expectError && err == nil {
-				t.Error("calculateFileSHA256() expected error, got nil")
-			}
-			if !tt.expectError && err != nil {
-				t.Errorf("calculateFileSHA256() unexpected error = %v", err)
-			}
-			if !tt.expectError {
-				if len(result) != 64 {
-					t.Errorf("calculateFileSHA256() returned hash of length %d, expected 64", len(result))
-				}
-				for _, c := range result {
-					if !isHexDigit(byte(c)) {
-						t.Errorf("calculateFileSHA256() returned invalid hex: %q", result)
-						break
-					}
-				}
-			}
-		})
+	if _, err := parseChecksum(list, "dotenv-tui_1.2.0_windows_amd64.zip"); err == nil {
+		t.Error("parseChecksum() expected error for a file the list does not cover, got nil")
 	}
-
-	t.Run("file not found", func(t *testing.T) {
-		nonExistentPath := "/tmp/non-existent-file-12345"
-
-		_, err := calculateFileSHA256(nonExistentPath)
-
-		if err == nil {
-			t.Error("calculateFileSHA256() expected error for non-existent file, got nil")
-		}
-	})
-}
-
-func isHexDigit(c byte) bool {
-	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
 }
 
 func TestVerifyChecksum(t *testing.T) {
-	t.Run("valid checksum", func(t *testing.T) {
-		content := "test content for checksum"
-		tmpFile, err := os.CreateTemp("", "binary-*")
-		if err != nil {
-			t.Fatalf("Failed to create temp file: %v", err)
-		}
-		defer func() { _ = os.Remove(tmpFile.Name()) }()
+	sum := sha256Hex([]byte("test content for checksum"))
 
-		if _, err := tmpFile.WriteString(content); err != nil {
-			t.Fatalf("Failed to write to temp file: %v", err)
-		}
-		if err := tmpFile.Close(); err != nil {
-			t.Fatalf("Failed to close temp file: %v", err)
-		}
-
-		actualHash, err := calculateFileSHA256(tmpFile.Name())
-		if err != nil {
-			t.Fatalf("Failed to calculate checksum: %v", err)
-		}
-
-		checksumFile, err := os.CreateTemp("", "checksum-*")
-		if err != nil {
-			t.Fatalf("Failed to create checksum temp file: %v", err)
-		}
-		defer func() { _ = os.Remove(checksumFile.Name()) }()
-
-		if _, err := checksumFile.WriteString(actualHash + "  " + filepath.Base(tmpFile.Name())); err != nil {
-			t.Fatalf("Failed to write checksum: %v", err)
-		}
-		if err := checksumFile.Close(); err != nil {
-			t.Fatalf("Failed to close checksum file: %v", err)
-		}
-
-		err = verifyChecksum(tmpFile.Name(), checksumFile.Name())
-
-		if err != nil {
-			t.Errorf("verifyChecksum() unexpected error = %v", err)
-		}
-	})
-
-	t.Run("checksum mismatch", func(t *testing.T) {
-		content := "test content for checksum"
-		tmpFile, err := os.CreateTemp("", "binary-*")
-		if err != nil {
-			t.Fatalf("Failed to create temp file: %v", err)
-		}
-		defer func() { _ = os.Remove(tmpFile.Name()) }()
-
-		if _, err := tmpFile.WriteString(content); err != nil {
-			t.Fatalf("Failed to write to temp file: %v", err)
-		}
-		if err := tmpFile.Close(); err != nil {
-			t.Fatalf("Failed to close temp file: %v", err)
-		}
-
-		checksumFile, err := os.CreateTemp("", "checksum-*")
-		if err != nil {
-			t.Fatalf("Failed to create checksum temp file: %v", err)
-		}
-		defer func() { _ = os.Remove(checksumFile.Name()) }()
-
-		wrongHash := strings.Repeat("0", 64)
-		if _, err := checksumFile.WriteString(wrongHash + "  " + filepath.Base(tmpFile.Name())); err != nil {
-			t.Fatalf("Failed to write checksum: %v", err)
-		}
-		if err := checksumFile.Close(); err != nil {
-			t.Fatalf("Failed to close checksum file: %v", err)
-		}
-
-		err = verifyChecksum(tmpFile.Name(), checksumFile.Name())
-
-		if err == nil {
-			t.Error("verifyChecksum() expected error for mismatch, got nil")
-		}
-	})
-
-	t.Run("missing binary file", func(t *testing.T) {
-		checksumFile, err := os.CreateTemp("", "checksum-*")
-		if err != nil {
-			t.Fatalf("Failed to create checksum temp file: %v", err)
-		}
-		defer func() { _ = os.Remove(checksumFile.Name()) }()
-
-		if _, err := checksumFile.WriteString(strings.Repeat("0", 64)); err != nil {
-			t.Fatalf("Failed to write checksum: %v", err)
-		}
-		if err := checksumFile.Close(); err != nil {
-			t.Fatalf("Failed to close checksum file: %v", err)
-		}
-
-		err = verifyChecksum("/tmp/non-existent-binary-12345", checksumFile.Name())
-
-		if err == nil {
-			t.Error("verifyChecksum() expected error for missing binary, got nil")
-		}
-	})
-
-	t.Run("missing checksum file", func(t *testing.T) {
-		content := "test content"
-		tmpFile, err := os.CreateTemp("", "binary-*")
-		if err != nil {
-			t.Fatalf("Failed to create temp file: %v", err)
-		}
-		defer func() { _ = os.Remove(tmpFile.Name()) }()
-
-		if _, err := tmpFile.WriteString(content); err != nil {
-			t.Fatalf("Failed to write to temp file: %v", err)
-		}
-		if err := tmpFile.Close(); err != nil {
-			t.Fatalf("Failed to close temp file: %v", err)
-		}
-
-		err = verifyChecksum(tmpFile.Name(), "/tmp/non-existent-checksum-12345")
-
-		if err == nil {
-			t.Error("verifyChecksum() expected error for missing checksum file, got nil")
-		}
-	})
+	if err := verifyChecksum(sum, sum); err != nil {
+		t.Errorf("verifyChecksum() unexpected error = %v", err)
+	}
+	if err := verifyChecksum(sum, strings.ToUpper(sum)); err != nil {
+		t.Errorf("verifyChecksum() upper-case digest: unexpected error = %v", err)
+	}
+	if err := verifyChecksum(sum, strings.Repeat("0", 64)); err == nil {
+		t.Error("verifyChecksum() expected error for mismatch, got nil")
+	}
 }
 
 func TestCopyFile(t *testing.T) {
@@ -704,47 +683,51 @@ func TestReplaceBinary(t *testing.T) {
 }
 
 func TestDownloadBinaryAndChecksum(t *testing.T) {
+	fastRetries(t)
+
+	privateCache(t)
+
 	t.Run("successful download with checksum", func(t *testing.T) {
 		binaryContent := []byte("binary content")
 		checksumContent := []byte("abc123  dotenv-tui-linux-amd64")
 
-		var binaryRequested, checksumRequested bool
+		var binaryRequested, checksumRequested atomic.Bool
 
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if strings.HasSuffix(r.URL.Path, "binary") {
-				binaryRequested = true
+				binaryRequested.Store(true)
 				w.WriteHeader(http.StatusOK)
 				_, _ = w.Write(binaryContent)
 			} else {
-				checksumRequested = true
+				checksumRequested.Store(true)
 				w.WriteHeader(http.StatusOK)
 				_, _ = w.Write(checksumContent)
 			}
 		}))
 		defer server.Close()
 
-		binaryURL := server.URL + "/binary"
-		checksumURL := server.URL + "/checksum"
+		binary := Asset{Name: "dotenv-tui-linux-amd64", URL: server.URL + "/binary"}
+		checksum := Asset{Name: "dotenv-tui-linux-amd64.sha256", URL: server.URL + "/checksum"}
 
-		binaryPath, checksumPath, err := downloadBinaryAndChecksum(binaryURL, checksumURL)
+		d, err := downloadBinaryAndChecksum(context.Background(), "v1.2.0", binary, checksum)
 
 		if err != nil {
 			t.Fatalf("downloadBinaryAndChecksum() error = %v", err)
 		}
 
-		if !binaryRequested {
+		if !binaryRequested.Load() {
 			t.Error("downloadBinaryAndChecksum() binary was not requested")
 		}
-		if !checksumRequested {
+		if !checksumRequested.Load() {
 			t.Error("downloadBinaryAndChecksum() checksum was not requested")
 		}
 
-		if binaryPath == "" {
+		if d.path == "" {
 			t.Fatal("downloadBinaryAndChecksum() binary path is empty")
 		}
-		defer func() { _ = os.Remove(binaryPath) }()
+		defer func() { _ = os.Remove(d.path) }()
 
-		actualContent, err := os.ReadFile(binaryPath)
+		actualContent, err := os.ReadFile(d.path)
 		if err != nil {
 			t.Fatalf("Failed to read binary file: %v", err)
 		}
@@ -752,22 +735,14 @@ func TestDownloadBinaryAndChecksum(t *testing.T) {
 		if !bytes.Equal(actualContent, binaryContent) {
 			t.Errorf("downloadBinaryAndChecksum() binary content mismatch")
 		}
-
-		if checksumPath == "" {
-			t.Fatal("downloadBinaryAndChecksum() checksum path is empty")
+		if want := sha256Hex(binaryContent); d.sha256 != want {
+			t.Errorf("downloadBinaryAndChecksum() sha256 = %s, want %s", d.sha256, want)
 		}
-		defer func() { _ = os.Remove(checksumPath) }()
-
-		checksumData, err := os.ReadFile(checksumPath)
-		if err != nil {
-			t.Fatalf("Failed to read checksum file: %v", err)
-		}
-
-		if !bytes.Equal(checksumData, checksumContent) {
-			t.Errorf("downloadBinaryAndChecksum() checksum content mismatch")
+		if d.expected != "abc123" {
+			t.Errorf("downloadBinaryAndChecksum() expected checksum = %q, want abc123", d.expected)
 		}
 
-		info, err := os.Stat(binaryPath)
+		info, err := os.Stat(d.path)
 		if err != nil {
 			t.Fatalf("Failed to stat binary: %v", err)
 		}
@@ -783,7 +758,9 @@ func TestDownloadBinaryAndChecksum(t *testing.T) {
 		}))
 		defer server.Close()
 
-		_, _, err := downloadBinaryAndChecksum(server.URL+"/binary", server.URL+"/checksum")
+		_, err := downloadBinaryAndChecksum(context.Background(), "v1.2.0",
+			Asset{Name: "dotenv-tui-linux-amd64", URL: server.URL + "/binary"},
+			Asset{Name: "dotenv-tui-linux-amd64.sha256", URL: server.URL + "/checksum"})
 
 		if err == nil {
 			t.Error("downloadBinaryAndChecksum() expected error for failed binary download, got nil")
@@ -803,14 +780,14 @@ func TestDownloadBinaryAndChecksum(t *testing.T) {
 		}))
 		defer server.Close()
 
-		binaryURL := server.URL + "/binary"
-		checksumURL := server.URL + "/checksum"
+		binary := Asset{Name: "dotenv-tui-linux-amd64", URL: server.URL + "/binary"}
+		checksum := Asset{Name: "dotenv-tui-linux-amd64.sha256", URL: server.URL + "/checksum"}
 
 		old := os.Stdout
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		binaryPath, checksumPath, err := downloadBinaryAndChecksum(binaryURL, checksumURL)
+		d, err := downloadBinaryAndChecksum(context.Background(), "v1.2.0", binary, checksum)
 
 		_ = w.Close()
 		os.Stdout = old
@@ -820,14 +797,13 @@ func TestDownloadBinaryAndChecksum(t *testing.T) {
 			t.Fatalf("downloadBinaryAndChecksum() unexpected error = %v", err)
 		}
 
-		if binaryPath == "" {
+		if d.path == "" {
 			t.Fatal("downloadBinaryAndChecksum() binary path is empty")
 		}
-		defer func() { _ = os.Remove(binaryPath) }()
+		defer func() { _ = os.Remove(d.path) }()
 
-		if checksumPath != "" {
-			t.Error("downloadBinaryAndChecksum() checksum path should be empty when download fails")
-			_ = os.Remove(checksumPath)
+		if d.expected != "" {
+			t.Errorf("downloadBinaryAndChecksum() expected checksum = %q, want none when download fails", d.expected)
 		}
 
 		if !bytes.Contains(output, []byte("Warning")) {
@@ -836,6 +812,129 @@ func TestDownloadBinaryAndChecksum(t *testing.T) {
 	})
 }
 
+// privateCache points the user cache directory, where partial downloads
+// are kept, at a temporary directory.
+func privateCache(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("LocalAppData", dir)
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatalf("UserCacheDir() error = %v", err)
+	}
+	return cache
+}
+
+func TestPartialPath(t *testing.T) {
+	cache := privateCache(t)
+	a, err := partialPath("https://example.com/v1.2.0/dotenv-tui-linux-amd64")
+	if err != nil {
+		t.Fatalf("partialPath() error = %v", err)
+	}
+	if again, _ := partialPath("https://example.com/v1.2.0/dotenv-tui-linux-amd64"); a != again {
+		t.Error("partialPath() differs between calls for the same URL")
+	}
+	if other, _ := partialPath("https://example.com/v1.3.0/dotenv-tui-linux-amd64"); a == other {
+		t.Error("partialPath() is the same for different releases")
+	}
+	if rel, err := filepath.Rel(cache, a); err != nil || strings.HasPrefix(rel, "..") {
+		t.Errorf("partialPath() = %s, want it under the cache directory %s", a, cache)
+	}
+	info, err := os.Stat(filepath.Dir(a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0700 {
+		t.Errorf("download directory mode = %v, want 0700", info.Mode().Perm())
+	}
+}
+
+func TestDownloadBinaryAndChecksumTamperedPartial(t *testing.T) {
+	fastRetries(t)
+	privateCache(t)
+
+	content := []byte(strings.Repeat("genuine binary ", 100))
+	tampered := append([]byte(strings.Repeat("X", 300)), content[300:700]...)
+	binary := Asset{Name: "dotenv-tui-linux-amd64"}
+	newServer := func(checksum bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case strings.HasSuffix(r.URL.Path, "binary"):
+				http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(content))
+			case checksum:
+				_, _ = fmt.Fprintf(w, "%s  %s\n", sha256Hex(content), binary.Name)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+	plant := func(t *testing.T, url string) {
+		t.Helper()
+		path, err := partialPath(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, tampered, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("resumed bytes fail verification", func(t *testing.T) {
+		server := newServer(true)
+		defer server.Close()
+		binary.URL = server.URL + "/binary"
+		plant(t, binary.URL)
+
+		d, err := downloadBinaryAndChecksum(context.Background(), "v1.2.0", binary, Asset{Name: "checksums.txt", URL: server.URL + "/checksum"})
+		if err != nil {
+			t.Fatalf("downloadBinaryAndChecksum() error = %v", err)
+		}
+		defer func() { _ = os.Remove(d.path) }()
+		if err := verifyChecksum(d.sha256, d.expected); err == nil {
+			t.Error("verifyChecksum() accepted a download resumed from tampered bytes")
+		}
+	})
+
+	t.Run("resumed bytes without a checksum are refused", func(t *testing.T) {
+		server := newServer(false)
+		defer server.Close()
+		binary.URL = server.URL + "/binary"
+		plant(t, binary.URL)
+
+		_, err := downloadBinaryAndChecksum(context.Background(), "v1.2.0", binary, Asset{Name: "checksums.txt", URL: server.URL + "/checksum"})
+		if err == nil || !strings.Contains(err.Error(), "cannot verify the resumed download") {
+			t.Errorf("downloadBinaryAndChecksum() error = %v, want the unverifiable resume refused", err)
+		}
+		path, _ := partialPath(binary.URL)
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("partial file left behind: %v", err)
+		}
+	})
+
+	t.Run("no published checksum downloads from the start", func(t *testing.T) {
+		server := newServer(false)
+		defer server.Close()
+		binary.URL = server.URL + "/binary"
+		plant(t, binary.URL)
+
+		old := os.Stdout
+		_, w, _ := os.Pipe()
+		os.Stdout = w
+		d, err := downloadBinaryAndChecksum(context.Background(), "v1.2.0", binary, Asset{})
+		_ = w.Close()
+		os.Stdout = old
+		if err != nil {
+			t.Fatalf("downloadBinaryAndChecksum() error = %v", err)
+		}
+		defer func() { _ = os.Remove(d.path) }()
+		if got, _ := os.ReadFile(d.path); !bytes.Equal(got, content) {
+			t.Error("downloadBinaryAndChecksum() kept the planted bytes without a checksum to verify them")
+		}
+	})
+}
+
 func TestRelease(t *testing.T) {
 	t.Run("valid JSON unmarshal", func(t *testing.T) {
 		jsonData := `{"tag_name": "v1.2.3", "name": "Release 1.2.3", "prerelease": false}`
@@ -867,3 +966,92 @@ func TestRelease(t *testing.T) {
 		}
 	})
 }
+
+func TestGetLatestRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"tag_name": "v1.1.0", "body": "Older notes"},
+			{"tag_name": "v1.2.0", "body": "## Changes\r\n- Faster scans", "assets": [
+				{"name": "dotenv-tui-linux-amd64", "size": 5242880},
+				{"name": "dotenv-tui-linux-amd64.sha256", "size": 89}
+			]}
+		]`))
+	}))
+	defer server.Close()
+
+	original := githubAPIURL
+	githubAPIURL = server.URL
+	defer func() { githubAPIURL = original }()
+
+	release, err := getLatestRelease(context.Background(), ChannelStable)
+	if err != nil {
+		t.Fatalf("getLatestRelease() unexpected error: %v", err)
+	}
+	if release.TagName != "v1.2.0" || release.Body != "## Changes\r\n- Faster scans" {
+		t.Errorf("getLatestRelease() = %+v, want v1.2.0 with its notes", release)
+	}
+	if a, ok := release.asset("dotenv-tui-linux-amd64"); !ok || a.Size != 5242880 {
+		t.Errorf("asset() = %+v, %v; want the 5 MB linux binary", a, ok)
+	}
+	if _, ok := release.asset("dotenv-tui-darwin-arm64"); ok {
+		t.Error("asset() found an asset that is not attached")
+	}
+}
+
+func TestPrintReleaseNotes(t *testing.T) {
+	var buf bytes.Buffer
+	printReleaseNotes(&buf, Release{TagName: "v1.2.0", Body: "## Changes\r\n- Faster scans\r\n"})
+	want := "\nRelease notes for v1.2.0:\n---\n## Changes\n- Faster scans\n---\n\n"
+	if buf.String() != want {
+		t.Errorf("printReleaseNotes() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	printReleaseNotes(&buf, Release{TagName: "v1.2.0"})
+	if !strings.Contains(buf.String(), "(no release notes)") {
+		t.Errorf("printReleaseNotes() = %q, want a no-notes marker", buf.String())
+	}
+}
+
+func TestPrintPlannedDownload(t *testing.T) {
+	const url = "https://example.com/v1.2.0/dotenv-tui-linux-amd64"
+	release := Release{TagName: "v1.2.0"}
+	asset := Asset{Name: "dotenv-tui-linux-amd64", Size: 5242880, URL: url}
+	tests := []struct {
+		name     string
+		checksum Asset
+		want     string
+	}{
+		{
+			name:     "with checksum",
+			checksum: Asset{Name: "dotenv-tui-linux-amd64.sha256"},
+			want:     "Checksum: dotenv-tui-linux-amd64.sha256\n",
+		},
+		{
+			name: "without checksum",
+			want: "Checksum: none published, the download would not be verified\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printPlannedDownload(&buf, release, asset, tt.checksum, "/usr/local/bin/dotenv-tui")
+			got := buf.String()
+			for _, want := range []string{"Asset: dotenv-tui-linux-amd64 (5.0 MB)\n", "URL: " + url + "\n", tt.want, "Would replace: /usr/local/bin/dotenv-tui\n"} {
+				if !strings.Contains(got, want) {
+					t.Errorf("printPlannedDownload() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAssetDownloadURL(t *testing.T) {
+	if got := (Asset{Name: "a", URL: "https://example.com/a"}).downloadURL("v1.2.0"); got != "https://example.com/a" {
+		t.Errorf("downloadURL() = %q, want the listed URL", got)
+	}
+	if got, want := (Asset{Name: "a"}).downloadURL("v1.2.0"), downloadBaseURL+"/v1.2.0/a"; got != want {
+		t.Errorf("downloadURL() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package upgrade
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// variant describes the parts of a platform that release asset names may
+// distinguish beyond the OS and architecture.
+type variant struct {
+	// musl is set on Linux systems using musl libc, such as Alpine.
+	musl bool
+	// arm is the ARM architecture version, 6 or 7, of 32-bit ARM systems;
+	// 0 when unknown or not ARM.
+	arm int
+}
+
+// String describes v as asset names would, e.g. "musl" or "armv6".
+func (v variant) String() string {
+	var parts []string
+	if v.arm > 0 {
+		parts = append(parts, "armv"+strconv.Itoa(v.arm))
+	}
+	if v.musl {
+		parts = append(parts, "musl")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Where detectVariant looks; tests point them at fixtures.
+var (
+	muslLoaderGlob = "/lib/ld-musl-*"
+	cpuInfoPath    = "/proc/cpuinfo"
+)
+
+// detectVariant detects the libc of a Linux system, from the musl dynamic
+// loader being installed, and the version of a 32-bit ARM processor, from
+// /proc/cpuinfo.
+func detectVariant(osType, arch string) variant {
+	var v variant
+	if osType != "linux" {
+		return v
+	}
+	if matches, _ := filepath.Glob(muslLoaderGlob); len(matches) > 0 {
+		v.musl = true
+	}
+	if arch == "arm" {
+		v.arm = armVersion(cpuInfoPath)
+	}
+	return v
+}
+
+// armVersion reads the "CPU architecture" of the cpuinfo file at path.
+// ARMv8 processors running 32-bit code run ARMv7 binaries, so versions
+// above 7 count as 7. It returns 0 when the version is unknown.
+func armVersion(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = f.Close() }()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		name, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok || strings.TrimSpace(name) != "CPU architecture" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return 0
+		}
+		return min(n, 7)
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package upgrade
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectVariant(t *testing.T) {
+	dir := t.TempDir()
+	cpuinfo := filepath.Join(dir, "cpuinfo")
+	if err := os.WriteFile(cpuinfo, []byte("processor\t: 0\nmodel name\t: ARMv6-compatible processor rev 7 (v6l)\nCPU architecture: 7\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	origGlob, origCPU := muslLoaderGlob, cpuInfoPath
+	t.Cleanup(func() { muslLoaderGlob, cpuInfoPath = origGlob, origCPU })
+	muslLoaderGlob = filepath.Join(dir, "ld-musl-*")
+	cpuInfoPath = cpuinfo
+
+	if got := detectVariant("linux", "arm"); got != (variant{arm: 7}) {
+		t.Errorf("detectVariant(linux, arm) = %+v, want armv7 glibc", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "ld-musl-x86_64.so.1"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := detectVariant("linux", "amd64"); got != (variant{musl: true}) {
+		t.Errorf("detectVariant(linux, amd64) = %+v, want musl", got)
+	}
+	if got := detectVariant("darwin", "arm64"); got != (variant{}) {
+		t.Errorf("detectVariant(darwin, arm64) = %+v, want none", got)
+	}
+}
+
+func TestARMVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		cpuinfo string
+		want    int
+	}{
+		{"armv6", "CPU architecture: 6\n", 6},
+		{"armv7", "processor : 0\nCPU architecture: 7\n", 7},
+		{"armv8 in 32-bit mode", "CPU architecture: 8\n", 7},
+		{"unknown", "processor : 0\n", 0},
+		{"malformed", "CPU architecture: AArch64\n", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cpuinfo")
+			if err := os.WriteFile(path, []byte(tt.cpuinfo), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if got := armVersion(path); got != tt.want {
+				t.Errorf("armVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+	if got := armVersion(filepath.Join(t.TempDir(), "missing")); got != 0 {
+		t.Errorf("armVersion(missing) = %d, want 0", got)
+	}
+}
+
+func TestVariantString(t *testing.T) {
+	if got := (variant{musl: true, arm: 6}).String(); got != "armv6, musl" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (variant{}).String(); got != "" {
+		t.Errorf("String() = %q, want empty", got)
+	}
+}
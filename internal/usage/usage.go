@@ -0,0 +1,76 @@
+// Package usage finds the environment variables source code reads, by
+// matching the common access patterns of popular languages.
+package usage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+)
+
+// key matches an environment variable name.
+const key = `([A-Za-z_][A-Za-z0-9_]*)`
+
+// patterns match a single key reference each, capturing the key name.
+var patterns = []*regexp.Regexp{
+	// JavaScript and TypeScript: process.env.KEY, process.env["KEY"],
+	// import.meta.env.KEY and Deno.env.get("KEY").
+	regexp.MustCompile(`process\.env\.` + key),
+	regexp.MustCompile(`process\.env\[\s*["'` + "`" + `]` + key + `["'` + "`" + `]\s*\]`),
+	regexp.MustCompile(`import\.meta\.env\.` + key),
+	regexp.MustCompile(`Deno\.env\.get\(\s*["']` + key + `["']`),
+	// Go os.Getenv and os.LookupEnv, Python os.getenv, PHP getenv and
+	// Java System.getenv.
+	regexp.MustCompile(`(?i:getenv|LookupEnv)\(\s*["']` + key + `["']`),
+	// Python os.environ["KEY"] and os.environ.get("KEY").
+	regexp.MustCompile(`os\.environ(?:\.get\(|\[)\s*["']` + key + `["']`),
+	// Ruby ENV["KEY"] and ENV.fetch("KEY"), and PHP $_ENV["KEY"].
+	regexp.MustCompile(`(?:\$_ENV|\bENV)(?:\.fetch\(|\[)\s*["']` + key + `["']`),
+	// Rust std::env::var("KEY") and Elixir System.get_env("KEY").
+	regexp.MustCompile(`env::var(?:_os)?\(\s*"` + key + `"`),
+	regexp.MustCompile(`System\.(?:get_env|fetch_env!?)\(\s*"` + key + `"`),
+	// C# Environment.GetEnvironmentVariable("KEY").
+	regexp.MustCompile(`GetEnvironmentVariable\(\s*"` + key + `"`),
+	// Config helpers such as Prisma's env("KEY") and Laravel's env('KEY').
+	regexp.MustCompile(`\benv\(\s*["']` + key + `["']`),
+}
+
+// Reference is a place where source code reads a key.
+type Reference struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Key  string `json:"key"`
+}
+
+// Find returns the key references in r, which holds the content of file,
+// in order of appearance.
+func Find(file string, r io.Reader) ([]Reference, error) {
+	var refs []Reference
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		// Report the line's references left to right, whichever pattern
+		// matched them.
+		starts := make(map[int]string)
+		for _, p := range patterns {
+			for _, m := range p.FindAllStringSubmatchIndex(line, -1) {
+				starts[m[2]] = line[m[2]:m[3]]
+			}
+		}
+		positions := make([]int, 0, len(starts))
+		for pos := range starts {
+			positions = append(positions, pos)
+		}
+		sort.Ints(positions)
+		for _, pos := range positions {
+			refs = append(refs, Reference{File: file, Line: n, Key: starts[pos]})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return refs, nil
+}
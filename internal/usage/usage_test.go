@@ -0,0 +1,57 @@
+package usage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{"node dot", "const url = process.env.DATABASE_URL;", []string{"DATABASE_URL"}},
+		{"node index", "process.env['API_KEY'] || process.env[\"PORT\"]", []string{"API_KEY", "PORT"}},
+		{"vite", "import.meta.env.VITE_API_URL", []string{"VITE_API_URL"}},
+		{"deno", `Deno.env.get("TOKEN")`, []string{"TOKEN"}},
+		{"go", `port := os.Getenv("PORT"); v, ok := os.LookupEnv("DEBUG")`, []string{"PORT", "DEBUG"}},
+		{"python", `os.environ["SECRET"]; os.environ.get('HOST'); os.getenv("USER_NAME")`, []string{"SECRET", "HOST", "USER_NAME"}},
+		{"ruby", `ENV["REDIS_URL"]; ENV.fetch("SMTP_HOST")`, []string{"REDIS_URL", "SMTP_HOST"}},
+		{"php", `$_ENV['APP_KEY']; getenv('APP_ENV'); env('DB_HOST')`, []string{"APP_KEY", "APP_ENV", "DB_HOST"}},
+		{"rust", `std::env::var("RUST_LOG")`, []string{"RUST_LOG"}},
+		{"java", `System.getenv("JAVA_OPTS")`, []string{"JAVA_OPTS"}},
+		{"elixir", `System.get_env("PHX_HOST")`, []string{"PHX_HOST"}},
+		{"csharp", `Environment.GetEnvironmentVariable("ASPNETCORE_ENV")`, []string{"ASPNETCORE_ENV"}},
+		{"prisma", `url = env("DATABASE_URL")`, []string{"DATABASE_URL"}},
+		{"no reference", `const env = loadEnv(); getenv(name)`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, err := Find("src", strings.NewReader(tt.src))
+			if err != nil {
+				t.Fatalf("Find() unexpected error: %v", err)
+			}
+			var keys []string
+			for _, r := range refs {
+				keys = append(keys, r.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.want) {
+				t.Errorf("Find() keys = %v, want %v", keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindLines(t *testing.T) {
+	refs, err := Find("app.js", strings.NewReader("// config\n\nconst port = process.env.PORT\n"))
+	if err != nil {
+		t.Fatalf("Find() unexpected error: %v", err)
+	}
+	want := []Reference{{File: "app.js", Line: 3, Key: "PORT"}}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("Find() = %+v, want %+v", refs, want)
+	}
+}
@@ -2,14 +2,39 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime/debug"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
 
+	"github.com/jellydn/dotenv-tui/internal/app"
 	"github.com/jellydn/dotenv-tui/internal/cli"
+	"github.com/jellydn/dotenv-tui/internal/config"
+	"github.com/jellydn/dotenv-tui/internal/console"
+	"github.com/jellydn/dotenv-tui/internal/convert"
+	"github.com/jellydn/dotenv-tui/internal/crash"
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/format"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/history"
+	"github.com/jellydn/dotenv-tui/internal/lint"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+	"github.com/jellydn/dotenv-tui/internal/shell"
+	"github.com/jellydn/dotenv-tui/internal/state"
+	"github.com/jellydn/dotenv-tui/internal/theme"
 	"github.com/jellydn/dotenv-tui/internal/tui"
 	"github.com/jellydn/dotenv-tui/internal/upgrade"
 )
@@ -32,195 +57,342 @@ func getVersion() string {
 	return "dev"
 }
 
-type model struct {
-	currentScreen screen
-	menu          tui.MenuModel
-	picker        tui.PickerModel
-	preview       tui.PreviewModel
-	form          tui.FormModel
-	fileList      []string
-	fileIndex     int
-	pickerMode    tui.MenuChoice
-	windowHeight  int
-	savedFiles    map[int]bool
+// terminalProgress renders yolo progress as a live bar. The bar starts with
+// the first finished file, after all overwrite prompts have been answered.
+type terminalProgress struct {
+	out    io.Writer
+	theme  theme.Theme
+	runner *tui.ProgressRunner
 }
 
-type screen int
-
-const (
-	menuScreen screen = iota
-	pickerScreen
-	previewScreen
-	formScreen
-)
+func (p *terminalProgress) Update(done, total int, r cli.FileResult) {
+	if p.runner == nil {
+		p.runner = tui.StartProgress(p.out, p.theme)
+	}
+	p.runner.Update(done, total, r.Status+" "+r.Target)
+}
 
-func initialModel() model {
-	return model{
-		currentScreen: menuScreen,
-		menu:          tui.NewMenuModel(),
+func (p *terminalProgress) Finish() {
+	if p.runner != nil {
+		p.runner.Finish()
 	}
 }
 
-func (m model) Init() tea.Cmd {
-	return nil
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if wsm, ok := msg.(tea.WindowSizeMsg); ok {
-		m.windowHeight = wsm.Height
-	}
+// stringList is a flag.Value collecting every occurrence of a repeatable flag.
+type stringList []string
 
-	switch m.currentScreen {
-	case menuScreen:
-		return updateMenu(msg, m)
-	case pickerScreen:
-		return updatePicker(msg, m)
-	case previewScreen:
-		return updatePreview(msg, m)
-	case formScreen:
-		return updateForm(msg, m)
-	}
-	return m, nil
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
 }
 
-func updateMenu(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
-	menuModel, menuCmd := m.menu.Update(msg)
-	m.menu = menuModel.(tui.MenuModel)
-	cmd := menuCmd
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
 
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		if keyMsg.String() == "enter" || keyMsg.String() == " " {
-			m.currentScreen = pickerScreen
-			m.picker.SetWindowHeight(m.windowHeight)
-			return m, tui.NewPickerModel(m.menu.Choice(), ".")
+// setValues merges the values of --set-file with the --set assignments,
+// which win.
+func setValues(file string, assignments []string) (map[string]string, error) {
+	values := make(map[string]string)
+	if file != "" {
+		loaded, err := cli.LoadAnswers(file, cli.RealFileSystem{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load --set-file: %w", err)
 		}
+		maps.Copy(values, loaded)
+	}
+	set, err := cli.ParseSetValues(assignments)
+	if err != nil {
+		return nil, err
 	}
+	maps.Copy(values, set)
+	return values, nil
+}
 
-	return m, cmd
+// secretReader reads a line from f without echoing it, or returns nil when
+// f is not a terminal, so piped answers are read like any other line.
+func secretReader(f *os.File) func() (string, error) {
+	if !term.IsTerminal(f.Fd()) {
+		return nil
+	}
+	return func() (string, error) {
+		line, err := term.ReadPassword(f.Fd())
+		return string(line), err
+	}
 }
 
-func updatePicker(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
-	pickerModel, pickerCmd := m.picker.Update(msg)
-	m.picker = pickerModel.(tui.PickerModel)
-	cmd := pickerCmd
-
-	switch msg := msg.(type) {
-	case tui.PickerFinishedMsg:
-		if len(msg.Selected) > 0 {
-			m.fileList = msg.Selected
-			m.fileIndex = 0
-			m.pickerMode = msg.Mode
-			m.savedFiles = make(map[int]bool)
-
-			if msg.Mode == tui.GenerateExample {
-				m.currentScreen = previewScreen
-				m.preview.SetWindowHeight(m.windowHeight)
-				return m, tui.NewPreviewModel(msg.Selected, m.menu.EnableBackup())
-			}
-			if msg.Mode == tui.GenerateEnv {
-				m.currentScreen = formScreen
-				return m, tui.NewFormModel(msg.Selected[0], 0, len(msg.Selected), m.savedFiles, m.menu.EnableBackup())
-			}
-		}
-		m.currentScreen = menuScreen
-		m.menu = tui.NewMenuModel()
-		return m, nil
-	case tea.KeyMsg:
-		if msg.String() == "q" || msg.String() == "esc" {
-			return returnToMenu(m), nil
+// interruptGrace is how long a command has to stop after Ctrl+C before the
+// process exits anyway, such as when it is waiting at a prompt.
+const interruptGrace = 2 * time.Second
+
+// interruptContext returns a context cancelled by the first Ctrl+C or
+// SIGTERM, so scans and downloads stop cleanly instead of being killed
+// mid-write. A second signal, or a command still running after
+// interruptGrace, exits with status 130. stop releases the signals, and
+// must be called before the TUI starts, since it handles them itself.
+func interruptContext() (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+		case <-stopped:
+			return
 		}
+		// Restore the default handling, so a second Ctrl+C kills the process.
+		signal.Stop(signals)
+		cancel()
+		time.Sleep(interruptGrace)
+		os.Exit(130)
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		close(stopped)
+		cancel()
 	}
-
-	return m, cmd
 }
 
-func updatePreview(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
-	previewModel, previewCmd := m.preview.Update(msg)
-	m.preview = previewModel.(tui.PreviewModel)
+func main() {
+	console.Setup()
+	defer crash.Recover(getVersion())
+
+	var (
+		generateExample   = flag.String("generate-example", "", "Generate .env.example from specified .env file")
+		generateTest      = flag.String("generate-test", "", "Generate .env.test with fake values for secrets from specified .env or .env.example file")
+		generateEnv       = flag.String("generate-env", "", "Generate .env from specified .env.example file")
+		exportFlag        = flag.String("export", "", "Convert a .env file for another tool: direnv, gh-secrets, shell, json, yaml or toml")
+		shellFlag         = flag.String("shell", "bash", "Shell for --export shell: bash, fish or pwsh")
+		repoFlag          = flag.String("repo", "", "With --export gh-secrets, the owner/name repository to set secrets in")
+		importEnvFlag     = flag.Bool("import-env", false, "Capture environment variables starting with --prefix into a new .env and masked .env.example")
+		prefixFlag        = flag.String("prefix", "", "Variable name prefix for --import-env, e.g. APP_")
+		importFileFlag    = flag.String("import-file", "", "Convert a JSON, YAML or TOML config file into a new .env and masked .env.example")
+		flattenFlag       = flag.Bool("flatten", false, "With --import-file, expand nested values into keys such as DATABASE__HOST")
+		separatorFlag     = flag.String("separator", convert.DefaultSeparator, "With --flatten or --unflatten, the string joining nested keys")
+		unflattenFlag     = flag.Bool("unflatten", false, "With --export json, yaml or toml, nest keys such as DATABASE__HOST")
+		pushFlag          = flag.String("push", "", "Push a .env file to a remote store: k8s")
+		pullFlag          = flag.String("pull", "", "Pull a .env file from a remote store: k8s")
+		namespaceFlag     = flag.String("namespace", "", "Kubernetes namespace for --push/--pull k8s (default: the current context's)")
+		nameFlag          = flag.String("name", "", "Kubernetes Secret name for --push/--pull k8s")
+		executeFlag       = flag.Bool("execute", false, "With --export gh-secrets, run gh secret set instead of printing the commands")
+		showHelp          = flag.Bool("help", false, "Show help information")
+		showVersion       = flag.Bool("version", false, "Show version information")
+		inlineFlag        = flag.Bool("inline", false, "Run the TUI inline instead of full screen, leaving a summary of written files in the scrollback")
+		scanFlag          = flag.Bool("scan", false, "Scan directory for .env files")
+		yoloFlag          = flag.Bool("yolo", false, "Auto-generate .env from all .env.example files")
+		forceFlag         = flag.Bool("force", false, "Force overwrite existing files")
+		noBackupFlag      = flag.Bool("no-backup", false, "Skip creating backup files")
+		backupDirFlag     = flag.String("backup-dir", "", "Write backups to this directory instead of next to each file")
+		compressFlag      = flag.Bool("compress-backups", false, "Gzip backup files")
+		annotateFlag      = flag.Bool("annotate", false, "Write a comment above each filled .env value recording where it came from")
+		deterministicFlag = flag.Bool("deterministic", false, "Leave dates out of written files and number backups instead of timestamping them, so output is byte-stable")
+		quoteFlag         = flag.String("quote-policy", "preserve", "Quoting for written values: preserve, always or when-needed")
+		dryRunFlag        = flag.Bool("dry-run", false, "Preview operations without writing files")
+		upgradeFlag       = flag.Bool("upgrade", false, "Upgrade to the latest version")
+		skipUpdateFlag    = flag.Bool("no-update-check", false, "Do not check for a newer release when the TUI starts")
+		noCacheFlag       = flag.Bool("no-cache", false, "Scan directories from disk instead of reusing the listings cached by earlier scans")
+		channelFlag       = flag.String("channel", "stable", "Release channel for --upgrade: stable or prerelease")
+		timeoutFlag       = flag.Duration("timeout", 0, "Time limit for each --upgrade network step (default 30s for lookups, 10m for downloads)")
+		remaskFlag        = flag.Bool("remask", false, "Mask real-looking secrets committed to .env.example files, after showing a diff")
+		whyFlag           = flag.String("why", "", "Explain why a key of a .env file is or is not treated as a secret")
+		auditFlag         = flag.Bool("audit", false, "Report real-looking secrets in examples and committed files, and keys past their rotate-by date")
+		lintFlag          = flag.Bool("lint", false, "Check env files for naming, duplicate, quoting and whitespace problems")
+		compatFlag        = flag.String("compat", "", "Lint env files for a loader's limitations: systemd, php, ruby or python")
+		checkFlag         = flag.Bool("check", false, "Report keys in .env.example files that the matching .env lacks")
+		unusedFlag        = flag.Bool("unused", false, "Report keys never read by source code, and keys read but missing from .env.example")
+		statsFlag         = flag.Bool("stats", false, "Summarize env files: keys, secret ratio, duplicate keys, missing examples, largest files")
+		formatFlag        = flag.String("format", "text", "Output format for --audit, --lint, --check, --unused, --stats and --dry-run: text or json")
+		maxDepthFlag      = flag.Int("max-depth", 0, "Maximum directory depth to scan (0 for unlimited)")
+		followLinksFlag   = flag.Bool("follow-symlinks", false, "Follow symlinked directories when scanning")
+		oneFSFlag         = flag.Bool("one-file-system", false, "Do not scan directories on other filesystems")
+		answersFlag       = flag.String("answers", "", "Fill values in --yolo output from a .env or JSON answers file")
+		fromEnvFlag       = flag.Bool("from-env", false, "Fill keys from the process environment when generating .env")
+		setFileFlag       = flag.String("set-file", "", "Set the keys of a .env or JSON file in the .env written by --generate-env")
+		promptsFlag       = flag.Bool("interactive-prompts", false, "With --generate-env, ask for each placeholder value on the command line instead of opening the TUI")
+		styleFlag         string
+		sortFlag          = flag.String("sort", "none", "Key order for generated examples: keys or none")
+		groupFlag         = flag.Bool("group-by-prefix", false, "Group keys sharing a prefix such as DB_ under section comments in generated examples")
+		changelogFlag     = flag.Bool("changelog", false, "Put a dated comment listing the keys added and removed at the top of regenerated examples")
+		visibleFlag       = flag.Int("mask-visible", detector.DefaultVisible, "Trailing characters shown by the partial placeholder style")
+		excludeFlag       stringList
+		setFlag           stringList
+		unmaskFlag        stringList
+	)
+	flag.StringVar(&styleFlag, "placeholder-style", "mask", "Placeholder style for generated examples: mask, descriptive or partial")
+	flag.StringVar(&styleFlag, "mask-style", "mask", "Alias for --placeholder-style")
+	flag.Var(&excludeFlag, "exclude", "Exclude paths matching a gitignore-style pattern when scanning (repeatable)")
+	flag.Var(&setFlag, "set", "Set KEY=VALUE in the .env written by --generate-env (repeatable)")
+	flag.Var(&unmaskFlag, "unmask-key", "Never mask KEY, or keys matching a glob such as NEXT_PUBLIC_*, in generated examples (repeatable)")
+
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	// Flags given on the command line override every config layer,
+	// including the .dotenv-tui.yaml files of subdirectories.
+	fileCfg := cfg
+	applyFlags := func(cfg *config.Config) {
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "no-backup":
+				cfg.Backup = !*noBackupFlag
+			case "backup-dir":
+				cfg.BackupDir = *backupDirFlag
+			case "compress-backups":
+				cfg.BackupCompress = *compressFlag
+			case "annotate":
+				cfg.Annotate = *annotateFlag
+			case "quote-policy":
+				cfg.QuotePolicy = *quoteFlag
+			case "placeholder-style", "mask-style":
+				cfg.Example.Style = styleFlag
+			case "mask-visible":
+				cfg.Example.Visible = *visibleFlag
+			case "sort":
+				cfg.Example.Sort = *sortFlag
+			case "group-by-prefix":
+				cfg.Example.GroupByPrefix = *groupFlag
+			case "changelog":
+				cfg.Example.Changelog = *changelogFlag
+			case "unmask-key":
+				cfg.Mask.IgnoreKeys = slices.Concat(cfg.Mask.IgnoreKeys, unmaskFlag)
+			case "exclude":
+				cfg.Scan.Exclude = slices.Concat(cfg.Scan.Exclude, excludeFlag)
+			case "max-depth":
+				cfg.Scan.MaxDepth = *maxDepthFlag
+			case "follow-symlinks":
+				cfg.Scan.FollowSymlinks = *followLinksFlag
+			case "one-file-system":
+				cfg.Scan.OneFilesystem = *oneFSFlag
+			case "no-update-check":
+				cfg.Update.Check = !*skipUpdateFlag
+			}
+		})
+	}
+	applyFlags(&cfg)
 
-	if _, ok := msg.(tui.PreviewFinishedMsg); ok {
-		return returnToMenu(m), nil
+	writeOpts, err := cfg.WriteOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	settings := cli.Settings{
+		Write:         writeOpts,
+		Annotate:      cfg.Annotate,
+		Deterministic: *deterministicFlag,
+		History:       history.DefaultPath(),
 	}
 
-	return m, previewCmd
-}
+	scanOpts, err := cfg.ScanOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if dir, err := state.Dir(); err == nil && !*noCacheFlag {
+		scanOpts.CachePath = filepath.Join(dir, scanner.CacheFileName)
+	}
+	dirScanner := cli.RealDirScanner{Options: scanOpts}
 
-func updateForm(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
-	formModel, formCmd := m.form.Update(msg)
-	m.form = formModel.(tui.FormModel)
+	exampleOpts, err := cfg.ExampleOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	settings.Rules = exampleOpts.Masking.Rules
 
-	if savedMsg, ok := msg.(tui.FormSavedMsg); ok {
-		if savedMsg.Success {
-			m.savedFiles[m.fileIndex] = true
+	lintOpts, err := cfg.LintOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if *compatFlag != "" {
+		if lintOpts.Compat, err = lint.ParseTarget(*compatFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 	}
 
-	if finishedMsg, ok := msg.(tui.FormFinishedMsg); ok {
-		if finishedMsg.Dir == 0 {
-			return returnToMenu(m), nil
+	// Nested .dotenv-tui.yaml files change the masking, backup and schema
+	// settings of the env files under them.
+	overrides, err := config.NewOverrides(".", fileCfg, os.LookupEnv, applyFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	settings.Dir = overrides.For
+	settingsFor := func(path string) config.DirSettings {
+		s, err := overrides.For(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+		return s
+	}
 
-		if len(m.savedFiles) >= len(m.fileList) {
-			return returnToMenu(m), nil
-		}
+	ctx, stop := interruptContext()
 
-		n := len(m.fileList)
-		nextIndex := (m.fileIndex + finishedMsg.Dir + n) % n
-		for i := 0; i < n-1; i++ {
-			if !m.savedFiles[nextIndex] {
-				break
-			}
-			nextIndex = (nextIndex + finishedMsg.Dir + n) % n
+	if flag.Arg(0) == "init" {
+		if err := runInit(flag.Args()[1:], exampleOpts, settings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+		return
+	}
 
-		if m.savedFiles[nextIndex] {
-			return returnToMenu(m), nil
+	if flag.Arg(0) == "rename" {
+		if err := runRename(ctx, flag.Args()[1:], dirScanner, cfg.Backup, settings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-
-		m.fileIndex = nextIndex
-		m.currentScreen = formScreen
-		return m, tui.NewFormModel(m.fileList[m.fileIndex], m.fileIndex, n, m.savedFiles, m.menu.EnableBackup())
+		return
 	}
 
-	return m, formCmd
-}
+	if flag.Arg(0) == "move" {
+		if err := runMove(flag.Args()[1:], cfg.Backup, settings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-func returnToMenu(m model) tea.Model {
-	m.currentScreen = menuScreen
-	m.menu = tui.NewMenuModel()
-	return m
-}
+	if flag.Arg(0) == "fmt" {
+		if err := runFmt(ctx, flag.Args()[1:], dirScanner, cfg.Format, settings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-func (m model) View() string {
-	switch m.currentScreen {
-	case menuScreen:
-		return m.menu.View()
-	case pickerScreen:
-		return m.picker.View()
-	case previewScreen:
-		return m.preview.View()
-	case formScreen:
-		return m.form.View()
-	default:
-		return ""
+	if flag.Arg(0) == "encrypt" || flag.Arg(0) == "decrypt" {
+		if err := runCrypt(flag.Arg(0), flag.Args()[1:], cfg.Backup, settings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
-}
 
-func main() {
-	var (
-		generateExample = flag.String("generate-example", "", "Generate .env.example from specified .env file")
-		generateEnv     = flag.String("generate-env", "", "Generate .env from specified .env.example file")
-		showHelp        = flag.Bool("help", false, "Show help information")
-		showVersion     = flag.Bool("version", false, "Show version information")
-		scanFlag        = flag.Bool("scan", false, "Scan directory for .env files")
-		yoloFlag        = flag.Bool("yolo", false, "Auto-generate .env from all .env.example files")
-		forceFlag       = flag.Bool("force", false, "Force overwrite existing files")
-		noBackupFlag    = flag.Bool("no-backup", false, "Skip creating backup files")
-		dryRunFlag      = flag.Bool("dry-run", false, "Preview operations without writing files")
-		upgradeFlag     = flag.Bool("upgrade", false, "Upgrade to the latest version")
-	)
+	if flag.Arg(0) == "template" {
+		if err := runTemplate(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	flag.Parse()
+	if flag.Arg(0) == "history" {
+		if err := runHistory(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	if *showVersion {
 		fmt.Printf("dotenv-tui version %s\n", getVersion())
@@ -232,16 +404,163 @@ func main() {
 		return
 	}
 
+	if *dryRunFlag && *formatFlag != cli.FormatText && *formatFlag != cli.FormatJSON {
+		fmt.Fprintf(os.Stderr, "Error: unsupported format %q (use %s or %s)\n", *formatFlag, cli.FormatText, cli.FormatJSON)
+		os.Exit(1)
+	}
+	jsonPlan := *dryRunFlag && *formatFlag == cli.FormatJSON
+
+	if *generateExample != "" && jsonPlan {
+		s := settingsFor(*generateExample)
+		if err := cli.PlanExampleFile(*generateExample, s.Backup, s.Example, settings, cli.RealFileSystem{}, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error planning .env.example: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *generateExample != "" {
-		if err := cli.GenerateExampleFile(*generateExample, *forceFlag, !*noBackupFlag, *dryRunFlag, cli.RealFileSystem{}, os.Stdout); err != nil {
+		s := settingsFor(*generateExample)
+		if err := cli.GenerateExampleFileWithOptions(*generateExample, *forceFlag, s.Backup, *dryRunFlag, s.Example, settings, cli.RealFileSystem{}, os.Stdout); err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating .env.example: %v\n", err)
 			os.Exit(1)
 		}
 		return
 	}
 
+	if *generateTest != "" {
+		s := settingsFor(*generateTest)
+		if err := cli.GenerateTestFile(*generateTest, *forceFlag, s.Backup, *dryRunFlag, s.Example.Masking, settings, cli.RealFileSystem{}, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating .env.test: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *importFileFlag != "" {
+		opts := cli.ImportOptions{
+			Output:       ".env",
+			Convert:      convert.Options{Flatten: *flattenFlag, Separator: *separatorFlag},
+			Force:        *forceFlag,
+			CreateBackup: cfg.Backup,
+			DryRun:       *dryRunFlag,
+			Example:      exampleOpts,
+			Settings:     settings,
+		}
+		if flag.NArg() > 0 {
+			opts.Output = flag.Arg(0)
+		}
+		if err := cli.ImportFile(*importFileFlag, opts, cli.RealFileSystem{}, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing %s: %v\n", *importFileFlag, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *importEnvFlag {
+		opts := cli.ImportOptions{Output: ".env", Prefix: *prefixFlag, Force: *forceFlag, CreateBackup: cfg.Backup, DryRun: *dryRunFlag, Example: exampleOpts, Settings: settings}
+		if flag.NArg() > 0 {
+			opts.Output = flag.Arg(0)
+		}
+		if err := cli.ImportEnv(opts, os.Environ(), cli.RealFileSystem{}, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing environment: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *pushFlag != "" || *pullFlag != "" {
+		target, push := *pullFlag, false
+		if *pushFlag != "" {
+			target, push = *pushFlag, true
+		}
+		if target != "k8s" {
+			fmt.Fprintf(os.Stderr, "Error: unsupported store %q (use k8s)\n", target)
+			os.Exit(1)
+		}
+		opts := cli.K8sOptions{File: ".env", Namespace: *namespaceFlag, Name: *nameFlag, DryRun: *dryRunFlag, CreateBackup: cfg.Backup, Settings: settings}
+		if flag.NArg() > 0 {
+			opts.File = flag.Arg(0)
+		}
+		run, verb := cli.PullK8s, "pulling"
+		if push {
+			run, verb = cli.PushK8s, "pushing"
+		}
+		if err := run(opts, cli.RealFileSystem{}, cli.RunKubectl, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error %s %s: %v\n", verb, opts.File, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *whyFlag != "" {
+		input := ".env"
+		if flag.NArg() > 0 {
+			input = flag.Arg(0)
+		}
+		if err := cli.Why(input, *whyFlag, settingsFor(input).Example.Masking, settings, cli.RealFileSystem{}, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *exportFlag != "" {
+		input := ".env"
+		if flag.NArg() > 0 {
+			input = flag.Arg(0)
+		}
+		var err error
+		switch *exportFlag {
+		case "direnv":
+			err = cli.ExportDirenv(input, *forceFlag, cfg.Backup, *dryRunFlag, settings, cli.RealFileSystem{}, os.Stdout)
+		case "gh-secrets":
+			opts := cli.GHSecretsOptions{Repo: *repoFlag, Execute: *executeFlag && !*dryRunFlag, Rules: settings.Rules}
+			err = cli.ExportGHSecrets(input, opts, cli.RealFileSystem{}, cli.GHSecretSet, os.Stdout)
+		case "shell":
+			var sh shell.Shell
+			if sh, err = shell.Parse(*shellFlag); err == nil {
+				err = cli.ExportShell(input, sh, cli.RealFileSystem{}, os.Stdout)
+			}
+		case convert.FormatJSON, convert.FormatYAML, convert.FormatTOML:
+			err = cli.ExportDocument(input, *exportFlag, *unflattenFlag, *separatorFlag, cli.RealFileSystem{}, os.Stdout)
+		default:
+			err = fmt.Errorf("unsupported export target %q (use direnv, gh-secrets, shell, json, yaml or toml)", *exportFlag)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting %s: %v\n", input, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *generateEnv != "" {
-		if err := cli.GenerateEnvFile(*generateEnv, *forceFlag, !*noBackupFlag, *dryRunFlag, cli.RealFileSystem{}, os.Stdout); err != nil {
+		var lookup cli.LookupFunc
+		if *fromEnvFlag {
+			lookup = os.LookupEnv
+		}
+		values, err := setValues(*setFileFlag, setFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		createBackup := settingsFor(*generateEnv).Backup
+		if jsonPlan {
+			if err := cli.PlanEnvFile(*generateEnv, createBackup, values, lookup, settings, cli.RealFileSystem{}, os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error planning .env: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		if *promptsFlag {
+			opts := cli.PromptOptions{Force: *forceFlag, CreateBackup: createBackup, DryRun: *dryRunFlag, Values: values, Env: lookup, ReadSecret: secretReader(os.Stdin), Settings: settings}
+			if err := cli.PromptEnvFile(*generateEnv, opts, cli.RealFileSystem{}, os.Stdin, os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating .env: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		if err := cli.GenerateEnvFileWithValues(*generateEnv, *forceFlag, createBackup, *dryRunFlag, values, lookup, settings, cli.RealFileSystem{}, os.Stdout); err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating .env: %v\n", err)
 			os.Exit(1)
 		}
@@ -254,15 +573,126 @@ func main() {
 		if len(args) > 0 {
 			scanPath = args[0]
 		}
-		if err := cli.ScanAndList(scanPath, cli.RealDirScanner{}, os.Stdout); err != nil {
+		if err := cli.ScanAndList(ctx, scanPath, dirScanner, os.Stdout); err != nil {
 			fmt.Fprintf(os.Stderr, "Error scanning directory: %v\n", err)
 			os.Exit(1)
 		}
 		return
 	}
 
+	if *remaskFlag {
+		opts := cli.RemaskOptions{
+			Files:        flag.Args(),
+			Masking:      exampleOpts.Masking,
+			DryRun:       *dryRunFlag,
+			Force:        *forceFlag,
+			CreateBackup: cfg.Backup,
+			Settings:     settings,
+		}
+		if err := cli.RemaskExamples(ctx, opts, cli.RealFileSystem{}, dirScanner, os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *auditFlag {
+		auditPath := "."
+		if args := flag.Args(); len(args) > 0 {
+			auditPath = args[0]
+		}
+		if err := cli.AuditFiles(ctx, auditPath, *formatFlag, exampleOpts.Masking, settings, dirScanner, cli.RealFileSystem{}, cli.RealGitInspector{}, os.Stdout); err != nil {
+			if !errors.Is(err, cli.ErrAuditFindings) {
+				fmt.Fprintf(os.Stderr, "Error auditing directory: %v\n", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *lintFlag || *compatFlag != "" {
+		lintPath := "."
+		if args := flag.Args(); len(args) > 0 {
+			lintPath = args[0]
+		}
+		if err := cli.LintFiles(ctx, lintPath, *formatFlag, lintOpts, settings, dirScanner, cli.RealFileSystem{}, os.Stdout); err != nil {
+			if !errors.Is(err, cli.ErrLintFindings) {
+				fmt.Fprintf(os.Stderr, "Error linting directory: %v\n", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *checkFlag {
+		checkPath := "."
+		if args := flag.Args(); len(args) > 0 {
+			checkPath = args[0]
+		}
+		if err := cli.CheckFiles(ctx, checkPath, *formatFlag, dirScanner, cli.RealFileSystem{}, os.Stdout); err != nil {
+			if !errors.Is(err, cli.ErrCheckFailed) {
+				fmt.Fprintf(os.Stderr, "Error checking directory: %v\n", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *unusedFlag {
+		unusedPath := "."
+		if args := flag.Args(); len(args) > 0 {
+			unusedPath = args[0]
+		}
+		if err := cli.FindUnusedKeys(ctx, unusedPath, *formatFlag, dirScanner, dirScanner, cli.RealFileSystem{}, os.Stdout); err != nil {
+			if !errors.Is(err, cli.ErrUsageFindings) {
+				fmt.Fprintf(os.Stderr, "Error scanning source: %v\n", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *statsFlag {
+		statsPath := "."
+		if args := flag.Args(); len(args) > 0 {
+			statsPath = args[0]
+		}
+		if err := cli.ShowStats(ctx, statsPath, *formatFlag, settings.Rules, dirScanner, cli.RealFileSystem{}, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error computing stats: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *yoloFlag {
-		if err := cli.GenerateAllEnvFiles(*forceFlag, !*noBackupFlag, *dryRunFlag, cli.RealFileSystem{}, cli.RealDirScanner{}, os.Stdin, os.Stdout); err != nil {
+		var progress cli.Progress = cli.NewPlainProgress(os.Stdout)
+		if isTerminal(os.Stdout) {
+			th, err := cfg.ResolveTheme()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+			progress = &terminalProgress{out: os.Stdout, theme: th}
+		}
+		opts := cli.YoloOptions{
+			Force:        *forceFlag,
+			CreateBackup: cfg.Backup,
+			DryRun:       *dryRunFlag,
+			Format:       *formatFlag,
+			Progress:     progress,
+			Settings:     settings,
+		}
+		if *fromEnvFlag {
+			opts.Env = os.LookupEnv
+		}
+		if *answersFlag != "" {
+			answers, err := cli.LoadAnswers(*answersFlag, cli.RealFileSystem{})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading answers: %v\n", err)
+				os.Exit(1)
+			}
+			opts.Answers = answers
+		}
+		if err := cli.GenerateAllEnvFilesWithOptions(ctx, opts, cli.RealFileSystem{}, dirScanner, os.Stdin, os.Stdout); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -270,18 +700,244 @@ func main() {
 	}
 
 	if *upgradeFlag {
-		if err := upgrade.Upgrade(getVersion()); err != nil {
+		channel, err := upgrade.ParseChannel(*channelFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := upgrade.UpgradeWithOptions(ctx, getVersion(), upgrade.Options{Channel: channel, DryRun: *dryRunFlag, Timeout: *timeoutFlag}); err != nil {
 			fmt.Fprintf(os.Stderr, "Error upgrading: %v\n", err)
 			os.Exit(1)
 		}
 		return
 	}
 
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	// Bubble Tea handles Ctrl+C and SIGTERM itself.
+	stop()
+
+	km, err := cfg.KeyMap()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	th, err := cfg.ResolveTheme()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	tuiOpts := tui.Options{
+		Keys:          km,
+		Theme:         th,
+		Scan:          scanOpts,
+		Example:       exampleOpts,
+		Lint:          lintOpts,
+		FormPreview:   cfg.Form.Preview,
+		Annotate:      cfg.Annotate,
+		Write:         writeOpts,
+		Dir:           overrides.For,
+		Deterministic: *deterministicFlag,
+		History:       settings.History,
+	}
+
+	m := app.New(tuiOpts).WithConfig(cfg, config.ProjectFileName).WithSession(state.DefaultPath())
+	if cfg.Update.Check {
+		cachePath := ""
+		if dir, err := state.Dir(); err == nil {
+			cachePath = filepath.Join(dir, upgrade.UpdateCacheName)
+		}
+		m = m.WithUpdateCheck(getVersion(), cachePath)
+	}
+
+	var opts []tea.ProgramOption
+	if *inlineFlag {
+		m = m.WithInline()
+	} else {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	final, err := crash.Run(m, getVersion(), opts...)
+	if errors.Is(err, crash.ErrCrashed) {
+		os.Exit(2)
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v", err)
 		os.Exit(1)
 	}
+	if fm, ok := final.(app.Model); ok && fm.Inline() {
+		fmt.Print(fm.Summary())
+	}
+}
+
+// runInit handles "dotenv-tui init", which takes its own flags.
+func runInit(args []string, exampleOpts generator.Options, settings cli.Settings) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	var (
+		yes         = fs.Bool("yes", false, "Accept the defaults without prompting")
+		force       = fs.Bool("force", false, "Overwrite existing files")
+		withSchema  = fs.Bool("schema", false, "Also create a .env.schema from .env")
+		noExample   = fs.Bool("no-example", false, "Do not create .env.example from .env")
+		noGitignore = fs.Bool("no-gitignore", false, "Do not add env file rules to .gitignore")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := cli.InitOptions{
+		Dir:            fs.Arg(0),
+		Force:          *force,
+		Example:        !*noExample,
+		Schema:         *withSchema,
+		Gitignore:      !*noGitignore,
+		Ask:            !*yes && fs.NFlag() == 0 && isTerminal(os.Stdin),
+		ExampleOptions: exampleOpts,
+		Settings:       settings,
+	}
+	return cli.Init(opts, cli.RealFileSystem{}, os.Stdin, os.Stdout)
+}
+
+// runHistory handles "dotenv-tui history", which takes its own flags.
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	var (
+		format = fs.String("format", cli.FormatText, "Output format: text or json")
+		limit  = fs.Int("limit", 0, "Show only the most recent writes (0 for all)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return cli.ShowHistory(cli.HistoryOptions{
+		Path:   history.DefaultPath(),
+		File:   fs.Arg(0),
+		Limit:  *limit,
+		Format: *format,
+	}, os.Stdout)
+}
+
+// runTemplate handles "dotenv-tui template", which prints a built-in
+// .env.example, or lists them with "template list".
+func runTemplate(args []string) error {
+	fs := flag.NewFlagSet("template", flag.ContinueOnError)
+	format := fs.String("format", cli.FormatText, "Output format of template list: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch name := fs.Arg(0); name {
+	case "":
+		return fmt.Errorf("template needs a name, or list to see them")
+	case "list":
+		return cli.ListTemplates(*format, os.Stdout)
+	default:
+		return cli.WriteTemplate(name, os.Stdout)
+	}
+}
+
+// runRename handles "dotenv-tui rename", which takes its own flags.
+func runRename(ctx context.Context, args []string, sc cli.DirScanner, createBackup bool, settings cli.Settings) error {
+	fs := flag.NewFlagSet("rename", flag.ContinueOnError)
+	var (
+		from     = fs.String("from", "", "Prefix of the keys to rename, e.g. OLD_PREFIX_")
+		to       = fs.String("to", "", "Prefix the keys get instead, e.g. NEW_PREFIX_")
+		dryRun   = fs.Bool("dry-run", false, "Print a diff of the changes without writing any file")
+		noBackup = fs.Bool("no-backup", !createBackup, "Skip creating backups of the renamed files")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return cli.RenamePrefix(ctx, cli.RenameOptions{
+		From:         *from,
+		To:           *to,
+		Files:        fs.Args(),
+		DryRun:       *dryRun,
+		CreateBackup: !*noBackup,
+		Settings:     settings,
+	}, cli.RealFileSystem{}, sc, os.Stdout)
+}
+
+// runMove handles "dotenv-tui move", which takes its own flags. The keys
+// may come before or after the flags, as in "move KEY --from a --to b".
+func runMove(args []string, createBackup bool, settings cli.Settings) error {
+	fs := flag.NewFlagSet("move", flag.ContinueOnError)
+	var (
+		from     = fs.String("from", "", "Env file to take the keys from")
+		to       = fs.String("to", "", "Env file to add the keys to, created if missing")
+		keepKeys = fs.Bool("copy", false, "Copy the keys, leaving them in --from")
+		dryRun   = fs.Bool("dry-run", false, "Print a diff of the changes without writing any file")
+		noBackup = fs.Bool("no-backup", !createBackup, "Skip creating backups of the changed files")
+	)
+	var keys []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		keys = append(keys, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	return cli.MoveKeys(cli.MoveOptions{
+		Keys:         keys,
+		From:         *from,
+		To:           *to,
+		Copy:         *keepKeys,
+		DryRun:       *dryRun,
+		CreateBackup: !*noBackup,
+		Settings:     settings,
+	}, cli.RealFileSystem{}, os.Stdout)
+}
+
+// runFmt handles "dotenv-tui fmt", which takes its own flags. They
+// default to the format section of the config.
+func runFmt(ctx context.Context, args []string, sc cli.DirScanner, defaults config.Format, settings cli.Settings) error {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	var (
+		check  = fs.Bool("check", false, "List files that are not formatted, without writing, and exit 1 if any")
+		quotes = fs.String("quotes", defaults.Quotes, "Quote style: preserve, minimal or double")
+		sorted = fs.Bool("sort", defaults.Sort, "Sort keys within each blank-line separated section")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	style, err := format.ParseQuoteStyle(*quotes)
+	if err != nil {
+		return err
+	}
+
+	return cli.FormatFiles(ctx, cli.FormatOptions{
+		Files:    fs.Args(),
+		Check:    *check,
+		Format:   format.Options{Quotes: style, Sort: *sorted},
+		Settings: settings,
+	}, cli.RealFileSystem{}, sc, os.Stdout)
+}
+
+// runCrypt handles "dotenv-tui encrypt" and "dotenv-tui decrypt", which
+// take their own flags and an env file, .env by default.
+func runCrypt(command string, args []string, createBackup bool, settings cli.Settings) error {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	var keys stringList
+	fs.Var(&keys, "key", "Only "+command+" this key (repeatable)")
+	fs.Var(&keys, "k", "Alias for --key")
+	stdout := fs.Bool("stdout", false, "Print the decrypted file instead of writing it")
+	noBackup := fs.Bool("no-backup", !createBackup, "Skip creating a backup of the file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	file := ".env"
+	if fs.NArg() > 0 {
+		file = fs.Arg(0)
+	}
+
+	opts := cli.CryptOptions{File: file, Keys: keys, Stdout: *stdout, CreateBackup: !*noBackup, Settings: settings}
+	if command == "encrypt" {
+		if *stdout {
+			return fmt.Errorf("--stdout only applies to decrypt")
+		}
+		return cli.EncryptFile(opts, cli.RealFileSystem{}, os.LookupEnv, os.Stdout)
+	}
+	return cli.DecryptFile(opts, cli.RealFileSystem{}, os.LookupEnv, os.Stdout)
 }
 
 func showUsage() {
@@ -289,30 +945,138 @@ func showUsage() {
 
 USAGE:
     dotenv-tui [FLAGS]
+    dotenv-tui init [--yes] [--schema] [--no-example] [--no-gitignore] [--force] [directory]
+    dotenv-tui history [--limit n] [--format text|json] [file]
+    dotenv-tui template list [--format text|json]
+    dotenv-tui template <name> > .env.example
+    dotenv-tui rename --from OLD_PREFIX_ --to NEW_PREFIX_ [--dry-run] [--no-backup] [files...]
+    dotenv-tui fmt [--check] [--quotes preserve|minimal|double] [--sort] [files...]
+    dotenv-tui move KEY... --from <file> --to <file> [--copy] [--dry-run] [--no-backup]
+    dotenv-tui encrypt [-k KEY]... [--no-backup] [file]
+    dotenv-tui decrypt [-k KEY]... [--stdout] [--no-backup] [file]
 
 FLAGS:
     --generate-example <path>    Generate .env.example from specified .env file
     --generate-env <path>        Generate .env from specified .env.example file
-    --scan [directory]           List discovered .env files (default: current directory)
+    --generate-test <path>       Generate .env.test with fake, well-formed values for secrets from a .env or .env.example
+    --export direnv [file]       Write a .envrc of "export KEY=value" lines from a .env file (default: .env)
+    --export shell [file]        Print export commands that load a .env file into the shell, for eval
+    --shell <bash|fish|pwsh>     Shell syntax for --export shell (default: bash, also for sh and zsh)
+    --export gh-secrets [file]   Print gh secret set commands for the secret keys of a .env file
+    --repo <owner/name>          With --export gh-secrets, the repository to set secrets in (default: current)
+    --execute                    With --export gh-secrets, run the gh commands instead of printing them
+    --import-env [file]          Capture variables starting with --prefix from the environment into a new
+                                 .env (default: .env) and a masked .env.example next to it
+    --prefix <PREFIX_>           Variable name prefix for --import-env
+    --import-file <path> [file]  Convert a JSON, YAML or TOML config file into a new .env (default: .env)
+                                 and a masked .env.example; nested values are kept as JSON unless --flatten
+    --flatten                    With --import-file, expand nested values into keys such as DATABASE__HOST
+    --export <json|yaml|toml>    Print a .env file (default: .env) as a JSON, YAML or TOML document
+    --unflatten                  With --export json, yaml or toml, nest DATABASE__HOST as database.host
+    --separator <sep>            With --flatten or --unflatten, the string joining nested keys (default: __)
+    --push k8s [file]            Create or update a Kubernetes Secret from a .env file with kubectl
+                                 (server-side apply; --dry-run shows the server-side diff)
+    --pull k8s [file]            Write the keys of a Kubernetes Secret into a .env file
+                                 (--push and --pull k8s need kubectl on PATH, using its current context)
+    --name <secret>              Secret name for --push/--pull k8s
+    --namespace <ns>             Namespace for --push/--pull k8s (default: the current context's)
+    --placeholder-style <style>  Mask secrets as "mask" (sk_***), "descriptive" (<your-stripe-secret-key>)
+                                 or "partial" (sk_live_••••••cdef); --mask-style is an alias
+    --mask-visible <n>           Trailing characters kept by the partial style (default: 4)
+    --sort <keys|none>           Order keys in generated examples (default: none, keep original order)
+    --group-by-prefix            Group keys sharing a prefix (DB_, AWS_) under section comments
+    --changelog                  When regenerating an example, list the keys added and removed in a
+                                 dated comment at its top
+    --unmask-key <key>           Never mask this key in generated examples, even if it looks secret;
+                                 globs such as NEXT_PUBLIC_* match several (repeatable)
+    --scan [directory]           List discovered .env files (default: current directory), with the
+                                 Docker Compose services reading them and missing env_file references
     --yolo                       Auto-generate .env from all .env.example files
+    --answers <file>             With --yolo, fill values from a .env or JSON file
+    --from-env                   Fill keys set in the environment when generating .env
+    --set KEY=VALUE              With --generate-env, set a key instead of keeping the example value (repeatable)
+    --set-file <file>            With --generate-env, set the keys of a .env or JSON file
+    --interactive-prompts        With --generate-env, ask for each placeholder value in plain prompts,
+                                 hiding secrets; Enter keeps the example value
     --force                      Force overwrite existing files
     --no-backup                  Skip creating backup files when overwriting
+    --backup-dir <dir>           Keep backups in one directory, e.g. .dotenv-tui/backups
+    --compress-backups           Gzip backup files
+    --annotate                   Note where each filled .env value came from in a comment above its key,
+                                 e.g. "# set via --set 2026-02-08"; later runs update the note
+    --quote-policy <policy>      Quote written values: preserve (default), always, or when-needed
+                                 for values with spaces, # or leading/trailing whitespace
     --dry-run                    Preview operations without writing files
-    --upgrade                    Upgrade to the latest version
+    --deterministic              Make output byte-stable across runs for diffing in CI: no dates in
+                                 --annotate and --changelog comments, backups numbered (.env.bak.00000000000001)
+    --remask [file...]           Mask real-looking secrets in .env.example files (default: all found),
+                                 showing a diff and asking first (--force to skip, --dry-run to only show)
+    --why <KEY> [file]           Explain why KEY in a .env file (default: .env) is or is not masked
+    --audit [directory]          Report secrets in examples and committed files, and keys
+                                 past their "# dotenv-tui: rotate-by=YYYY-MM-DD" date
+    --lint [directory]           Check env files for naming, duplicate, quoting and whitespace problems
+    --compat <loader>            Lint for what systemd, php, ruby or python dotenv loaders cannot read
+                                 (export prefixes, multiline values, interpolation); implies --lint
+    --check [directory]          Report keys in .env.example files that the matching .env lacks or leaves empty
+    --unused [directory]         Report keys never read by source code, and keys read but missing from .env.example
+    --stats [directory]          Summarize env hygiene: file and key counts, secret ratio, duplicate keys,
+                                 env files without an example and the largest files (computed locally)
+    --format <text|json>         Output format for --audit, --lint, --check, --unused, --stats and --dry-run (default: text)
+    --exclude <pattern>          Skip paths matching a gitignore-style pattern (repeatable)
+    --max-depth <n>              Limit how deep scans descend (default: unlimited)
+    --follow-symlinks            Follow symlinked directories when scanning
+    --one-file-system            Do not cross filesystem boundaries when scanning
+    --upgrade                    Upgrade to the latest version (set GITHUB_TOKEN to avoid API rate limits)
+    --no-update-check            Do not check for a newer release when the TUI starts
+    --no-cache                   Scan directories from disk instead of reusing cached listings
+    --inline                     Run the TUI inline and print the files it wrote on exit
+    --channel <name>             Release channel for --upgrade: stable or prerelease (default: stable)
+    --timeout <duration>         Time limit for --upgrade network steps, e.g. 2m (default: 30s lookup, 10m download)
     --version                    Show version information
     --help                       Show this help message
 
 EXAMPLES:
     dotenv-tui                                    # Launch interactive TUI
+    dotenv-tui --inline                           # Launch the TUI without taking over the screen
     dotenv-tui --generate-example .env            # Generate .env.example from .env
     dotenv-tui --generate-env .env.example        # Generate .env from .env.example
+    dotenv-tui --generate-env .env.example --set PORT=8080 --set-file secrets.env  # Fill keys without the form
+    dotenv-tui --export direnv .env.local         # Write .envrc from .env.local for direnv
+    eval "$(dotenv-tui --export shell)"           # Load .env into the current shell
+    dotenv-tui --export gh-secrets --repo acme/api --execute  # Upload secrets to GitHub Actions
+    dotenv-tui --import-env --prefix APP_         # Capture APP_* variables into .env and .env.example
+    dotenv-tui --import-file config.json --flatten  # Convert config.json into .env and .env.example
+    dotenv-tui --export yaml --unflatten > config.yaml  # Write .env as nested YAML
+    dotenv-tui --push k8s --namespace prod --name app-env .env.production --dry-run  # Diff a Secret
     dotenv-tui --scan                             # Scan current directory for .env files
     dotenv-tui --scan ./myproject                 # Scan specific directory
+    dotenv-tui --scan --exclude fixtures/         # Scan, skipping fixtures directories
     dotenv-tui --yolo                             # Auto-generate .env from all .env.example files
     dotenv-tui --yolo --force                     # Force overwrite existing .env files
+    dotenv-tui --yolo --answers ci.env            # Fill placeholders from ci.env
+    dotenv-tui --yolo --from-env                  # Fill keys already set in the environment
     dotenv-tui --yolo --force --no-backup         # Overwrite without backups (CI/CD use)
+    dotenv-tui --yolo --backup-dir .dotenv-tui/backups  # Collect backups in one place
     dotenv-tui --generate-example .env --dry-run  # Preview .env.example generation
+    dotenv-tui --generate-test .env.example       # Write .env.test with fake secrets for test suites
+    dotenv-tui --generate-example .env --force --changelog  # Regenerate, noting added/removed keys
+    dotenv-tui --generate-example .env --unmask-key PUBLIC_KEY --unmask-key 'NEXT_PUBLIC_*'
     dotenv-tui --yolo --dry-run                   # Preview all files that would be generated
+    dotenv-tui --yolo --dry-run --format json     # Print the plan as JSON for review tooling
+    dotenv-tui --yolo --dry-run --deterministic > plan.txt  # A plan that only changes when the files do
+    dotenv-tui --audit --format json              # Audit secrets as JSON (exit 1 on high severity)
+    dotenv-tui --why SENTRY_DSN .env.production   # Explain why a key is or is not masked
+    dotenv-tui --remask .env.example              # Mask secrets committed to an example by mistake
+    dotenv-tui --lint                             # Lint env files (exit 1 on errors)
+    dotenv-tui --compat systemd ./deploy          # Check env files load as a systemd EnvironmentFile
+    dotenv-tui --check                            # Find keys missing or empty in .env, with typo hints
+    dotenv-tui --unused                           # Cross-reference env keys with source code
+    dotenv-tui --stats ./services                 # Env hygiene overview of a repo
+    dotenv-tui init                               # Set up config, .env.example and .gitignore
+    dotenv-tui history --limit 20                 # Show the 20 most recent writes
+    dotenv-tui template list                      # List the built-in .env.example templates
+    dotenv-tui template nextjs > .env.example     # Start a Next.js project from a template
     dotenv-tui --upgrade                          # Upgrade to the latest version
+    dotenv-tui --upgrade --dry-run                # Show release notes without upgrading
  `)
 }
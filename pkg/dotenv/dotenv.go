@@ -0,0 +1,224 @@
+// Package dotenv is the public Go API for dotenv-tui's parsing, secret
+// detection, example generation, and scanning logic.
+//
+// It wraps the internal packages used by the dotenv-tui binary behind a
+// small, stable surface so other tools can generate .env.example files or
+// audit env files without shelling out to the CLI.
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jellydn/dotenv-tui/internal/detector"
+	"github.com/jellydn/dotenv-tui/internal/dotenvx"
+	"github.com/jellydn/dotenv-tui/internal/generator"
+	"github.com/jellydn/dotenv-tui/internal/parser"
+	"github.com/jellydn/dotenv-tui/internal/scanner"
+)
+
+// Entry is a single line of a .env file: a KeyValue, Comment, or BlankLine.
+// A file starting with a byte order mark is led by an Encoding entry.
+type Entry = parser.Entry
+
+// KeyValue is a KEY=VALUE line.
+type KeyValue = parser.KeyValue
+
+// Comment is a comment line, stored verbatim including the leading '#'.
+type Comment = parser.Comment
+
+// BlankLine is an empty line.
+type BlankLine = parser.BlankLine
+
+// Encoding is the first entry of a file stored as UTF-8 with a byte order
+// mark or as UTF-16. Writing it first stores the file that way again.
+type Encoding = parser.Encoding
+
+// Encodings Parse detects.
+const (
+	UTF8BOM = parser.UTF8BOM
+	UTF16LE = parser.UTF16LE
+	UTF16BE = parser.UTF16BE
+)
+
+// Entries is a list of entries that implements io.WriterTo.
+type Entries = parser.Entries
+
+// EntryWriter writes entries one at a time; see NewEntryWriter.
+type EntryWriter = parser.EntryWriter
+
+// ParseErrors lists the malformed lines found when parsing in strict mode.
+type ParseErrors = parser.ParseErrors
+
+// ParseOptions controls how env files are parsed.
+type ParseOptions struct {
+	// Strict reports malformed lines as ParseErrors instead of silently
+	// treating them as comments.
+	Strict bool
+}
+
+// WriteOptions controls how entries are written.
+type WriteOptions struct {
+	// QuotePolicy selects how unquoted values are quoted: "preserve"
+	// (default) writes them as they are, "always" quotes every value and
+	// "when-needed" quotes values containing spaces or "#", or with
+	// leading or trailing whitespace. Quoted values are never changed.
+	QuotePolicy string
+}
+
+// ExampleOptions controls how .env.example content is generated.
+type ExampleOptions struct {
+	// KeepKeys lists keys whose values are copied verbatim even if they
+	// look like secrets.
+	KeepKeys []string
+	// Style selects the placeholder style: "mask" (default, "sk_***"),
+	// "descriptive" ("<your-stripe-secret-key>") or "partial"
+	// ("sk_live_••••••cdef"). Unknown styles fall back to "mask".
+	Style string
+	// Visible is the number of trailing characters kept by the partial
+	// style; 0 uses the default of 4.
+	Visible int
+	// SortKeys orders keys alphabetically instead of keeping their order.
+	SortKeys bool
+	// GroupByPrefix gathers keys sharing a prefix such as DB_ under a
+	// "# DB" section comment.
+	GroupByPrefix bool
+}
+
+// ScanOptions controls directory scanning.
+type ScanOptions struct {
+	// Examples finds .env.example files instead of .env files.
+	Examples bool
+	// Exclude holds extra gitignore-style patterns to skip.
+	Exclude []string
+	// MaxDepth limits how many directory levels below root are entered;
+	// 0 means no limit.
+	MaxDepth int
+	// FollowSymlinks descends into symlinked directories, visiting each
+	// real directory once.
+	FollowSymlinks bool
+	// OneFilesystem skips directories on a different filesystem than root.
+	OneFilesystem bool
+	// ExtendedNames also matches app.env, env.local and env.list style names.
+	ExtendedNames bool
+}
+
+// Parse reads env file content into ordered entries, preserving comments and
+// blank lines.
+func Parse(r io.Reader, opts ParseOptions) ([]Entry, error) {
+	if opts.Strict {
+		return parser.ParseStrict(r)
+	}
+	return parser.Parse(r)
+}
+
+// ParseFunc reads env file content and calls fn with each entry as it is
+// read, so very large files can be transformed without holding every entry
+// in memory. Parsing stops at the first error fn returns.
+func ParseFunc(r io.Reader, fn func(Entry) error) error {
+	return parser.ParseFunc(r, fn)
+}
+
+// NewEntryWriter returns an EntryWriter that buffers entries written to w.
+// Call Flush after the last entry.
+func NewEntryWriter(w io.Writer) *EntryWriter {
+	return parser.NewEntryWriter(w)
+}
+
+// Write writes entries back out in .env format.
+func Write(w io.Writer, entries []Entry) error {
+	return parser.Write(w, entries)
+}
+
+// WriteWithOptions writes entries in .env format, quoting values per opts.
+// It returns an error for an unknown quote policy.
+func WriteWithOptions(w io.Writer, entries []Entry, opts WriteOptions) error {
+	policy, err := parser.ParseQuotePolicy(opts.QuotePolicy)
+	if err != nil {
+		return err
+	}
+	return parser.WriteWithOptions(w, entries, parser.WriteOptions{QuotePolicy: policy})
+}
+
+// IsEncrypted reports whether value was encrypted by dotenvx, i.e. starts
+// with "encrypted:".
+func IsEncrypted(value string) bool {
+	return dotenvx.IsEncrypted(value)
+}
+
+// Encrypt encrypts value the way dotenvx does for the hex encoded
+// DOTENV_PUBLIC_KEY publicKey.
+func Encrypt(publicKey, value string) (string, error) {
+	return dotenvx.Encrypt(publicKey, value)
+}
+
+// Decrypt decrypts a dotenvx encrypted value with the hex encoded
+// DOTENV_PRIVATE_KEY privateKey. Other values are returned as they are.
+func Decrypt(privateKey, value string) (string, error) {
+	return dotenvx.Decrypt(privateKey, value)
+}
+
+// IsSecret reports whether a key/value pair looks like it holds a secret.
+func IsSecret(key, value string) bool {
+	return detector.IsSecret(key, value)
+}
+
+// Placeholder returns the format-hint placeholder (e.g. "sk_***") that
+// would replace value in a generated example.
+func Placeholder(key, value string) string {
+	return detector.GeneratePlaceholder(key, value)
+}
+
+// PlaceholderKind returns the kind named by a descriptive placeholder, e.g.
+// "stripe-secret-key" for "<your-stripe-secret-key>".
+func PlaceholderKind(value string) (string, bool) {
+	return detector.PlaceholderKind(value)
+}
+
+// GenerateExample returns a copy of entries with secret values masked.
+func GenerateExample(entries []Entry, opts ExampleOptions) []Entry {
+	masking := detector.Masking{Style: detector.PlaceholderStyle(opts.Style), Visible: opts.Visible, Unmasked: opts.KeepKeys}
+	masked := generator.GenerateExampleWithStyle(entries, masking)
+
+	order := generator.SortNone
+	if opts.SortKeys {
+		order = generator.SortKeys
+	}
+	return generator.Arrange(masked, order, opts.GroupByPrefix)
+}
+
+// GenerateExampleFile reads a .env file from r and writes the masked
+// .env.example content to w.
+func GenerateExampleFile(r io.Reader, w io.Writer, opts ExampleOptions) error {
+	entries, err := parser.Parse(r)
+	if err != nil {
+		return fmt.Errorf("failed to parse .env file: %w", err)
+	}
+	return parser.Write(w, GenerateExample(entries, opts))
+}
+
+// Scan recursively finds env files under root, skipping dependency
+// directories such as node_modules and vendor as well as paths listed in the
+// root's .dotenvtuiignore. Returned paths are relative to root.
+func Scan(root string, opts ScanOptions) ([]string, error) {
+	return ScanContext(context.Background(), root, opts)
+}
+
+// ScanContext is like Scan but stops with ctx's error as soon as ctx is
+// done, so callers can cancel a scan of a large tree or give it a deadline.
+func ScanContext(ctx context.Context, root string, opts ScanOptions) ([]string, error) {
+	sopts := scanner.Options{
+		Exclude:        opts.Exclude,
+		MaxDepth:       opts.MaxDepth,
+		FollowSymlinks: opts.FollowSymlinks,
+		OneFilesystem:  opts.OneFilesystem,
+		ExtendedNames:  opts.ExtendedNames,
+	}
+	scan := scanner.ScanWithOptions
+	if opts.Examples {
+		scan = scanner.ScanExamplesWithOptions
+	}
+	result, err := scan(ctx, root, sopts)
+	return result.Files, err
+}
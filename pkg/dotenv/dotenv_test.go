@@ -0,0 +1,241 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    ParseOptions
+		wantErr bool
+		wantLen int
+	}{
+		{
+			name:    "lenient parse keeps stray lines",
+			input:   "KEY=value\nstray\n",
+			wantLen: 2,
+		},
+		{
+			name:    "strict parse reports stray lines",
+			input:   "KEY=value\nstray\n",
+			opts:    ParseOptions{Strict: true},
+			wantErr: true,
+			wantLen: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Parse(strings.NewReader(tt.input), tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(entries) != tt.wantLen {
+				t.Errorf("Parse() returned %d entries, expected %d", len(entries), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestWriteWithOptions(t *testing.T) {
+	entries := []Entry{KeyValue{Key: "NAME", Value: "my app"}, KeyValue{Key: "PORT", Value: "3000"}}
+
+	var out strings.Builder
+	if err := WriteWithOptions(&out, entries, WriteOptions{QuotePolicy: "when-needed"}); err != nil {
+		t.Fatalf("WriteWithOptions() error = %v", err)
+	}
+	if got, want := out.String(), "NAME=\"my app\"\nPORT=3000\n"; got != want {
+		t.Errorf("WriteWithOptions() = %q, want %q", got, want)
+	}
+	if err := WriteWithOptions(&out, entries, WriteOptions{QuotePolicy: "sometimes"}); err == nil {
+		t.Error("WriteWithOptions() should reject an unknown quote policy")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	const (
+		public  = "031f233cf0ce6e3676382d1ab265fc912e1e40ab911d7fd247706b8a50ef271a37"
+		private = "765c46f29b40e6ab9fa2b3090de228b6ee29cebf96954fddbd5d59f834ae1b4a"
+	)
+	encrypted, err := Encrypt(public, "s3cret")
+	if err != nil || !IsEncrypted(encrypted) {
+		t.Fatalf("Encrypt() = %q, %v", encrypted, err)
+	}
+	if IsSecret("API_KEY", encrypted) {
+		t.Error("an encrypted value should not count as a secret")
+	}
+	if got, err := Decrypt(private, encrypted); err != nil || got != "s3cret" {
+		t.Errorf("Decrypt() = %q, %v", got, err)
+	}
+}
+
+func TestGenerateExample(t *testing.T) {
+	entries := []Entry{
+		KeyValue{Key: "API_KEY", Value: "sk_live_abc123"},
+		KeyValue{Key: "PUBLIC_TOKEN", Value: "pub_visible"},
+		Comment{Text: "# comment"},
+		KeyValue{Key: "PORT", Value: "3000"},
+	}
+
+	tests := []struct {
+		name     string
+		opts     ExampleOptions
+		expected []string
+	}{
+		{
+			name:     "masks secrets by default",
+			expected: []string{"sk_***", "***", "", "3000"},
+		},
+		{
+			name:     "keeps listed keys verbatim",
+			opts:     ExampleOptions{KeepKeys: []string{"public_token"}},
+			expected: []string{"sk_***", "pub_visible", "", "3000"},
+		},
+		{
+			name:     "descriptive placeholders",
+			opts:     ExampleOptions{Style: "descriptive"},
+			expected: []string{"<your-stripe-secret-key>", "<your-public-token>", "", "3000"},
+		},
+		{
+			name:     "partial placeholders",
+			opts:     ExampleOptions{Style: "partial", Visible: 2},
+			expected: []string{"sk_live_••••••23", "••••••le", "", "3000"},
+		},
+		{
+			name:     "sorted keys",
+			opts:     ExampleOptions{SortKeys: true},
+			expected: []string{"sk_***", "", "3000", "***"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GenerateExample(entries, tt.opts)
+			if len(result) != len(entries) {
+				t.Fatalf("GenerateExample() returned %d entries, expected %d", len(result), len(entries))
+			}
+			for i, want := range tt.expected {
+				kv, ok := result[i].(KeyValue)
+				if !ok {
+					continue
+				}
+				if kv.Value != want {
+					t.Errorf("entry %d value = %q, expected %q", i, kv.Value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateExampleKeepKeysByKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Entry
+	}{
+		{
+			name: "provenance comment",
+			entries: []Entry{
+				Comment{Text: "# set via --set 2026-01-01"},
+				KeyValue{Key: "API_KEY", Value: "sk_live_abc123"},
+				KeyValue{Key: "PUBLIC_TOKEN", Value: "pub_visible"},
+				KeyValue{Key: "PORT", Value: "3000"},
+			},
+		},
+		{
+			name: "byte order mark",
+			entries: []Entry{
+				UTF8BOM,
+				KeyValue{Key: "API_KEY", Value: "sk_live_abc123"},
+				KeyValue{Key: "PUBLIC_TOKEN", Value: "pub_visible"},
+				KeyValue{Key: "PORT", Value: "3000"},
+			},
+		},
+	}
+	want := []Entry{
+		KeyValue{Key: "API_KEY", Value: "sk_***"},
+		KeyValue{Key: "PUBLIC_TOKEN", Value: "pub_visible"},
+		KeyValue{Key: "PORT", Value: "3000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateExample(tt.entries, ExampleOptions{KeepKeys: []string{"public_token"}})
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GenerateExample() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateExampleFile(t *testing.T) {
+	var out strings.Builder
+	err := GenerateExampleFile(strings.NewReader("# db\nDB_PASSWORD=hunter2\nDEBUG=true\n"), &out, ExampleOptions{})
+	if err != nil {
+		t.Fatalf("GenerateExampleFile() unexpected error: %v", err)
+	}
+
+	expected := "# db\nDB_PASSWORD=***\nDEBUG=true\n"
+	if out.String() != expected {
+		t.Errorf("GenerateExampleFile() = %q, expected %q", out.String(), expected)
+	}
+}
+
+func TestIsSecretAndPlaceholder(t *testing.T) {
+	if !IsSecret("GITHUB_TOKEN", "ghp_abcdef") {
+		t.Error("IsSecret() expected GITHUB_TOKEN to be a secret")
+	}
+	if IsSecret("PORT", "8080") {
+		t.Error("IsSecret() expected PORT not to be a secret")
+	}
+	if got := Placeholder("GITHUB_TOKEN", "ghp_abcdef"); got != "ghp_***" {
+		t.Errorf("Placeholder() = %q, expected %q", got, "ghp_***")
+	}
+	if kind, ok := PlaceholderKind("<your-github-token>"); !ok || kind != "github-token" {
+		t.Errorf("PlaceholderKind() = %q, %v; expected github-token", kind, ok)
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{".env", ".env.example", filepath.Join("api", ".env.local")} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("KEY=value\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	envFiles, err := Scan(root, ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() unexpected error: %v", err)
+	}
+	if len(envFiles) != 2 {
+		t.Errorf("Scan() found %v, expected 2 env files", envFiles)
+	}
+
+	examples, err := Scan(root, ScanOptions{Examples: true})
+	if err != nil {
+		t.Fatalf("Scan() unexpected error: %v", err)
+	}
+	if len(examples) != 1 || examples[0] != ".env.example" {
+		t.Errorf("Scan(Examples) = %v, expected [.env.example]", examples)
+	}
+}
+
+func TestScanContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := ScanContext(ctx, t.TempDir(), ScanOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ScanContext() error = %v, expected context.Canceled", err)
+	}
+}